@@ -20,14 +20,15 @@ package cmd
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v3"
 	"github.com/minio/mc/pkg/probe"
-	"github.com/minio/pkg/v2/console"
 )
 
 var adminDecommissionStatusCmd = cli.Command{
@@ -61,6 +62,100 @@ func checkAdminDecommissionStatusSyntax(ctx *cli.Context) {
 	}
 }
 
+// decomPoolStatusMessage - decommissioning status of a pool.
+type decomPoolStatusMessage struct {
+	madmin.PoolStatus
+}
+
+func (m decomPoolStatusMessage) String() string {
+	d := m.Decommission
+	switch {
+	case d == nil:
+		return ""
+	case d.Complete:
+		return color.GreenString(fmt.Sprintf("Decommission of pool %s is complete, you may now remove it from server command line", m.CmdLine))
+	case d.Failed:
+		return color.GreenString(fmt.Sprintf("Decommission of pool %s failed, please retry again", m.CmdLine))
+	case d.Canceled:
+		return color.GreenString(fmt.Sprintf("Decommission of pool %s was canceled, you may start again", m.CmdLine))
+	}
+	usedStart := (d.TotalSize - d.StartSize)
+	usedCurrent := (d.TotalSize - d.CurrentSize)
+
+	duration := float64(time.Since(d.StartTime)) / float64(time.Second)
+	msg := "Decommissioning is starting..."
+	if usedStart > usedCurrent && duration > 10 {
+		copied := uint64(usedStart - usedCurrent)
+		speed := uint64(float64(copied) / duration)
+		msg = "Decommissioning rate at " + humanize.IBytes(speed) + "/sec " + "[" + humanize.IBytes(
+			uint64(usedCurrent)) + "/" + humanize.IBytes(uint64(d.TotalSize)) + "]"
+		msg += "\nStarted: " + humanize.RelTime(time.Now().UTC(), d.StartTime, "", "ago")
+	}
+	return color.GreenString(msg)
+}
+
+func (m decomPoolStatusMessage) JSON() string {
+	statusJSONBytes, e := json.MarshalIndent(m.PoolStatus, "", "    ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(statusJSONBytes)
+}
+
+// decomStatusMessage - decommissioning status of all the pools.
+type decomStatusMessage struct {
+	Pools []madmin.PoolStatus
+}
+
+func (m decomStatusMessage) String() string {
+	rows := [][]string{{"ID", "Pools", "Raw Drives Usage", "Status"}}
+	for _, pool := range m.Pools {
+		capacity, status := "", "Active"
+		if d := pool.Decommission; d != nil {
+			totalSize := uint64(d.TotalSize)
+			usedCurrent := uint64(d.TotalSize - d.CurrentSize)
+			capacity = fmt.Sprintf("%.1f%% (total: %s)", 100*float64(usedCurrent)/float64(totalSize), humanize.IBytes(totalSize))
+			switch {
+			case d.Complete:
+				status = "Complete"
+			case d.Failed:
+				status = "Draining(Failed)"
+			case d.Canceled:
+				status = "Draining(Canceled)"
+			case !d.StartTime.IsZero():
+				status = "Draining"
+			}
+		}
+		rows = append(rows, []string{humanize.Ordinal(pool.ID + 1), pool.CmdLine, capacity, status})
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+	lines := make([]string, 0, len(rows))
+	for i, row := range rows {
+		cells := make([]string, len(row))
+		for j, cell := range row {
+			cells[j] = fmt.Sprintf("%-*s", widths[j], cell)
+		}
+		line := strings.TrimRight(strings.Join(cells, "  "), " ")
+		if i == 0 {
+			line = color.GreenString(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m decomStatusMessage) JSON() string {
+	statusJSONBytes, e := json.MarshalIndent(m.Pools, "", "    ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(statusJSONBytes)
+}
+
 // mainAdminDecommissionStatus is the handle for "mc admin decomission status" command.
 func mainAdminDecommissionStatus(ctx *cli.Context) error {
 	checkAdminDecommissionStatusSyntax(ctx)
@@ -78,93 +173,17 @@ func mainAdminDecommissionStatus(ctx *cli.Context) error {
 		poolStatus, e := client.StatusPool(globalContext, pool)
 		fatalIf(probe.NewError(e).Trace(args...), "Unable to get status per pool")
 
-		if globalJSON {
-			statusJSONBytes, e := json.MarshalIndent(poolStatus, "", "    ")
-			fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
-			console.Println(string(statusJSONBytes))
-			return nil
-		}
-
-		var msg string
-		if poolStatus.Decommission.Complete {
-			msg = color.GreenString(fmt.Sprintf("Decommission of pool %s is complete, you may now remove it from server command line", poolStatus.CmdLine))
-		} else if poolStatus.Decommission.Failed {
-			msg = color.GreenString(fmt.Sprintf("Decommission of pool %s failed, please retry again", poolStatus.CmdLine))
-		} else if poolStatus.Decommission.Canceled {
-			msg = color.GreenString(fmt.Sprintf("Decommission of pool %s was canceled, you may start again", poolStatus.CmdLine))
-		} else if !poolStatus.Decommission.StartTime.IsZero() {
-			usedStart := (poolStatus.Decommission.TotalSize - poolStatus.Decommission.StartSize)
-			usedCurrent := (poolStatus.Decommission.TotalSize - poolStatus.Decommission.CurrentSize)
-
-			duration := float64(time.Since(poolStatus.Decommission.StartTime)) / float64(time.Second)
-			if usedStart > usedCurrent && duration > 10 {
-				copied := uint64(usedStart - usedCurrent)
-				speed := uint64(float64(copied) / duration)
-				msg = "Decommissioning rate at " + humanize.IBytes(speed) + "/sec " + "[" + humanize.IBytes(
-					uint64(usedCurrent)) + "/" + humanize.IBytes(uint64(poolStatus.Decommission.TotalSize)) + "]"
-				msg += "\nStarted: " + humanize.RelTime(time.Now().UTC(), poolStatus.Decommission.StartTime, "", "ago")
-			} else {
-				msg = "Decommissioning is starting..."
-			}
-			msg = color.GreenString(msg)
-		} else {
+		d := poolStatus.Decommission
+		if !globalJSON && (d == nil || (!d.Complete && !d.Failed && !d.Canceled && d.StartTime.IsZero())) {
 			errorIf(errDummy().Trace(args...), "This pool is currently not scheduled for decomissioning")
 			return nil
 		}
-		fmt.Println(msg)
+		printMsg(decomPoolStatusMessage{PoolStatus: poolStatus})
 		return nil
 	}
 	poolStatuses, e := client.ListPoolsStatus(globalContext)
 	fatalIf(probe.NewError(e).Trace(args...), "Unable to get status for all pools")
 
-	if globalJSON {
-		statusJSONBytes, e := json.MarshalIndent(poolStatuses, "", "    ")
-		fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
-		console.Println(string(statusJSONBytes))
-		return nil
-	}
-
-	dspOrder := []col{colGreen} // Header
-	for i := 0; i < len(poolStatuses); i++ {
-		dspOrder = append(dspOrder, colGrey)
-	}
-	var printColors []*color.Color
-	for _, c := range dspOrder {
-		printColors = append(printColors, getPrintCol(c))
-	}
-
-	tbl := console.NewTable(printColors, []bool{false, false, false, false}, 0)
-
-	cellText := make([][]string, len(poolStatuses)+1)
-	cellText[0] = []string{
-		"ID",
-		"Pools",
-		"Raw Drives Usage",
-		"Status",
-	}
-	for idx, pool := range poolStatuses {
-		idx++
-		totalSize := uint64(pool.Decommission.TotalSize)
-		usedCurrent := uint64(pool.Decommission.TotalSize - pool.Decommission.CurrentSize)
-		capacity := fmt.Sprintf("%.1f%% (total: %s)", 100*float64(usedCurrent)/float64(totalSize), humanize.IBytes(totalSize))
-		status := "Active"
-		if pool.Decommission != nil {
-			if pool.Decommission.Complete {
-				status = "Complete"
-			} else if pool.Decommission.Failed {
-				status = "Draining(Failed)"
-			} else if pool.Decommission.Canceled {
-				status = "Draining(Canceled)"
-			} else if !pool.Decommission.StartTime.IsZero() {
-				status = "Draining"
-			}
-		}
-		cellText[idx] = []string{
-			humanize.Ordinal(pool.ID + 1),
-			pool.CmdLine,
-			capacity,
-			status,
-		}
-	}
-	return tbl.DisplayTable(cellText)
+	printMsg(decomStatusMessage{Pools: poolStatuses})
+	return nil
 }
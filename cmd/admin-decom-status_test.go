@@ -0,0 +1,52 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/madmin-go/v3"
+)
+
+func TestDecomStatusMessage(t *testing.T) {
+	msg := decomStatusMessage{Pools: []madmin.PoolStatus{
+		{ID: 0, CmdLine: "http://server{1...4}/disk{1...4}", Decommission: &madmin.PoolDecommissionInfo{TotalSize: 100, CurrentSize: 75, StartTime: time.Now()}},
+		{ID: 1, CmdLine: "http://server{5...8}/disk{1...4}"},
+	}}
+
+	var pools []madmin.PoolStatus
+	if e := json.Unmarshal([]byte(msg.JSON()), &pools); e != nil {
+		t.Fatal(e)
+	}
+	if len(pools) != 2 || pools[0].CmdLine != msg.Pools[0].CmdLine {
+		t.Fatalf("expected the pools as a JSON array, got %s", msg.JSON())
+	}
+
+	lines := strings.Split(msg.String(), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and a line per pool, got %q", lines)
+	}
+	for i, want := range []string{"25.0% (total: 100 B)  Draining", "Active"} {
+		if !strings.HasSuffix(lines[i+1], want) {
+			t.Errorf("pool %d: expected %q, got %q", i+1, want, lines[i+1])
+		}
+	}
+}
@@ -94,9 +94,8 @@ type uiData struct {
 	// health color code.
 	HealthCols map[col]int64
 
-	// channel to receive a prompt string to indicate activity on
-	// the terminal
-	CurChan (<-chan string)
+	// cursor to indicate activity on the terminal
+	Cursor *cursorAnimation
 }
 
 func (ui *uiData) updateStats(i madmin.HealResultItem) error {
@@ -344,7 +343,7 @@ func (ui *uiData) updateUI(s *madmin.HealTaskStatus) (err error) {
 		humanize.Comma(ui.ObjectsHealed), totalObjects,
 		totalSize, totalTime)
 
-	console.Print(console.Colorize("HealUpdateUI", fmt.Sprintf(" %s", <-ui.CurChan)))
+	console.Print(console.Colorize("HealUpdateUI", fmt.Sprintf(" %s", ui.Cursor.next())))
 	console.PrintC(fmt.Sprintf("  %s\n", scannedStr))
 	console.PrintC(fmt.Sprintf("    %s\n", healedStr))
 
@@ -634,9 +634,9 @@ func mainAdminHeal(ctx *cli.Context) error {
 	splits := splitStr(aliasedURL, "/", 3)
 	bucket, prefix := splits[1], splits[2]
 
-	clnt, err := newClient(aliasedURL)
+	_, err = newClient(aliasedURL)
 	if err != nil {
-		fatalIf(err.Trace(clnt.GetURL().String()), "Unable to create client for URL ", aliasedURL)
+		fatalIf(err.Trace(aliasedURL), "Unable to create client for URL `%s`.", aliasedURL)
 		return nil
 	}
 
@@ -689,7 +689,7 @@ func mainAdminHeal(ctx *cli.Context) error {
 		HealOpts:              &opts,
 		ObjectsByOnlineDrives: make(map[int]int64),
 		HealthCols:            make(map[col]int64),
-		CurChan:               cursorAnimate(),
+		Cursor:                newCursorAnimation(),
 	}
 
 	res, e := ui.DisplayAndFollowHealStatus(aliasedURL)
@@ -154,7 +154,7 @@ func generatePrometheusConfig(ctx *cli.Context) error {
 
 	hostConfig := mustGetHostConfig(alias)
 	if hostConfig == nil {
-		fatalIf(errInvalidAliasedURL(alias), "No such alias `"+alias+"` found.")
+		fatalIf(errInvalidAliasedURL(alias), "No such alias `"+alias+"` found."+aliasSuggestion(alias))
 		return nil
 	}
 
@@ -82,7 +82,7 @@ func printPrometheusMetrics(ctx *cli.Context) error {
 	}
 	hostConfig := mustGetHostConfig(alias)
 	if hostConfig == nil {
-		fatalIf(errInvalidAliasedURL(alias), "No such alias `"+alias+"` found.")
+		fatalIf(errInvalidAliasedURL(alias), "No such alias `"+alias+"` found."+aliasSuggestion(alias))
 		return nil
 	}
 
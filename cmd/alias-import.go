@@ -112,19 +112,19 @@ func checkCredentialsSyntax(credentials aliasConfigV10) {
 func importAlias(alias string, aliasCfgV10 aliasConfigV10) aliasMessage {
 	checkCredentialsSyntax(aliasCfgV10)
 
-	mcCfgV10, err := loadMcConfig()
-	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config `"+mustGetMcConfigPath()+"`.")
-
-	// Add new host.
-	mcCfgV10.Aliases[alias] = aliasCfgV10
-	fatalIf(saveMcConfig(mcCfgV10).Trace(alias), "Unable to import credentials to `"+mustGetMcConfigPath()+"`.")
+	err := updateMcConfig(func(mcCfgV10 *configV10) *probe.Error {
+		// Add new host.
+		mcCfgV10.Aliases[alias] = aliasCfgV10
+		return nil
+	})
+	fatalIf(err.Trace(alias), "Unable to import credentials to `"+mustGetMcConfigPath()+"`.")
 	return aliasMessage{
 		Alias:     alias,
-		URL:       mcCfgV10.Aliases[alias].URL,
-		AccessKey: mcCfgV10.Aliases[alias].AccessKey,
-		SecretKey: mcCfgV10.Aliases[alias].SecretKey,
-		API:       mcCfgV10.Aliases[alias].API,
-		Path:      mcCfgV10.Aliases[alias].Path,
+		URL:       aliasCfgV10.URL,
+		AccessKey: aliasCfgV10.AccessKey,
+		SecretKey: aliasCfgV10.SecretKey,
+		API:       aliasCfgV10.API,
+		Path:      aliasCfgV10.Path,
 	}
 }
 
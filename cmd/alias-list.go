@@ -130,6 +130,8 @@ func listAliases(alias string, deprecated bool) (aliases []aliasMessage) {
 				AccessKey:   v.AccessKey,
 				SecretKey:   v.SecretKey,
 				API:         v.API,
+				Provider:    v.Provider,
+				Region:      v.Region,
 			}
 
 			if deprecated {
@@ -140,7 +142,7 @@ func listAliases(alias string, deprecated bool) (aliases []aliasMessage) {
 
 			return []aliasMessage{aliasMsg}
 		}
-		fatalIf(errInvalidAliasedURL(alias), "No such alias `"+alias+"` found.")
+		fatalIf(errInvalidAliasedURL(alias), "No such alias `"+alias+"` found."+aliasSuggestion(alias))
 	}
 
 	for k, v := range conf.Aliases {
@@ -151,6 +153,8 @@ func listAliases(alias string, deprecated bool) (aliases []aliasMessage) {
 			AccessKey:   v.AccessKey,
 			SecretKey:   v.SecretKey,
 			API:         v.API,
+			Provider:    v.Provider,
+			Region:      v.Region,
 		}
 
 		if deprecated {
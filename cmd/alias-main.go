@@ -68,11 +68,14 @@ type aliasMessage struct {
 	prettyPrint bool
 	Status      string `json:"status"`
 	Alias       string `json:"alias"`
+	Profile     string `json:"profile,omitempty"`
 	URL         string `json:"URL"`
 	AccessKey   string `json:"accessKey,omitempty"`
 	SecretKey   string `json:"secretKey,omitempty"`
 	API         string `json:"api,omitempty"`
 	Path        string `json:"path,omitempty"`
+	Provider    string `json:"provider,omitempty"`
+	Region      string `json:"region,omitempty"`
 	// Deprecated field, replaced by Path
 	Lookup string `json:"lookup,omitempty"`
 }
@@ -84,25 +87,37 @@ func (h aliasMessage) String() string {
 	switch h.op {
 	case "list":
 		// Create a new pretty table with cols configuration
-		t := newPrettyRecord(2,
-			Row{"Alias", "Alias"},
-			Row{"URL", "URL"},
-			Row{"AccessKey", "AccessKey"},
-			Row{"SecretKey", "SecretKey"},
-			Row{"API", "API"},
-			Row{"Path", "Path"},
-		)
+		rows := []Row{
+			{"Alias", "Alias"},
+			{"URL", "URL"},
+			{"AccessKey", "AccessKey"},
+			{"SecretKey", "SecretKey"},
+			{"API", "API"},
+			{"Path", "Path"},
+		}
 		// Handle deprecated lookup
 		path := h.Path
 		if path == "" {
 			path = h.Lookup
 		}
-		return t.buildRecord(h.Alias, h.URL, h.AccessKey, h.SecretKey, h.API, path)
+		values := []string{h.Alias, h.URL, h.AccessKey, h.SecretKey, h.API, path}
+		if h.Provider != "" {
+			rows = append(rows, Row{"Provider", "Provider"})
+			values = append(values, h.Provider)
+		}
+		if h.Region != "" {
+			rows = append(rows, Row{"Region", "Region"})
+			values = append(values, h.Region)
+		}
+		return newPrettyRecord(2, rows...).buildRecord(values...)
 	case "remove":
 		return console.Colorize("AliasMessage", "Removed `"+h.Alias+"` successfully.")
 	case "add": // add is deprecated
 		fallthrough
 	case "set":
+		if h.Profile != "" {
+			return console.Colorize("AliasMessage", "Added profile `"+h.Profile+"` of `"+h.Alias+"` successfully.")
+		}
 		return console.Colorize("AliasMessage", "Added `"+h.Alias+"` successfully.")
 	case "import":
 		return console.Colorize("AliasMessage", "Imported `"+h.Alias+"` successfully.")
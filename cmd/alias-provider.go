@@ -0,0 +1,104 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"sort"
+	"strings"
+)
+
+// providerPreset - endpoint and signature settings of a storage provider
+// or AWS partition, selected with 'alias set --provider'.
+type providerPreset struct {
+	// Endpoint template, %s is replaced by the region or account.
+	endpoint string
+	// Fixed signing region, whatever the endpoint is.
+	signingRegion string
+	// Account based endpoints take an account ID instead of a region.
+	account bool
+	api     string
+	path    string
+}
+
+var providerPresets = map[string]providerPreset{
+	"aws": {
+		endpoint: "https://s3.%s.amazonaws.com",
+		api:      "s3v4",
+		path:     "auto",
+	},
+	"aws-cn": {
+		endpoint: "https://s3.%s.amazonaws.com.cn",
+		api:      "s3v4",
+		path:     "auto",
+	},
+	"aws-us-gov": {
+		endpoint: "https://s3.%s.amazonaws.com",
+		api:      "s3v4",
+		path:     "auto",
+	},
+	"wasabi": {
+		endpoint: "https://s3.%s.wasabisys.com",
+		api:      "s3v4",
+		path:     "auto",
+	},
+	"digitalocean": {
+		// Spaces only serve buckets with virtual host style requests.
+		endpoint: "https://%s.digitaloceanspaces.com",
+		api:      "s3v4",
+		path:     "off",
+	},
+	"cloudflare-r2": {
+		// R2 signs every request for the "auto" region.
+		endpoint:      "https://%s.r2.cloudflarestorage.com",
+		signingRegion: "auto",
+		account:       true,
+		api:           "s3v4",
+		path:          "on",
+	},
+}
+
+// providerPresetNames - returns the sorted names of the provider presets.
+func providerPresetNames() []string {
+	names := make([]string, 0, len(providerPresets))
+	for name := range providerPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupProviderPreset - returns the preset of a provider, case insensitive.
+func lookupProviderPreset(provider string) (providerPreset, bool) {
+	preset, ok := providerPresets[strings.ToLower(provider)]
+	return preset, ok
+}
+
+// resolve - returns the endpoint and signing region for the region or
+// account given in place of the alias URL. Full URLs are kept as is, so
+// that a preset can be used with a custom endpoint of the provider, their
+// region is then looked up by the client unless the provider fixes it.
+func (p providerPreset) resolve(location string) (url, region string) {
+	if isValidHostURL(location) {
+		return location, p.signingRegion
+	}
+	region = p.signingRegion
+	if region == "" {
+		region = location
+	}
+	return strings.Replace(p.endpoint, "%s", location, 1), region
+}
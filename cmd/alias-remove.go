@@ -20,6 +20,7 @@ package cmd
 import (
 	"github.com/fatih/color"
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/pkg/v2/console"
 )
 
@@ -85,22 +86,20 @@ func mainAliasRemove(ctx *cli.Context) error {
 func aliasMustExist(alias string) {
 	hostConfig := mustGetHostConfig(alias)
 	if hostConfig == nil {
-		fatalIf(errInvalidAliasedURL(alias), "No such alias `"+alias+"` found.")
+		fatalIf(errInvalidAliasedURL(alias), "No such alias `"+alias+"` found."+aliasSuggestion(alias))
 	}
 }
 
 // removeAlias - removes an alias.
 func removeAlias(alias string) aliasMessage {
-	conf, err := loadMcConfig()
-	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config version `"+globalMCConfigVersion+"`.")
-
 	// check if alias is valid
 	aliasMustExist(alias)
 
-	// Remove the alias from the config.
-	delete(conf.Aliases, alias)
-
-	err = saveMcConfig(conf)
+	err := updateMcConfig(func(conf *configV10) *probe.Error {
+		// Remove the alias from the config.
+		delete(conf.Aliases, alias)
+		return nil
+	})
 	fatalIf(err.Trace(alias), "Unable to save the delete alias in config version `"+globalMCConfigVersion+"`.")
 
 	return aliasMessage{Alias: alias}
@@ -27,6 +27,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -48,7 +49,11 @@ var aliasSetFlags = []cli.Flag{
 	},
 	cli.StringFlag{
 		Name:  "api",
-		Usage: "API signature. Valid options are '[S3v4, S3v2]'",
+		Usage: "API signature. Valid options are '[S3v4, S3v2, anonymous, auto, azure, gcs]'",
+	},
+	cli.StringFlag{
+		Name:  "provider",
+		Usage: "use the endpoint and signature settings of a provider, URL is then its region or account ID. Valid options are '[" + strings.Join(providerPresetNames(), ", ") + "]'",
 	},
 }
 
@@ -96,6 +101,34 @@ EXAMPLES:
      {{.Prompt}} echo -e "BKIKJAA5BMMU2RHO6IBB\nV8f1CwQqAcwo80UEIJEjc5gVQUSSx5ohQ9GSrr12" | \
                  {{.HelpName}} mys3 https://s3.amazonaws.com --api "s3v4" --path "off"
      {{.EnableHistory}}
+  6. Add the Azure Blob Storage account "myaccount" under "myazure" alias, using its account key.
+     The account is also reachable with az://myaccount/CONTAINER URLs.
+     {{.DisableHistory}}
+     {{.Prompt}} {{.HelpName}} myazure https://myaccount.blob.core.windows.net myaccount ACCOUNTKEY --api "azure"
+     {{.EnableHistory}}
+  7. Add the Amazon S3 China (Beijing) region under "mycn" alias. For security reasons turn off bash history momentarily.
+     {{.DisableHistory}}
+     {{.Prompt}} {{.HelpName}} mycn cn-north-1 BKIKJAA5BMMU2RHO6IBB V8f1CwQqAcwo80UEIJEjc5gVQUSSx5ohQ9GSrr12 --provider aws-cn
+     {{.EnableHistory}}
+  8. Add the Cloudflare R2 storage of an account under "myr2" alias, prompting for keys.
+     {{.Prompt}} {{.HelpName}} myr2 0123456789abcdef0123456789abcdef --provider cloudflare-r2
+     Enter Access Key: BKIKJAA5BMMU2RHO6IBB
+     Enter Secret Key: V8f1CwQqAcwo80UEIJEjc5gVQUSSx5ohQ9GSrr12
+  9. Add the Google Cloud Storage project "myproject" under "mygcs" alias, using the JSON API with a
+     service account key file. Buckets are also reachable with gs://BUCKET URLs.
+     {{.Prompt}} {{.HelpName}} mygcs https://storage.googleapis.com myproject ~/key.json --api "gcs"
+  10. Add read-only keys as the "readonly" profile of the endpoint of "myminio", used by the aliases of
+      the endpoint with '--profile readonly'. For security reasons turn off bash history momentarily.
+      {{.DisableHistory}}
+      {{.Prompt}} {{.HelpName}} --profile readonly myminio http://localhost:9000 readonly readonly123
+      {{.EnableHistory}}
+      {{.Prompt}} mc ls --profile readonly myminio/mybucket
+  11. Add the public buckets of an appliance under "mypublic" alias, with requests sent unsigned.
+      {{.Prompt}} {{.HelpName}} mypublic https://public.example.com "" "" --api "anonymous"
+  12. Add an appliance only speaking Signature V2 under "myv2" alias. For security reasons turn off bash history momentarily.
+      {{.DisableHistory}}
+      {{.Prompt}} {{.HelpName}} myv2 https://v2.example.com BKIKJAA5BMMU2RHO6IBB V8f1CwQqAcwo80UEIJEjc5gVQUSSx5ohQ9GSrr12 --api "v2"
+      {{.EnableHistory}}
 `,
 }
 
@@ -118,11 +151,31 @@ func checkAliasSetSyntax(ctx *cli.Context, accessKey, secretKey string, deprecat
 	api := ctx.String("api")
 	path := ctx.String("path")
 	bucketLookup := ctx.String("lookup")
+	provider := ctx.String("provider")
 
 	if !isValidAlias(alias) {
 		fatalIf(errInvalidAlias(alias), "Invalid alias.")
 	}
 
+	if provider != "" {
+		preset, ok := lookupProviderPreset(provider)
+		if !ok {
+			fatalIf(errInvalidArgument().Trace(provider),
+				"Unrecognized provider. Valid options are `["+strings.Join(providerPresetNames(), ", ")+"]`.")
+		}
+		if strings.EqualFold(api, azureAPI) || strings.EqualFold(api, gcsAPI) {
+			fatalIf(errInvalidArgument().Trace(provider, api),
+				"Provider `"+provider+"` does not support API signature `"+api+"`.")
+		}
+		location := "region"
+		if preset.account {
+			location = "account ID"
+		}
+		if url, _ = preset.resolve(url); !isValidHostURL(url) {
+			fatalIf(errInvalidURL(args.Get(1)), "Invalid URL or "+location+" for provider `"+provider+"`.")
+		}
+	}
+
 	if !isValidHostURL(url) {
 		fatalIf(errInvalidURL(url), "Invalid URL.")
 	}
@@ -139,7 +192,7 @@ func checkAliasSetSyntax(ctx *cli.Context, accessKey, secretKey string, deprecat
 
 	if api != "" && !isValidAPI(api) { // Empty value set to default "S3v4".
 		fatalIf(errInvalidArgument().Trace(api),
-			"Unrecognized API signature. Valid options are `[S3v4, S3v2]`.")
+			"Unrecognized API signature. Valid options are `[S3v4, S3v2, anonymous, auto, azure, gcs]`.")
 	}
 
 	if deprecated {
@@ -157,13 +210,15 @@ func checkAliasSetSyntax(ctx *cli.Context, accessKey, secretKey string, deprecat
 
 // setAlias - set an alias config.
 func setAlias(alias string, aliasCfgV10 aliasConfigV10) aliasMessage {
-	mcCfgV10, err := loadMcConfig()
-	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config `"+mustGetMcConfigPath()+"`.")
-
-	// Add new host.
-	mcCfgV10.Aliases[alias] = aliasCfgV10
-
-	err = saveMcConfig(mcCfgV10)
+	err := updateMcConfig(func(mcCfgV10 *configV10) *probe.Error {
+		// The upload rules are kept when the alias is set again.
+		if existing, ok := mcCfgV10.Aliases[alias]; ok && aliasCfgV10.UploadRules == nil {
+			aliasCfgV10.UploadRules = existing.UploadRules
+		}
+		// Add new host.
+		mcCfgV10.Aliases[alias] = aliasCfgV10
+		return nil
+	})
 	fatalIf(err.Trace(alias), "Unable to update hosts in config version `"+mustGetMcConfigPath()+"`.")
 
 	return aliasMessage{
@@ -173,12 +228,55 @@ func setAlias(alias string, aliasCfgV10 aliasConfigV10) aliasMessage {
 		SecretKey: aliasCfgV10.SecretKey,
 		API:       aliasCfgV10.API,
 		Path:      aliasCfgV10.Path,
+		Provider:  aliasCfgV10.Provider,
+		Region:    aliasCfgV10.Region,
+	}
+}
+
+// setAliasProfile - set the credentials of aliasCfgV10 as a profile of its
+// endpoint. The alias is added with them when missing, an existing alias
+// keeps its own credentials.
+func setAliasProfile(alias, profile string, aliasCfgV10 aliasConfigV10) aliasMessage {
+	err := updateMcConfig(func(mcCfgV10 *configV10) *probe.Error {
+		if mcCfgV10.Hosts == nil {
+			mcCfgV10.Hosts = make(map[string]hostConfigV10)
+		}
+		key := hostConfigKey(aliasCfgV10.URL)
+		hostCfg := mcCfgV10.Hosts[key]
+		if hostCfg.Profiles == nil {
+			hostCfg.Profiles = make(map[string]credentialProfileV10)
+		}
+		hostCfg.Profiles[profile] = credentialProfileV10{
+			AccessKey:    aliasCfgV10.AccessKey,
+			SecretKey:    aliasCfgV10.SecretKey,
+			SessionToken: aliasCfgV10.SessionToken,
+		}
+		mcCfgV10.Hosts[key] = hostCfg
+
+		if existing, ok := mcCfgV10.Aliases[alias]; ok {
+			aliasCfgV10.AccessKey, aliasCfgV10.SecretKey, aliasCfgV10.SessionToken = existing.AccessKey, existing.SecretKey, existing.SessionToken
+			aliasCfgV10.UploadRules = existing.UploadRules
+		}
+		mcCfgV10.Aliases[alias] = aliasCfgV10
+		return nil
+	})
+	fatalIf(err.Trace(alias, profile), "Unable to update hosts in config version `"+mustGetMcConfigPath()+"`.")
+
+	return aliasMessage{
+		Alias:    alias,
+		Profile:  profile,
+		URL:      aliasCfgV10.URL,
+		API:      aliasCfgV10.API,
+		Path:     aliasCfgV10.Path,
+		Provider: aliasCfgV10.Provider,
+		Region:   aliasCfgV10.Region,
 	}
 }
 
 // probeS3Signature - auto probe S3 server signature: issue a Stat call
-// using v4 signature then v2 in case of failure.
-func probeS3Signature(ctx context.Context, accessKey, secretKey, url string, peerCert *x509.Certificate) (string, *probe.Error) {
+// using v4 signature then v2 in case of failure. When signatures are
+// passed, only those are probed in the given order.
+func probeS3Signature(ctx context.Context, accessKey, secretKey, url, region string, peerCert *x509.Certificate, signatures ...string) (string, *probe.Error) {
 	probeBucketName := randString(60, rand.NewSource(time.Now().UnixNano()), "probe-bsign-")
 	// Test s3 connection for API auto probe
 	s3Config := &Config{
@@ -187,6 +285,7 @@ func probeS3Signature(ctx context.Context, accessKey, secretKey, url string, pee
 		AccessKey:         accessKey,
 		SecretKey:         secretKey,
 		HostURL:           urlJoinPath(url, probeBucketName),
+		Region:            region,
 		Debug:             globalDebug,
 		ConnReadDeadline:  globalConnReadDeadline,
 		ConnWriteDeadline: globalConnWriteDeadline,
@@ -223,24 +322,50 @@ func probeS3Signature(ctx context.Context, accessKey, secretKey, url string, pee
 		return stype, nil
 	}
 
-	stype, err := probeSignatureType("s3v4")
-	if err != nil {
-		if stype, err = probeSignatureType("s3v2"); err != nil {
-			return "", err.Trace("s3v4", "s3v2")
+	if len(signatures) == 0 {
+		signatures = []string{"s3v4", "s3v2"}
+	}
+	var err *probe.Error
+	for _, signature := range signatures {
+		var stype string
+		if stype, err = probeSignatureType(signature); err == nil {
+			return stype, nil
 		}
-		return stype, nil
 	}
-	return stype, nil
+	return "", err.Trace(signatures...)
+}
+
+// probeAzureAccount - validates the endpoint and credentials of an Azure
+// Blob Storage account by listing its containers.
+func probeAzureAccount(ctx context.Context, config *Config) *probe.Error {
+	clnt, err := azureNew(config)
+	if err != nil {
+		return err
+	}
+	_, err = clnt.ListBuckets(ctx)
+	return err
+}
+
+// probeGCSProject - validates the endpoint and service account key of a
+// Google Cloud Storage project by listing its buckets.
+func probeGCSProject(ctx context.Context, config *Config) *probe.Error {
+	clnt, err := gcsNew(config)
+	if err != nil {
+		return err
+	}
+	_, err = clnt.ListBuckets(ctx)
+	return err
 }
 
 // BuildS3Config constructs an S3 Config and does
 // signature auto-probe when needed.
-func BuildS3Config(ctx context.Context, alias, url, accessKey, secretKey, api, path string, peerCert *x509.Certificate) (*Config, *probe.Error) {
+func BuildS3Config(ctx context.Context, alias, url, accessKey, secretKey, api, path, region string, peerCert *x509.Certificate) (*Config, *probe.Error) {
 	s3Config := NewS3Config(alias, url, &aliasConfigV10{
 		AccessKey: accessKey,
 		SecretKey: secretKey,
 		URL:       url,
 		Path:      path,
+		Region:    region,
 	})
 
 	if peerCert != nil {
@@ -254,7 +379,7 @@ func BuildS3Config(ctx context.Context, alias, url, accessKey, secretKey, api, p
 		return s3Config, nil
 	}
 	// Probe S3 signature version
-	api, err := probeS3Signature(ctx, accessKey, secretKey, url, peerCert)
+	api, err := probeS3Signature(ctx, accessKey, secretKey, url, region, peerCert)
 	if err != nil {
 		return nil, err.Trace(url, accessKey, api, path)
 	}
@@ -310,6 +435,9 @@ func mainAliasSet(cli *cli.Context, deprecated bool) error {
 		api   = cli.String("api")
 		path  = cli.String("path")
 
+		provider = strings.ToLower(cli.String("provider"))
+		region   string
+
 		peerCert *x509.Certificate
 		err      *probe.Error
 	)
@@ -331,6 +459,20 @@ func mainAliasSet(cli *cli.Context, deprecated bool) error {
 	accessKey, secretKey := fetchAliasKeys(args)
 	checkAliasSetSyntax(cli, accessKey, secretKey, deprecated)
 
+	if provider != "" {
+		// Presets only fill in what the user did not set explicitly.
+		preset, _ := lookupProviderPreset(provider)
+		url, region = preset.resolve(url)
+		if api == "" {
+			api = preset.api
+		}
+		if !cli.IsSet("path") {
+			path = preset.path
+		}
+	}
+
+	api = normalizeAPI(api)
+
 	ctx, cancelAliasAdd := context.WithCancel(globalContext)
 	defer cancelAliasAdd()
 
@@ -339,16 +481,54 @@ func mainAliasSet(cli *cli.Context, deprecated bool) error {
 		fatalIf(err.Trace(alias, url, accessKey), "Unable to initialize new alias from the provided credentials.")
 	}
 
-	s3Config, err := BuildS3Config(ctx, alias, url, accessKey, secretKey, api, path, peerCert)
+	s3Config, err := BuildS3Config(ctx, alias, url, accessKey, secretKey, api, path, region, peerCert)
 	fatalIf(err.Trace(alias, url, accessKey), "Unable to initialize new alias from the provided credentials.")
 
-	msg := setAlias(alias, aliasConfigV10{
+	switch {
+	case strings.EqualFold(api, azureAPI):
+		// Azure Blob Storage, the access key is the account name and the secret key the account key.
+		s3Config.Signature = azureAPI
+		if err = probeAzureAccount(ctx, s3Config); err != nil {
+			errorIf(err.Trace(alias, url), "Unable to validate `"+url+"` as an Azure Blob Storage account, adding the alias anyway.")
+		}
+	case strings.EqualFold(api, gcsAPI):
+		// Google Cloud Storage, the access key is the project ID and the secret
+		// key the service account key file, kept as an absolute path.
+		s3Config.Signature = gcsAPI
+		if keyFile, e := filepath.Abs(s3Config.SecretKey); e == nil {
+			s3Config.SecretKey = keyFile
+		}
+		if err = probeGCSProject(ctx, s3Config); err != nil {
+			errorIf(err.Trace(alias, url), "Unable to validate `"+url+"` as a Google Cloud Storage project, adding the alias anyway.")
+		}
+	case api == autoAPI:
+		// The signature is probed whenever the alias is used, as it may change.
+		if _, err = probeS3Signature(ctx, accessKey, secretKey, url, region, peerCert); err != nil {
+			errorIf(err.Trace(alias, url, api), "Unable to validate `"+url+"`, adding the alias anyway.")
+		}
+	case api != "":
+		// The signature is not probed when provided by the user, still validate the
+		// endpoint and credentials but keep the alias for servers not reachable yet.
+		if _, err = probeS3Signature(ctx, accessKey, secretKey, url, region, peerCert, api); err != nil {
+			errorIf(err.Trace(alias, url, api), "Unable to validate `"+url+"` with API signature `"+api+"`, adding the alias anyway.")
+		}
+	}
+
+	aliasCfg := aliasConfigV10{
 		URL:       s3Config.HostURL,
 		AccessKey: s3Config.AccessKey,
 		SecretKey: s3Config.SecretKey,
 		API:       s3Config.Signature,
 		Path:      path,
-	}) // Add an alias with specified credentials.
+		Provider:  provider,
+		Region:    region,
+	}
+	var msg aliasMessage
+	if globalProfile != "" {
+		msg = setAliasProfile(alias, globalProfile, aliasCfg)
+	} else {
+		msg = setAlias(alias, aliasCfg) // Add an alias with specified credentials.
+	}
 
 	msg.op = "set"
 	if deprecated {
@@ -379,13 +559,13 @@ func configurePeerCertificate(s3Config *Config, peerCert *x509.Certificate) {
 			TLSHandshakeTimeout:   10 * time.Second,
 			ExpectContinueTimeout: 10 * time.Second,
 			DisableCompression:    true,
-			TLSClientConfig:       &tls.Config{RootCAs: globalRootCAs},
+			TLSClientConfig:       &tls.Config{RootCAs: globalRootCAs, Certificates: globalClientCertificates},
 		}
 	case s3Config.Transport.TLSClientConfig == nil || s3Config.Transport.TLSClientConfig.RootCAs == nil:
 		if globalRootCAs != nil {
 			globalRootCAs.AddCert(peerCert)
 		}
-		s3Config.Transport.TLSClientConfig = &tls.Config{RootCAs: globalRootCAs}
+		s3Config.Transport.TLSClientConfig = &tls.Config{RootCAs: globalRootCAs, Certificates: globalClientCertificates}
 	default:
 		s3Config.Transport.TLSClientConfig.RootCAs.AddCert(peerCert)
 	}
@@ -0,0 +1,433 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+var analyzeFlags = []cli.Flag{
+	cli.IntFlag{
+		Name:  "depth, d",
+		Usage: "group the objects by their prefix N levels below the target",
+		Value: 1,
+	},
+	cli.IntFlag{
+		Name:  "top",
+		Usage: "number of extensions, content types and prefixes reported",
+		Value: 10,
+	},
+	cli.StringFlag{
+		Name:  "rewind",
+		Usage: "analyze the objects as they were at the specified date",
+	},
+	cli.BoolFlag{
+		Name:  "versions",
+		Usage: "include all object versions",
+	},
+	cli.StringFlag{
+		Name:  "report-html",
+		Usage: "save the report in a standalone HTML page",
+	},
+}
+
+var analyzeCmd = cli.Command{
+	Name:         "analyze",
+	Usage:        "report the distribution of the objects by size, age, extension, content type and prefix",
+	Action:       mainAnalyze,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(append(analyzeFlags, ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+  Lists TARGET recursively and reports the number and the size of its objects
+  by size and age range, and the extensions, content types and prefixes
+  holding the most data, for capacity planning. Objects listed without a
+  content type are counted by the content type of their extension.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+ENVIRONMENT VARIABLES:
+  MC_ENCRYPT_KEY: list of comma delimited prefix=secret values
+
+EXAMPLES:
+  1. Analyze the objects of a bucket.
+     {{.Prompt}} {{.HelpName}} play/mybucket
+
+  2. Find the 20 prefixes two levels below a bucket holding the most data.
+     {{.Prompt}} {{.HelpName}} --depth 2 --top 20 play/mybucket
+
+  3. Analyze all the versions of the objects of a bucket, saved in an HTML page.
+     {{.Prompt}} {{.HelpName}} --versions --report-html mybucket.html play/mybucket
+
+  4. Analyze a local folder as JSON.
+     {{.Prompt}} {{.HelpName}} --json /var/lib/backups
+`,
+}
+
+// analyzeGroup - the number and the size of the objects of a range, an
+// extension, a content type or a prefix.
+type analyzeGroup struct {
+	Name    string `json:"name"`
+	Objects int64  `json:"objects"`
+	Size    int64  `json:"size"`
+}
+
+// analyzeMessage - the distribution of the objects of a target.
+type analyzeMessage struct {
+	Status       string         `json:"status"`
+	Target       string         `json:"target"`
+	Objects      int64          `json:"objects"`
+	Size         int64          `json:"size"`
+	IsVersions   bool           `json:"isVersions,omitempty"`
+	Sizes        []analyzeGroup `json:"sizes"`
+	Ages         []analyzeGroup `json:"ages"`
+	Extensions   []analyzeGroup `json:"extensions"`
+	ContentTypes []analyzeGroup `json:"contentTypes"`
+	Prefixes     []analyzeGroup `json:"prefixes"`
+}
+
+// Age ranges of the objects, from the most recent.
+var analyzeAgeRanges = []struct {
+	name  string
+	below time.Duration
+	text  string
+}{
+	{"LESS_THAN_1_DAY", 24 * time.Hour, "less than a day old"},
+	{"BETWEEN_1_DAY_AND_1_WEEK", 7 * 24 * time.Hour, "between a day and a week old"},
+	{"BETWEEN_1_WEEK_AND_1_MONTH", 30 * 24 * time.Hour, "between a week and a month old"},
+	{"BETWEEN_1_MONTH_AND_3_MONTHS", 90 * 24 * time.Hour, "between a month and 3 months old"},
+	{"BETWEEN_3_MONTHS_AND_1_YEAR", 365 * 24 * time.Hour, "between 3 months and a year old"},
+	{"OLDER_THAN_1_YEAR", 0, "older than a year"},
+}
+
+// analyzeRangeText - returns the description of a size or age range.
+func analyzeRangeText(name string) string {
+	if def, ok := histogramTagsDesc[name]; ok {
+		return def.text
+	}
+	for _, r := range analyzeAgeRanges {
+		if r.name == name {
+			return r.text
+		}
+	}
+	return name
+}
+
+func (m analyzeMessage) String() string {
+	var b strings.Builder
+	objects := "object"
+	if m.IsVersions {
+		objects = "version"
+	}
+	if m.Objects != 1 {
+		objects += "s"
+	}
+	fmt.Fprintf(&b, "%s: %s %s, %s.\n", console.Colorize("AnalyzeTarget", quoteName(m.Target)),
+		console.Colorize("AnalyzeCount", humanize.Comma(m.Objects)), objects, console.Colorize("AnalyzeSize", humanize.IBytes(uint64(m.Size))))
+
+	section := func(title string, groups []analyzeGroup, name func(string) string) {
+		fmt.Fprintf(&b, "\n%s\n", console.Colorize("AnalyzeTitle", title+":"))
+		if len(groups) == 0 {
+			fmt.Fprintf(&b, "   none\n")
+			return
+		}
+		var countWidth int
+		for _, g := range groups {
+			if n := len(humanize.Comma(g.Objects)); n > countWidth {
+				countWidth = n
+			}
+		}
+		for _, g := range groups {
+			fmt.Fprintf(&b, "   %s  %s  %s  %s\n",
+				console.Colorize("AnalyzeCount", fmt.Sprintf("%*s", countWidth, humanize.Comma(g.Objects))),
+				console.Colorize("AnalyzeSize", fmt.Sprintf("%10s", humanize.IBytes(uint64(g.Size)))),
+				console.Colorize("AnalyzePercent", fmt.Sprintf("%5.1f%%", percentOf(g.Size, m.Size))),
+				name(g.Name))
+		}
+	}
+	section("Sizes", m.Sizes, analyzeRangeText)
+	section("Ages", m.Ages, analyzeRangeText)
+	section("Extensions", m.Extensions, func(s string) string { return s })
+	section("Content types", m.ContentTypes, func(s string) string { return s })
+	section("Prefixes", m.Prefixes, quoteName)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (m analyzeMessage) JSON() string {
+	m.Status = "success"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// htmlReport - returns the report as an HTML page.
+func (m analyzeMessage) htmlReport(created time.Time) htmlReport {
+	objects := "object"
+	if m.IsVersions {
+		objects = "version"
+	}
+	if m.Objects != 1 {
+		objects += "s"
+	}
+	report := htmlReport{
+		Title:    "Analysis of " + m.Target,
+		Subtitle: fmt.Sprintf("%s %s, %s.", humanize.Comma(m.Objects), objects, humanize.IBytes(uint64(m.Size))),
+		Created:  created,
+	}
+	section := func(title, column string, groups []analyzeGroup, name func(string) string) {
+		s := htmlReportSection{Title: title, Columns: []string{column, "Objects", "Size", "Share"}}
+		for _, g := range groups {
+			s.Rows = append(s.Rows, []string{name(g.Name), humanize.Comma(g.Objects), humanize.IBytes(uint64(g.Size)),
+				fmt.Sprintf("%.1f%%", percentOf(g.Size, m.Size))})
+			s.Bars = append(s.Bars, percentOf(g.Size, m.Size)/100)
+		}
+		report.Sections = append(report.Sections, s)
+	}
+	section("Sizes", "Size", m.Sizes, analyzeRangeText)
+	section("Ages", "Age", m.Ages, analyzeRangeText)
+	section("Extensions", "Extension", m.Extensions, func(s string) string { return s })
+	section("Content types", "Content type", m.ContentTypes, func(s string) string { return s })
+	section("Prefixes", "Prefix", m.Prefixes, func(s string) string { return s })
+	return report
+}
+
+func percentOf(v, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return 100 * float64(v) / float64(total)
+}
+
+// analyzer - accumulates the distribution of the objects listed below
+// a target.
+type analyzer struct {
+	target       string
+	depth        int
+	now          time.Time
+	msg          analyzeMessage
+	extensions   map[string]*analyzeGroup
+	contentTypes map[string]*analyzeGroup
+	prefixes     map[string]*analyzeGroup
+}
+
+func newAnalyzer(target string, depth int, now time.Time) *analyzer {
+	a := &analyzer{
+		target:       target,
+		depth:        depth,
+		now:          now,
+		msg:          analyzeMessage{Target: target},
+		extensions:   make(map[string]*analyzeGroup),
+		contentTypes: make(map[string]*analyzeGroup),
+		prefixes:     make(map[string]*analyzeGroup),
+	}
+	for _, tag := range sortHistogramTags() {
+		a.msg.Sizes = append(a.msg.Sizes, analyzeGroup{Name: tag})
+	}
+	for _, r := range analyzeAgeRanges {
+		a.msg.Ages = append(a.msg.Ages, analyzeGroup{Name: r.name})
+	}
+	return a
+}
+
+func addToGroup(groups map[string]*analyzeGroup, name string, size int64) {
+	g, ok := groups[name]
+	if !ok {
+		g = &analyzeGroup{Name: name}
+		groups[name] = g
+	}
+	g.Objects++
+	g.Size += size
+}
+
+// add - counts an object, named by its path relative to the target with
+// slash separators.
+func (a *analyzer) add(name, contentType string, size int64, modTime time.Time) {
+	a.msg.Objects++
+	a.msg.Size += size
+
+	for i, tag := range sortHistogramTags() {
+		def := histogramTagsDesc[tag]
+		if uint64(size) >= def.start && (def.end == 0 || uint64(size) < def.end) {
+			a.msg.Sizes[i].Objects++
+			a.msg.Sizes[i].Size += size
+			break
+		}
+	}
+	if !modTime.IsZero() {
+		age := a.now.Sub(modTime)
+		for i, r := range analyzeAgeRanges {
+			if r.below == 0 || age < r.below {
+				a.msg.Ages[i].Objects++
+				a.msg.Ages[i].Size += size
+				break
+			}
+		}
+	}
+
+	ext := strings.ToLower(path.Ext(path.Base(name)))
+	if contentType == "" {
+		contentType = mime.TypeByExtension(ext)
+	}
+	if t, _, e := mime.ParseMediaType(contentType); e == nil {
+		contentType = t
+	}
+	if ext == "" {
+		ext = "(none)"
+	}
+	if contentType == "" {
+		contentType = "(unknown)"
+	}
+	addToGroup(a.extensions, ext, size)
+	addToGroup(a.contentTypes, contentType, size)
+
+	prefix := strings.TrimSuffix(a.target, "/") + "/"
+	if dirs := strings.Split(name, "/"); len(dirs) > 1 {
+		if len(dirs)-1 > a.depth {
+			dirs = dirs[:a.depth+1]
+		}
+		prefix += strings.Join(dirs[:len(dirs)-1], "/") + "/"
+	}
+	addToGroup(a.prefixes, prefix, size)
+}
+
+// topGroups - returns the top groups holding the most data.
+func topGroups(groups map[string]*analyzeGroup, top int) []analyzeGroup {
+	sorted := make([]analyzeGroup, 0, len(groups))
+	for _, g := range groups {
+		sorted = append(sorted, *g)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Size != sorted[j].Size {
+			return sorted[i].Size > sorted[j].Size
+		}
+		if sorted[i].Objects != sorted[j].Objects {
+			return sorted[i].Objects > sorted[j].Objects
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	if top > 0 && len(sorted) > top {
+		sorted = sorted[:top]
+	}
+	return sorted
+}
+
+// report - returns the distribution of the objects, with the top
+// extensions, content types and prefixes.
+func (a *analyzer) report(top int) analyzeMessage {
+	msg := a.msg
+	msg.Extensions = topGroups(a.extensions, top)
+	msg.ContentTypes = topGroups(a.contentTypes, top)
+	msg.Prefixes = topGroups(a.prefixes, top)
+	return msg
+}
+
+// analyze - lists the objects below urlStr recursively.
+func analyze(ctx context.Context, urlStr string, timeRef time.Time, withVersions bool, depth int) (*analyzer, *probe.Error) {
+	targetAlias, targetURL, _ := mustExpandAlias(urlStr)
+	if !strings.HasSuffix(targetURL, "/") {
+		targetURL += "/"
+	}
+	clnt, err := newClientFromAlias(targetAlias, targetURL)
+	if err != nil {
+		return nil, err.Trace(urlStr)
+	}
+	clntURL := clnt.GetURL()
+	targetPath := strings.TrimSuffix(clntURL.Path, string(clntURL.Separator)) + string(clntURL.Separator)
+
+	a := newAnalyzer(urlStr, depth, time.Now())
+	a.msg.IsVersions = withVersions
+	for content := range clnt.List(ctx, ListOptions{
+		Recursive:         true,
+		WithMetadata:      true,
+		WithOlderVersions: withVersions,
+		TimeRef:           timeRef,
+		ShowDir:           DirNone,
+	}) {
+		if content.Err != nil {
+			switch content.Err.ToGoError().(type) {
+			// Same as du, files which can't be read are skipped.
+			case BrokenSymlink, TooManyLevelsSymlink, PathNotFound, ObjectOnGlacier:
+				continue
+			case PathInsufficientPermission:
+				errorIf(content.Err.Trace(clntURL.String()), "Unable to list folder.")
+				continue
+			}
+			return nil, content.Err.Trace(urlStr)
+		}
+		if content.IsDeleteMarker || content.Type.IsDir() {
+			continue
+		}
+		name := strings.TrimPrefix(content.URL.Path, targetPath)
+		if clntURL.Separator != '/' {
+			name = strings.ReplaceAll(name, string(clntURL.Separator), "/")
+		}
+		a.add(name, content.ContentType, content.Size, content.Time)
+	}
+	return a, nil
+}
+
+// main for analyze command.
+func mainAnalyze(cliCtx *cli.Context) error {
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1)
+	}
+
+	console.SetColor("AnalyzeTarget", color.New(color.FgCyan, color.Bold))
+	console.SetColor("AnalyzeTitle", color.New(color.Bold))
+	console.SetColor("AnalyzeCount", color.New(color.FgGreen))
+	console.SetColor("AnalyzeSize", color.New(color.FgYellow))
+	console.SetColor("AnalyzePercent", color.New(color.FgHiBlack))
+
+	depth := cliCtx.Int("depth")
+	if depth < 1 {
+		fatalIf(errInvalidArgument().Trace(cliCtx.String("depth")), "The depth must be 1 or more.")
+	}
+	urlStr := cliCtx.Args().First()
+
+	ctx, cancelAnalyze := context.WithCancel(globalContext)
+	defer cancelAnalyze()
+
+	a, err := analyze(ctx, urlStr, parseRewindFlag(cliCtx.String("rewind")), cliCtx.Bool("versions"), depth)
+	fatalIf(err, "Unable to analyze `"+urlStr+"`.")
+	msg := a.report(cliCtx.Int("top"))
+
+	if reportPath := cliCtx.String("report-html"); reportPath != "" {
+		fatalIf(msg.htmlReport(time.Now()).write(reportPath), "Unable to save the report.")
+	}
+	printMsg(msg)
+	return nil
+}
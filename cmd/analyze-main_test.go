@@ -0,0 +1,89 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnalyzer(t *testing.T) {
+	now := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	a := newAnalyzer("play/bucket", 1, now)
+	a.add("readme.txt", "", 100, now.Add(-time.Hour))
+	a.add("logs/2023/app.LOG", "text/plain; charset=utf-8", 5000, now.Add(-10*24*time.Hour))
+	a.add("logs/2022/app.log", "", 2<<20, now.Add(-400*24*time.Hour))
+	a.add("images/cat.png", "image/png", 20<<20, time.Time{})
+	msg := a.report(2)
+
+	if msg.Objects != 4 || msg.Size != 100+5000+2<<20+20<<20 {
+		t.Errorf("got %d objects of %d bytes", msg.Objects, msg.Size)
+	}
+	sizes := []int64{1, 1, 1, 1, 0, 0, 0}
+	for i, g := range msg.Sizes {
+		if g.Objects != sizes[i] {
+			t.Errorf("%s: got %d objects, want %d", g.Name, g.Objects, sizes[i])
+		}
+	}
+	// Objects without a modification time have no age.
+	ages := []int64{1, 0, 1, 0, 0, 1}
+	for i, g := range msg.Ages {
+		if g.Objects != ages[i] {
+			t.Errorf("%s: got %d objects, want %d", g.Name, g.Objects, ages[i])
+		}
+	}
+	if want := []analyzeGroup{{".png", 1, 20 << 20}, {".log", 2, 2<<20 + 5000}}; !reflect.DeepEqual(msg.Extensions, want) {
+		t.Errorf("extensions %v, want %v", msg.Extensions, want)
+	}
+	// The content types of the extensions depend on the system.
+	if want := (analyzeGroup{"image/png", 1, 20 << 20}); len(msg.ContentTypes) == 0 || msg.ContentTypes[0] != want {
+		t.Errorf("content types %v, want %v first", msg.ContentTypes, want)
+	}
+	if want := []analyzeGroup{{"play/bucket/images/", 1, 20 << 20}, {"play/bucket/logs/", 2, 2<<20 + 5000}}; !reflect.DeepEqual(msg.Prefixes, want) {
+		t.Errorf("prefixes %v, want %v", msg.Prefixes, want)
+	}
+
+	a = newAnalyzer("play/bucket/", 2, now)
+	a.add("logs/2023/app.log", "", 1, now)
+	a.add("logs/app.log", "", 1, now)
+	if got := a.report(0).Prefixes; len(got) != 2 || got[0].Name != "play/bucket/logs/" || got[1].Name != "play/bucket/logs/2023/" {
+		t.Errorf("prefixes at depth 2 %v", got)
+	}
+}
+
+func TestAnalyzeHTMLReport(t *testing.T) {
+	a := newAnalyzer("play/<bucket>", 1, time.Now())
+	a.add("a.txt", "", 10, time.Now())
+	path := filepath.Join(t.TempDir(), "report.html")
+	if err := a.report(10).htmlReport(time.Now()).write(path); err != nil {
+		t.Fatal(err)
+	}
+	page, e := os.ReadFile(path)
+	if e != nil {
+		t.Fatal(e)
+	}
+	for _, want := range []string{"<title>Analysis of play/&lt;bucket&gt;</title>", "<td>.txt</td>", `style="width: 100.0%"`} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("the report has no %q", want)
+		}
+	}
+}
@@ -0,0 +1,460 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7/pkg/policy"
+	"github.com/minio/pkg/v2/console"
+)
+
+var auditAccessCmd = cli.Command{
+	Name:         "access",
+	Usage:        "report what anyone may read or write in buckets",
+	Action:       mainAuditAccess,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET
+
+  TARGET is an alias, to audit all its buckets, or a bucket. The report of
+  a bucket combines its policy, its ACL, its public access block and the
+  share links made by 'mc share' that have not expired yet. Anything that
+  anyone may read or write without credentials is flagged, unless the public
+  access block of the bucket ignores it. The statements of the bucket policy
+  that deny access are not evaluated.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Audit the access to all the buckets of an alias.
+     {{.Prompt}} {{.HelpName}} s3
+
+  2. Audit the access to a bucket, in JSON.
+     {{.Prompt}} {{.HelpName}} --json s3/mybucket
+`,
+}
+
+// Sources of the findings of an access audit.
+const (
+	auditSourcePolicy = "policy"
+	auditSourceACL    = "acl"
+	auditSourceShare  = "share"
+)
+
+// auditFinding - something that anyone may read or write.
+type auditFinding struct {
+	Source   string `json:"source"`
+	Access   string `json:"access"` // read, write or read-write
+	Resource string `json:"resource"`
+	Detail   string `json:"detail"`
+	// Ignored by the public access block of the bucket.
+	Blocked bool `json:"blocked,omitempty"`
+}
+
+// auditAccessMessage - the access report of a bucket.
+type auditAccessMessage struct {
+	Status            string             `json:"status"`
+	URL               string             `json:"url"`
+	Policy            string             `json:"policy,omitempty"`
+	ACL               string             `json:"acl,omitempty"`
+	PublicAccessBlock *publicAccessBlock `json:"publicAccessBlock,omitempty"`
+	Shares            int                `json:"shares"`
+	Readable          bool               `json:"publiclyReadable"`
+	Writable          bool               `json:"publiclyWritable"`
+	Findings          []auditFinding     `json:"findings,omitempty"`
+	// The checks which failed, the server may not implement them.
+	Unchecked []string `json:"unchecked,omitempty"`
+}
+
+// String colorized access audit message
+func (a auditAccessMessage) String() string {
+	var b strings.Builder
+	switch {
+	case a.Readable && a.Writable:
+		b.WriteString(console.Colorize("AuditPublic", a.URL+": publicly readable and writable"))
+	case a.Readable:
+		b.WriteString(console.Colorize("AuditPublic", a.URL+": publicly readable"))
+	case a.Writable:
+		b.WriteString(console.Colorize("AuditPublic", a.URL+": publicly writable"))
+	default:
+		b.WriteString(console.Colorize("AuditPrivate", a.URL+": private"))
+	}
+	b.WriteString("\n")
+
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "  %-15s %s\n", name+":", value)
+		}
+	}
+	field("Policy", a.Policy)
+	field("ACL", a.ACL)
+	switch block := a.PublicAccessBlock; {
+	case block != nil:
+		var settings []string
+		for _, s := range []struct {
+			name string
+			on   bool
+		}{
+			{"BlockPublicAcls", block.BlockPublicAcls},
+			{"IgnorePublicAcls", block.IgnorePublicAcls},
+			{"BlockPublicPolicy", block.BlockPublicPolicy},
+			{"RestrictPublicBuckets", block.RestrictPublicBuckets},
+		} {
+			if s.on {
+				settings = append(settings, s.name)
+			}
+		}
+		if len(settings) == 0 {
+			settings = []string{"none"}
+		}
+		field("Public access", "blocked by "+strings.Join(settings, ", "))
+	case !a.unchecked(auditSourcePublicAccessBlock):
+		field("Public access", "not blocked")
+	}
+	field("Share links", fmt.Sprint(a.Shares))
+	for _, f := range a.Findings {
+		line := fmt.Sprintf("%-10s %-6s %s: %s", f.Access, f.Source, f.Resource, f.Detail)
+		if f.Blocked {
+			fmt.Fprintf(&b, "  %s\n", console.Colorize("AuditBlocked", "- "+line+" (blocked)"))
+		} else {
+			fmt.Fprintf(&b, "  %s\n", console.Colorize("AuditPublic", "! "+line))
+		}
+	}
+	for _, u := range a.Unchecked {
+		fmt.Fprintf(&b, "  %s\n", console.Colorize("AuditUnchecked", "? unchecked "+u))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// JSON jsonified access audit message
+func (a auditAccessMessage) JSON() string {
+	a.Status = "success"
+	msgBytes, e := json.MarshalIndent(a, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// The public access block, a check without findings of its own.
+const auditSourcePublicAccessBlock = "public access block"
+
+func (a auditAccessMessage) unchecked(source string) bool {
+	for _, u := range a.Unchecked {
+		if strings.HasPrefix(u, source+":") {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *auditAccessMessage) addUnchecked(source string, err *probe.Error) {
+	a.Unchecked = append(a.Unchecked, source+": "+err.ToGoError().Error())
+}
+
+// auditAccessKind - the kind of access of an action of a policy, read
+// for the actions reading objects or listing them, empty for the actions
+// disclosing nothing.
+func auditAccessKind(action string) string {
+	action = strings.ToLower(action)
+	switch {
+	case action == "*" || action == "s3:*":
+		return "read-write"
+	case action == "s3:getbucketlocation":
+		return ""
+	case strings.HasPrefix(action, "s3:get") || strings.HasPrefix(action, "s3:list"):
+		return "read"
+	default:
+		return "write"
+	}
+}
+
+// mergeAccess - returns the access allowing both a and b.
+func mergeAccess(a, b string) string {
+	switch {
+	case a == "" || a == b:
+		return b
+	case b == "":
+		return a
+	default:
+		return "read-write"
+	}
+}
+
+// auditPolicy - returns the findings of the statements of the bucket
+// policy allowing anyone, in the order of the statements.
+func auditPolicy(policyJSON string) ([]auditFinding, *probe.Error) {
+	if policyJSON == "" {
+		return nil, nil
+	}
+	var p policy.BucketAccessPolicy
+	if e := json.Unmarshal([]byte(policyJSON), &p); e != nil {
+		return nil, probe.NewError(e)
+	}
+	var findings []auditFinding
+	for _, statement := range p.Statements {
+		if statement.Effect != "Allow" || !statement.Principal.AWS.Contains("*") {
+			continue
+		}
+		actions := statement.Actions.ToSlice()
+		var access string
+		for _, action := range actions {
+			access = mergeAccess(access, auditAccessKind(action))
+		}
+		if access == "" {
+			continue
+		}
+		detail := strings.Join(actions, ", ") + " allowed to anyone"
+		if len(statement.Conditions) > 0 {
+			detail += " under conditions"
+		}
+		findings = append(findings, auditFinding{
+			Source:   auditSourcePolicy,
+			Access:   access,
+			Resource: strings.Join(statement.Resources.ToSlice(), ", "),
+			Detail:   detail,
+		})
+	}
+	return findings, nil
+}
+
+// auditACL - returns the findings of the grants of the bucket ACL to all
+// users, with or without credentials.
+func auditACL(acl *bucketACL) (findings []auditFinding) {
+	for _, grant := range acl.Grants {
+		var grantee string
+		switch grant.Grantee.URI {
+		case aclAllUsersGroup:
+			grantee = "all users"
+		case aclAuthenticatedUsersGroup:
+			grantee = "all authenticated users"
+		default:
+			continue
+		}
+		var access string
+		switch grant.Permission {
+		case "READ", "READ_ACP":
+			access = "read"
+		case "WRITE", "WRITE_ACP":
+			access = "write"
+		default:
+			access = "read-write"
+		}
+		findings = append(findings, auditFinding{
+			Source:   auditSourceACL,
+			Access:   access,
+			Resource: "bucket",
+			Detail:   grant.Permission + " granted to " + grantee,
+		})
+	}
+	return findings
+}
+
+// auditShares - returns the findings of the share links of the objects
+// of bucketURL which have not expired, sorted by object.
+func auditShares(bucketURL string, downloads, uploads *shareDBV1) (findings []auditFinding) {
+	for _, db := range []struct {
+		shares *shareDBV1
+		access string
+		kind   string
+	}{
+		{downloads, "read", "download"},
+		{uploads, "write", "upload"},
+	} {
+		if db.shares == nil {
+			continue
+		}
+		for _, share := range db.shares.Shares {
+			left := share.Expiry - time.Since(share.Date)
+			if left <= 0 || (share.URL != bucketURL && !strings.HasPrefix(share.URL, bucketURL+"/")) {
+				continue
+			}
+			findings = append(findings, auditFinding{
+				Source:   auditSourceShare,
+				Access:   db.access,
+				Resource: share.URL,
+				Detail:   db.kind + " link expiring in " + timeDurationToHumanizedDuration(left).StringShort(),
+			})
+		}
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Resource != findings[j].Resource {
+			return findings[i].Resource < findings[j].Resource
+		}
+		return findings[i].Access < findings[j].Access
+	})
+	return findings
+}
+
+// loadShares - loads the share links of a share file, which does not exist
+// until a first share.
+func loadShares(file string) (*shareDBV1, *probe.Error) {
+	shares := newShareDBV1()
+	if err := shares.Load(file); err != nil {
+		if os.IsNotExist(err.ToGoError()) {
+			return nil, nil
+		}
+		return nil, err.Trace(file)
+	}
+	return shares, nil
+}
+
+// auditBucketAccess - returns the access report of the bucket.
+func auditBucketAccess(ctx context.Context, bucketURL string, downloads, uploads *shareDBV1) (auditAccessMessage, *probe.Error) {
+	msg := auditAccessMessage{URL: bucketURL}
+	clnt, err := newClient(bucketURL)
+	if err != nil {
+		return msg, err.Trace(bucketURL)
+	}
+	s3Clnt, ok := unwrapClient(clnt).(*S3Client)
+	if !ok {
+		return msg, probe.NewError(fmt.Errorf("`%s` is not an S3 bucket", bucketURL))
+	}
+
+	var findings []auditFinding
+	access, policyJSON, err := s3Clnt.GetAccess(ctx)
+	if err == nil {
+		var policyFindings []auditFinding
+		if policyFindings, err = auditPolicy(policyJSON); err == nil {
+			msg.Policy = access
+			findings = append(findings, policyFindings...)
+		}
+	}
+	if err != nil {
+		msg.addUnchecked(auditSourcePolicy, err)
+	}
+
+	if acl, err := s3Clnt.GetBucketACL(ctx); err != nil {
+		msg.addUnchecked(auditSourceACL, err)
+	} else {
+		aclFindings := auditACL(acl)
+		msg.ACL = "private"
+		if len(aclFindings) > 0 {
+			msg.ACL = "public"
+		}
+		findings = append(findings, aclFindings...)
+	}
+
+	block, err := s3Clnt.GetPublicAccessBlock(ctx)
+	if err != nil {
+		msg.addUnchecked(auditSourcePublicAccessBlock, err)
+	}
+	msg.PublicAccessBlock = block
+
+	shareFindings := auditShares(s3Clnt.GetURL().String(), downloads, uploads)
+	msg.Shares = len(shareFindings)
+	findings = append(findings, shareFindings...)
+
+	for i, f := range findings {
+		if block != nil {
+			switch f.Source {
+			case auditSourcePolicy:
+				findings[i].Blocked = block.RestrictPublicBuckets
+			case auditSourceACL:
+				findings[i].Blocked = block.IgnorePublicAcls
+			}
+		}
+		if findings[i].Blocked {
+			continue
+		}
+		msg.Readable = msg.Readable || f.Access != "write"
+		msg.Writable = msg.Writable || f.Access != "read"
+	}
+	msg.Findings = findings
+	return msg, nil
+}
+
+// auditBuckets - returns the buckets of targetURL, the bucket itself or
+// all the buckets of an alias.
+func auditBuckets(ctx context.Context, targetURL string) ([]string, *probe.Error) {
+	targetURL = strings.TrimSuffix(targetURL, "/")
+	alias, path := url2Alias(targetURL)
+	if alias == "" {
+		return nil, probe.NewError(fmt.Errorf("`%s` is not an alias or a bucket", targetURL))
+	}
+	if path = strings.Trim(path, "/"); path != "" {
+		if strings.Contains(path, "/") {
+			return nil, probe.NewError(fmt.Errorf("`%s` is not an alias or a bucket", targetURL))
+		}
+		return []string{targetURL}, nil
+	}
+	clnt, err := newClient(targetURL)
+	if err != nil {
+		return nil, err.Trace(targetURL)
+	}
+	buckets, err := clnt.ListBuckets(ctx)
+	if err != nil {
+		return nil, err.Trace(targetURL)
+	}
+	var bucketURLs []string
+	for _, b := range buckets {
+		name := strings.TrimSuffix(b.URL.Path, string(b.URL.Separator))
+		name = name[strings.LastIndex(name, string(b.URL.Separator))+1:]
+		bucketURLs = append(bucketURLs, alias+"/"+name)
+	}
+	return bucketURLs, nil
+}
+
+// main for audit access command.
+func mainAuditAccess(cliCtx *cli.Context) error {
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	console.SetColor("AuditPublic", color.New(color.FgRed, color.Bold))
+	console.SetColor("AuditPrivate", color.New(color.FgGreen, color.Bold))
+	console.SetColor("AuditBlocked", color.New(color.FgYellow))
+	console.SetColor("AuditUnchecked", color.New(color.FgHiBlack))
+
+	ctx, cancelAudit := context.WithCancel(globalContext)
+	defer cancelAudit()
+
+	targetURL := cliCtx.Args().First()
+	bucketURLs, err := auditBuckets(ctx, targetURL)
+	fatalIf(err, "Unable to list the buckets of `%s`.", targetURL)
+
+	downloads, err := loadShares(getShareDownloadsFile())
+	fatalIf(err, "Unable to load the shared downloads.")
+	uploads, err := loadShares(getShareUploadsFile())
+	fatalIf(err, "Unable to load the shared uploads.")
+
+	var retErr error
+	for _, bucketURL := range bucketURLs {
+		msg, err := auditBucketAccess(ctx, bucketURL, downloads, uploads)
+		if err != nil {
+			errorIf(err, "Unable to audit `%s`.", bucketURL)
+			retErr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		printMsg(msg)
+	}
+	return retErr
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// aclHandler - serves a bucket with a public ACL and without public
+// access block.
+type aclHandler struct{}
+
+func (aclHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	switch {
+	case r.Method != http.MethodGet || r.URL.Path != "/bucket/":
+		w.WriteHeader(http.StatusBadRequest)
+	case query.Has("location"):
+		w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+	case query.Has("acl"):
+		w.Write([]byte(`<AccessControlPolicy xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Owner><ID>owner</ID></Owner><AccessControlList>` +
+			`<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="CanonicalUser"><ID>owner</ID></Grantee><Permission>FULL_CONTROL</Permission></Grant>` +
+			`<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="Group"><URI>http://acs.amazonaws.com/groups/global/AllUsers</URI></Grantee><Permission>READ</Permission></Grant>` +
+			`</AccessControlList></AccessControlPolicy>`))
+	case query.Has("publicAccessBlock"):
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`<Error><Code>NoSuchPublicAccessBlockConfiguration</Code><Message>The public access block configuration was not found</Message></Error>`))
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestBucketACL(t *testing.T) {
+	server := httptest.NewServer(aclHandler{})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	clnt, err := S3New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s3Clnt := clnt.(*S3Client)
+
+	acl, err := s3Clnt.GetBucketACL(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []auditFinding{{Source: "acl", Access: "read", Resource: "bucket", Detail: "READ granted to all users"}}
+	if findings := auditACL(acl); !reflect.DeepEqual(findings, want) {
+		t.Errorf("got %+v, want %+v", findings, want)
+	}
+
+	block, err := s3Clnt.GetPublicAccessBlock(context.Background())
+	if err != nil || block != nil {
+		t.Errorf("got %+v, %v, want no public access block", block, err)
+	}
+}
+
+func TestAuditPolicy(t *testing.T) {
+	policyJSON := `{"Version":"2012-10-17","Statement":[` +
+		`{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetBucketLocation","s3:ListBucket"],"Resource":["arn:aws:s3:::bucket"]},` +
+		`{"Effect":"Allow","Principal":"*","Action":["s3:GetObject","s3:PutObject"],"Resource":["arn:aws:s3:::bucket/uploads/*"],"Condition":{"IpAddress":{"aws:SourceIp":["10.0.0.0/8"]}}},` +
+		`{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetBucketLocation"],"Resource":["arn:aws:s3:::bucket"]},` +
+		`{"Effect":"Allow","Principal":{"AWS":["arn:aws:iam::123456789012:root"]},"Action":["s3:*"],"Resource":["arn:aws:s3:::bucket/*"]},` +
+		`{"Effect":"Deny","Principal":{"AWS":["*"]},"Action":["s3:DeleteObject"],"Resource":["arn:aws:s3:::bucket/*"]}]}`
+	findings, err := auditPolicy(policyJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []auditFinding{
+		{Source: "policy", Access: "read", Resource: "arn:aws:s3:::bucket", Detail: "s3:GetBucketLocation, s3:ListBucket allowed to anyone"},
+		{Source: "policy", Access: "read-write", Resource: "arn:aws:s3:::bucket/uploads/*", Detail: "s3:GetObject, s3:PutObject allowed to anyone under conditions"},
+	}
+	if !reflect.DeepEqual(findings, want) {
+		t.Errorf("got %+v, want %+v", findings, want)
+	}
+
+	if findings, err = auditPolicy(""); err != nil || len(findings) != 0 {
+		t.Errorf("got %+v, %v, want no findings without policy", findings, err)
+	}
+}
+
+func TestAuditShares(t *testing.T) {
+	downloads := newShareDBV1()
+	downloads.Shares["https://s3/bucket/a?sig"] = shareEntryV1{URL: "https://s3/bucket/a", Date: time.Now(), Expiry: time.Hour}
+	downloads.Shares["https://s3/bucket/b?sig"] = shareEntryV1{URL: "https://s3/bucket/b", Date: time.Now().Add(-2 * time.Hour), Expiry: time.Hour}
+	downloads.Shares["https://s3/bucket2/a?sig"] = shareEntryV1{URL: "https://s3/bucket2/a", Date: time.Now(), Expiry: time.Hour}
+	uploads := newShareDBV1()
+	uploads.Shares["https://s3/bucket"] = shareEntryV1{URL: "https://s3/bucket/in/", Date: time.Now(), Expiry: 24 * time.Hour}
+
+	findings := auditShares("https://s3/bucket", downloads, uploads)
+	if len(findings) != 2 {
+		t.Fatalf("got %+v, want 2 findings", findings)
+	}
+	if f := findings[0]; f.Resource != "https://s3/bucket/a" || f.Access != "read" {
+		t.Errorf("got %+v, want the download of a", f)
+	}
+	if f := findings[1]; f.Resource != "https://s3/bucket/in/" || f.Access != "write" {
+		t.Errorf("got %+v, want the upload to in/", f)
+	}
+	if findings := auditShares("https://s3/bucket", nil, nil); len(findings) != 0 {
+		t.Errorf("got %+v, want no findings without shares", findings)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"github.com/minio/cli"
+	"github.com/minio/pkg/v2/env"
 	"github.com/posener/complete"
 )
 
@@ -59,6 +60,9 @@ func (fs fsComplete) Predict(a complete.Args) []string {
 }
 
 func completeAdminConfigKeys(aliasPath, keyPrefix string) (prediction []string) {
+	if !isRemoteCompletion() {
+		return nil
+	}
 	// Convert alias/bucket/incompl to alias/bucket/ to list its contents
 	parentDirPath := filepath.Dir(aliasPath) + "/"
 	clnt, err := newAdminClient(parentDirPath)
@@ -84,6 +88,9 @@ func completeAdminConfigKeys(aliasPath, keyPrefix string) (prediction []string)
 // then recursively scans it. This is needed to satisfy posener/complete
 // (look at posener/complete.PredictFiles)
 func completeS3Path(s3Path string) (prediction []string) {
+	if !isRemoteCompletion() {
+		return nil
+	}
 	// Convert alias/bucket/incompl to alias/bucket/ to list its contents
 	parentDirPath := filepath.Dir(s3Path) + "/"
 	clnt, err := newClient(parentDirPath)
@@ -246,6 +253,26 @@ var completeCmds = map[string]complete.Predictor{
 	"/anonymous": complete.PredictOr(s3Completer, fsCompleter),
 	"/tree":      complete.PredictOr(s3Complete{deepLevel: 2}, fsCompleter),
 	"/du":        complete.PredictOr(s3Complete{deepLevel: 2}, fsCompleter),
+	"/analyze":   complete.PredictOr(s3Complete{deepLevel: 2}, fsCompleter),
+	"/verify":    complete.PredictOr(s3Completer, fsCompleter),
+
+	"/audit/access": s3Completer,
+	"/completion":   complete.PredictSet("bash", "zsh", "fish"),
+	"/shell":        s3Completer,
+
+	"/snapshot/create": complete.PredictOr(s3Completer, fsCompleter),
+	"/snapshot/diff":   fsCompleter,
+
+	"/daemon/install":   nil,
+	"/daemon/uninstall": nil,
+
+	"/job/submit": nil,
+	"/job/list":   nil,
+	"/job/logs":   nil,
+	"/job/cancel": nil,
+
+	"/migrate/plan": fsCompleter,
+	"/migrate/run":  fsCompleter,
 
 	"/retention/set":   s3Completer,
 	"/retention/clear": s3Completer,
@@ -266,6 +293,10 @@ var completeCmds = map[string]complete.Predictor{
 	"/encrypt/info":  s3Complete{deepLevel: 2},
 	"/encrypt/clear": s3Complete{deepLevel: 2},
 
+	"/encrypt/key/set":    s3Completer,
+	"/encrypt/key/list":   aliasCompleter,
+	"/encrypt/key/remove": s3Completer,
+
 	"/replicate/add":     s3Complete{deepLevel: 2},
 	"/replicate/edit":    s3Complete{deepLevel: 2},
 	"/replicate/update":  s3Complete{deepLevel: 2},
@@ -489,12 +520,14 @@ var completeCmds = map[string]complete.Predictor{
 	"/support/top/disk":     aliasCompleter,
 	"/support/top/net":      aliasCompleter,
 	"/support/upload":       aliasCompleter,
+	"/support/features":     s3Completer,
 
 	"/license/register": aliasCompleter,
 	"/license/info":     aliasCompleter,
 	"/license/update":   aliasCompleter,
 
 	"/update":         nil,
+	"/usage":          aliasCompleter,
 	"/ready":          aliasCompleter,
 	"/ping":           aliasCompleter,
 	"/od":             nil,
@@ -554,6 +587,12 @@ func cmdToCompleteCmd(cmd cli.Command, parentPath string) complete.Command {
 
 // Main function to answer to bash completion calls
 func mainComplete() error {
+	// The flags are not parsed, the aliases are read from the config
+	// folder of the environment.
+	if configDir := env.Get(envPrefix+"CONFIG_DIR", ""); configDir != "" {
+		setMcConfigDir(configDir)
+	}
+
 	// Recursively register all commands and subcommands
 	// along with global and local flags
 	complCmds := make(complete.Commands)
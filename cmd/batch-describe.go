@@ -19,9 +19,9 @@ package cmd
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
 	"github.com/minio/mc/pkg/probe"
 )
 
@@ -73,6 +73,24 @@ func mainBatchDescribe(ctx *cli.Context) error {
 	job, e := adminClient.DescribeBatchJob(ctxt, jobID)
 	fatalIf(probe.NewError(e), "Unable to fetch the job definition")
 
-	fmt.Println(job)
+	printMsg(batchJobDefinitionMessage{Status: "success", Job: job})
 	return nil
 }
+
+// batchJobDefinitionMessage - YAML definition of a batch job.
+type batchJobDefinitionMessage struct {
+	Status string `json:"status"`
+	Job    string `json:"job"`
+}
+
+// String - the YAML definition as is.
+func (m batchJobDefinitionMessage) String() string {
+	return m.Job
+}
+
+// JSON jsonified batch job definition message.
+func (m batchJobDefinitionMessage) JSON() string {
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
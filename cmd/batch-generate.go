@@ -18,7 +18,6 @@
 package cmd
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/minio/cli"
@@ -96,6 +95,6 @@ func mainBatchGenerate(ctx *cli.Context) error {
 	})
 	fatalIf(probe.NewError(e), "Unable to generate %s", args.Get(1))
 
-	fmt.Println(string(out))
+	printMsg(batchJobDefinitionMessage{Status: "success", Job: string(out)})
 	return nil
 }
@@ -0,0 +1,104 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// buildFeature - an optional subsystem of mc, compiled in or not
+// depending on the target platform and the build tags.
+type buildFeature struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+}
+
+// buildFeatures - returns the optional subsystems of this binary.
+func buildFeatures() []buildFeature {
+	return []buildFeature{
+		{Name: "fips", Description: "FIPS 140-2 release channel of mc update", Enabled: buildWithFIPS},
+		{Name: "pipe-buffer", Description: "resizable stdin buffer of mc pipe --pipe-max-size", Enabled: buildWithPipeBuffer},
+		{Name: "xattr", Description: "extended attributes preserved by cp and mirror -a", Enabled: buildWithXattr},
+	}
+}
+
+// errNotBuiltWith - the error of the features of a subsystem this binary
+// was built without.
+func errNotBuiltWith(name string) *probe.Error {
+	for _, feature := range buildFeatures() {
+		if feature.Name == name {
+			return probe.NewError(fmt.Errorf("this mc was not built with %s, the %s, for %s/%s", name, feature.Description, runtime.GOOS, runtime.GOARCH)).Untrace()
+		}
+	}
+	return probe.NewError(fmt.Errorf("this mc was not built with %s", name)).Untrace()
+}
+
+// versionMessage - the version of mc printed by `mc --version --json`.
+type versionMessage struct {
+	Status    string         `json:"status"`
+	Version   string         `json:"version"`
+	CommitID  string         `json:"commitID"`
+	Runtime   string         `json:"runtime"`
+	Platform  string         `json:"platform"`
+	Features  []buildFeature `json:"features"`
+	Copyright string         `json:"copyright"`
+	License   string         `json:"license"`
+}
+
+func newVersionMessage(version string) versionMessage {
+	return versionMessage{
+		Status:    "success",
+		Version:   version,
+		CommitID:  CommitID,
+		Runtime:   runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		Features:  buildFeatures(),
+		Copyright: "Copyright (c) 2015-" + CopyrightYear + " MinIO, Inc.",
+		License:   "GNU AGPLv3 <https://www.gnu.org/licenses/agpl-3.0.html>",
+	}
+}
+
+// print - writes the version as text, or as JSON.
+func (v versionMessage) print(w io.Writer, name string, asJSON bool) {
+	if asJSON {
+		versionBytes, e := json.MarshalIndent(v, "", " ")
+		fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+		fmt.Fprintln(w, string(versionBytes))
+		return
+	}
+	features := make([]string, 0, len(v.Features))
+	for _, feature := range v.Features {
+		sign := "-"
+		if feature.Enabled {
+			sign = "+"
+		}
+		features = append(features, sign+feature.Name)
+	}
+	fmt.Fprintf(w, "%s version %s (commit-id=%s)\n", name, v.Version, v.CommitID)
+	fmt.Fprintf(w, "Runtime: %s %s\n", v.Runtime, v.Platform)
+	fmt.Fprintf(w, "Features: %s\n", strings.Join(features, " "))
+	fmt.Fprintf(w, "%s\n", v.Copyright)
+	fmt.Fprintf(w, "License %s\n", v.License)
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestVersionMessage(t *testing.T) {
+	var buf bytes.Buffer
+	newVersionMessage("RELEASE.2024-01-01T00-00-00Z").print(&buf, "mc", true)
+	var v versionMessage
+	if e := json.Unmarshal(buf.Bytes(), &v); e != nil {
+		t.Fatal(e)
+	}
+	if v.Version != "RELEASE.2024-01-01T00-00-00Z" || len(v.Features) != len(buildFeatures()) {
+		t.Errorf("unexpected version %+v", v)
+	}
+
+	buf.Reset()
+	newVersionMessage("RELEASE.2024-01-01T00-00-00Z").print(&buf, "mc", false)
+	sign := "-"
+	if buildWithPipeBuffer {
+		sign = "+"
+	}
+	if !strings.Contains(buf.String(), sign+"pipe-buffer") {
+		t.Errorf("expected the features to be listed, got %q", buf.String())
+	}
+
+	if msg := errNotBuiltWith("xattr").ToGoError().Error(); !strings.Contains(msg, "not built with xattr, the extended attributes") {
+		t.Errorf("unexpected error %q", msg)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"strings"
 	"syscall"
 	"time"
@@ -55,6 +56,10 @@ var catFlags = []cli.Flag{
 		Name:  "tail",
 		Usage: "tail number of bytes at ending of file",
 	},
+	cli.Int64Flag{
+		Name:  "length",
+		Usage: "number of bytes displayed from the start offset, or from the start of the tail",
+	},
 }
 
 // Display contents of a file.
@@ -99,6 +104,9 @@ EXAMPLES:
 
   7. Display the content of a particular object version
      {{.Prompt}} {{.HelpName}} --vid "3ddac055-89a7-40fa-8cd3-530a5581b6b8" play/my-bucket/my-object
+
+  8. Display 1MiB of a large log object from its 10GiB offset, without downloading the rest of it.
+     {{.Prompt}} {{.HelpName}} --offset 10737418240 --length 1048576 play/logs/app.log
 `,
 }
 
@@ -164,6 +172,7 @@ type catOpts struct {
 	timeRef   time.Time
 	startO    int64
 	tailO     int64
+	lengthO   int64
 	isZip     bool
 	stdinMode bool
 }
@@ -199,17 +208,18 @@ func parseCatSyntax(ctx *cli.Context) catOpts {
 	o.isZip = ctx.Bool("zip")
 	o.startO = ctx.Int64("offset")
 	o.tailO = ctx.Int64("tail")
+	o.lengthO = ctx.Int64("length")
 	if o.tailO != 0 && o.startO != 0 {
 		fatalIf(errInvalidArgument().Trace(), "You cannot specify both --tail and --offset")
 	}
-	if o.tailO < 0 || o.startO < 0 {
-		fatalIf(errInvalidArgument().Trace(), "You cannot specify negative --tail or --offset")
+	if o.tailO < 0 || o.startO < 0 || o.lengthO < 0 {
+		fatalIf(errInvalidArgument().Trace(), "You cannot specify negative --tail, --offset or --length")
 	}
-	if o.isZip && (o.tailO != 0 || o.startO != 0) {
-		fatalIf(errInvalidArgument().Trace(), "You cannot combine --zip with --tail or --offset")
+	if o.isZip && (o.tailO != 0 || o.startO != 0 || o.lengthO != 0) {
+		fatalIf(errInvalidArgument().Trace(), "You cannot combine --zip with --tail, --offset or --length")
 	}
-	if o.stdinMode && (o.isZip || o.startO != 0 || o.tailO != 0) {
-		fatalIf(errInvalidArgument().Trace(), "You cannot use --zip --tail or --offset with stdin")
+	if o.stdinMode && (o.isZip || o.startO != 0 || o.tailO != 0 || o.lengthO != 0) {
+		fatalIf(errInvalidArgument().Trace(), "You cannot use --zip --tail, --offset or --length with stdin")
 	}
 
 	return o
@@ -257,11 +267,18 @@ func catURL(ctx context.Context, sourceURL string, encKeyDB map[string][]prefixS
 					err := probe.NewError(fmt.Errorf("specified offset (%d) bigger than file (%d)", o.startO, content.Size))
 					return err.Trace(sourceURL)
 				}
+				if o.lengthO > 0 && o.lengthO < size {
+					size = o.lengthO
+				}
+				// Servers reject ranges starting at the end.
+				if size == 0 {
+					return nil
+				}
 			}
 		} else {
 			return err.Trace(sourceURL)
 		}
-		gopts := GetOptions{VersionID: versionID, Zip: o.isZip, RangeStart: o.startO}
+		gopts := GetOptions{VersionID: versionID, Zip: o.isZip, RangeStart: o.startO, RangeLength: o.lengthO}
 		if reader, err = getSourceStreamFromURL(ctx, sourceURL, encKeyDB, getSourceOpts{
 			GetOptions: gopts,
 			preserve:   false,
@@ -273,6 +290,12 @@ func catURL(ctx context.Context, sourceURL string, encKeyDB map[string][]prefixS
 	return catOut(reader, size).Trace(sourceURL)
 }
 
+// isStdoutClosed returns true if the error is caused by the reader
+// of stdout going away, e.g. `mc cat ... | head`.
+func isStdoutClosed(err *probe.Error) bool {
+	return err != nil && errors.Is(err.ToGoError(), syscall.EPIPE)
+}
+
 // catOut reads from reader stream and writes to stdout. Also check the length of the
 // read bytes against size parameter (if not -1) and return the appropriate error
 func catOut(r io.Reader, size int64) *probe.Error {
@@ -292,16 +315,7 @@ func catOut(r io.Reader, size int64) *probe.Error {
 
 	// Read till EOF.
 	if n, e = io.Copy(stdout, r); e != nil {
-		switch e := e.(type) {
-		case *os.PathError:
-			if e.Err == syscall.EPIPE {
-				// stdout closed by the user. Gracefully exit.
-				return nil
-			}
-			return probe.NewError(e)
-		default:
-			return probe.NewError(e)
-		}
+		return probe.NewError(e)
 	}
 	if size != -1 && n < size {
 		return probe.NewError(UnexpectedEOF{
@@ -332,9 +346,15 @@ func mainCat(cliCtx *cli.Context) error {
 
 	// Set command flags from context.
 
+	// Report writes to a closed stdout as EPIPE errors instead of
+	// being killed by SIGPIPE, so that we can exit gracefully.
+	signal.Ignore(syscall.SIGPIPE)
+
 	// handle std input data.
 	if o.stdinMode {
-		fatalIf(catOut(os.Stdin, -1).Trace(), "Unable to read from standard input.")
+		if err := catOut(os.Stdin, -1); !isStdoutClosed(err) {
+			fatalIf(err.Trace(), "Unable to read from standard input.")
+		}
 		return nil
 	}
 
@@ -351,7 +371,12 @@ func mainCat(cliCtx *cli.Context) error {
 
 	// Convert arguments to URLs: expand alias, fix format.
 	for _, url := range o.args {
-		fatalIf(catURL(ctx, url, encKeyDB, o).Trace(url), "Unable to read from `"+url+"`.")
+		err := catURL(ctx, url, encKeyDB, o)
+		if isStdoutClosed(err) {
+			// stdout closed by the user, skip the remaining arguments.
+			return nil
+		}
+		fatalIf(err.Trace(url), "Unable to read from `"+url+"`.")
 	}
 
 	return nil
@@ -18,6 +18,8 @@
 package cmd
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"os"
 	"path/filepath"
 
@@ -104,4 +106,34 @@ func loadRootCAs() {
 	if e != nil {
 		fatalIf(probe.NewError(e), "Unable to load certificates.")
 	}
+
+	// CA certificates of --cacert.
+	if globalCACertFile != "" {
+		pemCerts, e := os.ReadFile(globalCACertFile)
+		fatalIf(probe.NewError(e), "Unable to read the CA certificates of `%s`.", globalCACertFile)
+		if globalRootCAs == nil {
+			globalRootCAs = x509.NewCertPool()
+		}
+		if !globalRootCAs.AppendCertsFromPEM(pemCerts) {
+			fatalIf(errInvalidArgument().Trace(globalCACertFile), "No PEM certificate found in `%s`.", globalCACertFile)
+		}
+	}
+}
+
+// loadClientCertificate loads the certificate of --client-cert, with its
+// private key in the same file unless set by --client-key.
+func loadClientCertificate() {
+	if globalClientCertFile == "" {
+		if globalClientKeyFile != "" {
+			fatalIf(errInvalidArgument().Trace(globalClientKeyFile), "--client-key requires --client-cert.")
+		}
+		return
+	}
+	keyFile := globalClientKeyFile
+	if keyFile == "" {
+		keyFile = globalClientCertFile
+	}
+	cert, e := tls.LoadX509KeyPair(globalClientCertFile, keyFile)
+	fatalIf(probe.NewError(e), "Unable to load the client certificate `%s`.", globalClientCertFile)
+	globalClientCertificates = []tls.Certificate{cert}
 }
@@ -0,0 +1,160 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testCertificate - returns a certificate signed by parent, or self
+// signed when parent is nil, with its PEM encoding.
+func testCertificate(t *testing.T, template *x509.Certificate, parent *tls.Certificate) (tls.Certificate, []byte, []byte) {
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if e != nil {
+		t.Fatal(e)
+	}
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore, template.NotAfter = time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	signer, signerKey := template, interface{}(key)
+	if parent != nil {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, e := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if e != nil {
+		t.Fatal(e)
+	}
+	keyDER, e := x509.MarshalECPrivateKey(key)
+	if e != nil {
+		t.Fatal(e)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	cert, e := tls.X509KeyPair(certPEM, keyPEM)
+	if e != nil {
+		t.Fatal(e)
+	}
+	cert.Leaf, _ = x509.ParseCertificate(der)
+	return cert, certPEM, keyPEM
+}
+
+func TestClientCertificates(t *testing.T) {
+	useTestMcConfig(t)
+	rootCAs, clientCerts := globalRootCAs, globalClientCertificates
+	caFile, certFile, keyFile := globalCACertFile, globalClientCertFile, globalClientKeyFile
+	t.Cleanup(func() {
+		globalRootCAs, globalClientCertificates = rootCAs, clientCerts
+		globalCACertFile, globalClientCertFile, globalClientKeyFile = caFile, certFile, keyFile
+	})
+
+	ca, caPEM, _ := testCertificate(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+	serverCert, _, _ := testCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "server"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, &ca)
+	_, clientPEM, clientKeyPEM := testCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "client"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, &ca)
+
+	dir := t.TempDir()
+	for name, data := range map[string][]byte{
+		"ca.pem":         caPEM,
+		"client.pem":     clientPEM,
+		"client.key":     clientKeyPEM,
+		"client-key.pem": append(append([]byte{}, clientPEM...), clientKeyPEM...),
+	} {
+		if e := os.WriteFile(filepath.Join(dir, name), data, 0o600); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	get := func() error {
+		transport := getTransportForConfig(&Config{HostURL: server.URL}, false)
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, e := transport.RoundTrip(req)
+		if e == nil {
+			resp.Body.Close()
+		}
+		return e
+	}
+
+	testCases := []struct {
+		caFile, certFile, keyFile string
+		success                   bool
+	}{
+		// Unknown CA.
+		{"", "", "", false},
+		// No client certificate.
+		{"ca.pem", "", "", false},
+		{"ca.pem", "client.pem", "client.key", true},
+		// Certificate and key in the same file.
+		{"ca.pem", "client-key.pem", "", true},
+	}
+	for i, testCase := range testCases {
+		globalCACertFile, globalClientCertFile, globalClientKeyFile = "", "", ""
+		if testCase.caFile != "" {
+			globalCACertFile = filepath.Join(dir, testCase.caFile)
+		}
+		if testCase.certFile != "" {
+			globalClientCertFile = filepath.Join(dir, testCase.certFile)
+		}
+		if testCase.keyFile != "" {
+			globalClientKeyFile = filepath.Join(dir, testCase.keyFile)
+		}
+		globalClientCertificates = nil
+		loadRootCAs()
+		loadClientCertificate()
+		if e := get(); (e == nil) != testCase.success {
+			t.Errorf("Test %d: expected success %v, got %v", i+1, testCase.success, e)
+		}
+	}
+}
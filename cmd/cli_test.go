@@ -18,6 +18,8 @@
 package cmd
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/minio/cli"
@@ -47,3 +49,12 @@ func TestCLIOnUsageError(t *testing.T) {
 		checkOnUsageError(cmd, "")
 	}
 }
+
+func TestGlobalFlagsEnvVar(t *testing.T) {
+	for _, flag := range globalFlags {
+		envVar := reflect.ValueOf(flag).FieldByName("EnvVar").String()
+		if !strings.HasPrefix(envVar, envPrefix) {
+			t.Errorf("Global flag `%s` has no %s* environment variable", flag.GetName(), envPrefix)
+		}
+	}
+}
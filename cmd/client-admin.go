@@ -151,7 +151,8 @@ func newAnonymousClient(aliasedURL string) (*madmin.AnonymousClient, *probe.Erro
 
 	// Keep TLS config.
 	tlsConfig := &tls.Config{
-		RootCAs: globalRootCAs,
+		RootCAs:      globalRootCAs,
+		Certificates: globalClientCertificates,
 		// Can't use SSLv3 because of POODLE and BEAST
 		// Can't use TLSv1.0 because of POODLE and BEAST using CBC cipher
 		// Can't use TLSv1.1 because of RC4 cipher usage
@@ -0,0 +1,999 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/mc/pkg/hookreader"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/minio/minio-go/v7/pkg/replication"
+)
+
+const (
+	// API value of aliases pointing to an Azure Blob Storage account,
+	// the access key is the account name and the secret key the account key.
+	azureAPI = "azure"
+
+	// Azure URLs of the form az://ACCOUNT/CONTAINER/BLOB use the alias
+	// holding the credentials of ACCOUNT.
+	azureURLScheme = "az://"
+
+	azureAPIVersion = "2021-08-06"
+
+	// Largest blob uploaded with a single Put Blob request, larger or
+	// unknown sizes are uploaded as a list of blocks.
+	azureMaxPutBlobSize = 256 * humanize.MiByte
+	azureBlockSize      = 16 * humanize.MiByte
+
+	// First and longest wait between two polls of a pending server
+	// side copy.
+	azureCopyPoll    = time.Second
+	azureCopyMaxPoll = 30 * time.Second
+)
+
+// azureClient - Azure Blob Storage client, containers map to buckets and
+// blobs to objects.
+type azureClient struct {
+	targetURL  *ClientURL
+	account    string
+	key        []byte
+	httpClient *http.Client
+	userAgent  string
+	copyPoll   time.Duration
+}
+
+// azureNew - instantiates a new Azure Blob Storage client.
+func azureNew(config *Config) (Client, *probe.Error) {
+	key, e := base64.StdEncoding.DecodeString(config.SecretKey)
+	if e != nil {
+		return nil, probe.NewError(fmt.Errorf("invalid Azure account key: %w", e))
+	}
+	return &azureClient{
+		targetURL:  newClientURL(config.HostURL),
+		account:    config.AccessKey,
+		key:        key,
+		httpClient: &http.Client{Transport: getTransportForConfig(config, false)},
+		userAgent:  config.AppName + "/" + config.AppVersion,
+		copyPoll:   azureCopyPoll,
+	}, nil
+}
+
+// expandAzureURL - expands az://ACCOUNT/CONTAINER/BLOB using the alias
+// configured with the credentials of ACCOUNT.
+func expandAzureURL(azureURL string) (alias, urlStr string, aliasCfg *aliasConfigV10, err *probe.Error) {
+	account, path := splitAzureURL(azureURL)
+	config, err := loadMcConfig()
+	if err != nil {
+		return "", "", nil, err.Trace(azureURL)
+	}
+	aliases := make([]string, 0, len(config.Aliases))
+	for alias := range config.Aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		cfg := config.Aliases[alias]
+		if strings.EqualFold(cfg.API, azureAPI) && cfg.AccessKey == account {
+			return alias, urlJoinPath(cfg.URL, path), &cfg, nil
+		}
+	}
+	return "", "", nil, probe.NewError(fmt.Errorf("no alias with `--api %s` is configured for the Azure account `%s`", azureAPI, account))
+}
+
+// splitAzureURL - splits az://ACCOUNT/CONTAINER/BLOB into the account
+// and the CONTAINER/BLOB path.
+func splitAzureURL(azureURL string) (account, path string) {
+	tokens := splitStr(strings.TrimPrefix(azureURL, azureURLScheme), "/", 2)
+	return tokens[0], tokens[1]
+}
+
+// azureErrorResponse - error returned by the Blob service.
+type azureErrorResponse struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+
+	statusCode int
+}
+
+func (e azureErrorResponse) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s (%d %s)", e.Code, e.statusCode, http.StatusText(e.statusCode))
+	}
+	return strings.SplitN(e.Message, "\n", 2)[0]
+}
+
+// azureBlobPrefix - a virtual folder of a delimited listing.
+type azureBlobPrefix struct {
+	Name string `xml:"Name"`
+}
+
+// azureBlob - a blob entry of a listing.
+type azureBlob struct {
+	Name       string `xml:"Name"`
+	Properties struct {
+		LastModified  string `xml:"Last-Modified"`
+		ContentLength int64  `xml:"Content-Length"`
+		ContentType   string `xml:"Content-Type"`
+		Etag          string `xml:"Etag"`
+		AccessTier    string `xml:"AccessTier"`
+	} `xml:"Properties"`
+}
+
+// azureBlobList - response of List Blobs.
+type azureBlobList struct {
+	Blobs struct {
+		Blob       []azureBlob       `xml:"Blob"`
+		BlobPrefix []azureBlobPrefix `xml:"BlobPrefix"`
+	} `xml:"Blobs"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+// azureContainerList - response of List Containers.
+type azureContainerList struct {
+	Containers struct {
+		Container []struct {
+			Name       string `xml:"Name"`
+			Properties struct {
+				LastModified string `xml:"Last-Modified"`
+			} `xml:"Properties"`
+		} `xml:"Container"`
+	} `xml:"Containers"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+// azureStringToSign - returns the string to sign of a Shared Key authorized request.
+func azureStringToSign(account string, req *http.Request) string {
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+	var b strings.Builder
+	for _, v := range []string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, x-ms-date is used instead.
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+	} {
+		b.WriteString(v)
+		b.WriteByte('\n')
+	}
+
+	// Canonicalized headers.
+	var msHeaders []string
+	for k := range req.Header {
+		if k = strings.ToLower(k); strings.HasPrefix(k, "x-ms-") {
+			msHeaders = append(msHeaders, k)
+		}
+	}
+	sort.Strings(msHeaders)
+	for _, k := range msHeaders {
+		b.WriteString(k + ":" + strings.TrimSpace(req.Header.Get(k)) + "\n")
+	}
+
+	// Canonicalized resource.
+	b.WriteString("/" + account + req.URL.EscapedPath())
+	query := req.URL.Query()
+	params := make([]string, 0, len(query))
+	for k := range query {
+		params = append(params, k)
+	}
+	sort.Strings(params)
+	for _, k := range params {
+		values := query[k]
+		sort.Strings(values)
+		b.WriteString("\n" + strings.ToLower(k) + ":" + strings.Join(values, ","))
+	}
+	return b.String()
+}
+
+// request - sends a Shared Key authorized request for the container and blob.
+func (c *azureClient) request(ctx context.Context, method, container, blob string, query url.Values, header http.Header, body io.Reader, size int64) (*http.Response, *probe.Error) {
+	u := url.URL{
+		Scheme:   c.targetURL.Scheme,
+		Host:     c.targetURL.Host,
+		Path:     "/" + container,
+		RawQuery: query.Encode(),
+	}
+	if blob != "" {
+		u.Path += "/" + blob
+	}
+	if container == "" {
+		u.Path = "/"
+	}
+	req, e := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	req.Header.Set("x-ms-date", UTCNow().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", azureAPIVersion)
+	req.Header.Set("User-Agent", c.userAgent)
+
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(azureStringToSign(c.account, req)))
+	req.Header.Set("Authorization", "SharedKey "+c.account+":"+base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+
+	resp, e := c.httpClient.Do(req)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		errResp := azureErrorResponse{Code: resp.Header.Get("x-ms-error-code"), statusCode: resp.StatusCode}
+		if method != http.MethodHead {
+			data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*humanize.KiByte))
+			xml.Unmarshal(data, &errResp)
+		}
+		return nil, c.toError(container, blob, errResp)
+	}
+	return resp, nil
+}
+
+// toError - converts Blob service errors to errors understood by the commands.
+func (c *azureClient) toError(container, blob string, errResp azureErrorResponse) *probe.Error {
+	switch errResp.Code {
+	case "ContainerNotFound":
+		return probe.NewError(BucketDoesNotExist{Bucket: container})
+	case "ContainerAlreadyExists":
+		return probe.NewError(BucketExists{Bucket: container})
+	case "BlobNotFound":
+		return probe.NewError(ObjectMissing{})
+	case "AuthorizationFailure", "AuthorizationPermissionMismatch", "AuthenticationFailed":
+		return probe.NewError(PathInsufficientPermission{Path: c.targetURL.String()})
+	}
+	if errResp.statusCode == http.StatusNotFound {
+		if blob == "" {
+			return probe.NewError(BucketDoesNotExist{Bucket: container})
+		}
+		return probe.NewError(ObjectMissing{})
+	}
+	return probe.NewError(errResp)
+}
+
+// url2ContainerAndBlob - returns the container and the blob name of the client URL.
+func (c *azureClient) url2ContainerAndBlob() (container, blob string) {
+	return url2BucketAndObject(c.targetURL)
+}
+
+// contentURL - returns the URL of an entry of the container.
+func (c *azureClient) contentURL(container, name string) ClientURL {
+	u := c.targetURL.Clone()
+	u.Path = "/" + container
+	if name != "" {
+		u.Path += "/" + name
+	}
+	return u
+}
+
+// parseAzureTime - parses the RFC1123 dates returned by the Blob service.
+func parseAzureTime(s string) time.Time {
+	t, e := time.Parse(time.RFC1123, s)
+	if e != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func (c *azureClient) blob2ClientContent(container string, b azureBlob) *ClientContent {
+	return &ClientContent{
+		URL:          c.contentURL(container, b.Name),
+		BucketName:   container,
+		Time:         parseAzureTime(b.Properties.LastModified),
+		Size:         b.Properties.ContentLength,
+		ETag:         strings.Trim(b.Properties.Etag, "\""),
+		StorageClass: b.Properties.AccessTier,
+		ContentType:  b.Properties.ContentType,
+		Type:         os.FileMode(0o664),
+		Metadata:     map[string]string{"Content-Type": b.Properties.ContentType},
+	}
+}
+
+func (c *azureClient) prefix2ClientContent(container, prefix string) *ClientContent {
+	return &ClientContent{
+		URL:        c.contentURL(container, prefix),
+		BucketName: container,
+		Time:       time.Now(),
+		Type:       os.ModeDir,
+	}
+}
+
+func (c *azureClient) container2ClientContent(container string, lastModified time.Time) *ClientContent {
+	return &ClientContent{
+		URL:        c.contentURL(container, ""),
+		BucketName: container,
+		Time:       lastModified,
+		Type:       os.ModeDir,
+	}
+}
+
+// header2ClientContent - returns the blob properties from the headers of a HEAD or GET response.
+func (c *azureClient) header2ClientContent(container, blob string, h http.Header) *ClientContent {
+	content := &ClientContent{
+		URL:          c.contentURL(container, blob),
+		BucketName:   container,
+		Time:         parseAzureTime(h.Get("Last-Modified")),
+		ETag:         strings.Trim(h.Get("ETag"), "\""),
+		StorageClass: h.Get("x-ms-access-tier"),
+		Type:         os.FileMode(0o664),
+		Metadata:     map[string]string{},
+		UserMetadata: map[string]string{},
+	}
+	content.Size, _ = strconv.ParseInt(h.Get("Content-Length"), 10, 64)
+	if contentRange := h.Get("Content-Range"); contentRange != "" {
+		// Size of the whole blob for ranged requests.
+		if i := strings.LastIndex(contentRange, "/"); i >= 0 {
+			content.Size, _ = strconv.ParseInt(contentRange[i+1:], 10, 64)
+		}
+	}
+	for k, v := range h {
+		lk := strings.ToLower(k)
+		switch {
+		case strings.HasPrefix(lk, "x-ms-meta-"):
+			content.UserMetadata[strings.TrimPrefix(lk, "x-ms-meta-")] = v[0]
+		case lk == "content-type", lk == "content-encoding", lk == "content-language",
+			lk == "content-disposition", lk == "cache-control":
+			content.Metadata[k] = v[0]
+		}
+	}
+	return content
+}
+
+// listContainers - returns all containers of the account.
+func (c *azureClient) listContainers(ctx context.Context) ([]*ClientContent, *probe.Error) {
+	var contents []*ClientContent
+	marker := ""
+	for {
+		query := url.Values{"comp": {"list"}}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		resp, err := c.request(ctx, http.MethodGet, "", "", query, nil, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		var list azureContainerList
+		e := xml.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		for _, container := range list.Containers.Container {
+			contents = append(contents, c.container2ClientContent(container.Name, parseAzureTime(container.Properties.LastModified)))
+		}
+		if marker = list.NextMarker; marker == "" {
+			return contents, nil
+		}
+	}
+}
+
+// listBlobs - sends the blobs (and virtual folders unless recursive) found
+// under prefix in lexical order, maxResults is ignored when not positive.
+func (c *azureClient) listBlobs(ctx context.Context, container, prefix string, recursive bool, maxResults int, contentCh chan<- *ClientContent) bool {
+	marker := ""
+	for {
+		query := url.Values{"restype": {"container"}, "comp": {"list"}}
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if !recursive {
+			query.Set("delimiter", "/")
+		}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		if maxResults > 0 {
+			query.Set("maxresults", strconv.Itoa(maxResults))
+		}
+		resp, err := c.request(ctx, http.MethodGet, container, "", query, nil, nil, 0)
+		if err != nil {
+			return sendAzureContent(ctx, contentCh, &ClientContent{Err: err.Trace(container, prefix)})
+		}
+		var list azureBlobList
+		e := xml.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if e != nil {
+			return sendAzureContent(ctx, contentCh, &ClientContent{Err: probe.NewError(e)})
+		}
+
+		contents := make([]*ClientContent, 0, len(list.Blobs.Blob)+len(list.Blobs.BlobPrefix))
+		for _, b := range list.Blobs.Blob {
+			contents = append(contents, c.blob2ClientContent(container, b))
+		}
+		for _, p := range list.Blobs.BlobPrefix {
+			contents = append(contents, c.prefix2ClientContent(container, p.Name))
+		}
+		sort.Slice(contents, func(i, j int) bool {
+			return contents[i].URL.Path < contents[j].URL.Path
+		})
+		for _, content := range contents {
+			if !sendAzureContent(ctx, contentCh, content) {
+				return false
+			}
+		}
+		if marker = list.NextMarker; marker == "" || maxResults > 0 {
+			return true
+		}
+	}
+}
+
+// sendAzureContent - sends content unless ctx is canceled, returns false
+// when the listing must stop.
+func sendAzureContent(ctx context.Context, contentCh chan<- *ClientContent, content *ClientContent) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case contentCh <- content:
+		return content.Err == nil
+	}
+}
+
+// Stat - returns the properties of a blob, virtual folder or container.
+func (c *azureClient) Stat(ctx context.Context, _ StatOptions) (*ClientContent, *probe.Error) {
+	container, blob := c.url2ContainerAndBlob()
+	if container == "" {
+		u := c.targetURL.Clone()
+		u.Path = string(c.targetURL.Separator)
+		return &ClientContent{URL: u, Type: os.ModeDir}, nil
+	}
+	if blob == "" {
+		resp, err := c.request(ctx, http.MethodHead, container, "", url.Values{"restype": {"container"}}, nil, nil, 0)
+		if err != nil {
+			return nil, err.Trace(container)
+		}
+		resp.Body.Close()
+		return c.container2ClientContent(container, parseAzureTime(resp.Header.Get("Last-Modified"))), nil
+	}
+
+	if !strings.HasSuffix(blob, "/") {
+		resp, err := c.request(ctx, http.MethodHead, container, blob, nil, nil, nil, 0)
+		if err == nil {
+			resp.Body.Close()
+			return c.header2ClientContent(container, blob, resp.Header), nil
+		}
+		if !errors.As(err.ToGoError(), &ObjectMissing{}) {
+			return nil, err.Trace(container, blob)
+		}
+		blob += "/"
+	}
+
+	// Look for a virtual folder.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		c.listBlobs(ctx, container, blob, false, 1, contentCh)
+	}()
+	for content := range contentCh {
+		if content.Err != nil {
+			return nil, content.Err
+		}
+		return c.prefix2ClientContent(container, blob), nil
+	}
+	return nil, probe.NewError(ObjectMissing{})
+}
+
+// List - lists containers, blobs and virtual folders.
+func (c *azureClient) List(ctx context.Context, opts ListOptions) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		container, blob := c.url2ContainerAndBlob()
+		switch {
+		case container == "":
+			containers, err := c.listContainers(ctx)
+			if err != nil {
+				sendAzureContent(ctx, contentCh, &ClientContent{Err: err})
+				return
+			}
+			for _, content := range containers {
+				if !opts.Recursive {
+					if !sendAzureContent(ctx, contentCh, content) {
+						return
+					}
+					continue
+				}
+				if opts.ShowDir == DirFirst && !sendAzureContent(ctx, contentCh, content) {
+					return
+				}
+				if !c.listBlobs(ctx, content.BucketName, "", true, 0, contentCh) {
+					return
+				}
+				if opts.ShowDir == DirLast && !sendAzureContent(ctx, contentCh, content) {
+					return
+				}
+			}
+		case blob == "" && !opts.Recursive && !strings.HasSuffix(c.targetURL.Path, string(c.targetURL.Separator)):
+			content, err := c.Stat(ctx, StatOptions{})
+			if err != nil {
+				sendAzureContent(ctx, contentCh, &ClientContent{Err: err.Trace(container)})
+				return
+			}
+			sendAzureContent(ctx, contentCh, content)
+		default:
+			c.listBlobs(ctx, container, blob, opts.Recursive, 0, contentCh)
+		}
+	}()
+	return contentCh
+}
+
+// MakeBucket - creates a container.
+func (c *azureClient) MakeBucket(ctx context.Context, _ string, ignoreExisting, withLock bool) *probe.Error {
+	container, blob := c.url2ContainerAndBlob()
+	if container == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	if withLock {
+		return c.notImplemented("MakeBucket with object lock")
+	}
+	if blob != "" {
+		// Folders are virtual, nothing to create.
+		return nil
+	}
+	resp, err := c.request(ctx, http.MethodPut, container, "", url.Values{"restype": {"container"}}, nil, nil, 0)
+	if err != nil {
+		if ignoreExisting && errors.As(err.ToGoError(), &BucketExists{}) {
+			return nil
+		}
+		return err.Trace(container)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// RemoveBucket - removes a container.
+func (c *azureClient) RemoveBucket(ctx context.Context, _ bool) *probe.Error {
+	container, blob := c.url2ContainerAndBlob()
+	if container == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	if blob != "" {
+		return probe.NewError(BucketInvalid{Bucket: container + "/" + blob})
+	}
+	resp, err := c.request(ctx, http.MethodDelete, container, "", url.Values{"restype": {"container"}}, nil, nil, 0)
+	if err != nil {
+		return err.Trace(container)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// ListBuckets - lists the containers of the account.
+func (c *azureClient) ListBuckets(ctx context.Context) ([]*ClientContent, *probe.Error) {
+	return c.listContainers(ctx)
+}
+
+// Get - returns the blob data of the range of opts.
+func (c *azureClient) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *ClientContent, *probe.Error) {
+	container, blob := c.url2ContainerAndBlob()
+	if opts.SSE != nil || opts.Zip || opts.VersionID != "" {
+		return nil, nil, c.notImplemented("Get with encryption, zip or version")
+	}
+	header := http.Header{}
+	if opts.isRange() {
+		header.Set("x-ms-range", opts.rangeHeader())
+	}
+	resp, err := c.request(ctx, http.MethodGet, container, blob, nil, header, nil, 0)
+	if err != nil {
+		return nil, nil, err.Trace(container, blob)
+	}
+	return resp.Body, c.header2ClientContent(container, blob, resp.Header), nil
+}
+
+// azureBlobHeaders - maps object metadata to Blob service headers,
+// metadata names may only hold letters, digits and underscores.
+func azureBlobHeaders(metadata map[string]string, storageClass string, blobPrefix string) http.Header {
+	header := http.Header{}
+	for k, v := range metadata {
+		switch ck := http.CanonicalHeaderKey(k); ck {
+		case "Content-Type", "Content-Encoding", "Content-Language", "Content-Disposition", "Cache-Control":
+			header.Set(blobPrefix+ck, v)
+		default:
+			if strings.HasPrefix(ck, "X-Amz-") && !strings.HasPrefix(ck, "X-Amz-Meta-") {
+				// S3 specific headers such as object lock settings.
+				continue
+			}
+			name := strings.ReplaceAll(strings.TrimPrefix(ck, "X-Amz-Meta-"), "-", "_")
+			header.Set("x-ms-meta-"+name, v)
+		}
+	}
+	if storageClass != "" {
+		header.Set("x-ms-access-tier", storageClass)
+	}
+	return header
+}
+
+// Put - uploads a block blob, blobs larger than azureMaxPutBlobSize or of
+// unknown size are uploaded in blocks.
+func (c *azureClient) Put(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
+	container, blob := c.url2ContainerAndBlob()
+	if container == "" {
+		return 0, probe.NewError(BucketNameEmpty{})
+	}
+	if blob == "" {
+		return 0, probe.NewError(ObjectNameEmpty{})
+	}
+	if opts.sse != nil {
+		return 0, c.notImplemented("Put with encryption")
+	}
+	reader = hookreader.NewHook(reader, progress)
+
+	if size >= 0 && size <= azureMaxPutBlobSize {
+		header := azureBlobHeaders(opts.metadata, opts.storageClass, "")
+		header.Set("x-ms-blob-type", "BlockBlob")
+		var body io.Reader = http.NoBody
+		if size > 0 {
+			body = io.LimitReader(reader, size)
+		}
+		resp, err := c.request(ctx, http.MethodPut, container, blob, nil, header, body, size)
+		if err != nil {
+			return 0, err.Trace(container, blob)
+		}
+		resp.Body.Close()
+		return size, nil
+	}
+
+	blockSize := int64(azureBlockSize)
+	if opts.multipartSize > 0 {
+		blockSize = int64(opts.multipartSize)
+	}
+	buf := make([]byte, blockSize)
+	var (
+		blockList bytes.Buffer
+		total     int64
+	)
+	blockList.WriteString(`<?xml version="1.0" encoding="utf-8"?><BlockList>`)
+	for i := 0; ; i++ {
+		n, e := io.ReadFull(reader, buf)
+		if e != nil && e != io.EOF && e != io.ErrUnexpectedEOF {
+			return total, probe.NewError(e)
+		}
+		if n == 0 && i > 0 {
+			break
+		}
+		blockID := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%08d", i)))
+		query := url.Values{"comp": {"block"}, "blockid": {blockID}}
+		resp, err := c.request(ctx, http.MethodPut, container, blob, query, nil, bytes.NewReader(buf[:n]), int64(n))
+		if err != nil {
+			return total, err.Trace(container, blob)
+		}
+		resp.Body.Close()
+		total += int64(n)
+		blockList.WriteString("<Latest>" + blockID + "</Latest>")
+		if n < len(buf) {
+			break
+		}
+	}
+	blockList.WriteString("</BlockList>")
+
+	header := azureBlobHeaders(opts.metadata, opts.storageClass, "x-ms-blob-")
+	resp, err := c.request(ctx, http.MethodPut, container, blob, url.Values{"comp": {"blocklist"}}, header,
+		bytes.NewReader(blockList.Bytes()), int64(blockList.Len()))
+	if err != nil {
+		return total, err.Trace(container, blob)
+	}
+	resp.Body.Close()
+	return total, nil
+}
+
+// Copy - copies a blob of the same account server side, source is the
+// /CONTAINER/BLOB path of the source blob.
+func (c *azureClient) Copy(ctx context.Context, source string, opts CopyOptions, progress io.Reader) *probe.Error {
+	container, blob := c.url2ContainerAndBlob()
+	if opts.srcSSE != nil || opts.tgtSSE != nil || opts.versionID != "" {
+		return c.notImplemented("Copy with encryption or version")
+	}
+	sourceURL := url.URL{
+		Scheme: c.targetURL.Scheme,
+		Host:   c.targetURL.Host,
+		Path:   "/" + strings.TrimPrefix(source, "/"),
+	}
+	header := http.Header{}
+	if opts.isPreserve || len(opts.metadata) > 0 {
+		header = azureBlobHeaders(opts.metadata, "", "")
+		// Content headers are only taken from the source blob.
+		for _, k := range []string{"Content-Type", "Content-Encoding", "Content-Language", "Content-Disposition", "Cache-Control"} {
+			header.Del(k)
+		}
+	}
+	if opts.storageClass != "" {
+		header.Set("x-ms-access-tier", opts.storageClass)
+	}
+	header.Set("x-ms-copy-source", sourceURL.String())
+	resp, err := c.request(ctx, http.MethodPut, container, blob, nil, header, http.NoBody, 0)
+	if err != nil {
+		return err.Trace(source, container, blob)
+	}
+	resp.Body.Close()
+	if err = c.waitForCopy(ctx, container, blob, resp.Header); err != nil {
+		return err.Trace(source, container, blob)
+	}
+	if progress != nil && opts.size > 0 {
+		if _, e := io.CopyN(io.Discard, progress, opts.size); e != nil {
+			return probe.NewError(e)
+		}
+	}
+	return nil
+}
+
+// waitForCopy - Copy Blob is asynchronous, polls the properties of the
+// target blob with a backoff until the copy is no longer pending. Returns
+// an error when the copy failed or was aborted.
+func (c *azureClient) waitForCopy(ctx context.Context, container, blob string, header http.Header) *probe.Error {
+	poll := c.copyPoll
+	for {
+		switch status := header.Get("x-ms-copy-status"); status {
+		case "", "success":
+			return nil
+		case "pending":
+		default:
+			return probe.NewError(fmt.Errorf("copy to `%s/%s` is %s: %s", container, blob, status, header.Get("x-ms-copy-status-description")))
+		}
+
+		select {
+		case <-time.After(poll):
+		case <-ctx.Done():
+			return probe.NewError(ctx.Err())
+		}
+		if poll *= 2; poll > azureCopyMaxPoll {
+			poll = azureCopyMaxPoll
+		}
+
+		resp, err := c.request(ctx, http.MethodHead, container, blob, nil, nil, nil, 0)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		header = resp.Header
+	}
+}
+
+// Remove - removes blobs, and containers when isRemoveBucket is set.
+func (c *azureClient) Remove(ctx context.Context, isIncomplete, isRemoveBucket, _, _ bool, contentCh <-chan *ClientContent) <-chan RemoveResult {
+	resultCh := make(chan RemoveResult)
+	go func() {
+		defer close(resultCh)
+		if isIncomplete {
+			for range contentCh {
+				// Uncommitted blocks are garbage collected by the service.
+			}
+			return
+		}
+		for content := range contentCh {
+			container, blob := url2BucketAndObject(&content.URL)
+			var err *probe.Error
+			switch {
+			case blob == "" && isRemoveBucket:
+				var resp *http.Response
+				if resp, err = c.request(ctx, http.MethodDelete, container, "", url.Values{"restype": {"container"}}, nil, nil, 0); err == nil {
+					resp.Body.Close()
+				}
+			case blob == "", strings.HasSuffix(blob, "/"):
+				// Folders are virtual, nothing to remove.
+				continue
+			default:
+				var resp *http.Response
+				if resp, err = c.request(ctx, http.MethodDelete, container, blob, nil, nil, nil, 0); err == nil {
+					resp.Body.Close()
+				}
+			}
+			result := RemoveResult{BucketName: container, Err: err}
+			result.ObjectName = blob
+			select {
+			case <-ctx.Done():
+				return
+			case resultCh <- result:
+			}
+		}
+	}()
+	return resultCh
+}
+
+// GetURL - returns the client URL.
+func (c *azureClient) GetURL() ClientURL {
+	return c.targetURL.Clone()
+}
+
+// AddUserAgent - sets the application name and version sent with every request.
+func (c *azureClient) AddUserAgent(app, version string) {
+	c.userAgent = app + "/" + version
+}
+
+// GetBucketInfo - returns the container information.
+func (c *azureClient) GetBucketInfo(ctx context.Context) (BucketInfo, *probe.Error) {
+	content, err := c.Stat(ctx, StatOptions{})
+	if err != nil {
+		return BucketInfo{}, err
+	}
+	return BucketInfo{
+		URL:  content.URL,
+		Key:  content.BucketName,
+		Date: content.Time,
+		Type: content.Type,
+	}, nil
+}
+
+func (c *azureClient) notImplemented(api string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     api,
+		APIType: "Azure Blob Storage",
+	})
+}
+
+// The following operations have no Azure Blob Storage equivalent.
+
+func (c *azureClient) SetObjectLockConfig(context.Context, minio.RetentionMode, uint64, minio.ValidityUnit) *probe.Error {
+	return c.notImplemented("SetObjectLockConfig")
+}
+
+func (c *azureClient) GetObjectLockConfig(context.Context) (string, minio.RetentionMode, uint64, minio.ValidityUnit, *probe.Error) {
+	return "", "", 0, "", c.notImplemented("GetObjectLockConfig")
+}
+
+func (c *azureClient) GetAccess(context.Context) (string, string, *probe.Error) {
+	return "", "", c.notImplemented("GetAccess")
+}
+
+func (c *azureClient) GetAccessRules(context.Context) (map[string]string, *probe.Error) {
+	return nil, c.notImplemented("GetAccessRules")
+}
+
+func (c *azureClient) SetAccess(context.Context, string, bool) *probe.Error {
+	return c.notImplemented("SetAccess")
+}
+
+func (c *azureClient) Select(context.Context, string, encrypt.ServerSide, SelectObjectOpts) (io.ReadCloser, *probe.Error) {
+	return nil, c.notImplemented("Select")
+}
+
+func (c *azureClient) PutObjectRetention(context.Context, string, minio.RetentionMode, time.Time, bool) *probe.Error {
+	return c.notImplemented("PutObjectRetention")
+}
+
+func (c *azureClient) GetObjectRetention(context.Context, string) (minio.RetentionMode, time.Time, *probe.Error) {
+	return "", time.Time{}, c.notImplemented("GetObjectRetention")
+}
+
+func (c *azureClient) PutObjectLegalHold(context.Context, string, minio.LegalHoldStatus) *probe.Error {
+	return c.notImplemented("PutObjectLegalHold")
+}
+
+func (c *azureClient) GetObjectLegalHold(context.Context, string) (minio.LegalHoldStatus, *probe.Error) {
+	return "", c.notImplemented("GetObjectLegalHold")
+}
+
+func (c *azureClient) ShareDownload(context.Context, string, time.Duration) (string, *probe.Error) {
+	return "", c.notImplemented("ShareDownload")
+}
+
+func (c *azureClient) ShareUpload(context.Context, bool, time.Duration, string) (string, map[string]string, *probe.Error) {
+	return "", nil, c.notImplemented("ShareUpload")
+}
+
+func (c *azureClient) Watch(context.Context, WatchOptions) (*WatchObject, *probe.Error) {
+	return nil, c.notImplemented("Watch")
+}
+
+func (c *azureClient) GetTags(context.Context, string) (map[string]string, *probe.Error) {
+	return nil, c.notImplemented("GetTags")
+}
+
+func (c *azureClient) SetTags(context.Context, string, string) *probe.Error {
+	return c.notImplemented("SetTags")
+}
+
+func (c *azureClient) DeleteTags(context.Context, string) *probe.Error {
+	return c.notImplemented("DeleteTags")
+}
+
+func (c *azureClient) GetLifecycle(context.Context) (*lifecycle.Configuration, time.Time, *probe.Error) {
+	return nil, time.Time{}, c.notImplemented("GetLifecycle")
+}
+
+func (c *azureClient) SetLifecycle(context.Context, *lifecycle.Configuration) *probe.Error {
+	return c.notImplemented("SetLifecycle")
+}
+
+func (c *azureClient) GetVersion(context.Context) (minio.BucketVersioningConfiguration, *probe.Error) {
+	return minio.BucketVersioningConfiguration{}, c.notImplemented("GetVersion")
+}
+
+func (c *azureClient) SetVersion(context.Context, string, []string, bool) *probe.Error {
+	return c.notImplemented("SetVersion")
+}
+
+func (c *azureClient) GetReplication(context.Context) (replication.Config, *probe.Error) {
+	return replication.Config{}, c.notImplemented("GetReplication")
+}
+
+func (c *azureClient) SetReplication(context.Context, *replication.Config, replication.Options) *probe.Error {
+	return c.notImplemented("SetReplication")
+}
+
+func (c *azureClient) RemoveReplication(context.Context) *probe.Error {
+	return c.notImplemented("RemoveReplication")
+}
+
+func (c *azureClient) GetReplicationMetrics(context.Context) (replication.MetricsV2, *probe.Error) {
+	return replication.MetricsV2{}, c.notImplemented("GetReplicationMetrics")
+}
+
+func (c *azureClient) ResetReplication(context.Context, time.Duration, string) (replication.ResyncTargetsInfo, *probe.Error) {
+	return replication.ResyncTargetsInfo{}, c.notImplemented("ResetReplication")
+}
+
+func (c *azureClient) ReplicationResyncStatus(context.Context, string) (replication.ResyncTargetsInfo, *probe.Error) {
+	return replication.ResyncTargetsInfo{}, c.notImplemented("ReplicationResyncStatus")
+}
+
+func (c *azureClient) GetEncryption(context.Context) (string, string, *probe.Error) {
+	return "", "", c.notImplemented("GetEncryption")
+}
+
+func (c *azureClient) SetEncryption(context.Context, string, string) *probe.Error {
+	return c.notImplemented("SetEncryption")
+}
+
+func (c *azureClient) DeleteEncryption(context.Context) *probe.Error {
+	return c.notImplemented("DeleteEncryption")
+}
+
+func (c *azureClient) Restore(context.Context, string, int) *probe.Error {
+	return c.notImplemented("Restore")
+}
+
+func (c *azureClient) GetPart(context.Context, int) (io.ReadCloser, *probe.Error) {
+	return nil, c.notImplemented("GetPart")
+}
+
+func (c *azureClient) PutPart(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
+	return c.Put(ctx, reader, size, progress, opts)
+}
@@ -0,0 +1,285 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	checkv1 "gopkg.in/check.v1"
+)
+
+// azureHandler is an in memory Blob service of a single container
+// verifying the Shared Key signature of every request.
+type azureHandler struct {
+	account string
+	key     []byte
+
+	mu     sync.Mutex
+	blobs  map[string][]byte
+	blocks map[string][]byte
+}
+
+func (h *azureHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mac := hmac.New(sha256.New, h.key)
+	mac.Write([]byte(azureStringToSign(h.account, r)))
+	expected := "SharedKey " + h.account + ":" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	if r.Header.Get("Authorization") != expected {
+		w.Header().Set("x-ms-error-code", "AuthenticationFailed")
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	query := r.URL.Query()
+	if !strings.HasPrefix(r.URL.Path, "/container") {
+		w.Header().Set("x-ms-error-code", "ContainerNotFound")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	blob := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/container"), "/")
+
+	switch {
+	case r.Method == http.MethodGet && query.Get("comp") == "list":
+		prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+		var names []string
+		for name := range h.blobs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var b strings.Builder
+		b.WriteString("<EnumerationResults><Blobs>")
+		seen := map[string]bool{}
+		for _, name := range names {
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			if i := strings.Index(name[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+				p := name[:len(prefix)+i+1]
+				if !seen[p] {
+					seen[p] = true
+					fmt.Fprintf(&b, "<BlobPrefix><Name>%s</Name></BlobPrefix>", p)
+				}
+				continue
+			}
+			fmt.Fprintf(&b, "<Blob><Name>%s</Name><Properties><Last-Modified>Mon, 02 Jan 2006 15:04:05 GMT</Last-Modified>"+
+				"<Content-Length>%d</Content-Length><AccessTier>Hot</AccessTier></Properties></Blob>", name, len(h.blobs[name]))
+		}
+		b.WriteString("</Blobs><NextMarker/></EnumerationResults>")
+		io.WriteString(w, b.String())
+	case r.Method == http.MethodPut && query.Get("comp") == "block":
+		data, _ := io.ReadAll(r.Body)
+		h.blocks[query.Get("blockid")] = data
+	case r.Method == http.MethodPut && query.Get("comp") == "blocklist":
+		var list struct {
+			Latest []string `xml:"Latest"`
+		}
+		if e := xml.NewDecoder(r.Body).Decode(&list); e != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var data []byte
+		for _, id := range list.Latest {
+			data = append(data, h.blocks[id]...)
+		}
+		h.blobs[blob] = data
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPut:
+		if r.Header.Get("x-ms-blob-type") != "BlockBlob" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		h.blobs[blob] = data
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodGet, r.Method == http.MethodHead:
+		data, ok := h.blobs[blob]
+		if !ok {
+			w.Header().Set("x-ms-error-code", "BlobNotFound")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	case r.Method == http.MethodDelete:
+		delete(h.blobs, blob)
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func newTestAzureClient(c *checkv1.C, serverURL, path string) Client {
+	config := new(Config)
+	config.HostURL = serverURL + path
+	config.AccessKey = "account"
+	config.SecretKey = base64.StdEncoding.EncodeToString([]byte("account-key"))
+	clnt, err := azureNew(config)
+	c.Assert(err, checkv1.IsNil)
+	return clnt
+}
+
+func (s *TestSuite) TestAzureClient(c *checkv1.C) {
+	handler := &azureHandler{
+		account: "account",
+		key:     []byte("account-key"),
+		blobs:   map[string][]byte{},
+		blocks:  map[string][]byte{},
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	ctx := context.Background()
+
+	// Single request and block uploads.
+	data := []byte("hello azure")
+	clnt := newTestAzureClient(c, server.URL, "/container/dir/small")
+	n, err := clnt.Put(ctx, bytes.NewReader(data), int64(len(data)), nil, PutOptions{metadata: map[string]string{"Content-Type": "text/plain"}})
+	c.Assert(err, checkv1.IsNil)
+	c.Assert(n, checkv1.Equals, int64(len(data)))
+
+	large := bytes.Repeat([]byte("0123456789"), 10)
+	clnt = newTestAzureClient(c, server.URL, "/container/dir/large")
+	n, err = clnt.Put(ctx, bytes.NewReader(large), -1, nil, PutOptions{multipartSize: 32})
+	c.Assert(err, checkv1.IsNil)
+	c.Assert(n, checkv1.Equals, int64(len(large)))
+	c.Assert(handler.blobs["dir/large"], checkv1.DeepEquals, large)
+
+	reader, content, err := clnt.Get(ctx, GetOptions{})
+	c.Assert(err, checkv1.IsNil)
+	got, e := io.ReadAll(reader)
+	reader.Close()
+	c.Assert(e, checkv1.IsNil)
+	c.Assert(got, checkv1.DeepEquals, large)
+	c.Assert(content.Size, checkv1.Equals, int64(len(large)))
+
+	// Blobs, virtual folders and missing blobs.
+	content, err = newTestAzureClient(c, server.URL, "/container/dir/small").Stat(ctx, StatOptions{})
+	c.Assert(err, checkv1.IsNil)
+	c.Assert(content.Type.IsRegular(), checkv1.Equals, true)
+	content, err = newTestAzureClient(c, server.URL, "/container/dir").Stat(ctx, StatOptions{})
+	c.Assert(err, checkv1.IsNil)
+	c.Assert(content.Type.IsDir(), checkv1.Equals, true)
+	_, err = newTestAzureClient(c, server.URL, "/container/missing").Stat(ctx, StatOptions{})
+	c.Assert(err, checkv1.NotNil)
+	_, ok := err.ToGoError().(ObjectMissing)
+	c.Assert(ok, checkv1.Equals, true)
+
+	// Non recursive and recursive listings.
+	var names []string
+	for content := range newTestAzureClient(c, server.URL, "/container/").List(ctx, ListOptions{}) {
+		c.Assert(content.Err, checkv1.IsNil)
+		names = append(names, content.URL.Path)
+	}
+	c.Assert(names, checkv1.DeepEquals, []string{"/container/dir/"})
+	names = nil
+	for content := range newTestAzureClient(c, server.URL, "/container/").List(ctx, ListOptions{Recursive: true}) {
+		c.Assert(content.Err, checkv1.IsNil)
+		c.Assert(content.StorageClass, checkv1.Equals, "Hot")
+		names = append(names, content.URL.Path)
+	}
+	c.Assert(names, checkv1.DeepEquals, []string{"/container/dir/large", "/container/dir/small"})
+
+	// Wrong credentials.
+	config := new(Config)
+	config.HostURL = server.URL + "/container/dir/small"
+	config.AccessKey = "account"
+	config.SecretKey = base64.StdEncoding.EncodeToString([]byte("wrong-key"))
+	clnt, err = azureNew(config)
+	c.Assert(err, checkv1.IsNil)
+	_, err = clnt.Stat(ctx, StatOptions{})
+	c.Assert(err, checkv1.NotNil)
+}
+
+func (s *TestSuite) TestSplitAzureURL(c *checkv1.C) {
+	account, path := splitAzureURL("az://myaccount/container/dir/blob")
+	c.Assert(account, checkv1.Equals, "myaccount")
+	c.Assert(path, checkv1.Equals, "container/dir/blob")
+	account, path = splitAzureURL("az://myaccount")
+	c.Assert(account, checkv1.Equals, "myaccount")
+	c.Assert(path, checkv1.Equals, "")
+}
+
+func (s *TestSuite) TestAzureCopyStatus(c *checkv1.C) {
+	testCases := []struct {
+		statuses []string
+		success  bool
+	}{
+		{[]string{"success"}, true},
+		{[]string{"pending", "pending", "success"}, true},
+		{[]string{"pending", "failed"}, false},
+		{[]string{"aborted"}, false},
+	}
+	for i, testCase := range testCases {
+		var (
+			mu    sync.Mutex
+			polls int
+		)
+		statuses := testCase.statuses
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch r.Method {
+			case http.MethodPut:
+				if r.Header.Get("x-ms-copy-source") == "" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+			case http.MethodHead:
+				polls++
+			}
+			status := statuses[0]
+			if len(statuses) > 1 {
+				statuses = statuses[1:]
+			}
+			w.Header().Set("x-ms-copy-status", status)
+			if status == "failed" {
+				w.Header().Set("x-ms-copy-status-description", "500 InternalError")
+			}
+			w.WriteHeader(http.StatusAccepted)
+		}))
+
+		clnt := newTestAzureClient(c, server.URL, "/container/target").(*azureClient)
+		clnt.copyPoll = time.Millisecond
+		err := clnt.Copy(context.Background(), "/container/source", CopyOptions{}, nil)
+		server.Close()
+		if testCase.success {
+			c.Assert(err, checkv1.IsNil, checkv1.Commentf("Test %d", i+1))
+		} else {
+			c.Assert(err, checkv1.NotNil, checkv1.Commentf("Test %d", i+1))
+			c.Assert(strings.Contains(err.ToGoError().Error(), testCase.statuses[len(testCase.statuses)-1]), checkv1.Equals, true)
+		}
+		c.Assert(polls, checkv1.Equals, len(testCase.statuses)-1, checkv1.Commentf("Test %d", i+1))
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Regions of the buckets learnt from the redirects of the servers, by
+// server host and bucket, shared by all the transports to a host.
+var bucketRegions sync.Map
+
+func bucketRegionKey(host, bucket string) string {
+	return host + "/" + bucket
+}
+
+// Error codes of requests sent to the wrong region of a bucket.
+var bucketRegionErrorCodes = []string{
+	"AuthorizationHeaderMalformed",
+	"IllegalLocationConstraintException",
+	"InvalidRegion",
+	"PermanentRedirect",
+}
+
+// bucketRegionTransport - sends the requests of a bucket signed for the
+// region the server redirected to, following the redirects transparently
+// so that aliases don't need to know the region of every bucket.
+type bucketRegionTransport struct {
+	transport http.RoundTripper
+	host      string // host of the alias
+	accessKey string
+	secretKey string
+}
+
+func newBucketRegionTransport(config *Config, transport http.RoundTripper) http.RoundTripper {
+	host := config.HostURL
+	if u, e := url.Parse(config.HostURL); e == nil && u.Host != "" {
+		host = u.Host
+	}
+	return &bucketRegionTransport{
+		transport: transport,
+		host:      host,
+		accessKey: config.AccessKey,
+		secretKey: config.SecretKey,
+	}
+}
+
+// bucket - returns the bucket of the request, from the host of virtual
+// host style requests or else from the path.
+func (t *bucketRegionTransport) bucket(req *http.Request) string {
+	if host := req.URL.Host; host != t.host && strings.HasSuffix(host, "."+t.host) {
+		return strings.TrimSuffix(host, "."+t.host)
+	}
+	return strings.SplitN(strings.TrimPrefix(req.URL.Path, "/"), "/", 2)[0]
+}
+
+// RoundTrip - implements http.RoundTripper.
+func (t *bucketRegionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	bucket := t.bucket(req)
+	signedRegion := signV4Region(req)
+	if bucket == "" || signedRegion == "" {
+		return t.transport.RoundTrip(req)
+	}
+	if region, ok := bucketRegions.Load(bucketRegionKey(t.host, bucket)); ok && region.(string) != signedRegion {
+		if resigned := t.resign(req, region.(string)); resigned != nil {
+			req, signedRegion = resigned, region.(string)
+		}
+	}
+
+	resp, e := t.transport.RoundTrip(req)
+	if e != nil {
+		return resp, e
+	}
+	region := redirectedRegion(resp)
+	if region == "" || region == signedRegion {
+		return resp, nil
+	}
+	bucketRegions.Store(bucketRegionKey(t.host, bucket), region)
+
+	resigned := t.resign(req, region)
+	if resigned == nil {
+		return resp, nil
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return t.transport.RoundTrip(resigned)
+}
+
+// resign - returns a copy of the request signed for region and sent to
+// the regional endpoint of AWS S3, nil when it can't be sent again.
+func (t *bucketRegionTransport) resign(req *http.Request, region string) *http.Request {
+	payload := req.Header.Get("X-Amz-Content-Sha256")
+	if payload == "" || (strings.HasPrefix(payload, "STREAMING-") && payload != "STREAMING-UNSIGNED-PAYLOAD-TRAILER") {
+		return nil
+	}
+	resigned := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil
+		}
+		body, e := req.GetBody()
+		if e != nil {
+			return nil
+		}
+		resigned.Body = body
+	}
+	if host := awsRegionalHost(resigned.URL.Host, region); host != resigned.URL.Host {
+		resigned.URL.Host, resigned.Host = host, host
+		resigned.Header.Del("Host")
+	}
+	if !signV4InRegion(resigned, t.accessKey, t.secretKey, time.Now(), region) {
+		return nil
+	}
+	return resigned
+}
+
+// awsRegionalHost - returns the host of the AWS S3 endpoint of region
+// serving the same requests as host, host itself for other servers.
+func awsRegionalHost(host, region string) string {
+	i := strings.Index(host, "s3.")
+	if i < 0 || !strings.HasSuffix(host, ".amazonaws.com") || (i > 0 && host[i-1] != '.') {
+		return host
+	}
+	if strings.Contains(host[i:], "-accelerate.") || strings.Contains(host[i:], ".dualstack.") {
+		return host
+	}
+	return host[:i] + "s3." + region + ".amazonaws.com"
+}
+
+// signV4Region - returns the region of the V4 signature of the request,
+// empty for other requests.
+func signV4Region(req *http.Request) string {
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, signV4Algorithm+" ") {
+		return ""
+	}
+	for _, field := range strings.Split(strings.TrimPrefix(auth, signV4Algorithm+" "), ",") {
+		field = strings.TrimSpace(field)
+		if strings.HasPrefix(field, "Credential=") {
+			if scope := strings.Split(strings.TrimPrefix(field, "Credential="), "/"); len(scope) == 5 {
+				return scope[2]
+			}
+		}
+	}
+	return ""
+}
+
+// redirectedRegion - returns the region of the bucket when the response
+// rejects a request sent to another region, empty otherwise.
+func redirectedRegion(resp *http.Response) string {
+	switch resp.StatusCode {
+	case http.StatusMovedPermanently, http.StatusTemporaryRedirect, http.StatusBadRequest, http.StatusForbidden:
+	default:
+		return ""
+	}
+	var errResp struct {
+		Code   string `xml:"Code"`
+		Region string `xml:"Region"`
+	}
+	if resp.Body != nil {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+		xml.Unmarshal(body, &errResp)
+	}
+	found := resp.StatusCode == http.StatusMovedPermanently
+	for _, code := range bucketRegionErrorCodes {
+		if errResp.Code == code {
+			found = true
+		}
+	}
+	if !found {
+		return ""
+	}
+	if region := resp.Header.Get("x-amz-bucket-region"); region != "" {
+		return region
+	}
+	return errResp.Region
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
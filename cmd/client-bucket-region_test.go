@@ -0,0 +1,90 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/minio/minio-go/v7/pkg/signer"
+)
+
+func TestBucketRegionTransport(t *testing.T) {
+	var requests int
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if region := signV4Region(r); region != "eu-west-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "<Error><Code>AuthorizationHeaderMalformed</Code><Message>the region '%s' is wrong; expecting 'eu-west-1'</Message><Region>eu-west-1</Region></Error>", region)
+			return
+		}
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	clnt := &http.Client{Transport: newBucketRegionTransport(&Config{
+		HostURL:   server.URL,
+		AccessKey: "minio",
+		SecretKey: "minio123",
+	}, http.DefaultTransport)}
+	send := func(method, data string) int {
+		req, e := http.NewRequest(method, server.URL+"/region-bucket/object", bytes.NewReader([]byte(data)))
+		if e != nil {
+			t.Fatal(e)
+		}
+		req.Header.Set("X-Amz-Content-Sha256", "UNSIGNED-PAYLOAD")
+		resp, e := clnt.Do(signer.SignV4(*req, "minio", "minio123", "", "us-east-1"))
+		if e != nil {
+			t.Fatal(e)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// The first request is sent again once redirected, the region of
+	// the bucket is reused by the next ones.
+	if status := send(http.MethodPut, "data"); status != http.StatusOK || requests != 2 || string(body) != "data" {
+		t.Fatalf("expected the request to be sent again, got status %d after %d requests with %q", status, requests, body)
+	}
+	if status := send(http.MethodGet, ""); status != http.StatusOK || requests != 3 {
+		t.Fatalf("expected the cached region to be used, got status %d after %d requests", status, requests)
+	}
+}
+
+func TestAWSRegionalHost(t *testing.T) {
+	testCases := []struct {
+		host, region, expected string
+	}{
+		{"s3.amazonaws.com", "eu-west-1", "s3.eu-west-1.amazonaws.com"},
+		{"s3.us-east-1.amazonaws.com", "eu-west-1", "s3.eu-west-1.amazonaws.com"},
+		{"mybucket.s3.us-west-2.amazonaws.com", "ap-south-1", "mybucket.s3.ap-south-1.amazonaws.com"},
+		{"s3-accelerate.amazonaws.com", "eu-west-1", "s3-accelerate.amazonaws.com"},
+		{"mys3.amazonaws.com", "eu-west-1", "mys3.amazonaws.com"},
+		{"play.min.io", "eu-west-1", "play.min.io"},
+	}
+	for i, testCase := range testCases {
+		if host := awsRegionalHost(testCase.host, testCase.region); host != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, host)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7/pkg/s3utils"
+)
+
+const (
+	// Servers reject requests signed 15 minutes away from their time,
+	// a smaller skew is reported before requests start failing.
+	clockSkewThreshold = time.Minute
+
+	signV4Algorithm = "AWS4-HMAC-SHA256"
+	amzDateFormat   = "20060102T150405Z"
+	amzScopeFormat  = "20060102"
+)
+
+// clockSkew - offset of the server time from the local clock.
+type clockSkew struct {
+	offset int64 // nanoseconds, zero below clockSkewThreshold
+	warned uint32
+}
+
+// Clock skews by server host, shared by all the transports to a host.
+var clockSkews sync.Map
+
+func getClockSkew(host string) *clockSkew {
+	skew, _ := clockSkews.LoadOrStore(host, &clockSkew{})
+	return skew.(*clockSkew)
+}
+
+// observe - saves the offset of the Date header of a server response,
+// returns true when it changed.
+func (s *clockSkew) observe(serverTime, localTime time.Time) bool {
+	offset := serverTime.Sub(localTime)
+	if offset > -clockSkewThreshold && offset < clockSkewThreshold {
+		offset = 0
+	}
+	return atomic.SwapInt64(&s.offset, int64(offset)) != int64(offset)
+}
+
+func (s *clockSkew) get() time.Duration {
+	return time.Duration(atomic.LoadInt64(&s.offset))
+}
+
+// clockSkewTransport - warns when the local clock is skewed from the
+// server time, and with --adjust-clock-skew signs requests with the
+// server time instead.
+type clockSkewTransport struct {
+	transport http.RoundTripper
+	alias     string
+	accessKey string
+	secretKey string
+	skew      *clockSkew
+}
+
+func newClockSkewTransport(config *Config, transport http.RoundTripper) http.RoundTripper {
+	host := config.HostURL
+	if u, e := url.Parse(config.HostURL); e == nil && u.Host != "" {
+		host = u.Host
+	}
+	return &clockSkewTransport{
+		transport: transport,
+		alias:     config.Alias,
+		accessKey: config.AccessKey,
+		secretKey: config.SecretKey,
+		skew:      getClockSkew(host),
+	}
+}
+
+// RoundTrip - implements http.RoundTripper.
+func (t *clockSkewTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if offset := t.skew.get(); globalAdjustClockSkew && offset != 0 {
+		req = t.resign(req, offset)
+	}
+	resp, e := t.transport.RoundTrip(req)
+	if e != nil {
+		return resp, e
+	}
+	serverTime, e := http.ParseTime(resp.Header.Get("Date"))
+	if e != nil || !t.skew.observe(serverTime, time.Now()) {
+		return resp, nil
+	}
+	offset := t.skew.get()
+	if offset == 0 {
+		return resp, nil
+	}
+	t.warn(offset)
+
+	// Requests without a body are sent again, signed with the server
+	// time, when they were rejected because of the skew.
+	if globalAdjustClockSkew && resp.StatusCode == http.StatusForbidden &&
+		(req.Body == nil || req.Body == http.NoBody) {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return t.transport.RoundTrip(t.resign(req, offset))
+	}
+	return resp, nil
+}
+
+func (t *clockSkewTransport) warn(offset time.Duration) {
+	if !atomic.CompareAndSwapUint32(&t.skew.warned, 0, 1) {
+		return
+	}
+	direction := "behind"
+	if offset < 0 {
+		direction, offset = "ahead of", -offset
+	}
+	msg := "Please synchronize the local clock, requests signed 15 minutes away from the server time are rejected."
+	if globalAdjustClockSkew {
+		msg = "Requests are signed with the server time."
+	}
+	warningIf(probe.NewError(fmt.Errorf("the local clock is %s %s the server time of `%s`. %s",
+		offset.Round(time.Second), direction, t.alias, msg)), "Clock skew detected:")
+}
+
+// resign - returns a copy of the V4 signed request, signed again at the
+// local time shifted by offset.
+func (t *clockSkewTransport) resign(req *http.Request, offset time.Duration) *http.Request {
+	return resignRequest(req, t.accessKey, t.secretKey, time.Now().Add(offset))
+}
+
+// resignRequest - returns a copy of the V4 signed request, signed again at
+// time at. Requests with chunk signatures, bound to their signing time,
+// are returned as is.
+func resignRequest(req *http.Request, accessKey, secretKey string, at time.Time) *http.Request {
+	if !strings.HasPrefix(req.Header.Get("Authorization"), signV4Algorithm+" ") {
+		return req
+	}
+	payload := req.Header.Get("X-Amz-Content-Sha256")
+	if payload == "" || (strings.HasPrefix(payload, "STREAMING-") && payload != "STREAMING-UNSIGNED-PAYLOAD-TRAILER") {
+		return req
+	}
+	resigned := req.Clone(req.Context())
+	if !signV4At(resigned, accessKey, secretKey, at) {
+		return req
+	}
+	return resigned
+}
+
+// signV4At - signs again a V4 signed request at time at, with the
+// credential scope and signed headers of its current signature.
+func signV4At(req *http.Request, accessKey, secretKey string, at time.Time) bool {
+	return signV4InRegion(req, accessKey, secretKey, at, "")
+}
+
+// signV4InRegion - signs again a V4 signed request at time at, for region
+// when not empty, or else the region of its current signature.
+func signV4InRegion(req *http.Request, accessKey, secretKey string, at time.Time, region string) bool {
+	var credential, signedHeaders string
+	for _, field := range strings.Split(strings.TrimPrefix(req.Header.Get("Authorization"), signV4Algorithm+" "), ",") {
+		field = strings.TrimSpace(field)
+		switch {
+		case strings.HasPrefix(field, "Credential="):
+			credential = strings.TrimPrefix(field, "Credential=")
+		case strings.HasPrefix(field, "SignedHeaders="):
+			signedHeaders = strings.TrimPrefix(field, "SignedHeaders=")
+		}
+	}
+	// Credential is <access key>/<date>/<region>/<service>/aws4_request
+	scope := strings.Split(credential, "/")
+	if len(scope) != 5 || scope[0] != accessKey || signedHeaders == "" {
+		return false
+	}
+
+	at = at.UTC()
+	req.Header.Set("X-Amz-Date", at.Format(amzDateFormat))
+	scope[1] = at.Format(amzScopeFormat)
+	if region != "" {
+		scope[2] = region
+	}
+	credentialScope := strings.Join(scope[1:], "/")
+
+	var headers strings.Builder
+	for _, k := range strings.Split(signedHeaders, ";") {
+		headers.WriteString(k + ":")
+		if k == "host" {
+			headers.WriteString(signV4Host(req))
+		} else {
+			values := req.Header.Values(k)
+			for i, v := range values {
+				values[i] = strings.Join(strings.Fields(v), " ")
+			}
+			headers.WriteString(strings.Join(values, ","))
+		}
+		headers.WriteByte('\n')
+	}
+	req.URL.RawQuery = strings.ReplaceAll(req.URL.Query().Encode(), "+", "%20")
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3utils.EncodePath(req.URL.Path),
+		req.URL.RawQuery,
+		headers.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+	hashedRequest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := signV4Algorithm + "\n" + at.Format(amzDateFormat) + "\n" + credentialScope + "\n" + hex.EncodeToString(hashedRequest[:])
+
+	signingKey := []byte("AWS4" + secretKey)
+	for _, s := range scope[1:] {
+		signingKey = sumHMAC(signingKey, []byte(s))
+	}
+	signature := hex.EncodeToString(sumHMAC(signingKey, []byte(stringToSign)))
+
+	req.Header.Set("Authorization", signV4Algorithm+" "+strings.Join([]string{
+		"Credential=" + strings.Join(scope, "/"),
+		"SignedHeaders=" + signedHeaders,
+		"Signature=" + signature,
+	}, ", "))
+	return true
+}
+
+// signV4Host - returns the host signed by minio-go.
+func signV4Host(req *http.Request) string {
+	if host := req.Header.Get("Host"); host != "" && req.Host != host {
+		return host
+	}
+	if req.Host != "" {
+		return req.Host
+	}
+	return req.URL.Host
+}
+
+func sumHMAC(key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
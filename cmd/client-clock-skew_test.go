@@ -0,0 +1,93 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/signer"
+)
+
+func TestSignV4At(t *testing.T) {
+	req, e := http.NewRequest(http.MethodGet, "http://localhost:9000/bucket/my%20object?versionId=1&uploads=", nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	req.Header.Set("X-Amz-Content-Sha256", "UNSIGNED-PAYLOAD")
+	req.Header.Set("X-Amz-Meta-Note", "  two   spaces ")
+	signed := signer.SignV4(*req, "minio", "minio123", "", "us-east-1")
+
+	at, e := time.Parse(amzDateFormat, signed.Header.Get("X-Amz-Date"))
+	if e != nil {
+		t.Fatal(e)
+	}
+	resigned := signed.Clone(signed.Context())
+	if !signV4At(resigned, "minio", "minio123", at) {
+		t.Fatal("expected the request to be signed")
+	}
+	if got, want := resigned.Header.Get("Authorization"), signed.Header.Get("Authorization"); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if signV4At(signed.Clone(signed.Context()), "other", "minio123", at) {
+		t.Error("expected requests of other access keys to be left unsigned")
+	}
+}
+
+func TestClockSkewTransport(t *testing.T) {
+	serverOffset := 20 * time.Minute
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now().Add(serverOffset)
+		w.Header().Set("Date", now.UTC().Format(http.TimeFormat))
+		at, e := time.Parse(amzDateFormat, r.Header.Get("X-Amz-Date"))
+		if e != nil || now.Sub(at) > 15*time.Minute || at.Sub(now) > 15*time.Minute {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	defer func(adjust bool) { globalAdjustClockSkew = adjust }(globalAdjustClockSkew)
+	globalAdjustClockSkew = true
+
+	clnt := &http.Client{Transport: newClockSkewTransport(&Config{
+		Alias:     "myminio",
+		HostURL:   server.URL,
+		AccessKey: "minio",
+		SecretKey: "minio123",
+	}, http.DefaultTransport)}
+	for i := 0; i < 2; i++ {
+		req, e := http.NewRequest(http.MethodGet, server.URL+"/bucket", nil)
+		if e != nil {
+			t.Fatal(e)
+		}
+		req.Header.Set("X-Amz-Content-Sha256", "UNSIGNED-PAYLOAD")
+		resp, e := clnt.Do(signer.SignV4(*req, "minio", "minio123", "", "us-east-1"))
+		if e != nil {
+			t.Fatal(e)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("request %d: expected status 200, got %d", i, resp.StatusCode)
+		}
+	}
+	if offset := getClockSkew(server.Listener.Addr().String()).get(); offset < 19*time.Minute || offset > 21*time.Minute {
+		t.Errorf("expected a skew of about %s, got %s", serverOffset, offset)
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/signer"
+	"github.com/minio/pkg/v2/env"
+	"golang.org/x/term"
+)
+
+// credentialProvider - returns a provider of credentials for the alias of
+// config, tried when the config has no keys for its host.
+type credentialProvider func(config *Config, transport http.RoundTripper) credentials.Provider
+
+// credentialProviders - the fallback providers, by their name in
+// MC_CREDENTIAL_PROVIDERS.
+var credentialProviders = map[string]credentialProvider{
+	// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN, not
+	// named "env" as such values are read as `env://` URLs by env.Get.
+	"aws": func(*Config, http.RoundTripper) credentials.Provider {
+		return &credentials.EnvAWS{}
+	},
+	// ECS task role, or else EC2 instance metadata.
+	"iam": func(_ *Config, transport http.RoundTripper) credentials.Provider {
+		return &credentials.IAM{
+			Client: &http.Client{
+				Transport: transport,
+			},
+		}
+	},
+}
+
+// Instance metadata is only queried when listed, away from EC2 the
+// requests to it time out.
+const defaultCredentialProviders = "aws"
+
+// getCredentialProviders - returns the fallback providers listed in
+// MC_CREDENTIAL_PROVIDERS_<alias>, or else MC_CREDENTIAL_PROVIDERS.
+func getCredentialProviders(config *Config, transport http.RoundTripper) ([]credentials.Provider, *probe.Error) {
+	names := env.Get("MC_CREDENTIAL_PROVIDERS", defaultCredentialProviders)
+	names = env.Get("MC_CREDENTIAL_PROVIDERS_"+config.Alias, names)
+
+	var providers []credentials.Provider
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || name == "none" {
+			continue
+		}
+		newProvider, ok := credentialProviders[name]
+		if !ok {
+			return nil, probe.NewError(fmt.Errorf("unknown credential provider `%s`, expected one of `aws`, `iam` or `none`", name))
+		}
+		providers = append(providers, newProvider(config, transport))
+	}
+	return providers, nil
+}
+
+// MFA protected roles are assumed once per alias, and their
+// credentials shared by all the clients of the alias.
+var assumedRoles sync.Map
+
+// assumeRole - provides the credentials of the role MC_ROLE_ARN_<alias>,
+// assumed with the keys of the alias, and when MC_MFA_SERIAL_<alias> is
+// set, the token code of that MFA device.
+type assumeRole struct {
+	credentials.Expiry
+
+	mu          sync.Mutex
+	value       credentials.Value
+	err         error
+	client      *http.Client
+	endpoint    string
+	region      string
+	keys        *credentials.Credentials
+	roleARN     string
+	sessionName string
+	duration    int
+	mfaSerial   string
+	alias       string
+}
+
+func newAssumeRole(config *Config, transport http.RoundTripper, roleARN string, keys []credentials.Provider) *assumeRole {
+	endpoint := env.Get("MC_STS_ENDPOINT_"+config.Alias, "")
+	if endpoint == "" {
+		// MinIO serves STS at the host of its S3 API.
+		endpoint = config.HostURL
+		if u, e := url.Parse(config.HostURL); e == nil {
+			endpoint = u.Scheme + "://" + u.Host
+			if isAmazon(u.Host) {
+				endpoint = credentials.DefaultSTSRoleEndpoint
+			}
+		}
+	}
+	region := env.Get("MC_REGION", env.Get("AWS_REGION", config.Region))
+	if region == "" {
+		region = "us-east-1"
+	}
+	duration, _ := strconv.Atoi(env.Get("MC_ROLE_DURATION_"+config.Alias, ""))
+	if duration <= 0 {
+		duration = int(time.Hour / time.Second)
+	}
+	role := &assumeRole{
+		client:      &http.Client{Transport: transport},
+		endpoint:    endpoint,
+		region:      region,
+		keys:        credentials.NewChainCredentials(keys),
+		roleARN:     roleARN,
+		sessionName: env.Get("MC_ROLE_SESSION_NAME_"+config.Alias, randString(32, rand.NewSource(time.Now().UnixNano()), "mc-session-name-")),
+		duration:    duration,
+		mfaSerial:   env.Get("MC_MFA_SERIAL_"+config.Alias, ""),
+		alias:       config.Alias,
+	}
+	shared, _ := assumedRoles.LoadOrStore(config.Alias+"\x00"+roleARN+"\x00"+endpoint, role)
+	return shared.(*assumeRole)
+}
+
+// mfaTokenCode - returns MC_MFA_TOKEN_<alias>, or else reads the token
+// code from the terminal.
+func (a *assumeRole) mfaTokenCode() (string, error) {
+	if code := env.Get("MC_MFA_TOKEN_"+a.alias, ""); code != "" {
+		return code, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("a token code of the MFA device `%s` is required to assume the role `%s` of `%s`, set MC_MFA_TOKEN_%s", a.mfaSerial, a.roleARN, a.alias, a.alias)
+	}
+	fmt.Fprintf(os.Stderr, "Enter MFA code for `%s`: ", a.mfaSerial)
+	code, e := bufio.NewReader(os.Stdin).ReadString('\n')
+	if e != nil && e != io.EOF {
+		return "", e
+	}
+	return strings.TrimSpace(code), nil
+}
+
+// Retrieve - implements credentials.Provider, assumes the role again only
+// once its credentials expire.
+func (a *assumeRole) Retrieve() (credentials.Value, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.err != nil || (a.value.AccessKeyID != "" && !a.Expiry.IsExpired()) {
+		return a.value, a.err
+	}
+	a.value, a.err = a.assume()
+	if a.err != nil {
+		// The chain moves on to anonymous requests, the role is not
+		// assumed again, nor the token code asked again.
+		errorIf(probe.NewError(a.err), "Unable to assume the role `%s` for `%s`.", a.roleARN, a.alias)
+	}
+	return a.value, a.err
+}
+
+func (a *assumeRole) assume() (credentials.Value, error) {
+	keys, e := a.keys.Get()
+	if e != nil {
+		return credentials.Value{}, e
+	}
+	v := url.Values{}
+	v.Set("Action", "AssumeRole")
+	v.Set("Version", credentials.STSVersion)
+	v.Set("RoleArn", a.roleARN)
+	v.Set("RoleSessionName", a.sessionName)
+	v.Set("DurationSeconds", strconv.Itoa(a.duration))
+	if a.mfaSerial != "" {
+		code, e := a.mfaTokenCode()
+		if e != nil {
+			return credentials.Value{}, e
+		}
+		v.Set("SerialNumber", a.mfaSerial)
+		v.Set("TokenCode", code)
+	}
+
+	body := v.Encode()
+	req, e := http.NewRequest(http.MethodPost, strings.TrimSuffix(a.endpoint, "/")+"/", strings.NewReader(body))
+	if e != nil {
+		return credentials.Value{}, e
+	}
+	sum := sha256.Sum256([]byte(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sum[:]))
+	if keys.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", keys.SessionToken)
+	}
+	req = signer.SignV4STS(*req, keys.AccessKeyID, keys.SecretAccessKey, a.region)
+
+	resp, e := a.client.Do(req)
+	if e != nil {
+		return credentials.Value{}, e
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var errResp credentials.ErrorResponse
+		if e = xml.NewDecoder(resp.Body).Decode(&errResp); e != nil || errResp.STSError.Code == "" {
+			return credentials.Value{}, fmt.Errorf("unable to assume the role `%s`: %s", a.roleARN, resp.Status)
+		}
+		return credentials.Value{}, errResp
+	}
+	var result credentials.AssumeRoleResponse
+	if e = xml.NewDecoder(resp.Body).Decode(&result); e != nil {
+		return credentials.Value{}, e
+	}
+	creds := result.Result.Credentials
+	a.SetExpiration(creds.Expiration, credentials.DefaultExpiryWindow)
+	return credentials.Value{
+		AccessKeyID:     creds.AccessKey,
+		SecretAccessKey: creds.SecretKey,
+		SessionToken:    creds.SessionToken,
+		SignerType:      credentials.SignatureV4,
+	}, nil
+}
+
+// IsExpired - implements credentials.Provider.
+func (a *assumeRole) IsExpired() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.Expiry.IsExpired()
+}
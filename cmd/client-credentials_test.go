@@ -0,0 +1,110 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+func getTestCredentials(t *testing.T, config *Config) credentials.Value {
+	t.Helper()
+	chain, err := getCredentialsChainForConfig(config, http.DefaultTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, e := credentials.NewChainCredentials(chain).Get()
+	if e != nil {
+		t.Fatal(e)
+	}
+	return value
+}
+
+func TestCredentialProviders(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "ENVACCESSKEY")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "envsecretkey")
+	t.Setenv("AWS_SESSION_TOKEN", "envtoken")
+
+	// The keys of the config come first.
+	value := getTestCredentials(t, &Config{Alias: "creds", HostURL: "http://localhost:9000", AccessKey: "ACCESSKEY", SecretKey: "secretkey"})
+	if value.AccessKeyID != "ACCESSKEY" || value.SessionToken != "" {
+		t.Fatalf("expected the keys of the config, got %+v", value)
+	}
+
+	// Without keys, the environment.
+	value = getTestCredentials(t, &Config{Alias: "creds", HostURL: "http://localhost:9000"})
+	if value.AccessKeyID != "ENVACCESSKEY" || value.SecretAccessKey != "envsecretkey" || value.SessionToken != "envtoken" {
+		t.Fatalf("expected the keys of the environment, got %+v", value)
+	}
+
+	// Unless the alias has no fallback.
+	t.Setenv("MC_CREDENTIAL_PROVIDERS_creds", "none")
+	value = getTestCredentials(t, &Config{Alias: "creds", HostURL: "http://localhost:9000"})
+	if value.AccessKeyID != "" {
+		t.Fatalf("expected anonymous credentials, got %+v", value)
+	}
+
+	t.Setenv("MC_CREDENTIAL_PROVIDERS_creds", "aws,vault")
+	if _, err := getCredentialsChainForConfig(&Config{Alias: "creds"}, http.DefaultTransport); err == nil {
+		t.Fatal("expected an error for an unknown credential provider")
+	}
+}
+
+func TestAssumeRoleMFA(t *testing.T) {
+	var calls int32
+	sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if !strings.Contains(r.Header.Get("Authorization"), "Credential=ACCESSKEY/") ||
+			r.Form.Get("Action") != "AssumeRole" ||
+			r.Form.Get("RoleArn") != "arn:aws:iam::123456789012:role/admin" ||
+			r.Form.Get("SerialNumber") != "arn:aws:iam::123456789012:mfa/user" ||
+			r.Form.Get("TokenCode") != "123456" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`<ErrorResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/"><Error><Code>AccessDenied</Code><Message>Access denied</Message></Error></ErrorResponse>`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(`<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/"><AssumeRoleResult><Credentials><AccessKeyId>ROLEACCESSKEY</AccessKeyId><SecretAccessKey>rolesecretkey</SecretAccessKey><SessionToken>roletoken</SessionToken><Expiration>2099-01-01T00:00:00Z</Expiration></Credentials></AssumeRoleResult></AssumeRoleResponse>`))
+	}))
+	defer sts.Close()
+
+	t.Setenv("MC_STS_ENDPOINT_mfa", sts.URL)
+	t.Setenv("MC_ROLE_ARN_mfa", "arn:aws:iam::123456789012:role/admin")
+	t.Setenv("MC_MFA_SERIAL_mfa", "arn:aws:iam::123456789012:mfa/user")
+	t.Setenv("MC_MFA_TOKEN_mfa", "123456")
+
+	config := &Config{Alias: "mfa", HostURL: "http://localhost:9000", AccessKey: "ACCESSKEY", SecretKey: "secretkey"}
+	for i := 0; i < 2; i++ {
+		value := getTestCredentials(t, config)
+		if value.AccessKeyID != "ROLEACCESSKEY" || value.SecretAccessKey != "rolesecretkey" || value.SessionToken != "roletoken" {
+			t.Fatalf("expected the keys of the role, got %+v", value)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected the role to be assumed once, got %d calls", calls)
+	}
+}
@@ -0,0 +1,419 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/minio/mc/pkg/hookreader"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/secure-io/sio-go"
+)
+
+const (
+	encryptKeyTypeSSEC   = "sse-c"
+	encryptKeyTypeClient = "client"
+
+	// Client side encrypted objects are the nonce followed by the
+	// AES-256-GCM sio stream of the data, of sio.BufSize fragments.
+	clientEncryptionAlgorithm = sio.AES_256_GCM
+	clientEncryptionNonceSize = 8
+	clientEncryptionTagSize   = 16
+)
+
+// decodeEncryptKey - decodes a 32 bytes plain text or base64 encoded key.
+func decodeEncryptKey(key string) ([]byte, *probe.Error) {
+	if len(key) == 32 {
+		return []byte(key), nil
+	}
+	decoded, e := base64.StdEncoding.DecodeString(key)
+	if e != nil || len(decoded) != 32 {
+		return nil, probe.NewError(errors.New("Encryption key should be 32 bytes plain text key or 44 bytes base64 encoded key"))
+	}
+	return decoded, nil
+}
+
+// getConfigEncryptKeys - returns the encryption keys of the config file
+// of the given type, longest prefixes first.
+func getConfigEncryptKeys(keyType string) (prefixes []string, keys [][]byte) {
+	config, err := loadMcConfig()
+	if err != nil || len(config.EncryptKeys) == 0 {
+		return nil, nil
+	}
+	for prefix, k := range config.EncryptKeys {
+		t := k.Type
+		if t == "" {
+			t = encryptKeyTypeSSEC
+		}
+		if t == keyType {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		return len(prefixes[i]) > len(prefixes[j])
+	})
+	for _, prefix := range prefixes {
+		key, err := decodeEncryptKey(config.EncryptKeys[prefix].Key)
+		fatalIf(err.Trace(prefix), "Invalid encryption key of `"+prefix+"` in the configuration.")
+		keys = append(keys, key)
+	}
+	return prefixes, keys
+}
+
+// clientEncryptionKey - returns the client side encryption key of the
+// aliased URL, nil if objects under it are stored as is.
+func clientEncryptionKey(aliasedURL string) []byte {
+	prefixes, keys := getConfigEncryptKeys(encryptKeyTypeClient)
+	for i, prefix := range prefixes {
+		if strings.HasPrefix(aliasedURL, prefix) {
+			return keys[i]
+		}
+	}
+	return nil
+}
+
+// clientEncryptedSize - returns the size of size bytes once encrypted,
+// -1 when the size is unknown.
+func clientEncryptedSize(size int64) int64 {
+	if size < 0 {
+		return -1
+	}
+	fragments := (size + sio.BufSize - 1) / sio.BufSize
+	if fragments == 0 {
+		fragments = 1
+	}
+	return clientEncryptionNonceSize + size + fragments*clientEncryptionTagSize
+}
+
+// clientDecryptedSize - returns the size of the data of an encrypted
+// object of size bytes.
+func clientDecryptedSize(size int64) int64 {
+	size -= clientEncryptionNonceSize
+	if size <= clientEncryptionTagSize {
+		return 0
+	}
+	fragment := int64(sio.BufSize + clientEncryptionTagSize)
+	decrypted := size / fragment * sio.BufSize
+	if r := size % fragment; r > 0 {
+		decrypted += r - clientEncryptionTagSize
+	}
+	return decrypted
+}
+
+// encryptedClient - encrypts the objects put under the prefixes having a
+// client side encryption key, and decrypts them when read. Sizes are
+// reported decrypted, so that comparisons with plain text copies work.
+type encryptedClient struct {
+	Client
+	alias    string
+	hostURL  string
+	prefixes []string
+	keys     [][]byte
+}
+
+// newEncryptedClient - wraps clnt when client side encryption keys are
+// configured for prefixes of alias.
+func newEncryptedClient(clnt Client, alias string, hostCfg *aliasConfigV10) Client {
+	prefixes, keys := getConfigEncryptKeys(encryptKeyTypeClient)
+	c := &encryptedClient{Client: clnt, alias: alias, hostURL: strings.TrimSuffix(hostCfg.URL, "/")}
+	for i, prefix := range prefixes {
+		if keyAlias, _ := url2Alias(prefix); keyAlias == alias {
+			c.prefixes = append(c.prefixes, prefix)
+			c.keys = append(c.keys, keys[i])
+		}
+	}
+	if len(c.prefixes) == 0 {
+		return clnt
+	}
+	return c
+}
+
+// Unwrap - returns the wrapped client.
+func (c *encryptedClient) Unwrap() Client {
+	return c.Client
+}
+
+// keyFor - returns the key of the prefix the object at urlPath, relative
+// to the alias URL, belongs to.
+func (c *encryptedClient) keyFor(urlPath string) []byte {
+	aliasedURL := c.alias + "/" + strings.TrimPrefix(urlPath, "/")
+	for i, prefix := range c.prefixes {
+		if strings.HasPrefix(aliasedURL, prefix) {
+			return c.keys[i]
+		}
+	}
+	return nil
+}
+
+// contentKey - returns the key of a listed or stat'ed object.
+func (c *encryptedClient) contentKey(u ClientURL) []byte {
+	return c.keyFor(strings.TrimPrefix(u.String(), c.hostURL))
+}
+
+func (c *encryptedClient) targetKey() []byte {
+	return c.contentKey(c.GetURL())
+}
+
+func (c *encryptedClient) decryptedContent(content *ClientContent) *ClientContent {
+	if content != nil && content.Type.IsRegular() && c.contentKey(content.URL) != nil {
+		content.Size = clientDecryptedSize(content.Size)
+	}
+	return content
+}
+
+func (c *encryptedClient) notImplemented(api string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     api,
+		APIType: "client side encrypted objects",
+	})
+}
+
+// Stat - returns the object properties with the decrypted size.
+func (c *encryptedClient) Stat(ctx context.Context, opts StatOptions) (*ClientContent, *probe.Error) {
+	content, err := c.Client.Stat(ctx, opts)
+	return c.decryptedContent(content), err
+}
+
+// List - lists objects with their decrypted sizes.
+func (c *encryptedClient) List(ctx context.Context, opts ListOptions) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		for content := range c.Client.List(ctx, opts) {
+			select {
+			case <-ctx.Done():
+				return
+			case contentCh <- c.decryptedContent(content):
+			}
+		}
+	}()
+	return contentCh
+}
+
+// decryptReader - closes the encrypted object once decrypted.
+type decryptReader struct {
+	io.Reader
+	io.Closer
+}
+
+// Get - decrypts the object, ranges are read decrypting from the start.
+func (c *encryptedClient) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *ClientContent, *probe.Error) {
+	key := c.targetKey()
+	if key == nil {
+		return c.Client.Get(ctx, opts)
+	}
+	if opts.SSE != nil || opts.Zip {
+		return nil, nil, c.notImplemented("Get with server side encryption or zip")
+	}
+	stream, e := clientEncryptionAlgorithm.Stream(key)
+	if e != nil {
+		return nil, nil, probe.NewError(e)
+	}
+	if opts.isRange() {
+		return c.getRange(ctx, stream, opts)
+	}
+	reader, content, err := c.Client.Get(ctx, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, clientEncryptionNonceSize)
+	if _, e = io.ReadFull(reader, nonce); e != nil {
+		reader.Close()
+		return nil, nil, errNotClientEncrypted()
+	}
+	return decryptReader{Reader: stream.DecryptReader(reader, nonce, nil), Closer: reader}, c.decryptedContent(content), nil
+}
+
+func errNotClientEncrypted() *probe.Error {
+	return probe.NewError(errors.New("object is not client side encrypted"))
+}
+
+// getRange - reads a range of a client side encrypted object, starting
+// at the fragment holding the first byte of the range.
+func (c *encryptedClient) getRange(ctx context.Context, stream *sio.Stream, opts GetOptions) (io.ReadCloser, *ClientContent, *probe.Error) {
+	content, err := c.Stat(ctx, StatOptions{versionID: opts.VersionID, preserve: opts.Preserve})
+	if err != nil {
+		return nil, nil, err
+	}
+	start := opts.RangeStart
+	if start > content.Size {
+		start = content.Size
+	}
+	length := content.Size - start
+	if opts.RangeLength > 0 && opts.RangeLength < length {
+		length = opts.RangeLength
+	}
+
+	nonceOpts := opts
+	nonceOpts.RangeStart, nonceOpts.RangeLength = 0, clientEncryptionNonceSize
+	reader, _, err := c.Client.Get(ctx, nonceOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, clientEncryptionNonceSize)
+	_, e := io.ReadFull(reader, nonce)
+	reader.Close()
+	if e != nil {
+		return nil, nil, errNotClientEncrypted()
+	}
+
+	object := &encryptedObjectReaderAt{ctx: ctx, clnt: c.Client, opts: opts}
+	ciphertext := io.NewSectionReader(object, clientEncryptionNonceSize, math.MaxInt64-clientEncryptionNonceSize)
+	decrypted := io.NewSectionReader(stream.DecryptReaderAt(ciphertext, nonce, nil), start, length)
+	return decryptReader{Reader: decrypted, Closer: object}, content, nil
+}
+
+// encryptedObjectReaderAt - reads an object with ranged gets, continuing
+// the previous get for sequential reads. sio reads every fragment from its
+// start, the last fragments read are kept to serve reads starting within
+// a fragment read before.
+type encryptedObjectReaderAt struct {
+	ctx  context.Context
+	clnt Client
+	opts GetOptions
+
+	reader io.ReadCloser
+	pos    int64  // offset of the next byte of reader
+	tail   []byte // bytes read just before pos
+}
+
+const encryptedObjectTailSize = 2 * (sio.BufSize + clientEncryptionTagSize)
+
+func (r *encryptedObjectReaderAt) ReadAt(p []byte, off int64) (n int, e error) {
+	if k := r.pos - off; r.reader != nil && k > 0 && k <= int64(len(r.tail)) {
+		n = copy(p, r.tail[int64(len(r.tail))-k:])
+		if n == len(p) {
+			return n, nil
+		}
+		off += int64(n)
+	}
+	if r.reader == nil || off != r.pos {
+		r.Close()
+		opts := r.opts
+		opts.RangeStart, opts.RangeLength = off, 0
+		reader, _, err := r.clnt.Get(r.ctx, opts)
+		if err != nil {
+			return n, err.ToGoError()
+		}
+		r.reader, r.pos = reader, off
+	}
+	m, e := io.ReadFull(r.reader, p[n:])
+	r.remember(p[n : n+m])
+	if e == io.ErrUnexpectedEOF {
+		e = io.EOF
+	}
+	return n + m, e
+}
+
+func (r *encryptedObjectReaderAt) remember(b []byte) {
+	r.pos += int64(len(b))
+	r.tail = append(r.tail, b...)
+	if extra := len(r.tail) - encryptedObjectTailSize; extra > 0 {
+		r.tail = r.tail[:copy(r.tail, r.tail[extra:])]
+	}
+}
+
+func (r *encryptedObjectReaderAt) Close() error {
+	if r.reader == nil {
+		return nil
+	}
+	e := r.reader.Close()
+	r.reader, r.tail = nil, r.tail[:0]
+	return e
+}
+
+// Put - encrypts the object with a random nonce. Checksums of the
+// plain text are removed since the server can only verify encrypted data.
+func (c *encryptedClient) Put(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
+	key := c.targetKey()
+	if key == nil {
+		return c.Client.Put(ctx, reader, size, progress, opts)
+	}
+	if opts.sse != nil {
+		return 0, c.notImplemented("Put with server side encryption")
+	}
+	stream, e := clientEncryptionAlgorithm.Stream(key)
+	if e != nil {
+		return 0, probe.NewError(e)
+	}
+	nonce := make([]byte, clientEncryptionNonceSize)
+	if _, e = rand.Read(nonce); e != nil {
+		return 0, probe.NewError(e)
+	}
+	metadata := make(map[string]string, len(opts.metadata))
+	for k, v := range opts.metadata {
+		if !strings.HasPrefix(strings.ToLower(k), "x-amz-checksum-") && !strings.EqualFold(k, "Content-Md5") {
+			metadata[k] = v
+		}
+	}
+	opts.metadata = metadata
+
+	// Progress is reported for the plain text read.
+	plain := hookreader.NewHook(reader, progress)
+	if size >= 0 {
+		plain = io.LimitReader(plain, size)
+	}
+	encrypted := io.MultiReader(bytes.NewReader(nonce), stream.EncryptReader(plain, nonce, nil))
+	n, err := c.Client.Put(ctx, encrypted, clientEncryptedSize(size), nil, opts)
+	if err != nil {
+		return 0, err
+	}
+	return clientDecryptedSize(n), nil
+}
+
+// PutPart - parts of encrypted objects are uploaded as a whole.
+func (c *encryptedClient) PutPart(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
+	if c.targetKey() == nil {
+		return c.Client.PutPart(ctx, reader, size, progress, opts)
+	}
+	return c.Put(ctx, reader, size, progress, opts)
+}
+
+// Copy - server side copies are only possible between prefixes sharing
+// the same key, the encrypted data being copied as is.
+func (c *encryptedClient) Copy(ctx context.Context, source string, opts CopyOptions, progress io.Reader) *probe.Error {
+	if !bytes.Equal(c.keyFor(source), c.targetKey()) {
+		return c.notImplemented("Copy between prefixes of different encryption keys")
+	}
+	return c.Client.Copy(ctx, source, opts, progress)
+}
+
+// Select - encrypted objects cannot be queried by the server.
+func (c *encryptedClient) Select(ctx context.Context, expression string, sse encrypt.ServerSide, opts SelectObjectOpts) (io.ReadCloser, *probe.Error) {
+	if c.targetKey() != nil {
+		return nil, c.notImplemented("Select")
+	}
+	return c.Client.Select(ctx, expression, sse, opts)
+}
+
+// GetPart - a part of an encrypted object cannot be decrypted alone.
+func (c *encryptedClient) GetPart(ctx context.Context, part int) (io.ReadCloser, *probe.Error) {
+	if c.targetKey() != nil {
+		return nil, c.notImplemented("GetPart")
+	}
+	return c.Client.GetPart(ctx, part)
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/secure-io/sio-go"
+)
+
+func TestClientEncryptedSize(t *testing.T) {
+	stream, e := clientEncryptionAlgorithm.Stream(bytes.Repeat([]byte{1}, 32))
+	if e != nil {
+		t.Fatal(e)
+	}
+	for _, size := range []int64{0, 1, sio.BufSize - 1, sio.BufSize, sio.BufSize + 1, 3 * sio.BufSize, 5*sio.BufSize + 7} {
+		encrypted := clientEncryptedSize(size)
+		if expected := clientEncryptionNonceSize + size + stream.Overhead(size); encrypted != expected {
+			t.Errorf("size %d: expected encrypted size %d, got %d", size, expected, encrypted)
+		}
+		if decrypted := clientDecryptedSize(encrypted); decrypted != size {
+			t.Errorf("size %d: decrypted size %d", size, decrypted)
+		}
+	}
+	if size := clientEncryptedSize(-1); size != -1 {
+		t.Errorf("unknown size: expected -1, got %d", size)
+	}
+}
+
+func TestEncryptedClient(t *testing.T) {
+	root := t.TempDir()
+	newTestClient := func(p string) Client {
+		clnt, err := fsNew(filepath.Join(root, p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &encryptedClient{
+			Client:   clnt,
+			alias:    "enc",
+			hostURL:  root,
+			prefixes: []string{"enc/secret/"},
+			keys:     [][]byte{bytes.Repeat([]byte{7}, 32)},
+		}
+	}
+	ctx := context.Background()
+	data := bytes.Repeat([]byte("0123456789"), 2*sio.BufSize/10+3)
+
+	for _, p := range []string{"secret/object", "public/object"} {
+		n, err := newTestClient(p).Put(ctx, bytes.NewReader(data), int64(len(data)), nil, PutOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != int64(len(data)) {
+			t.Fatalf("%s: expected %d bytes put, got %d", p, len(data), n)
+		}
+	}
+
+	raw, e := os.ReadFile(filepath.Join(root, "secret", "object"))
+	if e != nil {
+		t.Fatal(e)
+	}
+	if int64(len(raw)) != clientEncryptedSize(int64(len(data))) || bytes.Contains(raw, data[:32]) {
+		t.Fatalf("object under the encrypted prefix is not encrypted")
+	}
+	if raw, _ = os.ReadFile(filepath.Join(root, "public", "object")); !bytes.Equal(raw, data) {
+		t.Fatalf("object outside of the encrypted prefix was modified")
+	}
+
+	clnt := newTestClient("secret/object")
+	content, err := clnt.Stat(ctx, StatOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content.Size != int64(len(data)) {
+		t.Errorf("stat: expected size %d, got %d", len(data), content.Size)
+	}
+	for content := range newTestClient("secret/").List(ctx, ListOptions{Recursive: true}) {
+		if content.Err != nil {
+			t.Fatal(content.Err)
+		}
+		if content.Size != int64(len(data)) {
+			t.Errorf("list: expected size %d, got %d", len(data), content.Size)
+		}
+	}
+
+	size := int64(len(data))
+	fragment := int64(sio.BufSize + clientEncryptionTagSize)
+	testCases := []struct {
+		start, length int64
+		firstGet      int64 // offset of the first ciphertext read
+	}{
+		{0, 0, -1},
+		{3, 10, clientEncryptionNonceSize},
+		{sio.BufSize - 2, 4, clientEncryptionNonceSize},
+		{sio.BufSize + 5, 0, clientEncryptionNonceSize + fragment},
+		{5, 2*sio.BufSize + 1, clientEncryptionNonceSize},
+		{size - 1, 0, clientEncryptionNonceSize + 2*fragment},
+		{size, 0, -1},
+		{size + 10, 5, -1},
+	}
+	for i, testCase := range testCases {
+		recorder := &getRecordingClient{Client: clnt.(*encryptedClient).Client}
+		rangeClnt := *clnt.(*encryptedClient)
+		rangeClnt.Client = recorder
+		reader, _, err := rangeClnt.Get(ctx, GetOptions{RangeStart: testCase.start, RangeLength: testCase.length})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, e := io.ReadAll(reader)
+		reader.Close()
+		if e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		start, end := testCase.start, size
+		if start > size {
+			start = size
+		}
+		if testCase.length > 0 && start+testCase.length < end {
+			end = start + testCase.length
+		}
+		if !bytes.Equal(got, data[start:end]) {
+			t.Errorf("Test %d: ranged get returned %d bytes of wrong data", i+1, len(got))
+		}
+		if testCase.firstGet >= 0 {
+			// The nonce, then the fragment holding the start of the range.
+			if len(recorder.starts) != 2 || recorder.starts[0] != 0 || recorder.starts[1] != testCase.firstGet {
+				t.Errorf("Test %d: expected reads at 0 and %d only, got %v", i+1, testCase.firstGet, recorder.starts)
+			}
+		}
+	}
+}
+
+// getRecordingClient - records the offsets of the objects read.
+type getRecordingClient struct {
+	Client
+	starts []int64
+}
+
+func (c *getRecordingClient) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *ClientContent, *probe.Error) {
+	c.starts = append(c.starts, opts.RangeStart)
+	return c.Client.Get(ctx, opts)
+}
+
+func TestClientEncryptionKey(t *testing.T) {
+	load := loadMcConfig
+	defer func() { loadMcConfig = load }()
+	conf := newConfigV10()
+	conf.EncryptKeys = map[string]encryptKeyV10{
+		"enc/secret/":      {Key: strings.Repeat("a", 32), Type: encryptKeyTypeClient},
+		"enc/secret/deep/": {Key: strings.Repeat("b", 32), Type: encryptKeyTypeClient},
+		"enc/ssec/":        {Key: strings.Repeat("c", 32)},
+	}
+	loadMcConfig = func() (*configV10, *probe.Error) { return conf, nil }
+
+	testCases := []struct {
+		url string
+		key []byte
+	}{
+		{"enc/secret/object", []byte(strings.Repeat("a", 32))},
+		{"enc/secret/deep/object", []byte(strings.Repeat("b", 32))},
+		{"enc/ssec/object", nil},
+		{"enc/public/object", nil},
+		{"other/secret/object", nil},
+	}
+	for i, testCase := range testCases {
+		if key := clientEncryptionKey(testCase.url); !bytes.Equal(key, testCase.key) {
+			t.Errorf("Test %d: expected key %q, got %q", i+1, testCase.key, key)
+		}
+	}
+}
@@ -164,6 +164,16 @@ func (e EmptyPath) Error() string {
 	return "Invalid path, path cannot be empty"
 }
 
+// ObjectNameConflict - object named like the prefix of other objects, a
+// filesystem can't hold both.
+type ObjectNameConflict struct {
+	Object string
+}
+
+func (e ObjectNameConflict) Error() string {
+	return "Object `" + e.Object + "` is also the prefix of other objects, use --name-conflict to keep the object or the prefix."
+}
+
 // ObjectMissing (EINVAL) - object key missing.
 type ObjectMissing struct {
 	timeRef time.Time
@@ -20,7 +20,6 @@ package cmd
 import (
 	"context"
 	"errors"
-	"fmt"
 	"io"
 	"os"
 	"path"
@@ -44,7 +43,6 @@ import (
 	"github.com/minio/minio-go/v7/pkg/lifecycle"
 	"github.com/minio/minio-go/v7/pkg/notification"
 	"github.com/minio/minio-go/v7/pkg/replication"
-	"github.com/minio/pkg/v2/console"
 )
 
 // filesystem client
@@ -165,6 +163,12 @@ func (f *fsClient) Watch(_ context.Context, options WatchOptions) (*WatchObject,
 		}
 	}
 
+	// Prefix and suffix are matched against the event path relative to the watched folder.
+	watchRoot, e := filepath.Abs(f.PathURL.Path)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+
 	// Set up a watchpoint listening for events within a directory tree rooted
 	// at current working directory. Dispatch remove events to c.
 	recursivePath := f.PathURL.Path
@@ -196,6 +200,9 @@ func (f *fsClient) Watch(_ context.Context, options WatchOptions) (*WatchObject,
 			if isIgnoredFile(event.Path()) {
 				continue
 			}
+			if !matchWatchPath(watchRoot, event.Path(), options.Prefix, options.Suffix) {
+				continue
+			}
 			var i os.FileInfo
 			if IsPutEvent(event.Event()) {
 				// Look for any writes, send a response to indicate a full copy.
@@ -241,6 +248,20 @@ func (f *fsClient) Watch(_ context.Context, options WatchOptions) (*WatchObject,
 	}, nil
 }
 
+// matchWatchPath - reports whether the path of a filesystem event, relative
+// to the watched folder root, starts with prefix and ends with suffix.
+func matchWatchPath(root, eventPath, prefix, suffix string) bool {
+	if prefix == "" && suffix == "" {
+		return true
+	}
+	rel, e := filepath.Rel(root, eventPath)
+	if e != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	return strings.HasPrefix(rel, prefix) && strings.HasSuffix(rel, suffix)
+}
+
 func preserveAttributes(fd *os.File, attr map[string]string) *probe.Error {
 	if val, ok := attr["mode"]; ok {
 		mode, e := strconv.ParseUint(val, 0, 32)
@@ -303,11 +324,13 @@ func (f *fsClient) put(_ context.Context, reader io.Reader, size int64, progress
 	// Write to a temporary file "object.part.minio" before commit.
 	objectPartPath := objectPath + partSuffix
 
-	// We cannot resume this operation, then we
-	// should remove any partial download if any.
-	defer os.Remove(objectPartPath)
+	// Remove the partial download on failure, unless
+	// it is to be resumed.
+	if !opts.keepPart {
+		defer os.Remove(objectPartPath)
+	}
 
-	tmpFile, e := os.OpenFile(objectPartPath, os.O_CREATE|os.O_WRONLY, 0o666)
+	tmpFile, e := openPartFile(objectPartPath, opts.resumeOffset)
 	if e != nil {
 		err := f.toClientError(e, f.PathURL.Path)
 		return 0, err.Trace(f.PathURL.Path)
@@ -321,12 +344,14 @@ func (f *fsClient) put(_ context.Context, reader io.Reader, size int64, progress
 			return 0, probe.NewError(e)
 		}
 		err := preserveAttributes(tmpFile, attr)
-		if err != nil {
-			console.Println(console.Colorize("Error", fmt.Sprintf("unable to preserve attributes, continuing to copy the content %s\n", err.ToGoError())))
-		}
+		warningIf(err, "Unable to preserve attributes, continuing to copy the content.")
 	}
 
-	totalWritten, e := io.Copy(tmpFile, hookreader.NewHook(reader, progress))
+	var writer io.WriteCloser = tmpFile
+	if opts.sparse {
+		writer = newSparseWriter(tmpFile, opts.resumeOffset)
+	}
+	totalWritten, e := io.Copy(writer, hookreader.NewHook(reader, progress))
 	if e != nil {
 		tmpFile.Close()
 		return 0, probe.NewError(e)
@@ -344,7 +369,7 @@ func (f *fsClient) put(_ context.Context, reader io.Reader, size int64, progress
 	// Close the file before renaming, we need to do this
 	// specifically for windows users - windows explicitly
 	// disallows renames on Open() fd's by default.
-	if e = tmpFile.Close(); e != nil {
+	if e = writer.Close(); e != nil {
 		return totalWritten, probe.NewError(e)
 	}
 
@@ -387,6 +412,26 @@ func (f *fsClient) put(_ context.Context, reader io.Reader, size int64, progress
 	return totalWritten, nil
 }
 
+// openPartFile - opens the partial file of a download at path, truncated
+// to offset bytes and positioned at its end, empty when offset is zero.
+func openPartFile(path string, offset int64) (*os.File, error) {
+	if offset <= 0 {
+		return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o666)
+	}
+	file, e := os.OpenFile(path, os.O_WRONLY, 0o666)
+	if e != nil {
+		return nil, e
+	}
+	if e = file.Truncate(offset); e == nil {
+		_, e = file.Seek(offset, io.SeekStart)
+	}
+	if e != nil {
+		file.Close()
+		return nil, e
+	}
+	return file, nil
+}
+
 // Put - create a new file with metadata.
 func (f *fsClient) Put(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
 	return f.put(ctx, reader, size, progress, opts)
@@ -417,11 +462,13 @@ func (f *fsClient) putN(_ context.Context, reader io.Reader, size int64, progres
 	// Write to a temporary file "object.part.minio" before commit.
 	objectPartPath := objectPath + partSuffix
 
-	// We cannot resume this operation, then we
-	// should remove any partial download if any.
-	defer os.Remove(objectPartPath)
+	// Remove the partial download on failure, unless
+	// it is to be resumed.
+	if !opts.keepPart {
+		defer os.Remove(objectPartPath)
+	}
 
-	tmpFile, e := os.OpenFile(objectPartPath, os.O_CREATE|os.O_WRONLY, 0o666)
+	tmpFile, e := openPartFile(objectPartPath, opts.resumeOffset)
 	if e != nil {
 		err := f.toClientError(e, f.PathURL.Path)
 		return 0, err.Trace(f.PathURL.Path)
@@ -435,12 +482,14 @@ func (f *fsClient) putN(_ context.Context, reader io.Reader, size int64, progres
 			return 0, probe.NewError(e)
 		}
 		err := preserveAttributes(tmpFile, attr)
-		if err != nil {
-			console.Println(console.Colorize("Error", fmt.Sprintf("unable to preserve attributes, continuing to copy the content %s\n", err.ToGoError())))
-		}
+		warningIf(err, "Unable to preserve attributes, continuing to copy the content.")
 	}
 
-	totalWritten, e := io.CopyN(tmpFile, hookreader.NewHook(reader, progress), size)
+	var writer io.WriteCloser = tmpFile
+	if opts.sparse {
+		writer = newSparseWriter(tmpFile, opts.resumeOffset)
+	}
+	totalWritten, e := io.CopyN(writer, hookreader.NewHook(reader, progress), size)
 	if e != nil {
 		tmpFile.Close()
 		return 0, probe.NewError(e)
@@ -458,7 +507,7 @@ func (f *fsClient) putN(_ context.Context, reader io.Reader, size int64, progres
 	// Close the file before renaming, we need to do this
 	// specifically for windows users - windows explicitly
 	// disallows renames on Open() fd's by default.
-	if e = tmpFile.Close(); e != nil {
+	if e = writer.Close(); e != nil {
 		return totalWritten, probe.NewError(e)
 	}
 
@@ -586,7 +635,7 @@ func (f *fsClient) Get(_ context.Context, opts GetOptions) (io.ReadCloser, *Clie
 		content.Metadata[metadataKey] = fileAttr
 	}
 
-	return fileData, content, nil
+	return limitReadCloser(fileData, opts.RangeLength), content, nil
 }
 
 // Check if the given error corresponds to ENOTEMPTY for unix
@@ -856,6 +905,7 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *ClientContent)
 			st, e := os.Stat(file)
 			if e != nil {
 				// Ignore any errors on symlink
+				warnSkippedSymlink(file, e)
 				continue
 			}
 			if strings.HasPrefix(file, prefix) {
@@ -924,6 +974,7 @@ func (f *fsClient) listInRoutine(contentCh chan<- *ClientContent) {
 				fi, e = os.Stat(fp)
 				if e != nil {
 					// Ignore all errors on symlinks
+					warnSkippedSymlink(fp, e)
 					continue
 				}
 			}
@@ -1104,6 +1155,7 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *ClientContent) {
 			fi, e = os.Stat(fp)
 			if e != nil {
 				// Ignore any errors for symlink
+				warnSkippedSymlink(fp, e)
 				return nil
 			}
 		}
@@ -1291,8 +1343,9 @@ func (f *fsClient) Stat(_ context.Context, opts StatOptions) (content *ClientCon
 	content.Size = st.Size()
 	content.Time = st.ModTime()
 	content.Type = st.Mode()
+	content.ContentType = guessURLContentType(f.PathURL.Path)
 	content.Metadata = map[string]string{
-		"Content-Type": guessURLContentType(f.PathURL.Path),
+		"Content-Type": content.ContentType,
 	}
 
 	path := f.PathURL.String()
@@ -84,3 +84,6 @@ func getAllXattrs(path string) (map[string]string, error) {
 	}
 	return xMetadata, nil
 }
+
+// Extended attributes are preserved with -a, see `mc --version`.
+const buildWithXattr = true
@@ -56,3 +56,6 @@ func IsDeleteEvent(event notify.Event) bool {
 func getAllXattrs(path string) (map[string]string, error) {
 	return nil, nil
 }
+
+// Extended attributes are not supported, see `mc --version`.
+const buildWithXattr = false
@@ -329,6 +329,12 @@ func (s *TestSuite) TestGetRange(c *checkv1.C) {
 	_, e = results.Write(buf)
 	c.Assert(e, checkv1.IsNil)
 	c.Assert([]byte("hello"), checkv1.DeepEquals, results.Bytes())
+
+	reader, _, err = fsClient.Get(context.Background(), GetOptions{RangeStart: 6, RangeLength: 3})
+	c.Assert(err, checkv1.IsNil)
+	got, e := io.ReadAll(reader)
+	c.Assert(e, checkv1.IsNil)
+	c.Assert(string(got), checkv1.Equals, "wor")
 }
 
 // Test stat file.
@@ -382,3 +388,23 @@ func (s *TestSuite) TestCopy(c *checkv1.C) {
 	err = fsClientTarget.Copy(context.Background(), sourcePath, CopyOptions{size: int64(len(data))}, nil)
 	c.Assert(err, checkv1.IsNil)
 }
+
+// Test prefix and suffix filtering of filesystem events.
+func (s *TestSuite) TestMatchWatchPath(c *checkv1.C) {
+	root := filepath.Join(os.TempDir(), "watch")
+	testCases := []struct {
+		path           string
+		prefix, suffix string
+		match          bool
+	}{
+		{filepath.Join(root, "a.log"), "", "", true},
+		{filepath.Join(root, "a.log"), "", ".log", true},
+		{filepath.Join(root, "a.txt"), "", ".log", false},
+		{filepath.Join(root, "nginx", "a.log"), "nginx/", ".log", true},
+		{filepath.Join(root, "apache", "a.log"), "nginx/", ".log", false},
+		{filepath.Join(root, "nginx", "a.txt"), "nginx/", ".log", false},
+	}
+	for _, testCase := range testCases {
+		c.Assert(matchWatchPath(root, testCase.path, testCase.prefix, testCase.suffix), checkv1.Equals, testCase.match)
+	}
+}
@@ -0,0 +1,1145 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/minio/mc/pkg/hookreader"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/minio/minio-go/v7/pkg/replication"
+)
+
+const (
+	// API value of aliases using the Google Cloud Storage JSON API, the
+	// access key is the project ID and the secret key the path of a
+	// service account key file.
+	gcsAPI = "gcs"
+
+	// Google Cloud Storage URLs of the form gs://BUCKET/OBJECT use the
+	// first alias configured with the gcs API.
+	gcsURLScheme = "gs://"
+
+	gcsScope = "https://www.googleapis.com/auth/devstorage.full_control"
+
+	// Objects up to gcsMaxSimpleUploadSize are uploaded with a single
+	// request, larger or unknown sizes with a resumable upload.
+	gcsMaxSimpleUploadSize = 8 * humanize.MiByte
+	// Resumable upload chunks must be a multiple of 256KiB.
+	gcsChunkAlign     = 256 * humanize.KiByte
+	gcsChunkSize      = 16 * humanize.MiByte
+	gcsChunkRetries   = 3
+	gcsStatusResumeOK = 308
+)
+
+// gcsClient - Google Cloud Storage client using the JSON API.
+type gcsClient struct {
+	targetURL  *ClientURL
+	project    string
+	keyFile    string
+	httpClient *http.Client
+	userAgent  string
+}
+
+// gcsServiceAccount - the fields of a service account key file used to
+// obtain access tokens.
+type gcsServiceAccount struct {
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// gcsToken - a cached access token.
+type gcsToken struct {
+	value  string
+	expiry time.Time
+}
+
+// Access tokens by key file, shared by all clients since a client is
+// created for every object copied.
+var (
+	gcsTokensMu sync.Mutex
+	gcsTokens   = map[string]gcsToken{}
+)
+
+// gcsNew - instantiates a new Google Cloud Storage client.
+func gcsNew(config *Config) (Client, *probe.Error) {
+	if config.SecretKey == "" {
+		return nil, probe.NewError(errors.New("a service account key file is required for Google Cloud Storage"))
+	}
+	return &gcsClient{
+		targetURL: newClientURL(config.HostURL),
+		project:   config.AccessKey,
+		keyFile:   config.SecretKey,
+		httpClient: &http.Client{
+			Transport: getTransportForConfig(config, false),
+			// 308 is the status of incomplete resumable uploads, not a redirect.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		userAgent: config.AppName + "/" + config.AppVersion,
+	}, nil
+}
+
+// expandGCSURL - expands gs://BUCKET/OBJECT using the first alias, in
+// lexical order, configured with the gcs API.
+func expandGCSURL(gcsURL string) (alias, urlStr string, aliasCfg *aliasConfigV10, err *probe.Error) {
+	path := strings.TrimPrefix(gcsURL, gcsURLScheme)
+	config, err := loadMcConfig()
+	if err != nil {
+		return "", "", nil, err.Trace(gcsURL)
+	}
+	aliases := make([]string, 0, len(config.Aliases))
+	for alias := range config.Aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		cfg := config.Aliases[alias]
+		if strings.EqualFold(cfg.API, gcsAPI) {
+			return alias, urlJoinPath(cfg.URL, path), &cfg, nil
+		}
+	}
+	return "", "", nil, probe.NewError(fmt.Errorf("no alias with `--api %s` is configured for `%s`", gcsAPI, gcsURL))
+}
+
+// accessToken - returns a valid access token of the service account,
+// requesting a new one shortly before the cached one expires.
+func (c *gcsClient) accessToken(ctx context.Context) (string, *probe.Error) {
+	gcsTokensMu.Lock()
+	defer gcsTokensMu.Unlock()
+	if token, ok := gcsTokens[c.keyFile]; ok && time.Until(token.expiry) > time.Minute {
+		return token.value, nil
+	}
+
+	data, e := os.ReadFile(c.keyFile)
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	var account gcsServiceAccount
+	if e = json.Unmarshal(data, &account); e != nil {
+		return "", probe.NewError(fmt.Errorf("invalid service account key file `%s`: %w", c.keyFile, e))
+	}
+	key, e := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if e != nil {
+		return "", probe.NewError(fmt.Errorf("invalid private key in `%s`: %w", c.keyFile, e))
+	}
+	now := UTCNow()
+	assertion := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   account.ClientEmail,
+		"scope": gcsScope,
+		"aud":   account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	assertion.Header["kid"] = account.PrivateKeyID
+	signed, e := assertion.SignedString(key)
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {signed},
+	}
+	req, e := http.NewRequestWithContext(ctx, http.MethodPost, account.TokenURI, strings.NewReader(form.Encode()))
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, e := c.httpClient.Do(req)
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	defer resp.Body.Close()
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}
+	if e = json.NewDecoder(resp.Body).Decode(&tokenResp); e != nil {
+		return "", probe.NewError(e)
+	}
+	if resp.StatusCode != http.StatusOK || tokenResp.AccessToken == "" {
+		return "", probe.NewError(fmt.Errorf("unable to obtain an access token for `%s`: %s %s",
+			account.ClientEmail, tokenResp.Error, tokenResp.Description))
+	}
+	gcsTokens[c.keyFile] = gcsToken{
+		value:  tokenResp.AccessToken,
+		expiry: now.Add(time.Duration(tokenResp.ExpiresIn) * time.Second),
+	}
+	return tokenResp.AccessToken, nil
+}
+
+// gcsErrorResponse - error returned by the JSON API.
+type gcsErrorResponse struct {
+	Err struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Errors  []struct {
+			Reason string `json:"reason"`
+		} `json:"errors"`
+	} `json:"error"`
+}
+
+func (e gcsErrorResponse) Error() string {
+	if e.Err.Message == "" {
+		return fmt.Sprintf("%d %s", e.Err.Code, http.StatusText(e.Err.Code))
+	}
+	return e.Err.Message
+}
+
+// gcsObject - an object resource.
+type gcsObject struct {
+	Bucket             string            `json:"bucket,omitempty"`
+	Name               string            `json:"name,omitempty"`
+	Size               string            `json:"size,omitempty"`
+	Updated            string            `json:"updated,omitempty"`
+	Etag               string            `json:"etag,omitempty"`
+	MD5Hash            string            `json:"md5Hash,omitempty"`
+	StorageClass       string            `json:"storageClass,omitempty"`
+	ContentType        string            `json:"contentType,omitempty"`
+	ContentEncoding    string            `json:"contentEncoding,omitempty"`
+	ContentDisposition string            `json:"contentDisposition,omitempty"`
+	ContentLanguage    string            `json:"contentLanguage,omitempty"`
+	CacheControl       string            `json:"cacheControl,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+}
+
+// gcsObjectList - response of the objects list method.
+type gcsObjectList struct {
+	Items         []gcsObject `json:"items"`
+	Prefixes      []string    `json:"prefixes"`
+	NextPageToken string      `json:"nextPageToken"`
+}
+
+// gcsBucket - a bucket resource.
+type gcsBucket struct {
+	Name         string `json:"name"`
+	TimeCreated  string `json:"timeCreated,omitempty"`
+	StorageClass string `json:"storageClass,omitempty"`
+	Location     string `json:"location,omitempty"`
+}
+
+// gcsBucketList - response of the buckets list method.
+type gcsBucketList struct {
+	Items         []gcsBucket `json:"items"`
+	NextPageToken string      `json:"nextPageToken"`
+}
+
+// gcsRewriteResponse - response of the objects rewrite method.
+type gcsRewriteResponse struct {
+	Done         bool   `json:"done"`
+	RewriteToken string `json:"rewriteToken"`
+}
+
+// apiURL - returns the URL of the JSON API path, bucket and object
+// names must already be escaped.
+func (c *gcsClient) apiURL(path string, query url.Values) string {
+	u := c.targetURL.Scheme + "://" + c.targetURL.Host + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// gcsObjectPath - returns the escaped JSON API path of an object, prefix
+// is "/storage/v1" or "/upload/storage/v1".
+func gcsObjectPath(prefix, bucket, object string) string {
+	p := prefix + "/b/" + url.PathEscape(bucket) + "/o"
+	if object != "" {
+		p += "/" + url.PathEscape(object)
+	}
+	return p
+}
+
+// request - sends an authorized request to rawURL.
+func (c *gcsClient) request(ctx context.Context, method, rawURL, bucket, object string, header http.Header, body io.Reader, size int64) (*http.Response, *probe.Error) {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, e := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, e := c.httpClient.Do(req)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		var errResp gcsErrorResponse
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*humanize.KiByte))
+		json.Unmarshal(data, &errResp)
+		errResp.Err.Code = resp.StatusCode
+		return nil, c.toError(bucket, object, errResp)
+	}
+	return resp, nil
+}
+
+// requestJSON - sends an authorized request and decodes the JSON response into v.
+func (c *gcsClient) requestJSON(ctx context.Context, method, rawURL, bucket, object string, in, v interface{}) *probe.Error {
+	var (
+		body   io.Reader
+		size   int64
+		header http.Header
+	)
+	if in != nil {
+		data, e := json.Marshal(in)
+		if e != nil {
+			return probe.NewError(e)
+		}
+		body, size = bytes.NewReader(data), int64(len(data))
+		header = http.Header{"Content-Type": {"application/json"}}
+	}
+	resp, err := c.request(ctx, method, rawURL, bucket, object, header, body, size)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if v == nil {
+		return nil
+	}
+	if e := json.NewDecoder(resp.Body).Decode(v); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// toError - converts JSON API errors to errors understood by the commands.
+func (c *gcsClient) toError(bucket, object string, errResp gcsErrorResponse) *probe.Error {
+	switch errResp.Err.Code {
+	case http.StatusNotFound:
+		if object == "" || strings.Contains(strings.ToLower(errResp.Err.Message), "bucket") {
+			return probe.NewError(BucketDoesNotExist{Bucket: bucket})
+		}
+		return probe.NewError(ObjectMissing{})
+	case http.StatusConflict:
+		if object == "" {
+			return probe.NewError(BucketExists{Bucket: bucket})
+		}
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return probe.NewError(PathInsufficientPermission{Path: c.targetURL.String()})
+	}
+	return probe.NewError(errResp)
+}
+
+// contentURL - returns the URL of an entry of the bucket.
+func (c *gcsClient) contentURL(bucket, name string) ClientURL {
+	u := c.targetURL.Clone()
+	u.Path = "/" + bucket
+	if name != "" {
+		u.Path += "/" + name
+	}
+	return u
+}
+
+// parseGCSTime - parses the RFC3339 dates returned by the JSON API.
+func parseGCSTime(s string) time.Time {
+	t, e := time.Parse(time.RFC3339Nano, s)
+	if e != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func (c *gcsClient) object2ClientContent(bucket string, o gcsObject) *ClientContent {
+	content := &ClientContent{
+		URL:          c.contentURL(bucket, o.Name),
+		BucketName:   bucket,
+		Time:         parseGCSTime(o.Updated),
+		ETag:         strings.Trim(o.Etag, "\""),
+		StorageClass: o.StorageClass,
+		ContentType:  o.ContentType,
+		Type:         os.FileMode(0o664),
+		Metadata:     map[string]string{},
+		UserMetadata: map[string]string{},
+	}
+	content.Size, _ = strconv.ParseInt(o.Size, 10, 64)
+	for k, v := range map[string]string{
+		"Content-Type":        o.ContentType,
+		"Content-Encoding":    o.ContentEncoding,
+		"Content-Disposition": o.ContentDisposition,
+		"Content-Language":    o.ContentLanguage,
+		"Cache-Control":       o.CacheControl,
+	} {
+		if v != "" {
+			content.Metadata[k] = v
+		}
+	}
+	for k, v := range o.Metadata {
+		content.UserMetadata[k] = v
+	}
+	return content
+}
+
+func (c *gcsClient) prefix2ClientContent(bucket, prefix string) *ClientContent {
+	return &ClientContent{
+		URL:        c.contentURL(bucket, prefix),
+		BucketName: bucket,
+		Time:       time.Now(),
+		Type:       os.ModeDir,
+	}
+}
+
+func (c *gcsClient) bucket2ClientContent(b gcsBucket) *ClientContent {
+	return &ClientContent{
+		URL:          c.contentURL(b.Name, ""),
+		BucketName:   b.Name,
+		Time:         parseGCSTime(b.TimeCreated),
+		StorageClass: b.StorageClass,
+		Type:         os.ModeDir,
+	}
+}
+
+// listBuckets - returns all buckets of the project.
+func (c *gcsClient) listBuckets(ctx context.Context) ([]*ClientContent, *probe.Error) {
+	var contents []*ClientContent
+	pageToken := ""
+	for {
+		query := url.Values{"project": {c.project}}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		var list gcsBucketList
+		if err := c.requestJSON(ctx, http.MethodGet, c.apiURL("/storage/v1/b", query), "", "", nil, &list); err != nil {
+			return nil, err
+		}
+		for _, b := range list.Items {
+			contents = append(contents, c.bucket2ClientContent(b))
+		}
+		if pageToken = list.NextPageToken; pageToken == "" {
+			return contents, nil
+		}
+	}
+}
+
+// listObjects - sends the objects (and folders unless recursive) found
+// under prefix in lexical order, following the page tokens of the
+// listing. Only the first page is listed when maxResults is positive.
+func (c *gcsClient) listObjects(ctx context.Context, bucket, prefix string, recursive bool, maxResults int, contentCh chan<- *ClientContent) bool {
+	pageToken := ""
+	for {
+		query := url.Values{}
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if !recursive {
+			query.Set("delimiter", "/")
+		}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		if maxResults > 0 {
+			query.Set("maxResults", strconv.Itoa(maxResults))
+		}
+		var list gcsObjectList
+		rawURL := c.apiURL(gcsObjectPath("/storage/v1", bucket, ""), query)
+		if err := c.requestJSON(ctx, http.MethodGet, rawURL, bucket, "", nil, &list); err != nil {
+			return sendGCSContent(ctx, contentCh, &ClientContent{Err: err.Trace(bucket, prefix)})
+		}
+
+		contents := make([]*ClientContent, 0, len(list.Items)+len(list.Prefixes))
+		for _, o := range list.Items {
+			if o.Name == prefix && strings.HasSuffix(o.Name, "/") && !recursive {
+				// Placeholder object of the listed folder.
+				continue
+			}
+			contents = append(contents, c.object2ClientContent(bucket, o))
+		}
+		for _, p := range list.Prefixes {
+			contents = append(contents, c.prefix2ClientContent(bucket, p))
+		}
+		sort.Slice(contents, func(i, j int) bool {
+			return contents[i].URL.Path < contents[j].URL.Path
+		})
+		for _, content := range contents {
+			if !sendGCSContent(ctx, contentCh, content) {
+				return false
+			}
+		}
+		if pageToken = list.NextPageToken; pageToken == "" || maxResults > 0 {
+			return true
+		}
+	}
+}
+
+// sendGCSContent - sends content unless ctx is canceled, returns false
+// when the listing must stop.
+func sendGCSContent(ctx context.Context, contentCh chan<- *ClientContent, content *ClientContent) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case contentCh <- content:
+		return content.Err == nil
+	}
+}
+
+// url2BucketAndObject - returns the bucket and the object name of the client URL.
+func (c *gcsClient) url2BucketAndObject() (bucket, object string) {
+	return url2BucketAndObject(c.targetURL)
+}
+
+// Stat - returns the properties of an object, folder or bucket.
+func (c *gcsClient) Stat(ctx context.Context, opts StatOptions) (*ClientContent, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		u := c.targetURL.Clone()
+		u.Path = string(c.targetURL.Separator)
+		return &ClientContent{URL: u, Type: os.ModeDir}, nil
+	}
+	if object == "" {
+		var b gcsBucket
+		rawURL := c.apiURL("/storage/v1/b/"+url.PathEscape(bucket), nil)
+		if err := c.requestJSON(ctx, http.MethodGet, rawURL, bucket, "", nil, &b); err != nil {
+			return nil, err.Trace(bucket)
+		}
+		return c.bucket2ClientContent(b), nil
+	}
+	if opts.versionID != "" || opts.sse != nil {
+		return nil, c.notImplemented("Stat with version or encryption")
+	}
+
+	if !strings.HasSuffix(object, "/") {
+		var o gcsObject
+		err := c.requestJSON(ctx, http.MethodGet, c.apiURL(gcsObjectPath("/storage/v1", bucket, object), nil), bucket, object, nil, &o)
+		if err == nil {
+			return c.object2ClientContent(bucket, o), nil
+		}
+		if !errors.As(err.ToGoError(), &ObjectMissing{}) {
+			return nil, err.Trace(bucket, object)
+		}
+		object += "/"
+	}
+
+	// Look for a folder.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		c.listObjects(ctx, bucket, object, true, 1, contentCh)
+	}()
+	for content := range contentCh {
+		if content.Err != nil {
+			return nil, content.Err
+		}
+		return c.prefix2ClientContent(bucket, object), nil
+	}
+	return nil, probe.NewError(ObjectMissing{})
+}
+
+// List - lists buckets, objects and folders.
+func (c *gcsClient) List(ctx context.Context, opts ListOptions) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		bucket, object := c.url2BucketAndObject()
+		switch {
+		case bucket == "":
+			buckets, err := c.listBuckets(ctx)
+			if err != nil {
+				sendGCSContent(ctx, contentCh, &ClientContent{Err: err})
+				return
+			}
+			for _, content := range buckets {
+				if !opts.Recursive {
+					if !sendGCSContent(ctx, contentCh, content) {
+						return
+					}
+					continue
+				}
+				if opts.ShowDir == DirFirst && !sendGCSContent(ctx, contentCh, content) {
+					return
+				}
+				if !c.listObjects(ctx, content.BucketName, "", true, 0, contentCh) {
+					return
+				}
+				if opts.ShowDir == DirLast && !sendGCSContent(ctx, contentCh, content) {
+					return
+				}
+			}
+		case object == "" && !opts.Recursive && !strings.HasSuffix(c.targetURL.Path, string(c.targetURL.Separator)):
+			content, err := c.Stat(ctx, StatOptions{})
+			if err != nil {
+				sendGCSContent(ctx, contentCh, &ClientContent{Err: err.Trace(bucket)})
+				return
+			}
+			sendGCSContent(ctx, contentCh, content)
+		default:
+			c.listObjects(ctx, bucket, object, opts.Recursive, 0, contentCh)
+		}
+	}()
+	return contentCh
+}
+
+// MakeBucket - creates a bucket in the project, region is its location.
+func (c *gcsClient) MakeBucket(ctx context.Context, region string, ignoreExisting, withLock bool) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	if withLock {
+		return c.notImplemented("MakeBucket with object lock")
+	}
+	if object != "" {
+		// Folders are virtual, nothing to create.
+		return nil
+	}
+	in := gcsBucket{Name: bucket}
+	if region != "" && region != "us-east-1" {
+		// us-east-1 is the S3 default, let the project default apply.
+		in.Location = region
+	}
+	err := c.requestJSON(ctx, http.MethodPost, c.apiURL("/storage/v1/b", url.Values{"project": {c.project}}), bucket, "", in, nil)
+	if err != nil {
+		if ignoreExisting && errors.As(err.ToGoError(), &BucketExists{}) {
+			return nil
+		}
+		return err.Trace(bucket)
+	}
+	return nil
+}
+
+// RemoveBucket - removes an empty bucket.
+func (c *gcsClient) RemoveBucket(ctx context.Context, _ bool) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	if object != "" {
+		return probe.NewError(BucketInvalid{Bucket: bucket + "/" + object})
+	}
+	return c.requestJSON(ctx, http.MethodDelete, c.apiURL("/storage/v1/b/"+url.PathEscape(bucket), nil), bucket, "", nil, nil)
+}
+
+// ListBuckets - lists the buckets of the project.
+func (c *gcsClient) ListBuckets(ctx context.Context) ([]*ClientContent, *probe.Error) {
+	return c.listBuckets(ctx)
+}
+
+// Get - returns the object data of the range of opts.
+func (c *gcsClient) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *ClientContent, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if opts.SSE != nil || opts.Zip || opts.VersionID != "" {
+		return nil, nil, c.notImplemented("Get with encryption, zip or version")
+	}
+	content, err := c.Stat(ctx, StatOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if content.Type.IsDir() {
+		return nil, nil, probe.NewError(ObjectMissing{})
+	}
+	header := http.Header{}
+	if opts.isRange() {
+		header.Set("Range", opts.rangeHeader())
+	}
+	rawURL := c.apiURL(gcsObjectPath("/storage/v1", bucket, object), url.Values{"alt": {"media"}})
+	resp, err := c.request(ctx, http.MethodGet, rawURL, bucket, object, header, nil, 0)
+	if err != nil {
+		return nil, nil, err.Trace(bucket, object)
+	}
+	return resp.Body, content, nil
+}
+
+// gcsObjectResource - maps object metadata to an object resource.
+func gcsObjectResource(name string, metadata map[string]string, storageClass string) gcsObject {
+	o := gcsObject{Name: name, StorageClass: storageClass}
+	for k, v := range metadata {
+		switch ck := http.CanonicalHeaderKey(k); ck {
+		case "Content-Type":
+			o.ContentType = v
+		case "Content-Encoding":
+			o.ContentEncoding = v
+		case "Content-Disposition":
+			o.ContentDisposition = v
+		case "Content-Language":
+			o.ContentLanguage = v
+		case "Cache-Control":
+			o.CacheControl = v
+		default:
+			if strings.HasPrefix(ck, "X-Amz-") && !strings.HasPrefix(ck, "X-Amz-Meta-") {
+				// S3 specific headers such as object lock settings.
+				continue
+			}
+			if o.Metadata == nil {
+				o.Metadata = map[string]string{}
+			}
+			o.Metadata[strings.TrimPrefix(ck, "X-Amz-Meta-")] = v
+		}
+	}
+	return o
+}
+
+// Put - uploads an object, objects larger than gcsMaxSimpleUploadSize or
+// of unknown size are uploaded with a resumable upload.
+func (c *gcsClient) Put(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return 0, probe.NewError(BucketNameEmpty{})
+	}
+	if object == "" {
+		return 0, probe.NewError(ObjectNameEmpty{})
+	}
+	if opts.sse != nil {
+		return 0, c.notImplemented("Put with encryption")
+	}
+	reader = hookreader.NewHook(reader, progress)
+	resource := gcsObjectResource(object, opts.metadata, opts.storageClass)
+	if size >= 0 && size <= gcsMaxSimpleUploadSize {
+		return c.multipartUpload(ctx, bucket, object, resource, reader, size)
+	}
+	chunkSize := int64(gcsChunkSize)
+	if opts.multipartSize > 0 {
+		chunkSize = int64(opts.multipartSize)
+	}
+	if chunkSize%gcsChunkAlign != 0 {
+		chunkSize += gcsChunkAlign - chunkSize%gcsChunkAlign
+	}
+	return c.resumableUpload(ctx, bucket, object, resource, reader, size, chunkSize)
+}
+
+// multipartUpload - uploads the object resource and data with a single request.
+func (c *gcsClient) multipartUpload(ctx context.Context, bucket, object string, resource gcsObject, reader io.Reader, size int64) (int64, *probe.Error) {
+	data, e := io.ReadAll(io.LimitReader(reader, size))
+	if e != nil {
+		return 0, probe.NewError(e)
+	}
+	if int64(len(data)) != size {
+		return 0, probe.NewError(io.ErrUnexpectedEOF)
+	}
+	meta, e := json.Marshal(resource)
+	if e != nil {
+		return 0, probe.NewError(e)
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, _ := w.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json; charset=UTF-8"}})
+	part.Write(meta)
+	contentType := resource.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	part, _ = w.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+	part.Write(data)
+	w.Close()
+
+	rawURL := c.apiURL(gcsObjectPath("/upload/storage/v1", bucket, ""), url.Values{"uploadType": {"multipart"}})
+	header := http.Header{"Content-Type": {"multipart/related; boundary=" + w.Boundary()}}
+	resp, err := c.request(ctx, http.MethodPost, rawURL, bucket, object, header, bytes.NewReader(body.Bytes()), int64(body.Len()))
+	if err != nil {
+		return 0, err.Trace(bucket, object)
+	}
+	resp.Body.Close()
+	return size, nil
+}
+
+// resumableUpload - uploads the object in chunks to a resumable upload
+// session, a chunk failing is resent from the offset the service has
+// persisted, up to gcsChunkRetries times.
+func (c *gcsClient) resumableUpload(ctx context.Context, bucket, object string, resource gcsObject, reader io.Reader, size, chunkSize int64) (int64, *probe.Error) {
+	meta, e := json.Marshal(resource)
+	if e != nil {
+		return 0, probe.NewError(e)
+	}
+	header := http.Header{"Content-Type": {"application/json; charset=UTF-8"}}
+	if resource.ContentType != "" {
+		header.Set("X-Upload-Content-Type", resource.ContentType)
+	}
+	if size >= 0 {
+		header.Set("X-Upload-Content-Length", strconv.FormatInt(size, 10))
+	}
+	rawURL := c.apiURL(gcsObjectPath("/upload/storage/v1", bucket, ""), url.Values{"uploadType": {"resumable"}})
+	resp, err := c.request(ctx, http.MethodPost, rawURL, bucket, object, header, bytes.NewReader(meta), int64(len(meta)))
+	if err != nil {
+		return 0, err.Trace(bucket, object)
+	}
+	resp.Body.Close()
+	session := resp.Header.Get("Location")
+	if session == "" {
+		return 0, probe.NewError(errors.New("no resumable upload session returned"))
+	}
+
+	buf := make([]byte, chunkSize)
+	var offset int64
+	for {
+		n, e := io.ReadFull(reader, buf)
+		if e != nil && e != io.EOF && e != io.ErrUnexpectedEOF {
+			return offset, probe.NewError(e)
+		}
+		total := "*"
+		if n < len(buf) {
+			// Short read, this is the last chunk.
+			total = strconv.FormatInt(offset+int64(n), 10)
+		}
+		if size >= 0 && offset+int64(n) == size {
+			total = strconv.FormatInt(size, 10)
+		}
+		done, err := c.uploadChunk(ctx, session, bucket, object, buf[:n], offset, total)
+		if err != nil {
+			return offset, err.Trace(bucket, object)
+		}
+		offset += int64(n)
+		if done {
+			return offset, nil
+		}
+		if total != "*" {
+			return offset, probe.NewError(errors.New("resumable upload not finalized by the service"))
+		}
+	}
+}
+
+// uploadChunk - sends the chunk at offset of the upload session, total is
+// the object size or "*" when unknown yet. Returns true once the object
+// is complete.
+func (c *gcsClient) uploadChunk(ctx context.Context, session, bucket, object string, chunk []byte, offset int64, total string) (done bool, err *probe.Error) {
+	sent := int64(0)
+	for attempt := 0; ; attempt++ {
+		contentRange := "bytes */" + total
+		if remaining := chunk[sent:]; len(remaining) > 0 {
+			contentRange = fmt.Sprintf("bytes %d-%d/%s", offset+sent, offset+int64(len(chunk))-1, total)
+		}
+		header := http.Header{"Content-Range": {contentRange}}
+		var resp *http.Response
+		resp, err = c.request(ctx, http.MethodPut, session, bucket, object, header, bytes.NewReader(chunk[sent:]), int64(len(chunk))-sent)
+		if err == nil {
+			resp.Body.Close()
+			return resp.StatusCode != gcsStatusResumeOK, nil
+		}
+		if attempt >= gcsChunkRetries || ctx.Err() != nil {
+			return false, err
+		}
+		// Ask how much of the upload was persisted and send the rest.
+		persisted, qerr := c.uploadedSize(ctx, session, bucket, object)
+		if qerr != nil {
+			return false, err
+		}
+		if persisted < offset || persisted > offset+int64(len(chunk)) {
+			return false, err
+		}
+		sent = persisted - offset
+	}
+}
+
+// uploadedSize - returns the number of bytes persisted by the upload session.
+func (c *gcsClient) uploadedSize(ctx context.Context, session, bucket, object string) (int64, *probe.Error) {
+	header := http.Header{"Content-Range": {"bytes */*"}}
+	resp, err := c.request(ctx, http.MethodPut, session, bucket, object, header, http.NoBody, 0)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	// Range is of the form bytes=0-N, absent when nothing is persisted.
+	r := resp.Header.Get("Range")
+	if r == "" {
+		return 0, nil
+	}
+	i := strings.LastIndex(r, "-")
+	last, e := strconv.ParseInt(r[i+1:], 10, 64)
+	if e != nil {
+		return 0, probe.NewError(e)
+	}
+	return last + 1, nil
+}
+
+// Copy - copies an object of the same alias server side with the rewrite
+// method, source is the /BUCKET/OBJECT path of the source object.
+func (c *gcsClient) Copy(ctx context.Context, source string, opts CopyOptions, progress io.Reader) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if opts.srcSSE != nil || opts.tgtSSE != nil || opts.versionID != "" {
+		return c.notImplemented("Copy with encryption or version")
+	}
+	tokens := splitStr(strings.TrimPrefix(source, "/"), "/", 2)
+	srcBucket, srcObject := tokens[0], tokens[1]
+
+	// Without a resource the metadata of the source object is kept.
+	var in interface{}
+	if opts.isPreserve || len(opts.metadata) > 0 || opts.storageClass != "" {
+		in = gcsObjectResource("", opts.metadata, opts.storageClass)
+	}
+	rewritePath := gcsObjectPath("/storage/v1", srcBucket, srcObject) + "/rewriteTo" + gcsObjectPath("", bucket, object)
+	rewriteToken := ""
+	for {
+		query := url.Values{}
+		if rewriteToken != "" {
+			query.Set("rewriteToken", rewriteToken)
+		}
+		var resp gcsRewriteResponse
+		if err := c.requestJSON(ctx, http.MethodPost, c.apiURL(rewritePath, query), bucket, object, in, &resp); err != nil {
+			return err.Trace(source, bucket, object)
+		}
+		if resp.Done {
+			break
+		}
+		if resp.RewriteToken == "" {
+			return probe.NewError(errors.New("rewrite not completed and no rewrite token returned")).Trace(source, bucket, object)
+		}
+		rewriteToken = resp.RewriteToken
+	}
+	if progress != nil && opts.size > 0 {
+		if _, e := io.CopyN(io.Discard, progress, opts.size); e != nil {
+			return probe.NewError(e)
+		}
+	}
+	return nil
+}
+
+// Remove - removes objects, and buckets when isRemoveBucket is set.
+func (c *gcsClient) Remove(ctx context.Context, isIncomplete, isRemoveBucket, _, _ bool, contentCh <-chan *ClientContent) <-chan RemoveResult {
+	resultCh := make(chan RemoveResult)
+	go func() {
+		defer close(resultCh)
+		if isIncomplete {
+			for range contentCh {
+				// Abandoned resumable uploads expire after a week.
+			}
+			return
+		}
+		for content := range contentCh {
+			bucket, object := url2BucketAndObject(&content.URL)
+			var err *probe.Error
+			switch {
+			case object == "" && isRemoveBucket:
+				err = c.requestJSON(ctx, http.MethodDelete, c.apiURL("/storage/v1/b/"+url.PathEscape(bucket), nil), bucket, "", nil, nil)
+			case object == "":
+				continue
+			case strings.HasSuffix(object, "/"):
+				// Folders are virtual unless a placeholder object exists.
+				err = c.requestJSON(ctx, http.MethodDelete, c.apiURL(gcsObjectPath("/storage/v1", bucket, object), nil), bucket, object, nil, nil)
+				if errors.As(err.ToGoError(), &ObjectMissing{}) {
+					continue
+				}
+			default:
+				err = c.requestJSON(ctx, http.MethodDelete, c.apiURL(gcsObjectPath("/storage/v1", bucket, object), nil), bucket, object, nil, nil)
+			}
+			result := RemoveResult{BucketName: bucket, Err: err}
+			result.ObjectName = object
+			select {
+			case <-ctx.Done():
+				return
+			case resultCh <- result:
+			}
+		}
+	}()
+	return resultCh
+}
+
+// GetURL - returns the client URL.
+func (c *gcsClient) GetURL() ClientURL {
+	return c.targetURL.Clone()
+}
+
+// AddUserAgent - sets the application name and version sent with every request.
+func (c *gcsClient) AddUserAgent(app, version string) {
+	c.userAgent = app + "/" + version
+}
+
+// GetBucketInfo - returns the bucket information.
+func (c *gcsClient) GetBucketInfo(ctx context.Context) (BucketInfo, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return BucketInfo{}, probe.NewError(BucketNameEmpty{})
+	}
+	if object != "" {
+		return BucketInfo{}, probe.NewError(InvalidArgument{})
+	}
+	var b gcsBucket
+	if err := c.requestJSON(ctx, http.MethodGet, c.apiURL("/storage/v1/b/"+url.PathEscape(bucket), nil), bucket, "", nil, &b); err != nil {
+		return BucketInfo{}, err.Trace(bucket)
+	}
+	content := c.bucket2ClientContent(b)
+	return BucketInfo{
+		URL:      content.URL,
+		Key:      bucket,
+		Date:     content.Time,
+		Type:     content.Type,
+		Location: b.Location,
+	}, nil
+}
+
+func (c *gcsClient) notImplemented(api string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     api,
+		APIType: "Google Cloud Storage",
+	})
+}
+
+// The following operations are not mapped to the JSON API.
+
+func (c *gcsClient) SetObjectLockConfig(context.Context, minio.RetentionMode, uint64, minio.ValidityUnit) *probe.Error {
+	return c.notImplemented("SetObjectLockConfig")
+}
+
+func (c *gcsClient) GetObjectLockConfig(context.Context) (string, minio.RetentionMode, uint64, minio.ValidityUnit, *probe.Error) {
+	return "", "", 0, "", c.notImplemented("GetObjectLockConfig")
+}
+
+func (c *gcsClient) GetAccess(context.Context) (string, string, *probe.Error) {
+	return "", "", c.notImplemented("GetAccess")
+}
+
+func (c *gcsClient) GetAccessRules(context.Context) (map[string]string, *probe.Error) {
+	return nil, c.notImplemented("GetAccessRules")
+}
+
+func (c *gcsClient) SetAccess(context.Context, string, bool) *probe.Error {
+	return c.notImplemented("SetAccess")
+}
+
+func (c *gcsClient) Select(context.Context, string, encrypt.ServerSide, SelectObjectOpts) (io.ReadCloser, *probe.Error) {
+	return nil, c.notImplemented("Select")
+}
+
+func (c *gcsClient) PutObjectRetention(context.Context, string, minio.RetentionMode, time.Time, bool) *probe.Error {
+	return c.notImplemented("PutObjectRetention")
+}
+
+func (c *gcsClient) GetObjectRetention(context.Context, string) (minio.RetentionMode, time.Time, *probe.Error) {
+	return "", time.Time{}, c.notImplemented("GetObjectRetention")
+}
+
+func (c *gcsClient) PutObjectLegalHold(context.Context, string, minio.LegalHoldStatus) *probe.Error {
+	return c.notImplemented("PutObjectLegalHold")
+}
+
+func (c *gcsClient) GetObjectLegalHold(context.Context, string) (minio.LegalHoldStatus, *probe.Error) {
+	return "", c.notImplemented("GetObjectLegalHold")
+}
+
+func (c *gcsClient) ShareDownload(context.Context, string, time.Duration) (string, *probe.Error) {
+	return "", c.notImplemented("ShareDownload")
+}
+
+func (c *gcsClient) ShareUpload(context.Context, bool, time.Duration, string) (string, map[string]string, *probe.Error) {
+	return "", nil, c.notImplemented("ShareUpload")
+}
+
+func (c *gcsClient) Watch(context.Context, WatchOptions) (*WatchObject, *probe.Error) {
+	return nil, c.notImplemented("Watch")
+}
+
+func (c *gcsClient) GetTags(context.Context, string) (map[string]string, *probe.Error) {
+	return nil, c.notImplemented("GetTags")
+}
+
+func (c *gcsClient) SetTags(context.Context, string, string) *probe.Error {
+	return c.notImplemented("SetTags")
+}
+
+func (c *gcsClient) DeleteTags(context.Context, string) *probe.Error {
+	return c.notImplemented("DeleteTags")
+}
+
+func (c *gcsClient) GetLifecycle(context.Context) (*lifecycle.Configuration, time.Time, *probe.Error) {
+	return nil, time.Time{}, c.notImplemented("GetLifecycle")
+}
+
+func (c *gcsClient) SetLifecycle(context.Context, *lifecycle.Configuration) *probe.Error {
+	return c.notImplemented("SetLifecycle")
+}
+
+func (c *gcsClient) GetVersion(context.Context) (minio.BucketVersioningConfiguration, *probe.Error) {
+	return minio.BucketVersioningConfiguration{}, c.notImplemented("GetVersion")
+}
+
+func (c *gcsClient) SetVersion(context.Context, string, []string, bool) *probe.Error {
+	return c.notImplemented("SetVersion")
+}
+
+func (c *gcsClient) GetReplication(context.Context) (replication.Config, *probe.Error) {
+	return replication.Config{}, c.notImplemented("GetReplication")
+}
+
+func (c *gcsClient) SetReplication(context.Context, *replication.Config, replication.Options) *probe.Error {
+	return c.notImplemented("SetReplication")
+}
+
+func (c *gcsClient) RemoveReplication(context.Context) *probe.Error {
+	return c.notImplemented("RemoveReplication")
+}
+
+func (c *gcsClient) GetReplicationMetrics(context.Context) (replication.MetricsV2, *probe.Error) {
+	return replication.MetricsV2{}, c.notImplemented("GetReplicationMetrics")
+}
+
+func (c *gcsClient) ResetReplication(context.Context, time.Duration, string) (replication.ResyncTargetsInfo, *probe.Error) {
+	return replication.ResyncTargetsInfo{}, c.notImplemented("ResetReplication")
+}
+
+func (c *gcsClient) ReplicationResyncStatus(context.Context, string) (replication.ResyncTargetsInfo, *probe.Error) {
+	return replication.ResyncTargetsInfo{}, c.notImplemented("ReplicationResyncStatus")
+}
+
+func (c *gcsClient) GetEncryption(context.Context) (string, string, *probe.Error) {
+	return "", "", c.notImplemented("GetEncryption")
+}
+
+func (c *gcsClient) SetEncryption(context.Context, string, string) *probe.Error {
+	return c.notImplemented("SetEncryption")
+}
+
+func (c *gcsClient) DeleteEncryption(context.Context) *probe.Error {
+	return c.notImplemented("DeleteEncryption")
+}
+
+func (c *gcsClient) Restore(context.Context, string, int) *probe.Error {
+	return c.notImplemented("Restore")
+}
+
+func (c *gcsClient) GetPart(context.Context, int) (io.ReadCloser, *probe.Error) {
+	return nil, c.notImplemented("GetPart")
+}
+
+func (c *gcsClient) PutPart(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
+	return c.Put(ctx, reader, size, progress, opts)
+}
@@ -0,0 +1,379 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v4"
+	checkv1 "gopkg.in/check.v1"
+)
+
+// gcsHandler is an in memory JSON API of a single project, listings are
+// paginated by two entries and the second chunk of every resumable
+// upload fails once.
+type gcsHandler struct {
+	key *rsa.PrivateKey
+
+	mu       sync.Mutex
+	url      string
+	buckets  map[string]bool
+	objects  map[string]gcsObject // by bucket/name
+	data     map[string][]byte
+	sessions map[string]*gcsTestSession
+}
+
+type gcsTestSession struct {
+	bucket, name string
+	data         []byte
+	failed       bool
+	chunks       int
+}
+
+func (h *gcsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if r.URL.Path == "/token" {
+		r.ParseForm()
+		_, e := jwt.Parse(r.Form.Get("assertion"), func(*jwt.Token) (interface{}, error) {
+			return &h.key.PublicKey, nil
+		})
+		if e != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		io.WriteString(w, `{"access_token":"token","expires_in":3600}`)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := r.URL.EscapedPath()
+	query := r.URL.Query()
+	switch {
+	case strings.HasPrefix(path, "/session/"):
+		h.uploadChunk(w, r, h.sessions[strings.TrimPrefix(path, "/session/")])
+	case strings.HasPrefix(path, "/upload/storage/v1/b/"):
+		bucket, _ := url.PathUnescape(strings.TrimSuffix(strings.TrimPrefix(path, "/upload/storage/v1/b/"), "/o"))
+		h.upload(w, r, bucket, query.Get("uploadType"))
+	case path == "/storage/v1/b" && r.Method == http.MethodPost:
+		var b gcsBucket
+		json.NewDecoder(r.Body).Decode(&b)
+		if h.buckets[b.Name] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		h.buckets[b.Name] = true
+		json.NewEncoder(w).Encode(b)
+	case path == "/storage/v1/b":
+		var list gcsBucketList
+		for name := range h.buckets {
+			list.Items = append(list.Items, gcsBucket{Name: name})
+		}
+		json.NewEncoder(w).Encode(list)
+	default:
+		tokens := strings.Split(strings.TrimPrefix(path, "/storage/v1/b/"), "/")
+		bucket, _ := url.PathUnescape(tokens[0])
+		if !h.buckets[bucket] {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"error":{"code":404,"message":"The specified bucket does not exist."}}`)
+			return
+		}
+		switch {
+		case len(tokens) == 1:
+			json.NewEncoder(w).Encode(gcsBucket{Name: bucket})
+		case len(tokens) == 2:
+			h.list(w, bucket, query)
+		case len(tokens) == 8 && tokens[3] == "rewriteTo":
+			h.rewrite(w, r, bucket, tokens)
+		default:
+			name, _ := url.PathUnescape(tokens[2])
+			o, ok := h.objects[bucket+"/"+name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				io.WriteString(w, `{"error":{"code":404,"message":"No such object."}}`)
+				return
+			}
+			switch {
+			case r.Method == http.MethodDelete:
+				delete(h.objects, bucket+"/"+name)
+				w.WriteHeader(http.StatusNoContent)
+			case query.Get("alt") == "media":
+				data := h.data[bucket+"/"+name]
+				if rng := r.Header.Get("Range"); rng != "" {
+					var start, end int
+					fmt.Sscanf(rng, "bytes=%d-%d", &start, &end)
+					if end > 0 {
+						data = data[:end+1]
+					}
+					data = data[start:]
+					w.WriteHeader(http.StatusPartialContent)
+				}
+				w.Write(data)
+			default:
+				json.NewEncoder(w).Encode(o)
+			}
+		}
+	}
+}
+
+func (h *gcsHandler) store(bucket, name string, o gcsObject, data []byte) gcsObject {
+	o.Bucket, o.Name = bucket, name
+	o.Size = strconv.Itoa(len(data))
+	o.Updated = "2006-01-02T15:04:05.000Z"
+	h.objects[bucket+"/"+name] = o
+	h.data[bucket+"/"+name] = data
+	return o
+}
+
+func (h *gcsHandler) list(w http.ResponseWriter, bucket string, query url.Values) {
+	prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+	var entries []string
+	seen := map[string]bool{}
+	for key := range h.objects {
+		name := strings.TrimPrefix(key, bucket+"/")
+		if !strings.HasPrefix(key, bucket+"/") || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if i := strings.Index(name[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+			name = name[:len(prefix)+i+1] + "\x00"
+		}
+		if !seen[name] {
+			seen[name] = true
+			entries = append(entries, name)
+		}
+	}
+	sort.Strings(entries)
+	start, _ := strconv.Atoi(query.Get("pageToken"))
+	end := start + 2
+	if maxResults, _ := strconv.Atoi(query.Get("maxResults")); maxResults > 0 && start+maxResults < end {
+		end = start + maxResults
+	}
+	var list gcsObjectList
+	if end < len(entries) {
+		list.NextPageToken = strconv.Itoa(end)
+	} else {
+		end = len(entries)
+	}
+	for _, name := range entries[start:end] {
+		if strings.HasSuffix(name, "\x00") {
+			list.Prefixes = append(list.Prefixes, strings.TrimSuffix(name, "\x00"))
+			continue
+		}
+		list.Items = append(list.Items, h.objects[bucket+"/"+name])
+	}
+	json.NewEncoder(w).Encode(list)
+}
+
+func (h *gcsHandler) upload(w http.ResponseWriter, r *http.Request, bucket, uploadType string) {
+	switch uploadType {
+	case "multipart":
+		_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		mr := multipart.NewReader(r.Body, params["boundary"])
+		part, _ := mr.NextPart()
+		var o gcsObject
+		json.NewDecoder(part).Decode(&o)
+		part, _ = mr.NextPart()
+		data, _ := io.ReadAll(part)
+		json.NewEncoder(w).Encode(h.store(bucket, o.Name, o, data))
+	case "resumable":
+		var o gcsObject
+		json.NewDecoder(r.Body).Decode(&o)
+		id := strconv.Itoa(len(h.sessions))
+		h.sessions[id] = &gcsTestSession{bucket: bucket, name: o.Name}
+		w.Header().Set("Location", h.url+"/session/"+id)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (h *gcsHandler) uploadChunk(w http.ResponseWriter, r *http.Request, s *gcsTestSession) {
+	var first, last int
+	var total string
+	contentRange := r.Header.Get("Content-Range")
+	data, _ := io.ReadAll(r.Body)
+	if strings.HasPrefix(contentRange, "bytes */") {
+		total = strings.TrimPrefix(contentRange, "bytes */")
+	} else {
+		fmt.Sscanf(contentRange, "bytes %d-%d/%s", &first, &last, &total)
+		if first != len(s.data) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if s.chunks++; s.chunks == 2 && !s.failed {
+			// Persist half of the chunk then fail.
+			s.failed = true
+			s.data = append(s.data, data[:len(data)/2]...)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		s.data = append(s.data, data...)
+	}
+	if total != "*" && strconv.Itoa(len(s.data)) == total {
+		json.NewEncoder(w).Encode(h.store(s.bucket, s.name, gcsObject{}, s.data))
+		return
+	}
+	if len(s.data) > 0 {
+		w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(s.data)-1))
+	}
+	w.WriteHeader(gcsStatusResumeOK)
+}
+
+func (h *gcsHandler) rewrite(w http.ResponseWriter, r *http.Request, bucket string, tokens []string) {
+	name, _ := url.PathUnescape(tokens[2])
+	dstBucket, _ := url.PathUnescape(tokens[5])
+	dstName, _ := url.PathUnescape(tokens[7])
+	if r.URL.Query().Get("rewriteToken") == "" {
+		io.WriteString(w, `{"done":false,"rewriteToken":"next"}`)
+		return
+	}
+	o := h.objects[bucket+"/"+name]
+	h.store(dstBucket, dstName, o, h.data[bucket+"/"+name])
+	io.WriteString(w, `{"done":true}`)
+}
+
+func newTestGCSClient(c *checkv1.C, serverURL, keyFile, path string) Client {
+	config := new(Config)
+	config.HostURL = serverURL + path
+	config.AccessKey = "project"
+	config.SecretKey = keyFile
+	clnt, err := gcsNew(config)
+	c.Assert(err, checkv1.IsNil)
+	return clnt
+}
+
+func (s *TestSuite) TestGCSClient(c *checkv1.C) {
+	key, e := rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(e, checkv1.IsNil)
+	handler := &gcsHandler{
+		key:      key,
+		buckets:  map[string]bool{},
+		objects:  map[string]gcsObject{},
+		data:     map[string][]byte{},
+		sessions: map[string]*gcsTestSession{},
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	handler.url = server.URL
+	ctx := context.Background()
+
+	keyFile := filepath.Join(c.MkDir(), "key.json")
+	account, e := json.Marshal(gcsServiceAccount{
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		ClientEmail: "mc@project.iam.gserviceaccount.com",
+		TokenURI:    server.URL + "/token",
+	})
+	c.Assert(e, checkv1.IsNil)
+	c.Assert(os.WriteFile(keyFile, account, 0o600), checkv1.IsNil)
+
+	err := newTestGCSClient(c, server.URL, keyFile, "/bucket").MakeBucket(ctx, "", false, false)
+	c.Assert(err, checkv1.IsNil)
+
+	// Single request and resumable uploads.
+	data := []byte("hello gcs")
+	clnt := newTestGCSClient(c, server.URL, keyFile, "/bucket/dir/small")
+	n, err := clnt.Put(ctx, bytes.NewReader(data), int64(len(data)), nil, PutOptions{metadata: map[string]string{"Content-Type": "text/plain"}})
+	c.Assert(err, checkv1.IsNil)
+	c.Assert(n, checkv1.Equals, int64(len(data)))
+	c.Assert(handler.objects["bucket/dir/small"].ContentType, checkv1.Equals, "text/plain")
+
+	large := bytes.Repeat([]byte("0123456789"), 60*1024)
+	clnt = newTestGCSClient(c, server.URL, keyFile, "/bucket/dir/large")
+	n, err = clnt.Put(ctx, bytes.NewReader(large), -1, nil, PutOptions{multipartSize: gcsChunkAlign})
+	c.Assert(err, checkv1.IsNil)
+	c.Assert(n, checkv1.Equals, int64(len(large)))
+	c.Assert(handler.data["bucket/dir/large"], checkv1.DeepEquals, large)
+
+	reader, content, err := clnt.Get(ctx, GetOptions{RangeStart: 10})
+	c.Assert(err, checkv1.IsNil)
+	got, e := io.ReadAll(reader)
+	reader.Close()
+	c.Assert(e, checkv1.IsNil)
+	c.Assert(got, checkv1.DeepEquals, large[10:])
+	c.Assert(content.Size, checkv1.Equals, int64(len(large)))
+
+	reader, _, err = clnt.Get(ctx, GetOptions{RangeStart: 10, RangeLength: 5})
+	c.Assert(err, checkv1.IsNil)
+	got, e = io.ReadAll(reader)
+	reader.Close()
+	c.Assert(e, checkv1.IsNil)
+	c.Assert(got, checkv1.DeepEquals, large[10:15])
+
+	// Server side copy.
+	err = newTestGCSClient(c, server.URL, keyFile, "/bucket/copy").Copy(ctx, "/bucket/dir/small", CopyOptions{}, nil)
+	c.Assert(err, checkv1.IsNil)
+	c.Assert(handler.data["bucket/copy"], checkv1.DeepEquals, data)
+
+	// Objects, folders and missing objects.
+	content, err = newTestGCSClient(c, server.URL, keyFile, "/bucket/dir").Stat(ctx, StatOptions{})
+	c.Assert(err, checkv1.IsNil)
+	c.Assert(content.Type.IsDir(), checkv1.Equals, true)
+	_, err = newTestGCSClient(c, server.URL, keyFile, "/bucket/missing").Stat(ctx, StatOptions{})
+	_, ok := err.ToGoError().(ObjectMissing)
+	c.Assert(ok, checkv1.Equals, true)
+	_, err = newTestGCSClient(c, server.URL, keyFile, "/nobucket/object").Stat(ctx, StatOptions{})
+	_, ok = err.ToGoError().(BucketDoesNotExist)
+	c.Assert(ok, checkv1.Equals, true)
+
+	// Paginated listings.
+	handler.store("bucket", "dir/extra", gcsObject{}, nil)
+	var names []string
+	for content := range newTestGCSClient(c, server.URL, keyFile, "/bucket/").List(ctx, ListOptions{}) {
+		c.Assert(content.Err, checkv1.IsNil)
+		names = append(names, content.URL.Path)
+	}
+	c.Assert(names, checkv1.DeepEquals, []string{"/bucket/copy", "/bucket/dir/"})
+	names = nil
+	for content := range newTestGCSClient(c, server.URL, keyFile, "/bucket/").List(ctx, ListOptions{Recursive: true}) {
+		c.Assert(content.Err, checkv1.IsNil)
+		names = append(names, content.URL.Path)
+	}
+	c.Assert(names, checkv1.DeepEquals, []string{"/bucket/copy", "/bucket/dir/extra", "/bucket/dir/large", "/bucket/dir/small"})
+
+	// Removal.
+	contentCh := make(chan *ClientContent, 1)
+	contentCh <- &ClientContent{URL: *newClientURL(server.URL + "/bucket/copy")}
+	close(contentCh)
+	for result := range clnt.Remove(ctx, false, false, false, false, contentCh) {
+		c.Assert(result.Err, checkv1.IsNil)
+	}
+	_, ok = handler.objects["bucket/copy"]
+	c.Assert(ok, checkv1.Equals, false)
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	// Number of retries when neither --retries nor the configuration
+	// sets one.
+	defaultRetries = 3
+
+	retryBaseDelay = 250 * time.Millisecond
+	retryMaxDelay  = 5 * time.Second
+)
+
+// getRetries - returns the number of retries of idempotent operations,
+// set by --retries or else by the transfer section of the configuration
+// file.
+func getRetries() int {
+	if globalRetries >= 0 {
+		return globalRetries
+	}
+	// The global flags are parsed before the configuration is loaded.
+	if loadMcConfig == nil {
+		return defaultRetries
+	}
+	if config, err := loadMcConfig(); err == nil && config.Transfer != nil && config.Transfer.Retries != nil {
+		return *config.Transfer.Retries
+	}
+	return defaultRetries
+}
+
+// retryDelay - returns the exponential backoff before the retry following
+// attempt, with a random jitter of up to half of the delay.
+func retryDelay(attempt int) time.Duration {
+	delay := retryMaxDelay
+	if attempt < 16 {
+		if d := retryBaseDelay << uint(attempt); d < retryMaxDelay {
+			delay = d
+		}
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// isRetryableError - returns true for the server errors, throttles,
+// timeouts and connection resets that might not happen again.
+func isRetryableError(err *probe.Error) bool {
+	if err == nil {
+		return false
+	}
+	e := err.ToGoError()
+	if errors.Is(e, context.Canceled) || errors.Is(e, context.DeadlineExceeded) {
+		return false
+	}
+	switch errResp := e.(type) {
+	case gcsErrorResponse:
+		return errResp.Err.Code >= http.StatusInternalServerError || errResp.Err.Code == http.StatusTooManyRequests
+	case azureErrorResponse:
+		return errResp.statusCode >= http.StatusInternalServerError || errResp.statusCode == http.StatusTooManyRequests
+	}
+	if errResp := minio.ToErrorResponse(e); errResp.StatusCode >= http.StatusInternalServerError ||
+		errResp.StatusCode == http.StatusTooManyRequests || isThrottleCode(errResp.Code) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(e, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(e, syscall.ECONNRESET) || errors.Is(e, io.ErrUnexpectedEOF)
+}
+
+// retryClient - retries the idempotent operations failing with a
+// transient error, waiting an exponential backoff between attempts.
+type retryClient struct {
+	Client
+	retries int
+}
+
+// newRetryClient - wraps clnt when retries are enabled, for the Azure, GCS
+// and SFTP clients. S3 clients are retried by minio-go instead.
+func newRetryClient(clnt Client) Client {
+	retries := getRetries()
+	if retries <= 0 {
+		return clnt
+	}
+	return &retryClient{Client: clnt, retries: retries}
+}
+
+// Unwrap - returns the wrapped client.
+func (c *retryClient) Unwrap() Client {
+	return c.Client
+}
+
+// retry - calls fn until it succeeds, fails with a permanent error or
+// the retries are exhausted.
+func (c *retryClient) retry(ctx context.Context, fn func() *probe.Error) *probe.Error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if attempt >= c.retries || !isRetryableError(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(retryDelay(attempt)):
+		}
+	}
+}
+
+// Stat - retries transient errors.
+func (c *retryClient) Stat(ctx context.Context, opts StatOptions) (content *ClientContent, err *probe.Error) {
+	err = c.retry(ctx, func() *probe.Error {
+		content, err = c.Client.Stat(ctx, opts)
+		return err
+	})
+	return content, err
+}
+
+// Get - retries transient errors before any data is read.
+func (c *retryClient) Get(ctx context.Context, opts GetOptions) (reader io.ReadCloser, content *ClientContent, err *probe.Error) {
+	err = c.retry(ctx, func() *probe.Error {
+		reader, content, err = c.Client.Get(ctx, opts)
+		return err
+	})
+	return reader, content, err
+}
+
+// MakeBucket - retries transient errors.
+func (c *retryClient) MakeBucket(ctx context.Context, region string, ignoreExisting, withLock bool) *probe.Error {
+	return c.retry(ctx, func() *probe.Error {
+		return c.Client.MakeBucket(ctx, region, ignoreExisting, withLock)
+	})
+}
+
+// List - restarts the listing on a transient error, as long as nothing
+// was listed yet.
+func (c *retryClient) List(ctx context.Context, opts ListOptions) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		for attempt := 0; ; attempt++ {
+			listCtx, cancel := context.WithCancel(ctx)
+			listCh := c.Client.List(listCtx, opts)
+			first, ok := <-listCh
+			if ok && first.Err != nil && attempt < c.retries && isRetryableError(first.Err) {
+				cancel()
+				for range listCh {
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(retryDelay(attempt)):
+				}
+				continue
+			}
+			defer cancel()
+			if !ok {
+				return
+			}
+			for content := first; ok; content, ok = <-listCh {
+				select {
+				case <-ctx.Done():
+					return
+				case contentCh <- content:
+				}
+			}
+			return
+		}
+	}()
+	return contentCh
+}
+
+// clientUnwrapper - implemented by the clients wrapping another client.
+type clientUnwrapper interface {
+	Unwrap() Client
+}
+
+// unwrapClient - returns the innermost client, for the callers needing a
+// specific client implementation.
+func unwrapClient(clnt Client) Client {
+	for {
+		w, ok := clnt.(clientUnwrapper)
+		if !ok {
+			return clnt
+		}
+		clnt = w.Unwrap()
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	testCases := []struct {
+		err       *probe.Error
+		retryable bool
+	}{
+		{nil, false},
+		{probe.NewError(minio.ErrorResponse{Code: "SlowDown", StatusCode: 503}), true},
+		{probe.NewError(minio.ErrorResponse{Code: "AccessDenied", StatusCode: 403}), false},
+		{probe.NewError(minio.ErrorResponse{Code: "TooManyRequests", StatusCode: 429}), true},
+		{probe.NewError(minio.ErrorResponse{Code: "SlowDown", StatusCode: 400}), true},
+		{probe.NewError(fmt.Errorf("read: %w", syscall.ECONNRESET)), true},
+		{probe.NewError(context.Canceled), false},
+		{probe.NewError(fmt.Errorf("get: %w", context.DeadlineExceeded)), false},
+		{probe.NewError(ObjectMissing{}), false},
+		{probe.NewError(azureErrorResponse{Code: "ServerBusy", statusCode: 503}), true},
+		{probe.NewError(errors.New("invalid argument")), false},
+	}
+	for i, testCase := range testCases {
+		if retryable := isRetryableError(testCase.err); retryable != testCase.retryable {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.retryable, retryable)
+		}
+	}
+}
+
+// flakyClient - fails its first operations with a transient error.
+type flakyClient struct {
+	Client
+	failures int
+	calls    int
+}
+
+func (c *flakyClient) fail() *probe.Error {
+	c.calls++
+	if c.calls <= c.failures {
+		return probe.NewError(minio.ErrorResponse{Code: "InternalError", StatusCode: 500})
+	}
+	return nil
+}
+
+func (c *flakyClient) Stat(_ context.Context, _ StatOptions) (*ClientContent, *probe.Error) {
+	if err := c.fail(); err != nil {
+		return nil, err
+	}
+	return &ClientContent{Size: 1}, nil
+}
+
+func (c *flakyClient) List(_ context.Context, _ ListOptions) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent, 2)
+	if err := c.fail(); err != nil {
+		contentCh <- &ClientContent{Err: err}
+	} else {
+		contentCh <- &ClientContent{Size: 1}
+		contentCh <- &ClientContent{Size: 2}
+	}
+	close(contentCh)
+	return contentCh
+}
+
+func TestRetryClient(t *testing.T) {
+	ctx := context.Background()
+
+	flaky := &flakyClient{failures: 2}
+	clnt := &retryClient{Client: flaky, retries: 2}
+	if _, err := clnt.Stat(ctx, StatOptions{}); err != nil {
+		t.Fatalf("expected Stat to succeed after retries, got %v", err)
+	}
+	if flaky.calls != 3 {
+		t.Fatalf("expected 3 Stat calls, got %d", flaky.calls)
+	}
+
+	flaky = &flakyClient{failures: 2}
+	clnt = &retryClient{Client: flaky, retries: 1}
+	if _, err := clnt.Stat(ctx, StatOptions{}); err == nil {
+		t.Fatal("expected Stat to fail once the retries are exhausted")
+	}
+
+	flaky = &flakyClient{failures: 1}
+	clnt = &retryClient{Client: flaky, retries: 1}
+	var sizes []int64
+	for content := range clnt.List(ctx, ListOptions{}) {
+		if content.Err != nil {
+			t.Fatalf("expected List to succeed after a retry, got %v", content.Err)
+		}
+		sizes = append(sizes, content.Size)
+	}
+	if len(sizes) != 2 || sizes[0] != 1 || sizes[1] != 2 {
+		t.Fatalf("unexpected listing %v", sizes)
+	}
+
+	if unwrapClient(&encryptedClient{Client: clnt}) != flaky {
+		t.Fatal("expected unwrapClient to return the innermost client")
+	}
+}
+
+func TestNewClientRetries(t *testing.T) {
+	useTestMcConfig(t)
+	cache := cacheCfgV10
+	t.Cleanup(func() { cacheCfgV10 = cache })
+	cacheCfgV10 = nil
+
+	err := updateMcConfig(func(config *configV10) *probe.Error {
+		config.Aliases["s3"] = aliasConfigV10{URL: "http://localhost:9000", AccessKey: "access", SecretKey: "secret12", API: "S3v4", Path: "auto"}
+		config.Aliases["azure"] = aliasConfigV10{URL: "http://localhost:10000", AccessKey: "account", SecretKey: "YWNjb3VudC1rZXk=", API: azureAPI}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// S3 requests are retried by minio-go, not wrapped a second time.
+	for alias, wrapped := range map[string]bool{"s3": false, "azure": true} {
+		clnt, err := newClient(alias + "/bucket/object")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := clnt.(*retryClient); ok != wrapped {
+			t.Errorf("%s: expected a retry client %v, got %T", alias, wrapped, clnt)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+)
+
+// Groups of grantees of the S3 ACLs.
+const (
+	aclAllUsersGroup           = "http://acs.amazonaws.com/groups/global/AllUsers"
+	aclAuthenticatedUsersGroup = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+)
+
+// bucketACLGrant - a grant of the ACL of a bucket.
+type bucketACLGrant struct {
+	Grantee struct {
+		ID          string `xml:"ID"`
+		DisplayName string `xml:"DisplayName"`
+		URI         string `xml:"URI"`
+		Email       string `xml:"EmailAddress"`
+	} `xml:"Grantee"`
+	Permission string `xml:"Permission"`
+}
+
+// bucketACL - the response of GetBucketAcl.
+type bucketACL struct {
+	XMLName xml.Name `xml:"AccessControlPolicy"`
+	Owner   struct {
+		ID          string `xml:"ID"`
+		DisplayName string `xml:"DisplayName"`
+	} `xml:"Owner"`
+	Grants []bucketACLGrant `xml:"AccessControlList>Grant"`
+}
+
+// publicAccessBlock - the response of GetPublicAccessBlock.
+type publicAccessBlock struct {
+	XMLName               xml.Name `xml:"PublicAccessBlockConfiguration" json:"-"`
+	BlockPublicAcls       bool     `xml:"BlockPublicAcls" json:"blockPublicAcls"`
+	IgnorePublicAcls      bool     `xml:"IgnorePublicAcls" json:"ignorePublicAcls"`
+	BlockPublicPolicy     bool     `xml:"BlockPublicPolicy" json:"blockPublicPolicy"`
+	RestrictPublicBuckets bool     `xml:"RestrictPublicBuckets" json:"restrictPublicBuckets"`
+}
+
+// getBucketSubresource - decodes into v the XML returned for the bucket
+// subresource, minio-go does not implement the requests of the ACLs and of
+// the public access block of buckets. The request is presigned, then sent
+// with the transport of the alias.
+func (c *S3Client) getBucketSubresource(ctx context.Context, subresource string, v interface{}) *probe.Error {
+	bucket, _ := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	u, e := c.api.Presign(ctx, http.MethodGet, bucket, "", time.Minute, url.Values{subresource: []string{""}})
+	if e != nil {
+		return probe.NewError(e)
+	}
+	req, e := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	transport := c.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, e := (&http.Client{Transport: transport}).Do(req)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	defer resp.Body.Close()
+	body, e := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if resp.StatusCode != http.StatusOK {
+		errResp := minio.ErrorResponse{StatusCode: resp.StatusCode, BucketName: bucket}
+		if xml.Unmarshal(body, &errResp) != nil || errResp.Code == "" {
+			errResp.Code = resp.Status
+			errResp.Message = http.StatusText(resp.StatusCode)
+		}
+		return probe.NewError(errResp)
+	}
+	if e = xml.Unmarshal(body, v); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// GetBucketACL - returns the ACL of the bucket.
+func (c *S3Client) GetBucketACL(ctx context.Context) (*bucketACL, *probe.Error) {
+	acl := &bucketACL{}
+	if err := c.getBucketSubresource(ctx, "acl", acl); err != nil {
+		return nil, err.Trace(c.GetURL().String())
+	}
+	return acl, nil
+}
+
+// GetPublicAccessBlock - returns the public access block of the bucket,
+// or nil when the bucket has none.
+func (c *S3Client) GetPublicAccessBlock(ctx context.Context) (*publicAccessBlock, *probe.Error) {
+	block := &publicAccessBlock{}
+	if err := c.getBucketSubresource(ctx, "publicAccessBlock", block); err != nil {
+		if minio.ToErrorResponse(err.ToGoError()).Code == "NoSuchPublicAccessBlockConfiguration" {
+			return nil, nil
+		}
+		return nil, err.Trace(c.GetURL().String())
+	}
+	return block, nil
+}
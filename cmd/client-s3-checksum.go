@@ -0,0 +1,68 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Integrity mechanisms of uploads, selected with --checksum-algo.
+const (
+	checksumAlgoAuto   = "auto"
+	checksumAlgoCRC32C = "crc32c"
+	checksumAlgoMD5    = "md5"
+	checksumAlgoNone   = "none"
+)
+
+// parseChecksumAlgo - validates the algorithm of --checksum-algo, auto
+// when empty.
+func parseChecksumAlgo(algo string) (string, error) {
+	switch strings.ToLower(algo) {
+	case "", checksumAlgoAuto:
+		return checksumAlgoAuto, nil
+	case checksumAlgoCRC32C:
+		return checksumAlgoCRC32C, nil
+	case checksumAlgoMD5:
+		return checksumAlgoMD5, nil
+	case checksumAlgoNone:
+		return checksumAlgoNone, nil
+	}
+	return "", fmt.Errorf("unknown checksum algorithm `%s`, expected one of `auto`, `crc32c`, `md5` or `none`", algo)
+}
+
+// getChecksumAlgo - returns the integrity mechanism of the uploads to an
+// endpoint: the one of --checksum-algo, or else trailing CRC32C checksums
+// for AWS, Content-MD5 for the other known providers, and nothing beyond
+// what the upload API requires for unknown endpoints, which might reject
+// the headers they do not implement.
+func getChecksumAlgo(provider, host string) string {
+	if globalChecksumAlgo != checksumAlgoAuto {
+		return globalChecksumAlgo
+	}
+	switch {
+	case isAmazon(host), strings.HasPrefix(strings.ToLower(provider), "aws"):
+		return checksumAlgoCRC32C
+	case isGoogle(host), getS3Capabilities(provider, host) != nil:
+		return checksumAlgoMD5
+	}
+	if _, ok := lookupProviderPreset(provider); ok {
+		return checksumAlgoMD5
+	}
+	return checksumAlgoNone
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetChecksumAlgo(t *testing.T) {
+	defer func(algo string) { globalChecksumAlgo = algo }(globalChecksumAlgo)
+	testCases := []struct {
+		algo, provider, host string
+		expected             string
+	}{
+		{checksumAlgoAuto, "", "s3.us-east-1.amazonaws.com", checksumAlgoCRC32C},
+		{checksumAlgoAuto, "aws-cn", "s3.example.com", checksumAlgoCRC32C},
+		{checksumAlgoAuto, "", "storage.googleapis.com", checksumAlgoMD5},
+		{checksumAlgoAuto, "", "account.r2.cloudflarestorage.com", checksumAlgoMD5},
+		{checksumAlgoAuto, "wasabi", "s3.example.com", checksumAlgoMD5},
+		{checksumAlgoAuto, "", "s3.example.com", checksumAlgoNone},
+		{checksumAlgoMD5, "", "s3.us-east-1.amazonaws.com", checksumAlgoMD5},
+		{checksumAlgoNone, "", "s3.us-east-1.amazonaws.com", checksumAlgoNone},
+	}
+	for i, testCase := range testCases {
+		globalChecksumAlgo = testCase.algo
+		if algo := getChecksumAlgo(testCase.provider, testCase.host); algo != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, algo)
+		}
+	}
+	if _, e := parseChecksumAlgo("sha1"); e == nil {
+		t.Error("expected an unknown algorithm to be refused")
+	}
+}
+
+func TestPutChecksumAlgo(t *testing.T) {
+	defer func(algo string) { globalChecksumAlgo = algo }(globalChecksumAlgo)
+	for _, algo := range []string{checksumAlgoCRC32C, checksumAlgoMD5, checksumAlgoNone} {
+		var header http.Header
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Query().Has("location"):
+				fmt.Fprint(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`)
+			case r.Method == http.MethodPut:
+				header = r.Header.Clone()
+				readAWSChunked(r)
+				w.Header().Set("ETag", `"etag"`)
+			default:
+				w.WriteHeader(http.StatusNotImplemented)
+			}
+		}))
+		globalChecksumAlgo = algo
+		conf := new(Config)
+		conf.HostURL = server.URL + "/bucket/object"
+		conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+		conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+		conf.Signature = "S3v4"
+		conf.Insecure = true
+		clnt, err := S3New(conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data := []byte("checksummed")
+		_, err = clnt.Put(context.Background(), bytes.NewReader(data), int64(len(data)), nil, PutOptions{})
+		server.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", algo, err)
+		}
+		withMD5 := header.Get("Content-Md5") != ""
+		withCRC := header.Get("X-Amz-Trailer") == "x-amz-checksum-crc32c"
+		if withMD5 != (algo == checksumAlgoMD5) || withCRC != (algo == checksumAlgoCRC32C) {
+			t.Errorf("%s: unexpected checksums, Content-Md5 %v and trailing CRC32C %v", algo, withMD5, withCRC)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	// Concurrent listings of the diff and mirror scans.
+	scanListParallel = 8
+
+	// Keys listed to find the prefixes splitting the keyspace.
+	listBoundariesMaxKeys = 1000
+	// Partitions listed by each of the concurrent listings.
+	listPartitionsPerWorker = 4
+	// Objects listed ahead by a partition waiting for the previous ones.
+	listPartitionBuffer = 1000
+)
+
+// listObjectsRecursive - lists recursively the objects under object, with
+// concurrent listings when opts.Parallel is more than one.
+func (c *S3Client) listObjectsRecursive(ctx context.Context, bucket, object string, opts ListOptions) <-chan minio.ObjectInfo {
+	if opts.Parallel > 1 && !opts.ListZip {
+		return c.listObjectsParallel(ctx, bucket, object, opts)
+	}
+	return c.listObjectWrapper(ctx, bucket, object, true, time.Time{}, false, false, opts.WithMetadata, -1, opts.ListZip)
+}
+
+// listObjectsParallel - lists recursively the objects under prefix with
+// opts.Parallel concurrent listings, each of a partition of the keyspace
+// split at the prefixes under prefix. Objects are returned in the order of
+// a single listing.
+func (c *S3Client) listObjectsParallel(ctx context.Context, bucket, prefix string, opts ListOptions) <-chan minio.ObjectInfo {
+	objectCh := make(chan minio.ObjectInfo)
+	go func() {
+		defer close(objectCh)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// Partition i lists the keys after boundaries[i-1] up to
+		// boundaries[i] included.
+		boundaries := c.listBoundaries(ctx, bucket, prefix, opts.Parallel*listPartitionsPerWorker)
+		partitions := make([]chan minio.ObjectInfo, len(boundaries)+1)
+		for i := range partitions {
+			partitions[i] = make(chan minio.ObjectInfo, listPartitionBuffer)
+		}
+		go func() {
+			workers := make(chan struct{}, opts.Parallel)
+			for i := range partitions {
+				select {
+				case <-ctx.Done():
+					return
+				case workers <- struct{}{}:
+				}
+				var startAfter, end string
+				if i > 0 {
+					startAfter = boundaries[i-1]
+				}
+				if i < len(boundaries) {
+					end = boundaries[i]
+				}
+				go func(partition chan minio.ObjectInfo) {
+					defer func() { <-workers }()
+					c.listPartition(ctx, bucket, prefix, startAfter, end, opts.WithMetadata, partition)
+				}(partitions[i])
+			}
+		}()
+
+		for _, partition := range partitions {
+			for {
+				var object minio.ObjectInfo
+				var ok bool
+				select {
+				case <-ctx.Done():
+					return
+				case object, ok = <-partition:
+				}
+				if !ok {
+					break
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case objectCh <- object:
+				}
+				if object.Err != nil {
+					return
+				}
+			}
+		}
+	}()
+	return objectCh
+}
+
+// listBoundaries - returns at most n of the prefixes found under prefix,
+// evenly spread, in lexical order.
+func (c *S3Client) listBoundaries(ctx context.Context, bucket, prefix string, n int) []string {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var prefixes []string
+	var listed int
+	opts := minio.ListObjectsOptions{Prefix: prefix, MaxKeys: listBoundariesMaxKeys, UseV1: isGoogle(c.targetURL.Host)}
+	for object := range c.api.ListObjects(ctx, bucket, opts) {
+		if object.Err != nil {
+			// Reported by the listing of the partitions.
+			return nil
+		}
+		if strings.HasSuffix(object.Key, "/") {
+			prefixes = append(prefixes, object.Key)
+		}
+		if listed++; listed == listBoundariesMaxKeys {
+			break
+		}
+	}
+	// Objects and prefixes of a page are not returned in lexical order.
+	sort.Strings(prefixes)
+	if len(prefixes) <= n {
+		return prefixes
+	}
+	boundaries := make([]string, n)
+	for i := range boundaries {
+		boundaries[i] = prefixes[(i+1)*len(prefixes)/(n+1)]
+	}
+	return boundaries
+}
+
+// listPartition - lists recursively the objects under prefix after
+// startAfter up to end included, or to the last object when end is empty.
+func (c *S3Client) listPartition(ctx context.Context, bucket, prefix, startAfter, end string, metadata bool, partition chan<- minio.ObjectInfo) {
+	defer close(partition)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	opts := minio.ListObjectsOptions{Prefix: prefix, Recursive: true, StartAfter: startAfter}
+	if isGoogle(c.targetURL.Host) {
+		opts.UseV1 = true
+	} else {
+		opts.WithMetadata = metadata
+	}
+	for object := range c.api.ListObjects(ctx, bucket, opts) {
+		if object.Err == nil && end != "" && object.Key > end {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case partition <- object:
+		}
+		if object.Err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// listBucketHandler - a bucket answering ListObjectsV2 with pages of
+// three entries, recording the start-after of the listings.
+type listBucketHandler struct {
+	mu          sync.Mutex
+	keys        []string
+	startAfters []string
+}
+
+func (h *listBucketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Has("location") {
+		fmt.Fprint(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`)
+		return
+	}
+	if query.Get("list-type") != "2" {
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+	prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+	after := query.Get("start-after")
+	if token := query.Get("continuation-token"); token != "" {
+		after = token
+	} else if after != "" {
+		h.mu.Lock()
+		h.startAfters = append(h.startAfters, after)
+		h.mu.Unlock()
+	}
+
+	type content struct {
+		Key          string
+		LastModified string
+		ETag         string
+		Size         int64
+		StorageClass string
+	}
+	type commonPrefix struct {
+		Prefix string
+	}
+	var result struct {
+		XMLName               xml.Name `xml:"ListBucketResult"`
+		Name                  string
+		Prefix                string
+		KeyCount              int
+		MaxKeys               int
+		IsTruncated           bool
+		NextContinuationToken string
+		Contents              []content
+		CommonPrefixes        []commonPrefix
+	}
+	result.Name, result.Prefix, result.MaxKeys = "bucket", prefix, 3
+	for _, key := range h.keys {
+		if !strings.HasPrefix(key, prefix) || key <= after {
+			continue
+		}
+		// Keys of the common prefix returned last.
+		if strings.HasSuffix(after, delimiter) && delimiter != "" && strings.HasPrefix(key, after) {
+			continue
+		}
+		if result.KeyCount == result.MaxKeys {
+			result.IsTruncated = true
+			break
+		}
+		entry := key
+		if i := strings.Index(key[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+			entry = key[:len(prefix)+i+1]
+			result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix{entry})
+		} else {
+			result.Contents = append(result.Contents, content{key, "2024-01-01T00:00:00.000Z", `"etag"`, 1, "STANDARD"})
+		}
+		result.KeyCount++
+		result.NextContinuationToken, after = entry, entry
+	}
+	if !result.IsTruncated {
+		result.NextContinuationToken = ""
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(result)
+}
+
+func TestListObjectsParallel(t *testing.T) {
+	handler := &listBucketHandler{}
+	for _, p := range []string{"a", "b", "c", "d", "e", "f"} {
+		for i := 0; i < 5; i++ {
+			handler.keys = append(handler.keys, p+"/"+strconv.Itoa(i))
+		}
+	}
+	// Keys sorted around the b/ prefix.
+	handler.keys = append(handler.keys, "b!", "b.txt", "b/", "b0", "top")
+	sort.Strings(handler.keys)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	for _, prefix := range []string{"", "b", "b/"} {
+		conf := new(Config)
+		conf.HostURL = server.URL + "/bucket/" + prefix
+		conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+		conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+		conf.Signature = "S3v4"
+		clnt, err := S3New(conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var want []string
+		for _, key := range handler.keys {
+			if strings.HasPrefix(key, prefix) {
+				want = append(want, key)
+			}
+		}
+		for _, parallel := range []int{0, 2} {
+			var got []string
+			for content := range clnt.List(context.Background(), ListOptions{Recursive: true, ShowDir: DirNone, Parallel: parallel}) {
+				if content.Err != nil {
+					t.Fatal(content.Err)
+				}
+				got = append(got, strings.TrimPrefix(content.URL.Path, "/bucket/"))
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("prefix %q, parallel %d: expected %v, got %v", prefix, parallel, want, got)
+			}
+		}
+	}
+	if len(handler.startAfters) == 0 {
+		t.Error("expected partitions listed after the prefixes")
+	}
+}
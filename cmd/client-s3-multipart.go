@@ -0,0 +1,211 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// resumeMultipartUpload - completes the last incomplete multipart upload
+// of the object left by an interrupted upload of the same file, uploading
+// only the missing parts. Returns false when there is no such upload, or
+// when its parts don't match the file and the file is uploaded again.
+func (c *S3Client) resumeMultipartUpload(ctx context.Context, bucket, object string, reader io.ReaderAt, size int64, opts minio.PutObjectOptions) (minio.UploadInfo, bool, error) {
+	totalParts, partSize, lastPartSize, e := minio.OptimalPartInfo(size, opts.PartSize)
+	if e != nil || totalParts < 2 {
+		return minio.UploadInfo{}, false, nil
+	}
+
+	var upload minio.ObjectMultipartInfo
+	for info := range c.api.ListIncompleteUploads(ctx, bucket, object, false) {
+		if info.Err != nil {
+			return minio.UploadInfo{}, false, nil
+		}
+		if info.Key == object && info.Initiated.After(upload.Initiated) {
+			upload = info
+		}
+	}
+	if upload.UploadID == "" {
+		return minio.UploadInfo{}, false, nil
+	}
+
+	core := minio.Core{Client: c.api}
+	uploaded := make(map[int]minio.ObjectPart)
+	for marker := 0; ; {
+		result, e := core.ListObjectParts(ctx, bucket, object, upload.UploadID, marker, 1000)
+		if e != nil {
+			return minio.UploadInfo{}, false, nil
+		}
+		for _, part := range result.ObjectParts {
+			uploaded[part.PartNumber] = part
+		}
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextPartNumberMarker
+	}
+	if len(uploaded) == 0 {
+		return minio.UploadInfo{}, false, nil
+	}
+
+	// The upload is resumed only if all its parts are parts of the file.
+	partLength := func(partNumber int) int64 {
+		if partNumber == totalParts {
+			return lastPartSize
+		}
+		return partSize
+	}
+	withChecksum := false
+	for partNumber, part := range uploaded {
+		if partNumber < 1 || partNumber > totalParts || part.Size != partLength(partNumber) {
+			return minio.UploadInfo{}, false, nil
+		}
+		sum := md5.New()
+		if _, e := io.Copy(sum, io.NewSectionReader(reader, int64(partNumber-1)*partSize, part.Size)); e != nil {
+			return minio.UploadInfo{}, true, e
+		}
+		if strings.Trim(part.ETag, `"`) != hex.EncodeToString(sum.Sum(nil)) {
+			return minio.UploadInfo{}, false, nil
+		}
+		withChecksum = withChecksum || part.ChecksumCRC32C != ""
+	}
+	if opts.Progress != nil {
+		for _, part := range uploaded {
+			io.CopyN(io.Discard, opts.Progress, part.Size)
+		}
+	}
+
+	// Upload the missing parts.
+	missing := make(chan int)
+	go func() {
+		defer close(missing)
+		for partNumber := 1; partNumber <= totalParts; partNumber++ {
+			if _, ok := uploaded[partNumber]; !ok {
+				select {
+				case <-ctx.Done():
+					return
+				case missing <- partNumber:
+				}
+			}
+		}
+	}()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	threads := int(opts.NumThreads)
+	if threads < 1 {
+		threads = 1
+	}
+	var mutex sync.Mutex
+	var uploadErr error
+	fail := func(e error) {
+		mutex.Lock()
+		if uploadErr == nil {
+			uploadErr = e
+		}
+		mutex.Unlock()
+		cancel()
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for partNumber := range missing {
+				section := io.NewSectionReader(reader, int64(partNumber-1)*partSize, partLength(partNumber))
+				var partOpts minio.PutObjectPartOptions
+				var checksum string
+				if withChecksum {
+					crc := crc32.New(crc32.MakeTable(crc32.Castagnoli))
+					if _, e := io.Copy(crc, section); e != nil {
+						fail(e)
+						return
+					}
+					section.Seek(0, io.SeekStart)
+					checksum = base64.StdEncoding.EncodeToString(crc.Sum(nil))
+					partOpts.CustomHeader = http.Header{}
+					partOpts.CustomHeader.Set("x-amz-checksum-crc32c", checksum)
+				}
+				part, e := core.PutObjectPart(ctx, bucket, object, upload.UploadID, partNumber, section, section.Size(), partOpts)
+				if e != nil {
+					fail(e)
+					return
+				}
+				part.ChecksumCRC32C = checksum
+				mutex.Lock()
+				uploaded[partNumber] = part
+				mutex.Unlock()
+				if opts.Progress != nil {
+					io.CopyN(io.Discard, opts.Progress, part.Size)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if uploadErr == nil {
+		uploadErr = ctx.Err()
+	}
+	if uploadErr != nil {
+		return minio.UploadInfo{}, true, uploadErr
+	}
+
+	parts := make([]minio.CompletePart, 0, len(uploaded))
+	for partNumber, part := range uploaded {
+		parts = append(parts, minio.CompletePart{PartNumber: partNumber, ETag: part.ETag, ChecksumCRC32C: part.ChecksumCRC32C})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	var completeOpts minio.PutObjectOptions
+	if withChecksum {
+		// The checksum of the part checksums, as minio-go sends it.
+		crc := crc32.New(crc32.MakeTable(crc32.Castagnoli))
+		for _, part := range parts {
+			if cs, e := base64.StdEncoding.DecodeString(part.ChecksumCRC32C); e == nil {
+				crc.Write(cs)
+			}
+		}
+		completeOpts.UserMetadata = map[string]string{"X-Amz-Checksum-Crc32c": base64.StdEncoding.EncodeToString(crc.Sum(nil))}
+	}
+	info, e := core.CompleteMultipartUpload(ctx, bucket, object, upload.UploadID, parts, completeOpts)
+	if e != nil {
+		return info, true, e
+	}
+	info.Size = size
+	return info, true, nil
+}
+
+// isWholeFile - returns true when file is read from its start and holds
+// size bytes, parts are then read from the file at their offset.
+func isWholeFile(file *os.File, size int64) bool {
+	offset, e := file.Seek(0, io.SeekCurrent)
+	if e != nil || offset != 0 {
+		return false
+	}
+	fi, e := file.Stat()
+	return e == nil && fi.Mode().IsRegular() && fi.Size() == size
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// multipartUploadHandler - a bucket holding an incomplete multipart upload
+// of an object, recording the parts uploaded and completed.
+type multipartUploadHandler struct {
+	mu        sync.Mutex
+	parts     map[int][]byte
+	uploaded  []int
+	completed []int
+}
+
+func (h *multipartUploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	query := r.URL.Query()
+	switch {
+	case query.Has("location"):
+		fmt.Fprint(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`)
+	case r.Method == http.MethodGet && query.Has("uploads"):
+		fmt.Fprint(w, `<ListMultipartUploadsResult><Bucket>bucket</Bucket><IsTruncated>false</IsTruncated>`+
+			`<Upload><Key>object</Key><UploadId>u1</UploadId><Initiated>2024-01-01T00:00:00.000Z</Initiated></Upload></ListMultipartUploadsResult>`)
+	case r.Method == http.MethodGet && query.Get("uploadId") == "u1":
+		var numbers []int
+		for partNumber := range h.parts {
+			numbers = append(numbers, partNumber)
+		}
+		sort.Ints(numbers)
+		fmt.Fprint(w, `<ListPartsResult><Bucket>bucket</Bucket><Key>object</Key><UploadId>u1</UploadId><IsTruncated>false</IsTruncated>`)
+		for _, partNumber := range numbers {
+			sum := md5.Sum(h.parts[partNumber])
+			fmt.Fprintf(w, `<Part><PartNumber>%d</PartNumber><ETag>"%s"</ETag><Size>%d</Size><LastModified>2024-01-01T00:00:00.000Z</LastModified></Part>`,
+				partNumber, hex.EncodeToString(sum[:]), len(h.parts[partNumber]))
+		}
+		fmt.Fprint(w, `</ListPartsResult>`)
+	case r.Method == http.MethodPut && query.Get("uploadId") == "u1":
+		partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+		data := readAWSChunked(r)
+		h.parts[partNumber] = data
+		h.uploaded = append(h.uploaded, partNumber)
+		sum := md5.Sum(data)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	case r.Method == http.MethodPost && query.Get("uploadId") == "u1":
+		var complete struct {
+			Parts []struct{ PartNumber int } `xml:"Part"`
+		}
+		xml.NewDecoder(r.Body).Decode(&complete)
+		for _, part := range complete.Parts {
+			h.completed = append(h.completed, part.PartNumber)
+		}
+		fmt.Fprint(w, `<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><ETag>"etag-3"</ETag></CompleteMultipartUploadResult>`)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+// readAWSChunked - returns the payload of a request body, with the chunk
+// signatures of streaming uploads removed.
+func readAWSChunked(r *http.Request) []byte {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		data, _ := io.ReadAll(r.Body)
+		return data
+	}
+	var data []byte
+	body := bufio.NewReader(r.Body)
+	for {
+		header, e := body.ReadString('\n')
+		if e != nil {
+			return data
+		}
+		size, e := strconv.ParseInt(strings.SplitN(strings.TrimSpace(header), ";", 2)[0], 16, 64)
+		if e != nil || size == 0 {
+			return data
+		}
+		chunk := make([]byte, size+2)
+		if _, e = io.ReadFull(body, chunk); e != nil {
+			return data
+		}
+		data = append(data, chunk[:size]...)
+	}
+}
+
+func TestResumeMultipartUpload(t *testing.T) {
+	const partSize = 5 << 20
+	data := bytes.Repeat([]byte("0123456789abcdef"), (2*partSize+1<<20)/16)
+	file := filepath.Join(t.TempDir(), "object")
+	if e := os.WriteFile(file, data, 0o600); e != nil {
+		t.Fatal(e)
+	}
+
+	testCases := []struct {
+		first     []byte
+		resumed   bool
+		uploaded  []int
+		completed []int
+	}{
+		// The first part was uploaded before the interruption.
+		{data[:partSize], true, []int{2, 3}, []int{1, 2, 3}},
+		// The first part is not a part of the file.
+		{bytes.Repeat([]byte("x"), partSize), false, nil, nil},
+	}
+	for i, testCase := range testCases {
+		handler := &multipartUploadHandler{parts: map[int][]byte{1: testCase.first}}
+		server := httptest.NewServer(handler)
+		conf := new(Config)
+		conf.HostURL = server.URL + "/bucket/object"
+		conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+		conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+		conf.Signature = "S3v4"
+		clnt, err := S3New(conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, e := os.Open(file)
+		if e != nil {
+			t.Fatal(e)
+		}
+		info, resumed, e := clnt.(*S3Client).resumeMultipartUpload(context.Background(), "bucket", "object", f, int64(len(data)),
+			minio.PutObjectOptions{PartSize: partSize, NumThreads: 2})
+		f.Close()
+		server.Close()
+		if e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		sort.Ints(handler.uploaded)
+		if resumed != testCase.resumed || !reflect.DeepEqual(handler.uploaded, testCase.uploaded) || !reflect.DeepEqual(handler.completed, testCase.completed) {
+			t.Errorf("Test %d: expected %v with parts %v uploaded and %v completed, got %v with %v and %v", i+1,
+				testCase.resumed, testCase.uploaded, testCase.completed, resumed, handler.uploaded, handler.completed)
+		}
+		if resumed && (info.Size != int64(len(data)) || !bytes.Equal(bytes.Join([][]byte{handler.parts[1], handler.parts[2], handler.parts[3]}, nil), data)) {
+			t.Errorf("Test %d: the parts don't hold the file", i+1)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// s3Feature - a group of S3 APIs which some providers do not implement.
+type s3Feature int
+
+const (
+	s3FeatureBucketPolicy s3Feature = iota
+	s3FeatureVersioning
+	s3FeatureObjectLock
+	s3FeatureTagging
+	s3FeatureReplication
+	s3FeatureNotification
+	s3FeatureLifecycle
+	s3FeatureEncryption
+	s3FeatureSelect
+	s3FeatureRestore
+)
+
+// s3Capabilities - what an S3 compatible provider supports, requests
+// for missing features fail early with APINotImplemented instead of
+// whatever error the provider returns for them.
+type s3Capabilities struct {
+	name    string
+	missing map[s3Feature]bool
+	// Largest object size accepted, zero when not limited below S3.
+	maxObjectSize int64
+}
+
+// Capabilities of the providers with known gaps, S3 and MinIO are
+// assumed to support everything.
+var s3ProviderCapabilities = map[string]s3Capabilities{
+	"cloudflare-r2": {
+		name: "Cloudflare R2",
+		missing: map[s3Feature]bool{
+			s3FeatureBucketPolicy: true,
+			s3FeatureVersioning:   true,
+			s3FeatureObjectLock:   true,
+			s3FeatureTagging:      true,
+			s3FeatureReplication:  true,
+			s3FeatureNotification: true,
+			s3FeatureEncryption:   true,
+			s3FeatureSelect:       true,
+			s3FeatureRestore:      true,
+		},
+		maxObjectSize: 5*humanize.TiByte - 5*humanize.GiByte,
+	},
+	"wasabi": {
+		name: "Wasabi",
+		missing: map[s3Feature]bool{
+			s3FeatureReplication:  true,
+			s3FeatureNotification: true,
+			s3FeatureLifecycle:    true,
+			s3FeatureEncryption:   true,
+			s3FeatureSelect:       true,
+			s3FeatureRestore:      true,
+		},
+	},
+	"digitalocean": {
+		name: "DigitalOcean Spaces",
+		missing: map[s3Feature]bool{
+			s3FeatureObjectLock:   true,
+			s3FeatureReplication:  true,
+			s3FeatureNotification: true,
+			s3FeatureEncryption:   true,
+			s3FeatureSelect:       true,
+			s3FeatureRestore:      true,
+		},
+	},
+}
+
+// Host name suffixes of the providers, to recognize aliases
+// added without --provider.
+var s3ProviderHostSuffixes = map[string]string{
+	".r2.cloudflarestorage.com": "cloudflare-r2",
+	".wasabisys.com":            "wasabi",
+	".digitaloceanspaces.com":   "digitalocean",
+}
+
+// getS3Capabilities - returns the capabilities of the alias provider, or
+// of the provider serving host when the alias has none. Nil means that
+// every feature is supported.
+func getS3Capabilities(provider, host string) *s3Capabilities {
+	if provider == "" {
+		host = strings.ToLower(host)
+		for suffix, name := range s3ProviderHostSuffixes {
+			if strings.HasSuffix(host, suffix) {
+				provider = name
+				break
+			}
+		}
+	}
+	capabilities, ok := s3ProviderCapabilities[strings.ToLower(provider)]
+	if !ok {
+		return nil
+	}
+	return &capabilities
+}
+
+// supports - reports whether the feature is implemented by the provider.
+func (c *s3Capabilities) supports(feature s3Feature) bool {
+	return c == nil || !c.missing[feature]
+}
+
+// unsupported - returns an APINotImplemented error for API when the
+// provider does not implement the feature it belongs to.
+func (c *S3Client) unsupported(api string, feature s3Feature) *probe.Error {
+	if c.capabilities.supports(feature) {
+		return nil
+	}
+	return probe.NewError(APINotImplemented{API: api, APIType: c.capabilities.name})
+}
+
+// checkObjectSize - fails uploads larger than the provider accepts
+// before any data is sent.
+func (c *S3Client) checkObjectSize(size int64) *probe.Error {
+	if c.capabilities == nil || c.capabilities.maxObjectSize == 0 || size <= c.capabilities.maxObjectSize {
+		return nil
+	}
+	return probe.NewError(fmt.Errorf("object size %s exceeds the maximum of %s allowed by %s",
+		humanize.IBytes(uint64(size)), humanize.IBytes(uint64(c.capabilities.maxObjectSize)), c.capabilities.name))
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"testing"
+)
+
+func TestRedactTraceRequest(t *testing.T) {
+	testCases := []struct {
+		url    string
+		header map[string]string
+		secret []string
+		keep   []string
+	}{
+		{
+			url: "https://play.min.io/bucket/object",
+			header: map[string]string{
+				"Authorization":        "AWS4-HMAC-SHA256 Credential=minioadmin/20240310/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-date, Signature=bbfaa693c626021bcb5f911cd898a1a30206c1fad6bad1e0eb89e282173bd24c",
+				"X-Amz-Security-Token": "sessiontoken",
+			},
+			secret: []string{"minioadmin", "bbfaa693c626", "sessiontoken"},
+			keep:   []string{"SignedHeaders=host;x-amz-date", "/20240310/us-east-1/s3/aws4_request"},
+		},
+		{
+			url: "https://play.min.io/bucket/object",
+			header: map[string]string{
+				"Authorization": "AWS AKIAJVA5BMMU2RHO6IO1:Y10YHUZ0DTUterAUI6w3XKX7Iqk=",
+				"X-Amz-Server-Side-Encryption-Customer-Key":             "Y3VzdG9tZXJrZXk=",
+				"X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key": "c291cmNla2V5",
+			},
+			secret: []string{"AKIAJVA5BMMU2RHO6IO1", "Y10YHUZ0DTUterAUI6w3XKX7Iqk", "Y3VzdG9tZXJrZXk", "c291cmNla2V5"},
+		},
+		{
+			url:    "https://account.blob.core.windows.net/container/blob",
+			header: map[string]string{"Authorization": "SharedKey account:c2lnbmF0dXJl"},
+			secret: []string{"account:c2lnbmF0dXJl"},
+			keep:   []string{"SharedKey **REDACTED**"},
+		},
+		{
+			url:    "https://storage.googleapis.com/storage/v1/b",
+			header: map[string]string{"Authorization": "Bearer ya29.token"},
+			secret: []string{"ya29.token"},
+		},
+		{
+			url:    "https://play.min.io/bucket/object?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Credential=minioadmin%2F20240310%2Fus-east-1%2Fs3%2Faws4_request&X-Amz-Signature=abcdef0123&versionId=v1",
+			secret: []string{"minioadmin", "abcdef0123"},
+			keep:   []string{"versionId=v1"},
+		},
+	}
+	for i, testCase := range testCases {
+		req, e := http.NewRequest(http.MethodGet, testCase.url, nil)
+		if e != nil {
+			t.Fatal(e)
+		}
+		for k, v := range testCase.header {
+			req.Header.Set(k, v)
+		}
+		orig := req.Header.Clone()
+		origURL := req.URL.String()
+
+		dump, e := httputil.DumpRequestOut(redactTraceRequest(req), false)
+		if e != nil {
+			t.Fatal(e)
+		}
+		trace := string(dump)
+		for _, secret := range testCase.secret {
+			if strings.Contains(trace, secret) {
+				t.Errorf("Test %d: %q is not redacted from:\n%s", i+1, secret, trace)
+			}
+		}
+		for _, keep := range testCase.keep {
+			if !strings.Contains(trace, keep) {
+				t.Errorf("Test %d: %q is missing from:\n%s", i+1, keep, trace)
+			}
+		}
+		// The request sent is unchanged.
+		for k := range testCase.header {
+			if req.Header.Get(k) != orig.Get(k) {
+				t.Errorf("Test %d: the %s header of the request changed", i+1, k)
+			}
+		}
+		if req.URL.String() != origURL {
+			t.Errorf("Test %d: the URL of the request changed", i+1)
+		}
+	}
+}
@@ -65,7 +65,10 @@ type S3Client struct {
 	sync.Mutex
 	targetURL    *ClientURL
 	api          *minio.Client
+	transport    http.RoundTripper
 	virtualStyle bool
+	capabilities *s3Capabilities
+	checksumAlgo string
 }
 
 const (
@@ -129,7 +132,7 @@ func getConfigHash(config *Config) uint32 {
 
 	// Generate a hash out of s3Conf.
 	confHash := fnv.New32a()
-	confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey + config.SessionToken))
+	confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey + config.SessionToken + config.Region + strings.ToLower(config.Signature) + strings.ToLower(config.Provider)))
 	confSum := confHash.Sum32()
 	return confSum
 }
@@ -175,7 +178,8 @@ func getTransportForConfig(config *Config, withS3v2 bool) http.RoundTripper {
 		if useTLS {
 			// Keep TLS config.
 			tlsConfig := &tls.Config{
-				RootCAs: globalRootCAs,
+				RootCAs:      globalRootCAs,
+				Certificates: globalClientCertificates,
 				// Can't use SSLv3 because of POODLE and BEAST
 				// Can't use TLSv1.0 because of POODLE and BEAST using CBC cipher
 				// Can't use TLSv1.1 because of RC4 cipher usage
@@ -200,13 +204,28 @@ func getTransportForConfig(config *Config, withS3v2 bool) http.RoundTripper {
 
 	transport = limiter.New(config.UploadLimit, config.DownloadLimit, transport)
 
+	// Account the traffic of the alias, see `mc usage`.
+	if config.Alias != "" {
+		transport = newUsageTransport(config.Alias, transport)
+	}
+
 	if config.Debug {
-		if strings.EqualFold(config.Signature, "S3v4") {
-			transport = httptracer.GetNewTraceTransport(newTraceV4(), transport)
-		} else if strings.EqualFold(config.Signature, "S3v2") && withS3v2 {
+		if strings.EqualFold(config.Signature, "S3v2") && withS3v2 {
 			transport = httptracer.GetNewTraceTransport(newTraceV2(), transport)
+		} else {
+			transport = httptracer.GetNewTraceTransport(newTraceV4(), transport)
 		}
 	}
+
+	// Slow down when the server asks to.
+	transport = newThrottleTransport(config, transport)
+
+	// Report, and optionally adjust, the skew of the local clock.
+	transport = newClockSkewTransport(config, transport)
+
+	// Hold the requests while the transfers are paused.
+	transport = newPauseTransport(config, transport)
+	transport = newBucketRegionTransport(config, transport)
 	transport = gzhttp.Transport(transport)
 	return transport
 }
@@ -215,11 +234,12 @@ func getTransportForConfig(config *Config, withS3v2 bool) http.RoundTripper {
 // and the STS configuration (if present)
 func getCredentialsChainForConfig(config *Config, transport http.RoundTripper) ([]credentials.Provider, *probe.Error) {
 	var credsChain []credentials.Provider
+	webIdentityFile := env.Get("MC_WEB_IDENTITY_TOKEN_FILE_"+config.Alias, "")
 	// if an STS endpoint is set, we will add that to the chain
-	if stsEndpoint := env.Get("MC_STS_ENDPOINT_"+config.Alias, ""); stsEndpoint != "" {
+	if stsEndpoint := env.Get("MC_STS_ENDPOINT_"+config.Alias, ""); stsEndpoint != "" && (webIdentityFile != "" || env.Get("MC_ROLE_ARN_"+config.Alias, "") == "") {
 		// set AWS_WEB_IDENTITY_TOKEN_FILE is MC_WEB_IDENTITY_TOKEN_FILE is set
-		if val := env.Get("MC_WEB_IDENTITY_TOKEN_FILE_"+config.Alias, ""); val != "" {
-			os.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", val)
+		if webIdentityFile != "" {
+			os.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", webIdentityFile)
 			if val := env.Get("MC_ROLE_ARN_"+config.Alias, ""); val != "" {
 				os.Setenv("AWS_ROLE_ARN", val)
 			}
@@ -250,13 +270,29 @@ func getCredentialsChainForConfig(config *Config, transport http.RoundTripper) (
 			SignerType:      credentials.SignatureV4,
 		},
 	}
-	credsChain = append(credsChain, credsV4)
-	return credsChain, nil
+	keysChain := []credentials.Provider{credsV4}
+
+	// Without keys in the config, the credentials are resolved from
+	// the environment, see MC_CREDENTIAL_PROVIDERS.
+	if config.AccessKey == "" && config.SecretKey == "" {
+		providers, err := getCredentialProviders(config, transport)
+		if err != nil {
+			return nil, err
+		}
+		keysChain = append(keysChain, providers...)
+	}
+
+	// A role assumed with the keys, optionally with MFA, replaces them.
+	if roleARN := env.Get("MC_ROLE_ARN_"+config.Alias, ""); roleARN != "" && webIdentityFile == "" {
+		return append(credsChain, newAssumeRole(config, transport, roleARN, keysChain)), nil
+	}
+	return append(credsChain, keysChain...), nil
 }
 
 // newFactory encloses New function with client cache.
 func newFactory() func(config *Config) (Client, *probe.Error) {
 	clientCache := make(map[uint32]*minio.Client)
+	transportCache := make(map[uint32]http.RoundTripper)
 	var mutex sync.Mutex
 
 	// Return New function.
@@ -277,6 +313,8 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 		s3Clnt.targetURL = targetURL
 
 		s3Clnt.virtualStyle = isVirtualHostStyle(hostName, config.Lookup)
+		s3Clnt.capabilities = getS3Capabilities(config.Provider, targetURL.Host)
+		s3Clnt.checksumAlgo = getChecksumAlgo(config.Provider, targetURL.Host)
 		isS3AcceleratedEndpoint := isAmazonAccelerated(hostName)
 
 		if s3Clnt.virtualStyle {
@@ -295,23 +333,34 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 
 			transport := getTransportForConfig(config, true)
 
-			credsChain, err := getCredentialsChainForConfig(config, transport)
-			if err != nil {
-				return nil, err
-			}
+			// The signature of the alias, see `mc alias set --api`.
+			var creds *credentials.Credentials
+			switch {
+			case strings.EqualFold(config.Signature, anonymousAPI):
+				creds = credentials.NewStatic("", "", "", credentials.SignatureAnonymous)
+			case strings.EqualFold(config.Signature, "S3v2"):
+				creds = credentials.NewStaticV2(config.AccessKey, config.SecretKey, "")
+			default:
+				credsChain, err := getCredentialsChainForConfig(config, transport)
+				if err != nil {
+					return nil, err
+				}
 
-			// V2 Credentials
-			credsV2 := &credentials.Static{
-				Value: credentials.Value{
-					AccessKeyID:     config.AccessKey,
-					SecretAccessKey: config.SecretKey,
-					SessionToken:    "",
-					SignerType:      credentials.SignatureV2,
-				},
-			}
-			credsChain = append(credsChain, credsV2)
+				// V2 Credentials, except in place of an assumed role.
+				if _, ok := credsChain[len(credsChain)-1].(*assumeRole); !ok {
+					credsV2 := &credentials.Static{
+						Value: credentials.Value{
+							AccessKeyID:     config.AccessKey,
+							SecretAccessKey: config.SecretKey,
+							SessionToken:    "",
+							SignerType:      credentials.SignatureV2,
+						},
+					}
+					credsChain = append(credsChain, credsV2)
+				}
 
-			creds := credentials.NewChainCredentials(credsChain)
+				creds = credentials.NewChainCredentials(credsChain)
+			}
 
 			// Not found. Instantiate a new MinIO
 			var e error
@@ -319,9 +368,11 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 			options := minio.Options{
 				Creds:        creds,
 				Secure:       useTLS,
-				Region:       env.Get("MC_REGION", env.Get("AWS_REGION", "")),
+				Region:       env.Get("MC_REGION", env.Get("AWS_REGION", config.Region)),
 				BucketLookup: config.Lookup,
 				Transport:    transport,
+				// Trailing checksums are only sent with V4 signatures.
+				TrailingHeaders: s3Clnt.checksumAlgo == checksumAlgoCRC32C,
 			}
 
 			api, e = minio.New(hostName, &options)
@@ -339,10 +390,12 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 
 			// Cache the new MinIO Client with hash of config as key.
 			clientCache[confSum] = api
+			transportCache[confSum] = transport
 		}
 
 		// Store the new api object.
 		s3Clnt.api = api
+		s3Clnt.transport = transportCache[confSum]
 
 		return s3Clnt, nil
 	}
@@ -359,6 +412,9 @@ func (c *S3Client) GetURL() ClientURL {
 
 // AddNotificationConfig - Add bucket notification
 func (c *S3Client) AddNotificationConfig(ctx context.Context, arn string, events []string, prefix, suffix string, ignoreExisting bool) *probe.Error {
+	if err := c.unsupported("AddNotificationConfig", s3FeatureNotification); err != nil {
+		return err
+	}
 	bucket, _ := c.url2BucketAndObject()
 
 	accountArn, err := notification.NewArnFromString(arn)
@@ -430,6 +486,9 @@ func (c *S3Client) AddNotificationConfig(ctx context.Context, arn string, events
 
 // RemoveNotificationConfig - Remove bucket notification
 func (c *S3Client) RemoveNotificationConfig(ctx context.Context, arn, event, prefix, suffix string) *probe.Error {
+	if err := c.unsupported("RemoveNotificationConfig", s3FeatureNotification); err != nil {
+		return err
+	}
 	bucket, _ := c.url2BucketAndObject()
 	// Remove all notification configs if arn is empty
 	if arn == "" {
@@ -523,6 +582,9 @@ type NotificationConfig struct {
 
 // ListNotificationConfigs - List notification configs
 func (c *S3Client) ListNotificationConfigs(ctx context.Context, arn string) ([]NotificationConfig, *probe.Error) {
+	if err := c.unsupported("ListNotificationConfigs", s3FeatureNotification); err != nil {
+		return nil, err
+	}
 	var configs []NotificationConfig
 	bucket, _ := c.url2BucketAndObject()
 	mb, e := c.api.GetBucketNotification(ctx, bucket)
@@ -753,6 +815,9 @@ func selectCompressionType(selOpts SelectObjectOpts, object string) minio.Select
 
 // Select - select object content wrapper.
 func (c *S3Client) Select(ctx context.Context, expression string, sse encrypt.ServerSide, selOpts SelectObjectOpts) (io.ReadCloser, *probe.Error) {
+	if err := c.unsupported("Select", s3FeatureSelect); err != nil {
+		return nil, err
+	}
 	opts := minio.SelectObjectOptions{
 		Expression:     expression,
 		ExpressionType: minio.QueryExpressionTypeSQL,
@@ -852,6 +917,9 @@ func (c *S3Client) notificationToEventsInfo(ninfo notification.Info) []EventInfo
 
 // Watch - Start watching on all bucket events for a given account ID.
 func (c *S3Client) Watch(ctx context.Context, options WatchOptions) (*WatchObject, *probe.Error) {
+	if err := c.unsupported("Watch", s3FeatureNotification); err != nil {
+		return nil, err
+	}
 	// Extract bucket and object.
 	bucket, object := c.url2BucketAndObject()
 
@@ -951,8 +1019,8 @@ func (c *S3Client) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *Cl
 	if opts.Zip {
 		o.Set("x-minio-extract", "true")
 	}
-	if opts.RangeStart != 0 {
-		err := o.SetRange(opts.RangeStart, 0)
+	if opts.isRange() {
+		err := o.SetRange(opts.RangeStart, opts.rangeEnd())
 		if err != nil {
 			return nil, nil, probe.NewError(err)
 		}
@@ -960,25 +1028,20 @@ func (c *S3Client) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *Cl
 	// Disallow automatic decompression for some objects with content-encoding set.
 	o.Set("Accept-Encoding", "identity")
 
-	reader, e := c.api.GetObject(ctx, bucket, object, o)
-	if e != nil {
-		errResponse := minio.ToErrorResponse(e)
-		if errResponse.Code == "NoSuchBucket" {
-			return nil, nil, probe.NewError(BucketDoesNotExist{
-				Bucket: bucket,
-			})
-		}
-		if errResponse.Code == "InvalidBucketName" {
-			return nil, nil, probe.NewError(BucketInvalid{
-				Bucket: bucket,
-			})
-		}
-		if errResponse.Code == "NoSuchKey" {
-			return nil, nil, probe.NewError(ObjectMissing{})
+	var reader io.ReadCloser
+	var objStat minio.ObjectInfo
+	var e error
+	if opts.isRange() {
+		// Objects are read from their start once they were stat, a range
+		// is read with a single request instead.
+		reader, objStat, _, e = minio.Core{Client: c.api}.GetObject(ctx, bucket, object, o)
+	} else {
+		var obj *minio.Object
+		if obj, e = c.api.GetObject(ctx, bucket, object, o); e == nil {
+			reader = obj
+			objStat, e = obj.Stat()
 		}
-		return nil, nil, probe.NewError(e)
 	}
-	objStat, e := reader.Stat()
 	if e != nil {
 		errResponse := minio.ToErrorResponse(e)
 		if errResponse.Code == "NoSuchBucket" {
@@ -1053,6 +1116,18 @@ func (c *S3Client) Copy(ctx context.Context, source string, opts CopyOptions, pr
 		delete(metadata, AmzObjectLockLegalHold)
 	}
 
+	// Tags are replaced with the tags of the header, copies keep the tags
+	// of the source otherwise.
+	if tagsHdr, ok := metadata["X-Amz-Tagging"]; ok {
+		tagsSet, e := tags.Parse(tagsHdr, true)
+		if e != nil {
+			return probe.NewError(e)
+		}
+		destOpts.UserTags = tagsSet.ToMap()
+		destOpts.ReplaceTags = true
+		delete(metadata, "X-Amz-Tagging")
+	}
+
 	// Assign metadata after irrelevant parts are delete above
 	destOpts.UserMetadata = metadata
 	destOpts.ReplaceMetadata = len(metadata) > 0
@@ -1095,6 +1170,9 @@ func (c *S3Client) Put(ctx context.Context, reader io.Reader, size int64, progre
 	if bucket == "" {
 		return 0, probe.NewError(BucketNameEmpty{})
 	}
+	if err := c.checkObjectSize(size); err != nil {
+		return 0, err
+	}
 
 	metadata := make(map[string]string, len(putOpts.metadata))
 	for k, v := range putOpts.metadata {
@@ -1171,7 +1249,7 @@ func (c *S3Client) Put(ctx context.Context, reader io.Reader, size int64, progre
 		StorageClass:          strings.ToUpper(putOpts.storageClass),
 		ServerSideEncryption:  putOpts.sse,
 		SendContentMd5:        putOpts.md5,
-		DisableMultipart:      putOpts.disableMultipart,
+		DisableMultipart:      putOpts.disableMultipart || (size >= 0 && uint64(size) < putOpts.multipartThreshold),
 		PartSize:              putOpts.multipartSize,
 		NumThreads:            putOpts.multipartThreads,
 		ConcurrentStreamParts: putOpts.concurrentStream, // if enabled honors NumThreads for piped() uploads
@@ -1192,7 +1270,23 @@ func (c *S3Client) Put(ctx context.Context, reader io.Reader, size int64, progre
 		opts.SendContentMd5 = true
 	}
 
-	ui, e := c.api.PutObject(ctx, bucket, object, reader, size, opts)
+	// Uploads requiring an MD5 are never resumed, those of the endpoints
+	// checked with MD5 are as their parts are compared with the file.
+	resumable := !opts.SendContentMd5
+	if c.checksumAlgo == checksumAlgoMD5 {
+		opts.SendContentMd5 = true
+	}
+
+	var ui minio.UploadInfo
+	var e error
+	resumed := false
+	// A multipart upload of the file interrupted before is resumed.
+	if file, ok := reader.(*os.File); ok && isWholeFile(file, size) && !opts.DisableMultipart && opts.ServerSideEncryption == nil && resumable {
+		ui, resumed, e = c.resumeMultipartUpload(ctx, bucket, object, file, size, opts)
+	}
+	if !resumed {
+		ui, e = c.api.PutObject(ctx, bucket, object, reader, size, opts)
+	}
 	if e != nil {
 		errResponse := minio.ToErrorResponse(e)
 		if errResponse.Code == "UnexpectedEOF" || e == io.EOF {
@@ -1502,6 +1596,12 @@ func (c *S3Client) MakeBucket(ctx context.Context, region string, ignoreExisting
 		}
 	}
 
+	if withLock {
+		if err := c.unsupported("MakeBucket --with-lock", s3FeatureObjectLock); err != nil {
+			return err
+		}
+	}
+
 	var e error
 	opts := minio.MakeBucketOptions{Region: region, ObjectLocking: withLock}
 	if e = c.api.MakeBucket(ctx, bucket, opts); e != nil {
@@ -1538,6 +1638,9 @@ func (c *S3Client) RemoveBucket(ctx context.Context, forceRemove bool) *probe.Er
 
 // GetAccessRules - get configured policies from the server
 func (c *S3Client) GetAccessRules(ctx context.Context) (map[string]string, *probe.Error) {
+	if err := c.unsupported("GetAccessRules", s3FeatureBucketPolicy); err != nil {
+		return nil, err
+	}
 	bucket, object := c.url2BucketAndObject()
 	if bucket == "" {
 		return map[string]string{}, probe.NewError(BucketNameEmpty{})
@@ -1564,6 +1667,9 @@ func (c *S3Client) GetAccessRules(ctx context.Context) (map[string]string, *prob
 
 // GetAccess get access policy permissions.
 func (c *S3Client) GetAccess(ctx context.Context) (string, string, *probe.Error) {
+	if err := c.unsupported("GetAccess", s3FeatureBucketPolicy); err != nil {
+		return "", "", err
+	}
 	bucket, object := c.url2BucketAndObject()
 	if bucket == "" {
 		return "", "", probe.NewError(BucketNameEmpty{})
@@ -1588,6 +1694,9 @@ func (c *S3Client) GetAccess(ctx context.Context) (string, string, *probe.Error)
 
 // SetAccess set access policy permissions.
 func (c *S3Client) SetAccess(ctx context.Context, bucketPolicy string, isJSON bool) *probe.Error {
+	if err := c.unsupported("SetAccess", s3FeatureBucketPolicy); err != nil {
+		return err
+	}
 	bucket, object := c.url2BucketAndObject()
 	if bucket == "" {
 		return probe.NewError(BucketNameEmpty{})
@@ -2302,6 +2411,7 @@ func (c *S3Client) objectInfo2ClientContent(bucket string, entry minio.ObjectInf
 	content.ExpirationRuleID = entry.ExpirationRuleID
 	content.VersionID = entry.VersionID
 	content.StorageClass = entry.StorageClass
+	content.ContentType = entry.ContentType
 	content.IsDeleteMarker = entry.IsDeleteMarker
 	content.IsLatest = entry.IsLatest
 	content.Restore = entry.Restore
@@ -2441,8 +2551,7 @@ func (c *S3Client) listRecursiveInRoutine(ctx context.Context, contentCh chan *C
 				contentCh <- c.bucketInfo2ClientContent(bucket)
 			}
 
-			isRecursive := true
-			for object := range c.listObjectWrapper(ctx, bucket.Name, o, isRecursive, time.Time{}, false, false, opts.WithMetadata, -1, opts.ListZip) {
+			for object := range c.listObjectsRecursive(ctx, bucket.Name, o, opts) {
 				if object.Err != nil {
 					contentCh <- &ClientContent{
 						Err: probe.NewError(object.Err),
@@ -2457,8 +2566,7 @@ func (c *S3Client) listRecursiveInRoutine(ctx context.Context, contentCh chan *C
 			}
 		}
 	default:
-		isRecursive := true
-		for object := range c.listObjectWrapper(ctx, b, o, isRecursive, time.Time{}, false, false, opts.WithMetadata, -1, opts.ListZip) {
+		for object := range c.listObjectsRecursive(ctx, b, o, opts) {
 			if object.Err != nil {
 				contentCh <- &ClientContent{
 					Err: probe.NewError(object.Err),
@@ -2517,6 +2625,9 @@ func (c *S3Client) ShareUpload(ctx context.Context, isRecursive bool, expires ti
 
 // SetObjectLockConfig - Set object lock configurataion of bucket.
 func (c *S3Client) SetObjectLockConfig(ctx context.Context, mode minio.RetentionMode, validity uint64, unit minio.ValidityUnit) *probe.Error {
+	if err := c.unsupported("SetObjectLockConfig", s3FeatureObjectLock); err != nil {
+		return err
+	}
 	bucket, object := c.url2BucketAndObject()
 
 	if bucket == "" || object != "" {
@@ -2544,6 +2655,9 @@ func (c *S3Client) SetObjectLockConfig(ctx context.Context, mode minio.Retention
 
 // PutObjectRetention - Set object retention for a given object.
 func (c *S3Client) PutObjectRetention(ctx context.Context, versionID string, mode minio.RetentionMode, retainUntilDate time.Time, bypassGovernance bool) *probe.Error {
+	if err := c.unsupported("PutObjectRetention", s3FeatureObjectLock); err != nil {
+		return err
+	}
 	bucket, object := c.url2BucketAndObject()
 
 	var (
@@ -2575,6 +2689,9 @@ func (c *S3Client) PutObjectRetention(ctx context.Context, versionID string, mod
 
 // GetObjectRetention - Get object retention for a given object.
 func (c *S3Client) GetObjectRetention(ctx context.Context, versionID string) (minio.RetentionMode, time.Time, *probe.Error) {
+	if err := c.unsupported("GetObjectRetention", s3FeatureObjectLock); err != nil {
+		return "", time.Time{}, err
+	}
 	bucket, object := c.url2BucketAndObject()
 	if object == "" {
 		return "", time.Time{}, probe.NewError(ObjectNameEmpty{}).Trace(c.GetURL().String())
@@ -2598,6 +2715,9 @@ func (c *S3Client) GetObjectRetention(ctx context.Context, versionID string) (mi
 
 // PutObjectLegalHold - Set object legal hold for a given object.
 func (c *S3Client) PutObjectLegalHold(ctx context.Context, versionID string, lhold minio.LegalHoldStatus) *probe.Error {
+	if err := c.unsupported("PutObjectLegalHold", s3FeatureObjectLock); err != nil {
+		return err
+	}
 	bucket, object := c.url2BucketAndObject()
 	if lhold.IsValid() {
 		opts := minio.PutObjectLegalHoldOptions{
@@ -2615,6 +2735,9 @@ func (c *S3Client) PutObjectLegalHold(ctx context.Context, versionID string, lho
 
 // GetObjectLegalHold - Get object legal hold for a given object.
 func (c *S3Client) GetObjectLegalHold(ctx context.Context, versionID string) (minio.LegalHoldStatus, *probe.Error) {
+	if err := c.unsupported("GetObjectLegalHold", s3FeatureObjectLock); err != nil {
+		return "", err
+	}
 	var lhold minio.LegalHoldStatus
 	bucket, object := c.url2BucketAndObject()
 	opts := minio.GetObjectLegalHoldOptions{
@@ -2637,6 +2760,9 @@ func (c *S3Client) GetObjectLegalHold(ctx context.Context, versionID string) (mi
 
 // GetObjectLockConfig - Get object lock configuration of bucket.
 func (c *S3Client) GetObjectLockConfig(ctx context.Context) (string, minio.RetentionMode, uint64, minio.ValidityUnit, *probe.Error) {
+	if err := c.unsupported("GetObjectLockConfig", s3FeatureObjectLock); err != nil {
+		return "", "", 0, "", err
+	}
 	bucket, object := c.url2BucketAndObject()
 
 	if bucket == "" || object != "" {
@@ -2659,6 +2785,9 @@ func (c *S3Client) GetObjectLockConfig(ctx context.Context) (string, minio.Reten
 
 // GetTags - Get tags of bucket or object.
 func (c *S3Client) GetTags(ctx context.Context, versionID string) (map[string]string, *probe.Error) {
+	if err := c.unsupported("GetTags", s3FeatureTagging); err != nil {
+		return nil, err
+	}
 	bucketName, objectName := c.url2BucketAndObject()
 	if bucketName == "" {
 		return nil, probe.NewError(BucketNameEmpty{})
@@ -2687,6 +2816,9 @@ func (c *S3Client) GetTags(ctx context.Context, versionID string) (map[string]st
 
 // SetTags - Set tags of bucket or object.
 func (c *S3Client) SetTags(ctx context.Context, versionID, tagString string) *probe.Error {
+	if err := c.unsupported("SetTags", s3FeatureTagging); err != nil {
+		return err
+	}
 	bucketName, objectName := c.url2BucketAndObject()
 	if bucketName == "" {
 		return probe.NewError(BucketNameEmpty{})
@@ -2715,6 +2847,9 @@ func (c *S3Client) SetTags(ctx context.Context, versionID, tagString string) *pr
 
 // DeleteTags - Delete tags of bucket or object
 func (c *S3Client) DeleteTags(ctx context.Context, versionID string) *probe.Error {
+	if err := c.unsupported("DeleteTags", s3FeatureTagging); err != nil {
+		return err
+	}
 	bucketName, objectName := c.url2BucketAndObject()
 	if bucketName == "" {
 		return probe.NewError(BucketNameEmpty{})
@@ -2739,6 +2874,9 @@ func (c *S3Client) DeleteTags(ctx context.Context, versionID string) *probe.Erro
 
 // GetLifecycle - Get current lifecycle configuration.
 func (c *S3Client) GetLifecycle(ctx context.Context) (*lifecycle.Configuration, time.Time, *probe.Error) {
+	if err := c.unsupported("GetLifecycle", s3FeatureLifecycle); err != nil {
+		return nil, time.Time{}, err
+	}
 	bucket, _ := c.url2BucketAndObject()
 	if bucket == "" {
 		return nil, time.Time{}, probe.NewError(BucketNameEmpty{})
@@ -2754,6 +2892,9 @@ func (c *S3Client) GetLifecycle(ctx context.Context) (*lifecycle.Configuration,
 
 // SetLifecycle - Set lifecycle configuration on a bucket
 func (c *S3Client) SetLifecycle(ctx context.Context, config *lifecycle.Configuration) *probe.Error {
+	if err := c.unsupported("SetLifecycle", s3FeatureLifecycle); err != nil {
+		return err
+	}
 	bucket, _ := c.url2BucketAndObject()
 	if bucket == "" {
 		return probe.NewError(BucketNameEmpty{})
@@ -2768,6 +2909,9 @@ func (c *S3Client) SetLifecycle(ctx context.Context, config *lifecycle.Configura
 
 // GetVersion - gets bucket version info.
 func (c *S3Client) GetVersion(ctx context.Context) (config minio.BucketVersioningConfiguration, err *probe.Error) {
+	if err = c.unsupported("GetVersion", s3FeatureVersioning); err != nil {
+		return config, err
+	}
 	bucket, _ := c.url2BucketAndObject()
 	if bucket == "" {
 		return config, probe.NewError(BucketNameEmpty{})
@@ -2783,6 +2927,9 @@ func (c *S3Client) GetVersion(ctx context.Context) (config minio.BucketVersionin
 
 // SetVersion - Set version configuration on a bucket
 func (c *S3Client) SetVersion(ctx context.Context, status string, prefixes []string, excludeFolders bool) *probe.Error {
+	if err := c.unsupported("SetVersion", s3FeatureVersioning); err != nil {
+		return err
+	}
 	bucket, _ := c.url2BucketAndObject()
 	if bucket == "" {
 		return probe.NewError(BucketNameEmpty{})
@@ -2817,6 +2964,9 @@ func (c *S3Client) SetVersion(ctx context.Context, status string, prefixes []str
 
 // GetReplication - gets replication configuration for a given bucket.
 func (c *S3Client) GetReplication(ctx context.Context) (replication.Config, *probe.Error) {
+	if err := c.unsupported("GetReplication", s3FeatureReplication); err != nil {
+		return replication.Config{}, err
+	}
 	bucket, _ := c.url2BucketAndObject()
 	if bucket == "" {
 		return replication.Config{}, probe.NewError(BucketNameEmpty{})
@@ -2831,6 +2981,9 @@ func (c *S3Client) GetReplication(ctx context.Context) (replication.Config, *pro
 
 // RemoveReplication - removes replication configuration for a given bucket.
 func (c *S3Client) RemoveReplication(ctx context.Context) *probe.Error {
+	if err := c.unsupported("RemoveReplication", s3FeatureReplication); err != nil {
+		return err
+	}
 	bucket, _ := c.url2BucketAndObject()
 	if bucket == "" {
 		return probe.NewError(BucketNameEmpty{})
@@ -2842,6 +2995,9 @@ func (c *S3Client) RemoveReplication(ctx context.Context) *probe.Error {
 
 // SetReplication sets replication configuration for a given bucket.
 func (c *S3Client) SetReplication(ctx context.Context, cfg *replication.Config, opts replication.Options) *probe.Error {
+	if err := c.unsupported("SetReplication", s3FeatureReplication); err != nil {
+		return err
+	}
 	bucket, objectPrefix := c.url2BucketAndObject()
 	if bucket == "" {
 		return probe.NewError(BucketNameEmpty{})
@@ -2872,6 +3028,9 @@ func (c *S3Client) SetReplication(ctx context.Context, cfg *replication.Config,
 
 // GetReplicationMetrics - Get replication metrics for a given bucket.
 func (c *S3Client) GetReplicationMetrics(ctx context.Context) (replication.MetricsV2, *probe.Error) {
+	if err := c.unsupported("GetReplicationMetrics", s3FeatureReplication); err != nil {
+		return replication.MetricsV2{}, err
+	}
 	bucket, _ := c.url2BucketAndObject()
 	if bucket == "" {
 		return replication.MetricsV2{}, probe.NewError(BucketNameEmpty{})
@@ -2887,6 +3046,9 @@ func (c *S3Client) GetReplicationMetrics(ctx context.Context) (replication.Metri
 // ResetReplication - kicks off replication again on previously replicated objects if existing object
 // replication is enabled in the replication config.Optional to provide a timestamp
 func (c *S3Client) ResetReplication(ctx context.Context, before time.Duration, tgtArn string) (rinfo replication.ResyncTargetsInfo, err *probe.Error) {
+	if err = c.unsupported("ResetReplication", s3FeatureReplication); err != nil {
+		return rinfo, err
+	}
 	bucket, _ := c.url2BucketAndObject()
 	if bucket == "" {
 		return rinfo, probe.NewError(BucketNameEmpty{})
@@ -2901,6 +3063,9 @@ func (c *S3Client) ResetReplication(ctx context.Context, before time.Duration, t
 
 // ReplicationResyncStatus - gets status of replication resync for this target arn
 func (c *S3Client) ReplicationResyncStatus(ctx context.Context, arn string) (rinfo replication.ResyncTargetsInfo, err *probe.Error) {
+	if err = c.unsupported("ReplicationResyncStatus", s3FeatureReplication); err != nil {
+		return rinfo, err
+	}
 	bucket, _ := c.url2BucketAndObject()
 	if bucket == "" {
 		return rinfo, probe.NewError(BucketNameEmpty{})
@@ -2915,6 +3080,9 @@ func (c *S3Client) ReplicationResyncStatus(ctx context.Context, arn string) (rin
 
 // GetEncryption - gets bucket encryption info.
 func (c *S3Client) GetEncryption(ctx context.Context) (algorithm, keyID string, err *probe.Error) {
+	if err = c.unsupported("GetEncryption", s3FeatureEncryption); err != nil {
+		return "", "", err
+	}
 	bucket, _ := c.url2BucketAndObject()
 	if bucket == "" {
 		return "", "", probe.NewError(BucketNameEmpty{})
@@ -2936,6 +3104,9 @@ func (c *S3Client) GetEncryption(ctx context.Context) (algorithm, keyID string,
 
 // SetEncryption - Set encryption configuration on a bucket
 func (c *S3Client) SetEncryption(ctx context.Context, encType, kmsKeyID string) *probe.Error {
+	if err := c.unsupported("SetEncryption", s3FeatureEncryption); err != nil {
+		return err
+	}
 	bucket, _ := c.url2BucketAndObject()
 	if bucket == "" {
 		return probe.NewError(BucketNameEmpty{})
@@ -2957,6 +3128,9 @@ func (c *S3Client) SetEncryption(ctx context.Context, encType, kmsKeyID string)
 
 // DeleteEncryption - removes encryption configuration on a bucket
 func (c *S3Client) DeleteEncryption(ctx context.Context) *probe.Error {
+	if err := c.unsupported("DeleteEncryption", s3FeatureEncryption); err != nil {
+		return err
+	}
 	bucket, _ := c.url2BucketAndObject()
 	if bucket == "" {
 		return probe.NewError(BucketNameEmpty{})
@@ -2967,6 +3141,22 @@ func (c *S3Client) DeleteEncryption(ctx context.Context) *probe.Error {
 	return nil
 }
 
+// bucketCreationDate looks up the creation date of the bucket, which S3
+// only reports when listing all buckets of the account.
+func (c *S3Client) bucketCreationDate(ctx context.Context) (time.Time, *probe.Error) {
+	bucket, _ := c.url2BucketAndObject()
+	buckets, e := c.api.ListBuckets(ctx)
+	if e != nil {
+		return time.Time{}, probe.NewError(e)
+	}
+	for _, bi := range buckets {
+		if bi.Name == bucket {
+			return bi.CreationDate, nil
+		}
+	}
+	return time.Time{}, probe.NewError(BucketDoesNotExist{Bucket: bucket})
+}
+
 // GetBucketInfo gets info about a bucket
 func (c *S3Client) GetBucketInfo(ctx context.Context) (BucketInfo, *probe.Error) {
 	var b BucketInfo
@@ -2990,7 +3180,7 @@ func (c *S3Client) GetBucketInfo(ctx context.Context) (BucketInfo, *probe.Error)
 		b.Versioning.Status = vcfg.Status
 		b.Versioning.MFADelete = vcfg.MFADelete
 	}
-	if enabled, mode, validity, unit, err := c.api.GetObjectLockConfig(ctx, bucket); err == nil {
+	if enabled, mode, validity, unit, err := c.getObjectLockConfig(ctx, bucket); err == nil {
 		if mode != nil {
 			b.Locking.Mode = *mode
 		}
@@ -3026,14 +3216,43 @@ func (c *S3Client) GetBucketInfo(ctx context.Context) (BucketInfo, *probe.Error)
 	if lfc, _, err := c.GetLifecycle(ctx); err == nil {
 		b.ILM.Config = lfc
 	}
-	if nfc, err := c.api.GetBucketNotification(ctx, bucket); err == nil {
+	if nfc, err := c.getBucketNotification(ctx, bucket); err == nil {
 		b.Notification.Config = nfc
 	}
 	return b, nil
 }
 
+// getObjectLockConfig - returns the raw object lock configuration of the
+// bucket, without a request when the provider does not implement locking.
+func (c *S3Client) getObjectLockConfig(ctx context.Context, bucket string) (string, *minio.RetentionMode, *uint, *minio.ValidityUnit, *probe.Error) {
+	if err := c.unsupported("GetObjectLockConfig", s3FeatureObjectLock); err != nil {
+		return "", nil, nil, nil, err
+	}
+	enabled, mode, validity, unit, e := c.api.GetObjectLockConfig(ctx, bucket)
+	if e != nil {
+		return "", nil, nil, nil, probe.NewError(e)
+	}
+	return enabled, mode, validity, unit, nil
+}
+
+// getBucketNotification - returns the notification configuration of the
+// bucket, without a request when the provider does not implement it.
+func (c *S3Client) getBucketNotification(ctx context.Context, bucket string) (notification.Configuration, *probe.Error) {
+	if err := c.unsupported("GetBucketNotification", s3FeatureNotification); err != nil {
+		return notification.Configuration{}, err
+	}
+	nfc, e := c.api.GetBucketNotification(ctx, bucket)
+	if e != nil {
+		return notification.Configuration{}, probe.NewError(e)
+	}
+	return nfc, nil
+}
+
 // Restore gets a copy of an archived object
 func (c *S3Client) Restore(ctx context.Context, versionID string, days int) *probe.Error {
+	if err := c.unsupported("Restore", s3FeatureRestore); err != nil {
+		return err
+	}
 	bucket, object := c.url2BucketAndObject()
 	if bucket == "" {
 		return probe.NewError(BucketNameEmpty{})
@@ -26,6 +26,7 @@ import (
 	"net/http/httptest"
 	"strconv"
 
+	"github.com/dustin/go-humanize"
 	minio "github.com/minio/minio-go/v7"
 	checkv1 "gopkg.in/check.v1"
 )
@@ -299,3 +300,60 @@ func (s *TestSuite) TestSelectCompressionType(c *checkv1.C) {
 		c.Assert(cType, checkv1.DeepEquals, test.compressionType)
 	}
 }
+
+// TestProviderCapabilities - tests that APIs missing on a provider fail
+// without sending any request.
+func (s *TestSuite) TestProviderCapabilities(c *checkv1.C) {
+	conf := new(Config)
+	conf.HostURL = "https://account.r2.cloudflarestorage.com/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := S3New(conf)
+	c.Assert(err, checkv1.IsNil)
+
+	err = s3c.SetVersion(context.Background(), "enable", nil, false)
+	c.Assert(err, checkv1.NotNil)
+	c.Assert(err.ToGoError(), checkv1.DeepEquals, APINotImplemented{API: "SetVersion", APIType: "Cloudflare R2"})
+
+	_, err = s3c.Put(context.Background(), bytes.NewReader(nil), 5*humanize.TiByte, nil, PutOptions{})
+	c.Assert(err, checkv1.NotNil)
+
+	c.Assert(getS3Capabilities("", "s3.amazonaws.com"), checkv1.IsNil)
+	c.Assert(getS3Capabilities("wasabi", "s3.example.com").supports(s3FeatureLifecycle), checkv1.Equals, false)
+	c.Assert(getS3Capabilities("", "nyc3.digitaloceanspaces.com").supports(s3FeatureVersioning), checkv1.Equals, true)
+}
+
+// TestCopyTags - tests that server side copies replace the tags of the
+// source only with the tags of the metadata.
+func (s *TestSuite) TestCopyTags(c *checkv1.C) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		response := []byte("<CopyObjectResult><LastModified>2015-05-21T18:24:21.097Z</LastModified><ETag>\"9af2f8218b150c351ad802c6f3d66abe\"</ETag></CopyObjectResult>")
+		w.Header().Set("Content-Length", strconv.Itoa(len(response)))
+		w.Write(response)
+	}))
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/copy"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := S3New(conf)
+	c.Assert(err, checkv1.IsNil)
+
+	err = s3c.Copy(context.Background(), "/bucket/object", CopyOptions{size: 12}, nil)
+	c.Assert(err, checkv1.IsNil)
+	c.Assert(header.Get("X-Amz-Tagging-Directive"), checkv1.Equals, "")
+
+	err = s3c.Copy(context.Background(), "/bucket/object", CopyOptions{
+		size:     12,
+		metadata: map[string]string{"X-Amz-Tagging": "project=mc&team=storage"},
+	}, nil)
+	c.Assert(err, checkv1.IsNil)
+	c.Assert(header.Get("X-Amz-Tagging-Directive"), checkv1.Equals, "REPLACE")
+	c.Assert(header.Get("X-Amz-Tagging"), checkv1.Equals, "project=mc&team=storage")
+	c.Assert(header.Get("X-Amz-Meta-X-Amz-Tagging"), checkv1.Equals, "")
+}
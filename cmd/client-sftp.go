@@ -0,0 +1,743 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/deadlineconn"
+	"github.com/minio/mc/pkg/hookreader"
+	"github.com/minio/mc/pkg/limiter"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/minio/minio-go/v7/pkg/replication"
+	"github.com/minio/pkg/v2/env"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	// API value of the aliases implied by sftp:// URLs.
+	sftpAPI = "sftp"
+
+	// SFTP URLs of the form sftp://USER@HOST[:PORT]/PATH address a
+	// remote server directly, no alias is needed. Paths are absolute,
+	// /~/ is the home directory of USER.
+	sftpScheme    = "sftp"
+	sftpURLScheme = sftpScheme + "://"
+
+	sftpDefaultPort = "22"
+	sftpDialTimeout = 30 * time.Second
+)
+
+// SSH connections by USER@HOST:PORT, shared by all clients since a
+// client is created for every file copied.
+var (
+	sftpConnsMu sync.Mutex
+	sftpConns   = map[string]*sftp.Client{}
+)
+
+// sftpClient - SFTP client, the remote file system is a single bucket
+// less namespace like a local file system.
+type sftpClient struct {
+	targetURL *ClientURL
+	conn      *sftp.Client
+}
+
+// sftpNew - instantiates a new SFTP client, connecting to the server
+// unless a connection to it is already open.
+func sftpNew(config *Config) (Client, *probe.Error) {
+	u, e := url.Parse(config.HostURL)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, probe.NewError(fmt.Errorf("a user name is required in `%s`, e.g. sftp://user@host/path", config.HostURL))
+	}
+	conn, err := sftpConnect(config.Alias, u.User.Username(), u.Host)
+	if err != nil {
+		return nil, err.Trace(config.HostURL)
+	}
+	return &sftpClient{
+		targetURL: newClientURL(config.HostURL),
+		conn:      conn,
+	}, nil
+}
+
+// expandSFTPURL - sftp:// URLs are their own alias, the alias
+// configuration holds the user and server address.
+func expandSFTPURL(sftpURL string) (alias, urlStr string, aliasCfg *aliasConfigV10, err *probe.Error) {
+	authority := splitStr(strings.TrimPrefix(sftpURL, sftpURLScheme), "/", 2)[0]
+	if authority == "" {
+		return "", "", nil, probe.NewError(fmt.Errorf("invalid SFTP URL `%s`, expected sftp://user@host/path", sftpURL))
+	}
+	alias = sftpURLScheme + authority
+	return alias, sftpURL, &aliasConfigV10{URL: alias, API: sftpAPI}, nil
+}
+
+// sftpConnect - returns the cached connection of user to the server at
+// addr, dialing a new one when none is open. Its traffic is accounted to
+// alias.
+func sftpConnect(alias, user, addr string) (*sftp.Client, *probe.Error) {
+	if _, _, e := net.SplitHostPort(addr); e != nil {
+		addr = net.JoinHostPort(addr, sftpDefaultPort)
+	}
+	key := user + "@" + addr
+
+	sftpConnsMu.Lock()
+	defer sftpConnsMu.Unlock()
+	if conn, ok := sftpConns[key]; ok {
+		return conn, nil
+	}
+
+	hostKeyCallback, err := sftpHostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+	netConn, e := net.DialTimeout("tcp", addr, sftpDialTimeout)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	// SSH connections are limited like the HTTP transports.
+	netConn = limiter.NewConn(netConn, int64(globalLimitUpload), int64(globalLimitDownload))
+	if alias != "" {
+		netConn = newUsageConn(alias, netConn)
+	}
+	if globalIdleTimeout > 0 {
+		netConn = deadlineconn.New(netConn).
+			WithReadDeadline(globalIdleTimeout).
+			WithWriteDeadline(globalIdleTimeout)
+	}
+	sshConn, chans, reqs, e := ssh.NewClientConn(netConn, addr, &ssh.ClientConfig{
+		User:            user,
+		Auth:            sftpAuthMethods(),
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         sftpDialTimeout,
+	})
+	if e != nil {
+		netConn.Close()
+		return nil, probe.NewError(e)
+	}
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+	conn, e := sftp.NewClient(sshClient)
+	if e != nil {
+		sshClient.Close()
+		return nil, probe.NewError(e)
+	}
+	sftpConns[key] = conn
+	return conn, nil
+}
+
+// sftpHostKeyCallback - verifies server keys with the known_hosts file
+// of the user, or accepts any key with --insecure.
+func sftpHostKeyCallback() (ssh.HostKeyCallback, *probe.Error) {
+	if globalInsecure {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	knownHosts := env.Get("MC_SFTP_KNOWN_HOSTS", "")
+	if knownHosts == "" {
+		home, e := os.UserHomeDir()
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		knownHosts = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	callback, e := knownhosts.New(knownHosts)
+	if e != nil {
+		return nil, probe.NewError(fmt.Errorf("unable to load known hosts, use --insecure to skip host key verification: %w", e))
+	}
+	return callback, nil
+}
+
+// sftpAuthMethods - authenticates with the SSH agent, the identity file
+// set with MC_SFTP_IDENTITY or the default identity files, and finally
+// the password set with MC_SFTP_PASSWORD.
+func sftpAuthMethods() []ssh.AuthMethod {
+	var methods []ssh.AuthMethod
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if agentConn, e := net.Dial("unix", sock); e == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(agentConn).Signers))
+		}
+	}
+
+	var identities []string
+	if identity := env.Get("MC_SFTP_IDENTITY", ""); identity != "" {
+		identities = []string{identity}
+	} else if home, e := os.UserHomeDir(); e == nil {
+		for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+			identities = append(identities, filepath.Join(home, ".ssh", name))
+		}
+	}
+	var signers []ssh.Signer
+	for _, identity := range identities {
+		data, e := os.ReadFile(identity)
+		if e != nil {
+			continue
+		}
+		// Keys protected by a passphrase are expected to be in the agent.
+		if signer, e := ssh.ParsePrivateKey(data); e == nil {
+			signers = append(signers, signer)
+		}
+	}
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+
+	if password := env.Get("MC_SFTP_PASSWORD", ""); password != "" {
+		methods = append(methods, ssh.Password(password))
+	}
+	return methods
+}
+
+// remotePath - returns the server path of a URL path, resolving /~/ to
+// the working directory of the session.
+func (c *sftpClient) remotePath(urlPath string) string {
+	if urlPath == "/~" || strings.HasPrefix(urlPath, "/~/") {
+		if wd, e := c.conn.Getwd(); e == nil {
+			return path.Join(wd, strings.TrimPrefix(urlPath, "/~"))
+		}
+	}
+	if urlPath == "" {
+		return "/"
+	}
+	return urlPath
+}
+
+// toError - maps SFTP status errors to client errors.
+func (c *sftpClient) toError(e error, p string) *probe.Error {
+	switch {
+	case errors.Is(e, os.ErrNotExist):
+		return probe.NewError(PathNotFound{Path: p})
+	case errors.Is(e, os.ErrPermission):
+		return probe.NewError(PathInsufficientPermission{Path: p})
+	}
+	return probe.NewError(e)
+}
+
+// contentURL - returns the URL of the entry name under the URL path dir.
+func (c *sftpClient) contentURL(dir, name string) ClientURL {
+	u := c.targetURL.Clone()
+	u.Path = path.Join(dir, name)
+	return u
+}
+
+func (c *sftpClient) fileInfo2ClientContent(u ClientURL, fi os.FileInfo) *ClientContent {
+	if fi.IsDir() && !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return &ClientContent{
+		URL:  u,
+		Time: fi.ModTime(),
+		Size: fi.Size(),
+		Type: fi.Mode(),
+	}
+}
+
+// readDir - returns the entries of dir in the lexical order of their
+// paths, directories sort as if they ended with a separator.
+func (c *sftpClient) readDir(dir string) ([]os.FileInfo, *probe.Error) {
+	entries, e := c.conn.ReadDir(c.remotePath(dir))
+	if e != nil {
+		return nil, c.toError(e, dir)
+	}
+	sortName := func(fi os.FileInfo) string {
+		if fi.IsDir() {
+			return fi.Name() + "/"
+		}
+		return fi.Name()
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return sortName(entries[i]) < sortName(entries[j])
+	})
+	return entries, nil
+}
+
+// walk - sends the files under dir recursively in lexical order, and the
+// directories as requested by showDir.
+func (c *sftpClient) walk(ctx context.Context, dir string, showDir DirOpt, contentCh chan<- *ClientContent) bool {
+	entries, err := c.readDir(dir)
+	if err != nil {
+		return sendSFTPContent(ctx, contentCh, &ClientContent{Err: err})
+	}
+	for _, fi := range entries {
+		content := c.fileInfo2ClientContent(c.contentURL(dir, fi.Name()), fi)
+		if !fi.IsDir() {
+			if !sendSFTPContent(ctx, contentCh, content) {
+				return false
+			}
+			continue
+		}
+		if showDir == DirFirst && !sendSFTPContent(ctx, contentCh, content) {
+			return false
+		}
+		if !c.walk(ctx, content.URL.Path, showDir, contentCh) {
+			return false
+		}
+		if showDir == DirLast && !sendSFTPContent(ctx, contentCh, content) {
+			return false
+		}
+	}
+	return true
+}
+
+// sendSFTPContent - sends content unless ctx is canceled, returns false
+// when the listing must stop.
+func sendSFTPContent(ctx context.Context, contentCh chan<- *ClientContent, content *ClientContent) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case contentCh <- content:
+		return content.Err == nil
+	}
+}
+
+// Stat - returns the properties of a file or directory.
+func (c *sftpClient) Stat(_ context.Context, _ StatOptions) (*ClientContent, *probe.Error) {
+	fi, e := c.conn.Stat(c.remotePath(c.targetURL.Path))
+	if e != nil {
+		return nil, c.toError(e, c.targetURL.Path)
+	}
+	content := c.fileInfo2ClientContent(c.targetURL.Clone(), fi)
+	content.ContentType = guessURLContentType(c.targetURL.Path)
+	content.Metadata = map[string]string{
+		"Content-Type": content.ContentType,
+	}
+	return content, nil
+}
+
+// List - lists files and directories, paths which do not exist are
+// listed as a prefix of the entries of their parent directory.
+func (c *sftpClient) List(ctx context.Context, opts ListOptions) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		if opts.Incomplete || opts.ListZip {
+			return
+		}
+
+		target := c.targetURL.Path
+		fi, e := c.conn.Stat(c.remotePath(target))
+		switch {
+		case e == nil && !fi.IsDir():
+			sendSFTPContent(ctx, contentCh, c.fileInfo2ClientContent(c.targetURL.Clone(), fi))
+			return
+		case e == nil && (opts.Recursive || strings.HasSuffix(target, "/")):
+			if opts.Recursive {
+				c.walk(ctx, target, opts.ShowDir, contentCh)
+				return
+			}
+			entries, err := c.readDir(target)
+			if err != nil {
+				sendSFTPContent(ctx, contentCh, &ClientContent{Err: err})
+				return
+			}
+			for _, fi := range entries {
+				if !sendSFTPContent(ctx, contentCh, c.fileInfo2ClientContent(c.contentURL(target, fi.Name()), fi)) {
+					return
+				}
+			}
+			return
+		case e != nil && !errors.Is(e, os.ErrNotExist):
+			sendSFTPContent(ctx, contentCh, &ClientContent{Err: c.toError(e, target)})
+			return
+		}
+
+		// A directory without a trailing separator or a prefix.
+		dir := path.Dir(target)
+		entries, err := c.readDir(dir)
+		if err != nil {
+			if !errors.As(err.ToGoError(), &PathNotFound{}) {
+				sendSFTPContent(ctx, contentCh, &ClientContent{Err: err})
+			}
+			return
+		}
+		for _, fi := range entries {
+			content := c.fileInfo2ClientContent(c.contentURL(dir, fi.Name()), fi)
+			if !strings.HasPrefix(content.URL.Path, target) {
+				continue
+			}
+			if fi.IsDir() && opts.Recursive {
+				if !c.walk(ctx, content.URL.Path, opts.ShowDir, contentCh) {
+					return
+				}
+				continue
+			}
+			if !sendSFTPContent(ctx, contentCh, content) {
+				return
+			}
+		}
+	}()
+	return contentCh
+}
+
+// MakeBucket - creates the directory and its parents.
+func (c *sftpClient) MakeBucket(_ context.Context, _ string, ignoreExisting, withLock bool) *probe.Error {
+	if withLock {
+		return c.notImplemented("MakeBucket with object lock")
+	}
+	p := c.remotePath(c.targetURL.Path)
+	if !ignoreExisting {
+		if _, e := c.conn.Stat(p); e == nil {
+			return probe.NewError(BucketExists{Bucket: c.targetURL.Path})
+		}
+	}
+	if e := c.conn.MkdirAll(p); e != nil {
+		return c.toError(e, c.targetURL.Path)
+	}
+	return nil
+}
+
+// RemoveBucket - removes the directory, and its contents with forceRemove.
+func (c *sftpClient) RemoveBucket(_ context.Context, forceRemove bool) *probe.Error {
+	p := c.remotePath(c.targetURL.Path)
+	var e error
+	if forceRemove {
+		e = c.conn.RemoveAll(p)
+	} else {
+		e = c.conn.RemoveDirectory(p)
+	}
+	if e != nil {
+		return c.toError(e, c.targetURL.Path)
+	}
+	return nil
+}
+
+// ListBuckets - lists the directories of the target directory.
+func (c *sftpClient) ListBuckets(_ context.Context) ([]*ClientContent, *probe.Error) {
+	entries, err := c.readDir(c.targetURL.Path)
+	if err != nil {
+		return nil, err
+	}
+	var contents []*ClientContent
+	for _, fi := range entries {
+		if fi.IsDir() {
+			contents = append(contents, c.fileInfo2ClientContent(c.contentURL(c.targetURL.Path, fi.Name()), fi))
+		}
+	}
+	return contents, nil
+}
+
+// Get - returns the file data of the range of opts.
+func (c *sftpClient) Get(_ context.Context, opts GetOptions) (io.ReadCloser, *ClientContent, *probe.Error) {
+	if opts.SSE != nil || opts.Zip || opts.VersionID != "" {
+		return nil, nil, c.notImplemented("Get with encryption, zip or version")
+	}
+	f, e := c.conn.Open(c.remotePath(c.targetURL.Path))
+	if e != nil {
+		return nil, nil, c.toError(e, c.targetURL.Path)
+	}
+	fi, e := f.Stat()
+	if e != nil {
+		f.Close()
+		return nil, nil, c.toError(e, c.targetURL.Path)
+	}
+	if fi.IsDir() {
+		f.Close()
+		return nil, nil, probe.NewError(PathIsNotRegular{Path: c.targetURL.Path})
+	}
+	if opts.RangeStart > 0 {
+		if _, e = f.Seek(opts.RangeStart, io.SeekStart); e != nil {
+			f.Close()
+			return nil, nil, probe.NewError(e)
+		}
+	}
+	return limitReadCloser(f, opts.RangeLength), c.fileInfo2ClientContent(c.targetURL.Clone(), fi), nil
+}
+
+// Put - uploads to a temporary file renamed to the target path once
+// complete, creating the parent directories as needed.
+func (c *sftpClient) Put(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
+	if opts.sse != nil {
+		return 0, c.notImplemented("Put with encryption")
+	}
+	if strings.HasSuffix(c.targetURL.Path, "/") {
+		return 0, probe.NewError(ObjectNameEmpty{})
+	}
+	p := c.remotePath(c.targetURL.Path)
+	if e := c.conn.MkdirAll(path.Dir(p)); e != nil {
+		return 0, c.toError(e, path.Dir(c.targetURL.Path))
+	}
+
+	partPath := p + partSuffix
+	f, e := c.conn.Create(partPath)
+	if e != nil {
+		return 0, c.toError(e, c.targetURL.Path)
+	}
+	reader = hookreader.NewHook(reader, progress)
+	if size >= 0 {
+		reader = io.LimitReader(reader, size)
+	}
+	n, e := f.ReadFrom(&contextReader{ctx: ctx, reader: reader})
+	if e == nil {
+		e = f.Close()
+	} else {
+		f.Close()
+	}
+	if e == nil && size >= 0 && n != size {
+		e = io.ErrUnexpectedEOF
+	}
+	if e != nil {
+		c.conn.Remove(partPath)
+		return n, probe.NewError(e)
+	}
+
+	if e = c.conn.PosixRename(partPath, p); e != nil {
+		// Servers without the posix-rename extension fail to
+		// rename onto an existing file.
+		c.conn.Remove(p)
+		if e = c.conn.Rename(partPath, p); e != nil {
+			c.conn.Remove(partPath)
+			return n, c.toError(e, c.targetURL.Path)
+		}
+	}
+	return n, nil
+}
+
+// contextReader - stops reading once ctx is canceled.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if e := r.ctx.Err(); e != nil {
+		return 0, e
+	}
+	return r.reader.Read(p)
+}
+
+// Copy - copies a file of the same server, SFTP has no server side copy
+// so the data goes through the client.
+func (c *sftpClient) Copy(ctx context.Context, source string, opts CopyOptions, progress io.Reader) *probe.Error {
+	if opts.srcSSE != nil || opts.tgtSSE != nil || opts.versionID != "" {
+		return c.notImplemented("Copy with encryption or version")
+	}
+	f, e := c.conn.Open(c.remotePath(source))
+	if e != nil {
+		return c.toError(e, source)
+	}
+	defer f.Close()
+	_, err := c.Put(ctx, f, opts.size, progress, PutOptions{})
+	return err
+}
+
+// Remove - removes files and empty directories, and directories with
+// their contents when isRemoveBucket is set.
+func (c *sftpClient) Remove(ctx context.Context, isIncomplete, isRemoveBucket, _, _ bool, contentCh <-chan *ClientContent) <-chan RemoveResult {
+	resultCh := make(chan RemoveResult)
+	go func() {
+		defer close(resultCh)
+		for content := range contentCh {
+			p := content.URL.Path
+			if isIncomplete {
+				p += partSuffix
+			}
+			var e error
+			if isRemoveBucket && strings.HasSuffix(p, "/") {
+				e = c.conn.RemoveAll(c.remotePath(p))
+			} else {
+				e = c.conn.Remove(c.remotePath(strings.TrimSuffix(p, "/")))
+			}
+			result := RemoveResult{}
+			result.ObjectName = content.URL.Path
+			if e != nil {
+				result.Err = c.toError(e, content.URL.Path)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case resultCh <- result:
+			}
+		}
+	}()
+	return resultCh
+}
+
+// GetURL - returns the client URL.
+func (c *sftpClient) GetURL() ClientURL {
+	return c.targetURL.Clone()
+}
+
+// AddUserAgent - the SSH protocol has no user agent.
+func (c *sftpClient) AddUserAgent(_, _ string) {}
+
+// GetBucketInfo - returns the directory information.
+func (c *sftpClient) GetBucketInfo(ctx context.Context) (BucketInfo, *probe.Error) {
+	content, err := c.Stat(ctx, StatOptions{})
+	if err != nil {
+		return BucketInfo{}, err
+	}
+	return BucketInfo{
+		URL:  content.URL,
+		Date: content.Time,
+		Type: content.Type,
+	}, nil
+}
+
+func (c *sftpClient) notImplemented(api string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     api,
+		APIType: "SFTP",
+	})
+}
+
+// The following operations have no SFTP equivalent.
+
+func (c *sftpClient) SetObjectLockConfig(context.Context, minio.RetentionMode, uint64, minio.ValidityUnit) *probe.Error {
+	return c.notImplemented("SetObjectLockConfig")
+}
+
+func (c *sftpClient) GetObjectLockConfig(context.Context) (string, minio.RetentionMode, uint64, minio.ValidityUnit, *probe.Error) {
+	return "", "", 0, "", c.notImplemented("GetObjectLockConfig")
+}
+
+func (c *sftpClient) GetAccess(context.Context) (string, string, *probe.Error) {
+	return "", "", c.notImplemented("GetAccess")
+}
+
+func (c *sftpClient) GetAccessRules(context.Context) (map[string]string, *probe.Error) {
+	return nil, c.notImplemented("GetAccessRules")
+}
+
+func (c *sftpClient) SetAccess(context.Context, string, bool) *probe.Error {
+	return c.notImplemented("SetAccess")
+}
+
+func (c *sftpClient) Select(context.Context, string, encrypt.ServerSide, SelectObjectOpts) (io.ReadCloser, *probe.Error) {
+	return nil, c.notImplemented("Select")
+}
+
+func (c *sftpClient) PutObjectRetention(context.Context, string, minio.RetentionMode, time.Time, bool) *probe.Error {
+	return c.notImplemented("PutObjectRetention")
+}
+
+func (c *sftpClient) GetObjectRetention(context.Context, string) (minio.RetentionMode, time.Time, *probe.Error) {
+	return "", time.Time{}, c.notImplemented("GetObjectRetention")
+}
+
+func (c *sftpClient) PutObjectLegalHold(context.Context, string, minio.LegalHoldStatus) *probe.Error {
+	return c.notImplemented("PutObjectLegalHold")
+}
+
+func (c *sftpClient) GetObjectLegalHold(context.Context, string) (minio.LegalHoldStatus, *probe.Error) {
+	return "", c.notImplemented("GetObjectLegalHold")
+}
+
+func (c *sftpClient) ShareDownload(context.Context, string, time.Duration) (string, *probe.Error) {
+	return "", c.notImplemented("ShareDownload")
+}
+
+func (c *sftpClient) ShareUpload(context.Context, bool, time.Duration, string) (string, map[string]string, *probe.Error) {
+	return "", nil, c.notImplemented("ShareUpload")
+}
+
+func (c *sftpClient) Watch(context.Context, WatchOptions) (*WatchObject, *probe.Error) {
+	return nil, c.notImplemented("Watch")
+}
+
+func (c *sftpClient) GetTags(context.Context, string) (map[string]string, *probe.Error) {
+	return nil, c.notImplemented("GetTags")
+}
+
+func (c *sftpClient) SetTags(context.Context, string, string) *probe.Error {
+	return c.notImplemented("SetTags")
+}
+
+func (c *sftpClient) DeleteTags(context.Context, string) *probe.Error {
+	return c.notImplemented("DeleteTags")
+}
+
+func (c *sftpClient) GetLifecycle(context.Context) (*lifecycle.Configuration, time.Time, *probe.Error) {
+	return nil, time.Time{}, c.notImplemented("GetLifecycle")
+}
+
+func (c *sftpClient) SetLifecycle(context.Context, *lifecycle.Configuration) *probe.Error {
+	return c.notImplemented("SetLifecycle")
+}
+
+func (c *sftpClient) GetVersion(context.Context) (minio.BucketVersioningConfiguration, *probe.Error) {
+	return minio.BucketVersioningConfiguration{}, c.notImplemented("GetVersion")
+}
+
+func (c *sftpClient) SetVersion(context.Context, string, []string, bool) *probe.Error {
+	return c.notImplemented("SetVersion")
+}
+
+func (c *sftpClient) GetReplication(context.Context) (replication.Config, *probe.Error) {
+	return replication.Config{}, c.notImplemented("GetReplication")
+}
+
+func (c *sftpClient) SetReplication(context.Context, *replication.Config, replication.Options) *probe.Error {
+	return c.notImplemented("SetReplication")
+}
+
+func (c *sftpClient) RemoveReplication(context.Context) *probe.Error {
+	return c.notImplemented("RemoveReplication")
+}
+
+func (c *sftpClient) GetReplicationMetrics(context.Context) (replication.MetricsV2, *probe.Error) {
+	return replication.MetricsV2{}, c.notImplemented("GetReplicationMetrics")
+}
+
+func (c *sftpClient) ResetReplication(context.Context, time.Duration, string) (replication.ResyncTargetsInfo, *probe.Error) {
+	return replication.ResyncTargetsInfo{}, c.notImplemented("ResetReplication")
+}
+
+func (c *sftpClient) ReplicationResyncStatus(context.Context, string) (replication.ResyncTargetsInfo, *probe.Error) {
+	return replication.ResyncTargetsInfo{}, c.notImplemented("ReplicationResyncStatus")
+}
+
+func (c *sftpClient) GetEncryption(context.Context) (string, string, *probe.Error) {
+	return "", "", c.notImplemented("GetEncryption")
+}
+
+func (c *sftpClient) SetEncryption(context.Context, string, string) *probe.Error {
+	return c.notImplemented("SetEncryption")
+}
+
+func (c *sftpClient) DeleteEncryption(context.Context) *probe.Error {
+	return c.notImplemented("DeleteEncryption")
+}
+
+func (c *sftpClient) Restore(context.Context, string, int) *probe.Error {
+	return c.notImplemented("Restore")
+}
+
+func (c *sftpClient) GetPart(context.Context, int) (io.ReadCloser, *probe.Error) {
+	return nil, c.notImplemented("GetPart")
+}
+
+func (c *sftpClient) PutPart(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
+	return c.Put(ctx, reader, size, progress, opts)
+}
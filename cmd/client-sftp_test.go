@@ -0,0 +1,145 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/sftp"
+	checkv1 "gopkg.in/check.v1"
+)
+
+// pipeConn - one end of an in memory SFTP session.
+type pipeConn struct {
+	io.Reader
+	io.WriteCloser
+}
+
+// newTestSFTPConn - connects a client to an SFTP server running in
+// process, on top of the local file system.
+func newTestSFTPConn(c *checkv1.C) (*sftp.Client, *sftp.Server) {
+	serverReader, clientWriter := io.Pipe()
+	clientReader, serverWriter := io.Pipe()
+	server, e := sftp.NewServer(pipeConn{serverReader, serverWriter})
+	c.Assert(e, checkv1.IsNil)
+	go server.Serve()
+	conn, e := sftp.NewClientPipe(clientReader, clientWriter)
+	c.Assert(e, checkv1.IsNil)
+	return conn, server
+}
+
+func newTestSFTPClient(c *checkv1.C, urlPath string) Client {
+	config := new(Config)
+	config.HostURL = "sftp://user@sftp-test-host" + filepath.ToSlash(urlPath)
+	clnt, err := sftpNew(config)
+	c.Assert(err, checkv1.IsNil)
+	return clnt
+}
+
+func (s *TestSuite) TestSFTPClient(c *checkv1.C) {
+	conn, server := newTestSFTPConn(c)
+	defer conn.Close()
+	defer server.Close()
+	sftpConnsMu.Lock()
+	sftpConns["user@sftp-test-host:22"] = conn
+	sftpConnsMu.Unlock()
+	defer func() {
+		sftpConnsMu.Lock()
+		delete(sftpConns, "user@sftp-test-host:22")
+		sftpConnsMu.Unlock()
+	}()
+	ctx := context.Background()
+	root := filepath.ToSlash(c.MkDir())
+
+	// Uploads create the parent directories.
+	for _, name := range []string{"a/b", "a.b", "c"} {
+		data := "data of " + name
+		n, err := newTestSFTPClient(c, root+"/"+name).Put(ctx, strings.NewReader(data), int64(len(data)), nil, PutOptions{})
+		c.Assert(err, checkv1.IsNil)
+		c.Assert(n, checkv1.Equals, int64(len(data)))
+	}
+	got, e := os.ReadFile(filepath.Join(root, "a", "b"))
+	c.Assert(e, checkv1.IsNil)
+	c.Assert(string(got), checkv1.Equals, "data of a/b")
+
+	content, err := newTestSFTPClient(c, root+"/a").Stat(ctx, StatOptions{})
+	c.Assert(err, checkv1.IsNil)
+	c.Assert(content.Type.IsDir(), checkv1.Equals, true)
+	c.Assert(content.URL.String(), checkv1.Equals, "sftp://user@sftp-test-host"+root+"/a/")
+	_, err = newTestSFTPClient(c, root+"/missing").Stat(ctx, StatOptions{})
+	c.Assert(err, checkv1.NotNil)
+	_, ok := err.ToGoError().(PathNotFound)
+	c.Assert(ok, checkv1.Equals, true)
+
+	// Recursive listings are in the lexical order of object storage.
+	var names []string
+	for content := range newTestSFTPClient(c, root+"/").List(ctx, ListOptions{Recursive: true}) {
+		c.Assert(content.Err, checkv1.IsNil)
+		names = append(names, strings.TrimPrefix(content.URL.Path, root+"/"))
+	}
+	c.Assert(names, checkv1.DeepEquals, []string{"a.b", "a/b", "c"})
+	names = nil
+	for content := range newTestSFTPClient(c, root+"/a").List(ctx, ListOptions{}) {
+		c.Assert(content.Err, checkv1.IsNil)
+		names = append(names, strings.TrimPrefix(content.URL.Path, root+"/"))
+	}
+	c.Assert(names, checkv1.DeepEquals, []string{"a.b", "a/"})
+
+	// Ranged reads and copies through the client.
+	reader, content, err := newTestSFTPClient(c, root+"/a.b").Get(ctx, GetOptions{RangeStart: 8})
+	c.Assert(err, checkv1.IsNil)
+	got, e = io.ReadAll(reader)
+	reader.Close()
+	c.Assert(e, checkv1.IsNil)
+	c.Assert(string(got), checkv1.Equals, "a.b")
+	c.Assert(content.Size, checkv1.Equals, int64(len("data of a.b")))
+
+	err = newTestSFTPClient(c, root+"/d").Copy(ctx, root+"/c", CopyOptions{size: int64(len("data of c"))}, nil)
+	c.Assert(err, checkv1.IsNil)
+	got, e = os.ReadFile(filepath.Join(root, "d"))
+	c.Assert(e, checkv1.IsNil)
+	c.Assert(string(got), checkv1.Equals, "data of c")
+
+	clnt := newTestSFTPClient(c, root+"/")
+	contentCh := make(chan *ClientContent, 1)
+	contentCh <- &ClientContent{URL: *newClientURL("sftp://user@sftp-test-host" + root + "/d")}
+	close(contentCh)
+	for result := range clnt.Remove(ctx, false, false, false, false, contentCh) {
+		c.Assert(result.Err, checkv1.IsNil)
+	}
+	_, e = os.Stat(filepath.Join(root, "d"))
+	c.Assert(os.IsNotExist(e), checkv1.Equals, true)
+}
+
+func (s *TestSuite) TestExpandSFTPURL(c *checkv1.C) {
+	alias, urlStr, cfg, err := expandSFTPURL("sftp://backup@host:2222/srv/data")
+	c.Assert(err, checkv1.IsNil)
+	c.Assert(alias, checkv1.Equals, "sftp://backup@host:2222")
+	c.Assert(urlStr, checkv1.Equals, "sftp://backup@host:2222/srv/data")
+	c.Assert(cfg.API, checkv1.Equals, sftpAPI)
+
+	u := newClientURL(urlStr)
+	c.Assert(u.Type, checkv1.Equals, ClientURLType(objectStorage))
+	c.Assert(u.Host, checkv1.Equals, "backup@host:2222")
+	c.Assert(u.Path, checkv1.Equals, "/srv/data")
+	c.Assert(u.String(), checkv1.Equals, urlStr)
+}
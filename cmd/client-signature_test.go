@@ -0,0 +1,134 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// v2OnlyHandler - rejects V4 signatures like appliances only speaking
+// Signature V2, and keeps the Authorization header of the last request.
+type v2OnlyHandler struct {
+	mu            sync.Mutex
+	authorization string
+}
+
+func (h *v2OnlyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	h.authorization = r.Header.Get("Authorization")
+	h.mu.Unlock()
+	w.Header().Set("Content-Type", "application/xml")
+	if strings.HasPrefix(r.Header.Get("Authorization"), signV4Algorithm) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`<Error><Code>InvalidArgument</Code><Message>Signature V4 is not supported</Message></Error>`))
+		return
+	}
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte(`<Error><Code>NoSuchBucket</Code><Message>The specified bucket does not exist</Message></Error>`))
+}
+
+func (h *v2OnlyHandler) lastAuthorization() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.authorization
+}
+
+func TestSignatureSelection(t *testing.T) {
+	handler := &v2OnlyHandler{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	for api, prefix := range map[string]string{"S3v4": signV4Algorithm + " ", "S3v2": "AWS ", anonymousAPI: ""} {
+		config := &Config{
+			HostURL:   server.URL + "/bucket",
+			AccessKey: "WLGDGYAQYIGI833EV05A",
+			SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF",
+			Signature: api,
+		}
+		clnt, err := S3New(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clnt.Stat(context.Background(), StatOptions{})
+		authorization := handler.lastAuthorization()
+		if prefix == "" && authorization != "" || !strings.HasPrefix(authorization, prefix) {
+			t.Errorf("%s: unexpected Authorization `%s`", api, authorization)
+		}
+	}
+
+	hostCfg := &aliasConfigV10{
+		URL:       server.URL,
+		AccessKey: "WLGDGYAQYIGI833EV05A",
+		SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF",
+	}
+	if api := probeAliasSignature("", hostCfg); api != "S3v2" {
+		t.Errorf("expected S3v2 to be probed, got %s", api)
+	}
+}
+
+func TestProbeAliasSignatureSaved(t *testing.T) {
+	useTestMcConfig(t)
+	cache := cacheCfgV10
+	t.Cleanup(func() { cacheCfgV10 = cache })
+	cacheCfgV10 = nil
+
+	server := httptest.NewServer(&v2OnlyHandler{})
+	defer server.Close()
+	hostCfg := aliasConfigV10{
+		URL:       server.URL,
+		AccessKey: "WLGDGYAQYIGI833EV05A",
+		SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF",
+		Path:      "auto",
+	}
+	err := updateMcConfig(func(config *configV10) *probe.Error {
+		config.Aliases["legacy"] = hostCfg
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if api := probeAliasSignature("legacy", &hostCfg); api != "S3v2" {
+		t.Fatalf("expected S3v2 to be probed, got %s", api)
+	}
+	config, err := loadMcConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api := config.Aliases["legacy"].API; api != "S3v2" {
+		t.Errorf("expected the probed signature saved, got `%s`", api)
+	}
+}
+
+func TestNormalizeAPI(t *testing.T) {
+	for api, expected := range map[string]string{"v2": "S3v2", "s3v2": "S3v2", "V4": "S3v4", "S3v4": "S3v4", "Anonymous": anonymousAPI, "auto": autoAPI, "azure": azureAPI} {
+		if got := normalizeAPI(api); got != expected {
+			t.Errorf("%s: expected %s, got %s", api, expected, got)
+		}
+		if !isValidAPI(api) {
+			t.Errorf("%s: expected a valid API", api)
+		}
+	}
+}
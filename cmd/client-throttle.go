@@ -0,0 +1,235 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Longest wait honored from the hint of a throttled response.
+const maxThrottleDelay = 5 * time.Minute
+
+// Error codes of the providers asking to slow down.
+var throttleCodes = map[string]bool{
+	"SlowDown":             true,
+	"SlowDownRead":         true,
+	"SlowDownWrite":        true,
+	"TooManyRequests":      true,
+	"RequestLimitExceeded": true,
+	"Throttling":           true,
+	"ThrottlingException":  true,
+	"RequestThrottled":     true,
+	"ServerBusy":           true,
+	"rateLimitExceeded":    true,
+}
+
+func isThrottleCode(code string) bool {
+	return throttleCodes[code]
+}
+
+var errorCodeRegexp = regexp.MustCompile(`<Code>([^<]+)</Code>`)
+
+// throttleStats - the responses throttled by the servers during the run,
+// reported in the summary at the end of the run.
+var throttleStats struct {
+	requests int64
+	waited   int64 // nanoseconds
+}
+
+// throttle - holds back the requests to a host until the time its last
+// throttled response asked to wait for.
+type throttle struct {
+	until int64 // unix nanoseconds
+	hits  int32 // consecutive throttled responses
+}
+
+// Throttles by server host, shared by all the transports to a host.
+var throttles sync.Map
+
+func getThrottle(host string) *throttle {
+	t, _ := throttles.LoadOrStore(host, &throttle{})
+	return t.(*throttle)
+}
+
+// hold - holds back the requests for delay, unless they already are for longer.
+func (t *throttle) hold(delay time.Duration) {
+	until := time.Now().Add(delay).UnixNano()
+	for {
+		current := atomic.LoadInt64(&t.until)
+		if current >= until || atomic.CompareAndSwapInt64(&t.until, current, until) {
+			return
+		}
+	}
+}
+
+// wait - waits until the requests are no longer held back.
+func (t *throttle) wait(ctx context.Context) error {
+	delay := time.Until(time.Unix(0, atomic.LoadInt64(&t.until)))
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	start := time.Now()
+	defer func() {
+		atomic.AddInt64(&throttleStats.waited, int64(time.Since(start)))
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// throttleTransport - honors the Retry-After headers and the SlowDown
+// errors of the servers, by holding back the following requests to the
+// host, retried or not, for as long as asked or else for a backoff.
+type throttleTransport struct {
+	transport http.RoundTripper
+	throttle  *throttle
+}
+
+func newThrottleTransport(config *Config, transport http.RoundTripper) http.RoundTripper {
+	host := config.HostURL
+	if u, e := url.Parse(config.HostURL); e == nil && u.Host != "" {
+		host = u.Host
+	}
+	return &throttleTransport{
+		transport: transport,
+		throttle:  getThrottle(host),
+	}
+}
+
+// RoundTrip - implements http.RoundTripper.
+func (t *throttleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if e := t.throttle.wait(req.Context()); e != nil {
+		return nil, e
+	}
+	resp, e := t.transport.RoundTrip(req)
+	if e != nil {
+		return resp, e
+	}
+	delay, throttled := throttleDelay(resp)
+	if !throttled {
+		atomic.StoreInt32(&t.throttle.hits, 0)
+		return resp, nil
+	}
+	atomic.AddInt64(&throttleStats.requests, 1)
+	hits := atomic.AddInt32(&t.throttle.hits, 1)
+	if delay <= 0 {
+		delay = retryDelay(int(hits) - 1)
+	}
+	t.throttle.hold(delay)
+	return resp, nil
+}
+
+// throttleDelay - returns true for the responses asking to slow down,
+// with the delay they asked to wait for when they set one.
+func throttleDelay(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	delay, hinted := retryAfter(resp.Header, time.Now())
+	if delay > maxThrottleDelay {
+		delay = maxThrottleDelay
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || hinted {
+		return delay, true
+	}
+
+	// A 503 is only a throttle when its error code says so, the body
+	// is read back into the response for the client to decode.
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return 0, false
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	match := errorCodeRegexp.FindSubmatch(body)
+	return 0, match != nil && isThrottleCode(string(match[1]))
+}
+
+// retryAfter - returns the delay of the Retry-After header, in seconds
+// or as a date, or of the milliseconds headers of Azure.
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	for _, k := range []string{"X-Ms-Retry-After-Ms", "Retry-After-Ms"} {
+		if ms, e := strconv.ParseInt(header.Get(k), 10, 64); e == nil && ms >= 0 {
+			return time.Duration(ms) * time.Millisecond, true
+		}
+	}
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, e := strconv.ParseInt(value, 10, 64); e == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, e := http.ParseTime(value); e == nil {
+		if delay := at.Sub(now); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// throttleMessage - reports the throttled responses at the end of a run,
+// so that the parallelism can be reduced.
+type throttleMessage struct {
+	Status   string  `json:"status"`
+	Requests int64   `json:"throttledRequests"`
+	Waited   float64 `json:"throttledSeconds"`
+}
+
+// getThrottleMessage - returns the throttle statistics of the run, false
+// when nothing was throttled.
+func getThrottleMessage() (throttleMessage, bool) {
+	msg := throttleMessage{
+		Status:   "success",
+		Requests: atomic.LoadInt64(&throttleStats.requests),
+		Waited:   time.Duration(atomic.LoadInt64(&throttleStats.waited)).Seconds(),
+	}
+	return msg, msg.Requests > 0
+}
+
+func (t throttleMessage) String() string {
+	return fmt.Sprintf("<INFO> %d request(s) were throttled by the server, waiting %s. Please consider reducing the parallelism.",
+		t.Requests, time.Duration(t.Waited*float64(time.Second)).Round(time.Millisecond))
+}
+
+func (t throttleMessage) JSON() string {
+	jsonMessageBytes, e := json.MarshalIndent(t, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		header http.Header
+		delay  time.Duration
+		hinted bool
+	}{
+		{http.Header{}, 0, false},
+		{http.Header{"Retry-After": {"3"}}, 3 * time.Second, true},
+		{http.Header{"Retry-After": {now.Add(time.Minute).Format(http.TimeFormat)}}, time.Minute, true},
+		{http.Header{"Retry-After": {now.Add(-time.Minute).Format(http.TimeFormat)}}, 0, true},
+		{http.Header{"Retry-After": {"soon"}}, 0, false},
+		{http.Header{"X-Ms-Retry-After-Ms": {"250"}}, 250 * time.Millisecond, true},
+	}
+	for i, testCase := range testCases {
+		delay, hinted := retryAfter(testCase.header, now)
+		if delay != testCase.delay || hinted != testCase.hinted {
+			t.Errorf("Test %d: expected %s (%v), got %s (%v)", i+1, testCase.delay, testCase.hinted, delay, hinted)
+		}
+	}
+}
+
+func TestThrottleTransport(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			io.WriteString(w, "<Error><Code>SlowDown</Code><Message>Please reduce your request rate.</Message></Error>")
+		}
+	}))
+	defer server.Close()
+
+	requested, waited := throttleStats.requests, throttleStats.waited
+	clnt := &http.Client{Transport: newThrottleTransport(&Config{HostURL: server.URL}, http.DefaultTransport)}
+	resp, e := clnt.Get(server.URL)
+	if e != nil {
+		t.Fatal(e)
+	}
+	resp.Body.Close()
+
+	// The next request waits for the Retry-After of the first one.
+	start := time.Now()
+	resp, e = clnt.Get(server.URL)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("expected the request to wait for the Retry-After, sent after %s", elapsed)
+	}
+	body, e := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if e != nil || !strings.Contains(string(body), "<Code>SlowDown</Code>") {
+		t.Errorf("expected the SlowDown error to be read back, got %q (%v)", body, e)
+	}
+
+	if got := throttleStats.requests - requested; got != 2 {
+		t.Errorf("expected 2 throttled requests, got %d", got)
+	}
+	if throttleStats.waited == waited {
+		t.Error("expected the wait to be accounted")
+	}
+}
@@ -113,6 +113,17 @@ func newClientURL(urlStr string) *ClientURL {
 		if rest == "" {
 			rest = "/"
 		}
+		if authority != "" && scheme == sftpScheme {
+			// The user is part of the host, SFTP URLs have no alias.
+			return &ClientURL{
+				Scheme:          scheme,
+				Type:            objectStorage,
+				Host:            authority,
+				Path:            rest,
+				SchemeSeparator: "://",
+				Separator:       '/',
+			}
+		}
 		host := getHost(authority)
 		if host != "" && (scheme == "http" || scheme == "https") {
 			return &ClientURL{
@@ -195,6 +206,31 @@ func urlJoinPath(url1, url2 string) string {
 	return joinURLs(u1, u2).String()
 }
 
+// rsyncTargetURL returns the target a source folder is copied into following
+// rsync trailing slash semantics, `dir/` copies the contents of dir into the
+// target while `dir` copies the folder itself.
+func rsyncTargetURL(sourceURL, targetURL string) string {
+	_, expandedURL, _ := mustExpandAlias(sourceURL)
+	if name := urlFolderName(newClientURL(expandedURL)); name != "" {
+		return urlJoinPath(targetURL, name)
+	}
+	return targetURL
+}
+
+// urlFolderName returns the last path element of a URL, or an empty string
+// when the URL ends with a separator or has no named path element.
+func urlFolderName(u *ClientURL) string {
+	separator := string(u.Separator)
+	if strings.HasSuffix(u.Path, separator) {
+		return ""
+	}
+	name := u.Path[strings.LastIndex(u.Path, separator)+1:]
+	if name == "." || name == ".." {
+		return ""
+	}
+	return name
+}
+
 // url2Stat returns stat info for URL - supports bucket, object and a prefixe with or without a trailing slash
 func url2Stat(ctx context.Context, opts url2StatOptions) (client Client, content *ClientContent, err *probe.Error) {
 	client, err = newClient(opts.urlStr)
@@ -52,3 +52,14 @@ func (s *TestSuite) TestURLJoinPath(c *checkv1.C) {
 	url = urlJoinPath(url1, url2)
 	c.Assert(url, checkv1.Equals, "http://s3.mycompany.io/dev/mybucket/bin/")
 }
+
+// TestURLFolderName - tests rsync trailing slash semantics.
+func (s *TestSuite) TestURLFolderName(c *checkv1.C) {
+	c.Assert(urlFolderName(newClientURL("/data/photos")), checkv1.Equals, "photos")
+	c.Assert(urlFolderName(newClientURL("/data/photos/")), checkv1.Equals, "")
+	c.Assert(urlFolderName(newClientURL("photos")), checkv1.Equals, "photos")
+	c.Assert(urlFolderName(newClientURL(".")), checkv1.Equals, "")
+	c.Assert(urlFolderName(newClientURL("/")), checkv1.Equals, "")
+	c.Assert(urlFolderName(newClientURL("https://s3.amazonaws.com/mybucket")), checkv1.Equals, "mybucket")
+	c.Assert(urlFolderName(newClientURL("https://s3.amazonaws.com")), checkv1.Equals, "")
+}
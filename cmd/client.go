@@ -19,6 +19,7 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
@@ -43,13 +44,48 @@ const (
 	DirLast
 )
 
-// GetOptions holds options of the GET operation
+// GetOptions holds options of the GET operation, RangeLength bytes
+// are read from RangeStart, up to the end of the object when zero.
 type GetOptions struct {
-	SSE        encrypt.ServerSide
-	VersionID  string
-	Zip        bool
-	RangeStart int64
-	Preserve   bool
+	SSE         encrypt.ServerSide
+	VersionID   string
+	Zip         bool
+	RangeStart  int64
+	RangeLength int64
+	Preserve    bool
+}
+
+// isRange - returns true if only a part of the object is to be read.
+func (o GetOptions) isRange() bool {
+	return o.RangeStart > 0 || o.RangeLength > 0
+}
+
+// rangeEnd - returns the inclusive offset of the last byte to be read,
+// zero when reading up to the end of the object.
+func (o GetOptions) rangeEnd() int64 {
+	if o.RangeLength <= 0 {
+		return 0
+	}
+	return o.RangeStart + o.RangeLength - 1
+}
+
+// rangeHeader - returns the HTTP Range header value of the options.
+func (o GetOptions) rangeHeader() string {
+	if o.RangeLength > 0 {
+		return fmt.Sprintf("bytes=%d-%d", o.RangeStart, o.rangeEnd())
+	}
+	return fmt.Sprintf("bytes=%d-", o.RangeStart)
+}
+
+// limitReadCloser - reads at most n bytes of rc when n is positive.
+func limitReadCloser(rc io.ReadCloser, n int64) io.ReadCloser {
+	if n <= 0 {
+		return rc
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(rc, n), rc}
 }
 
 // PutOptions holds options for PUT operation
@@ -61,7 +97,13 @@ type PutOptions struct {
 	storageClass          string
 	multipartSize         uint64
 	multipartThreads      uint
+	multipartThreshold    uint64
 	concurrentStream      bool
+	sparse                bool
+	// Downloads keep their partial file on failure, to be resumed
+	// later from resumeOffset.
+	keepPart     bool
+	resumeOffset int64
 }
 
 // StatOptions holds options of the HEAD operation
@@ -92,6 +134,8 @@ type ListOptions struct {
 	TimeRef           time.Time
 	ShowDir           DirOpt
 	Count             int
+	// Concurrent listings of a recursive listing, when supported.
+	Parallel int
 }
 
 // CopyOptions holds options for copying operation
@@ -197,6 +241,7 @@ type ClientContent struct {
 	Size         int64
 	Type         os.FileMode
 	StorageClass string
+	ContentType  string
 	Metadata     map[string]string
 	Tags         map[string]string
 	UserMetadata map[string]string
@@ -235,6 +280,8 @@ type Config struct {
 	Debug             bool
 	Insecure          bool
 	Lookup            minio.BucketLookupType
+	Region            string
+	Provider          string
 	ConnReadDeadline  time.Duration
 	ConnWriteDeadline time.Duration
 	UploadLimit       int64
@@ -18,6 +18,7 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"errors"
@@ -26,8 +27,10 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/net/http/httpguts"
@@ -37,6 +40,7 @@ import (
 	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/tags"
 	"github.com/minio/pkg/v2/env"
 )
 
@@ -97,9 +101,35 @@ func getEncKeys(ctx *cli.Context) (map[string][]prefixSSEPair, *probe.Error) {
 		return nil, err.Trace(sseKeys)
 	}
 
+	// SSE-C keys of the configuration apply to the prefixes not
+	// given on the command line.
+	prefixes, keys := getConfigEncryptKeys(encryptKeyTypeSSEC)
+	for i, prefix := range prefixes {
+		alias, _ := url2Alias(prefix)
+		if hasSSEPrefix(encKeyDB[alias], prefix) {
+			continue
+		}
+		sse, e := encrypt.NewSSEC(keys[i])
+		if e != nil {
+			return nil, probe.NewError(e).Trace(prefix)
+		}
+		encKeyDB[alias] = append(encKeyDB[alias], prefixSSEPair{Prefix: prefix, SSE: sse})
+		sort.Sort(byPrefixLength(encKeyDB[alias]))
+	}
+
 	return encKeyDB, nil
 }
 
+// hasSSEPrefix - reports whether a key is set for prefix.
+func hasSSEPrefix(encKeys []prefixSSEPair, prefix string) bool {
+	for _, k := range encKeys {
+		if k.Prefix == prefix {
+			return true
+		}
+	}
+	return false
+}
+
 // Check if the passed URL represents a folder. It may or may not exist yet.
 // If it exists, we can easily check if it is a folder, if it doesn't exist,
 // we can guess if the url is a folder from how it looks.
@@ -269,6 +299,13 @@ func putTargetStream(ctx context.Context, alias, urlStr, mode, until, legalHold
 		opts.metadata[AmzObjectLockLegalHold] = legalHold
 	}
 
+	if err = checkUploadRules(alias, urlStr, size, opts.metadata["Content-Type"]); err != nil {
+		return 0, err.Trace(alias, urlStr)
+	}
+	if size < 0 {
+		reader = limitUploadReader(alias, urlStr, reader)
+	}
+
 	n, err := targetClnt.Put(ctx, reader, size, progress, opts)
 	if err != nil {
 		return n, err.Trace(alias, urlStr)
@@ -282,14 +319,52 @@ func putTargetStreamWithURL(urlStr string, reader io.Reader, size int64, opts Pu
 	if err != nil {
 		return 0, err.Trace(alias, urlStr)
 	}
-	contentType := guessURLContentType(urlStr)
 	if opts.metadata == nil {
 		opts.metadata = map[string]string{}
 	}
-	opts.metadata["Content-Type"] = contentType
+	// Guess the content type unless it was set.
+	if _, ok := opts.metadata["Content-Type"]; !ok {
+		opts.metadata["Content-Type"] = guessURLContentType(urlStr)
+	}
 	return putTargetStream(context.Background(), alias, urlStrFull, "", "", "", reader, size, nil, opts)
 }
 
+// isSameEndpointAlias - reports whether objects of the source alias can be
+// copied server side into the target alias, which is the case for the same
+// alias or different aliases pointing to the same endpoint with the same
+// credentials.
+func isSameEndpointAlias(sourceAlias, targetAlias string) bool {
+	if sourceAlias == targetAlias {
+		return true
+	}
+	if sourceAlias == "" || targetAlias == "" {
+		// Local filesystem.
+		return false
+	}
+	return sameEndpointConfig(mustGetHostConfig(sourceAlias), mustGetHostConfig(targetAlias))
+}
+
+// sameEndpointConfig - reports whether both alias configs address the same
+// endpoint with the same credentials.
+func sameEndpointConfig(a, b *aliasConfigV10) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSuffix(a.URL, "/"), strings.TrimSuffix(b.URL, "/")) &&
+		a.AccessKey == b.AccessKey &&
+		a.SecretKey == b.SecretKey &&
+		a.SessionToken == b.SessionToken &&
+		strings.EqualFold(a.API, b.API)
+}
+
+// canStreamAfterCopyError - reports whether a failed server side copy
+// between two aliases of the same endpoint is worth retrying by streaming
+// the object, e.g. when the server denies the copy but allows reads.
+func canStreamAfterCopyError(err *probe.Error) bool {
+	e := err.ToGoError()
+	return !errors.Is(e, context.Canceled) && !errors.Is(e, context.DeadlineExceeded)
+}
+
 // copySourceToTargetURL copies to targetURL from source.
 func copySourceToTargetURL(ctx context.Context, alias, urlStr, source, sourceVersionID, mode, until, legalHold string, size int64, progress io.Reader, opts CopyOptions) *probe.Error {
 	targetClnt, err := newClientFromAlias(alias, urlStr)
@@ -297,6 +372,10 @@ func copySourceToTargetURL(ctx context.Context, alias, urlStr, source, sourceVer
 		return err.Trace(alias, urlStr)
 	}
 
+	if err = checkUploadRules(alias, urlStr, size, opts.metadata["Content-Type"]); err != nil {
+		return err.Trace(alias, urlStr)
+	}
+
 	opts.versionID = sourceVersionID
 	opts.size = size
 	opts.metadata[AmzObjectLockMode] = mode
@@ -350,6 +429,39 @@ func getAllMetadata(ctx context.Context, sourceAlias, sourceURLStr string, srcSS
 	return filterMetadata(metadata), nil
 }
 
+// addSourceTags - adds the tags of the source object to the metadata of
+// an object store target, unless tags are already set for the target.
+func addSourceTags(ctx context.Context, urls URLs, metadata map[string]string) *probe.Error {
+	sourceURL := urls.SourceContent.URL
+	if sourceURL.Type != objectStorage || urls.TargetContent.URL.Type != objectStorage {
+		return nil
+	}
+	if _, ok := metadata["X-Amz-Tagging"]; ok {
+		return nil
+	}
+	sourceClnt, err := newClientFromAlias(urls.SourceAlias, sourceURL.String())
+	if err != nil {
+		return err.Trace(urls.SourceAlias, sourceURL.String())
+	}
+	tagsMap, err := sourceClnt.GetTags(ctx, urls.SourceContent.VersionID)
+	if err != nil {
+		// Stores without tags have none to copy.
+		if _, ok := err.ToGoError().(APINotImplemented); ok || minio.ToErrorResponse(err.ToGoError()).Code == "NotImplemented" {
+			return nil
+		}
+		return err.Trace(sourceURL.String())
+	}
+	if len(tagsMap) == 0 {
+		return nil
+	}
+	tagsSet, e := tags.NewTags(tagsMap, true)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	metadata["X-Amz-Tagging"] = tagsSet.String()
+	return nil
+}
+
 // uploadSourceToTargetURL - uploads to targetURL from source.
 // optionally optimizes copy for object sizes <= 5GiB by using
 // server side copy operation.
@@ -415,7 +527,12 @@ func uploadSourceToTargetURL(ctx context.Context, uploadOpts uploadSourceToTarge
 	}
 
 	// Optimize for server side copy if the host is same.
-	if sourceAlias == targetAlias && !uploadOpts.isZip {
+	// Objects encrypted on the client side are copied server side only
+	// as long as they keep the same key.
+	// Parts of objects are streamed through the client.
+	serverSide := isSameEndpointAlias(sourceAlias, targetAlias) && !uploadOpts.isZip && !uploadOpts.ranged &&
+		bytes.Equal(clientEncryptionKey(sourcePath), clientEncryptionKey(targetPath))
+	if serverSide {
 		// preserve new metadata and save existing ones.
 		if uploadOpts.preserve {
 			currentMetadata, err := getAllMetadata(ctx, sourceAlias, sourceURL.String(), srcSSE, uploadOpts.urls)
@@ -437,6 +554,13 @@ func uploadSourceToTargetURL(ctx context.Context, uploadOpts uploadSourceToTarge
 			metadata[http.CanonicalHeaderKey(k)] = v
 		}
 
+		// Multipart copies do not keep the tags of the source.
+		if uploadOpts.preserve {
+			if err = addSourceTags(ctx, uploadOpts.urls, metadata); err != nil {
+				return uploadOpts.urls.WithError(err.Trace(sourceURL.String()))
+			}
+		}
+
 		sourcePath := filepath.ToSlash(sourceURL.Path)
 		if uploadOpts.urls.SourceContent.RetentionEnabled {
 			err = putTargetRetention(ctx, targetAlias, targetURL.String(), metadata)
@@ -454,7 +578,14 @@ func uploadSourceToTargetURL(ctx context.Context, uploadOpts uploadSourceToTarge
 
 		err = copySourceToTargetURL(ctx, targetAlias, targetURL.String(), sourcePath, sourceVersion, mode, until,
 			legalHold, length, uploadOpts.progress, opts)
-	} else {
+		if err != nil && sourceAlias != targetAlias && canStreamAfterCopyError(err) {
+			// Different aliases of the same endpoint, fall back
+			// to streaming the object through the client.
+			serverSide = false
+			err = nil
+		}
+	}
+	if !serverSide {
 		if uploadOpts.urls.SourceContent.RetentionEnabled {
 			// preserve new metadata and save existing ones.
 			if uploadOpts.preserve {
@@ -483,18 +614,38 @@ func uploadSourceToTargetURL(ctx context.Context, uploadOpts uploadSourceToTarge
 
 		// Proceed with regular stream copy.
 		var (
-			content *ClientContent
-			reader  io.ReadCloser
+			content      *ClientContent
+			reader       io.ReadCloser
+			resumeOffset int64
 		)
 
-		reader, content, err = getSourceStream(ctx, sourceAlias, sourceURL.String(), getSourceOpts{
+		sourceOpts := getSourceOpts{
 			GetOptions: GetOptions{
 				VersionID: sourceVersion,
 				SSE:       srcSSE,
 				Zip:       uploadOpts.isZip,
 				Preserve:  uploadOpts.preserve,
 			},
-		})
+		}
+		if uploadOpts.ranged {
+			// The size of the source is the size of its part.
+			sourceOpts.RangeStart = uploadOpts.rangeStart
+			sourceOpts.RangeLength = length
+		}
+		// Checksums of the parts of objects encrypted on the client side
+		// are checksums of their encrypted data.
+		skipParts := uploadOpts.skipMatchingParts && !uploadOpts.ranged && sourceURL.Type == objectStorage && targetURL.Type == fileSystem &&
+			!uploadOpts.isZip && clientEncryptionKey(sourcePath) == nil
+		// The part file is kept on failures only for the downloads
+		// that can be resumed from it.
+		resumable := !skipParts && isResumableDownload(uploadOpts, sourceURL, targetURL, sourcePath)
+		if skipParts {
+			reader, content, err = getChangedPartsStream(ctx, sourceAlias, sourceURL.String(), targetURL.Path, sourceOpts)
+		} else if resumable {
+			reader, content, resumeOffset, err = getResumedSourceStream(ctx, sourceAlias, sourceURL.String(), targetURL.Path+partSuffix, sourceOpts)
+		} else {
+			reader, content, err = getSourceStream(ctx, sourceAlias, sourceURL.String(), sourceOpts)
+		}
 		if err != nil {
 			return uploadOpts.urls.WithError(err.Trace(sourceURL.String()))
 		}
@@ -522,6 +673,13 @@ func uploadSourceToTargetURL(ctx context.Context, uploadOpts uploadSourceToTarge
 			metadata[http.CanonicalHeaderKey(k)] = v
 		}
 
+		// Tags are not returned with the object.
+		if uploadOpts.preserve {
+			if err = addSourceTags(ctx, uploadOpts.urls, metadata); err != nil {
+				return uploadOpts.urls.WithError(err.Trace(sourceURL.String()))
+			}
+		}
+
 		var e error
 		var multipartSize uint64
 		var multipartThreads int
@@ -547,29 +705,63 @@ func uploadSourceToTargetURL(ctx context.Context, uploadOpts uploadSourceToTarge
 			return uploadOpts.urls.WithError(probe.NewError(e))
 		}
 
-		putOpts := PutOptions{
-			metadata:         filterMetadata(metadata),
-			sse:              tgtSSE,
-			storageClass:     uploadOpts.urls.TargetContent.StorageClass,
-			md5:              uploadOpts.urls.MD5,
-			disableMultipart: uploadOpts.urls.DisableMultipart,
-			isPreserve:       uploadOpts.preserve,
-			multipartSize:    multipartSize,
-			multipartThreads: uint(multipartThreads),
+		var multipartThreshold uint64
+		if uploadOpts.multipartThreshold != "" {
+			multipartThreshold, e = humanize.ParseBytes(uploadOpts.multipartThreshold)
+			if e != nil {
+				return uploadOpts.urls.WithError(probe.NewError(e))
+			}
 		}
 
-		if isReadAt(reader) || length == 0 {
-			_, err = putTargetStream(ctx, targetAlias, targetURL.String(), mode, until,
-				legalHold, reader, length, uploadOpts.progress, putOpts)
-		} else {
-			_, err = putTargetStream(ctx, targetAlias, targetURL.String(), mode, until,
-				legalHold, io.LimitReader(reader, length), length, uploadOpts.progress, putOpts)
+		putOpts := PutOptions{
+			metadata:           filterMetadata(metadata),
+			sse:                tgtSSE,
+			storageClass:       uploadOpts.urls.TargetContent.StorageClass,
+			md5:                uploadOpts.urls.MD5,
+			disableMultipart:   uploadOpts.urls.DisableMultipart,
+			isPreserve:         uploadOpts.preserve,
+			multipartSize:      multipartSize,
+			multipartThreads:   uint(multipartThreads),
+			multipartThreshold: multipartThreshold,
+			sparse:             uploadOpts.sparse,
+			keepPart:           resumable,
+			resumeOffset:       resumeOffset,
+		}
+		if resumeOffset > 0 {
+			addProgress(uploadOpts.progress, resumeOffset)
+			length -= resumeOffset
+		}
+
+		var body io.Reader = reader
+		if !isReadAt(reader) && length != 0 {
+			body = io.LimitReader(reader, length)
+		}
+		// Downloads are scanned while they are written to the partial
+		// file, which is renamed once the object is clean.
+		if uploadOpts.scanner != nil && sourceURL.Type == objectStorage && targetURL.Type == fileSystem {
+			scan, err := uploadOpts.scanner.start(ctx, sourcePath, body)
+			if err != nil {
+				return uploadOpts.urls.WithError(err.Trace(sourceURL.String()))
+			}
+			defer scan.abort()
+			body = scan
 		}
+		_, err = putTargetStream(ctx, targetAlias, targetURL.String(), mode, until,
+			legalHold, body, length, uploadOpts.progress, putOpts)
 	}
 	if err != nil {
 		return uploadOpts.urls.WithError(err.Trace(sourceURL.String()))
 	}
 
+	if uploadOpts.verify {
+		err = verifyCopy(ctx,
+			verifyEndpoint{alias: sourceAlias, urlStr: sourceURL.String(), aliasedURL: sourcePath, versionID: sourceVersion, sse: srcSSE},
+			verifyEndpoint{alias: targetAlias, urlStr: targetURL.String(), aliasedURL: targetPath, sse: tgtSSE})
+		if err != nil {
+			return uploadOpts.urls.WithError(err)
+		}
+	}
+
 	return uploadOpts.urls.WithError(nil)
 }
 
@@ -593,15 +785,92 @@ func newClientFromAlias(alias, urlStr string) (Client, *probe.Error) {
 	}
 
 	s3Config := NewS3Config(alias, urlStr, hostCfg)
-	s3Client, err := S3New(s3Config)
+	var clnt Client
+	switch {
+	case strings.EqualFold(hostCfg.API, azureAPI):
+		clnt, err = azureNew(s3Config)
+	case strings.EqualFold(hostCfg.API, gcsAPI):
+		clnt, err = gcsNew(s3Config)
+	case strings.EqualFold(hostCfg.API, sftpAPI):
+		clnt, err = sftpNew(s3Config)
+	default:
+		if hostCfg.API == "" || strings.EqualFold(hostCfg.API, autoAPI) {
+			s3Config.Signature = probeAliasSignature(alias, hostCfg)
+		}
+		// minio-go retries the requests of S3 clients itself, as set
+		// by setGlobalsFromContext.
+		if clnt, err = S3New(s3Config); err != nil {
+			return nil, err.Trace(alias, urlStr)
+		}
+		return newEncryptedClient(clnt, alias, hostCfg), nil
+	}
 	if err != nil {
 		return nil, err.Trace(alias, urlStr)
 	}
-	return s3Client, nil
+	// Transient errors of idempotent operations are retried, and objects
+	// under prefixes with a client side encryption key are encrypted and
+	// decrypted transparently.
+	return newEncryptedClient(newRetryClient(clnt), alias, hostCfg), nil
+}
+
+// Signatures of the aliases without an API, probed once per endpoint
+// and keys for the life of the process.
+var probedSignatures sync.Map
+
+// probedSignature - the signature probed for an endpoint, concurrent
+// clients of the same endpoint wait for a single probe.
+type probedSignature struct {
+	once sync.Once
+	api  string
+}
+
+// probeAliasSignature - returns the signature accepted by the endpoint of
+// hostCfg, S3v4 when none could be probed, for the request to report why.
+// Aliases of the configuration file without any API keep the probed
+// signature, as `alias set` does, so that it is probed only once.
+func probeAliasSignature(alias string, hostCfg *aliasConfigV10) string {
+	key := hostCfg.URL + "\x00" + hostCfg.AccessKey
+	value, _ := probedSignatures.LoadOrStore(key, &probedSignature{})
+	probed := value.(*probedSignature)
+	probed.once.Do(func() {
+		// Without keys, the credentials are resolved from the environment
+		// and signed with V4.
+		probed.api = "S3v4"
+		if hostCfg.AccessKey == "" && hostCfg.SecretKey == "" {
+			return
+		}
+		api, err := probeS3Signature(globalContext, hostCfg.AccessKey, hostCfg.SecretKey, hostCfg.URL, hostCfg.Region, nil)
+		if err != nil {
+			return
+		}
+		probed.api = normalizeAPI(api)
+		if hostCfg.API == "" {
+			saveAliasSignature(alias, hostCfg, probed.api)
+		}
+	})
+	return probed.api
+}
+
+// saveAliasSignature - sets the API of the alias in the configuration
+// file, unless the alias was changed meanwhile. Failures are ignored,
+// the signature is probed again by the next command.
+func saveAliasSignature(alias string, hostCfg *aliasConfigV10, api string) {
+	if !isMcConfigExists() {
+		return
+	}
+	updateMcConfig(func(config *configV10) *probe.Error {
+		aliasCfg, ok := config.Aliases[alias]
+		if !ok || aliasCfg.API != "" || aliasCfg.URL != hostCfg.URL || aliasCfg.AccessKey != hostCfg.AccessKey {
+			return nil
+		}
+		aliasCfg.API = api
+		config.Aliases[alias] = aliasCfg
+		return nil
+	})
 }
 
 // urlRgx - verify if aliased url is real URL.
-var urlRgx = regexp.MustCompile("^https?://")
+var urlRgx = regexp.MustCompile("(?i)^https?://")
 
 // newClient gives a new client interface
 func newClient(aliasedURL string) (Client, *probe.Error) {
@@ -637,5 +906,13 @@ type uploadSourceToTargetURLOpts struct {
 	preserve, isZip     bool
 	multipartSize       string
 	multipartThreads    string
+	multipartThreshold  string
 	updateProgressTotal bool
+	verify              bool
+	scanner             *downloadScanner
+	sparse              bool
+	skipMatchingParts   bool
+	rangeStart          int64
+	ranged              bool
+	resume              bool
 }
@@ -0,0 +1,179 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/env"
+)
+
+var completionFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "remote",
+		Usage: "complete the buckets and objects of the aliases too, listed from the servers at each completion",
+	},
+}
+
+var completionCmd = cli.Command{
+	Name:         "completion",
+	Usage:        "print the completion script of a shell",
+	Action:       mainCompletion,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(completionFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [--remote] bash|zsh|fish
+
+  The script completes the commands, the flags and the aliases, by running
+  this mc binary, without connecting to the servers of the aliases unless
+  --remote is set.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Enable the completion in the current bash session.
+     {{.Prompt}} source <({{.HelpName}} bash)
+
+  2. Install the completion of zsh, with the buckets and objects of the aliases.
+     {{.Prompt}} {{.HelpName}} --remote zsh > "${fpath[1]}/_mc"
+
+  3. Install the completion of fish.
+     {{.Prompt}} {{.HelpName}} fish > ~/.config/fish/completions/mc.fish
+`,
+}
+
+// Environment variable set to 'off' by the completion scripts without
+// --remote, the completion installed by --autocompletion lists the
+// servers.
+const envCompletionRemote = "MC_COMPLETION_REMOTE"
+
+// isRemoteCompletion - reports whether the completion may list the buckets
+// and objects of the aliases.
+func isRemoteCompletion() bool {
+	return env.Get(envCompletionRemote, "on") != "off"
+}
+
+var completionScripts = map[string]string{
+	"bash": `# bash completion of {{.Cmd}}, generated by '{{.Cmd}} completion bash'.
+{{.Func}}() {
+    local IFS=$'\n'
+    COMPREPLY=($(COMP_LINE="$COMP_LINE" COMP_POINT="$COMP_POINT" {{.Env}}={{.Remote}} {{.Bin}} {{.Cmd}}))
+    if [[ ${#COMPREPLY[@]} -eq 1 && ${COMPREPLY[0]} == */ ]]; then
+        compopt -o nospace
+    fi
+}
+complete -F {{.Func}} {{.Cmd}}
+`,
+	"zsh": `#compdef {{.Cmd}}
+# zsh completion of {{.Cmd}}, generated by '{{.Cmd}} completion zsh'.
+{{.Func}}() {
+    local -a completions folders others
+    completions=(${(f)"$(COMP_LINE="${BUFFER[1,CURSOR]}" COMP_POINT=$CURSOR {{.Env}}={{.Remote}} {{.Bin}} {{.Cmd}})"})
+    folders=(${(M)completions:#*/})
+    others=(${completions:#*/})
+    (( ${#folders} )) && compadd -Q -S '' -- "${folders[@]}"
+    (( ${#others} )) && compadd -Q -- "${others[@]}"
+}
+if [[ "$funcstack[1]" == "{{.Func}}" ]]; then
+    {{.Func}} "$@"
+else
+    compdef {{.Func}} {{.Cmd}}
+fi
+`,
+	"fish": `# fish completion of {{.Cmd}}, generated by '{{.Cmd}} completion fish'.
+function {{.Func}}
+    set -lx COMP_LINE (commandline -cp)
+    test -z (commandline -ct)
+    and set COMP_LINE "$COMP_LINE "
+    set -lx COMP_POINT (string length -- "$COMP_LINE")
+    set -lx {{.Env}} {{.Remote}}
+    {{.Bin}} {{.Cmd}}
+end
+complete -c {{.Cmd}} -f -a '({{.Func}})'
+`,
+}
+
+// posixQuote - single quotes s for bash and zsh.
+func posixQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote - quotes s for fish.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// completionScript - returns the completion script of shell for the
+// command cmd, completed by the binary bin.
+func completionScript(shell, cmd, bin string, remote bool) (string, *probe.Error) {
+	text, ok := completionScripts[shell]
+	if !ok {
+		return "", probe.NewError(fmt.Errorf("unsupported shell `%s`, the supported shells are bash, zsh and fish", shell))
+	}
+	params := struct {
+		Cmd, Func, Bin, Env, Remote string
+	}{
+		Cmd:    cmd,
+		Func:   "__" + regexp.MustCompile(`[^A-Za-z0-9_]`).ReplaceAllString(cmd, "_") + "_complete",
+		Bin:    posixQuote(bin),
+		Env:    envCompletionRemote,
+		Remote: "off",
+	}
+	if shell == "fish" {
+		params.Bin = fishQuote(bin)
+	}
+	if remote {
+		params.Remote = "on"
+	}
+	var buf bytes.Buffer
+	if e := template.Must(template.New(shell).Parse(text)).Execute(&buf, params); e != nil {
+		return "", probe.NewError(e)
+	}
+	return buf.String(), nil
+}
+
+// main for completion command.
+func mainCompletion(cliCtx *cli.Context) error {
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	bin, e := os.Executable()
+	fatalIf(probe.NewError(e), "Unable to find the mc binary.")
+	if resolved, e := filepath.EvalSymlinks(bin); e == nil {
+		bin = resolved
+	}
+	cmd := strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+
+	script, err := completionScript(cliCtx.Args().First(), cmd, bin, cliCtx.Bool("remote"))
+	fatalIf(err, "Unable to print the completion script.")
+	fmt.Print(script)
+	return nil
+}
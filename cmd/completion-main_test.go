@@ -0,0 +1,75 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompletionScript(t *testing.T) {
+	testCases := []struct {
+		shell  string
+		cmd    string
+		bin    string
+		remote bool
+		want   []string
+	}{
+		{"bash", "mc", "/usr/local/bin/mc", false, []string{
+			"__mc_complete() {",
+			"MC_COMPLETION_REMOTE=off '/usr/local/bin/mc' mc))",
+			"complete -F __mc_complete mc\n",
+		}},
+		{"zsh", "mcli", "/opt/it's/mcli", true, []string{
+			"#compdef mcli\n",
+			`MC_COMPLETION_REMOTE=on '/opt/it'\''s/mcli' mcli)"})`,
+			"compdef __mcli_complete mcli\n",
+		}},
+		{"fish", "minio-client", `C:\mc\minio-client`, false, []string{
+			"function __minio_client_complete\n",
+			"set -lx MC_COMPLETION_REMOTE off\n",
+			`    'C:\\mc\\minio-client' minio-client`,
+			"complete -c minio-client -f -a '(__minio_client_complete)'\n",
+		}},
+	}
+	for i, testCase := range testCases {
+		script, err := completionScript(testCase.shell, testCase.cmd, testCase.bin, testCase.remote)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		for _, want := range testCase.want {
+			if !strings.Contains(script, want) {
+				t.Errorf("Test %d: %q not found in\n%s", i+1, want, script)
+			}
+		}
+	}
+	if _, err := completionScript("tcsh", "mc", "/usr/local/bin/mc", false); err == nil {
+		t.Errorf("tcsh scripts should not be supported")
+	}
+}
+
+func TestIsRemoteCompletion(t *testing.T) {
+	t.Setenv(envCompletionRemote, "")
+	if !isRemoteCompletion() {
+		t.Errorf("the completion should list the servers by default")
+	}
+	t.Setenv(envCompletionRemote, "off")
+	if isRemoteCompletion() || completeS3Path("play/") != nil {
+		t.Errorf("the completion should not list the servers with %s=off", envCompletionRemote)
+	}
+}
@@ -32,6 +32,7 @@ var configCmd = cli.Command{
 	Flags:           globalFlags,
 	Subcommands: []cli.Command{
 		configHostCmd,
+		configMigrateCmd,
 	},
 }
 
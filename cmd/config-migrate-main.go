@@ -0,0 +1,88 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+var configMigrateCmd = cli.Command{
+	Name:            "migrate",
+	Usage:           "migrate the configuration file to the latest version",
+	Action:          mainConfigMigrate,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	OnUsageError:    onUsageError,
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}}
+
+  Configuration files of older versions are migrated on every command, this
+  command migrates them and validates the result without doing anything else.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Migrate the configuration file to the latest version.
+     {{.Prompt}} {{.HelpName}}
+
+  2. Migrate the configuration file in a custom configuration folder.
+     {{.Prompt}} MC_CONFIG_DIR=/opt/mc/ {{.HelpName}}
+`,
+}
+
+// configMigrateMessage - the version of a migrated configuration file.
+type configMigrateMessage struct {
+	Status  string `json:"status"`
+	Path    string `json:"path"`
+	Version string `json:"version"`
+}
+
+func (m configMigrateMessage) String() string {
+	return console.Colorize("ConfigMigrate", "Configuration `"+m.Path+"` is at version `"+m.Version+"`.")
+}
+
+func (m configMigrateMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func mainConfigMigrate(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	console.SetColor("ConfigMigrate", color.New(color.FgGreen))
+
+	// The configuration file was migrated and validated before the
+	// command ran, read it again to report its version.
+	config, err := reloadConfigV10()
+	fatalIf(err.Trace(mustGetMcConfigPath()), "Unable to read the configuration file.")
+
+	printMsg(configMigrateMessage{Path: mustGetMcConfigPath(), Version: config.Version})
+	return nil
+}
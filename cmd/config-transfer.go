@@ -0,0 +1,90 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// globalTransfer - transfer section of the configuration file, loaded by
+// the commands transferring objects.
+var globalTransfer transferConfigV10
+
+// validate - returns an error for the first invalid option.
+func (t transferConfigV10) validate() *probe.Error {
+	invalid := func(option, value, reason string) *probe.Error {
+		return probe.NewError(fmt.Errorf("invalid `transfer.%s` %q in the configuration file, %s", option, value, reason))
+	}
+	if t.Parallel < 0 || t.Parallel > maxParallelWorkers {
+		return invalid("parallel", fmt.Sprint(t.Parallel), fmt.Sprintf("it should be between 1 and %d", maxParallelWorkers))
+	}
+	if t.PartSize != "" {
+		partSize, e := humanize.ParseBytes(t.PartSize)
+		if e != nil || partSize < minPartSize || partSize > maxPartSize {
+			return invalid("partSize", t.PartSize, "it should be between 5MiB and 5GiB")
+		}
+	}
+	if t.ParallelParts < 0 {
+		return invalid("parallelParts", fmt.Sprint(t.ParallelParts), "it should be equal or greater than 1")
+	}
+	if t.Retries != nil && *t.Retries < 0 {
+		return invalid("retries", fmt.Sprint(*t.Retries), "it should be equal or greater than 0")
+	}
+	for option, limit := range map[string]string{"limitUpload": t.LimitUpload, "limitDownload": t.LimitDownload} {
+		if _, e := parseRateLimit(limit); limit != "" && e != nil {
+			return invalid(option, limit, "it should be a rate such as 10MiB/s")
+		}
+	}
+	if t.MultipartThreshold != "" {
+		threshold, e := humanize.ParseBytes(t.MultipartThreshold)
+		if e != nil || threshold > maxPartSize {
+			return invalid("multipartThreshold", t.MultipartThreshold, "it should be a size of at most 5GiB")
+		}
+	}
+	if t.ConfirmAbove != "" {
+		if _, e := humanize.ParseBytes(t.ConfirmAbove); e != nil {
+			return invalid("confirmAbove", t.ConfirmAbove, "it should be a size such as 500GiB")
+		}
+	}
+	return nil
+}
+
+// loadTransferConfig - loads the transfer section of the configuration
+// file, and applies its rate limits unless set by the flags or the
+// environment. The other options are read by the commands.
+func loadTransferConfig() *probe.Error {
+	config, err := loadMcConfig()
+	if err != nil || config.Transfer == nil {
+		// A missing configuration is reported by the aliases.
+		return nil
+	}
+	if err = config.Transfer.validate(); err != nil {
+		return err
+	}
+	globalTransfer = *config.Transfer
+	if globalLimitUpload == 0 && globalTransfer.LimitUpload != "" {
+		globalLimitUpload, _ = parseRateLimit(globalTransfer.LimitUpload)
+	}
+	if globalLimitDownload == 0 && globalTransfer.LimitDownload != "" {
+		globalLimitDownload, _ = parseRateLimit(globalTransfer.LimitDownload)
+	}
+	return nil
+}
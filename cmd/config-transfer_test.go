@@ -0,0 +1,102 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestLoadTransferConfig(t *testing.T) {
+	load, limitUpload, limitDownload, transfer := loadMcConfig, globalLimitUpload, globalLimitDownload, globalTransfer
+	defer func() {
+		loadMcConfig, globalLimitUpload, globalLimitDownload, globalTransfer = load, limitUpload, limitDownload, transfer
+	}()
+
+	testCases := []struct {
+		config        string
+		limitUpload   uint64 // set on the command line
+		err           bool
+		wantUpload    uint64
+		wantDownload  uint64
+		wantParallel  int
+		wantThreshold string
+	}{
+		{`{"version":"10","aliases":{}}`, 0, false, 0, 0, 0, ""},
+		{`{"version":"10","aliases":{},"transfer":{"parallel":8,"limitUpload":"1MiB/s","limitDownload":"2MiB","multipartThreshold":"64MiB"}}`, 0, false, 1 << 20, 2 << 20, 8, "64MiB"},
+		// The flags win over the configuration file.
+		{`{"version":"10","aliases":{},"transfer":{"limitUpload":"1MiB/s"}}`, 512, false, 512, 0, 0, ""},
+		{`{"version":"10","aliases":{},"transfer":{"partSize":"1MiB"}}`, 0, true, 0, 0, 0, ""},
+		{`{"version":"10","aliases":{},"transfer":{"parallel":1000}}`, 0, true, 0, 0, 0, ""},
+		{`{"version":"10","aliases":{},"transfer":{"retries":-1}}`, 0, true, 0, 0, 0, ""},
+		{`{"version":"10","aliases":{},"transfer":{"limitDownload":"fast"}}`, 0, true, 0, 0, 0, ""},
+		{`{"version":"10","aliases":{},"transfer":{"multipartThreshold":"6GiB"}}`, 0, true, 0, 0, 0, ""},
+	}
+	for i, testCase := range testCases {
+		conf := new(configV10)
+		if e := json.Unmarshal([]byte(testCase.config), conf); e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		loadMcConfig = func() (*configV10, *probe.Error) { return conf, nil }
+		globalLimitUpload, globalLimitDownload, globalTransfer = testCase.limitUpload, 0, transferConfigV10{}
+
+		err := loadTransferConfig()
+		if (err != nil) != testCase.err {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if err != nil {
+			continue
+		}
+		if globalLimitUpload != testCase.wantUpload || globalLimitDownload != testCase.wantDownload {
+			t.Errorf("Test %d: got limits %d, %d", i+1, globalLimitUpload, globalLimitDownload)
+		}
+		if globalTransfer.Parallel != testCase.wantParallel || globalTransfer.MultipartThreshold != testCase.wantThreshold {
+			t.Errorf("Test %d: got %+v", i+1, globalTransfer)
+		}
+	}
+}
+
+func TestGetRetriesTransferConfig(t *testing.T) {
+	load, retries := loadMcConfig, globalRetries
+	defer func() { loadMcConfig, globalRetries = load, retries }()
+
+	one, five := 1, 5
+	conf := &configV10{Retries: &one, Transfer: &transferConfigV10{Retries: &five}}
+	conf.moveRetries()
+	loadMcConfig = func() (*configV10, *probe.Error) { return conf, nil }
+	globalRetries = -1
+	if n := getRetries(); n != 5 {
+		t.Errorf("got %d retries, want those of the transfer section", n)
+	}
+
+	// The top level retries of older configs move into the transfer section.
+	conf = &configV10{Retries: &one}
+	conf.moveRetries()
+	if conf.Retries != nil || conf.Transfer == nil {
+		t.Fatalf("expected the top level retries moved, got %+v", conf)
+	}
+	if n := getRetries(); n != 1 {
+		t.Errorf("got %d retries, want the top level retries", n)
+	}
+	globalRetries = 0
+	if n := getRetries(); n != 0 {
+		t.Errorf("got %d retries, want those of --retries", n)
+	}
+}
@@ -19,7 +19,14 @@ package cmd
 
 import "strings"
 
-var validAPIs = []string{"S3v4", "S3v2"}
+var validAPIs = []string{"S3v4", "S3v2", anonymousAPI, autoAPI}
+
+const (
+	// anonymousAPI - requests to the alias are not signed.
+	anonymousAPI = "anonymous"
+	// autoAPI - the signature of the alias is probed when used.
+	autoAPI = "auto"
+)
 
 const (
 	accessKeyMinLen = 3
@@ -64,12 +71,26 @@ func isValidHostURL(hostURL string) (ok bool) {
 // isValidAPI - Validates if API signature string of supported type.
 func isValidAPI(api string) (ok bool) {
 	switch strings.ToLower(api) {
-	case "s3v2", "s3v4":
+	case "s3v2", "s3v4", "v2", "v4", anonymousAPI, autoAPI, azureAPI, gcsAPI:
 		ok = true
 	}
 	return ok
 }
 
+// normalizeAPI - returns the API signature as saved in the config, v2 and
+// v4 are short for S3v2 and S3v4.
+func normalizeAPI(api string) string {
+	switch strings.ToLower(api) {
+	case "s3v2", "v2":
+		return "S3v2"
+	case "s3v4", "v4":
+		return "S3v4"
+	case anonymousAPI, autoAPI, azureAPI, gcsAPI, sftpAPI:
+		return strings.ToLower(api)
+	}
+	return api
+}
+
 // isValidLookup - validates if bucket lookup is of valid type
 func isValidLookup(lookup string) (ok bool) {
 	l := strings.ToLower(strings.TrimSpace(lookup))
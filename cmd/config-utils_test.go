@@ -77,3 +77,35 @@ func TestValidAccessKeys(t *testing.T) {
 	equalAssert(isValidAccessKey("EXOb76bfeb1234562iu679f11588"), true, t)
 	equalAssert(isValidAccessKey("BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"), true, t)
 }
+
+// Tests the endpoints and signing regions of provider presets.
+func TestProviderPresetResolve(t *testing.T) {
+	testCases := []struct {
+		provider string
+		location string
+		url      string
+		region   string
+	}{
+		{"aws-cn", "cn-north-1", "https://s3.cn-north-1.amazonaws.com.cn", "cn-north-1"},
+		{"AWS-US-GOV", "us-gov-east-1", "https://s3.us-gov-east-1.amazonaws.com", "us-gov-east-1"},
+		{"wasabi", "eu-central-1", "https://s3.eu-central-1.wasabisys.com", "eu-central-1"},
+		{"digitalocean", "ams3", "https://ams3.digitaloceanspaces.com", "ams3"},
+		{"cloudflare-r2", "abcdef", "https://abcdef.r2.cloudflarestorage.com", "auto"},
+		{"cloudflare-r2", "https://abcdef.eu.r2.cloudflarestorage.com", "https://abcdef.eu.r2.cloudflarestorage.com", "auto"},
+		{"wasabi", "https://s3.wasabisys.com", "https://s3.wasabisys.com", ""},
+	}
+
+	for i, testCase := range testCases {
+		preset, ok := lookupProviderPreset(testCase.provider)
+		if !ok {
+			t.Fatalf("Test %d: unknown provider %s", i+1, testCase.provider)
+		}
+		url, region := preset.resolve(testCase.location)
+		if url != testCase.url || region != testCase.region {
+			t.Errorf("Test %d: expected %s %s, got %s %s", i+1, testCase.url, testCase.region, url, region)
+		}
+	}
+	if _, ok := lookupProviderPreset("unknown"); ok {
+		t.Errorf("Expected unknown provider to be rejected")
+	}
+}
@@ -44,14 +44,73 @@ type aliasConfigV10 struct {
 	SessionToken string `json:"sessionToken,omitempty"`
 	API          string `json:"api"`
 	Path         string `json:"path"`
+	Provider     string `json:"provider,omitempty"`
+	Region       string `json:"region,omitempty"`
 	License      string `json:"license,omitempty"`
 	APIKey       string `json:"apiKey,omitempty"`
+
+	UploadRules *uploadRulesV10 `json:"uploadRules,omitempty"`
+}
+
+// uploadRulesV10 - rules checked by mc before uploading objects to an
+// alias, the servers don't know about them.
+type uploadRulesV10 struct {
+	// Largest object uploaded, e.g. "100MiB".
+	MaxSize string `json:"maxSize,omitempty"`
+	// Content types uploaded, e.g. "image/*", any when empty.
+	ContentTypes []string `json:"contentTypes,omitempty"`
+	// Extensions of the objects never uploaded, e.g. ".exe".
+	ForbiddenExtensions []string `json:"forbiddenExtensions,omitempty"`
+}
+
+// transferConfigV10 - defaults of the transfer options of cp, mv, mirror
+// and pipe, used unless set by their flags or environment variables.
+type transferConfigV10 struct {
+	// Objects copied in parallel, adjusted to the bandwidth when zero.
+	Parallel      int    `json:"parallel,omitempty"`
+	PartSize      string `json:"partSize,omitempty"`
+	ParallelParts int    `json:"parallelParts,omitempty"`
+	Retries       *int   `json:"retries,omitempty"`
+	LimitUpload   string `json:"limitUpload,omitempty"`
+	LimitDownload string `json:"limitDownload,omitempty"`
+	// Objects smaller than the threshold are uploaded in one request.
+	MultipartThreshold string `json:"multipartThreshold,omitempty"`
+	// Transfers larger than this size are confirmed before they start.
+	ConfirmAbove string `json:"confirmAbove,omitempty"`
+}
+
+// encryptKeyV10 - encryption key of an alias prefix, applied to every
+// command accessing objects under the prefix.
+type encryptKeyV10 struct {
+	// Base64 encoded 32 bytes key.
+	Key string `json:"key"`
+	// Either "sse-c", the default, or "client".
+	Type string `json:"type,omitempty"`
+}
+
+// credentialProfileV10 - named credentials of an endpoint, used by all its
+// aliases instead of their own with --profile.
+type credentialProfileV10 struct {
+	AccessKey    string `json:"accessKey"`
+	SecretKey    string `json:"secretKey"`
+	SessionToken string `json:"sessionToken,omitempty"`
+}
+
+// hostConfigV10 - configuration of an endpoint, keyed by its URL.
+type hostConfigV10 struct {
+	Profiles map[string]credentialProfileV10 `json:"profiles"`
 }
 
 // configV10 config version.
 type configV10 struct {
-	Version string                    `json:"version"`
-	Aliases map[string]aliasConfigV10 `json:"aliases"`
+	Version     string                    `json:"version"`
+	Aliases     map[string]aliasConfigV10 `json:"aliases"`
+	Hosts       map[string]hostConfigV10  `json:"hosts,omitempty"`
+	EncryptKeys map[string]encryptKeyV10  `json:"encryptKeys,omitempty"`
+	// Retries of older configs, moved into the transfer section when
+	// the config is read.
+	Retries  *int               `json:"retries,omitempty"`
+	Transfer *transferConfigV10 `json:"transfer,omitempty"`
 }
 
 // newConfigV10 - new config version.
@@ -110,14 +169,27 @@ func (c *configV10) loadDefaults() {
 
 // loadConfigV10 - loads a new config.
 func loadConfigV10() (*configV10, *probe.Error) {
-	cfgMutex.RLock()
-	defer cfgMutex.RUnlock()
+	cfgMutex.Lock()
+	defer cfgMutex.Unlock()
 
 	// If already cached, return the cached value.
 	if cacheCfgV10 != nil {
 		return cacheCfgV10, nil
 	}
+	return readConfigV10()
+}
 
+// reloadConfigV10 - loads the config from disk again, with the changes
+// saved by other mc processes since it was cached.
+func reloadConfigV10() (*configV10, *probe.Error) {
+	cfgMutex.Lock()
+	defer cfgMutex.Unlock()
+
+	return readConfigV10()
+}
+
+// readConfigV10 - reads and caches the config, cfgMutex is held.
+func readConfigV10() (*configV10, *probe.Error) {
 	if !isMcConfigExists() {
 		return nil, errInvalidArgument().Trace()
 	}
@@ -135,6 +207,7 @@ func loadConfigV10() (*configV10, *probe.Error) {
 	}
 
 	cfgV10 := qc.Data().(*configV10)
+	cfgV10.moveRetries()
 
 	// Cache config.
 	cacheCfgV10 = cfgV10
@@ -143,6 +216,21 @@ func loadConfigV10() (*configV10, *probe.Error) {
 	return cfgV10, nil
 }
 
+// moveRetries - moves the top level retries of older configs into the
+// transfer section, whose retries win when both are set.
+func (c *configV10) moveRetries() {
+	if c.Retries == nil {
+		return
+	}
+	if c.Transfer == nil {
+		c.Transfer = &transferConfigV10{}
+	}
+	if c.Transfer.Retries == nil {
+		c.Transfer.Retries = c.Retries
+	}
+	c.Retries = nil
+}
+
 // saveConfigV10 - saves an updated config.
 func saveConfigV10(cfgV10 *configV10) *probe.Error {
 	cfgMutex.Lock()
@@ -19,7 +19,10 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+
+	"github.com/minio/mc/pkg/probe"
 )
 
 // Check if version of the config is valid
@@ -31,36 +34,51 @@ func validateConfigVersion(config *configV10) (bool, string) {
 	return true, ""
 }
 
-// Verifies the config file of the MinIO Client
+// Verifies the config file of the MinIO Client, errors name the offending
+// field as `aliases.<alias>.<field>`.
 func validateConfigFile(config *configV10) (bool, []string) {
 	ok, err := validateConfigVersion(config)
 	validationSuccessful := true
 	var errors []string
 	if !ok {
 		validationSuccessful = false
-		errors = append(errors, err)
+		errors = append(errors, "`version`: "+err)
+	}
+	aliases := make([]string, 0, len(config.Aliases))
+	for alias := range config.Aliases {
+		aliases = append(aliases, alias)
 	}
-	aliases := config.Aliases
-	for _, aliasConfig := range aliases {
-		aliasConfigHealthOk, aliasErrors := validateConfigHost(aliasConfig)
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		aliasConfigHealthOk, aliasErrors := validateConfigHost(alias, config.Aliases[alias])
 		if !aliasConfigHealthOk {
 			validationSuccessful = false
 			errors = append(errors, aliasErrors...)
 		}
 	}
+	// Retries apply to every command, unlike the other transfer options
+	// validated by the commands transferring objects.
+	if config.Transfer != nil && config.Transfer.Retries != nil && *config.Transfer.Retries < 0 {
+		validationSuccessful = false
+		errors = append(errors, fmt.Sprintf("`transfer.retries`: %d should be equal or greater than 0", *config.Transfer.Retries))
+	}
 	return validationSuccessful, errors
 }
 
-func validateConfigHost(host aliasConfigV10) (bool, []string) {
+func validateConfigHost(alias string, host aliasConfigV10) (bool, []string) {
 	validationSuccessful := true
 	var hostErrors []string
-	if !isValidAPI(strings.ToLower(host.API)) {
+	fieldError := func(field string, err *probe.Error) {
 		validationSuccessful = false
-		hostErrors = append(hostErrors, errInvalidAPISignature(host.API, host.URL).ToGoError().Error())
+		hostErrors = append(hostErrors, fmt.Sprintf("`%s`: %s", field, err.ToGoError()))
+	}
+	field := "aliases." + alias
+	// Without an API, the signature is probed as with `auto`.
+	if host.API != "" && !isValidAPI(strings.ToLower(host.API)) {
+		fieldError(field+".api", errInvalidAPISignature(host.API, host.URL))
 	}
 	if !isValidHostURL(host.URL) {
-		validationSuccessful = false
-		hostErrors = append(hostErrors, errInvalidURL(host.URL).ToGoError().Error())
+		fieldError(field+".url", errInvalidURL(host.URL))
 	}
 	return validationSuccessful, hostErrors
 }
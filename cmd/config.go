@@ -51,9 +51,45 @@ func getMcConfigDir() (string, *probe.Error) {
 		return "", probe.NewError(e)
 	}
 	configDir := filepath.Join(homeDir, defaultMCConfigDir())
+	if xdgDir, ok := xdgBaseDir("XDG_CONFIG_HOME", configDir); ok {
+		return xdgDir, nil
+	}
 	return configDir, nil
 }
 
+// getMcStateDir - construct MinIO Client folder of the sessions, shares,
+// usage ledger and profiles, the config folder unless XDG_STATE_HOME is set.
+func getMcStateDir() (string, *probe.Error) {
+	if mcCustomConfigDir != "" {
+		return mcCustomConfigDir, nil
+	}
+	homeDir, e := homedir.Dir()
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	if xdgDir, ok := xdgBaseDir("XDG_STATE_HOME", filepath.Join(homeDir, defaultMCConfigDir())); ok {
+		return xdgDir, nil
+	}
+	return getMcConfigDir()
+}
+
+// xdgBaseDir - returns the mc folder under the XDG base directory set in
+// the environment variable key. The legacy folder is kept when it exists.
+func xdgBaseDir(key, legacyDir string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		return "", false
+	}
+	// Relative paths are invalid and ignored by the specification.
+	baseDir := env.Get(key, "")
+	if !filepath.IsAbs(baseDir) {
+		return "", false
+	}
+	if _, e := os.Stat(legacyDir); !os.IsNotExist(e) {
+		return "", false
+	}
+	return filepath.Join(baseDir, filepath.Base(os.Args[0])), true
+}
+
 // Return default default mc config directory.
 // Generally you want to use getMcConfigDir which returns custom overrides.
 func defaultMCConfigDir() string {
@@ -88,6 +124,18 @@ func createMcConfigDir() *probe.Error {
 	return nil
 }
 
+// createMcStateDir - create MinIO Client state folder
+func createMcStateDir() *probe.Error {
+	p, err := getMcStateDir()
+	if err != nil {
+		return err.Trace()
+	}
+	if e := os.MkdirAll(p, 0o700); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
 // getMcConfigPath - construct MinIO Client configuration path
 func getMcConfigPath() (string, *probe.Error) {
 	if mcCustomConfigDir != "" {
@@ -150,6 +198,32 @@ func saveMcConfig(config *configV10) *probe.Error {
 	return nil
 }
 
+// updateMcConfig - applies update to the config on disk and saves it, locked
+// against concurrent mc processes so that none of their changes are lost.
+// A new config is updated when none exists yet.
+func updateMcConfig(update func(config *configV10) *probe.Error) *probe.Error {
+	err := createMcConfigDir()
+	if err != nil {
+		return err.Trace(mustGetMcConfigDir())
+	}
+	unlock, err := lockStateFile(mustGetMcConfigPath())
+	if err != nil {
+		return err.Trace()
+	}
+	defer unlock()
+
+	config := newMcConfig()
+	if isMcConfigExists() {
+		if config, err = reloadConfigV10(); err != nil {
+			return err.Trace(mustGetMcConfigPath())
+		}
+	}
+	if err = update(config); err != nil {
+		return err.Trace()
+	}
+	return saveMcConfig(config)
+}
+
 // isMcConfigExists returns err if config doesn't exist.
 func isMcConfigExists() bool {
 	configFile, err := getMcConfigPath()
@@ -185,6 +259,14 @@ func getAliasConfig(alias string) (*aliasConfigV10, *probe.Error) {
 	// if host is exact return quickly.
 	if _, ok := mcCfg.Aliases[alias]; ok {
 		hostCfg := mcCfg.Aliases[alias]
+		if globalProfile != "" {
+			profile, ok := mcCfg.Hosts[hostConfigKey(hostCfg.URL)].Profiles[globalProfile]
+			if !ok {
+				fatalIf(errInvalidArgument().Trace(alias, globalProfile),
+					"Profile `"+globalProfile+"` is not configured for `"+hostCfg.URL+"` of alias `"+alias+"`.")
+			}
+			hostCfg.AccessKey, hostCfg.SecretKey, hostCfg.SessionToken = profile.AccessKey, profile.SecretKey, profile.SessionToken
+		}
 		return &hostCfg, nil
 	}
 
@@ -192,6 +274,12 @@ func getAliasConfig(alias string) (*aliasConfigV10, *probe.Error) {
 	return nil, errNoMatchingHost(alias).Trace(alias)
 }
 
+// hostConfigKey - returns the key of the endpoint of an alias URL in the
+// hosts of the config.
+func hostConfigKey(urlStr string) string {
+	return strings.ToLower(strings.TrimSuffix(urlStr, "/"))
+}
+
 // mustGetHostConfig retrieves host specific configuration such as access keys, signature type.
 func mustGetHostConfig(alias string) *aliasConfigV10 {
 	aliasCfg, _ := getAliasConfig(alias)
@@ -311,6 +399,19 @@ func expandAliasFromEnv(envURL string) (*aliasConfigV10, *probe.Error) {
 
 // expandAlias expands aliased URL if any match is found, returns as is otherwise.
 func expandAlias(aliasedURL string) (alias, urlStr string, aliasCfg *aliasConfigV10, err *probe.Error) {
+	if aliasedURL, err = normalizeAliasedURL(aliasedURL); err != nil {
+		return "", "", nil, err.Trace(aliasedURL)
+	}
+	if strings.HasPrefix(aliasedURL, azureURLScheme) {
+		return expandAzureURL(aliasedURL)
+	}
+	if strings.HasPrefix(aliasedURL, gcsURLScheme) {
+		return expandGCSURL(aliasedURL)
+	}
+	if strings.HasPrefix(aliasedURL, sftpURLScheme) {
+		return expandSFTPURL(aliasedURL)
+	}
+
 	// Extract alias from the URL.
 	alias, path := url2Alias(aliasedURL)
 
@@ -337,6 +438,9 @@ func expandAlias(aliasedURL string) (alias, urlStr string, aliasCfg *aliasConfig
 
 // mustExpandAlias expands aliased URL if any match is found, returns as is otherwise.
 func mustExpandAlias(aliasedURL string) (alias, urlStr string, aliasCfg *aliasConfigV10) {
-	alias, urlStr, aliasCfg, _ = expandAlias(aliasedURL)
+	alias, urlStr, aliasCfg, err := expandAlias(aliasedURL)
+	if err != nil {
+		return "", aliasedURL, nil
+	}
 	return alias, urlStr, aliasCfg
 }
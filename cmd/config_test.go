@@ -17,7 +17,18 @@
 
 package cmd
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/quick"
+	"github.com/mitchellh/go-homedir"
+)
 
 // Tests valid host URL functionality.
 func TestParseEnvURLStr(t *testing.T) {
@@ -142,3 +153,173 @@ func TestParseEnvURLStrInvalid(t *testing.T) {
 		t.Fatalf("Expected failure")
 	}
 }
+
+func TestUpdateMcConfig(t *testing.T) {
+	useTestMcConfig(t)
+	cache := cacheCfgV10
+	t.Cleanup(func() { cacheCfgV10 = cache })
+	cacheCfgV10 = nil
+
+	add := func(alias string) func(config *configV10) *probe.Error {
+		return func(config *configV10) *probe.Error {
+			config.Aliases[alias] = aliasConfigV10{URL: "http://localhost:9000", API: "s3v4", Path: "auto"}
+			return nil
+		}
+	}
+	if err := updateMcConfig(add("first")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another mc process saves the config after this one cached it.
+	other := newMcConfig()
+	add("first")(other)
+	add("other")(other)
+	qs, e := quick.NewConfig(other, nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if e = qs.Save(mustGetMcConfigPath()); e != nil {
+		t.Fatal(e)
+	}
+
+	if err := updateMcConfig(add("second")); err != nil {
+		t.Fatal(err)
+	}
+	config, err := loadMcConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, alias := range []string{"first", "other", "second", "play"} {
+		if _, ok := config.Aliases[alias]; !ok {
+			t.Errorf("%s is missing", alias)
+		}
+	}
+
+	// Updates wait for the lock of another process.
+	unlock, err := lockStateFile(mustGetMcConfigPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		unlock()
+	}()
+	if err := updateMcConfig(add("third")); err != nil {
+		t.Fatal(err)
+	}
+	if _, e := os.Stat(mustGetMcConfigPath() + ".lock"); !os.IsNotExist(e) {
+		t.Errorf("expected the lock released, got %v", e)
+	}
+}
+
+func TestMcConfigDirXDG(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("XDG base directories are not used on windows")
+	}
+	configDir, disableCache := mcCustomConfigDir, homedir.DisableCache
+	t.Cleanup(func() { mcCustomConfigDir, homedir.DisableCache = configDir, disableCache })
+	mcCustomConfigDir, homedir.DisableCache = "", true
+
+	home, xdg := t.TempDir(), t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(xdg, "config"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(xdg, "state"))
+	name := filepath.Base(os.Args[0])
+	legacyDir := filepath.Join(home, defaultMCConfigDir())
+
+	dirs := func() (string, string) {
+		configDir, err := getMcConfigDir()
+		if err != nil {
+			t.Fatal(err)
+		}
+		stateDir, err := getMcStateDir()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return configDir, stateDir
+	}
+	testCases := []struct {
+		setup               func()
+		configDir, stateDir string
+	}{
+		{func() {}, filepath.Join(xdg, "config", name), filepath.Join(xdg, "state", name)},
+		// Relative base directories are ignored.
+		{func() { t.Setenv("XDG_STATE_HOME", "state") }, filepath.Join(xdg, "config", name), filepath.Join(xdg, "config", name)},
+		{func() { t.Setenv("XDG_CONFIG_HOME", "") }, legacyDir, legacyDir},
+		// An existing legacy folder is kept.
+		{func() {
+			t.Setenv("XDG_CONFIG_HOME", filepath.Join(xdg, "config"))
+			t.Setenv("XDG_STATE_HOME", filepath.Join(xdg, "state"))
+			if e := os.MkdirAll(legacyDir, 0o700); e != nil {
+				t.Fatal(e)
+			}
+		}, legacyDir, legacyDir},
+		// --config-dir holds everything.
+		{func() { mcCustomConfigDir = xdg }, xdg, xdg},
+	}
+	for i, testCase := range testCases {
+		testCase.setup()
+		configDir, stateDir := dirs()
+		if filepath.Clean(configDir) != filepath.Clean(testCase.configDir) || filepath.Clean(stateDir) != filepath.Clean(testCase.stateDir) {
+			t.Errorf("Test %d: expected %s and %s, got %s and %s", i+1, testCase.configDir, testCase.stateDir, configDir, stateDir)
+		}
+	}
+}
+
+func TestValidateConfigFile(t *testing.T) {
+	config := newConfigV10()
+	config.Aliases["b"] = aliasConfigV10{URL: "https://b.example.com", API: "S3v9"}
+	config.Aliases["a"] = aliasConfigV10{URL: "b.example.com/path", API: "S3v4"}
+	config.Aliases["ok"] = aliasConfigV10{URL: "https://ok.example.com", API: "S3v2"}
+	retries := -1
+	config.Transfer = &transferConfigV10{Retries: &retries}
+
+	ok, errs := validateConfigFile(config)
+	if ok {
+		t.Fatal("expected the config to be invalid")
+	}
+	fields := []string{"`aliases.a.url`: ", "`aliases.b.api`: ", "`transfer.retries`: "}
+	if len(errs) != len(fields) {
+		t.Fatalf("expected %d errors, got %v", len(fields), errs)
+	}
+	for i, field := range fields {
+		if !strings.HasPrefix(errs[i], field) {
+			t.Errorf("expected error %d to point at %s, got %q", i+1, field, errs[i])
+		}
+	}
+
+	config.Version = "9"
+	if _, errs = validateConfigFile(config); len(errs) == 0 || !strings.HasPrefix(errs[0], "`version`: ") {
+		t.Errorf("expected a version error, got %v", errs)
+	}
+}
+
+func TestCredentialProfile(t *testing.T) {
+	useTestMcConfig(t)
+	profile := globalProfile
+	t.Cleanup(func() { globalProfile = profile })
+
+	setAlias("myminio", aliasConfigV10{URL: "http://localhost:9000", AccessKey: "admin", SecretKey: "admin-secret", API: "S3v4", Path: "auto"})
+	setAliasProfile("myminio", "readonly", aliasConfigV10{URL: "http://localhost:9000/", AccessKey: "reader", SecretKey: "reader-secret", API: "S3v4", Path: "auto"})
+	setAliasProfile("other", "readonly", aliasConfigV10{URL: "http://LOCALHOST:9000", AccessKey: "reader2", SecretKey: "reader2-secret", API: "S3v4", Path: "auto"})
+	loadMcConfig = loadMcConfigFactory()
+
+	testCases := []struct {
+		profile, alias, accessKey string
+	}{
+		{"", "myminio", "admin"},
+		{"", "other", "reader2"},
+		{"readonly", "myminio", "reader2"},
+		{"readonly", "other", "reader2"},
+	}
+	for i, testCase := range testCases {
+		globalProfile = testCase.profile
+		hostCfg, err := getAliasConfig(testCase.alias)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if hostCfg.AccessKey != testCase.accessKey {
+			t.Errorf("Test %d: expected access key %s, got %s", i+1, testCase.accessKey, hostCfg.AccessKey)
+		}
+	}
+}
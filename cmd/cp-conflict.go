@@ -0,0 +1,176 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+// Policies applied by --on-conflict when the copy target already exists.
+const (
+	conflictOverwrite = "overwrite"
+	conflictSkip      = "skip"
+	conflictRename    = "rename"
+	conflictFail      = "fail"
+)
+
+// Maximum number of renamed candidates tried for a single target.
+const maxConflictRenames = 10000
+
+// copyConflictMessage - reports a copy skipped or renamed by --on-conflict.
+type copyConflictMessage struct {
+	Status  string `json:"status"`
+	Source  string `json:"source"`
+	Target  string `json:"target"`
+	Action  string `json:"action"`
+	Renamed string `json:"renamed,omitempty"`
+}
+
+// String colorized copy conflict message
+func (c copyConflictMessage) String() string {
+	if c.Action == conflictRename {
+		return console.Colorize("CopyConflict", fmt.Sprintf("`%s` already exists, copying `%s` -> `%s`", c.Target, c.Source, c.Renamed))
+	}
+	return console.Colorize("CopyConflict", fmt.Sprintf("`%s` already exists, skipping `%s`", c.Target, c.Source))
+}
+
+// JSON jsonified copy conflict message
+func (c copyConflictMessage) JSON() string {
+	c.Status = "success"
+	msgBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// copyConflictResolver - applies the --on-conflict policy to copy targets,
+// targets claimed by copies still in flight are treated as existing too.
+type copyConflictResolver struct {
+	policy string
+
+	mu      sync.Mutex
+	claimed map[string]struct{}
+}
+
+// newCopyConflictResolver - returns nil for the default overwrite policy.
+func newCopyConflictResolver(policy string) *copyConflictResolver {
+	if policy == "" || policy == conflictOverwrite {
+		return nil
+	}
+	return &copyConflictResolver{
+		policy:  policy,
+		claimed: make(map[string]struct{}),
+	}
+}
+
+// claim - reserves the target, returns false if it was already reserved.
+func (r *copyConflictResolver) claim(target string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.claimed[target]; ok {
+		return false
+	}
+	r.claimed[target] = struct{}{}
+	return true
+}
+
+// exists - claims the target URL and reports whether it is already in use.
+func (r *copyConflictResolver) exists(ctx context.Context, alias string, targetURL ClientURL, encKeyDB map[string][]prefixSSEPair) (bool, *probe.Error) {
+	if !r.claim(targetURL.String()) {
+		return true, nil
+	}
+	clnt, err := newClientFromAlias(alias, targetURL.String())
+	if err != nil {
+		return false, err
+	}
+	targetPath := filepath.ToSlash(filepath.Join(alias, targetURL.Path))
+	_, err = clnt.Stat(ctx, StatOptions{sse: getSSE(targetPath, encKeyDB[alias])})
+	if err == nil {
+		return true, nil
+	}
+	switch err.ToGoError().(type) {
+	case ObjectMissing, PathNotFound, BucketDoesNotExist:
+		return false, nil
+	}
+	return false, err
+}
+
+// resolve - applies the policy to cpURLs, skip is true when the copy must not happen.
+func (r *copyConflictResolver) resolve(ctx context.Context, cpURLs URLs, encKeyDB map[string][]prefixSSEPair) (_ URLs, skip bool, msg *copyConflictMessage) {
+	targetAlias := cpURLs.TargetAlias
+	targetURL := cpURLs.TargetContent.URL
+
+	exists, err := r.exists(ctx, targetAlias, targetURL, encKeyDB)
+	if err != nil {
+		return cpURLs.WithError(err.Trace(targetURL.String())), false, nil
+	}
+	if !exists {
+		return cpURLs, false, nil
+	}
+
+	msg = &copyConflictMessage{
+		Source: filepath.ToSlash(filepath.Join(cpURLs.SourceAlias, cpURLs.SourceContent.URL.Path)),
+		Target: filepath.ToSlash(filepath.Join(targetAlias, targetURL.Path)),
+		Action: r.policy,
+	}
+
+	switch r.policy {
+	case conflictSkip:
+		return cpURLs, true, msg
+	case conflictRename:
+		for i := 1; i <= maxConflictRenames; i++ {
+			renamedURL := targetURL
+			renamedURL.Path = conflictRenamedPath(targetURL.Path, targetURL.Separator, i)
+			exists, err = r.exists(ctx, targetAlias, renamedURL, encKeyDB)
+			if err != nil {
+				return cpURLs.WithError(err.Trace(renamedURL.String())), false, nil
+			}
+			if !exists {
+				target := *cpURLs.TargetContent
+				target.URL = renamedURL
+				cpURLs.TargetContent = &target
+				msg.Renamed = filepath.ToSlash(filepath.Join(targetAlias, renamedURL.Path))
+				return cpURLs, false, msg
+			}
+		}
+	}
+	return cpURLs.WithError(errTargetExists(msg.Target)), false, nil
+}
+
+// conflictRenamedPath - returns the n-th renamed candidate of a target path,
+// the suffix is inserted before the extension, e.g. photo.jpg -> photo-1.jpg
+func conflictRenamedPath(targetPath string, separator rune, n int) string {
+	i := strings.LastIndex(targetPath, string(separator)) + 1
+	dir, base := targetPath[:i], targetPath[i:]
+	ext := path.Ext(base)
+	if ext == base {
+		// Dot files such as .profile have no extension.
+		ext = ""
+	}
+	name := strings.TrimSuffix(base, ext)
+	return dir + name + "-" + strconv.Itoa(n) + ext
+}
@@ -26,6 +26,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 
 	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
@@ -60,24 +61,34 @@ var (
 			Usage: "copy objects newer than value in duration string (e.g. 7d10h31s)",
 		},
 		cli.StringFlag{
-			Name:  "storage-class, sc",
-			Usage: "set storage class for new object(s) on target",
+			Name:   "storage-class, sc",
+			Usage:  "set storage class for new object(s) on target",
+			EnvVar: envPrefix + "STORAGE_CLASS",
 		},
 		cli.StringFlag{
 			Name:  "attr",
 			Usage: "add custom metadata for the object",
 		},
+		cli.StringFlag{
+			Name:  "content-type",
+			Usage: "set the Content-Type of the new object(s) on target, instead of guessing it from the file extension",
+		},
+		cli.StringFlag{
+			Name:  "cache-control",
+			Usage: "set the Cache-Control of the new object(s) on target (e.g. 'max-age=3600')",
+		},
 		cli.BoolFlag{
 			Name:  "continue, c",
 			Usage: "create or resume copy session",
 		},
 		cli.BoolFlag{
 			Name:  "preserve, a",
-			Usage: "preserve filesystem attributes (mode, ownership, timestamps)",
+			Usage: "preserve object tags and filesystem attributes (mode, ownership, timestamps)",
 		},
 		cli.BoolFlag{
-			Name:  "disable-multipart",
-			Usage: "disable multipart upload feature",
+			Name:   "disable-multipart",
+			Usage:  "disable multipart upload feature",
+			EnvVar: envPrefix + "DISABLE_MULTIPART",
 		},
 		cli.BoolFlag{
 			Name:  "md5",
@@ -103,6 +114,63 @@ var (
 			Name:  "zip",
 			Usage: "Extract from remote zip file (MinIO server source only)",
 		},
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "report the object(s) that would be copied, without copying them",
+		},
+		cli.BoolFlag{
+			Name:  "verify",
+			Usage: "verify the checksum of each copied object against its source",
+		},
+		cli.StringSliceFlag{
+			Name:  "exclude",
+			Usage: "exclude object(s) that match specified object name pattern",
+		},
+		cli.StringSliceFlag{
+			Name:  "include",
+			Usage: "only copy object(s) that match specified object name pattern",
+		},
+		cli.StringFlag{
+			Name:  "name-conflict",
+			Usage: "keep the 'object' or the 'prefix' of an object named like a prefix, when copying to a filesystem",
+		},
+		cli.BoolFlag{
+			Name:  "flatten",
+			Usage: "copy all objects directly under the target, without their source folders",
+		},
+		cli.IntFlag{
+			Name:  "strip-components",
+			Usage: "strip NUMBER leading folders from the source object names on the target",
+		},
+		cli.StringFlag{
+			Name:  "name-template",
+			Usage: "rewrite target object names with a Go template (e.g. '{{.Dir}}/{{.NameLower}}{{.ExtLower}}')",
+		},
+		cli.StringFlag{
+			Name:  "organize-by",
+			Usage: "organize copied objects under YYYY/MM/DD/ prefixes of the target (exif-date)",
+		},
+		cli.StringFlag{
+			Name:  "on-conflict",
+			Usage: "action when the target object already exists: overwrite, skip, rename or fail",
+			Value: conflictOverwrite,
+		},
+		cli.BoolFlag{
+			Name:  "scan-secrets",
+			Usage: "warn of the AWS keys, private keys and tokens found in the uploaded text files",
+		},
+		cli.BoolFlag{
+			Name:  "block-secrets",
+			Usage: "skip the upload of the text files with AWS keys, private keys or tokens, implies --scan-secrets",
+		},
+		cli.Int64Flag{
+			Name:  "offset",
+			Usage: "copy the part of the source object starting at this byte offset",
+		},
+		cli.Int64Flag{
+			Name:  "length",
+			Usage: "copy this number of bytes of the source object, from --offset",
+		},
 	}
 )
 
@@ -122,7 +190,7 @@ var cpCmd = cli.Command{
 	Action:       mainCopy,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(append(cpFlags, ioFlags...), globalFlags...),
+	Flags:        append(append(append(append(append(append(cpFlags, downloadScanFlags...), downloadSparseFlags...), transferConfirmFlags...), multipartFlags...), ioFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -198,6 +266,64 @@ EXAMPLES:
   20. Set tags to the uploaded objects
       {{.Prompt}} {{.HelpName}} -r --tags "category=prod&type=backup" ./data/ play/another-bucket/
 
+  21. Copy photos into YYYY/MM/DD/ prefixes based on the date they were taken.
+      {{.Prompt}} {{.HelpName}} -r --organize-by exif-date ./DCIM/ play/photos/
+
+  22. Copy all files found under a folder recursively without reproducing any of the sub-folders.
+      {{.Prompt}} {{.HelpName}} -r --flatten ./logs/ play/logs/
+
+  23. Copy a folder recursively, removing the first sub-folder level from the object names.
+      {{.Prompt}} {{.HelpName}} -r --strip-components 1 ./releases/ play/releases/
+
+  24. Copy a folder recursively, lower-casing all the object names on the target.
+      {{.Prompt}} {{.HelpName}} -r --name-template '{{"{{"}}.Dir{{"}}"}}/{{"{{"}}.NameLower{{"}}"}}{{"{{"}}.ExtLower{{"}}"}}' ./DCIM/ play/photos/
+
+  25. Copy a folder recursively into a non-empty bucket, renaming copies of objects that already exist (photo.jpg -> photo-1.jpg).
+      {{.Prompt}} {{.HelpName}} -r --flatten --on-conflict rename ./DCIM/ play/photos/
+
+  26. Copy a project folder recursively, skipping '.git/', 'node_modules/' and temporary files.
+      {{.Prompt}} {{.HelpName}} -r --exclude ".git/" --exclude "node_modules/" --exclude "*.tmp" ./project/ play/backup/project/
+
+  27. Copy only the PDF documents of a folder recursively.
+      {{.Prompt}} {{.HelpName}} -r --include "*.pdf" ./documents/ play/documents/
+
+  28. Report the objects a recursive copy would upload, without uploading them.
+      {{.Prompt}} {{.HelpName}} -r --dry-run ./documents/ play/documents/
+
+  29. Copy a large file in 64MiB parts, 8 of them uploaded in parallel. Running the same command
+      again after an interruption uploads only the missing parts.
+      {{.Prompt}} {{.HelpName}} --part-size 64MiB --parallel-parts 8 ./backup.tar play/backups/
+
+  30. Copy a folder recursively, verifying the ETag of each uploaded object against the checksum of its file.
+      {{.Prompt}} {{.HelpName}} -r --verify ./photos/ play/photos/
+
+  31. Copy a bucket to a local folder, keeping the objects named like a prefix, e.g. 'logs', over the objects under it, e.g. 'logs/today.log'.
+      {{.Prompt}} {{.HelpName}} -r --name-conflict object play/mybucket/ /mnt/backup/
+
+  32. Copy a folder of web assets recursively, served as JavaScript and cached for a day.
+      {{.Prompt}} {{.HelpName}} -r --content-type "application/javascript" --cache-control "max-age=86400" ./dist/js/ play/website/js/
+
+  33. Copy a folder recursively to a shared bucket, skipping the files with credentials.
+      {{.Prompt}} {{.HelpName}} -r --block-secrets ./project/ play/shared/project/
+
+  34. Download a folder recursively, scanning the objects with clamd and keeping the infected ones in a quarantine folder.
+      {{.Prompt}} {{.HelpName}} -r --scan-command "clamdscan --no-summary -" --quarantine-dir /var/quarantine play/uploads/ ./uploads/
+
+  35. Copy a bucket to another object store, keeping the content types, metadata and tags of the objects.
+      {{.Prompt}} {{.HelpName}} -r -a play/mybucket s3/mybucket
+
+  36. Copy a folder recursively without the confirmation asked for copies larger than 'transfer.confirmAbove' of the configuration file.
+      {{.Prompt}} {{.HelpName}} -r --yes ./dumps/ prod-backup/dumps/
+
+  37. Copy a VM image to a local file updated by a previous copy, downloading only its changed parts and leaving holes in place of its blocks of zeros.
+      {{.Prompt}} {{.HelpName}} --sparse --skip-matching-parts play/images/vm.qcow2 /var/lib/images/vm.qcow2
+
+  38. Copy the 1MiB of a large log object starting at its 10GiB offset to a local file.
+      {{.Prompt}} {{.HelpName}} --offset 10737418240 --length 1048576 play/logs/app.log ./app-part.log
+
+  39. Download a large object, resuming the download interrupted in a previous run from the part already downloaded.
+      {{.Prompt}} {{.HelpName}} --continue play/images/vm.qcow2 /var/lib/images/vm.qcow2
+
 `,
 }
 
@@ -209,11 +335,16 @@ type copyMessage struct {
 	Size       int64  `json:"size"`
 	TotalCount int64  `json:"totalCount"`
 	TotalSize  int64  `json:"totalSize"`
+	DryRun     bool   `json:"dryRun,omitempty"`
 }
 
 // String colorized copy message
 func (c copyMessage) String() string {
-	return console.Colorize("Copy", fmt.Sprintf("`%s` -> `%s`", c.Source, c.Target))
+	msg := console.Colorize("Copy", fmt.Sprintf("`%s` -> `%s`", c.Source, c.Target))
+	if c.DryRun {
+		msg = "DRYRUN: " + msg
+	}
+	return msg
 }
 
 // JSON jsonified copy message
@@ -239,6 +370,31 @@ type ProgressReader interface {
 	Progress
 }
 
+// prepareCopyTarget - rewrites the target of cpURLs and applies the
+// --on-conflict policy. It is called in the order the sources are listed,
+// before the copies run in parallel, so that renamed targets don't depend
+// on which copy starts first. skip is true when the copy must not happen.
+func prepareCopyTarget(ctx context.Context, cpURLs URLs, target copyTargetOpts, conflicts *copyConflictResolver, encKeyDB map[string][]prefixSSEPair) (_ URLs, skip bool) {
+	cpURLs = mapCopyTarget(ctx, cpURLs, target, encKeyDB)
+	if cpURLs.Error != nil || conflicts == nil {
+		return cpURLs, false
+	}
+
+	cpURLs, skip, conflictMsg := conflicts.resolve(ctx, cpURLs, encKeyDB)
+	if cpURLs.Error != nil {
+		return cpURLs, false
+	}
+	if conflictMsg != nil {
+		// Print in new line and adjust to top so that we
+		// don't print over the ongoing progress bar.
+		if isProgressBarEnabled() {
+			console.Eraseline()
+		}
+		printMsg(conflictMsg)
+	}
+	return cpURLs, skip
+}
+
 // doCopy - Copy a single file from source to destination
 func doCopy(ctx context.Context, copyOpts doCopyOpts) URLs {
 	if copyOpts.cpURLs.Error != nil {
@@ -253,6 +409,42 @@ func doCopy(ctx context.Context, copyOpts doCopyOpts) URLs {
 	length := copyOpts.cpURLs.SourceContent.Size
 	sourcePath := filepath.ToSlash(filepath.Join(sourceAlias, sourceURL.Path))
 
+	if copyOpts.scanSecrets && sourceURL.Type == fileSystem && targetURL.Type == objectStorage {
+		findings, err := scanFileSecrets(sourceURL.Path)
+		if err != nil {
+			return copyOpts.cpURLs.WithError(err.Trace(sourceURL.String()))
+		}
+		if len(findings) > 0 && copyOpts.blockSecrets {
+			doCopyFake(copyOpts.cpURLs, copyOpts.pg)
+			return copyOpts.cpURLs.WithError(probe.NewError(SecretsFound{Path: sourcePath, Findings: findings}))
+		}
+		if len(findings) > 0 {
+			if isProgressBarEnabled() {
+				console.Eraseline()
+			}
+			printMsg(secretsMessage{
+				Source:   sourcePath,
+				Target:   filepath.ToSlash(filepath.Join(targetAlias, targetURL.Path)),
+				Findings: findings,
+			})
+		}
+	}
+
+	if copyOpts.dryRun {
+		if isProgressBarEnabled() {
+			console.Eraseline()
+		}
+		printMsg(copyMessage{
+			Source:     sourcePath,
+			Target:     filepath.ToSlash(filepath.Join(targetAlias, targetURL.Path)),
+			Size:       length,
+			TotalCount: copyOpts.cpURLs.TotalCount,
+			TotalSize:  copyOpts.cpURLs.TotalSize,
+			DryRun:     true,
+		})
+		return doCopyFake(copyOpts.cpURLs, copyOpts.pg)
+	}
+
 	if progressReader, ok := copyOpts.pg.(*progressBar); ok {
 		progressReader.SetCaption(copyOpts.cpURLs.SourceContent.URL.String() + ":")
 	} else {
@@ -274,7 +466,15 @@ func doCopy(ctx context.Context, copyOpts doCopyOpts) URLs {
 		isZip:               copyOpts.isZip,
 		multipartSize:       copyOpts.multipartSize,
 		multipartThreads:    copyOpts.multipartThreads,
+		multipartThreshold:  copyOpts.multipartThreshold,
 		updateProgressTotal: copyOpts.updateProgressTotal,
+		verify:              copyOpts.verify,
+		scanner:             copyOpts.scanner,
+		sparse:              copyOpts.sparse,
+		skipMatchingParts:   copyOpts.skipMatchingParts,
+		rangeStart:          copyOpts.rangeStart,
+		ranged:              copyOpts.ranged,
+		resume:              copyOpts.resume,
 	})
 	if copyOpts.isMvCmd && urls.Error == nil {
 		rmManager.add(ctx, sourceAlias, sourceURL.String())
@@ -309,12 +509,18 @@ func doPrepareCopyURLs(ctx context.Context, session *sessionV8, cancelCopy conte
 	encrypt := session.Header.CommandStringFlags["encrypt"]
 	encKeyDB, err := parseAndValidateEncryptionKeys(encryptKeys, encrypt)
 	fatalIf(err, "Unable to parse encryption keys.")
+	filter := listFilter{
+		exclude: splitSessionFlag(session.Header.CommandStringFlags["exclude"]),
+		include: splitSessionFlag(session.Header.CommandStringFlags["include"]),
+	}
+	filter.nameConflict, err = parseNameConflictMode(session.Header.CommandStringFlags["name-conflict"])
+	fatalIf(err, "Invalid --name-conflict, valid values are `object` and `prefix`.")
 
 	// Create a session data file to store the processed URLs.
 	dataFP := session.NewDataWriter()
 
 	var scanBar scanBarFunc
-	if !globalQuiet && !globalJSON { // set up progress bar
+	if isProgressBarEnabled() { // set up progress bar
 		scanBar = scanBarFactory()
 	}
 
@@ -327,6 +533,7 @@ func doPrepareCopyURLs(ctx context.Context, session *sessionV8, cancelCopy conte
 		newerThan:   newerThan,
 		timeRef:     parseRewindFlag(rewind),
 		versionID:   versionID,
+		filter:      filter,
 	}
 
 	URLsCh := prepareCopyURLs(ctx, opts)
@@ -353,7 +560,7 @@ func doPrepareCopyURLs(ctx context.Context, session *sessionV8, cancelCopy conte
 			}
 			dataFP.Write(jsonData)
 			dataFP.Write([]byte{'\n'})
-			if !globalQuiet && !globalJSON {
+			if isProgressBarEnabled() {
 				scanBar(cpURLs.SourceContent.URL.String())
 			}
 
@@ -362,7 +569,7 @@ func doPrepareCopyURLs(ctx context.Context, session *sessionV8, cancelCopy conte
 		case <-globalContext.Done():
 			cancelCopy()
 			// Print in new line and adjust to top so that we don't print over the ongoing scan bar
-			if !globalQuiet && !globalJSON {
+			if isProgressBarEnabled() {
 				console.Eraseline()
 			}
 			session.Delete() // If we are interrupted during the URL scanning, we drop the session.
@@ -379,7 +586,7 @@ func doPrepareCopyURLs(ctx context.Context, session *sessionV8, cancelCopy conte
 func printCopyURLsError(cpURLs *URLs) {
 	// Print in new line and adjust to top so that we
 	// don't print over the ongoing scan bar
-	if !globalQuiet && !globalJSON {
+	if isProgressBarEnabled() {
 		console.Eraseline()
 	}
 
@@ -399,23 +606,45 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 
 	cpURLsCh := make(chan URLs, 10000)
 	errSeen := false
-
-	// Store a progress bar or an accounter
-	var pg ProgressReader
-
-	// Enable progress bar reader only during default mode.
-	if !globalQuiet && !globalJSON { // set up progress bar
-		pg = newProgressBar(totalBytes)
-	} else {
-		pg = newAccounter(totalBytes)
-	}
+	multipartSize, multipartThreads, multipartThreshold := multipartFlagValues(cli)
+	scanner, err := newDownloadScanner(cli)
+	fatalIf(err, "Unable to scan the downloads.")
 
 	sourceURLs := cli.Args()[:len(cli.Args())-1]
 	targetURL := cli.Args()[len(cli.Args())-1] // Last one is target
 
+	// Options rewriting the target object names.
+	targetOpts := copyTargetOpts{
+		flatten:         cli.Bool("flatten"),
+		stripComponents: cli.Int("strip-components"),
+		organizeBy:      cli.String("organize-by"),
+	}
+	_, targetOpts.root, _ = mustExpandAlias(targetURL)
+	conflicts := newCopyConflictResolver(cli.String("on-conflict"))
+	if text := cli.String("name-template"); text != "" {
+		targetOpts.nameTemplate, err = parseNameTemplate(text)
+		fatalIf(err.Trace(text), "Unable to parse --name-template.")
+	}
+	if targetOpts.isSet() && conflicts == nil {
+		targetOpts.claims = newCopyTargetClaims()
+	}
+
 	// Check if the target path has object locking enabled
 	withLock, _ := isBucketLockEnabled(ctx, targetURL)
 
+	// Transfers larger than --confirm-above are listed once first,
+	// to be confirmed.
+	confirmAbove, err := confirmAboveSize(cli)
+	fatalIf(err, "Invalid value for --confirm-above.")
+	if isDryRun(cli) {
+		confirmAbove = 0
+	}
+	summary := transferSummary{
+		Verb:   transferVerb(aliasedURLType(sourceURLs[0]), aliasedURLType(targetURL), isMvCmd),
+		Target: targetURL,
+	}
+
+	var opts prepareCopyURLsOpts
 	if session != nil {
 		// isCopied returns true if an object has been already copied
 		// or not. This is useful when we resume from a session.
@@ -426,7 +655,52 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 		} else {
 			totalBytes, totalObjects = session.Header.TotalBytes, session.Header.TotalObjects
 		}
+		summary.Objects, summary.Size = totalObjects, totalBytes
+	} else {
+		// Access recursive flag inside the session header.
+		isRecursive := cli.Bool("recursive")
+		olderThan := cli.String("older-than")
+		newerThan := cli.String("newer-than")
+		rewind := cli.String("rewind")
+		versionID := cli.String("version-id")
+		opts = prepareCopyURLsOpts{
+			sourceURLs:  sourceURLs,
+			targetURL:   targetURL,
+			isRecursive: isRecursive,
+			encKeyDB:    encKeyDB,
+			olderThan:   olderThan,
+			newerThan:   newerThan,
+			timeRef:     parseRewindFlag(rewind),
+			versionID:   versionID,
+			isZip:       cli.Bool("zip"),
+			filter:      newListFilter(cli),
+			rangeStart:  cli.Int64("offset"),
+			rangeLength: cli.Int64("length"),
+		}
 
+		if confirmAbove > 0 {
+			// The transfer stops at the same error, and reports it.
+			for cpURLs := range prepareCopyURLs(ctx, opts) {
+				if cpURLs.Error != nil {
+					break
+				}
+				summary.add(cpURLs.SourceContent.Size)
+			}
+		}
+	}
+	fatalIf(confirmTransfer(summary, confirmAbove), "Unable to start the transfer.")
+
+	// Store a progress bar or an accounter
+	var pg ProgressReader
+
+	// Enable progress bar reader only during default mode.
+	if isProgressBarEnabled() { // set up progress bar
+		pg = newProgressBar(totalBytes)
+	} else {
+		pg = newAccounter(totalBytes)
+	}
+
+	if session != nil {
 		pg.SetTotal(totalBytes)
 
 		go func() {
@@ -450,27 +724,8 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 		}()
 
 	} else {
-		// Access recursive flag inside the session header.
-		isRecursive := cli.Bool("recursive")
-		olderThan := cli.String("older-than")
-		newerThan := cli.String("newer-than")
-		rewind := cli.String("rewind")
-		versionID := cli.String("version-id")
-
 		go func() {
 			totalBytes := int64(0)
-			opts := prepareCopyURLsOpts{
-				sourceURLs:  sourceURLs,
-				targetURL:   targetURL,
-				isRecursive: isRecursive,
-				encKeyDB:    encKeyDB,
-				olderThan:   olderThan,
-				newerThan:   newerThan,
-				timeRef:     parseRewindFlag(rewind),
-				versionID:   versionID,
-				isZip:       cli.Bool("zip"),
-			}
-
 			for cpURLs := range prepareCopyURLs(ctx, opts) {
 				if cpURLs.Error != nil {
 					errSeen = true
@@ -480,7 +735,7 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 
 				totalBytes += cpURLs.SourceContent.Size
 				pg.SetTotal(totalBytes)
-				totalObjects++
+				atomic.AddInt64(&totalObjects, 1)
 				cpURLsCh <- cpURLs
 			}
 			close(cpURLsCh)
@@ -490,7 +745,20 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 	quitCh := make(chan struct{})
 	statusCh := make(chan URLs)
 
-	parallel := newParallelManager(statusCh)
+	parallel := newParallelManager(statusCh, globalTransfer.Parallel)
+
+	operation := "cp"
+	if isMvCmd {
+		operation = "mv"
+	}
+	var copied int64
+	defer startLiveStatus(operation, func(m *liveStatusMessage) {
+		m.Objects = atomic.LoadInt64(&copied)
+		m.TotalObjects = atomic.LoadInt64(&totalObjects)
+		m.Transferred = pg.Get()
+		m.TotalSize = progressTotal(pg)
+		m.Queued = parallel.queued()
+	})()
 
 	go func() {
 		gracefulStop := func() {
@@ -511,7 +779,7 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 				}
 
 				// Save total count.
-				cpURLs.TotalCount = totalObjects
+				cpURLs.TotalCount = atomic.LoadInt64(&totalObjects)
 
 				// Save totalSize.
 				cpURLs.TotalSize = totalBytes
@@ -542,6 +810,12 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 				if tags := cli.String("tags"); tags != "" {
 					cpURLs.TargetContent.Metadata["X-Amz-Tagging"] = tags
 				}
+				if contentType := cli.String("content-type"); contentType != "" {
+					cpURLs.TargetContent.Metadata["Content-Type"] = contentType
+				}
+				if cacheControl := cli.String("cache-control"); cacheControl != "" {
+					cpURLs.TargetContent.Metadata["Cache-Control"] = cacheControl
+				}
 
 				preserve := cli.Bool("preserve")
 				isZip := cli.Bool("zip")
@@ -570,6 +844,13 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 						}
 						startContinue = false
 					}
+					var skip bool
+					if cpURLs, skip = prepareCopyTarget(ctx, cpURLs, targetOpts, conflicts, encKeyDB); skip {
+						parallel.queueTask(func() URLs {
+							return doCopyFake(cpURLs, pg)
+						}, 0)
+						continue
+					}
 					parallel.queueTask(func() URLs {
 						return doCopy(ctx, doCopyOpts{
 							cpURLs:   cpURLs,
@@ -578,6 +859,20 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 							isMvCmd:  isMvCmd,
 							preserve: preserve,
 							isZip:    isZip,
+							dryRun:   isDryRun(cli),
+
+							multipartSize:      multipartSize,
+							multipartThreads:   multipartThreads,
+							multipartThreshold: multipartThreshold,
+							verify:             cli.Bool("verify"),
+							scanSecrets:        cli.Bool("scan-secrets") || cli.Bool("block-secrets"),
+							blockSecrets:       cli.Bool("block-secrets"),
+							scanner:            scanner,
+							sparse:             cli.Bool("sparse"),
+							skipMatchingParts:  cli.Bool("skip-matching-parts"),
+							rangeStart:         cli.Int64("offset"),
+							ranged:             cli.IsSet("offset") || cli.IsSet("length"),
+							resume:             session != nil,
 						})
 					}, cpURLs.SourceContent.Size)
 				}
@@ -595,7 +890,7 @@ loop:
 			close(quitCh)
 			cancelCopy()
 			// Receive interrupt notification.
-			if !globalQuiet && !globalJSON {
+			if isProgressBarEnabled() {
 				console.Eraseline()
 			}
 			if session != nil {
@@ -608,6 +903,7 @@ loop:
 				break loop
 			}
 			if cpURLs.Error == nil {
+				atomic.AddInt64(&copied, 1)
 				if session != nil {
 					session.Header.LastCopied = cpURLs.SourceContent.URL.String()
 					session.Save()
@@ -620,7 +916,7 @@ loop:
 
 				// Print in new line and adjust to top so that we
 				// don't print over the ongoing progress bar.
-				if !globalQuiet && !globalJSON {
+				if isProgressBarEnabled() {
 					console.Eraseline()
 				}
 				errorIf(cpURLs.Error.Trace(cpURLs.SourceContent.URL.String()),
@@ -656,7 +952,7 @@ loop:
 	if progressReader, ok := pg.(*progressBar); ok {
 		if errSeen || (cpAllFilesErr && totalObjects > 0) {
 			// We only erase a line if we are displaying a progress bar
-			if !globalQuiet && !globalJSON {
+			if isProgressBarEnabled() {
 				console.Eraseline()
 			}
 		} else if progressReader.ProgressBar.Get() > 0 {
@@ -666,7 +962,7 @@ loop:
 		if accntReader, ok := pg.(*accounter); ok {
 			if errSeen || (cpAllFilesErr && totalObjects > 0) {
 				// We only erase a line if we are displaying a progress bar
-				if !globalQuiet && !globalJSON {
+				if isProgressBarEnabled() {
 					console.Eraseline()
 				}
 			} else {
@@ -685,6 +981,12 @@ loop:
 
 // mainCopy is the entry point for cp command.
 func mainCopy(cliCtx *cli.Context) error {
+	return runCopy(cliCtx, getHash("cp", os.Args[1:]))
+}
+
+// runCopy - copies the sources of cliCtx to its target, saving the copy
+// session sessionID with --continue.
+func runCopy(cliCtx *cli.Context, sessionID string) error {
 	ctx, cancelCopy := context.WithCancel(globalContext)
 	defer cancelCopy()
 
@@ -692,6 +994,8 @@ func mainCopy(cliCtx *cli.Context) error {
 	encKeyDB, err := getEncKeys(cliCtx)
 	fatalIf(err, "Unable to parse encryption keys.")
 
+	fatalIf(loadTransferConfig(), "Unable to load the transfer options.")
+
 	// Parse metadata.
 	userMetaMap := make(map[string]string)
 	if cliCtx.String("attr") != "" {
@@ -703,6 +1007,8 @@ func mainCopy(cliCtx *cli.Context) error {
 	checkCopySyntax(cliCtx)
 	// Additional command specific theme customization.
 	console.SetColor("Copy", color.New(color.FgGreen, color.Bold))
+	console.SetColor("SecretsWarning", color.New(color.FgYellow, color.Bold))
+	console.SetColor("CopyConflict", color.New(color.FgYellow))
 
 	recursive := cliCtx.Bool("recursive")
 	rewind := cliCtx.String("rewind")
@@ -728,7 +1034,6 @@ func mainCopy(cliCtx *cli.Context) error {
 	var session *sessionV8
 
 	if cliCtx.Bool("continue") {
-		sessionID := getHash("cp", os.Args[1:])
 		if isSessionExists(sessionID) {
 			session, err = loadSessionV8(sessionID)
 			fatalIf(err.Trace(sessionID), "Unable to load session.")
@@ -742,11 +1047,21 @@ func mainCopy(cliCtx *cli.Context) error {
 			session.Header.CommandStringFlags["newer-than"] = newerThan
 			session.Header.CommandStringFlags["storage-class"] = storageClass
 			session.Header.CommandStringFlags["tags"] = tags
+			session.Header.CommandStringFlags["content-type"] = cliCtx.String("content-type")
+			session.Header.CommandStringFlags["cache-control"] = cliCtx.String("cache-control")
 			session.Header.CommandStringFlags[rmFlag] = retentionMode
 			session.Header.CommandStringFlags[rdFlag] = retentionDuration
 			session.Header.CommandStringFlags[lhFlag] = legalHold
 			session.Header.CommandStringFlags["encrypt-key"] = sseKeys
 			session.Header.CommandStringFlags["encrypt"] = sse
+			session.Header.CommandStringFlags["organize-by"] = cliCtx.String("organize-by")
+			session.Header.CommandStringFlags["exclude"] = strings.Join(cliCtx.StringSlice("exclude"), "\n")
+			session.Header.CommandStringFlags["include"] = strings.Join(cliCtx.StringSlice("include"), "\n")
+			session.Header.CommandStringFlags["name-conflict"] = cliCtx.String("name-conflict")
+			session.Header.CommandStringFlags["name-template"] = cliCtx.String("name-template")
+			session.Header.CommandStringFlags["on-conflict"] = cliCtx.String("on-conflict")
+			session.Header.CommandBoolFlags["flatten"] = cliCtx.Bool("flatten")
+			session.Header.CommandIntFlags["strip-components"] = cliCtx.Int("strip-components")
 			session.Header.CommandBoolFlags["session"] = cliCtx.Bool("continue")
 
 			if cliCtx.Bool("preserve") {
@@ -783,4 +1098,15 @@ type doCopyOpts struct {
 	updateProgressTotal      bool
 	multipartSize            string
 	multipartThreads         string
+	multipartThreshold       string
+	dryRun                   bool
+	verify                   bool
+	scanSecrets              bool
+	blockSecrets             bool
+	scanner                  *downloadScanner
+	sparse                   bool
+	skipMatchingParts        bool
+	rangeStart               int64
+	ranged                   bool
+	resume                   bool
 }
@@ -18,8 +18,14 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseMetaData(t *testing.T) {
@@ -74,3 +80,226 @@ func TestParseMetaData(t *testing.T) {
 		}
 	}
 }
+
+func TestParseExifDate(t *testing.T) {
+	// Little endian TIFF structure with an IFD0 pointing to an EXIF
+	// sub-IFD which holds a single DateTimeOriginal tag.
+	tiff := []byte("II*\x00\x08\x00\x00\x00")
+	// IFD0 at offset 8: one entry, ExifIFDPointer (LONG) -> 26.
+	tiff = append(tiff, 0x01, 0x00, 0x69, 0x87, 0x04, 0x00, 0x01, 0x00, 0x00, 0x00, 0x1a, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00)
+	// EXIF IFD at offset 26: one entry, DateTimeOriginal (ASCII, 20 bytes) -> 44.
+	tiff = append(tiff, 0x01, 0x00, 0x03, 0x90, 0x02, 0x00, 0x14, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00)
+	tiff = append(tiff, []byte("2021:07:04 10:20:30\x00")...)
+
+	app1 := append([]byte("Exif\x00\x00"), tiff...)
+	jpeg := []byte{0xff, 0xd8, 0xff, 0xe0, 0x00, 0x04, 0x00, 0x00, 0xff, 0xe1, byte((len(app1) + 2) >> 8), byte(len(app1) + 2)}
+	jpeg = append(jpeg, app1...)
+	jpeg = append(jpeg, 0xff, 0xda, 0x00, 0x02)
+
+	expected := time.Date(2021, 7, 4, 10, 20, 30, 0, time.UTC)
+	testCases := []struct {
+		data     []byte
+		expected time.Time
+	}{
+		{jpeg, expected},
+		{tiff, expected},
+		{[]byte{0xff, 0xd8, 0xff, 0xda, 0x00, 0x02}, time.Time{}},
+		{[]byte("plain text"), time.Time{}},
+	}
+	for i, testCase := range testCases {
+		if got := parseExifDate(testCase.data); !got.Equal(testCase.expected) {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestHasExifHeader(t *testing.T) {
+	testCases := []struct {
+		path        string
+		contentType string
+		expected    bool
+	}{
+		{"photos/a.jpg", "", true},
+		{"photos/a.JPG", "application/octet-stream", true},
+		{"photos/a.nef", "", true},
+		{"photos/a.bin", "image/jpeg", true},
+		{"photos/a.jpg", "text/plain", false},
+		{"docs/a.pdf", "", false},
+		{"docs/a", "", false},
+	}
+	for i, testCase := range testCases {
+		content := &ClientContent{URL: *newClientURL(testCase.path), ContentType: testCase.contentType}
+		if got := hasExifHeader(content); got != testCase.expected {
+			t.Errorf("Test %d: expected %t, got %t", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestSplitCopyTargetPath(t *testing.T) {
+	testCases := []struct {
+		targetPath, rootPath, root, rel string
+	}{
+		{"/photos/archive/DCIM/img.jpg", "/photos/archive/", "/photos/archive/", "DCIM/img.jpg"},
+		{"/photos/archive/img.jpg", "/photos/archive", "/photos/archive", "img.jpg"},
+		{"/photos/archive/img.jpg", "/photos/archive/img.jpg", "/photos/archive/", "img.jpg"},
+	}
+	for i, testCase := range testCases {
+		root, rel := splitCopyTargetPath(testCase.targetPath, testCase.rootPath)
+		if root != testCase.root || rel != testCase.rel {
+			t.Errorf("Test %d: expected (%s, %s), got (%s, %s)", i+1, testCase.root, testCase.rel, root, rel)
+		}
+	}
+}
+
+func TestStripCopyTargetPath(t *testing.T) {
+	testCases := []struct {
+		rel      string
+		n        int
+		flatten  bool
+		expected string
+	}{
+		{"a/b/c.txt", 0, false, "a/b/c.txt"},
+		{"a/b/c.txt", 1, false, "b/c.txt"},
+		{"a/b/c.txt", 5, false, "c.txt"},
+		{"a/b/c.txt", 0, true, "c.txt"},
+		{"c.txt", 1, false, "c.txt"},
+	}
+	for i, testCase := range testCases {
+		if got := stripCopyTargetPath(testCase.rel, testCase.n, testCase.flatten); got != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestMapCopyTargetCollision(t *testing.T) {
+	opts := copyTargetOpts{root: "/dst/", flatten: true, claims: newCopyTargetClaims()}
+	copyURLs := func(name string) URLs {
+		return URLs{
+			SourceContent: &ClientContent{URL: *newClientURL("/src/" + name)},
+			TargetContent: &ClientContent{URL: *newClientURL("/dst/" + name)},
+		}
+	}
+	ctx := context.Background()
+	if got := mapCopyTarget(ctx, copyURLs("a/x"), opts, nil); got.Error != nil || got.TargetContent.URL.Path != "/dst/x" {
+		t.Fatalf("expected /dst/x, got %v (%v)", got.TargetContent.URL.Path, got.Error)
+	}
+	// Copying the same source again is no collision.
+	if got := mapCopyTarget(ctx, copyURLs("a/x"), opts, nil); got.Error != nil {
+		t.Fatalf("unexpected error %v", got.Error)
+	}
+	if got := mapCopyTarget(ctx, copyURLs("b/x"), opts, nil); got.Error == nil {
+		t.Fatal("expected b/x to collide with a/x")
+	}
+	if got := mapCopyTarget(ctx, copyURLs("b/y"), opts, nil); got.Error != nil {
+		t.Fatalf("unexpected error %v", got.Error)
+	}
+}
+
+func TestRenderNameTemplate(t *testing.T) {
+	modTime := time.Date(2021, 7, 4, 10, 20, 30, 0, time.UTC)
+	testCases := []struct {
+		template, rel, expected string
+		success                 bool
+	}{
+		{"{{.Dir}}/{{.NameLower}}{{.ExtLower}}", "DCIM/IMG_001.JPG", "DCIM/img_001.jpg", true},
+		{"{{.Dir}}/{{.NameLower}}{{.Ext}}", "IMG_001.JPG", "img_001.JPG", true},
+		{"{{.ModTime.Format \"2006\"}}/{{.Base}}", "a/b.txt", "2021/b.txt", true},
+		{"../{{.Base}}", "a/b.txt", "", false},
+		{"{{.Dir}}/", "a/b.txt", "", false},
+	}
+	for i, testCase := range testCases {
+		tmpl, err := parseNameTemplate(testCase.template)
+		if err != nil {
+			if testCase.success {
+				t.Fatalf("Test %d: unexpected error: %v", i+1, err)
+			}
+			continue
+		}
+		got, err := renderNameTemplate(tmpl, testCase.rel, 0, modTime)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: expected success %t, got error %v", i+1, testCase.success, err)
+		}
+		if got != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestConflictRenamedPath(t *testing.T) {
+	testCases := []struct {
+		targetPath string
+		separator  rune
+		n          int
+		expected   string
+	}{
+		{"/bucket/photos/img.jpg", '/', 1, "/bucket/photos/img-1.jpg"},
+		{"/bucket/photos/img.tar.gz", '/', 2, "/bucket/photos/img.tar-2.gz"},
+		{"/bucket/photos/README", '/', 3, "/bucket/photos/README-3"},
+		{"/bucket/photos/.profile", '/', 1, "/bucket/photos/.profile-1"},
+		{`C:\backup\img.jpg`, '\\', 1, `C:\backup\img-1.jpg`},
+	}
+	for i, testCase := range testCases {
+		if got := conflictRenamedPath(testCase.targetPath, testCase.separator, testCase.n); got != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestCopyConflictResolver(t *testing.T) {
+	useTestMcConfig(t)
+	root := t.TempDir()
+	existing := filepath.Join(root, "x.txt")
+	if e := os.WriteFile(existing, []byte("x"), 0o644); e != nil {
+		t.Fatal(e)
+	}
+	copyURLs := func(source, target string) URLs {
+		return URLs{
+			SourceContent: &ClientContent{URL: *newClientURL(source)},
+			TargetContent: &ClientContent{URL: *newClientURL(target)},
+		}
+	}
+	ctx := context.Background()
+
+	if newCopyConflictResolver(conflictOverwrite) != nil {
+		t.Error("expected no resolver when overwriting")
+	}
+
+	r := newCopyConflictResolver(conflictSkip)
+	if got, skip, msg := r.resolve(ctx, copyURLs("/src/a/x.txt", existing), nil); got.Error != nil || !skip || msg == nil {
+		t.Errorf("skip: expected the existing target to be skipped, got %v %t", got.Error, skip)
+	}
+	if got, skip, _ := r.resolve(ctx, copyURLs("/src/y.txt", filepath.Join(root, "y.txt")), nil); got.Error != nil || skip {
+		t.Errorf("skip: expected a missing target to be copied, got %v %t", got.Error, skip)
+	}
+
+	r = newCopyConflictResolver(conflictFail)
+	if got, _, _ := r.resolve(ctx, copyURLs("/src/a/x.txt", existing), nil); got.Error == nil {
+		t.Error("fail: expected an error for the existing target")
+	}
+
+	// Renames follow the order targets are resolved in.
+	r = newCopyConflictResolver(conflictRename)
+	for i, source := range []string{"/src/a/x.txt", "/src/b/x.txt"} {
+		got, skip, msg := r.resolve(ctx, copyURLs(source, existing), nil)
+		if got.Error != nil || skip || msg == nil {
+			t.Fatalf("rename: unexpected result %v %t", got.Error, skip)
+		}
+		if want := filepath.Join(root, fmt.Sprintf("x-%d.txt", i+1)); got.TargetContent.URL.Path != want {
+			t.Errorf("rename: expected %s, got %s", want, got.TargetContent.URL.Path)
+		}
+	}
+}
+
+func TestCopyMessageDryRun(t *testing.T) {
+	msg := copyMessage{Source: "src/a", Target: "tgt/a"}
+	if s := msg.String(); strings.HasPrefix(s, "DRYRUN: ") {
+		t.Errorf("unexpected dry run message %q", s)
+	}
+	msg.DryRun = true
+	if s := msg.String(); !strings.HasPrefix(s, "DRYRUN: ") {
+		t.Errorf("expected a dry run message, got %q", s)
+	}
+	if s := (makeBucketMessage{Bucket: "tgt/bucket", DryRun: true}).String(); !strings.HasPrefix(s, "DRYRUN: ") {
+		t.Errorf("expected a dry run message, got %q", s)
+	}
+}
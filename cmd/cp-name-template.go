@@ -0,0 +1,90 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// nameTemplateFields - fields available to --name-template, all paths
+// are relative to the target and slash separated.
+type nameTemplateFields struct {
+	Path      string // dir/photo.JPG
+	Dir       string // dir
+	Base      string // photo.JPG
+	Name      string // photo
+	NameLower string // photo
+	NameUpper string // PHOTO
+	Ext       string // .JPG
+	ExtLower  string // .jpg
+	Size      int64
+	ModTime   time.Time
+}
+
+// parseNameTemplate - parses and validates a --name-template value.
+func parseNameTemplate(text string) (*template.Template, *probe.Error) {
+	tmpl, e := template.New("name-template").Option("missingkey=error").Parse(text)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	// Render with sample values so that unknown fields are reported upfront.
+	if _, err := renderNameTemplate(tmpl, "dir/name.ext", 0, time.Time{}); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// renderNameTemplate - renders the template for the slash separated relative path.
+func renderNameTemplate(tmpl *template.Template, rel string, size int64, modTime time.Time) (string, *probe.Error) {
+	dir, base := path.Split(rel)
+	ext := path.Ext(base)
+	name := strings.TrimSuffix(base, ext)
+	fields := nameTemplateFields{
+		Path:      rel,
+		Dir:       strings.TrimSuffix(dir, "/"),
+		Base:      base,
+		Name:      name,
+		NameLower: strings.ToLower(name),
+		NameUpper: strings.ToUpper(name),
+		Ext:       ext,
+		ExtLower:  strings.ToLower(ext),
+		Size:      size,
+		ModTime:   modTime,
+	}
+
+	var b strings.Builder
+	if e := tmpl.Execute(&b, fields); e != nil {
+		return "", probe.NewError(e)
+	}
+	newRel := path.Clean("/" + b.String())
+	if newRel == "/" || strings.HasSuffix(b.String(), "/") {
+		return "", probe.NewError(fmt.Errorf("--name-template renders `%s` into an invalid object name `%s`", rel, b.String()))
+	}
+	for _, elem := range strings.Split(b.String(), "/") {
+		if elem == ".." {
+			return "", probe.NewError(fmt.Errorf("--name-template renders `%s` outside of the target `%s`", rel, b.String()))
+		}
+	}
+	return strings.TrimPrefix(newRel, "/"), nil
+}
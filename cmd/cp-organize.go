@@ -0,0 +1,215 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7/pkg/set"
+)
+
+const (
+	// Organize copied objects by the date found in their EXIF header.
+	organizeByExifDate = "exif-date"
+
+	// Number of bytes read from the beginning of an image to find its EXIF header.
+	exifProbeHeadSize = 128 * 1024
+
+	// EXIF tags holding the date a photo was taken.
+	exifTagDateTime         = 0x0132
+	exifTagExifIFDPointer   = 0x8769
+	exifTagDateTimeOriginal = 0x9003
+)
+
+// copyOrganizeDate - returns the date a photo was taken read from the EXIF
+// header of the source, sources without an EXIF date fall back to their
+// modification time.
+func copyOrganizeDate(ctx context.Context, cpURLs URLs, encKeyDB map[string][]prefixSSEPair) (time.Time, *probe.Error) {
+	exifDate, err := sourceExifDate(ctx, cpURLs, encKeyDB)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !exifDate.IsZero() {
+		return exifDate, nil
+	}
+	return cpURLs.SourceContent.Time, nil
+}
+
+// Camera RAW formats are TIFF based but have no registered content type.
+var exifRawExtensions = set.CreateStringSet(".arw", ".cr2", ".dng", ".nef", ".orf", ".pef", ".raf", ".rw2", ".srw")
+
+// hasExifHeader - returns true if the content type of the object, or its
+// extension when unknown, is of an image format that may hold EXIF data.
+func hasExifHeader(content *ClientContent) bool {
+	contentType := content.ContentType
+	if contentType == "" {
+		contentType = content.Metadata["Content-Type"]
+	}
+	if contentType == "" || contentType == "application/octet-stream" {
+		ext := strings.ToLower(filepath.Ext(content.URL.Path))
+		if exifRawExtensions.Contains(ext) {
+			return true
+		}
+		contentType = guessURLContentType(content.URL.Path)
+	}
+	return strings.HasPrefix(contentType, "image/")
+}
+
+// sourceExifDate - reads the beginning of the source object and returns the
+// date found in its EXIF header, zero time is returned if there is none.
+func sourceExifDate(ctx context.Context, cpURLs URLs, encKeyDB map[string][]prefixSSEPair) (time.Time, *probe.Error) {
+	if !hasExifHeader(cpURLs.SourceContent) {
+		return time.Time{}, nil
+	}
+	sourceURL := cpURLs.SourceContent.URL
+	sourcePath := filepath.ToSlash(filepath.Join(cpURLs.SourceAlias, sourceURL.Path))
+
+	clnt, err := newClientFromAlias(cpURLs.SourceAlias, sourceURL.String())
+	if err != nil {
+		return time.Time{}, err
+	}
+	reader, _, err := clnt.Get(ctx, GetOptions{
+		SSE:         getSSE(sourcePath, encKeyDB[cpURLs.SourceAlias]),
+		VersionID:   cpURLs.SourceContent.VersionID,
+		RangeLength: exifProbeHeadSize,
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer reader.Close()
+
+	head, e := io.ReadAll(reader)
+	if e != nil {
+		return time.Time{}, probe.NewError(e)
+	}
+	return parseExifDate(head), nil
+}
+
+// parseExifDate - returns the date a photo was taken from the EXIF header
+// of JPEG and TIFF based (including most camera RAW formats) images.
+func parseExifDate(b []byte) time.Time {
+	switch {
+	case len(b) >= 2 && b[0] == 0xff && b[1] == 0xd8:
+		// JPEG, look for the APP1 segment holding the EXIF header.
+		b = b[2:]
+		for len(b) >= 4 && b[0] == 0xff {
+			marker := b[1]
+			size := int(binary.BigEndian.Uint16(b[2:4]))
+			if size < 2 || marker == 0xda {
+				// Start of scan, no more metadata after this point.
+				break
+			}
+			if size+2 > len(b) {
+				size = len(b) - 2
+			}
+			segment := b[4 : size+2]
+			if marker == 0xe1 && len(segment) >= 6 && string(segment[:6]) == "Exif\x00\x00" {
+				return parseTiffDate(segment[6:])
+			}
+			b = b[size+2:]
+		}
+	case len(b) >= 4 && (string(b[:4]) == "II*\x00" || string(b[:4]) == "MM\x00*"):
+		return parseTiffDate(b)
+	}
+	return time.Time{}
+}
+
+// parseTiffDate - returns DateTimeOriginal from the EXIF sub-IFD of a TIFF
+// structure, falling back to DateTime of the first IFD.
+func parseTiffDate(b []byte) time.Time {
+	if len(b) < 8 {
+		return time.Time{}
+	}
+	var order binary.ByteOrder
+	switch string(b[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return time.Time{}
+	}
+
+	ifd0 := tiffIFDTags(b, order, order.Uint32(b[4:8]))
+	if offset, ok := ifd0[exifTagExifIFDPointer]; ok {
+		exifIFD := tiffIFDTags(b, order, order.Uint32(offset))
+		if value, ok := exifIFD[exifTagDateTimeOriginal]; ok {
+			if t := parseExifDateTime(tiffASCII(b, order, value)); !t.IsZero() {
+				return t
+			}
+		}
+	}
+	if value, ok := ifd0[exifTagDateTime]; ok {
+		return parseExifDateTime(tiffASCII(b, order, value))
+	}
+	return time.Time{}
+}
+
+// tiffIFDTags - returns the raw 8 bytes (count and value/offset) of the
+// date related tags found in the IFD at the given offset.
+func tiffIFDTags(b []byte, order binary.ByteOrder, offset uint32) map[uint16][]byte {
+	tags := make(map[uint16][]byte)
+	if int64(offset)+2 > int64(len(b)) {
+		return tags
+	}
+	count := int(order.Uint16(b[offset:]))
+	entries := b[offset+2:]
+	for i := 0; i < count && len(entries) >= 12; i++ {
+		switch tag := order.Uint16(entries[0:2]); tag {
+		case exifTagDateTime, exifTagExifIFDPointer, exifTagDateTimeOriginal:
+			if tag == exifTagExifIFDPointer {
+				tags[tag] = entries[8:12]
+			} else {
+				tags[tag] = entries[4:12]
+			}
+		}
+		entries = entries[12:]
+	}
+	return tags
+}
+
+// tiffASCII - returns the ASCII value referenced by a count and offset pair.
+func tiffASCII(b []byte, order binary.ByteOrder, value []byte) string {
+	count := order.Uint32(value[0:4])
+	var data []byte
+	if count <= 4 {
+		data = value[4 : 4+count]
+	} else {
+		offset := order.Uint32(value[4:8])
+		if int64(offset)+int64(count) > int64(len(b)) {
+			return ""
+		}
+		data = b[offset : offset+count]
+	}
+	return strings.TrimRight(string(data), "\x00 ")
+}
+
+// parseExifDateTime - parses EXIF dates of the form "YYYY:MM:DD HH:MM:SS".
+func parseExifDateTime(s string) time.Time {
+	t, e := time.Parse("2006:01:02 15:04:05", s)
+	if e != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -20,10 +20,62 @@ package cmd
 import (
 	"fmt"
 	"runtime"
+	"strconv"
 
+	"github.com/dustin/go-humanize"
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
 )
 
+const (
+	// Part sizes accepted by S3.
+	minPartSize = 5 * humanize.MiByte
+	maxPartSize = 5 * humanize.GiByte
+)
+
+// checkMultipartSyntax - validates the multipart flags of cp and mirror.
+func checkMultipartSyntax(cliCtx *cli.Context) {
+	if v := cliCtx.String("part-size"); v != "" {
+		partSize, e := humanize.ParseBytes(v)
+		fatalIf(probe.NewError(e).Trace(v), "Unable to parse --part-size.")
+		if partSize < minPartSize || partSize > maxPartSize {
+			fatalIf(errInvalidArgument().Trace(v), "--part-size should be between 5MiB and 5GiB.")
+		}
+	}
+	if cliCtx.IsSet("parallel-parts") && cliCtx.Int("parallel-parts") < 1 {
+		fatalIf(errInvalidArgument().Trace(cliCtx.String("parallel-parts")), "--parallel-parts should be equal or greater than 1.")
+	}
+	if v := cliCtx.String("multipart-threshold"); v != "" {
+		threshold, e := humanize.ParseBytes(v)
+		fatalIf(probe.NewError(e).Trace(v), "Unable to parse --multipart-threshold.")
+		if threshold > maxPartSize {
+			fatalIf(errInvalidArgument().Trace(v), "--multipart-threshold should be at most 5GiB.")
+		}
+	}
+}
+
+// multipartFlagValues - returns the part size, the parallel parts and the
+// threshold of the multipart uploads, from the flags or else from the
+// transfer section of the configuration file, empty for defaults.
+func multipartFlagValues(cliCtx *cli.Context) (partSize, parallelParts, threshold string) {
+	partSize = cliCtx.String("part-size")
+	if partSize == "" {
+		partSize = globalTransfer.PartSize
+	}
+	n := cliCtx.Int("parallel-parts")
+	if !cliCtx.IsSet("parallel-parts") && globalTransfer.ParallelParts > 0 {
+		n = globalTransfer.ParallelParts
+	}
+	if n > 0 {
+		parallelParts = strconv.Itoa(n)
+	}
+	threshold = cliCtx.String("multipart-threshold")
+	if threshold == "" {
+		threshold = globalTransfer.MultipartThreshold
+	}
+	return partSize, parallelParts, threshold
+}
+
 func checkCopySyntax(cliCtx *cli.Context) {
 	if len(cliCtx.Args()) < 2 {
 		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code.
@@ -39,6 +91,7 @@ func checkCopySyntax(cliCtx *cli.Context) {
 	tgtURL := URLs[len(URLs)-1]
 	isZip := cliCtx.Bool("zip")
 	versionID := cliCtx.String("version-id")
+	checkMultipartSyntax(cliCtx)
 
 	if versionID != "" && len(srcURLs) > 1 {
 		fatalIf(errDummy().Trace(cliCtx.Args()...), "Unable to pass --version flag with multiple copy sources arguments.")
@@ -64,6 +117,41 @@ func checkCopySyntax(cliCtx *cli.Context) {
 		fatalIf(errInvalidArgument().Trace(), fmt.Sprintf("Both object retention flags `--%s` and `--%s` are required.\n", rdFlag, rmFlag))
 	}
 
+	if organizeBy := cliCtx.String("organize-by"); organizeBy != "" && organizeBy != organizeByExifDate {
+		fatalIf(errInvalidArgument().Trace(organizeBy), fmt.Sprintf("Unsupported --organize-by value `%s`, only `%s` is supported.", organizeBy, organizeByExifDate))
+	}
+
+	switch onConflict := cliCtx.String("on-conflict"); onConflict {
+	case "", conflictOverwrite, conflictSkip, conflictRename, conflictFail:
+	default:
+		fatalIf(errInvalidArgument().Trace(onConflict), fmt.Sprintf("Unsupported --on-conflict value `%s`, use one of overwrite, skip, rename or fail.", onConflict))
+	}
+
+	if cliCtx.Int("strip-components") < 0 {
+		fatalIf(errInvalidArgument().Trace(), "--strip-components cannot be negative.")
+	}
+
+	if cliCtx.Bool("flatten") && cliCtx.IsSet("strip-components") {
+		fatalIf(errInvalidArgument().Trace(), "--flatten and --strip-components cannot be used together.")
+	}
+
+	if text := cliCtx.String("name-template"); text != "" {
+		_, err := parseNameTemplate(text)
+		fatalIf(err.Trace(text), "Unable to parse --name-template.")
+	}
+
+	if cliCtx.IsSet("offset") || cliCtx.IsSet("length") {
+		if cliCtx.Int64("offset") < 0 || cliCtx.Int64("length") < 0 {
+			fatalIf(errInvalidArgument().Trace(), "--offset and --length cannot be negative.")
+		}
+		if len(srcURLs) > 1 || cliCtx.Bool("recursive") {
+			fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--offset and --length copy a part of a single source object, they cannot be used with --recursive or multiple sources.")
+		}
+		if isZip || cliCtx.Bool("verify") || cliCtx.Bool("continue") {
+			fatalIf(errInvalidArgument().Trace(), "--offset and --length cannot be used with --zip, --verify or --continue.")
+		}
+	}
+
 	// Preserve functionality not supported for windows
 	if cliCtx.Bool("preserve") && runtime.GOOS == "windows" {
 		fatalIf(errInvalidArgument().Trace(), "Permissions are not preserved on windows platform.")
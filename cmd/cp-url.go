@@ -19,8 +19,12 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"path"
 	"path/filepath"
 	"strings"
+	"sync"
+	"text/template"
 	"time"
 
 	"github.com/minio/mc/pkg/probe"
@@ -225,10 +229,14 @@ func prepareCopyURLsTypeC(ctx context.Context, cc copyURLsContent, o prepareCopy
 		}
 	}
 
+	_, expandedTargetURL, _ := mustExpandAlias(cc.targetURL)
+	filter := o.filter.forTarget(newClientURL(expandedTargetURL).Type)
+
 	go func(sourceClient Client, cc copyURLsContent, o prepareCopyURLsOpts, copyURLsCh chan URLs) {
 		defer close(copyURLsCh)
 
-		for sourceContent := range sourceClient.List(ctx, ListOptions{Recursive: o.isRecursive, TimeRef: o.timeRef, ShowDir: DirNone, ListZip: o.isZip}) {
+		listCh := sourceClient.List(ctx, ListOptions{Recursive: o.isRecursive, TimeRef: o.timeRef, ShowDir: DirNone, ListZip: o.isZip})
+		for sourceContent := range filterList(ctx, listCh, sourceClient.GetURL().String(), filter) {
 			if sourceContent.Err != nil {
 				// Listing failed.
 				copyURLsCh <- URLs{Error: sourceContent.Err.Trace(sourceClient.GetURL().String())}
@@ -265,6 +273,108 @@ func makeCopyContentTypeC(cc copyURLsContent, sourceClientURL ClientURL) URLs {
 	return makeCopyContentTypeA(cc)
 }
 
+// splitCopyTargetPath - splits a slash separated target path into the target
+// root and the path relative to it. When the target is the root itself, i.e.
+// a single file copied to an object name, the object name is the relative part.
+func splitCopyTargetPath(targetPath, rootPath string) (root, rel string) {
+	rel = strings.TrimPrefix(targetPath, rootPath)
+	if rel == targetPath || strings.TrimPrefix(rel, "/") == "" {
+		return path.Split(targetPath)
+	}
+	return rootPath, strings.TrimPrefix(rel, "/")
+}
+
+// stripCopyTargetPath - removes the first n directories of the slash separated
+// relative path, or all of them when flatten is set. The object name itself is
+// never removed.
+func stripCopyTargetPath(rel string, n int, flatten bool) string {
+	elems := strings.Split(rel, "/")
+	if flatten || n >= len(elems) {
+		n = len(elems) - 1
+	}
+	return strings.Join(elems[n:], "/")
+}
+
+// copyTargetClaims - remembers the source each rewritten target is copied
+// from, rewriting may map several sources to the same target.
+type copyTargetClaims struct {
+	mu      sync.Mutex
+	sources map[string]string
+}
+
+func newCopyTargetClaims() *copyTargetClaims {
+	return &copyTargetClaims{sources: make(map[string]string)}
+}
+
+// claim - reserves target for source, the source already holding the
+// target is returned if it is another one.
+func (c *copyTargetClaims) claim(target, source string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if other, ok := c.sources[target]; ok && other != source {
+		return other, false
+	}
+	c.sources[target] = source
+	return "", true
+}
+
+// copyTargetOpts - options rewriting target object names, applied in this order.
+type copyTargetOpts struct {
+	root            string // expanded target URL
+	flatten         bool
+	stripComponents int
+	nameTemplate    *template.Template
+	organizeBy      string
+
+	// Detects targets rewritten from several sources, unset when
+	// --on-conflict takes care of existing targets.
+	claims *copyTargetClaims
+}
+
+// isSet - returns true if any target rewriting is requested.
+func (o copyTargetOpts) isSet() bool {
+	return o.flatten || o.stripComponents > 0 || o.nameTemplate != nil || o.organizeBy != ""
+}
+
+// mapCopyTarget - rewrites the target object name of cpURLs, relative to the target root.
+func mapCopyTarget(ctx context.Context, cpURLs URLs, o copyTargetOpts, encKeyDB map[string][]prefixSSEPair) URLs {
+	if !o.isSet() || cpURLs.Error != nil || cpURLs.SourceContent == nil || cpURLs.TargetContent == nil {
+		return cpURLs
+	}
+
+	target := *cpURLs.TargetContent
+	root, rel := splitCopyTargetPath(filepath.ToSlash(target.URL.Path), filepath.ToSlash(newClientURL(o.root).Path))
+	rel = stripCopyTargetPath(rel, o.stripComponents, o.flatten)
+
+	if o.nameTemplate != nil {
+		var err *probe.Error
+		rel, err = renderNameTemplate(o.nameTemplate, rel, cpURLs.SourceContent.Size, cpURLs.SourceContent.Time)
+		if err != nil {
+			return cpURLs.WithError(err.Trace(cpURLs.SourceContent.URL.String()))
+		}
+	}
+
+	if o.organizeBy == organizeByExifDate {
+		date, err := copyOrganizeDate(ctx, cpURLs, encKeyDB)
+		if err != nil {
+			return cpURLs.WithError(err.Trace(cpURLs.SourceContent.URL.String()))
+		}
+		if !date.IsZero() {
+			rel = path.Join(date.Format("2006/01/02"), rel)
+		}
+	}
+
+	target.URL.Path = path.Join(root, rel)
+	if o.claims != nil {
+		source := cpURLs.SourceContent.URL.String()
+		if other, ok := o.claims.claim(target.URL.String(), source); !ok {
+			return cpURLs.WithError(probe.NewError(fmt.Errorf("`%s` and `%s` are both copied to `%s`, use --on-conflict to choose how to resolve it", other, source, target.URL.String())))
+		}
+	}
+	cpURLs.TargetContent = &target
+	return cpURLs
+}
+
 // MULTI-SOURCE - Type D: copy([](f|d...), d) -> []B
 // prepareCopyURLsTypeE - prepares target and source clientURLs for copying.
 func prepareCopyURLsTypeD(ctx context.Context, cc copyURLsContent, o prepareCopyURLsOpts) <-chan URLs {
@@ -316,6 +426,8 @@ type prepareCopyURLsOpts struct {
 	versionID               string
 	isZip                   bool
 	ignoreBucketExistsCheck bool
+	filter                  listFilter
+	rangeStart, rangeLength int64
 }
 
 type copyURLsContent struct {
@@ -376,9 +488,33 @@ func prepareCopyURLs(ctx context.Context, o prepareCopyURLsOpts) chan URLs {
 				continue
 			}
 
+			if o.rangeStart > 0 || o.rangeLength > 0 {
+				cpURLs = withCopyRange(cpURLs, o.rangeStart, o.rangeLength)
+			}
+
 			finalCopyURLsCh <- cpURLs
 		}
 	}()
 
 	return finalCopyURLsCh
 }
+
+// withCopyRange - sets the size of the source of cpURLs to the size of its
+// part of length bytes starting at offset start, up to its end when length
+// is zero.
+func withCopyRange(cpURLs URLs, start, length int64) URLs {
+	size := cpURLs.SourceContent.Size
+	if start > 0 && start >= size {
+		return cpURLs.WithError(probe.NewError(fmt.Errorf("specified offset (%d) not smaller than the size of the source (%d)", start, size)).Trace(cpURLs.SourceContent.URL.String()))
+	}
+	content := *cpURLs.SourceContent
+	content.Size = size - start
+	if content.Size < 0 {
+		content.Size = 0
+	}
+	if length > 0 && length < content.Size {
+		content.Size = length
+	}
+	cpURLs.SourceContent = &content
+	return cpURLs
+}
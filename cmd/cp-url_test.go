@@ -0,0 +1,55 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestWithCopyRange(t *testing.T) {
+	testCases := []struct {
+		size, start, length int64
+		expectedSize        int64
+		expectErr           bool
+	}{
+		{size: 100, start: 10, length: 20, expectedSize: 20},
+		{size: 100, start: 10, expectedSize: 90},
+		{size: 100, length: 30, expectedSize: 30},
+		{size: 100, start: 90, length: 20, expectedSize: 10},
+		{size: 100, start: 100, expectErr: true},
+		{size: 0, length: 10, expectedSize: 0},
+		{size: 0, start: 1, expectErr: true},
+	}
+	for i, tc := range testCases {
+		source := &ClientContent{URL: *newClientURL("/tmp/object"), Size: tc.size}
+		cpURLs := withCopyRange(URLs{SourceContent: source}, tc.start, tc.length)
+		if tc.expectErr {
+			if cpURLs.Error == nil {
+				t.Fatalf("Test %d: expected an error", i+1)
+			}
+			continue
+		}
+		if cpURLs.Error != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, cpURLs.Error)
+		}
+		if cpURLs.SourceContent.Size != tc.expectedSize {
+			t.Fatalf("Test %d: expected size %d, got %d", i+1, tc.expectedSize, cpURLs.SourceContent.Size)
+		}
+		if source.Size != tc.size {
+			t.Fatalf("Test %d: the source content was modified", i+1)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/pkg/v2/console"
+)
+
+var daemonInstallFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "user",
+		Usage: "install a systemd user unit, run when the user is logged in, or at boot after 'loginctl enable-linger'",
+	},
+}
+
+var daemonInstallCmd = cli.Command{
+	Name:           "install",
+	Usage:          "install a continuous mirror or watch as a systemd unit or a Windows service",
+	Action:         mainDaemonInstall,
+	OnUsageError:   onUsageError,
+	Before:         setGlobalsFromContext,
+	Flags:          append(daemonInstallFlags, globalFlags...),
+	SkipArgReorder: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] NAME COMMAND [ARGS...]
+
+  COMMAND is 'mirror --watch' or 'watch', run by the service 'mc-NAME' with
+  the current config and working folders. The service starts at boot and
+  restarts 10 seconds after the command ends.
+
+  On Linux, the output of the command is logged to the journal,
+  'journalctl -u mc-NAME'. On Windows, it is logged to the file
+  'daemon\NAME.log' of the config folder, and the failures to the
+  Application event log.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Mirror a local folder to a bucket continuously, across reboots.
+     {{.Prompt}} {{.HelpName}} photos mirror --watch --overwrite ~/Photos s3/mybucket/photos
+
+  2. Log the events of a bucket as a systemd user unit, in JSON.
+     {{.Prompt}} {{.HelpName}} --user events watch --json s3/mybucket
+`,
+}
+
+// main for daemon install command.
+func mainDaemonInstall(cliCtx *cli.Context) error {
+	if len(cliCtx.Args()) < 2 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	console.SetColor("Daemon", color.New(color.FgGreen, color.Bold))
+
+	args := cliCtx.Args()
+	opts, err := newDaemonOptions(args.First(), args.Tail())
+	fatalIf(err, "Invalid daemon.")
+	opts.user = cliCtx.Bool("user")
+
+	file, err := installDaemon(opts)
+	fatalIf(err.Trace(opts.name), "Unable to install the daemon `%s`.", opts.name)
+
+	printMsg(daemonMessage{
+		Event:   "installed",
+		Name:    opts.name,
+		Service: daemonServiceName(opts.name),
+		Command: mcCommandLine(args.Tail()),
+		File:    file,
+	})
+	return nil
+}
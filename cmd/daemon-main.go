@@ -0,0 +1,159 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+var daemonSubcommands = []cli.Command{
+	daemonInstallCmd,
+	daemonUninstallCmd,
+	daemonRunCmd,
+}
+
+var daemonCmd = cli.Command{
+	Name:            "daemon",
+	Usage:           "run a continuous mirror or watch as a system service",
+	Action:          mainDaemon,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	Subcommands:     daemonSubcommands,
+	HideHelpCommand: true,
+}
+
+// mainDaemon is the handle for "mc daemon" command.
+func mainDaemon(ctx *cli.Context) error {
+	commandNotFound(ctx, daemonSubcommands)
+	return nil
+	// Sub-commands like "install", "uninstall" have their own main.
+}
+
+var daemonNameRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// daemonOptions - a daemon, the mc command run by the service named name.
+type daemonOptions struct {
+	name string
+	args []string // arguments of mc, the config folder first
+	dir  string   // working folder of the command
+	user bool     // systemd user unit
+}
+
+// daemonServiceName - returns the name of the service of the daemon name.
+func daemonServiceName(name string) string {
+	return "mc-" + name
+}
+
+func checkDaemonName(name string) *probe.Error {
+	if !daemonNameRegexp.MatchString(name) {
+		return probe.NewError(fmt.Errorf("invalid daemon name `%s`, use letters, digits, '.', '_' and '-'", name))
+	}
+	return nil
+}
+
+// checkDaemonCommand - the daemons run the commands that don't end,
+// 'mirror --watch' and 'watch'.
+func checkDaemonCommand(args []string) *probe.Error {
+	if len(args) == 0 {
+		return probe.NewError(fmt.Errorf("missing the command of the daemon, 'mirror --watch' or 'watch'"))
+	}
+	switch args[0] {
+	case "watch":
+		return nil
+	case "mirror":
+		for _, arg := range args[1:] {
+			if arg == "--" {
+				break
+			}
+			if arg == "--watch" || arg == "-w" || arg == "--watch=true" {
+				return nil
+			}
+		}
+		return probe.NewError(fmt.Errorf("'mirror' needs --watch to run as a daemon, it ends once the mirror is done otherwise"))
+	}
+	return probe.NewError(fmt.Errorf("unsupported daemon command `%s`, use 'mirror --watch' or 'watch'", args[0]))
+}
+
+// newDaemonOptions - returns the daemon running the mc command args, with
+// the current config folder and working folder.
+func newDaemonOptions(name string, args []string) (daemonOptions, *probe.Error) {
+	opts := daemonOptions{name: name}
+	if err := checkDaemonName(name); err != nil {
+		return opts, err
+	}
+	if err := checkDaemonCommand(args); err != nil {
+		return opts, err
+	}
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return opts, err.Trace()
+	}
+	configDir, e := filepath.Abs(configDir)
+	if e != nil {
+		return opts, probe.NewError(e)
+	}
+	if opts.dir, e = os.Getwd(); e != nil {
+		return opts, probe.NewError(e)
+	}
+	opts.args = append([]string{"--config-dir", configDir}, args...)
+	return opts, nil
+}
+
+// daemonMessage - reports a daemon installed or uninstalled.
+type daemonMessage struct {
+	Status  string `json:"status"`
+	Event   string `json:"event"` // installed or uninstalled
+	Name    string `json:"name"`
+	Service string `json:"service"`
+	Command string `json:"command,omitempty"`
+	File    string `json:"file,omitempty"`
+}
+
+// String colorized daemon message
+func (d daemonMessage) String() string {
+	if d.Event == "uninstalled" {
+		return console.Colorize("Daemon", fmt.Sprintf("Removed the service `%s`.", d.Service))
+	}
+	msg := fmt.Sprintf("Installed the service `%s` running `%s`, started now and at boot.", d.Service, d.Command)
+	if d.File != "" {
+		msg += fmt.Sprintf(" Its unit file is `%s`.", d.File)
+	}
+	return console.Colorize("Daemon", msg)
+}
+
+// JSON jsonified daemon message
+func (d daemonMessage) JSON() string {
+	d.Status = "success"
+	msgBytes, e := json.MarshalIndent(d, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// mcCommandLine - returns the mc command line of args, to display.
+func mcCommandLine(args []string) string {
+	return strings.Join(append([]string{"mc"}, args...), " ")
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestCheckDaemonCommand(t *testing.T) {
+	testCases := []struct {
+		args  []string
+		valid bool
+	}{
+		{nil, false},
+		{[]string{"watch", "s3/mybucket"}, true},
+		{[]string{"mirror", "--watch", "~/Photos", "s3/mybucket"}, true},
+		{[]string{"mirror", "-w", "~/Photos", "s3/mybucket"}, true},
+		{[]string{"mirror", "~/Photos", "s3/mybucket"}, false},
+		{[]string{"mirror", "--", "--watch", "s3/mybucket"}, false},
+		{[]string{"ls", "s3/mybucket"}, false},
+	}
+	for i, testCase := range testCases {
+		if err := checkDaemonCommand(testCase.args); (err == nil) != testCase.valid {
+			t.Errorf("Test %d: expected valid %v, got %v", i+1, testCase.valid, err)
+		}
+	}
+
+	for _, name := range []string{"", "a b", "-photos", "../photos"} {
+		if checkDaemonName(name) == nil {
+			t.Errorf("expected `%s` to be an invalid name", name)
+		}
+	}
+	if err := checkDaemonName("photos-2"); err != nil {
+		t.Error(err)
+	}
+}
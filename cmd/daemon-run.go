@@ -0,0 +1,54 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"github.com/minio/cli"
+)
+
+// daemonRunCmd - runs the command of a daemon under the Windows service
+// manager, which needs the service to answer its requests.
+var daemonRunCmd = cli.Command{
+	Name:            "run",
+	Usage:           "run the command of a daemon as a Windows service",
+	Action:          mainDaemonRun,
+	Hidden:          true,
+	SkipFlagParsing: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} NAME DIR ARGS...
+`,
+}
+
+// main for daemon run command.
+func mainDaemonRun(cliCtx *cli.Context) error {
+	args := cliCtx.Args()
+	if len(args) < 3 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	opts := daemonOptions{name: args[0], dir: args[1], args: args[2:]}
+	// The flags are not parsed, the config folder is the daemon's.
+	if len(opts.args) > 1 && opts.args[0] == "--config-dir" {
+		setMcConfigDir(opts.args[1])
+	}
+	fatalIf(checkDaemonName(opts.name), "Invalid daemon.")
+	fatalIf(runDaemon(opts).Trace(opts.name), "Unable to run the daemon `%s`.", opts.name)
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/pkg/v2/console"
+)
+
+var daemonUninstallFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "user",
+		Usage: "uninstall a systemd user unit",
+	},
+}
+
+var daemonUninstallCmd = cli.Command{
+	Name:         "uninstall",
+	Usage:        "stop and remove a daemon installed by 'mc daemon install'",
+	Action:       mainDaemonUninstall,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(daemonUninstallFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] NAME
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Stop and remove the daemon "photos".
+     {{.Prompt}} {{.HelpName}} photos
+`,
+}
+
+// main for daemon uninstall command.
+func mainDaemonUninstall(cliCtx *cli.Context) error {
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	console.SetColor("Daemon", color.New(color.FgGreen, color.Bold))
+
+	name := cliCtx.Args().First()
+	fatalIf(checkDaemonName(name), "Invalid daemon.")
+	opts := daemonOptions{name: name, user: cliCtx.Bool("user")}
+	fatalIf(uninstallDaemon(opts).Trace(name), "Unable to uninstall the daemon `%s`.", name)
+
+	printMsg(daemonMessage{
+		Event:   "uninstalled",
+		Name:    name,
+		Service: daemonServiceName(name),
+	})
+	return nil
+}
@@ -0,0 +1,166 @@
+//go:build linux
+// +build linux
+
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// systemdUnitDir - returns the folder of the system units, or of the user
+// units of the current user.
+func systemdUnitDir(userUnit bool) (string, *probe.Error) {
+	if !userUnit {
+		return "/etc/systemd/system", nil
+	}
+	configDir, e := os.UserConfigDir()
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	return filepath.Join(configDir, "systemd", "user"), nil
+}
+
+// systemdQuote - quotes an argument of ExecStart, escaping the specifiers
+// and the variables expanded by systemd.
+func systemdQuote(arg string) string {
+	arg = strings.NewReplacer("%", "%%", "$", "$$").Replace(arg)
+	if arg != "" && !strings.ContainsAny(arg, " \t\n\"'\\;") {
+		return arg
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(arg) + `"`
+}
+
+// systemdUnit - returns the unit running the daemon with the mc binary
+// exe, restarted 10 seconds after it ends.
+func systemdUnit(opts daemonOptions, exe, username string) string {
+	execStart := []string{systemdQuote(exe)}
+	for _, arg := range opts.args {
+		execStart = append(execStart, systemdQuote(arg))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Unit]\n")
+	fmt.Fprintf(&b, "Description=MinIO Client daemon %s\n", opts.name)
+	fmt.Fprintf(&b, "Wants=network-online.target\n")
+	fmt.Fprintf(&b, "After=network-online.target\n")
+	fmt.Fprintf(&b, "\n[Service]\n")
+	fmt.Fprintf(&b, "Type=simple\n")
+	if username != "" {
+		fmt.Fprintf(&b, "User=%s\n", username)
+	}
+	fmt.Fprintf(&b, "WorkingDirectory=%s\n", systemdQuote(opts.dir))
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(execStart, " "))
+	fmt.Fprintf(&b, "Restart=always\n")
+	fmt.Fprintf(&b, "RestartSec=10\n")
+	fmt.Fprintf(&b, "StandardOutput=journal\n")
+	fmt.Fprintf(&b, "StandardError=journal\n")
+	fmt.Fprintf(&b, "SyslogIdentifier=%s\n", daemonServiceName(opts.name))
+	fmt.Fprintf(&b, "\n[Install]\n")
+	if opts.user {
+		fmt.Fprintf(&b, "WantedBy=default.target\n")
+	} else {
+		fmt.Fprintf(&b, "WantedBy=multi-user.target\n")
+	}
+	return b.String()
+}
+
+func systemctl(userUnit bool, args ...string) *probe.Error {
+	if userUnit {
+		args = append([]string{"--user"}, args...)
+	}
+	out, e := exec.Command("systemctl", args...).CombinedOutput()
+	if e != nil {
+		return probe.NewError(fmt.Errorf("systemctl %s: %v: %s", strings.Join(args, " "), e, strings.TrimSpace(string(out))))
+	}
+	return nil
+}
+
+// installDaemon - writes the systemd unit of the daemon, then enables and
+// starts it. Returns the unit file.
+func installDaemon(opts daemonOptions) (string, *probe.Error) {
+	exe, e := os.Executable()
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	// System units run as the user installing them, with its config.
+	var username string
+	if !opts.user {
+		if u, e := user.Current(); e == nil {
+			username = u.Username
+		}
+	}
+	dir, err := systemdUnitDir(opts.user)
+	if err != nil {
+		return "", err.Trace()
+	}
+	if e = os.MkdirAll(dir, 0o755); e != nil {
+		return "", probe.NewError(e)
+	}
+	unit := daemonServiceName(opts.name) + ".service"
+	file := filepath.Join(dir, unit)
+	if _, e = os.Stat(file); e == nil {
+		return "", probe.NewError(fmt.Errorf("the unit `%s` already exists", file))
+	}
+	if e = os.WriteFile(file, []byte(systemdUnit(opts, exe, username)), 0o644); e != nil {
+		return "", probe.NewError(e)
+	}
+	if err = systemctl(opts.user, "daemon-reload"); err == nil {
+		err = systemctl(opts.user, "enable", "--now", unit).Trace(unit)
+	}
+	if err != nil {
+		// The unit is installed again once the failure is fixed.
+		os.Remove(file)
+		systemctl(opts.user, "daemon-reload")
+		return "", err.Trace()
+	}
+	return file, nil
+}
+
+// uninstallDaemon - stops and disables the systemd unit of the daemon,
+// then removes it.
+func uninstallDaemon(opts daemonOptions) *probe.Error {
+	dir, err := systemdUnitDir(opts.user)
+	if err != nil {
+		return err.Trace()
+	}
+	unit := daemonServiceName(opts.name) + ".service"
+	file := filepath.Join(dir, unit)
+	if _, e := os.Stat(file); e != nil {
+		return probe.NewError(e)
+	}
+	if err = systemctl(opts.user, "disable", "--now", unit); err != nil {
+		return err.Trace(unit)
+	}
+	if e := os.Remove(file); e != nil {
+		return probe.NewError(e)
+	}
+	return systemctl(opts.user, "daemon-reload")
+}
+
+func runDaemon(opts daemonOptions) *probe.Error {
+	return probe.NewError(fmt.Errorf("the daemons run under systemd on Linux, see 'systemctl status %s'", daemonServiceName(opts.name)))
+}
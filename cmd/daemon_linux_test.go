@@ -0,0 +1,71 @@
+//go:build linux
+// +build linux
+
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSystemdQuote(t *testing.T) {
+	testCases := map[string]string{
+		"s3/mybucket":  "s3/mybucket",
+		"":             `""`,
+		"My Photos":    `"My Photos"`,
+		`a"b`:          `"a\"b"`,
+		`c:\photos`:    `"c:\\photos"`,
+		"100%":         "100%%",
+		"$HOME/Photos": "$$HOME/Photos",
+		"*.tmp":        "*.tmp",
+		";":            `";"`,
+	}
+	for arg, expected := range testCases {
+		if quoted := systemdQuote(arg); quoted != expected {
+			t.Errorf("expected %s for `%s`, got %s", expected, arg, quoted)
+		}
+	}
+}
+
+func TestSystemdUnit(t *testing.T) {
+	opts := daemonOptions{
+		name: "photos",
+		args: []string{"--config-dir", "/home/me/.mc", "mirror", "--watch", "My Photos", "s3/mybucket"},
+		dir:  "/home/me",
+	}
+	unit := systemdUnit(opts, "/usr/local/bin/mc", "me")
+	for _, line := range []string{
+		"User=me",
+		"WorkingDirectory=/home/me",
+		`ExecStart=/usr/local/bin/mc --config-dir /home/me/.mc mirror --watch "My Photos" s3/mybucket`,
+		"Restart=always",
+		"SyslogIdentifier=mc-photos",
+		"WantedBy=multi-user.target",
+	} {
+		if !strings.Contains(unit, line+"\n") {
+			t.Errorf("expected the line `%s` in the unit:\n%s", line, unit)
+		}
+	}
+
+	opts.user = true
+	if unit = systemdUnit(opts, "/usr/local/bin/mc", ""); strings.Contains(unit, "User=") || !strings.Contains(unit, "WantedBy=default.target\n") {
+		t.Errorf("unexpected user unit:\n%s", unit)
+	}
+}
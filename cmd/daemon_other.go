@@ -0,0 +1,42 @@
+//go:build !linux && !windows
+// +build !linux,!windows
+
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+var errDaemonUnsupported = fmt.Errorf("daemons are not supported on %s, only systemd units on Linux and Windows services", runtime.GOOS)
+
+func installDaemon(opts daemonOptions) (string, *probe.Error) {
+	return "", probe.NewError(errDaemonUnsupported)
+}
+
+func uninstallDaemon(opts daemonOptions) *probe.Error {
+	return probe.NewError(errDaemonUnsupported)
+}
+
+func runDaemon(opts daemonOptions) *probe.Error {
+	return probe.NewError(errDaemonUnsupported)
+}
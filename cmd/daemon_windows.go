@@ -0,0 +1,201 @@
+//go:build windows
+// +build windows
+
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// Delay of the restart of a failed daemon command.
+const daemonRestartDelay = 10 * time.Second
+
+// installDaemon - creates the Windows service running the daemon through
+// 'mc daemon run', started at boot and restarted when it fails.
+func installDaemon(opts daemonOptions) (string, *probe.Error) {
+	if opts.user {
+		return "", probe.NewError(fmt.Errorf("--user is only supported by systemd"))
+	}
+	exe, e := os.Executable()
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	m, e := mgr.Connect()
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	defer m.Disconnect()
+
+	name := daemonServiceName(opts.name)
+	args := append([]string{"daemon", "run", opts.name, opts.dir}, opts.args...)
+	s, e := m.CreateService(name, exe, mgr.Config{
+		DisplayName:      "MinIO Client daemon " + opts.name,
+		Description:      mcCommandLine(opts.args),
+		StartType:        mgr.StartAutomatic,
+		DelayedAutoStart: true,
+	}, args...)
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	defer s.Close()
+
+	restart := mgr.RecoveryAction{Type: mgr.ServiceRestart, Delay: daemonRestartDelay}
+	if e = s.SetRecoveryActions([]mgr.RecoveryAction{restart, restart, restart}, uint32((24 * time.Hour).Seconds())); e == nil {
+		e = s.SetRecoveryActionsOnNonCrashFailures(true)
+	}
+	if e == nil {
+		e = eventlog.InstallAsEventCreate(name, eventlog.Error|eventlog.Warning|eventlog.Info)
+	}
+	if e == nil {
+		e = s.Start()
+	}
+	if e != nil {
+		s.Delete()
+		eventlog.Remove(name)
+		return "", probe.NewError(e)
+	}
+	return "", nil
+}
+
+// uninstallDaemon - stops and deletes the Windows service of the daemon.
+func uninstallDaemon(opts daemonOptions) *probe.Error {
+	m, e := mgr.Connect()
+	if e != nil {
+		return probe.NewError(e)
+	}
+	defer m.Disconnect()
+
+	name := daemonServiceName(opts.name)
+	s, e := m.OpenService(name)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	defer s.Close()
+
+	if status, e := s.Query(); e == nil && status.State != svc.Stopped {
+		s.Control(svc.Stop)
+	}
+	if e = s.Delete(); e != nil {
+		return probe.NewError(e)
+	}
+	eventlog.Remove(name)
+	return nil
+}
+
+// daemonService - runs the command of a daemon in a child process, with
+// its output appended to the log file of the daemon.
+type daemonService struct {
+	opts    daemonOptions
+	logFile string
+	elog    *eventlog.Log
+}
+
+// Execute - implements svc.Handler. The service fails when the command
+// ends, to be restarted by the service manager.
+func (d *daemonService) Execute(_ []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+
+	logFile, e := os.OpenFile(d.logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if e != nil {
+		d.elog.Error(1, fmt.Sprintf("Unable to open the log file `%s`: %v", d.logFile, e))
+		return true, 1
+	}
+	defer logFile.Close()
+
+	exe, e := os.Executable()
+	if e != nil {
+		d.elog.Error(1, e.Error())
+		return true, 1
+	}
+	cmd := exec.Command(exe, d.opts.args...)
+	cmd.Dir = d.opts.dir
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if e = cmd.Start(); e != nil {
+		d.elog.Error(1, fmt.Sprintf("Unable to start `%s`: %v", mcCommandLine(d.opts.args), e))
+		return true, 1
+	}
+	d.elog.Info(1, fmt.Sprintf("Started `%s`, logged to `%s`.", mcCommandLine(d.opts.args), d.logFile))
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	changes <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	for {
+		select {
+		case e = <-done:
+			d.elog.Error(1, fmt.Sprintf("`%s` ended: %v", mcCommandLine(d.opts.args), e))
+			return true, 1
+		case r := <-requests:
+			switch r.Cmd {
+			case svc.Interrogate:
+				changes <- r.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				changes <- svc.Status{State: svc.StopPending}
+				cmd.Process.Kill()
+				<-done
+				return false, 0
+			}
+		}
+	}
+}
+
+// runDaemon - runs the daemon under the Windows service manager.
+func runDaemon(opts daemonOptions) *probe.Error {
+	isService, e := svc.IsWindowsService()
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if !isService {
+		return probe.NewError(fmt.Errorf("the daemons are run by the Windows service manager, see 'sc query %s'", daemonServiceName(opts.name)))
+	}
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return err.Trace()
+	}
+	logDir := filepath.Join(configDir, "daemon")
+	if e = os.MkdirAll(logDir, 0o700); e != nil {
+		return probe.NewError(e)
+	}
+	name := daemonServiceName(opts.name)
+	elog, e := eventlog.Open(name)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	defer elog.Close()
+
+	e = svc.Run(name, &daemonService{
+		opts:    opts,
+		logFile: filepath.Join(logDir, opts.name+".log"),
+		elog:    elog,
+	})
+	if e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+// baseChange - change of an object since the base snapshot.
+type baseChange int
+
+const (
+	baseUnchanged baseChange = iota
+	baseAdded
+	baseModified
+	baseDeleted
+)
+
+func (c baseChange) String() string {
+	switch c {
+	case baseAdded:
+		return "added"
+	case baseModified:
+		return "modified"
+	case baseDeleted:
+		return "deleted"
+	}
+	return ""
+}
+
+// changeSinceBase - returns the change of content, nil when missing, since
+// the entry of the snapshot created at created, found when inBase.
+func changeSinceBase(entry snapshotEntry, inBase bool, content *ClientContent, created time.Time) baseChange {
+	switch {
+	case content == nil && inBase:
+		return baseDeleted
+	case content == nil:
+		return baseUnchanged
+	case !inBase:
+		return baseAdded
+	case !entry.unchanged(content, created):
+		return baseModified
+	}
+	return baseUnchanged
+}
+
+// diffBaseMessage - an object changed since the base snapshot on either
+// side, conflicting when both sides changed it differently.
+type diffBaseMessage struct {
+	Status   string `json:"status"`
+	Name     string `json:"name"`
+	Source   string `json:"source,omitempty"`
+	Target   string `json:"target,omitempty"`
+	Conflict bool   `json:"conflict"`
+}
+
+func newDiffBaseMessage(name string, source, target baseChange, first, second *ClientContent) (diffBaseMessage, bool) {
+	msg := diffBaseMessage{Name: name, Source: source.String(), Target: target.String()}
+	switch {
+	case source == baseUnchanged && target == baseUnchanged:
+		return msg, false
+	case source == baseUnchanged || target == baseUnchanged:
+	case source == baseDeleted && target == baseDeleted:
+	case source == baseDeleted || target == baseDeleted:
+		msg.Conflict = true
+	default:
+		// Added or modified on both sides, alike unless their sizes or
+		// their ETags differ.
+		msg.Conflict = first.Size != second.Size ||
+			first.ETag != "" && second.ETag != "" && first.ETag != second.ETag
+	}
+	return msg, true
+}
+
+// String colorized diff message
+func (d diffBaseMessage) String() string {
+	switch {
+	case d.Conflict:
+		return console.Colorize("DiffConflict", fmt.Sprintf("! %s (%s in source, %s in target)", quoteName(d.Name), d.Source, d.Target))
+	case d.Target == "":
+		return console.Colorize("DiffOnlyInFirst", fmt.Sprintf("< %s (%s in source)", quoteName(d.Name), d.Source))
+	case d.Source == "":
+		return console.Colorize("DiffOnlyInSecond", fmt.Sprintf("> %s (%s in target)", quoteName(d.Name), d.Target))
+	}
+	return console.Colorize("DiffBoth", fmt.Sprintf("= %s (%s in source and target)", quoteName(d.Name), d.Source))
+}
+
+// JSON jsonified diff message
+func (d diffBaseMessage) JSON() string {
+	d.Status = "success"
+	diffJSONBytes, e := json.MarshalIndent(d, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(diffJSONBytes)
+}
+
+// newDiffBaseReport - returns the report of --report-html with --base,
+// with the objects counted by diffBaseReportKind.
+func newDiffBaseReport(firstURL, secondURL, baseFile string) *runReport {
+	return newRunReport(fmt.Sprintf("Changes to %s and %s since %s", firstURL, secondURL, baseFile),
+		"Changed in source", "Changed in target", "Changed alike", "Conflicts")
+}
+
+// diffBaseReportKind - returns the kind of the change in the report.
+func diffBaseReportKind(d diffBaseMessage) int {
+	switch {
+	case d.Conflict:
+		return 3
+	case d.Target == "":
+		return 0
+	case d.Source == "":
+		return 1
+	}
+	return 2
+}
+
+// doDiffBase - compares both folders with the base snapshot, reports the
+// objects changed since on either side and the conflicting changes.
+func doDiffBase(ctx context.Context, firstURL, secondURL, baseFile string, opts diffOptions) error {
+	base, err := loadSnapshot(baseFile)
+	fatalIf(err.Trace(baseFile), "Unable to load the snapshot `%s`.", baseFile)
+	entries := base.byName()
+
+	firstClient, secondClient := newDiffClients(firstURL, secondURL)
+	firstBase, secondBase := firstClient.GetURL().String(), secondClient.GetURL().String()
+
+	var differences int64
+	defer startLiveStatus("diff", func(m *liveStatusMessage) {
+		m.Differences = atomic.LoadInt64(&differences)
+	})()
+
+	report := func(name string, first, second *ClientContent) {
+		entry, inBase := entries[name]
+		delete(entries, name)
+		if matchExcludeStorageClasses(opts.ignoreStorageClasses, first, second) {
+			return
+		}
+		msg, changed := newDiffBaseMessage(name, changeSinceBase(entry, inBase, first, base.Created), changeSinceBase(entry, inBase, second, base.Created), first, second)
+		if changed {
+			atomic.AddInt64(&differences, 1)
+			if opts.report != nil {
+				opts.report.add(msg.Name, diffBaseReportKind(msg), contentSize(first, second))
+			}
+			printMsg(msg)
+		}
+	}
+
+	var errSeen bool
+	for diffMsg := range objectDifference(ctx, firstClient, secondClient, false, true, diffTimeNone, opts.filter) {
+		if diffMsg.Error != nil {
+			errorIf(diffMsg.Error, "Unable to calculate objects difference.")
+			errSeen = true
+			if opts.report != nil {
+				opts.report.addFailure(diffMsg.Error, diffMsg.FirstURL, diffMsg.SecondURL)
+			}
+			continue
+		}
+		first, second := diffMsg.firstContent, diffMsg.secondContent
+		if first != nil {
+			report(relativeName(first.URL.String(), firstBase, first.URL.Separator), first, second)
+		} else {
+			report(relativeName(second.URL.String(), secondBase, second.URL.Separator), nil, second)
+		}
+	}
+
+	// The objects of the snapshot left were deleted on both sides, unless
+	// they were not compared.
+	if !errSeen {
+		names := make([]string, 0, len(entries))
+		for name := range entries {
+			if opts.filter.match(name, objectStorage) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			report(name, nil, nil)
+		}
+	}
+
+	if !opts.exitCode {
+		return nil
+	}
+	if errSeen {
+		return exitStatus(diffExitStatusTrouble)
+	}
+	if differences > 0 {
+		return exitStatus(diffExitStatusDifferent)
+	}
+	return nil
+}
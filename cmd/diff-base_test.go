@@ -0,0 +1,64 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewDiffBaseMessage(t *testing.T) {
+	created := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	before, after := created.Add(-time.Hour), created.Add(time.Hour)
+	entry := snapshotEntry{Name: "a", Size: 1, LastModified: before}
+	same := &ClientContent{Size: 1, Time: before}
+	modified := &ClientContent{Size: 2, Time: after}
+	other := &ClientContent{Size: 3, Time: after}
+
+	testCases := []struct {
+		inBase            bool
+		first, second     *ClientContent
+		source, target    string
+		changed, conflict bool
+	}{
+		{true, same, same, "", "", false, false},
+		{true, modified, same, "modified", "", true, false},
+		{true, same, nil, "", "deleted", true, false},
+		{true, nil, nil, "deleted", "deleted", true, false},
+		{true, modified, modified, "modified", "modified", true, false},
+		{true, modified, other, "modified", "modified", true, true},
+		{true, nil, modified, "deleted", "modified", true, true},
+		{false, modified, nil, "added", "", true, false},
+		{false, modified, modified, "added", "added", true, false},
+		{false, modified, other, "added", "added", true, true},
+	}
+	for i, testCase := range testCases {
+		msg, changed := newDiffBaseMessage("a",
+			changeSinceBase(entry, testCase.inBase, testCase.first, created),
+			changeSinceBase(entry, testCase.inBase, testCase.second, created),
+			testCase.first, testCase.second)
+		if changed != testCase.changed {
+			t.Errorf("Test %d: expected changed %v, got %v", i+1, testCase.changed, changed)
+			continue
+		}
+		if msg.Source != testCase.source || msg.Target != testCase.target || msg.Conflict != testCase.conflict {
+			t.Errorf("Test %d: expected %s/%s conflict %v, got %s/%s conflict %v", i+1,
+				testCase.source, testCase.target, testCase.conflict, msg.Source, msg.Target, msg.Conflict)
+		}
+	}
+}
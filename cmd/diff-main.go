@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/fatih/color"
@@ -32,7 +33,58 @@ import (
 
 // diff specific flags.
 var (
-	diffFlags = []cli.Flag{}
+	diffFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "trailing-slash",
+			Usage: "follow rsync trailing slash semantics, 'dir' is compared with TARGET/dir and 'dir/' with TARGET",
+		},
+		cli.BoolFlag{
+			Name:  "newer",
+			Usage: "compare modification time only, report objects newer in source",
+		},
+		cli.BoolFlag{
+			Name:  "older",
+			Usage: "compare modification time only, report objects older in source",
+		},
+		cli.StringSliceFlag{
+			Name:  "ignore-storage-class",
+			Usage: "ignore object(s) stored in the specified storage class on either side",
+		},
+		cli.StringSliceFlag{
+			Name:  "exclude",
+			Usage: "exclude object(s) that match specified object name pattern",
+		},
+		cli.StringSliceFlag{
+			Name:  "include",
+			Usage: "only compare object(s) that match specified object name pattern",
+		},
+		cli.BoolFlag{
+			Name:  "skip-dir-markers",
+			Usage: "skip the zero byte objects named like a folder, e.g. 'photos/', created by other tools to show folders",
+		},
+		cli.BoolFlag{
+			Name:  "exit-code",
+			Usage: "exit with 1 when differences are found and 2 on errors, like GNU diff",
+		},
+		cli.BoolFlag{
+			Name:  "name-only",
+			Usage: "print only the names of the differing objects, relative to SOURCE and TARGET",
+		},
+		cli.BoolFlag{
+			Name:  "summary",
+			Usage: "print only the number of differing objects",
+		},
+		cli.StringFlag{
+			Name:  "base",
+			Usage: "report the changes made on each side since the snapshot of 'mc snapshot create', and the conflicting ones",
+		},
+	}
+)
+
+// Exit status of diff with --exit-code, like GNU diff.
+const (
+	diffExitStatusDifferent = 1
+	diffExitStatusTrouble   = 2
 )
 
 // Compute differences in object name, size, and date between two buckets.
@@ -42,7 +94,7 @@ var diffCmd = cli.Command{
 	Action:       mainDiff,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(diffFlags, globalFlags...),
+	Flags:        append(append(diffFlags, reportHTMLFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -54,11 +106,25 @@ FLAGS:
   {{end}}
 DESCRIPTION:
   Diff only calculates differences in object name, size and time. It *DOES NOT* compare objects' contents.
+  Objects under a prefix with a client side encryption key, see 'mc encrypt key set --client-side',
+  are compared by their decrypted size.
+
+  SOURCE or TARGET may be a snapshot file of 'mc snapshot create', its objects are compared by size
+  and by ETag when both are known, to audit a bucket or folder without the data the snapshot was
+  taken of.
 
 LEGEND:
   < - object is only in source.
   > - object is only in destination.
   ! - newer object is in source.
+  ~ - object differs in modification time (--newer or --older).
+  ! - object differs in ETag from its snapshot.
+
+  With --base, the objects changed since the snapshot:
+  < - object is only changed in source.
+  > - object is only changed in destination.
+  = - object is changed alike in source and destination.
+  ! - object is changed differently in source and destination, a conflict.
 
 EXAMPLES:
   1. Compare a local folder with a folder on Amazon S3 cloud storage.
@@ -66,6 +132,40 @@ EXAMPLES:
 
   2. Compare two folders on a local filesystem.
      {{.Prompt}} {{.HelpName}} ~/Photos /Media/Backup/Photos
+
+  3. List objects modified locally after they were last uploaded to Amazon S3 cloud storage.
+     {{.Prompt}} {{.HelpName}} --newer ~/Photos s3/mybucket/Photos
+
+  4. Compare a local folder with the copy made by 'mc mirror --trailing-slash ~/Photos s3/mybucket'.
+     {{.Prompt}} {{.HelpName}} --trailing-slash ~/Photos s3/mybucket
+
+  5. Compare two buckets, ignoring objects archived to GLACIER.
+     {{.Prompt}} {{.HelpName}} --ignore-storage-class GLACIER s3/mybucket play/mybucket
+
+  6. Compare a local project with its backup, skipping '.git/', 'node_modules/' and temporary files.
+     {{.Prompt}} {{.HelpName}} --exclude ".git/" --exclude "node_modules/" --exclude "*.tmp" ~/project s3/backup/project
+
+  7. Compare only the Go source files of two folders.
+     {{.Prompt}} {{.HelpName}} --include "*.go" ~/project /Media/Backup/project
+
+  8. Compare two buckets, ignoring the empty folder markers created by other tools.
+     {{.Prompt}} {{.HelpName}} --skip-dir-markers s3/mybucket play/mybucket
+
+  9. Check in a script whether a backup is up to date.
+     {{.Prompt}} {{.HelpName}} --exit-code --summary ~/Photos s3/backup/Photos || echo "backup is stale"
+
+  10. Copy the objects missing or differing in a backup, listed by their names.
+     {{.Prompt}} {{.HelpName}} --name-only s3/mybucket play/mybucket | while read -r name; do mc cp "s3/mybucket/$name" "play/mybucket/$name"; done
+
+  11. List the changes made to a local folder and to its copy since they were last synced, and the conflicts.
+     {{.Prompt}} mc snapshot create s3/mybucket/photos photos.snapshot
+     {{.Prompt}} {{.HelpName}} --base photos.snapshot ~/Photos s3/mybucket/photos
+
+  12. Audit a bucket against its snapshot of last month.
+     {{.Prompt}} {{.HelpName}} photos-2023-04.json.gz s3/mybucket/photos
+
+  13. Compare a bucket with its replica, and save the differences per prefix in a page attached to a change ticket.
+     {{.Prompt}} {{.HelpName}} --summary --report-html diff-report.html s3/mybucket play/mybucket
 `,
 }
 
@@ -85,19 +185,23 @@ func (d diffMessage) String() string {
 	msg := ""
 	switch d.Diff {
 	case differInFirst:
-		msg = console.Colorize("DiffOnlyInFirst", "< "+d.FirstURL)
+		msg = console.Colorize("DiffOnlyInFirst", "< "+quoteName(d.FirstURL))
 	case differInSecond:
-		msg = console.Colorize("DiffOnlyInSecond", "> "+d.SecondURL)
+		msg = console.Colorize("DiffOnlyInSecond", "> "+quoteName(d.SecondURL))
 	case differInType:
-		msg = console.Colorize("DiffType", "! "+d.SecondURL)
+		msg = console.Colorize("DiffType", "! "+quoteName(d.SecondURL))
 	case differInSize:
-		msg = console.Colorize("DiffSize", "! "+d.SecondURL)
+		msg = console.Colorize("DiffSize", "! "+quoteName(d.SecondURL))
 	case differInMetadata:
-		msg = console.Colorize("DiffMetadata", "! "+d.SecondURL)
+		msg = console.Colorize("DiffMetadata", "! "+quoteName(d.SecondURL))
 	case differInAASourceMTime:
-		msg = console.Colorize("DiffMMSourceMTime", "! "+d.SecondURL)
+		msg = console.Colorize("DiffMMSourceMTime", "! "+quoteName(d.SecondURL))
+	case differInTime:
+		msg = console.Colorize("DiffTime", "~ "+quoteName(d.SecondURL))
+	case differInETag:
+		msg = console.Colorize("DiffETag", "! "+quoteName(d.SecondURL))
 	case differInNone:
-		msg = console.Colorize("DiffInNone", "= "+d.FirstURL)
+		msg = console.Colorize("DiffInNone", "= "+quoteName(d.FirstURL))
 	default:
 		fatalIf(errDummy().Trace(d.FirstURL, d.SecondURL),
 			"Unhandled difference between `"+d.FirstURL+"` and `"+d.SecondURL+"`.")
@@ -114,6 +218,86 @@ func (d diffMessage) JSON() string {
 	return string(diffJSONBytes)
 }
 
+// diffNameMessage - name of a differing object, relative to the compared
+// folders, printed with --name-only.
+type diffNameMessage struct {
+	Status string     `json:"status"`
+	Name   string     `json:"name"`
+	Diff   differType `json:"diff"`
+}
+
+func (d diffNameMessage) String() string {
+	return quoteName(d.Name)
+}
+
+func (d diffNameMessage) JSON() string {
+	d.Status = "success"
+	diffJSONBytes, e := json.MarshalIndent(d, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(diffJSONBytes)
+}
+
+// diffSummaryMessage - number of differing objects, printed with --summary.
+type diffSummaryMessage struct {
+	Status       string `json:"status"`
+	OnlyInSource int64  `json:"onlyInSource"`
+	OnlyInTarget int64  `json:"onlyInTarget"`
+	Differing    int64  `json:"differing"`
+	Total        int64  `json:"total"`
+}
+
+func (d *diffSummaryMessage) add(diff differType) {
+	switch diff {
+	case differInFirst:
+		d.OnlyInSource++
+	case differInSecond:
+		d.OnlyInTarget++
+	default:
+		d.Differing++
+	}
+	d.Total++
+}
+
+func (d diffSummaryMessage) String() string {
+	return fmt.Sprintf("%d object(s) only in source, %d only in target, %d differing, %d in total.",
+		d.OnlyInSource, d.OnlyInTarget, d.Differing, d.Total)
+}
+
+func (d diffSummaryMessage) JSON() string {
+	d.Status = "success"
+	diffJSONBytes, e := json.MarshalIndent(d, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(diffJSONBytes)
+}
+
+// newDiffReport - returns the report of --report-html, with the objects
+// counted by diffReportKind.
+func newDiffReport(firstURL, secondURL string) *runReport {
+	return newRunReport(fmt.Sprintf("Differences between %s and %s", firstURL, secondURL),
+		"Only in source", "Only in target", "Differing")
+}
+
+// diffReportKind - returns the kind of the difference in the report.
+func diffReportKind(diff differType) int {
+	switch diff {
+	case differInFirst:
+		return 0
+	case differInSecond:
+		return 1
+	}
+	return 2
+}
+
+// diffOptions - options of the diff of two folders.
+type diffOptions struct {
+	cmpTime              diffTimeMode
+	ignoreStorageClasses []string
+	filter               listFilter
+	nameOnly, summary    bool
+	exitCode             bool
+	report               *runReport
+}
+
 func checkDiffSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 	if len(cliCtx.Args()) != 2 {
 		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
@@ -123,12 +307,46 @@ func checkDiffSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 			fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "Unable to validate empty argument.")
 		}
 	}
+	if cliCtx.Bool("newer") && cliCtx.Bool("older") {
+		fatalIf(errInvalidArgument().Trace(), "--newer and --older cannot be used together.")
+	}
+	if cliCtx.Bool("name-only") && cliCtx.Bool("summary") {
+		fatalIf(errInvalidArgument().Trace(), "--name-only and --summary cannot be used together.")
+	}
+	if cliCtx.IsSet("base") {
+		for _, flag := range []string{"newer", "older", "name-only", "summary"} {
+			if cliCtx.Bool(flag) {
+				fatalIf(errInvalidArgument().Trace(), "--base and --"+flag+" cannot be used together.")
+			}
+		}
+	}
+
 	URLs := cliCtx.Args()
 	firstURL := URLs[0]
 	secondURL := URLs[1]
 
 	// Diff only works between two directories, verify them below.
 
+	if cliCtx.IsSet("base") && (isSnapshotFile(firstURL) || isSnapshotFile(secondURL)) {
+		fatalIf(errInvalidArgument().Trace(firstURL, secondURL), "--base compares two folders, not snapshots.")
+	}
+	if isSnapshotFile(firstURL) || isSnapshotFile(secondURL) {
+		// Snapshots are loaded once compared, the folder is verified.
+		for _, u := range []string{firstURL, secondURL} {
+			if isSnapshotFile(u) {
+				continue
+			}
+			_, content, err := url2Stat(ctx, url2StatOptions{urlStr: u, encKeyDB: encKeyDB})
+			if err != nil {
+				fatalIf(err.Trace(u), fmt.Sprintf("Unable to stat '%s'.", u))
+			}
+			if !content.Type.IsDir() {
+				fatalIf(errInvalidArgument().Trace(u), fmt.Sprintf("`%s` is not a folder.", u))
+			}
+		}
+		return
+	}
+
 	// Verify if firstURL is accessible.
 	_, firstContent, err := url2Stat(ctx, url2StatOptions{urlStr: firstURL, versionID: "", fileAttr: false, encKeyDB: encKeyDB, timeRef: time.Time{}, isZip: false, ignoreBucketExistsCheck: false})
 	if err != nil {
@@ -155,44 +373,96 @@ func checkDiffSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 	}
 }
 
-// doDiffMain runs the diff.
-func doDiffMain(ctx context.Context, firstURL, secondURL string) error {
+// newDiffClient - returns the client of the folder u.
+func newDiffClient(u string) Client {
 	// Source and targets are always directories
-	sourceSeparator := string(newClientURL(firstURL).Separator)
-	if !strings.HasSuffix(firstURL, sourceSeparator) {
-		firstURL = firstURL + sourceSeparator
-	}
-	targetSeparator := string(newClientURL(secondURL).Separator)
-	if !strings.HasSuffix(secondURL, targetSeparator) {
-		secondURL = secondURL + targetSeparator
+	separator := string(newClientURL(u).Separator)
+	if !strings.HasSuffix(u, separator) {
+		u = u + separator
 	}
 
 	// Expand aliased urls.
-	firstAlias, firstURL, _ := mustExpandAlias(firstURL)
-	secondAlias, secondURL, _ := mustExpandAlias(secondURL)
-
-	firstClient, err := newClientFromAlias(firstAlias, firstURL)
+	alias, u, _ := mustExpandAlias(u)
+	clnt, err := newClientFromAlias(alias, u)
 	if err != nil {
-		fatalIf(err.Trace(firstAlias, firstURL, secondAlias, secondURL),
-			fmt.Sprintf("Failed to diff '%s' and '%s'", firstURL, secondURL))
+		fatalIf(err.Trace(alias, u), fmt.Sprintf("Failed to diff '%s'", u))
 	}
+	return clnt
+}
 
-	secondClient, err := newClientFromAlias(secondAlias, secondURL)
-	if err != nil {
-		fatalIf(err.Trace(firstAlias, firstURL, secondAlias, secondURL),
-			fmt.Sprintf("Failed to diff '%s' and '%s'", firstURL, secondURL))
+// newDiffClients - returns the clients of the folders firstURL and
+// secondURL.
+func newDiffClients(firstURL, secondURL string) (firstClient, secondClient Client) {
+	return newDiffClient(firstURL), newDiffClient(secondURL)
+}
+
+// doDiffMain runs the diff.
+func doDiffMain(ctx context.Context, firstURL, secondURL string, opts diffOptions) error {
+	var (
+		diffCh                chan diffMessage
+		firstBase, secondBase string
+	)
+	if isSnapshotFile(firstURL) || isSnapshotFile(secondURL) {
+		diffCh, firstBase, secondBase = snapshotDifference(ctx, firstURL, secondURL, opts.filter)
+	} else {
+		firstClient, secondClient := newDiffClients(firstURL, secondURL)
+		firstBase, secondBase = firstClient.GetURL().String(), secondClient.GetURL().String()
+		diffCh = objectDifference(ctx, firstClient, secondClient, true, false, opts.cmpTime, opts.filter)
 	}
 
+	var differences int64
+	defer startLiveStatus("diff", func(m *liveStatusMessage) {
+		m.Differences = atomic.LoadInt64(&differences)
+	})()
+
+	var (
+		summary diffSummaryMessage
+		errSeen bool
+	)
 	// Diff first and second urls.
-	for diffMsg := range objectDifference(ctx, firstClient, secondClient, true) {
+	for diffMsg := range diffCh {
 		if diffMsg.Error != nil {
 			errorIf(diffMsg.Error, "Unable to calculate objects difference.")
 			// Ignore error and proceed to next object.
+			errSeen = true
+			if opts.report != nil {
+				opts.report.addFailure(diffMsg.Error, diffMsg.FirstURL, diffMsg.SecondURL)
+			}
+			continue
+		}
+		if matchExcludeStorageClasses(opts.ignoreStorageClasses, diffMsg.firstContent, diffMsg.secondContent) {
 			continue
 		}
-		printMsg(diffMsg)
+		atomic.AddInt64(&differences, 1)
+		summary.add(diffMsg.Diff)
+		name := strings.TrimPrefix(diffMsg.SecondURL, secondBase)
+		if diffMsg.Diff == differInFirst {
+			name = strings.TrimPrefix(diffMsg.FirstURL, firstBase)
+		}
+		if opts.report != nil {
+			opts.report.add(strings.TrimPrefix(name, "/"), diffReportKind(diffMsg.Diff), contentSize(diffMsg.firstContent, diffMsg.secondContent))
+		}
+		switch {
+		case opts.summary:
+		case opts.nameOnly:
+			printMsg(diffNameMessage{Name: name, Diff: diffMsg.Diff})
+		default:
+			printMsg(diffMsg)
+		}
+	}
+	if opts.summary {
+		printMsg(summary)
 	}
 
+	if !opts.exitCode {
+		return nil
+	}
+	if errSeen {
+		return exitStatus(diffExitStatusTrouble)
+	}
+	if summary.Total > 0 {
+		return exitStatus(diffExitStatusDifferent)
+	}
 	return nil
 }
 
@@ -205,6 +475,14 @@ func mainDiff(cliCtx *cli.Context) error {
 	encKeyDB, err := getEncKeys(cliCtx)
 	fatalIf(err, "Unable to parse encryption keys.")
 
+	// Failures exit with 2 with --exit-code, as 1 reports differences.
+	if cliCtx.Bool("exit-code") {
+		console.Fatalln = func(data ...interface{}) {
+			console.Errorln(data...)
+			cli.OsExiter(diffExitStatusTrouble)
+		}
+	}
+
 	// check 'diff' cli arguments.
 	checkDiffSyntax(ctx, cliCtx, encKeyDB)
 
@@ -216,10 +494,51 @@ func mainDiff(cliCtx *cli.Context) error {
 	console.SetColor("DiffSize", color.New(color.FgYellow, color.Bold))
 	console.SetColor("DiffMetadata", color.New(color.FgYellow, color.Bold))
 	console.SetColor("DiffMMSourceMTime", color.New(color.FgYellow, color.Bold))
+	console.SetColor("DiffTime", color.New(color.FgYellow, color.Bold))
+	console.SetColor("DiffETag", color.New(color.FgYellow, color.Bold))
+	console.SetColor("DiffBoth", color.New(color.FgCyan))
+	console.SetColor("DiffConflict", color.New(color.FgRed, color.Bold))
 
 	URLs := cliCtx.Args()
 	firstURL := URLs.Get(0)
 	secondURL := URLs.Get(1)
+	if cliCtx.Bool("trailing-slash") {
+		secondURL = rsyncTargetURL(firstURL, secondURL)
+	}
+
+	cmpTime := diffTimeNone
+	switch {
+	case cliCtx.Bool("newer"):
+		cmpTime = diffTimeNewer
+	case cliCtx.Bool("older"):
+		cmpTime = diffTimeOlder
+	}
 
-	return doDiffMain(ctx, firstURL, secondURL)
+	opts := diffOptions{
+		cmpTime:              cmpTime,
+		ignoreStorageClasses: cliCtx.StringSlice("ignore-storage-class"),
+		filter:               newListFilter(cliCtx),
+		nameOnly:             cliCtx.Bool("name-only"),
+		summary:              cliCtx.Bool("summary"),
+		exitCode:             cliCtx.Bool("exit-code"),
+	}
+	base := cliCtx.String("base")
+	reportPath := cliCtx.String("report-html")
+	switch {
+	case reportPath != "" && base != "":
+		opts.report = newDiffBaseReport(firstURL, secondURL, base)
+	case reportPath != "":
+		opts.report = newDiffReport(firstURL, secondURL)
+	}
+
+	var e error
+	if base != "" {
+		e = doDiffBase(ctx, firstURL, secondURL, base, opts)
+	} else {
+		e = doDiffMain(ctx, firstURL, secondURL, opts)
+	}
+	if opts.report != nil {
+		fatalIf(opts.report.write(reportPath), "Unable to save the report.")
+	}
+	return e
 }
@@ -0,0 +1,97 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"os"
+)
+
+// isSnapshotFile - returns true when u, compared by diff, is a local file,
+// a snapshot of 'mc snapshot create', instead of a folder.
+func isSnapshotFile(u string) bool {
+	if _, _, aliasCfg := mustExpandAlias(u); aliasCfg != nil {
+		return false
+	}
+	st, e := os.Stat(u)
+	return e == nil && st.Mode().IsRegular()
+}
+
+// snapshotContents - lists the objects of the snapshot as the contents of
+// baseURL, in the listing order of the snapshot.
+func snapshotContents(ctx context.Context, s snapshot, baseURL string) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		for _, entry := range s.Entries {
+			content := &ClientContent{
+				URL:  ClientURL{Type: fileSystem, Path: baseURL + entry.Name, Separator: '/'},
+				Size: entry.Size,
+				Time: entry.LastModified,
+				ETag: entry.ETag,
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case contentCh <- content:
+			}
+		}
+	}()
+	return contentCh
+}
+
+// diffSideList - returns the recursive listing of a side of diff, the
+// content of a snapshot file or of a folder, and its base URL.
+func diffSideList(ctx context.Context, u string, filter listFilter) (string, <-chan *ClientContent) {
+	if isSnapshotFile(u) {
+		s, err := loadSnapshot(u)
+		fatalIf(err.Trace(u), "Unable to load the snapshot `%s`.", u)
+		baseURL := u + "/"
+		return baseURL, filterList(ctx, snapshotContents(ctx, s, baseURL), baseURL, filter)
+	}
+	clnt := newDiffClient(u)
+	baseURL := clnt.GetURL().String()
+	return baseURL, filterList(ctx, clnt.List(ctx, ListOptions{Recursive: true, ShowDir: DirNone, Parallel: scanListParallel}), baseURL, filter)
+}
+
+// snapshotDifference - finds the difference between two folders, one or
+// both saved to snapshots. Snapshots have no metadata, objects of the same
+// size differ in ETag when both are known.
+func snapshotDifference(ctx context.Context, firstURL, secondURL string, filter listFilter) (diffCh chan diffMessage, firstBase, secondBase string) {
+	// Snapshots hold objects named like a prefix as they were listed.
+	filter.nameConflict = nameConflictAllow
+
+	firstBase, firstCh := diffSideList(ctx, firstURL, filter)
+	secondBase, secondCh := diffSideList(ctx, secondURL, filter)
+
+	diffCh = make(chan diffMessage, 10000)
+	go func() {
+		defer close(diffCh)
+		for diffMsg := range difference(firstBase, firstCh, secondBase, secondCh, false, true, diffTimeNone) {
+			if diffMsg.Error == nil && diffMsg.Diff == differInNone {
+				first, second := diffMsg.firstContent, diffMsg.secondContent
+				if first.ETag == "" || second.ETag == "" || first.ETag == second.ETag {
+					continue
+				}
+				diffMsg.Diff = differInETag
+			}
+			diffCh <- diffMsg
+		}
+	}()
+	return diffCh, firstBase, secondBase
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSnapshotDifference(t *testing.T) {
+	useTestMcConfig(t)
+	dir := t.TempDir()
+	modTime := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	save := func(name string, entries ...snapshotEntry) string {
+		file := filepath.Join(dir, name)
+		err := saveSnapshot(file, snapshot{
+			snapshotHeader: snapshotHeader{Version: snapshotVersion, Created: modTime, URL: "s3/mybucket"},
+			Entries:        entries,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+	first := save("april.json.gz",
+		snapshotEntry{Name: "a", Size: 1, LastModified: modTime, ETag: "a1"},
+		snapshotEntry{Name: "b", Size: 1, LastModified: modTime, ETag: "b1"},
+		snapshotEntry{Name: "c", Size: 1, LastModified: modTime, ETag: "c1"},
+		snapshotEntry{Name: "d", Size: 1, LastModified: modTime, ETag: "d1"},
+	)
+	second := save("may.json",
+		snapshotEntry{Name: "a", Size: 1, LastModified: modTime, ETag: "a1"},
+		snapshotEntry{Name: "b", Size: 2, LastModified: modTime, ETag: "b2"},
+		snapshotEntry{Name: "c", Size: 1, LastModified: modTime, ETag: "c2"},
+		snapshotEntry{Name: "e", Size: 1, LastModified: modTime},
+	)
+	if !isSnapshotFile(first) || isSnapshotFile(dir) {
+		t.Fatal("expected the snapshots to be files, unlike folders")
+	}
+
+	diffCh, firstBase, secondBase := snapshotDifference(context.Background(), first, second, listFilter{})
+	expected := map[string]differType{"b": differInSize, "c": differInETag, "d": differInFirst, "e": differInSecond}
+	for diffMsg := range diffCh {
+		if diffMsg.Error != nil {
+			t.Fatal(diffMsg.Error)
+		}
+		name := relativeName(diffMsg.SecondURL, secondBase, '/')
+		if diffMsg.Diff == differInFirst {
+			name = relativeName(diffMsg.FirstURL, firstBase, '/')
+		}
+		if diff, ok := expected[name]; !ok || diff != diffMsg.Diff {
+			t.Errorf("unexpected difference %v of `%s`", diffMsg.Diff, name)
+		}
+		delete(expected, name)
+	}
+	if len(expected) != 0 {
+		t.Errorf("missing differences %v", expected)
+	}
+}
@@ -19,6 +19,10 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -42,6 +46,17 @@ const (
 	differInFirst                    // only in source (FIRST)
 	differInSecond                   // only in target (SECOND)
 	differInAASourceMTime            // differs in active-active source modtime
+	differInTime                     // differs in modification time
+	differInETag                     // differs in ETag, compared with a snapshot
+)
+
+// diffTimeMode compares objects by their modification time instead of their size.
+type diffTimeMode int
+
+const (
+	diffTimeNone  diffTimeMode = iota // compare size and metadata
+	diffTimeNewer                     // differs if the source is newer than the target
+	diffTimeOlder                     // differs if the source is older than the target
 )
 
 func (d differType) String() string {
@@ -54,6 +69,10 @@ func (d differType) String() string {
 		return "metadata"
 	case differInAASourceMTime:
 		return "mm-source-mtime"
+	case differInTime:
+		return "time"
+	case differInETag:
+		return "etag"
 	case differInType:
 		return "type"
 	case differInFirst:
@@ -161,14 +180,183 @@ func metadataEqual(m1, m2 map[string]string) bool {
 	return true
 }
 
-func objectDifference(ctx context.Context, sourceClnt, targetClnt Client, isMetadata bool) (diffCh chan diffMessage) {
+// timeDiffers returns true if src and dst modification times differ in the requested direction.
+func timeDiffers(src, dst *ClientContent, mode diffTimeMode) bool {
+	switch mode {
+	case diffTimeNewer:
+		return src.Time.After(dst.Time)
+	case diffTimeOlder:
+		return src.Time.Before(dst.Time)
+	}
+	return false
+}
+
+// contentDifference - compares a source and a target object of the same name.
+func contentDifference(srcCtnt, tgtCtnt *ClientContent, cmpMetadata bool, cmpTime diffTimeMode) differType {
+	srcType, tgtType := srcCtnt.Type, tgtCtnt.Type
+	switch {
+	case srcType.IsRegular() && !tgtType.IsRegular() ||
+		!srcType.IsRegular() && tgtType.IsRegular():
+		// Type differs. Source is never a directory.
+		return differInType
+	case cmpTime != diffTimeNone:
+		// Only modification times matter, the target is up-to-date otherwise.
+		if timeDiffers(srcCtnt, tgtCtnt, cmpTime) {
+			return differInTime
+		}
+	case srcCtnt.Size != tgtCtnt.Size:
+		// Regular files differing in size.
+		return differInSize
+	case activeActiveModTimeUpdated(srcCtnt, tgtCtnt):
+		return differInAASourceMTime
+	case cmpMetadata &&
+		!metadataEqual(srcCtnt.UserMetadata, tgtCtnt.UserMetadata) &&
+		!metadataEqual(srcCtnt.Metadata, tgtCtnt.Metadata):
+		// Regular files user requesting additional metadata to same file.
+		return differInMetadata
+	}
+	return differInNone
+}
+
+func objectDifference(ctx context.Context, sourceClnt, targetClnt Client, isMetadata, returnSimilar bool, cmpTime diffTimeMode, filter listFilter) (diffCh chan diffMessage) {
+	// Filtered objects are dropped while listing, they are not compared.
+	// Source objects named like a prefix are resolved when the target can't
+	// hold both.
 	sourceURL := sourceClnt.GetURL().String()
-	sourceCh := sourceClnt.List(ctx, ListOptions{Recursive: true, WithMetadata: isMetadata, ShowDir: DirNone})
+	targetURL := targetClnt.GetURL().String()
+	sourceFilter, targetFilter := filter.forTarget(targetClnt.GetURL().Type), filter
+	targetFilter.nameConflict = nameConflictAllow
+
+	sourceCh := filterList(ctx, sourceClnt.List(ctx, ListOptions{Recursive: true, WithMetadata: isMetadata, ShowDir: DirNone, Parallel: scanListParallel}), sourceURL, sourceFilter)
+	targetCh := filterList(ctx, targetClnt.List(ctx, ListOptions{Recursive: true, WithMetadata: isMetadata, ShowDir: DirNone, Parallel: scanListParallel}), targetURL, targetFilter)
+	sourceCh = matchDirMarkers(ctx, sourceCh, sourceURL, targetClnt)
+	targetCh = matchDirMarkers(ctx, targetCh, targetURL, sourceClnt)
 
+	return difference(sourceURL, sourceCh, targetURL, targetCh, isMetadata, returnSimilar, cmpTime)
+}
+
+// matchDirMarkers - drops the directory markers of the object storage
+// listing of baseURL when the filesystem folder other, whose listings hold
+// no directories, has a directory of the same name. They would otherwise
+// be reported, and copied, again on every comparison.
+func matchDirMarkers(ctx context.Context, listCh <-chan *ClientContent, baseURL string, other Client) <-chan *ClientContent {
+	otherURL := other.GetURL()
+	if newClientURL(baseURL).Type != objectStorage || otherURL.Type != fileSystem {
+		return listCh
+	}
+	matchedCh := make(chan *ClientContent)
+	go func() {
+		defer close(matchedCh)
+		for content := range listCh {
+			if content.Err == nil && isDirMarker(content, objectStorage) {
+				rel := strings.TrimPrefix(content.URL.String(), baseURL)
+				if st, e := os.Stat(filepath.Join(otherURL.Path, filepath.FromSlash(rel))); e == nil && st.IsDir() {
+					continue
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case matchedCh <- content:
+			}
+		}
+	}()
+	return matchedCh
+}
+
+// relativeName - returns the slash separated, NFC normalized name of the
+// listed URL u relative to the listed folder base.
+func relativeName(u, base string, separator rune) string {
+	rel := strings.TrimPrefix(strings.TrimPrefix(u, base), string(separator))
+	return norm.NFC.String(strings.ReplaceAll(rel, string(separator), "/"))
+}
+
+// renameFn - returns the slash separated target name of a source object
+// from its name relative to the source URL.
+type renameFn func(rel string, content *ClientContent) (string, *probe.Error)
+
+// renamedObjectDifference - finds the difference between the source objects
+// and the target objects they are renamed to. Renamed names don't sort like
+// the source names, the target is listed upfront and kept in memory.
+func renamedObjectDifference(ctx context.Context, sourceClnt, targetClnt Client, rename renameFn, isMetadata, returnSimilar bool, cmpTime diffTimeMode, filter listFilter) (diffCh chan diffMessage) {
+	diffCh = make(chan diffMessage, 10000)
+
+	sourceURL := sourceClnt.GetURL().String()
 	targetURL := targetClnt.GetURL().String()
-	targetCh := targetClnt.List(ctx, ListOptions{Recursive: true, WithMetadata: isMetadata, ShowDir: DirNone})
+	// Renamed names don't sort like the source names, name conflicts are
+	// not resolved.
+	filter.nameConflict = nameConflictAllow
 
-	return difference(sourceURL, sourceCh, targetURL, targetCh, isMetadata, false)
+	go func() {
+		defer close(diffCh)
+
+		targets := make(map[string]*ClientContent)
+		for tgtCtnt := range filterList(ctx, targetClnt.List(ctx, ListOptions{Recursive: true, WithMetadata: isMetadata, ShowDir: DirNone, Parallel: scanListParallel}), targetURL, filter) {
+			if tgtCtnt.Err != nil {
+				diffCh <- diffMessage{Error: tgtCtnt.Err.Trace(sourceURL, targetURL)}
+				return
+			}
+			targets[relativeName(tgtCtnt.URL.String(), targetURL, tgtCtnt.URL.Separator)] = tgtCtnt
+		}
+
+		// Target names claimed by the source objects renamed so far.
+		renamed := make(map[string]string)
+		for srcCtnt := range filterList(ctx, sourceClnt.List(ctx, ListOptions{Recursive: true, WithMetadata: isMetadata, ShowDir: DirNone, Parallel: scanListParallel}), sourceURL, filter) {
+			if srcCtnt.Err != nil {
+				diffCh <- diffMessage{Error: srcCtnt.Err.Trace(sourceURL, targetURL)}
+				return
+			}
+			rel, err := rename(relativeName(srcCtnt.URL.String(), sourceURL, srcCtnt.URL.Separator), srcCtnt)
+			if err != nil {
+				diffCh <- diffMessage{Error: err.Trace(srcCtnt.URL.String())}
+				continue
+			}
+			rel = norm.NFC.String(rel)
+			if other, ok := renamed[rel]; ok {
+				diffCh <- diffMessage{Error: probe.NewError(fmt.Errorf("`%s` and `%s` are both renamed to `%s`", other, srcCtnt.URL.String(), rel))}
+				continue
+			}
+			renamed[rel] = srcCtnt.URL.String()
+
+			secondURL := urlJoinPath(targetURL, rel)
+			tgtCtnt, ok := targets[rel]
+			if !ok {
+				diffCh <- diffMessage{
+					FirstURL:     srcCtnt.URL.String(),
+					SecondURL:    secondURL,
+					Diff:         differInFirst,
+					firstContent: srcCtnt,
+				}
+				continue
+			}
+			delete(targets, rel)
+			if diff := contentDifference(srcCtnt, tgtCtnt, isMetadata, cmpTime); diff != differInNone || returnSimilar {
+				diffCh <- diffMessage{
+					FirstURL:      srcCtnt.URL.String(),
+					SecondURL:     tgtCtnt.URL.String(),
+					Diff:          diff,
+					firstContent:  srcCtnt,
+					secondContent: tgtCtnt,
+				}
+			}
+		}
+
+		// Whatever is left on the target has no source.
+		rels := make([]string, 0, len(targets))
+		for rel := range targets {
+			rels = append(rels, rel)
+		}
+		sort.Strings(rels)
+		for _, rel := range rels {
+			diffCh <- diffMessage{
+				SecondURL:     targets[rel].URL.String(),
+				Diff:          differInSecond,
+				secondContent: targets[rel],
+			}
+		}
+	}()
+
+	return diffCh
 }
 
 func bucketDifference(ctx context.Context, sourceClnt, targetClnt Client) (diffCh chan diffMessage) {
@@ -215,11 +403,11 @@ func bucketDifference(ctx context.Context, sourceClnt, targetClnt Client) (diffC
 		}
 	}()
 
-	return difference(sourceURL, sourceCh, targetURL, targetCh, false, false)
+	return difference(sourceURL, sourceCh, targetURL, targetCh, false, false, diffTimeNone)
 }
 
 func differenceInternal(sourceURL string, srcCh <-chan *ClientContent, targetURL string, tgtCh <-chan *ClientContent,
-	cmpMetadata, returnSimilar bool, diffCh chan<- diffMessage,
+	cmpMetadata, returnSimilar bool, cmpTime diffTimeMode, diffCh chan<- diffMessage,
 ) *probe.Error {
 	// Pop first entries from the source and targets
 	srcCtnt, srcOk := <-srcCh
@@ -237,6 +425,18 @@ func differenceInternal(sourceURL string, srcCh <-chan *ClientContent, targetURL
 		}
 
 		if !srcEOF && srcCtnt.Err != nil {
+			if _, ok := srcCtnt.Err.ToGoError().(ObjectNameConflict); ok {
+				// The target can't hold both the source object and
+				// the source objects under its prefix.
+				diffCh <- diffMessage{
+					FirstURL:     srcCtnt.URL.String(),
+					SecondURL:    urlJoinPath(targetURL, strings.TrimPrefix(srcCtnt.URL.String(), sourceURL)),
+					Diff:         differInType,
+					firstContent: srcCtnt,
+				}
+				srcCtnt, srcOk = <-srcCh
+				continue
+			}
 			return srcCtnt.Err.Trace(sourceURL, targetURL)
 		}
 
@@ -301,57 +501,13 @@ func differenceInternal(sourceURL string, srcCh <-chan *ClientContent, targetURL
 			continue
 		}
 		if normalizedExpected == normalizedCurrent {
-			srcType, tgtType := srcCtnt.Type, tgtCtnt.Type
-			srcSize, tgtSize := srcCtnt.Size, tgtCtnt.Size
-			if srcType.IsRegular() && !tgtType.IsRegular() ||
-				!srcType.IsRegular() && tgtType.IsRegular() {
-				// Type differs. Source is never a directory.
-				diffCh <- diffMessage{
-					FirstURL:      srcCtnt.URL.String(),
-					SecondURL:     tgtCtnt.URL.String(),
-					Diff:          differInType,
-					firstContent:  srcCtnt,
-					secondContent: tgtCtnt,
-				}
-				continue
-			}
-			if srcSize != tgtSize {
-				// Regular files differing in size.
-				diffCh <- diffMessage{
-					FirstURL:      srcCtnt.URL.String(),
-					SecondURL:     tgtCtnt.URL.String(),
-					Diff:          differInSize,
-					firstContent:  srcCtnt,
-					secondContent: tgtCtnt,
-				}
-			} else if activeActiveModTimeUpdated(srcCtnt, tgtCtnt) {
-				diffCh <- diffMessage{
-					FirstURL:      srcCtnt.URL.String(),
-					SecondURL:     tgtCtnt.URL.String(),
-					Diff:          differInAASourceMTime,
-					firstContent:  srcCtnt,
-					secondContent: tgtCtnt,
-				}
-			} else if cmpMetadata &&
-				!metadataEqual(srcCtnt.UserMetadata, tgtCtnt.UserMetadata) &&
-				!metadataEqual(srcCtnt.Metadata, tgtCtnt.Metadata) {
-
-				// Regular files user requesting additional metadata to same file.
-				diffCh <- diffMessage{
-					FirstURL:      srcCtnt.URL.String(),
-					SecondURL:     tgtCtnt.URL.String(),
-					Diff:          differInMetadata,
-					firstContent:  srcCtnt,
-					secondContent: tgtCtnt,
-				}
-			}
-
-			// No differ
-			if returnSimilar {
+			diff := contentDifference(srcCtnt, tgtCtnt, cmpMetadata, cmpTime)
+			// Similar objects are only reported when requested.
+			if diff != differInNone || returnSimilar {
 				diffCh <- diffMessage{
 					FirstURL:      srcCtnt.URL.String(),
 					SecondURL:     tgtCtnt.URL.String(),
-					Diff:          differInNone,
+					Diff:          diff,
 					firstContent:  srcCtnt,
 					secondContent: tgtCtnt,
 				}
@@ -375,13 +531,13 @@ func differenceInternal(sourceURL string, srcCh <-chan *ClientContent, targetURL
 
 // objectDifference function finds the difference between all objects
 // recursively in sorted order from source and target.
-func difference(sourceURL string, sourceCh <-chan *ClientContent, targetURL string, targetCh <-chan *ClientContent, cmpMetadata, returnSimilar bool) (diffCh chan diffMessage) {
+func difference(sourceURL string, sourceCh <-chan *ClientContent, targetURL string, targetCh <-chan *ClientContent, cmpMetadata, returnSimilar bool, cmpTime diffTimeMode) (diffCh chan diffMessage) {
 	diffCh = make(chan diffMessage, 10000)
 
 	go func() {
 		defer close(diffCh)
 
-		err := differenceInternal(sourceURL, sourceCh, targetURL, targetCh, cmpMetadata, returnSimilar, diffCh)
+		err := differenceInternal(sourceURL, sourceCh, targetURL, targetCh, cmpMetadata, returnSimilar, cmpTime, diffCh)
 		if err != nil {
 			// handle this specifically for filesystem related errors.
 			switch v := err.ToGoError().(type) {
@@ -18,7 +18,16 @@
 package cmd
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
 )
 
 var testCases = []struct {
@@ -52,6 +61,11 @@ var testCases = []struct {
 	{[]string{"*.txt"}, "/file/abc/bcd/def.txt", true, fileSystem},
 	{[]string{".*"}, ".sys", true, fileSystem},
 	{[]string{"*."}, ".sys.", true, fileSystem},
+	{[]string{".git/"}, ".git/config", true, objectStorage},
+	{[]string{".git/"}, "src/.git/config", true, objectStorage},
+	{[]string{".git/"}, "src/.gitignore", false, objectStorage},
+	{[]string{"node_modules/"}, "/web/node_modules/a/index.js", true, fileSystem},
+	{[]string{"node_modules/"}, "/web/my_node_modules/index.js", false, fileSystem},
 }
 
 func TestExcludeOptions(t *testing.T) {
@@ -61,3 +75,315 @@ func TestExcludeOptions(t *testing.T) {
 		}
 	}
 }
+
+func TestDifferenceTime(t *testing.T) {
+	now := time.Now()
+	content := func(urlStr string, size int64, modTime time.Time) *ClientContent {
+		return &ClientContent{URL: *newClientURL(urlStr), Size: size, Time: modTime, Type: 0o644}
+	}
+	testCases := []struct {
+		src, tgt *ClientContent
+		cmpTime  diffTimeMode
+		expected differType
+	}{
+		// source newer, same size
+		{content("/src/a", 1, now), content("/tgt/a", 1, now.Add(-time.Hour)), diffTimeNewer, differInTime},
+		// source newer but target considered up-to-date for --older
+		{content("/src/a", 1, now), content("/tgt/a", 1, now.Add(-time.Hour)), diffTimeOlder, differInNone},
+		// target newer, sizes differ: --newer ignores the size
+		{content("/src/a", 1, now.Add(-time.Hour)), content("/tgt/a", 2, now), diffTimeNewer, differInNone},
+		{content("/src/a", 1, now.Add(-time.Hour)), content("/tgt/a", 2, now), diffTimeOlder, differInTime},
+		{content("/src/a", 1, now.Add(-time.Hour)), content("/tgt/a", 2, now), diffTimeNone, differInSize},
+	}
+	for i, testCase := range testCases {
+		srcCh := make(chan *ClientContent, 1)
+		tgtCh := make(chan *ClientContent, 1)
+		srcCh <- testCase.src
+		tgtCh <- testCase.tgt
+		close(srcCh)
+		close(tgtCh)
+
+		diffCh := make(chan diffMessage, 2)
+		if err := differenceInternal("/src/", srcCh, "/tgt/", tgtCh, false, false, testCase.cmpTime, diffCh); err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		close(diffCh)
+		got := differInNone
+		for d := range diffCh {
+			got = d.Diff
+		}
+		if got != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestFilterList(t *testing.T) {
+	names := []string{"a.txt", "a.tmp", ".git/", ".git/config", "src/", "src/main.go", "src/node_modules/x.js"}
+	listCh := make(chan *ClientContent, len(names))
+	for _, name := range names {
+		typ := os.FileMode(0)
+		if strings.HasSuffix(name, "/") {
+			typ = os.ModeDir
+		}
+		listCh <- &ClientContent{URL: *newClientURL("play/bucket/" + name), Type: typ}
+	}
+	close(listCh)
+
+	f := listFilter{exclude: []string{".git/", "node_modules/", "*.tmp"}, include: []string{"*.go", "*.txt"}}
+	var got []string
+	for content := range filterList(context.Background(), listCh, "play/bucket/", f) {
+		got = append(got, strings.TrimPrefix(content.URL.String(), "play/bucket/"))
+	}
+	if want := []string{"a.txt", "src/", "src/main.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestMatchExcludeStorageClasses(t *testing.T) {
+	glacier := &ClientContent{StorageClass: "GLACIER"}
+	standard := &ClientContent{StorageClass: "STANDARD"}
+	testCases := []struct {
+		storageClasses []string
+		contents       []*ClientContent
+		match          bool
+	}{
+		{nil, []*ClientContent{glacier}, false},
+		{[]string{"GLACIER"}, []*ClientContent{glacier, nil}, true},
+		{[]string{"glacier"}, []*ClientContent{nil, glacier}, true},
+		{[]string{"GLACIER"}, []*ClientContent{standard, standard}, false},
+		{[]string{"GLACIER"}, []*ClientContent{standard, glacier}, true},
+		{[]string{"GLACIER"}, []*ClientContent{{}, nil}, false},
+	}
+	for i, testCase := range testCases {
+		if match := matchExcludeStorageClasses(testCase.storageClasses, testCase.contents...); match != testCase.match {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.match, match)
+		}
+	}
+}
+
+func TestRenamedObjectDifference(t *testing.T) {
+	root := t.TempDir()
+	modTime := time.Now().Add(-time.Hour)
+	for name, data := range map[string]string{
+		"src/A.TXT":   "a",
+		"src/dir/B.C": "bb",
+		"src/D":       "d",
+		"tgt/a.txt":   "a",
+		"tgt/dir/b.c": "b",
+		"tgt/extra":   "e",
+	} {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if e := os.MkdirAll(filepath.Dir(p), 0o755); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(p, []byte(data), 0o644); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.Chtimes(p, modTime, modTime); e != nil {
+			t.Fatal(e)
+		}
+	}
+	sourceClnt, err := fsNew(filepath.Join(root, "src") + string(filepath.Separator))
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetClnt, err := fsNew(filepath.Join(root, "tgt") + string(filepath.Separator))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rename := func(rel string, _ *ClientContent) (string, *probe.Error) {
+		return strings.ToLower(rel), nil
+	}
+
+	got := make(map[string]differType)
+	for d := range renamedObjectDifference(context.Background(), sourceClnt, targetClnt, rename, false, false, diffTimeNone, listFilter{}) {
+		if d.Error != nil {
+			t.Fatal(d.Error)
+		}
+		key := filepath.ToSlash(strings.TrimPrefix(d.SecondURL, filepath.Join(root, "tgt")))
+		if d.Diff == differInSecond {
+			key = "-" + key
+		}
+		got[key] = d.Diff
+	}
+	want := map[string]differType{
+		"/dir/b.c": differInSize,
+		"/d":       differInFirst,
+		"-/extra":  differInSecond,
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: expected %s, got %s", k, v, got[k])
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// Two sources renamed to the same target are reported.
+	collide := func(rel string, _ *ClientContent) (string, *probe.Error) {
+		return "same", nil
+	}
+	var collisions int
+	for d := range renamedObjectDifference(context.Background(), sourceClnt, targetClnt, collide, false, false, diffTimeNone, listFilter{}) {
+		if d.Error != nil {
+			collisions++
+		}
+	}
+	if collisions != 2 {
+		t.Errorf("expected 2 collisions, got %d", collisions)
+	}
+}
+
+func TestResolveNameConflicts(t *testing.T) {
+	names := []string{"a", "a-b", "a/x", "a/y/z", "b", "c", "c.txt", "c/d", "c/d/e", "d/", "dd"}
+	list := func() <-chan *ClientContent {
+		listCh := make(chan *ClientContent, len(names))
+		for _, name := range names {
+			typ := os.FileMode(0)
+			if strings.HasSuffix(name, "/") {
+				typ = os.ModeDir
+			}
+			listCh <- &ClientContent{URL: *newClientURL("https://play.min.io/bucket/" + name), Type: typ}
+		}
+		close(listCh)
+		return listCh
+	}
+	testCases := []struct {
+		mode     nameConflictMode
+		expected []string
+	}{
+		{nameConflictAllow, names},
+		{nameConflictReport, []string{"!a", "a-b", "a/x", "a/y/z", "b", "!c", "c.txt", "!c/d", "c/d/e", "d/", "dd"}},
+		{nameConflictObject, []string{"a", "a-b", "b", "c", "c.txt", "d/", "dd"}},
+		{nameConflictPrefix, []string{"a-b", "a/x", "a/y/z", "b", "c.txt", "c/d/e", "d/", "dd"}},
+	}
+	for i, testCase := range testCases {
+		var got []string
+		for content := range resolveNameConflicts(context.Background(), list(), "https://play.min.io/bucket/", testCase.mode) {
+			name := strings.TrimPrefix(content.URL.String(), "https://play.min.io/bucket/")
+			if content.Err != nil {
+				if _, ok := content.Err.ToGoError().(ObjectNameConflict); !ok {
+					t.Fatalf("Test %d: unexpected error %v", i+1, content.Err)
+				}
+				name = "!" + name
+			}
+			got = append(got, name)
+		}
+		if !reflect.DeepEqual(got, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestNameConflictDifference(t *testing.T) {
+	srcCh := make(chan *ClientContent, 3)
+	tgtCh := make(chan *ClientContent, 1)
+	for _, name := range []string{"a", "a/x"} {
+		srcCh <- &ClientContent{URL: *newClientURL("https://play.min.io/bucket/" + name), Size: 1}
+	}
+	close(srcCh)
+	tgtCh <- &ClientContent{URL: *newClientURL("/tgt/a/x"), Size: 1}
+	close(tgtCh)
+
+	diffCh := make(chan diffMessage, 2)
+	sourceCh := resolveNameConflicts(context.Background(), srcCh, "https://play.min.io/bucket/", nameConflictReport)
+	if err := differenceInternal("https://play.min.io/bucket/", sourceCh, "/tgt/", tgtCh, false, false, diffTimeNone, diffCh); err != nil {
+		t.Fatal(err)
+	}
+	close(diffCh)
+	var got []differType
+	for d := range diffCh {
+		got = append(got, d.Diff)
+	}
+	if want := []differType{differInType}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestDirMarkers(t *testing.T) {
+	target := t.TempDir()
+	if e := os.MkdirAll(filepath.Join(target, "photos"), 0o755); e != nil {
+		t.Fatal(e)
+	}
+	targetClnt, err := fsNew(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const sourceURL = "https://play.min.io/bucket/"
+	list := func() <-chan *ClientContent {
+		listCh := make(chan *ClientContent, 4)
+		for _, name := range []string{"empty/", "photos/", "photos/a.jpg", "slash/"} {
+			content := &ClientContent{URL: *newClientURL(sourceURL + name), Type: os.FileMode(0o664), Size: 1}
+			if strings.HasSuffix(name, "/") {
+				content.Type, content.Size = os.ModeDir, 0
+			}
+			listCh <- content
+		}
+		close(listCh)
+		return listCh
+	}
+	names := func(listCh <-chan *ClientContent) (got []string) {
+		for content := range listCh {
+			got = append(got, strings.TrimPrefix(content.URL.String(), sourceURL))
+		}
+		return got
+	}
+
+	// The marker of the existing directory is matched, the others are missing.
+	if got, want := names(matchDirMarkers(context.Background(), list(), sourceURL, targetClnt)), []string{"empty/", "photos/a.jpg", "slash/"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got, want := names(filterList(context.Background(), list(), sourceURL, listFilter{skipDirMarkers: true})), []string{"photos/a.jpg"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("--skip-dir-markers: expected %v, got %v", want, got)
+	}
+}
+
+func TestDiffExitCode(t *testing.T) {
+	useTestMcConfig(t)
+	root := t.TempDir()
+	first, second := filepath.Join(root, "first"), filepath.Join(root, "second")
+	for p, data := range map[string]string{
+		filepath.Join(first, "a"):      "a",
+		filepath.Join(first, "same"):   "same",
+		filepath.Join(second, "same"):  "same",
+		filepath.Join(second, "b"):     "b",
+		filepath.Join(first, "sub/c"):  "c",
+		filepath.Join(second, "sub/c"): "cc",
+	} {
+		if e := os.MkdirAll(filepath.Dir(p), 0o755); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(p, []byte(data), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	exitCode := func(e error) int {
+		if e == nil {
+			return 0
+		}
+		return e.(cli.ExitCoder).ExitCode()
+	}
+	opts := diffOptions{summary: true, exitCode: true}
+	if code := exitCode(doDiffMain(context.Background(), first, second, opts)); code != diffExitStatusDifferent {
+		t.Errorf("expected exit status %d with differences, got %d", diffExitStatusDifferent, code)
+	}
+	if code := exitCode(doDiffMain(context.Background(), first, first, opts)); code != 0 {
+		t.Errorf("expected exit status 0 without differences, got %d", code)
+	}
+	opts.exitCode = false
+	if code := exitCode(doDiffMain(context.Background(), first, second, opts)); code != 0 {
+		t.Errorf("expected exit status 0 without --exit-code, got %d", code)
+	}
+
+	var summary diffSummaryMessage
+	for _, d := range []differType{differInFirst, differInSecond, differInSize, differInTime} {
+		summary.add(d)
+	}
+	if want := (diffSummaryMessage{OnlyInSource: 1, OnlyInTarget: 1, Differing: 2, Total: 4}); summary != want {
+		t.Errorf("expected summary %+v, got %+v", want, summary)
+	}
+}
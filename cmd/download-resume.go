@@ -0,0 +1,113 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// downloadResumeOffset - returns the offset the download of the object
+// content resumes from, to the partial file at path. The parts of the
+// object the file holds are verified with their checksums, otherwise the
+// file must have been written after the object was modified. Zero when
+// the download restarts.
+func downloadResumeOffset(ctx context.Context, clnt Client, content *ClientContent, path string, opts GetOptions) int64 {
+	file, e := os.Open(path)
+	if e != nil {
+		return 0
+	}
+	defer file.Close()
+	st, e := file.Stat()
+	if e != nil || !st.Mode().IsRegular() || st.Size() == 0 || st.Size() >= content.Size {
+		return 0
+	}
+
+	var offset int64
+	for _, part := range objectPartChecksums(ctx, clnt, content.Size, opts) {
+		// The rest of a part is downloaded again with it.
+		if part.start+part.length > st.Size() {
+			break
+		}
+		matching, e := fileMatchingParts(file, []partChecksum{part})
+		if e != nil || !matching[0] {
+			return offset
+		}
+		offset += part.length
+	}
+	if offset > 0 {
+		return offset
+	}
+	if content.Time.After(st.ModTime()) {
+		return 0
+	}
+	return st.Size()
+}
+
+// getResumedSourceStream - returns a reader of the object at urlStr from
+// the offset its download to the partial file at path resumes from, with
+// the content of the whole object.
+func getResumedSourceStream(ctx context.Context, alias, urlStr, path string, opts getSourceOpts) (io.ReadCloser, *ClientContent, int64, *probe.Error) {
+	clnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return nil, nil, 0, err.Trace(alias, urlStr)
+	}
+	content, err := clnt.Stat(ctx, StatOptions{sse: opts.SSE, versionID: opts.VersionID, preserve: opts.Preserve})
+	if err != nil {
+		return nil, nil, 0, err.Trace(alias, urlStr)
+	}
+	// The rest is read from the version the partial file is compared with.
+	opts.VersionID = content.VersionID
+
+	offset := downloadResumeOffset(ctx, clnt, content, path, opts.GetOptions)
+	if offset == 0 {
+		reader, content, err := getSourceStream(ctx, alias, urlStr, opts)
+		return reader, content, 0, err
+	}
+	opts.RangeStart = offset
+	reader, rest, err := getSourceStream(ctx, alias, urlStr, opts)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	// The size of the content of a ranged read is the size of the range.
+	whole := *rest
+	whole.Size = content.Size
+	return reader, &whole, offset, nil
+}
+
+// addProgress - advances progress by n bytes not transferred, e.g. the
+// bytes of a download resumed.
+func addProgress(progress io.Reader, n int64) {
+	switch p := progress.(type) {
+	case *progressBar:
+		p.ProgressBar.Add64(n)
+	case *accounter:
+		p.Add(n)
+	}
+}
+
+// isResumableDownload - reports whether the download continues from the
+// part file of a previous attempt, for plain downloads of whole objects.
+// Resumed downloads would only scan their rest.
+func isResumableDownload(uploadOpts uploadSourceToTargetURLOpts, sourceURL, targetURL ClientURL, sourcePath string) bool {
+	return uploadOpts.resume && !uploadOpts.ranged && uploadOpts.scanner == nil && !uploadOpts.isZip &&
+		sourceURL.Type == objectStorage && targetURL.Type == fileSystem && clientEncryptionKey(sourcePath) == nil
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDownloadResumeOffset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "object"+partSuffix)
+	if e := os.WriteFile(path, make([]byte, 100), 0o644); e != nil {
+		t.Fatal(e)
+	}
+	written := time.Now().Add(-time.Hour)
+	if e := os.Chtimes(path, written, written); e != nil {
+		t.Fatal(e)
+	}
+
+	testCases := []struct {
+		size     int64
+		modTime  time.Time
+		expected int64
+	}{
+		// Object unchanged since the partial file was written.
+		{size: 1000, modTime: written.Add(-time.Hour), expected: 100},
+		// Object modified after the partial file was written.
+		{size: 1000, modTime: written.Add(time.Minute)},
+		// Partial file not smaller than the object.
+		{size: 100, modTime: written.Add(-time.Hour)},
+	}
+	for i, tc := range testCases {
+		content := &ClientContent{Size: tc.size, Time: tc.modTime}
+		if got := downloadResumeOffset(globalContext, &fakeRangeClient{}, content, path, GetOptions{}); got != tc.expected {
+			t.Fatalf("Test %d: expected offset %d, got %d", i+1, tc.expected, got)
+		}
+	}
+	if got := downloadResumeOffset(globalContext, &fakeRangeClient{}, &ClientContent{Size: 1000}, path+".missing", GetOptions{}); got != 0 {
+		t.Fatalf("expected no offset without partial file, got %d", got)
+	}
+}
+
+func TestOpenPartFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "object"+partSuffix)
+	if e := os.WriteFile(path, []byte("hello world"), 0o644); e != nil {
+		t.Fatal(e)
+	}
+	file, e := openPartFile(path, 6)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if _, e = file.Write([]byte("minio")); e != nil {
+		t.Fatal(e)
+	}
+	file.Close()
+	if data, _ := os.ReadFile(path); string(data) != "hello minio" {
+		t.Fatalf("expected `hello minio`, got `%s`", data)
+	}
+
+	file, e = openPartFile(path, 0)
+	if e != nil {
+		t.Fatal(e)
+	}
+	file.Close()
+	if st, _ := os.Stat(path); st.Size() != 0 {
+		t.Fatalf("expected an empty file, got %d bytes", st.Size())
+	}
+}
+
+func TestIsResumableDownload(t *testing.T) {
+	useTestMcConfig(t)
+	remote, local := ClientURL{Type: objectStorage}, ClientURL{Type: fileSystem}
+	testCases := []struct {
+		opts           uploadSourceToTargetURLOpts
+		source, target ClientURL
+		resumable      bool
+	}{
+		{uploadSourceToTargetURLOpts{resume: true}, remote, local, true},
+		{uploadSourceToTargetURLOpts{}, remote, local, false},
+		// Copies between filesystems or to object storage restart.
+		{uploadSourceToTargetURLOpts{resume: true}, local, local, false},
+		{uploadSourceToTargetURLOpts{resume: true}, remote, remote, false},
+		// Ranged, scanned and zip downloads restart.
+		{uploadSourceToTargetURLOpts{resume: true, ranged: true}, remote, local, false},
+		{uploadSourceToTargetURLOpts{resume: true, scanner: &downloadScanner{}}, remote, local, false},
+		{uploadSourceToTargetURLOpts{resume: true, isZip: true}, remote, local, false},
+	}
+	for i, testCase := range testCases {
+		if resumable := isResumableDownload(testCase.opts, testCase.source, testCase.target, "s3/bucket/object"); resumable != testCase.resumable {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.resumable, resumable)
+		}
+	}
+}
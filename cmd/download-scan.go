@@ -0,0 +1,398 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Flags of the commands downloading objects.
+var downloadScanFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:   "scan-command",
+		Usage:  "scan the downloaded objects with a command reading them on its standard input and exiting with 1 when infected, e.g. 'clamdscan --no-summary -'",
+		EnvVar: envPrefix + "SCAN_COMMAND",
+	},
+	cli.StringFlag{
+		Name:   "scan-icap",
+		Usage:  "scan the downloaded objects with an ICAP server, e.g. 'icap://127.0.0.1:1344/avscan'",
+		EnvVar: envPrefix + "SCAN_ICAP",
+	},
+	cli.StringFlag{
+		Name:   "quarantine-dir",
+		Usage:  "save the infected objects in a folder instead of discarding them",
+		EnvVar: envPrefix + "QUARANTINE_DIR",
+	},
+}
+
+// Time to connect to and to wait for the verdict of an ICAP server once
+// the object is sent.
+const icapTimeout = time.Minute
+
+// ObjectInfected - the download of an object was stopped by its scan.
+type ObjectInfected struct {
+	Path       string `json:"path"`
+	Threat     string `json:"threat,omitempty"`
+	Quarantine string `json:"quarantine,omitempty"`
+}
+
+func (e ObjectInfected) Error() string {
+	msg := fmt.Sprintf("`%s` is infected", e.Path)
+	if e.Threat != "" {
+		msg += " (" + e.Threat + ")"
+	}
+	if e.Quarantine != "" {
+		return msg + ", quarantined in `" + e.Quarantine + "`."
+	}
+	return msg + ", not saved."
+}
+
+// downloadScanner - scans the objects downloaded to the local disk before
+// they are saved.
+type downloadScanner struct {
+	command       []string
+	icap          *url.URL
+	quarantineDir string
+}
+
+// newDownloadScanner - returns the scanner set by the flags, or nil when
+// the downloads are not scanned.
+func newDownloadScanner(cliCtx *cli.Context) (*downloadScanner, *probe.Error) {
+	command := strings.Fields(cliCtx.String("scan-command"))
+	icap := cliCtx.String("scan-icap")
+	quarantineDir := cliCtx.String("quarantine-dir")
+	switch {
+	case len(command) > 0 && icap != "":
+		return nil, probe.NewError(errors.New("--scan-command and --scan-icap cannot be used together"))
+	case len(command) == 0 && icap == "":
+		if quarantineDir != "" {
+			return nil, probe.NewError(errors.New("--quarantine-dir requires --scan-command or --scan-icap"))
+		}
+		return nil, nil
+	}
+	s := &downloadScanner{command: command, quarantineDir: quarantineDir}
+	if icap != "" {
+		u, e := url.Parse(icap)
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		if u.Scheme != "icap" || u.Host == "" {
+			return nil, probe.NewError(fmt.Errorf("invalid ICAP service `%s`, e.g. icap://127.0.0.1:1344/avscan", icap))
+		}
+		if u.Port() == "" {
+			u.Host = net.JoinHostPort(u.Hostname(), "1344")
+		}
+		s.icap = u
+	}
+	return s, nil
+}
+
+// downloadScan - scan of an object, fed by reading the object through it.
+type downloadScan struct {
+	reader io.Reader
+	sink   io.WriteCloser
+	// The writes to the sink stop at the first error, a scanner
+	// returning its verdict early doesn't read the rest.
+	sinkErr error
+	wait    func() (threat string, infected bool, err *probe.Error)
+
+	path       string
+	quarantine *os.File
+	target     string
+	done       bool
+}
+
+// start - starts the scan of the object at the aliased path, read from
+// reader.
+func (s *downloadScanner) start(ctx context.Context, path string, reader io.Reader) (*downloadScan, *probe.Error) {
+	scan := &downloadScan{reader: reader, path: path}
+	var err *probe.Error
+	if s.icap != nil {
+		err = scan.startICAP(ctx, s.icap, path)
+	} else {
+		err = scan.startCommand(ctx, s.command, path)
+	}
+	if err != nil {
+		return nil, err.Trace(path)
+	}
+	if s.quarantineDir != "" {
+		scan.target = filepath.Join(s.quarantineDir, filepath.FromSlash(path))
+		e := os.MkdirAll(filepath.Dir(scan.target), 0o700)
+		if e == nil {
+			scan.quarantine, e = os.CreateTemp(filepath.Dir(scan.target), "."+filepath.Base(scan.target)+".*")
+		}
+		if e != nil {
+			scan.sink.Close()
+			scan.wait()
+			return nil, probe.NewError(e).Trace(path)
+		}
+	}
+	return scan, nil
+}
+
+// Read - reads the object, and returns its verdict once the object is read
+// in place of io.EOF.
+func (scan *downloadScan) Read(p []byte) (int, error) {
+	if scan.done {
+		return 0, io.EOF
+	}
+	n, e := scan.reader.Read(p)
+	if n > 0 {
+		if scan.sinkErr == nil {
+			_, scan.sinkErr = scan.sink.Write(p[:n])
+		}
+		if scan.quarantine != nil {
+			if _, qe := scan.quarantine.Write(p[:n]); qe != nil {
+				scan.abort()
+				return n, qe
+			}
+		}
+	}
+	if e == io.EOF {
+		scan.done = true
+		if err := scan.verdict(); err != nil {
+			return n, err
+		}
+	} else if e != nil {
+		scan.abort()
+	}
+	return n, e
+}
+
+// verdict - waits for the result of the scan, returns ObjectInfected for
+// infected objects after moving them to the quarantine.
+func (scan *downloadScan) verdict() error {
+	scan.sink.Close()
+	threat, infected, err := scan.wait()
+	switch {
+	case err != nil:
+		scan.discard()
+		return err.Trace(scan.path).ToGoError()
+	case !infected:
+		scan.discard()
+		return nil
+	}
+	infection := ObjectInfected{Path: scan.path, Threat: threat}
+	if scan.quarantine != nil {
+		e := scan.quarantine.Close()
+		if e == nil {
+			e = os.Rename(scan.quarantine.Name(), scan.target)
+		}
+		if e != nil {
+			os.Remove(scan.quarantine.Name())
+			return e
+		}
+		infection.Quarantine = scan.target
+	}
+	return infection
+}
+
+// abort - stops a scan before the end of the object.
+func (scan *downloadScan) abort() {
+	if scan.done {
+		return
+	}
+	scan.done = true
+	scan.sink.Close()
+	scan.wait()
+	scan.discard()
+}
+
+func (scan *downloadScan) discard() {
+	if scan.quarantine != nil {
+		scan.quarantine.Close()
+		os.Remove(scan.quarantine.Name())
+	}
+}
+
+// startCommand - pipes the object to the standard input of the command,
+// which exits with 0 for clean objects and 1 for infected objects, like
+// clamscan. The object path is in the MC_SCAN_OBJECT environment variable.
+func (scan *downloadScan) startCommand(ctx context.Context, command []string, path string) *probe.Error {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Env = append(os.Environ(), envPrefix+"SCAN_OBJECT="+path)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	stdin, e := cmd.StdinPipe()
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if e = cmd.Start(); e != nil {
+		return probe.NewError(e)
+	}
+	scan.sink = stdin
+	scan.wait = func() (string, bool, *probe.Error) {
+		e := cmd.Wait()
+		var exitErr *exec.ExitError
+		switch {
+		case e == nil:
+			return "", false, nil
+		case errors.As(e, &exitErr) && exitErr.ExitCode() == 1:
+			return lastLine(out.String()), true, nil
+		}
+		if output := lastLine(out.String()); output != "" {
+			e = fmt.Errorf("%v: %s", e, output)
+		}
+		return "", false, probe.NewError(fmt.Errorf("scan command failed: %v", e))
+	}
+	return nil
+}
+
+// lastLine - returns the last line of the output of a scan command, e.g.
+// 'stdin: Eicar-Signature FOUND'.
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// startICAP - sends the object to the ICAP service in a RESPMOD request
+// (RFC 3507). The server answers 204 for clean objects, and 200 with the
+// response to send instead for blocked objects.
+func (scan *downloadScan) startICAP(ctx context.Context, service *url.URL, path string) *probe.Error {
+	dialer := net.Dialer{Timeout: icapTimeout}
+	conn, e := dialer.DialContext(ctx, "tcp", service.Host)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	reqHdr := "GET /" + strings.TrimPrefix((&url.URL{Path: path}).EscapedPath(), "/") + " HTTP/1.1\r\nHost: mc\r\n\r\n"
+	resHdr := "HTTP/1.1 200 OK\r\nContent-Type: application/octet-stream\r\n\r\n"
+	header := fmt.Sprintf("RESPMOD %s ICAP/1.0\r\nHost: %s\r\nAllow: 204\r\nEncapsulated: req-hdr=0, res-hdr=%d, res-body=%d\r\n\r\n",
+		service.String(), service.Host, len(reqHdr), len(reqHdr)+len(resHdr))
+	if _, e = io.WriteString(conn, header+reqHdr+resHdr); e != nil {
+		conn.Close()
+		return probe.NewError(e)
+	}
+
+	type icapVerdict struct {
+		threat   string
+		infected bool
+		err      *probe.Error
+	}
+	verdictCh := make(chan icapVerdict, 1)
+	var closeOnce sync.Once
+	closeConn := func() { closeOnce.Do(func() { conn.Close() }) }
+	go func() {
+		threat, infected, err := readICAPResponse(bufio.NewReader(conn))
+		verdictCh <- icapVerdict{threat, infected, err}
+		// Stop the writes of a server answering before the end of the
+		// object.
+		closeConn()
+	}()
+
+	scan.sink = &icapChunkWriter{conn: conn}
+	scan.wait = func() (string, bool, *probe.Error) {
+		conn.SetReadDeadline(time.Now().Add(icapTimeout))
+		v := <-verdictCh
+		closeConn()
+		return v.threat, v.infected, v.err
+	}
+	return nil
+}
+
+// icapChunkWriter - writes the object to the ICAP server in chunks.
+type icapChunkWriter struct {
+	conn net.Conn
+}
+
+func (w *icapChunkWriter) Write(p []byte) (int, error) {
+	if _, e := fmt.Fprintf(w.conn, "%x\r\n%s\r\n", len(p), p); e != nil {
+		return 0, e
+	}
+	return len(p), nil
+}
+
+// Close - ends the object, the connection is closed once the server
+// answered.
+func (w *icapChunkWriter) Close() error {
+	_, e := io.WriteString(w.conn, "0\r\n\r\n")
+	return e
+}
+
+// readICAPResponse - reads the verdict of an ICAP server, with the threat
+// of infected objects from the headers of common servers.
+func readICAPResponse(r *bufio.Reader) (threat string, infected bool, err *probe.Error) {
+	tp := textproto.NewReader(r)
+	status, e := tp.ReadLine()
+	if e != nil {
+		return "", false, probe.NewError(fmt.Errorf("ICAP server: %v", e))
+	}
+	fields := strings.SplitN(status, " ", 3)
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "ICAP/") {
+		return "", false, probe.NewError(fmt.Errorf("ICAP server: malformed status line `%s`", status))
+	}
+	code, e := strconv.Atoi(fields[1])
+	if e != nil {
+		return "", false, probe.NewError(fmt.Errorf("ICAP server: malformed status line `%s`", status))
+	}
+	header, e := tp.ReadMIMEHeader()
+	if e != nil {
+		return "", false, probe.NewError(fmt.Errorf("ICAP server: %v", e))
+	}
+	switch code {
+	case 204:
+		return "", false, nil
+	case 200:
+	default:
+		return "", false, probe.NewError(fmt.Errorf("ICAP server: %s", status))
+	}
+
+	for _, key := range []string{"X-Infection-Found", "X-Violations-Found", "X-Virus-Id"} {
+		if v := header.Get(key); v != "" {
+			return icapThreat(v), true, nil
+		}
+	}
+	// Without these headers, the objects replaced by an error page are
+	// blocked, the objects sent back as is are clean.
+	if strings.Contains(header.Get("Encapsulated"), "res-hdr") {
+		httpStatus, e := tp.ReadLine()
+		if e == nil && len(strings.Fields(httpStatus)) > 1 && strings.Fields(httpStatus)[1] == "200" {
+			return "", false, nil
+		}
+	}
+	return "", true, nil
+}
+
+// icapThreat - returns the threat of an X-Infection-Found header, e.g.
+// 'Type=0; Resolution=2; Threat=Eicar-Signature;', or the header as is.
+func icapThreat(v string) string {
+	for _, field := range strings.Split(v, ";") {
+		if k, threat, ok := strings.Cut(strings.TrimSpace(field), "="); ok && strings.EqualFold(k, "Threat") {
+			return threat
+		}
+	}
+	return strings.TrimSpace(v)
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// icapHandler - answers RESPMOD requests like an antivirus, blocking the
+// objects containing EICAR.
+func icapHandler(t *testing.T, conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	tp := textproto.NewReader(r)
+	if _, e := tp.ReadLine(); e != nil {
+		t.Error(e)
+		return
+	}
+	header, e := tp.ReadMIMEHeader()
+	if e != nil {
+		t.Error(e)
+		return
+	}
+	_, offset, _ := strings.Cut(header.Get("Encapsulated"), "res-body=")
+	n, _ := strconv.Atoi(offset)
+	if _, e = io.CopyN(io.Discard, r, int64(n)); e != nil {
+		t.Error(e)
+		return
+	}
+	var body bytes.Buffer
+	for {
+		line, e := tp.ReadLine()
+		if e != nil {
+			t.Error(e)
+			return
+		}
+		size, _ := strconv.ParseInt(line, 16, 64)
+		if size == 0 {
+			tp.ReadLine()
+			break
+		}
+		io.CopyN(&body, r, size)
+		tp.ReadLine()
+	}
+	if bytes.Contains(body.Bytes(), []byte("EICAR")) {
+		io.WriteString(conn, "ICAP/1.0 200 OK\r\nX-Infection-Found: Type=0; Resolution=2; Threat=Eicar-Test-Signature;\r\nEncapsulated: res-hdr=0, null-body=40\r\n\r\nHTTP/1.1 403 Forbidden\r\nContent-Length: 0\r\n\r\n")
+		return
+	}
+	io.WriteString(conn, "ICAP/1.0 204 No Content\r\nEncapsulated: null-body=0\r\n\r\n")
+}
+
+func TestDownloadScanICAP(t *testing.T) {
+	l, e := net.Listen("tcp", "127.0.0.1:0")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, e := l.Accept()
+			if e != nil {
+				return
+			}
+			go icapHandler(t, conn)
+		}
+	}()
+
+	quarantineDir := t.TempDir()
+	scanner := &downloadScanner{
+		icap:          &url.URL{Scheme: "icap", Host: l.Addr().String(), Path: "/avscan"},
+		quarantineDir: quarantineDir,
+	}
+	testCases := []struct {
+		path     string
+		content  string
+		infected bool
+	}{
+		{"play/bucket/clean.txt", "hello world\n", false},
+		{"play/bucket/dir/eicar.txt", strings.Repeat("x", 100000) + "EICAR-STANDARD-ANTIVIRUS-TEST-FILE", true},
+	}
+	for i, testCase := range testCases {
+		scan, err := scanner.start(context.Background(), testCase.path, strings.NewReader(testCase.content))
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		var out bytes.Buffer
+		_, e = io.Copy(&out, scan)
+		scan.abort()
+
+		quarantine := filepath.Join(quarantineDir, filepath.FromSlash(testCase.path))
+		if !testCase.infected {
+			if e != nil || out.String() != testCase.content {
+				t.Errorf("Test %d: clean object read with %v", i+1, e)
+			}
+			if _, e = os.Stat(quarantine); !os.IsNotExist(e) {
+				t.Errorf("Test %d: clean object quarantined", i+1)
+			}
+			continue
+		}
+		var infection ObjectInfected
+		if !errors.As(e, &infection) {
+			t.Fatalf("Test %d: infected object read with %v", i+1, e)
+		}
+		if infection.Threat != "Eicar-Test-Signature" || infection.Quarantine != quarantine {
+			t.Errorf("Test %d: got %+v", i+1, infection)
+		}
+		if data, e := os.ReadFile(quarantine); e != nil || string(data) != testCase.content {
+			t.Errorf("Test %d: object not quarantined: %v", i+1, e)
+		}
+	}
+	// Only the quarantined object is left.
+	entries, _ := os.ReadDir(filepath.Join(quarantineDir, "play", "bucket"))
+	if len(entries) != 1 || entries[0].Name() != "dir" {
+		t.Errorf("unexpected quarantine files %v", entries)
+	}
+}
+
+func TestReadICAPResponse(t *testing.T) {
+	testCases := []struct {
+		response string
+		threat   string
+		infected bool
+		err      bool
+	}{
+		{"ICAP/1.0 204 No Content\r\n\r\n", "", false, false},
+		{"ICAP/1.0 200 OK\r\nX-Virus-ID: Win.Test.EICAR_HDB-1\r\nEncapsulated: res-hdr=0\r\n\r\n", "Win.Test.EICAR_HDB-1", true, false},
+		{"ICAP/1.0 200 OK\r\nEncapsulated: res-hdr=0, res-body=19\r\n\r\nHTTP/1.1 200 OK\r\n\r\n", "", false, false},
+		{"ICAP/1.0 200 OK\r\nEncapsulated: res-hdr=0, res-body=26\r\n\r\nHTTP/1.1 403 Forbidden\r\n\r\n", "", true, false},
+		{"ICAP/1.0 500 Server Error\r\n\r\n", "", false, true},
+		{"HTTP/1.1 200 OK\r\n\r\n", "", false, true},
+	}
+	for i, testCase := range testCases {
+		threat, infected, err := readICAPResponse(bufio.NewReader(strings.NewReader(testCase.response)))
+		if (err != nil) != testCase.err {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if threat != testCase.threat || infected != testCase.infected {
+			t.Errorf("Test %d: got %q, %v", i+1, threat, infected)
+		}
+	}
+}
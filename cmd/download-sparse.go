@@ -0,0 +1,286 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	minio "github.com/minio/minio-go/v7"
+)
+
+// Flags of the commands downloading objects to files.
+var downloadSparseFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "sparse",
+		Usage: "leave holes in the downloaded files in place of their blocks of zeros, e.g. for disk images",
+	},
+	cli.BoolFlag{
+		Name:  "skip-matching-parts",
+		Usage: "download only the parts of the objects whose checksums differ from the existing files",
+	},
+}
+
+// Size of the blocks of zeros skipped by sparse writes, a multiple of
+// the block size of the filesystems.
+const sparseBlockSize = 64 << 10
+
+var zeroBlock = make([]byte, sparseBlockSize)
+
+// sparseWriter - writes a file seeking over its blocks of zeros instead
+// of writing them, the blocks are left unallocated by the filesystems
+// supporting sparse files.
+type sparseWriter struct {
+	file   *os.File
+	offset int64
+}
+
+// newSparseWriter - returns a writer of file from offset, the current
+// offset of file.
+func newSparseWriter(file *os.File, offset int64) *sparseWriter {
+	return &sparseWriter{file: file, offset: offset}
+}
+
+func (w *sparseWriter) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		// Blocks are aligned on their offset in the file.
+		n := sparseBlockSize - int(w.offset%sparseBlockSize)
+		if n > len(p) {
+			n = len(p)
+		}
+		if bytes.Equal(p[:n], zeroBlock[:n]) {
+			if _, e := w.file.Seek(int64(n), io.SeekCurrent); e != nil {
+				return written, e
+			}
+		} else if _, e := w.file.Write(p[:n]); e != nil {
+			return written, e
+		}
+		w.offset += int64(n)
+		written += n
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// Close - sets the size of the file, which ends with a hole when its
+// last block is made of zeros, and closes it.
+func (w *sparseWriter) Close() error {
+	if e := w.file.Truncate(w.offset); e != nil {
+		w.file.Close()
+		return e
+	}
+	return w.file.Close()
+}
+
+// partChecksum - the checksum of a part of an object.
+type partChecksum struct {
+	start, length int64
+	algo          minio.ChecksumType
+	sum           string // Base64 encoded
+}
+
+// partChecksumOf - returns a checksum of a part, or of an object uploaded
+// in a single part.
+func partChecksumOf(crc32, crc32c, sha1, sha256 string) (minio.ChecksumType, string) {
+	switch {
+	case sha256 != "":
+		return minio.ChecksumSHA256, sha256
+	case sha1 != "":
+		return minio.ChecksumSHA1, sha1
+	case crc32c != "":
+		return minio.ChecksumCRC32C, crc32c
+	case crc32 != "":
+		return minio.ChecksumCRC32, crc32
+	}
+	return minio.ChecksumNone, ""
+}
+
+// objectPartChecksums - returns the checksums of the parts of an object
+// of size bytes, none when they are not known, e.g. the object was
+// uploaded without checksums or the server does not report them.
+func objectPartChecksums(ctx context.Context, clnt Client, size int64, opts GetOptions) []partChecksum {
+	s3Clnt, ok := unwrapClient(clnt).(*S3Client)
+	if !ok {
+		return nil
+	}
+	bucket, object := s3Clnt.url2BucketAndObject()
+	attrOpts := minio.ObjectAttributesOptions{VersionID: opts.VersionID, ServerSideEncryption: opts.SSE}
+
+	var parts []partChecksum
+	var start int64
+	for {
+		attrs, e := s3Clnt.api.GetObjectAttributes(ctx, bucket, object, attrOpts)
+		if e != nil {
+			return nil
+		}
+		if attrs.ObjectParts.PartsCount == 0 {
+			c := attrs.Checksum
+			algo, sum := partChecksumOf(c.ChecksumCRC32, c.ChecksumCRC32C, c.ChecksumSHA1, c.ChecksumSHA256)
+			// Checksums of multipart objects without parts are
+			// checksums of checksums.
+			if !algo.IsSet() || strings.Contains(sum, "-") || int64(attrs.ObjectSize) != size {
+				return nil
+			}
+			return []partChecksum{{length: size, algo: algo, sum: sum}}
+		}
+		for _, p := range attrs.ObjectParts.Parts {
+			algo, sum := partChecksumOf(p.ChecksumCRC32, p.ChecksumCRC32C, p.ChecksumSHA1, p.ChecksumSHA256)
+			if !algo.IsSet() {
+				return nil
+			}
+			parts = append(parts, partChecksum{start: start, length: int64(p.Size), algo: algo, sum: sum})
+			start += int64(p.Size)
+		}
+		if !attrs.ObjectParts.IsTruncated || len(attrs.ObjectParts.Parts) == 0 {
+			break
+		}
+		attrOpts.PartNumberMarker = attrs.ObjectParts.NextPartNumberMarker
+	}
+	if start != size {
+		return nil
+	}
+	return parts
+}
+
+// fileMatchingParts - returns which parts of an object the file holds
+// already, by their checksums.
+func fileMatchingParts(file *os.File, parts []partChecksum) ([]bool, error) {
+	matching := make([]bool, len(parts))
+	for i, part := range parts {
+		h := part.algo.Hasher()
+		if _, e := io.Copy(h, io.NewSectionReader(file, part.start, part.length)); e != nil {
+			return nil, e
+		}
+		matching[i] = base64.StdEncoding.EncodeToString(h.Sum(nil)) == part.sum
+	}
+	return matching, nil
+}
+
+// partsReader - reads an object from a file for the ranges it holds
+// already, and with ranged requests for the others.
+type partsReader struct {
+	ctx    context.Context
+	clnt   Client
+	opts   GetOptions
+	file   *os.File
+	ranges []partChecksum // Consecutive parts of the same origin merged
+	local  []bool
+	cur    io.ReadCloser
+	next   int
+}
+
+func newPartsReader(ctx context.Context, clnt Client, opts GetOptions, file *os.File, parts []partChecksum, matching []bool) *partsReader {
+	r := &partsReader{ctx: ctx, clnt: clnt, opts: opts, file: file}
+	for i, part := range parts {
+		if n := len(r.ranges); n > 0 && r.local[n-1] == matching[i] {
+			r.ranges[n-1].length += part.length
+			continue
+		}
+		r.ranges = append(r.ranges, partChecksum{start: part.start, length: part.length})
+		r.local = append(r.local, matching[i])
+	}
+	return r
+}
+
+func (r *partsReader) Read(p []byte) (int, error) {
+	for {
+		if r.cur == nil {
+			if r.next == len(r.ranges) {
+				return 0, io.EOF
+			}
+			part := r.ranges[r.next]
+			if r.local[r.next] {
+				r.cur = io.NopCloser(io.NewSectionReader(r.file, part.start, part.length))
+			} else {
+				opts := r.opts
+				opts.RangeStart, opts.RangeLength = part.start, part.length
+				reader, _, err := r.clnt.Get(r.ctx, opts)
+				if err != nil {
+					return 0, err.ToGoError()
+				}
+				r.cur = reader
+			}
+			r.next++
+		}
+		n, e := r.cur.Read(p)
+		if e == io.EOF {
+			r.cur.Close()
+			r.cur = nil
+			if n == 0 {
+				continue
+			}
+			e = nil
+		}
+		return n, e
+	}
+}
+
+func (r *partsReader) Close() error {
+	if r.cur != nil {
+		r.cur.Close()
+	}
+	return r.file.Close()
+}
+
+// getChangedPartsStream - returns a reader of the object at urlStr which
+// downloads only the parts differing from the file at path, or a reader
+// of the whole object when none of them match.
+func getChangedPartsStream(ctx context.Context, alias, urlStr, path string, opts getSourceOpts) (io.ReadCloser, *ClientContent, *probe.Error) {
+	clnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return nil, nil, err.Trace(alias, urlStr)
+	}
+	content, err := clnt.Stat(ctx, StatOptions{sse: opts.SSE, versionID: opts.VersionID, preserve: opts.Preserve})
+	if err != nil {
+		return nil, nil, err.Trace(alias, urlStr)
+	}
+	// The parts are read from the version whose checksums are compared.
+	opts.VersionID = content.VersionID
+
+	file, e := os.Open(path)
+	if e != nil {
+		return getSourceStream(ctx, alias, urlStr, opts)
+	}
+	if st, e := file.Stat(); e != nil || !st.Mode().IsRegular() || st.Size() != content.Size {
+		file.Close()
+		return getSourceStream(ctx, alias, urlStr, opts)
+	}
+	parts := objectPartChecksums(ctx, clnt, content.Size, opts.GetOptions)
+	matching, e := fileMatchingParts(file, parts)
+	if e != nil || !anyTrue(matching) {
+		file.Close()
+		return getSourceStream(ctx, alias, urlStr, opts)
+	}
+	return newPartsReader(ctx, clnt, opts.GetOptions, file, parts, matching), content, nil
+}
+
+func anyTrue(values []bool) bool {
+	for _, v := range values {
+		if v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+	minio "github.com/minio/minio-go/v7"
+)
+
+func TestSparseWriter(t *testing.T) {
+	data := make([]byte, 5*sparseBlockSize+100)
+	rand.Read(data[sparseBlockSize/2 : sparseBlockSize])
+	rand.Read(data[3*sparseBlockSize : 3*sparseBlockSize+10])
+
+	for _, chunk := range []int{1000, sparseBlockSize, 3 * sparseBlockSize} {
+		path := filepath.Join(t.TempDir(), "sparse")
+		file, e := os.Create(path)
+		if e != nil {
+			t.Fatal(e)
+		}
+		w := newSparseWriter(file, 0)
+		for p := data; len(p) > 0; {
+			n := chunk
+			if n > len(p) {
+				n = len(p)
+			}
+			if _, e = w.Write(p[:n]); e != nil {
+				t.Fatal(e)
+			}
+			p = p[n:]
+		}
+		if e = w.Close(); e != nil {
+			t.Fatal(e)
+		}
+		got, e := os.ReadFile(path)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("writes of %d bytes: the file differs from the data, %d bytes instead of %d", chunk, len(got), len(data))
+		}
+	}
+}
+
+func TestPartsReader(t *testing.T) {
+	object := make([]byte, 3000)
+	rand.Read(object)
+	var parts []partChecksum
+	for start := int64(0); start < int64(len(object)); start += 1000 {
+		sum := sha256.Sum256(object[start : start+1000])
+		parts = append(parts, partChecksum{start: start, length: 1000, algo: minio.ChecksumSHA256, sum: base64.StdEncoding.EncodeToString(sum[:])})
+	}
+
+	local := append([]byte(nil), object...)
+	local[1500] ^= 0xff
+	path := filepath.Join(t.TempDir(), "object")
+	if e := os.WriteFile(path, local, 0o644); e != nil {
+		t.Fatal(e)
+	}
+	file, e := os.Open(path)
+	if e != nil {
+		t.Fatal(e)
+	}
+	matching, e := fileMatchingParts(file, parts)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if want := []bool{true, false, true}; len(matching) != len(want) || matching[0] != want[0] || matching[1] != want[1] || matching[2] != want[2] {
+		t.Fatalf("matching parts %v, want %v", matching, want)
+	}
+
+	// The part differing from the file is read from the object.
+	remote := &fakeRangeClient{data: object}
+	r := newPartsReader(globalContext, remote, GetOptions{}, file, parts, matching)
+	got, e := io.ReadAll(r)
+	r.Close()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !bytes.Equal(got, object) {
+		t.Error("the object read differs from the object")
+	}
+	if len(remote.ranges) != 1 || remote.ranges[0] != [2]int64{1000, 1000} {
+		t.Errorf("ranges read %v, want [[1000 1000]]", remote.ranges)
+	}
+}
+
+// fakeRangeClient - a client whose Get reads ranges of data.
+type fakeRangeClient struct {
+	Client
+	data   []byte
+	ranges [][2]int64
+}
+
+func (c *fakeRangeClient) Get(_ context.Context, opts GetOptions) (io.ReadCloser, *ClientContent, *probe.Error) {
+	c.ranges = append(c.ranges, [2]int64{opts.RangeStart, opts.RangeLength})
+	data := c.data[opts.RangeStart : opts.RangeStart+opts.RangeLength]
+	return io.NopCloser(bytes.NewReader(data)), &ClientContent{Size: int64(len(data))}, nil
+}
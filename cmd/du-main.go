@@ -111,7 +111,7 @@ func (r duMessage) String() string {
 	}
 	return fmt.Sprintf("%s\t%s\t%s", console.Colorize("Size", humanSize),
 		console.Colorize("Objects", cnt),
-		console.Colorize("Prefix", r.Prefix))
+		console.Colorize("Prefix", quoteName(r.Prefix)))
 }
 
 // JSON'ified message for scripting.
@@ -0,0 +1,82 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/pkg/v2/console"
+)
+
+var encryptKeyListCmd = cli.Command{
+	Name:            "list",
+	ShortName:       "ls",
+	Usage:           "list the prefixes having a saved encryption key",
+	Action:          mainEncryptKeyList,
+	OnUsageError:    onUsageError,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [ALIAS]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. List the prefixes of all aliases having a saved encryption key.
+     {{.Prompt}} {{.HelpName}}
+
+  2. List the prefixes of "myminio" having a saved encryption key.
+     {{.Prompt}} {{.HelpName}} myminio
+`,
+}
+
+// mainEncryptKeyList is the handle for "mc encrypt key list" command.
+func mainEncryptKeyList(ctx *cli.Context) error {
+	if len(ctx.Args()) > 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+
+	console.SetColor("EncryptKeyPrefix", color.New(color.FgCyan, color.Bold))
+
+	alias := cleanAlias(ctx.Args().Get(0))
+	conf, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config version `"+globalMCConfigVersion+"`.")
+
+	prefixes := make([]string, 0, len(conf.EncryptKeys))
+	for prefix := range conf.EncryptKeys {
+		if keyAlias, _ := url2Alias(prefix); alias == "" || keyAlias == alias {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	sort.Strings(prefixes)
+	for _, prefix := range prefixes {
+		keyType := conf.EncryptKeys[prefix].Type
+		if keyType == "" {
+			keyType = encryptKeyTypeSSEC
+		}
+		printMsg(encryptKeyMessage{op: "list", Prefix: prefix, Type: keyType})
+	}
+	return nil
+}
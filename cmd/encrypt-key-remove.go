@@ -0,0 +1,73 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+var encryptKeyRemoveCmd = cli.Command{
+	Name:            "remove",
+	ShortName:       "rm",
+	Usage:           "remove the saved encryption key of a prefix",
+	Action:          mainEncryptKeyRemove,
+	OnUsageError:    onUsageError,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} ALIAS/PREFIX
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Remove the encryption key saved for "myminio/documents/".
+     {{.Prompt}} {{.HelpName}} myminio/documents/
+`,
+}
+
+// mainEncryptKeyRemove is the handle for "mc encrypt key remove" command.
+func mainEncryptKeyRemove(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+
+	console.SetColor("EncryptKeyMessage", color.New(color.FgGreen))
+
+	prefix := ctx.Args().Get(0)
+	conf, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config version `"+globalMCConfigVersion+"`.")
+	if _, ok := conf.EncryptKeys[prefix]; !ok {
+		fatalIf(errInvalidArgument().Trace(prefix), "No encryption key is saved for `"+prefix+"`.")
+	}
+	err = updateMcConfig(func(conf *configV10) *probe.Error {
+		delete(conf.EncryptKeys, prefix)
+		return nil
+	})
+	fatalIf(err.Trace(prefix), "Unable to remove the encryption key in config version `"+globalMCConfigVersion+"`.")
+
+	printMsg(encryptKeyMessage{op: "remove", Prefix: prefix})
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/base64"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+var encryptKeySetFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "client-side",
+		Usage: "encrypt objects in mc before uploading them, instead of using SSE-C",
+	},
+}
+
+var encryptKeySetCmd = cli.Command{
+	Name:            "set",
+	Usage:           "save the encryption key of a prefix",
+	Action:          mainEncryptKeySet,
+	OnUsageError:    onUsageError,
+	Before:          setGlobalsFromContext,
+	Flags:           append(encryptKeySetFlags, globalFlags...),
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] ALIAS/PREFIX KEY
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Saved keys apply to every command accessing objects under the prefix, as if
+  they were given with --encrypt-key. Keys given with --encrypt-key take
+  precedence over the saved key of the same prefix.
+
+  Client side encrypted objects are encrypted with AES-256-GCM before being
+  uploaded and decrypted when downloaded, the server only ever sees encrypted
+  data. Listings, stat and diff report the sizes of the decrypted objects.
+
+EXAMPLES:
+  1. Save an SSE-C key for the objects under "myminio/documents/".
+     {{.Prompt}} {{.HelpName}} myminio/documents/ 32byteslongsecretkeymustbegiven1
+
+  2. Encrypt the objects under "s3/backups/" client side with a base64 encoded key.
+     {{.Prompt}} {{.HelpName}} --client-side s3/backups/ MzJieXRlc2xvbmdzZWNyZWFiY2RlZmcJZ2l2ZW5uMjE=
+`,
+}
+
+// checkEncryptKeySetSyntax - validate all the passed arguments
+func checkEncryptKeySetSyntax(ctx *cli.Context) []byte {
+	if len(ctx.Args()) != 2 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	checkEncryptKeyPrefix(ctx.Args().Get(0))
+	key, err := decodeEncryptKey(ctx.Args().Get(1))
+	fatalIf(err, "Invalid encryption key.")
+	return key
+}
+
+// mainEncryptKeySet is the handle for "mc encrypt key set" command.
+func mainEncryptKeySet(ctx *cli.Context) error {
+	key := checkEncryptKeySetSyntax(ctx)
+
+	console.SetColor("EncryptKeyMessage", color.New(color.FgGreen))
+
+	prefix := ctx.Args().Get(0)
+	keyType := encryptKeyTypeSSEC
+	if ctx.Bool("client-side") {
+		keyType = encryptKeyTypeClient
+	}
+
+	err := updateMcConfig(func(conf *configV10) *probe.Error {
+		if conf.EncryptKeys == nil {
+			conf.EncryptKeys = make(map[string]encryptKeyV10)
+		}
+		conf.EncryptKeys[prefix] = encryptKeyV10{
+			Key:  base64.StdEncoding.EncodeToString(key),
+			Type: keyType,
+		}
+		return nil
+	})
+	fatalIf(err.Trace(prefix), "Unable to save the encryption key in config version `"+globalMCConfigVersion+"`.")
+
+	printMsg(encryptKeyMessage{op: "set", Prefix: prefix, Type: keyType})
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+var encryptKeySubcommands = []cli.Command{
+	encryptKeySetCmd,
+	encryptKeyListCmd,
+	encryptKeyRemoveCmd,
+}
+
+var encryptKeyCmd = cli.Command{
+	Name:            "key",
+	Usage:           "manage encryption keys of the configuration file",
+	HideHelpCommand: true,
+	Action:          mainEncryptKey,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	Subcommands:     encryptKeySubcommands,
+}
+
+// mainEncryptKey is the handle for "mc encrypt key" command.
+func mainEncryptKey(ctx *cli.Context) error {
+	commandNotFound(ctx, encryptKeySubcommands)
+	return nil
+	// Sub-commands like "set", "list", "remove" have their own main.
+}
+
+// encryptKeyMessage - a key of the configuration, the key material is
+// never printed.
+type encryptKeyMessage struct {
+	op     string
+	Status string `json:"status"`
+	Prefix string `json:"prefix"`
+	Type   string `json:"type,omitempty"`
+}
+
+func (m encryptKeyMessage) String() string {
+	switch m.op {
+	case "set":
+		return console.Colorize("EncryptKeyMessage", fmt.Sprintf("Encryption key (%s) set for `%s`.", m.Type, m.Prefix))
+	case "remove":
+		return console.Colorize("EncryptKeyMessage", fmt.Sprintf("Encryption key removed for `%s`.", m.Prefix))
+	}
+	return console.Colorize("EncryptKeyPrefix", fmt.Sprintf("%-6s", m.Type)) + "  " + m.Prefix
+}
+
+func (m encryptKeyMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// checkEncryptKeyPrefix - verifies that prefix starts with a configured alias.
+func checkEncryptKeyPrefix(prefix string) {
+	alias, _ := url2Alias(prefix)
+	if !isValidAlias(alias) {
+		fatalIf(errInvalidAlias(alias).Trace(prefix), "Invalid alias in `"+prefix+"`.")
+	}
+	aliasMustExist(alias)
+}
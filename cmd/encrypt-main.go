@@ -23,6 +23,7 @@ var encryptSubcommands = []cli.Command{
 	encryptSetCmd,
 	encryptClearCmd,
 	encryptInfoCmd,
+	encryptKeyCmd,
 }
 
 var encryptCmd = cli.Command{
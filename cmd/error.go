@@ -32,8 +32,9 @@ import (
 
 // causeMessage container for golang error messages
 type causeMessage struct {
-	Message string `json:"message"`
-	Error   error  `json:"error"`
+	Message string   `json:"message"`
+	Error   error    `json:"error"`
+	Causes  []string `json:"causes,omitempty"`
 }
 
 // errorMessage container for error messages
@@ -45,6 +46,61 @@ type errorMessage struct {
 	SysInfo   map[string]string  `json:"sysinfo,omitempty"`
 }
 
+// newErrorMessage - returns the message of err of type typ, with the
+// messages of the errors it wraps.
+func newErrorMessage(err *probe.Error, typ, msg string, data ...interface{}) errorMessage {
+	e := err.ToGoError()
+	errorMsg := errorMessage{
+		Message: fmt.Sprintf(msg, data...),
+		Type:    typ,
+		Cause: causeMessage{
+			Message: e.Error(),
+			Error:   e,
+		},
+	}
+	for cause := errors.Unwrap(e); cause != nil; cause = errors.Unwrap(cause) {
+		errorMsg.Cause.Causes = append(errorMsg.Cause.Causes, cause.Error())
+	}
+	if globalDebug {
+		errorMsg.CallTrace = err.CallTrace
+		errorMsg.SysInfo = err.SysInfo
+	}
+	return errorMsg
+}
+
+// String - errors are printed by fatal, errorIf and warningIf.
+func (e errorMessage) String() string {
+	return fmt.Sprintf("%s %s", e.Message, e.Cause.Message)
+}
+
+// JSON - jsonified error message.
+func (e errorMessage) JSON() string {
+	msgBytes, err := json.MarshalIndent(struct {
+		Status string       `json:"status"`
+		Error  errorMessage `json:"error"`
+	}{
+		Status: "error",
+		Error:  e,
+	}, "", " ")
+	if err != nil {
+		console.Fatalln(probe.NewError(err))
+	}
+	return string(msgBytes)
+}
+
+// commandError - returns the error reported for err, why ctx ended when
+// the command was canceled or timed out.
+func commandError(ctx context.Context, err *probe.Error) error {
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled):
+		// mc is getting killed
+		return errors.New("Canceling upon user request")
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("Timed out after %s (--timeout)", globalTimeout)
+	}
+	return err.ToGoError()
+}
+
 // fatalIf wrapper function which takes error and selectively prints stack frames if available on debug
 func fatalIf(err *probe.Error, msg string, data ...interface{}) {
 	if err == nil {
@@ -54,44 +110,19 @@ func fatalIf(err *probe.Error, msg string, data ...interface{}) {
 }
 
 func fatal(err *probe.Error, msg string, data ...interface{}) {
+	// Save the traffic done before exiting
+	globalUsage.flush()
+
 	if globalJSON {
-		errorMsg := errorMessage{
-			Message: msg,
-			Type:    "fatal",
-			Cause: causeMessage{
-				Message: err.ToGoError().Error(),
-				Error:   err.ToGoError(),
-			},
-		}
-		if globalDebug {
-			errorMsg.CallTrace = err.CallTrace
-			errorMsg.SysInfo = err.SysInfo
-		}
-		json, e := json.MarshalIndent(struct {
-			Status string       `json:"status"`
-			Error  errorMessage `json:"error"`
-		}{
-			Status: "error",
-			Error:  errorMsg,
-		}, "", " ")
-		if e != nil {
-			console.Fatalln(probe.NewError(e))
-		}
-		console.Println(string(json))
+		printMsg(newErrorMessage(err, "fatal", msg, data...))
 		console.Fatalln()
 	}
 
 	msg = fmt.Sprintf(msg, data...)
 	errmsg := err.String()
 	if !globalDebug {
-		var e error
-		if errors.Is(globalContext.Err(), context.Canceled) {
-			// mc is getting killed
-			e = errors.New("Canceling upon user request")
-		} else {
-			e = err.ToGoError()
-		}
-		errmsg = e.Error()
+		e := commandError(globalContext, err)
+		errmsg = e.Error() + aliasTypoHint(e)
 	}
 
 	// Remove unnecessary leading spaces in generic/detailed error messages
@@ -100,7 +131,7 @@ func fatal(err *probe.Error, msg string, data ...interface{}) {
 
 	// Add punctuations when needed
 	if len(errmsg) > 0 && len(msg) > 0 {
-		if msg[len(msg)-1] != ':' && msg[len(msg)-1] != '.' {
+		if msg[len(msg)-1] != ':' && msg[len(msg)-1] != '.' && msg[len(msg)-1] != '?' {
 			// The detailed error message starts with a capital letter,
 			// we should then add '.', otherwise add ':'.
 			if unicode.IsUpper(rune(errmsg[0])) {
@@ -110,7 +141,7 @@ func fatal(err *probe.Error, msg string, data ...interface{}) {
 			}
 		}
 		// Add '.' to the detail error if not found
-		if errmsg[len(errmsg)-1] != '.' {
+		if errmsg[len(errmsg)-1] != '.' && errmsg[len(errmsg)-1] != '?' {
 			errmsg += "."
 		}
 	}
@@ -132,47 +163,40 @@ func errorIf(err *probe.Error, msg string, data ...interface{}) {
 	if err == nil {
 		return
 	}
+	recordWarning()
+	recordRecentError(strings.TrimSpace(fmt.Sprintf(msg, data...) + " " + err.ToGoError().Error()))
 	if globalJSON {
-		errorMsg := errorMessage{
-			Message: fmt.Sprintf(msg, data...),
-			Type:    "error",
-			Cause: causeMessage{
-				Message: err.ToGoError().Error(),
-				Error:   err.ToGoError(),
-			},
-		}
-		if globalDebug {
-			errorMsg.CallTrace = err.CallTrace
-			errorMsg.SysInfo = err.SysInfo
-		}
-		json, e := json.MarshalIndent(struct {
-			Status string       `json:"status"`
-			Error  errorMessage `json:"error"`
-		}{
-			Status: "error",
-			Error:  errorMsg,
-		}, "", " ")
-		if e != nil {
-			console.Fatalln(probe.NewError(e))
-		}
-		console.Println(string(json))
+		printMsg(newErrorMessage(err, "error", msg, data...))
 		return
 	}
 	msg = fmt.Sprintf(msg, data...)
 	if !globalDebug {
-		var e error
-		if errors.Is(globalContext.Err(), context.Canceled) {
-			// mc is getting killed
-			e = errors.New("Canceling upon user request")
-		} else {
-			e = err.ToGoError()
-		}
-		console.Errorln(fmt.Sprintf("%s %s", msg, e))
+		e := commandError(globalContext, err)
+		console.Errorln(fmt.Sprintf("%s %s%s", msg, e, aliasTypoHint(e)))
 		return
 	}
 	console.Errorln(fmt.Sprintf("%s %s", msg, err))
 }
 
+// warningIf - reports an error the command recovers from, such as a
+// file copied without its attributes.
+func warningIf(err *probe.Error, msg string, data ...interface{}) {
+	if err == nil {
+		return
+	}
+	recordWarning()
+	if globalJSON {
+		printMsg(newErrorMessage(err, "warning", msg, data...))
+		return
+	}
+	msg = fmt.Sprintf(msg, data...)
+	if globalDebug {
+		console.Infoln(fmt.Sprintf("[Warn] %s %s", msg, err))
+		return
+	}
+	console.Infoln(fmt.Sprintf("[Warn] %s %s", msg, err.ToGoError()))
+}
+
 // deprecatedError function for deprecated commands
 func deprecatedError(newCommandName string) {
 	err := probe.NewError(fmt.Errorf("Please use '%s' instead", newCommandName))
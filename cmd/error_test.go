@@ -0,0 +1,84 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestNewErrorMessage(t *testing.T) {
+	e := fmt.Errorf("unable to copy: %w", &os.PathError{Op: "open", Path: "/tmp/x", Err: errors.New("permission denied")})
+	msg := newErrorMessage(probe.NewError(e), "error", "Failed to copy `%s`.", "/tmp/x")
+	if msg.Message != "Failed to copy `/tmp/x`." || msg.Type != "error" {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+	if want := []string{"open /tmp/x: permission denied", "permission denied"}; !reflect.DeepEqual(msg.Cause.Causes, want) {
+		t.Errorf("expected causes %v, got %v", want, msg.Cause.Causes)
+	}
+
+	var doc struct {
+		Status string `json:"status"`
+		Error  struct {
+			Message string `json:"message"`
+			Cause   struct {
+				Message string   `json:"message"`
+				Causes  []string `json:"causes"`
+			} `json:"cause"`
+		} `json:"error"`
+	}
+	if e := json.Unmarshal([]byte(msg.JSON()), &doc); e != nil {
+		t.Fatal(e)
+	}
+	if doc.Status != "error" || doc.Error.Cause.Message != e.Error() || len(doc.Error.Cause.Causes) != 2 {
+		t.Errorf("unexpected JSON document %+v", doc)
+	}
+}
+
+func TestCommandError(t *testing.T) {
+	timeout := globalTimeout
+	t.Cleanup(func() { globalTimeout = timeout })
+	globalTimeout = time.Minute
+
+	err := probe.NewError(errors.New("connection refused"))
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	timedOut, cancel := context.WithDeadline(context.Background(), time.Now())
+	defer cancel()
+	testCases := []struct {
+		ctx context.Context
+		msg string
+	}{
+		{context.Background(), "connection refused"},
+		{canceled, "Canceling upon user request"},
+		{timedOut, "Timed out after 1m0s (--timeout)"},
+	}
+	for i, testCase := range testCases {
+		if msg := commandError(testCase.ctx, err).Error(); msg != testCase.msg {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.msg, msg)
+		}
+	}
+}
@@ -130,7 +130,7 @@ func mainEventAdd(cliCtx *cli.Context) error {
 		fatalIf(err.Trace(), "Unable to parse the provided url.")
 	}
 
-	s3Client, ok := client.(*S3Client)
+	s3Client, ok := unwrapClient(client).(*S3Client)
 	if !ok {
 		fatalIf(errDummy().Trace(), "The provided url doesn't point to a S3 server.")
 	}
@@ -48,7 +48,7 @@ type findMessage struct {
 // String calls tells the console what to print and how to print it.
 func (f findMessage) String() string {
 	var msg string
-	msg += f.contentMessage.Key
+	msg += quoteName(f.contentMessage.Key)
 	if f.VersionID != "" {
 		msg += " (" + f.contentMessage.VersionID + ")"
 	}
@@ -494,6 +494,15 @@ func getRegexMap(cliCtx *cli.Context, key string) map[string]*regexp.Regexp {
 	return reMap
 }
 
+// matchContentTags - reports whether the tags of an object listed with
+// metadata match all the tag regexes, folders hold no tags.
+func matchContentTags(tags map[string]*regexp.Regexp, content *ClientContent) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	return !content.Type.IsDir() && matchRegexMaps(tags, content.Tags)
+}
+
 // matchRegexMaps will check if all regexes in 'm' match values in 'v' with the same key.
 // If a regex is nil, it must either not exist in v or have a 0 length value.
 func matchRegexMaps(m map[string]*regexp.Regexp, v map[string]string) bool {
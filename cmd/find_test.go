@@ -19,6 +19,7 @@ package cmd
 
 import (
 	"context"
+	"os"
 	"os/exec"
 	"regexp"
 	"runtime"
@@ -448,3 +449,29 @@ func TestGetExitStatus(t *testing.T) {
 		}
 	}
 }
+
+func TestMatchContentTags(t *testing.T) {
+	tags := map[string]*regexp.Regexp{
+		"project": regexp.MustCompile("^apollo$"),
+		"status":  nil,
+	}
+	testCases := []struct {
+		content *ClientContent
+		match   bool
+	}{
+		{&ClientContent{Tags: map[string]string{"project": "apollo"}}, true},
+		{&ClientContent{Tags: map[string]string{"project": "apollo", "status": ""}}, true},
+		{&ClientContent{Tags: map[string]string{"project": "apollo", "status": "old"}}, false},
+		{&ClientContent{Tags: map[string]string{"project": "apollo-2"}}, false},
+		{&ClientContent{}, false},
+		{&ClientContent{Type: os.ModeDir, Tags: map[string]string{"project": "apollo"}}, false},
+	}
+	for i, testCase := range testCases {
+		if got := matchContentTags(tags, testCase.content); got != testCase.match {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.match, got)
+		}
+	}
+	if !matchContentTags(nil, &ClientContent{Type: os.ModeDir}) {
+		t.Errorf("expected a match without tags")
+	}
+}
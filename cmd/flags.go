@@ -49,6 +49,11 @@ var globalFlags = []cli.Flag{
 		Usage:  "disable color theme",
 		EnvVar: envPrefix + "NO_COLOR",
 	},
+	cli.BoolFlag{
+		Name:   "force-color",
+		Usage:  "keep colors and status line updates when the output is not a terminal",
+		EnvVar: envPrefix + "FORCE_COLOR",
+	},
 	cli.BoolFlag{
 		Name:   "json",
 		Usage:  "enable JSON lines formatted output",
@@ -64,6 +69,26 @@ var globalFlags = []cli.Flag{
 		Usage:  "disable SSL certificate verification",
 		EnvVar: envPrefix + "INSECURE",
 	},
+	cli.StringFlag{
+		Name:   "cacert",
+		Usage:  "trust the CA certificates of a PEM file, with the system ones and those of the certs/CAs folder",
+		EnvVar: envPrefix + "CACERT",
+	},
+	cli.StringFlag{
+		Name:   "client-cert",
+		Usage:  "present the certificate of a PEM file to the servers requesting one, such as mTLS gateways",
+		EnvVar: envPrefix + "CLIENT_CERT",
+	},
+	cli.StringFlag{
+		Name:   "client-key",
+		Usage:  "private key of --client-cert, when not in the same PEM file",
+		EnvVar: envPrefix + "CLIENT_KEY",
+	},
+	cli.BoolFlag{
+		Name:   "strict",
+		Usage:  "exit with an error when any warning is reported",
+		EnvVar: envPrefix + "STRICT",
+	},
 	cli.StringFlag{
 		Name:   "limit-upload",
 		Usage:  "limits uploads to a maximum rate in KiB/s, MiB/s, GiB/s. (default: unlimited)",
@@ -74,15 +99,53 @@ var globalFlags = []cli.Flag{
 		Usage:  "limits downloads to a maximum rate in KiB/s, MiB/s, GiB/s. (default: unlimited)",
 		EnvVar: envPrefix + "LIMIT_DOWNLOAD",
 	},
+	cli.StringFlag{
+		Name:   "profile",
+		Usage:  "use the credentials of the named profile of the endpoints of the aliases",
+		EnvVar: envPrefix + "PROFILE",
+	},
+	cli.StringFlag{
+		Name:   "quote",
+		Usage:  "quote the object names printed: 'shell', 'c' or 'none'",
+		EnvVar: envPrefix + "QUOTE",
+	},
+	cli.StringFlag{
+		Name:   "checksum-algo",
+		Usage:  "integrity check of uploads: 'crc32c', 'md5', 'none' or 'auto' to pick the best one of the endpoint",
+		EnvVar: envPrefix + "CHECKSUM_ALGO",
+	},
+	cli.IntFlag{
+		Name:   "retries",
+		Usage:  "retry idempotent requests failing with a transient error, 0 disables",
+		EnvVar: envPrefix + "RETRIES",
+		Value:  defaultRetries,
+	},
+	cli.BoolFlag{
+		Name:   "adjust-clock-skew",
+		Usage:  "sign requests with the server time when the local clock is skewed",
+		EnvVar: envPrefix + "ADJUST_CLOCK_SKEW",
+	},
+	cli.DurationFlag{
+		Name:   "timeout",
+		Usage:  "fail the command when it runs longer than the specified duration, e.g. 30m",
+		EnvVar: envPrefix + "TIMEOUT",
+	},
+	cli.DurationFlag{
+		Name:   "idle-timeout",
+		Usage:  "fail operations when no data is sent or received for the specified duration, e.g. 1m",
+		EnvVar: envPrefix + "IDLE_TIMEOUT",
+	},
 	cli.DurationFlag{
 		Name:   "conn-read-deadline",
 		Usage:  "custom connection READ deadline",
+		EnvVar: envPrefix + "CONN_READ_DEADLINE",
 		Hidden: true,
 		Value:  10 * time.Minute,
 	},
 	cli.DurationFlag{
 		Name:   "conn-write-deadline",
 		Usage:  "custom connection WRITE deadline",
+		EnvVar: envPrefix + "CONN_WRITE_DEADLINE",
 		Hidden: true,
 		Value:  10 * time.Minute,
 	},
@@ -101,3 +164,23 @@ var ioFlags = []cli.Flag{
 		EnvVar: envPrefix + "ENCRYPT",
 	},
 }
+
+// Flags of the multipart uploads of cp and mirror.
+var multipartFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:   "part-size",
+		Usage:  "size of each part of multipart uploads, between 5MiB and 5GiB",
+		EnvVar: envPrefix + "UPLOAD_MULTIPART_SIZE",
+	},
+	cli.IntFlag{
+		Name:   "parallel-parts",
+		Usage:  "number of parts of a multipart upload uploaded in parallel",
+		EnvVar: envPrefix + "UPLOAD_MULTIPART_THREADS",
+		Value:  4,
+	},
+	cli.StringFlag{
+		Name:   "multipart-threshold",
+		Usage:  "upload the objects smaller than this size, up to 5GiB, in a single request instead of in parts",
+		EnvVar: envPrefix + "MULTIPART_THRESHOLD",
+	},
+}
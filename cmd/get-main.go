@@ -27,7 +27,7 @@ import (
 
 // get command flags.
 var (
-	getFlags = []cli.Flag{}
+	getFlags = append(downloadScanFlags, downloadSparseFlags...)
 )
 
 // Get command.
@@ -54,6 +54,12 @@ ENVIRONMENT VARIABLES:
 EXAMPLES:
   1. Get an object from S3 storage to local file system 
     {{.Prompt}} {{.HelpName}} ALIAS/BUCKET/object path-to/object 
+
+  2. Get an object, scanned by an ICAP antivirus server before it is saved
+    {{.Prompt}} {{.HelpName}} --scan-icap icap://127.0.0.1:1344/avscan ALIAS/BUCKET/object path-to/object
+
+  3. Get a disk image again, downloading only its parts changed since the last download and leaving holes in place of its blocks of zeros
+    {{.Prompt}} {{.HelpName}} --sparse --skip-matching-parts ALIAS/BUCKET/disk.img path-to/disk.img
 `,
 }
 
@@ -65,6 +71,9 @@ func mainGet(cliCtx *cli.Context) (e error) {
 	encKeyDB, err := getEncKeys(cliCtx)
 	fatalIf(err, "Unable to parse encryption keys.")
 
+	scanner, err := newDownloadScanner(cliCtx)
+	fatalIf(err, "Unable to scan the downloads.")
+
 	args := cliCtx.Args()
 	if len(args) != 2 {
 		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code.
@@ -80,7 +89,7 @@ func mainGet(cliCtx *cli.Context) (e error) {
 	// Store a progress bar or an accounter
 	var pg ProgressReader
 	// Enable progress bar reader only during default mode.
-	if !globalQuiet && !globalJSON { // set up progress bar
+	if isProgressBarEnabled() { // set up progress bar
 		pg = newProgressBar(totalBytes)
 	} else {
 		pg = newAccounter(totalBytes)
@@ -123,6 +132,9 @@ func mainGet(cliCtx *cli.Context) (e error) {
 				pg:                  pg,
 				encKeyDB:            encKeyDB,
 				updateProgressTotal: true,
+				scanner:             scanner,
+				sparse:              cliCtx.Bool("sparse"),
+				skipMatchingParts:   cliCtx.Bool("skip-matching-parts"),
 			})
 			if urls.Error != nil {
 				e = urls.Error.ToGoError()
@@ -136,7 +148,7 @@ func mainGet(cliCtx *cli.Context) (e error) {
 func printGetURLsError(cpURLs *URLs) {
 	// Print in new line and adjust to top so that we
 	// don't print over the ongoing scan bar
-	if !globalQuiet && !globalJSON {
+	if isProgressBarEnabled() {
 		console.Eraseline()
 	}
 
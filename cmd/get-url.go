@@ -80,7 +80,7 @@ func guessGetURLType(ctx context.Context, o prepareCopyURLsOpts) (*copyURLsConte
 			cc.copyType = copyURLsTypeInvalid
 			return cc, err
 		}
-		s3clnt, ok := client.(*S3Client)
+		s3clnt, ok := unwrapClient(client).(*S3Client)
 		if !ok {
 			return cc, probe.NewError(fmt.Errorf("Source is not s3."))
 		}
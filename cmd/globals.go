@@ -20,15 +20,19 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
 	"crypto/x509"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dustin/go-humanize"
 	"github.com/minio/cli"
 	"github.com/minio/madmin-go/v3"
+	"github.com/minio/minio-go/v7"
 	"github.com/minio/pkg/v2/console"
+	"github.com/minio/pkg/v2/env"
 	"github.com/muesli/termenv"
 )
 
@@ -44,6 +48,9 @@ const (
 	globalSharedURLsDataDir    = "share"
 	globalSessionConfigVersion = "8"
 
+	// traffic of the aliases by month
+	globalUsageLedgerFile = "usage.json"
+
 	// Profile directory for dumping profiler outputs.
 	globalProfileDir = "profile"
 
@@ -61,23 +68,39 @@ const (
 )
 
 var (
-	globalQuiet          = false               // Quiet flag set via command line
-	globalJSON           = false               // Json flag set via command line
-	globalJSONLine       = false               // Print json as single line.
-	globalDebug          = false               // Debug flag set via command line
-	globalNoColor        = false               // No Color flag set via command line
-	globalInsecure       = false               // Insecure flag set via command line
-	globalDevMode        = false               // dev flag set via command line
-	globalAirgapped      = false               // Airgapped flag set via command line
-	globalSubnetProxyURL *url.URL              // Proxy to be used for communication with subnet
-	globalSubnetConfig   []madmin.SubsysConfig // Subnet config
+	globalQuiet      = false // Quiet flag set via command line
+	globalJSON       = false // Json flag set via command line
+	globalJSONLine   = false // Print json as single line.
+	globalDebug      = false // Debug flag set via command line
+	globalNoColor    = false // No Color flag set via command line
+	globalForceColor = false // Force color flag set via command line
+	globalInsecure   = false // Insecure flag set via command line
+	globalDevMode    = false // dev flag set via command line
+	globalAirgapped  = false // Airgapped flag set via command line
+	globalStrict     = false // Strict flag set via command line
+	globalDryRun     = false // Dry run flag set before the command
+
+	globalAdjustClockSkew = false               // Adjust clock skew flag set via command line
+	globalSubnetProxyURL  *url.URL              // Proxy to be used for communication with subnet
+	globalSubnetConfig    []madmin.SubsysConfig // Subnet config
 
 	globalConnReadDeadline  time.Duration
 	globalConnWriteDeadline time.Duration
 
+	globalTimeout     time.Duration // Deadline of the command, zero when not set
+	globalIdleTimeout time.Duration // Deadline of idle connections, zero when not set
+
 	globalLimitUpload   uint64
 	globalLimitDownload uint64
 
+	globalRetries = -1 // Retries of transient errors, -1 when not set via command line
+
+	globalProfile string // Credential profile of the aliases set via command line
+
+	globalQuote = quoteNone // Quoting style of the object names printed, set via command line
+
+	globalChecksumAlgo = checksumAlgoAuto // Integrity check of uploads, set via command line
+
 	globalContext, globalCancel = context.WithCancel(context.Background())
 )
 
@@ -91,6 +114,14 @@ var (
 
 	// CA root certificates, a nil value means system certs pool will be used
 	globalRootCAs *x509.CertPool
+
+	// TLS files set via command line, see loadRootCAs and loadClientCertificate
+	globalCACertFile     string
+	globalClientCertFile string
+	globalClientKeyFile  string
+
+	// Client certificate presented to the servers requesting one
+	globalClientCertificates []tls.Certificate
 )
 
 func parsePagerDisableFlag(args []string) {
@@ -101,40 +132,170 @@ func parsePagerDisableFlag(args []string) {
 	}
 }
 
+// parseTimeoutFlag returns the --timeout of args, or else of MC_TIMEOUT, so
+// that the deadline of the command starts before it runs. The flag is parsed
+// again with the other flags, which reports invalid values.
+func parseTimeoutFlag(args []string) time.Duration {
+	value := env.Get(envPrefix+"TIMEOUT", "")
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name, v, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "timeout" {
+			continue
+		}
+		if !hasValue {
+			v = ""
+			if i+1 < len(args) {
+				v = args[i+1]
+			}
+		}
+		value = v
+	}
+	timeout, e := time.ParseDuration(value)
+	if e != nil || timeout < 0 {
+		return 0
+	}
+	return timeout
+}
+
+// connDeadlinesFromContext returns the read and write deadlines of the
+// connections and the idle timeout. --idle-timeout sets both deadlines,
+// unless they are set explicitly by --conn-read-deadline and
+// --conn-write-deadline.
+func connDeadlinesFromContext(ctx *cli.Context) (read, write, idle time.Duration) {
+	duration := func(name string) (time.Duration, bool) {
+		switch {
+		case ctx.IsSet(name):
+			return ctx.Duration(name), true
+		case ctx.GlobalIsSet(name):
+			return ctx.GlobalDuration(name), true
+		}
+		if d := ctx.Duration(name); d > 0 {
+			return d, false
+		}
+		return ctx.GlobalDuration(name), false
+	}
+	read, readSet := duration("conn-read-deadline")
+	write, writeSet := duration("conn-write-deadline")
+	if idle, _ = duration("idle-timeout"); idle > 0 {
+		if !readSet {
+			read = idle
+		}
+		if !writeSet {
+			write = idle
+		}
+	}
+	return read, write, idle
+}
+
+// stringFromContext returns the string flag of the command, or else the
+// global one, def when neither is set.
+func stringFromContext(ctx *cli.Context, name, def string) string {
+	if v := ctx.String(name); v != "" {
+		return v
+	}
+	if v := ctx.GlobalString(name); v != "" {
+		return v
+	}
+	return def
+}
+
+// isDryRun returns true when the mutating command of ctx should only
+// report its operations, with its own --dry-run or the global one.
+func isDryRun(ctx *cli.Context) bool {
+	return globalDryRun || ctx.Bool("dry-run")
+}
+
+// parseRateLimit parses a rate such as 10MiB/s or 500KiB, in bytes per second.
+func parseRateLimit(rate string) (uint64, error) {
+	rate = strings.TrimSpace(rate)
+	if strings.HasSuffix(strings.ToLower(rate), "/s") {
+		rate = rate[:len(rate)-2]
+	}
+	return humanize.ParseBytes(rate)
+}
+
 // Set global states. NOTE: It is deliberately kept monolithic to ensure we dont miss out any flags.
 func setGlobalsFromContext(ctx *cli.Context) error {
 	quiet := ctx.IsSet("quiet") || ctx.GlobalIsSet("quiet")
 	debug := ctx.IsSet("debug") || ctx.GlobalIsSet("debug")
 	json := ctx.IsSet("json") || ctx.GlobalIsSet("json")
 	noColor := ctx.IsSet("no-color") || ctx.GlobalIsSet("no-color")
+	forceColor := ctx.IsSet("force-color") || ctx.GlobalIsSet("force-color")
 	insecure := ctx.IsSet("insecure") || ctx.GlobalIsSet("insecure")
 	devMode := ctx.IsSet("dev") || ctx.GlobalIsSet("dev")
 	airgapped := ctx.IsSet("airgap") || ctx.GlobalIsSet("airgap")
+	strict := ctx.IsSet("strict") || ctx.GlobalIsSet("strict")
+	dryRun := ctx.GlobalBool("dry-run")
+	adjustClockSkew := ctx.IsSet("adjust-clock-skew") || ctx.GlobalIsSet("adjust-clock-skew")
 
 	globalQuiet = globalQuiet || quiet
 	globalDebug = globalDebug || debug
 	globalJSONLine = !isTerminal() && json
 	globalJSON = globalJSON || json
 	globalNoColor = globalNoColor || noColor || globalJSONLine
+	globalForceColor = globalForceColor || forceColor
 	globalInsecure = globalInsecure || insecure
 	globalDevMode = globalDevMode || devMode
 	globalAirgapped = globalAirgapped || airgapped
+	globalStrict = globalStrict || strict
+	globalDryRun = globalDryRun || dryRun
+	globalAdjustClockSkew = globalAdjustClockSkew || adjustClockSkew
 
 	// Disable colorified messages if requested.
 	if globalNoColor || globalQuiet {
 		console.SetColorOff()
 		lipgloss.SetColorProfile(termenv.Ascii)
+	} else if globalForceColor {
+		// Colors are otherwise disabled when the output is not a terminal.
+		console.SetColorOn()
+		lipgloss.SetColorProfile(termenv.ANSI256)
+	}
+
+	globalConnReadDeadline, globalConnWriteDeadline, globalIdleTimeout = connDeadlinesFromContext(ctx)
+
+	if ctx.IsSet("profile") {
+		globalProfile = ctx.String("profile")
+	} else if ctx.GlobalIsSet("profile") {
+		globalProfile = ctx.GlobalString("profile")
 	}
 
-	globalConnReadDeadline = ctx.Duration("conn-read-deadline")
-	if globalConnReadDeadline <= 0 {
-		globalConnReadDeadline = ctx.GlobalDuration("conn-read-deadline")
+	globalCACertFile = stringFromContext(ctx, "cacert", globalCACertFile)
+	globalClientCertFile = stringFromContext(ctx, "client-cert", globalClientCertFile)
+	globalClientKeyFile = stringFromContext(ctx, "client-key", globalClientKeyFile)
+
+	quote := ctx.String("quote")
+	if quote == "" {
+		quote = ctx.GlobalString("quote")
+	}
+	if quote != "" {
+		var e error
+		if globalQuote, e = parseQuoteStyle(quote); e != nil {
+			return e
+		}
+	}
+
+	checksumAlgo := ctx.String("checksum-algo")
+	if checksumAlgo == "" {
+		checksumAlgo = ctx.GlobalString("checksum-algo")
+	}
+	if checksumAlgo != "" {
+		var e error
+		if globalChecksumAlgo, e = parseChecksumAlgo(checksumAlgo); e != nil {
+			return e
+		}
 	}
 
-	globalConnWriteDeadline = ctx.Duration("conn-write-deadline")
-	if globalConnWriteDeadline <= 0 {
-		globalConnWriteDeadline = ctx.GlobalDuration("conn-write-deadline")
+	if ctx.IsSet("retries") {
+		globalRetries = ctx.Int("retries")
+	} else if ctx.GlobalIsSet("retries") {
+		globalRetries = ctx.GlobalInt("retries")
 	}
+	// S3 requests are retried by minio-go, --retries counts the retries
+	// after the first attempt.
+	minio.MaxRetry = getRetries() + 1
 
 	limitUploadStr := ctx.String("limit-upload")
 	if limitUploadStr == "" {
@@ -142,7 +303,7 @@ func setGlobalsFromContext(ctx *cli.Context) error {
 	}
 	if limitUploadStr != "" {
 		var e error
-		globalLimitUpload, e = humanize.ParseBytes(limitUploadStr)
+		globalLimitUpload, e = parseRateLimit(limitUploadStr)
 		if e != nil {
 			return e
 		}
@@ -155,7 +316,7 @@ func setGlobalsFromContext(ctx *cli.Context) error {
 
 	if limitDownloadStr != "" {
 		var e error
-		globalLimitDownload, e = humanize.ParseBytes(limitDownloadStr)
+		globalLimitDownload, e = parseRateLimit(limitDownloadStr)
 		if e != nil {
 			return e
 		}
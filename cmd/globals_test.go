@@ -0,0 +1,143 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/cli"
+)
+
+func TestParseTimeoutFlag(t *testing.T) {
+	testCases := []struct {
+		env     string
+		args    []string
+		timeout time.Duration
+	}{
+		{"", []string{"ls", "play"}, 0},
+		{"", []string{"--timeout", "30m", "ls", "play"}, 30 * time.Minute},
+		{"", []string{"ls", "--timeout=1h", "play"}, time.Hour},
+		{"", []string{"cp", "-timeout", "5s", "a", "b"}, 5 * time.Second},
+		{"10s", []string{"ls", "play"}, 10 * time.Second},
+		{"10s", []string{"ls", "--timeout", "20s", "play"}, 20 * time.Second},
+		{"", []string{"ls", "--timeout", "soon"}, 0},
+		{"", []string{"ls", "--timeout"}, 0},
+		{"", []string{"cat", "--", "--timeout", "1s"}, 0},
+	}
+	for i, testCase := range testCases {
+		t.Setenv(envPrefix+"TIMEOUT", testCase.env)
+		if timeout := parseTimeoutFlag(testCase.args); timeout != testCase.timeout {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.timeout, timeout)
+		}
+	}
+}
+
+func TestConnDeadlinesFromContext(t *testing.T) {
+	testCases := []struct {
+		args              []string
+		read, write, idle time.Duration
+	}{
+		{[]string{"mc", "test"}, 10 * time.Minute, 10 * time.Minute, 0},
+		{[]string{"mc", "test", "--idle-timeout", "1m"}, time.Minute, time.Minute, time.Minute},
+		{[]string{"mc", "--idle-timeout", "1m", "test"}, time.Minute, time.Minute, time.Minute},
+		// Explicit deadlines are kept.
+		{[]string{"mc", "test", "--idle-timeout", "1m", "--conn-read-deadline", "5m"}, 5 * time.Minute, time.Minute, time.Minute},
+		{[]string{"mc", "--conn-write-deadline", "2m", "test", "--idle-timeout", "1m"}, time.Minute, 2 * time.Minute, time.Minute},
+		{[]string{"mc", "--conn-read-deadline", "3m", "test"}, 3 * time.Minute, 10 * time.Minute, 0},
+	}
+	for i, testCase := range testCases {
+		var read, write, idle time.Duration
+		app := cli.NewApp()
+		app.Flags = globalFlags
+		app.Commands = []cli.Command{{
+			Name:  "test",
+			Flags: globalFlags,
+			Action: func(ctx *cli.Context) error {
+				read, write, idle = connDeadlinesFromContext(ctx)
+				return nil
+			},
+		}}
+		if e := app.Run(testCase.args); e != nil {
+			t.Fatal(e)
+		}
+		if read != testCase.read || write != testCase.write || idle != testCase.idle {
+			t.Errorf("Test %d: expected %s/%s/%s, got %s/%s/%s", i+1,
+				testCase.read, testCase.write, testCase.idle, read, write, idle)
+		}
+	}
+}
+
+func TestIsStatusLineEnabled(t *testing.T) {
+	terminal, forceColor, jsonOutput := isTerminal, globalForceColor, globalJSON
+	t.Cleanup(func() { isTerminal, globalForceColor, globalJSON = terminal, forceColor, jsonOutput })
+
+	testCases := []struct {
+		terminal, forceColor, json bool
+		enabled                    bool
+	}{
+		{true, false, false, true},
+		{false, false, false, false},
+		{false, true, false, true},
+		{true, true, true, false},
+	}
+	for i, testCase := range testCases {
+		isTerminal = func() bool { return testCase.terminal }
+		globalForceColor, globalJSON = testCase.forceColor, testCase.json
+		if enabled := isStatusLineEnabled(); enabled != testCase.enabled {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.enabled, enabled)
+		}
+	}
+}
+
+func TestIsDryRun(t *testing.T) {
+	dryRun := globalDryRun
+	t.Cleanup(func() { globalDryRun = dryRun })
+
+	testCases := []struct {
+		env    string
+		args   []string
+		dryRun bool
+	}{
+		{"", []string{"mc", "test"}, false},
+		{"", []string{"mc", "test", "--dry-run"}, true},
+		{"", []string{"mc", "--dry-run", "test"}, true},
+		{"true", []string{"mc", "test"}, true},
+	}
+	for i, testCase := range testCases {
+		t.Setenv(envPrefix+"DRY_RUN", testCase.env)
+		var dryRun bool
+		app := cli.NewApp()
+		app.Flags = mcFlags
+		app.Commands = []cli.Command{{
+			Name:  "test",
+			Flags: []cli.Flag{cli.BoolFlag{Name: "dry-run"}},
+			Action: func(ctx *cli.Context) error {
+				globalDryRun = ctx.GlobalBool("dry-run")
+				dryRun = isDryRun(ctx)
+				return nil
+			},
+		}}
+		if e := app.Run(testCase.args); e != nil {
+			t.Fatal(e)
+		}
+		if dryRun != testCase.dryRun {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.dryRun, dryRun)
+		}
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"compress/bzip2"
 	"compress/gzip"
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"strings"
@@ -38,6 +39,10 @@ var headFlags = []cli.Flag{
 		Usage: "print the first 'n' lines",
 		Value: 10,
 	},
+	cli.Int64Flag{
+		Name:  "c,bytes",
+		Usage: "print the first 'c' bytes instead of lines",
+	},
 	cli.StringFlag{
 		Name:  "rewind",
 		Usage: "select an object version at specified time",
@@ -55,7 +60,7 @@ var headFlags = []cli.Flag{
 // Display contents of a file.
 var headCmd = cli.Command{
 	Name:         "head",
-	Usage:        "display first 'n' lines of an object",
+	Usage:        "display first 'n' lines or bytes of an object",
 	Action:       mainHead,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
@@ -74,6 +79,9 @@ ENVIRONMENT VARIABLES:
 
 NOTE:
   '{{.HelpName}}' automatically decompresses 'gzip', 'bzip2' compressed objects.
+  Objects are read with ranged requests of growing sizes, only about the part
+  displayed is downloaded. Each object is preceded by its name when several are
+  displayed.
 
 EXAMPLES:
   1. Display only first line from a 'gzip' compressed object on Amazon S3.
@@ -88,19 +96,126 @@ EXAMPLES:
 
   4. Display the first lines of a specific object version.
      {{.Prompt}} {{.HelpName}} --version-id "3ddac055-89a7-40fa-8cd3-530a5581b6b8" s3/json-data/population.json
+
+  5. Display the header lines of several CSV objects.
+     {{.Prompt}} {{.HelpName}} -n 1 s3/csv-data/2023.csv s3/csv-data/2024.csv
+
+  6. Display the first 512 bytes of a large log object, downloading only them.
+     {{.Prompt}} {{.HelpName}} -c 512 s3/logs/app.log
 `,
 }
 
+// Sizes of the ranged reads of head, doubled from the first one as long
+// as more of the object is needed.
+const (
+	headFirstRead = 64 << 10
+	headMaxRead   = 8 << 20
+)
+
+// headReader - reads an object of size bytes with ranged requests of
+// growing sizes, so that only about the part displayed is downloaded.
+type headReader struct {
+	ctx    context.Context
+	clnt   Client
+	opts   GetOptions
+	size   int64
+	offset int64
+	next   int64 // Size of the next ranged read
+	cur    io.ReadCloser
+}
+
+func (r *headReader) Read(p []byte) (int, error) {
+	for {
+		if r.cur == nil {
+			if r.offset >= r.size {
+				return 0, io.EOF
+			}
+			opts := r.opts
+			opts.RangeStart, opts.RangeLength = r.offset, r.next
+			if opts.RangeLength > r.size-r.offset {
+				opts.RangeLength = r.size - r.offset
+			}
+			reader, _, err := r.clnt.Get(r.ctx, opts)
+			if err != nil {
+				return 0, err.ToGoError()
+			}
+			r.cur = reader
+			r.offset += opts.RangeLength
+			if r.next < headMaxRead {
+				r.next *= 2
+			}
+		}
+		n, e := r.cur.Read(p)
+		if e == io.EOF {
+			r.cur.Close()
+			r.cur = nil
+			if n == 0 {
+				continue
+			}
+			e = nil
+		}
+		return n, e
+	}
+}
+
+func (r *headReader) Close() error {
+	if r.cur == nil {
+		return nil
+	}
+	return r.cur.Close()
+}
+
+// getHeadStream - returns a reader of the object at aliasedURL, whose
+// first ranged read is of first bytes.
+func getHeadStream(ctx context.Context, aliasedURL, versionID string, timeRef time.Time, encKeyDB map[string][]prefixSSEPair, zip bool, first int64) (io.ReadCloser, *ClientContent, *probe.Error) {
+	// Files are extracted from zip files read up to their end.
+	if zip {
+		return getSourceStreamMetadataFromURL(ctx, aliasedURL, versionID, timeRef, encKeyDB, zip)
+	}
+	alias, urlStrFull, _, err := expandAlias(aliasedURL)
+	if err != nil {
+		return nil, nil, err.Trace(aliasedURL)
+	}
+	if !timeRef.IsZero() {
+		_, content, err := url2Stat(ctx, url2StatOptions{urlStr: aliasedURL, timeRef: timeRef})
+		if err != nil {
+			return nil, nil, err
+		}
+		versionID = content.VersionID
+	}
+	clnt, err := newClientFromAlias(alias, urlStrFull)
+	if err != nil {
+		return nil, nil, err.Trace(aliasedURL)
+	}
+	opts := GetOptions{SSE: getSSE(aliasedURL, encKeyDB[alias]), VersionID: versionID}
+	content, err := clnt.Stat(ctx, StatOptions{sse: opts.SSE, versionID: versionID})
+	if err != nil {
+		return nil, nil, err.Trace(aliasedURL)
+	}
+	if content.Type.IsDir() {
+		return nil, nil, errSourceIsDir(aliasedURL).Trace(aliasedURL)
+	}
+	// The ranges are read from the version stat.
+	opts.VersionID = content.VersionID
+	return &headReader{ctx: ctx, clnt: clnt, opts: opts, size: content.Size, next: first}, content, nil
+}
+
 // headURL displays contents of a URL to stdout.
-func headURL(sourceURL, sourceVersion string, timeRef time.Time, encKeyDB map[string][]prefixSSEPair, nlines int64, zip bool) *probe.Error {
+func headURL(stdout io.Writer, sourceURL, sourceVersion string, timeRef time.Time, encKeyDB map[string][]prefixSSEPair, nlines, nbytes int64, zip bool) *probe.Error {
 	var reader io.ReadCloser
 	switch sourceURL {
 	case "-":
 		reader = os.Stdin
 	default:
+		// The bytes displayed are read with a single request,
+		// unless the object is compressed.
+		first := int64(headFirstRead)
+		if nbytes > 0 {
+			first = nbytes
+		}
 		var err *probe.Error
 		var content *ClientContent
-		if reader, content, err = getSourceStreamMetadataFromURL(context.Background(), sourceURL, sourceVersion, timeRef, encKeyDB, zip); err != nil {
+		if reader, content, err = getHeadStream(context.Background(), sourceURL, sourceVersion, timeRef, encKeyDB, zip, first); err != nil {
 			return err.Trace(sourceURL)
 		}
 
@@ -119,21 +234,15 @@ func headURL(sourceURL, sourceVersion string, timeRef time.Time, encKeyDB map[st
 			defer reader.Close()
 		}
 	}
-	return headOut(reader, nlines).Trace(sourceURL)
+	return headOut(stdout, reader, nlines, nbytes).Trace(sourceURL)
 }
 
-// headOut reads from reader stream and writes to stdout. Also check the length of the
-// read bytes against size parameter (if not -1) and return the appropriate error
-func headOut(r io.Reader, nlines int64) *probe.Error {
-	var stdout io.Writer
-
-	// In case of a user showing the object content in a terminal,
-	// avoid printing control and other bad characters to avoid
-	// terminal session corruption
-	if isTerminal() {
-		stdout = newPrettyStdout(os.Stdout)
-	} else {
-		stdout = os.Stdout
+// headOut reads from reader stream and writes to stdout its first nlines
+// lines, or its first nbytes bytes when positive.
+func headOut(stdout io.Writer, r io.Reader, nlines, nbytes int64) *probe.Error {
+	if nbytes > 0 {
+		_, e := io.Copy(stdout, io.LimitReader(r, nbytes))
+		return headWriteError(e)
 	}
 
 	// Initialize a new scanner.
@@ -144,18 +253,9 @@ func headOut(r io.Reader, nlines int64) *probe.Error {
 		nlines = 10
 	}
 
-	for scn.Scan() && nlines > 0 {
+	for nlines > 0 && scn.Scan() {
 		if _, e := stdout.Write(scn.Bytes()); e != nil {
-			switch e := e.(type) {
-			case *os.PathError:
-				if e.Err == syscall.EPIPE {
-					// stdout closed by the user. Gracefully exit.
-					return nil
-				}
-				return probe.NewError(e)
-			default:
-				return probe.NewError(e)
-			}
+			return headWriteError(e)
 		}
 		stdout.Write([]byte("\n"))
 		nlines--
@@ -166,6 +266,43 @@ func headOut(r io.Reader, nlines int64) *probe.Error {
 	return nil
 }
 
+// headStdout - returns the writer of the displayed content.
+func headStdout() io.Writer {
+	// In case of a user showing the object content in a terminal,
+	// avoid printing control and other bad characters to avoid
+	// terminal session corruption
+	if isTerminal() {
+		return newPrettyStdout(os.Stdout)
+	}
+	return os.Stdout
+}
+
+// writeHeadName - writes the name preceding the content of an object when
+// several are displayed, like GNU head, separated by an empty line from
+// the content of the previous object.
+func writeHeadName(stdout io.Writer, name string, first bool) *probe.Error {
+	if !first {
+		if _, e := io.WriteString(stdout, "\n"); e != nil {
+			return headWriteError(e)
+		}
+	}
+	_, e := fmt.Fprintf(stdout, "==> %s <==\n", name)
+	return headWriteError(e)
+}
+
+// headWriteError - returns the error of a write to stdout, none when
+// stdout was closed by the user.
+func headWriteError(e error) *probe.Error {
+	if e == nil {
+		return nil
+	}
+	if e, ok := e.(*os.PathError); ok && e.Err == syscall.EPIPE {
+		// stdout closed by the user. Gracefully exit.
+		return nil
+	}
+	return probe.NewError(e)
+}
+
 // parseHeadSyntax performs command-line input validation for head command.
 func parseHeadSyntax(ctx *cli.Context) (args []string, versionID string, timeRef time.Time) {
 	args = ctx.Args()
@@ -181,6 +318,14 @@ func parseHeadSyntax(ctx *cli.Context) (args []string, versionID string, timeRef
 		fatalIf(errInvalidArgument().Trace(), "You need to pass at least one argument if --version-id is specified")
 	}
 
+	if ctx.Int64("bytes") < 0 {
+		fatalIf(errInvalidArgument().Trace(), "You cannot specify a negative --bytes")
+	}
+
+	if ctx.IsSet("bytes") && ctx.IsSet("lines") {
+		fatalIf(errInvalidArgument().Trace(), "You cannot specify --bytes and --lines at the same time")
+	}
+
 	timeRef = parseRewindFlag(rewind)
 	return
 }
@@ -194,16 +339,21 @@ func mainHead(ctx *cli.Context) error {
 	args, versionID, timeRef := parseHeadSyntax(ctx)
 
 	stdinMode := len(args) == 0
+	stdout := headStdout()
 
 	// handle std input data.
 	if stdinMode {
-		fatalIf(headOut(os.Stdin, ctx.Int64("lines")).Trace(), "Unable to read from standard input.")
+		fatalIf(headOut(stdout, os.Stdin, ctx.Int64("lines"), ctx.Int64("bytes")).Trace(), "Unable to read from standard input.")
 		return nil
 	}
 
 	// Convert arguments to URLs: expand alias, fix format.
-	for _, url := range ctx.Args() {
-		fatalIf(headURL(url, versionID, timeRef, encKeyDB, ctx.Int64("lines"), ctx.Bool("zip")).Trace(url), "Unable to read from `"+url+"`.")
+	for i, url := range args {
+		// The names would corrupt the output of --json.
+		if len(args) > 1 && !globalJSON {
+			fatalIf(writeHeadName(stdout, url, i == 0).Trace(url), "Unable to write to standard output.")
+		}
+		fatalIf(headURL(stdout, url, versionID, timeRef, encKeyDB, ctx.Int64("lines"), ctx.Int64("bytes"), ctx.Bool("zip")).Trace(url), "Unable to read from `"+url+"`.")
 	}
 
 	return nil
@@ -0,0 +1,75 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestHeadReader(t *testing.T) {
+	object := make([]byte, 300<<10)
+	for i := range object {
+		object[i] = byte(i % 251)
+	}
+	clnt := &fakeRangeClient{data: object}
+	r := &headReader{ctx: globalContext, clnt: clnt, size: int64(len(object)), next: headFirstRead}
+
+	// Only the first range is read for the first bytes.
+	head := make([]byte, 100)
+	if _, e := io.ReadFull(r, head); e != nil {
+		t.Fatal(e)
+	}
+	if expected := [][2]int64{{0, 64 << 10}}; !reflect.DeepEqual(clnt.ranges, expected) {
+		t.Fatalf("expected ranges %v, got %v", expected, clnt.ranges)
+	}
+
+	rest, e := io.ReadAll(r)
+	if e != nil {
+		t.Fatal(e)
+	}
+	r.Close()
+	if !bytes.Equal(append(head, rest...), object) {
+		t.Fatal("the object read differs")
+	}
+	expected := [][2]int64{{0, 64 << 10}, {64 << 10, 128 << 10}, {192 << 10, 108 << 10}}
+	if !reflect.DeepEqual(clnt.ranges, expected) {
+		t.Fatalf("expected ranges %v, got %v", expected, clnt.ranges)
+	}
+}
+
+func TestHeadOut(t *testing.T) {
+	var out bytes.Buffer
+	for i, name := range []string{"s3/bucket/a.csv", "s3/bucket/b.csv"} {
+		if err := writeHeadName(&out, name, i == 0); err != nil {
+			t.Fatal(err)
+		}
+		if err := headOut(&out, bytes.NewReader([]byte("1\n2\n3\n")), 2, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := headOut(&out, bytes.NewReader([]byte("abcdef")), 10, 4); err != nil {
+		t.Fatal(err)
+	}
+	expected := "==> s3/bucket/a.csv <==\n1\n2\n\n==> s3/bucket/b.csv <==\n1\n2\nabcd"
+	if out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}
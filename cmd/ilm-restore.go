@@ -225,7 +225,7 @@ var dotCycle = 0
 
 // Clear and print text in the same line
 func printStatus(msg string, args ...interface{}) {
-	if globalJSON {
+	if !isStatusLineEnabled() {
 		return
 	}
 
@@ -235,6 +235,46 @@ func printStatus(msg string, args ...interface{}) {
 	fmt.Printf(msg+string(dots), args...)
 }
 
+// ilmRestoreRequestsMessage - restore requests sent.
+type ilmRestoreRequestsMessage struct {
+	Status string `json:"status"`
+	Sent   int    `json:"sent"`
+}
+
+func (m ilmRestoreRequestsMessage) String() string {
+	return fmt.Sprintf("Sent restore requests to %d object(s)", m.Sent)
+}
+
+func (m ilmRestoreRequestsMessage) JSON() string {
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// ilmRestoreMessage - objects restored out of the restore requests sent.
+type ilmRestoreMessage struct {
+	Status   string `json:"status"`
+	Sent     int    `json:"sent"`
+	Restored int    `json:"restored"`
+}
+
+func (m ilmRestoreMessage) String() string {
+	return fmt.Sprintf("%d/%d object(s) successfully restored", m.Restored, m.Sent)
+}
+
+func (m ilmRestoreMessage) JSON() string {
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// clearStatus - clears the line printed by printStatus.
+func clearStatus() {
+	if isStatusLineEnabled() {
+		fmt.Print("\r\033[K")
+	}
+}
+
 // Receive restore request & restore finished status and print in the console
 func showRestoreStatus(restoreReqStatus, restoreFinishedStatus chan *probe.Error, doneCh chan struct{}) {
 	var sent, finished int
@@ -260,9 +300,8 @@ func showRestoreStatus(restoreReqStatus, restoreFinishedStatus chan *probe.Error
 		printStatus("Sent restore requests to %d object(s)", sent)
 	}
 
-	if !globalJSON {
-		fmt.Println("")
-	}
+	clearStatus()
+	printMsg(ilmRestoreRequestsMessage{Status: "success", Sent: sent})
 
 	done = false
 
@@ -282,17 +321,8 @@ func showRestoreStatus(restoreReqStatus, restoreFinishedStatus chan *probe.Error
 		printStatus("%d/%d object(s) successfully restored", finished, sent)
 	}
 
-	if !globalJSON {
-		fmt.Println("")
-	} else {
-		type ilmRestore struct {
-			Status   string `json:"status"`
-			Restored int    `json:"restored"`
-		}
-
-		msgBytes, _ := json.Marshal(ilmRestore{Status: "success", Restored: sent})
-		fmt.Println(string(msgBytes))
-	}
+	clearStatus()
+	printMsg(ilmRestoreMessage{Status: "success", Sent: sent, Restored: finished})
 
 	close(doneCh)
 }
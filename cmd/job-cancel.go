@@ -0,0 +1,92 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+var jobCancelCmd = cli.Command{
+	Name:         "cancel",
+	Usage:        "cancel a queued or running job",
+	Action:       mainJobCancel,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] NAME
+
+  The command of the job is interrupted like with ctrl-c, the session of a
+  'cp --continue' is kept to be resumed.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Cancel the job "backup".
+     {{.Prompt}} {{.HelpName}} backup
+`,
+}
+
+// cancelJob - marks the job name canceled for its runner, then stops its
+// command when started.
+func cancelJob(name string) *probe.Error {
+	job, err := loadJob(name)
+	if err != nil {
+		return err.Trace()
+	}
+	if job.finished() {
+		return probe.NewError(fmt.Errorf("the job `%s` is already %s", name, job.Status))
+	}
+	cancelFile, err := jobFile(name, "cancel")
+	if err != nil {
+		return err.Trace()
+	}
+	if e := os.WriteFile(cancelFile, nil, 0o600); e != nil {
+		return probe.NewError(e)
+	}
+	if job.PID != 0 {
+		if e := stopProcess(job.PID); e != nil {
+			return probe.NewError(e)
+		}
+	}
+	return nil
+}
+
+// main for job cancel command.
+func mainJobCancel(cliCtx *cli.Context) error {
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	console.SetColor("Job", color.New(color.FgGreen, color.Bold))
+
+	name := cliCtx.Args().First()
+	fatalIf(checkJobName(name), "Invalid job.")
+	fatalIf(cancelJob(name).Trace(name), "Unable to cancel the job `%s`.", name)
+	printMsg(jobMessage{Event: "canceled", Name: name})
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+var jobListCmd = cli.Command{
+	Name:         "list",
+	ShortName:    "ls",
+	Usage:        "list the background jobs",
+	Action:       mainJobList,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. List the jobs, with their status and command.
+     {{.Prompt}} {{.HelpName}}
+`,
+}
+
+// jobListMessage - a job listed by 'mc job list'.
+type jobListMessage struct {
+	Status    string     `json:"status"`
+	Name      string     `json:"name"`
+	JobStatus string     `json:"jobStatus"`
+	Command   string     `json:"command"`
+	Submitted time.Time  `json:"submitted"`
+	Started   *time.Time `json:"started,omitempty"`
+	Finished  *time.Time `json:"finished,omitempty"`
+	ExitCode  int        `json:"exitCode"`
+	Error     string     `json:"error,omitempty"`
+}
+
+func newJobListMessage(job jobState) jobListMessage {
+	return jobListMessage{
+		Name:      job.Name,
+		JobStatus: job.Status,
+		Command:   mcCommandLine(job.Args),
+		Submitted: job.Submitted,
+		Started:   job.Started,
+		Finished:  job.Finished,
+		ExitCode:  job.ExitCode,
+		Error:     job.Error,
+	}
+}
+
+// String colorized job list message
+func (j jobListMessage) String() string {
+	status := j.JobStatus
+	var elapsed time.Duration
+	switch {
+	case j.Started != nil && j.Finished != nil:
+		elapsed = j.Finished.Sub(*j.Started)
+	case j.Started != nil && j.JobStatus == jobRunning:
+		elapsed = time.Since(*j.Started)
+	}
+	if elapsed = elapsed.Round(time.Second); elapsed > 0 {
+		status += " " + timeDurationToHumanizedDuration(elapsed).StringShort()
+	}
+	if j.JobStatus == jobFailed {
+		status += fmt.Sprintf(" (exit %d)", j.ExitCode)
+	}
+	return fmt.Sprintf("%s %s %s %s",
+		console.Colorize("Time", "["+j.Submitted.Local().Format(printDate)+"]"),
+		console.Colorize("JobName", fmt.Sprintf("%-16s", j.Name)),
+		console.Colorize("JobStatus", fmt.Sprintf("%-16s", status)),
+		j.Command)
+}
+
+// JSON jsonified job list message
+func (j jobListMessage) JSON() string {
+	j.Status = "success"
+	msgBytes, e := json.MarshalIndent(j, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// main for job list command.
+func mainJobList(cliCtx *cli.Context) error {
+	if len(cliCtx.Args()) != 0 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	console.SetColor("Time", color.New(color.FgGreen))
+	console.SetColor("JobName", color.New(color.Bold))
+	console.SetColor("JobStatus", color.New(color.FgYellow))
+
+	jobs, err := listJobs()
+	fatalIf(err, "Unable to list the jobs.")
+	for _, job := range jobs {
+		printMsg(newJobListMessage(job))
+	}
+	return nil
+}
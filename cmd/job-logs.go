@@ -0,0 +1,121 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var jobLogsFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "follow, f",
+		Usage: "print the log as it grows until the job ends, and exit with the exit status of its command",
+	},
+}
+
+var jobLogsCmd = cli.Command{
+	Name:         "logs",
+	Usage:        "print the log of a background job",
+	Action:       mainJobLogs,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(jobLogsFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] NAME
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Print the log of the job "backup".
+     {{.Prompt}} {{.HelpName}} backup
+
+  2. Follow the log of the job "backup" until it ends.
+     {{.Prompt}} {{.HelpName}} --follow backup
+`,
+}
+
+// Interval of the reads of a followed log.
+const jobLogsFollowInterval = 500 * time.Millisecond
+
+// printJobLogs - copies the log of the job name to w, as it grows until
+// the job ends when follow. Returns the job once printed.
+func printJobLogs(name string, w io.Writer, follow bool) (jobState, *probe.Error) {
+	job, err := loadJob(name)
+	if err != nil {
+		return job, err.Trace()
+	}
+	logFile, err := jobFile(name, "log")
+	if err != nil {
+		return job, err.Trace()
+	}
+	f, e := os.Open(logFile)
+	if e != nil {
+		return job, probe.NewError(e)
+	}
+	defer f.Close()
+
+	for {
+		// The job is loaded before the log is read, nothing is
+		// left to read once it finished.
+		if follow {
+			if job, err = loadJob(name); err != nil {
+				return job, err.Trace()
+			}
+		}
+		if _, e = io.Copy(w, f); e != nil {
+			return job, probe.NewError(e)
+		}
+		if !follow || job.finished() {
+			return job, nil
+		}
+		select {
+		case <-globalContext.Done():
+			return job, probe.NewError(globalContext.Err())
+		case <-time.After(jobLogsFollowInterval):
+		}
+	}
+}
+
+// main for job logs command.
+func mainJobLogs(cliCtx *cli.Context) error {
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	name := cliCtx.Args().First()
+	fatalIf(checkJobName(name), "Invalid job.")
+
+	follow := cliCtx.Bool("follow")
+	job, err := printJobLogs(name, os.Stdout, follow)
+	fatalIf(err.Trace(name), "Unable to print the log of the job `%s`.", name)
+	if follow && job.Status != jobDone {
+		if job.ExitCode > 0 {
+			return exitStatus(job.ExitCode)
+		}
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/minio/cli"
+	colorjson "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+var jobSubcommands = []cli.Command{
+	jobSubmitCmd,
+	jobListCmd,
+	jobLogsCmd,
+	jobCancelCmd,
+	jobRunCmd,
+}
+
+var jobCmd = cli.Command{
+	Name:            "job",
+	Usage:           "run commands as named background jobs",
+	Action:          mainJob,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	Subcommands:     jobSubcommands,
+	HideHelpCommand: true,
+}
+
+// mainJob is the handle for "mc job" command.
+func mainJob(ctx *cli.Context) error {
+	commandNotFound(ctx, jobSubcommands)
+	return nil
+	// Sub-commands like "submit", "list" have their own main.
+}
+
+const jobStateVersion = "1"
+
+// Status of a job.
+const (
+	jobQueued   = "queued"   // submitted, its command is not started yet
+	jobRunning  = "running"  // its command runs
+	jobDone     = "done"     // its command succeeded
+	jobFailed   = "failed"   // its command failed
+	jobCanceled = "canceled" // canceled by 'mc job cancel'
+	jobLost     = "lost"     // its runner ended without saving the result, e.g. at a reboot
+)
+
+// Time for the runner of a submitted job to start.
+const jobStartTimeout = time.Minute
+
+var jobNameRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// jobState - a job, saved to the file job.json of the folder of the job,
+// with the log of its command.
+type jobState struct {
+	Version   string     `json:"version"`
+	Name      string     `json:"name"`
+	Args      []string   `json:"args"` // arguments of mc
+	Dir       string     `json:"dir"`  // working folder of the command
+	Status    string     `json:"status"`
+	RunnerPID int        `json:"runnerPID,omitempty"`
+	PID       int        `json:"pid,omitempty"`
+	Submitted time.Time  `json:"submitted"`
+	Started   *time.Time `json:"started,omitempty"`
+	Finished  *time.Time `json:"finished,omitempty"`
+	ExitCode  int        `json:"exitCode"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// finished - returns true when the command of the job has ended.
+func (j jobState) finished() bool {
+	switch j.Status {
+	case jobDone, jobFailed, jobCanceled, jobLost:
+		return true
+	}
+	return false
+}
+
+func checkJobName(name string) *probe.Error {
+	if !jobNameRegexp.MatchString(name) {
+		return probe.NewError(fmt.Errorf("invalid job name `%s`, use letters, digits, '.', '_' and '-'", name))
+	}
+	return nil
+}
+
+// getJobsDir - returns the folder of the jobs, in the state folder.
+func getJobsDir() (string, *probe.Error) {
+	stateDir, err := getMcStateDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	return filepath.Join(stateDir, "jobs"), nil
+}
+
+func getJobDir(name string) (string, *probe.Error) {
+	jobsDir, err := getJobsDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	return filepath.Join(jobsDir, name), nil
+}
+
+// jobFile - returns the file named file of the folder of the job name.
+func jobFile(name, file string) (string, *probe.Error) {
+	dir, err := getJobDir(name)
+	if err != nil {
+		return "", err.Trace()
+	}
+	return filepath.Join(dir, file), nil
+}
+
+// loadJob - reads the state of the job name. The jobs whose runner ended
+// before saving their result, or never started, are lost.
+func loadJob(name string) (jobState, *probe.Error) {
+	var job jobState
+	file, err := jobFile(name, "job.json")
+	if err != nil {
+		return job, err.Trace()
+	}
+	data, e := os.ReadFile(file)
+	if os.IsNotExist(e) {
+		return job, probe.NewError(fmt.Errorf("no job named `%s`", name))
+	}
+	if e != nil {
+		return job, probe.NewError(e)
+	}
+	if e = json.Unmarshal(data, &job); e != nil {
+		return job, probe.NewError(e)
+	}
+	if job.Version != jobStateVersion {
+		return job, probe.NewError(fmt.Errorf("unsupported job version `%s`", job.Version))
+	}
+	if !job.finished() && !processAlive(job.RunnerPID) &&
+		(job.RunnerPID != 0 || time.Since(job.Submitted) > jobStartTimeout) {
+		job.Status = jobLost
+	}
+	return job, nil
+}
+
+// saveJob - writes the state of the job, replaced once complete.
+func saveJob(job jobState) *probe.Error {
+	file, err := jobFile(job.Name, "job.json")
+	if err != nil {
+		return err.Trace()
+	}
+	data, e := json.MarshalIndent(job, "", "  ")
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if e = os.WriteFile(file+".tmp", append(data, '\n'), 0o600); e != nil {
+		return probe.NewError(e)
+	}
+	if e = os.Rename(file+".tmp", file); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// listJobs - returns the jobs, by submission time.
+func listJobs() ([]jobState, *probe.Error) {
+	jobsDir, err := getJobsDir()
+	if err != nil {
+		return nil, err.Trace()
+	}
+	entries, e := os.ReadDir(jobsDir)
+	if e != nil && !os.IsNotExist(e) {
+		return nil, probe.NewError(e)
+	}
+	var jobs []jobState
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		job, err := loadJob(entry.Name())
+		if err != nil {
+			errorIf(err.Trace(entry.Name()), "Unable to read the job `%s`.", entry.Name())
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Submitted.Before(jobs[j].Submitted)
+	})
+	return jobs, nil
+}
+
+// jobArgs - returns the arguments of mc running args with the current
+// config folder.
+func jobArgs(args ...string) ([]string, *probe.Error) {
+	if mcCustomConfigDir == "" {
+		return args, nil
+	}
+	configDir, e := filepath.Abs(mcCustomConfigDir)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	return append([]string{"--config-dir", configDir}, args...), nil
+}
+
+// jobMessage - reports a job submitted or canceled.
+type jobMessage struct {
+	Status  string `json:"status"`
+	Event   string `json:"event"` // submitted or canceled
+	Name    string `json:"name"`
+	Command string `json:"command,omitempty"`
+}
+
+// String colorized job message
+func (j jobMessage) String() string {
+	if j.Event == "canceled" {
+		return console.Colorize("Job", fmt.Sprintf("Canceled the job `%s`.", j.Name))
+	}
+	return console.Colorize("Job", fmt.Sprintf("Submitted the job `%s` running `%s`, follow it with `mc job logs --follow %s`.", j.Name, j.Command, j.Name))
+}
+
+// JSON jsonified job message
+func (j jobMessage) JSON() string {
+	j.Status = "success"
+	msgBytes, e := colorjson.MarshalIndent(j, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestJobState(t *testing.T) {
+	useTestMcConfig(t)
+
+	if jobs, err := listJobs(); err != nil || len(jobs) != 0 {
+		t.Fatalf("expected no job, got %v, %v", jobs, err)
+	}
+	if _, err := loadJob("backup"); err == nil {
+		t.Fatal("expected a missing job error")
+	}
+
+	dir, err := getJobDir("backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := os.MkdirAll(dir, 0o700); e != nil {
+		t.Fatal(e)
+	}
+	job := jobState{
+		Version:   jobStateVersion,
+		Name:      "backup",
+		Args:      []string{"cp", "--recursive", "a", "b"},
+		Status:    jobQueued,
+		Submitted: UTCNow(),
+	}
+	if err = saveJob(job); err != nil {
+		t.Fatal(err)
+	}
+	// Queued with no runner yet.
+	if job, err = loadJob("backup"); err != nil || job.Status != jobQueued {
+		t.Fatalf("expected a queued job, got %v, %v", job.Status, err)
+	}
+
+	// Running in this process.
+	started := UTCNow()
+	job.Status, job.RunnerPID, job.Started = jobRunning, os.Getpid(), &started
+	if err = saveJob(job); err != nil {
+		t.Fatal(err)
+	}
+	if job, err = loadJob("backup"); err != nil || job.Status != jobRunning {
+		t.Fatalf("expected a running job, got %v, %v", job.Status, err)
+	}
+
+	// Queued, never started by its runner.
+	job.Status, job.RunnerPID, job.Submitted = jobQueued, 0, UTCNow().Add(-2*jobStartTimeout)
+	if err = saveJob(job); err != nil {
+		t.Fatal(err)
+	}
+	jobs, err := listJobs()
+	if err != nil || len(jobs) != 1 || jobs[0].Status != jobLost {
+		t.Fatalf("expected a lost job, got %v, %v", jobs, err)
+	}
+}
+
+func TestPrintJobLogs(t *testing.T) {
+	useTestMcConfig(t)
+
+	dir, err := getJobDir("backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := os.MkdirAll(dir, 0o700); e != nil {
+		t.Fatal(e)
+	}
+	finished := UTCNow()
+	job := jobState{Version: jobStateVersion, Name: "backup", Status: jobDone, Submitted: finished.Add(-time.Minute), Finished: &finished}
+	if err = saveJob(job); err != nil {
+		t.Fatal(err)
+	}
+	logFile, _ := jobFile("backup", "log")
+	if e := os.WriteFile(logFile, []byte("copied\n"), 0o600); e != nil {
+		t.Fatal(e)
+	}
+	for _, follow := range []bool{false, true} {
+		var buf bytes.Buffer
+		job, err := printJobLogs("backup", &buf, follow)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != "copied\n" || job.Status != jobDone {
+			t.Fatalf("expected the log of the done job, got %q, %v", buf.String(), job.Status)
+		}
+	}
+}
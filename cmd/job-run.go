@@ -0,0 +1,116 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// jobRunCmd - the runner of a job, started by 'mc job submit', runs its
+// command and saves its state.
+var jobRunCmd = cli.Command{
+	Name:   "run",
+	Usage:  "run the command of a submitted job",
+	Action: mainJobRun,
+	Hidden: true,
+	Before: setGlobalsFromContext,
+	Flags:  globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} NAME
+`,
+}
+
+// runJob - runs the command of the job name, its output goes to the
+// output of the runner, the log of the job.
+func runJob(name string) *probe.Error {
+	job, err := loadJob(name)
+	if err != nil {
+		return err.Trace()
+	}
+	job.RunnerPID = os.Getpid()
+	cancelFile, err := jobFile(name, "cancel")
+	if err != nil {
+		return err.Trace()
+	}
+	canceled := func() bool {
+		_, e := os.Stat(cancelFile)
+		return e == nil
+	}
+	finish := func(status string, e error) *probe.Error {
+		finished := UTCNow()
+		job.Status, job.Finished = status, &finished
+		if e != nil {
+			job.Error = e.Error()
+		}
+		return saveJob(job)
+	}
+	if canceled() {
+		return finish(jobCanceled, nil)
+	}
+
+	exe, e := os.Executable()
+	if e != nil {
+		return finish(jobFailed, e)
+	}
+	cmd := exec.Command(exe, job.Args...)
+	cmd.Dir = job.Dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if e = cmd.Start(); e != nil {
+		return finish(jobFailed, e)
+	}
+	started := UTCNow()
+	job.Status, job.PID, job.Started = jobRunning, cmd.Process.Pid, &started
+	if err = saveJob(job); err != nil {
+		cmd.Process.Kill()
+		return err.Trace()
+	}
+
+	e = cmd.Wait()
+	job.ExitCode = cmd.ProcessState.ExitCode()
+	var exitErr *exec.ExitError
+	switch {
+	case canceled():
+		return finish(jobCanceled, nil)
+	case e == nil:
+		return finish(jobDone, nil)
+	case errors.As(e, &exitErr):
+		// The command logged why it failed.
+		return finish(jobFailed, nil)
+	}
+	return finish(jobFailed, e)
+}
+
+// main for job run command.
+func mainJobRun(cliCtx *cli.Context) error {
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	name := cliCtx.Args().First()
+	fatalIf(checkJobName(name), "Invalid job.")
+	fatalIf(runJob(name).Trace(name), "Unable to run the job `%s`.", name)
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+var jobSubmitCmd = cli.Command{
+	Name:           "submit",
+	Usage:          "run a command as a named background job",
+	Action:         mainJobSubmit,
+	OnUsageError:   onUsageError,
+	Before:         setGlobalsFromContext,
+	Flags:          globalFlags,
+	SkipArgReorder: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] NAME COMMAND [ARGS...]
+
+  The mc COMMAND runs in the background, in the current working folder,
+  and keeps running once the terminal is closed. Its output is logged to
+  the folder of the job, shown by 'mc job logs NAME'. A finished job is
+  replaced by the next job of the same name.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Copy a folder to a bucket in the background, then follow its progress.
+     {{.Prompt}} {{.HelpName}} backup cp --recursive ~/Photos s3/mybucket/photos
+     {{.Prompt}} mc job logs --follow backup
+`,
+}
+
+// submitJob - saves the job running the mc command args and starts its
+// runner, detached from the terminal.
+func submitJob(name string, args []string) *probe.Error {
+	if job, err := loadJob(name); err == nil && !job.finished() {
+		return probe.NewError(fmt.Errorf("the job `%s` is %s", name, job.Status))
+	}
+	dir, err := getJobDir(name)
+	if err != nil {
+		return err.Trace()
+	}
+	if e := os.RemoveAll(dir); e != nil {
+		return probe.NewError(e)
+	}
+	if e := os.MkdirAll(dir, 0o700); e != nil {
+		return probe.NewError(e)
+	}
+
+	job := jobState{
+		Version:   jobStateVersion,
+		Name:      name,
+		Status:    jobQueued,
+		Submitted: UTCNow(),
+	}
+	if job.Args, err = jobArgs(args...); err != nil {
+		return err.Trace()
+	}
+	wd, e := os.Getwd()
+	if e != nil {
+		return probe.NewError(e)
+	}
+	job.Dir = wd
+	if err = saveJob(job); err != nil {
+		return err.Trace()
+	}
+
+	logFile, err := jobFile(name, "log")
+	if err != nil {
+		return err.Trace()
+	}
+	log, e := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	defer log.Close()
+
+	exe, e := os.Executable()
+	if e != nil {
+		return probe.NewError(e)
+	}
+	runnerArgs, err := jobArgs("job", "run", name)
+	if err != nil {
+		return err.Trace()
+	}
+	runner := exec.Command(exe, runnerArgs...)
+	runner.Dir = wd
+	runner.Stdout = log
+	runner.Stderr = log
+	detachProcess(runner)
+	if e = runner.Start(); e != nil {
+		return probe.NewError(e)
+	}
+	runner.Process.Release()
+	return nil
+}
+
+// main for job submit command.
+func mainJobSubmit(cliCtx *cli.Context) error {
+	args := cliCtx.Args()
+	if len(args) < 2 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	console.SetColor("Job", color.New(color.FgGreen, color.Bold))
+
+	name, command := args.First(), args.Tail()
+	fatalIf(checkJobName(name), "Invalid job.")
+	// The commands of mc are those of the app of the top context.
+	root := cliCtx
+	for root.Parent() != nil {
+		root = root.Parent()
+	}
+	switch c := root.App.Command(command[0]); {
+	case c == nil:
+		fatalIf(errInvalidArgument().Trace(command[0]), "Unknown command `%s`.", command[0])
+	case c.Name == "job" || c.Name == "daemon":
+		fatalIf(errInvalidArgument().Trace(command[0]), "`%s` can't run as a job.", command[0])
+	}
+
+	fatalIf(submitJob(name, command).Trace(name), "Unable to submit the job `%s`.", name)
+	printMsg(jobMessage{Event: "submitted", Name: name, Command: mcCommandLine(command)})
+	return nil
+}
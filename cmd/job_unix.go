@@ -0,0 +1,45 @@
+//go:build !windows
+// +build !windows
+
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachProcess - starts cmd in a new session, it keeps running once the
+// terminal is closed.
+func detachProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	e := syscall.Kill(pid, 0)
+	return e == nil || e == syscall.EPERM
+}
+
+// stopProcess - interrupts the process pid, which saves its state and exits.
+func stopProcess(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
@@ -0,0 +1,64 @@
+//go:build windows
+// +build windows
+
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// detachProcess - starts cmd without console, it keeps running once the
+// console is closed.
+func detachProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: windows.DETACHED_PROCESS | windows.CREATE_NEW_PROCESS_GROUP}
+}
+
+// Exit code of the processes still running, STILL_ACTIVE.
+const processStillActive = 259
+
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	h, e := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if e != nil {
+		return false
+	}
+	defer windows.CloseHandle(h)
+	var code uint32
+	if e = windows.GetExitCodeProcess(h, &code); e != nil {
+		return false
+	}
+	return code == processStillActive
+}
+
+// stopProcess - ends the process pid, processes without console can't be
+// interrupted.
+func stopProcess(pid int) error {
+	p, e := os.FindProcess(pid)
+	if e != nil {
+		return e
+	}
+	return p.Kill()
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/minio/cli"
+)
+
+// listFilter - selects the objects of a recursive listing by their name
+// relative to the listed URL, with --exclude and --include patterns, drops
+// the directory markers with --skip-dir-markers and resolves the objects
+// named like a prefix with --name-conflict.
+type listFilter struct {
+	exclude, include []string
+	skipDirMarkers   bool
+	nameConflict     nameConflictMode
+}
+
+func newListFilter(cliCtx *cli.Context) listFilter {
+	nameConflict, err := parseNameConflictMode(cliCtx.String("name-conflict"))
+	fatalIf(err, "Invalid --name-conflict, valid values are `object` and `prefix`.")
+	return listFilter{
+		exclude:        cliCtx.StringSlice("exclude"),
+		include:        cliCtx.StringSlice("include"),
+		skipDirMarkers: cliCtx.Bool("skip-dir-markers"),
+		nameConflict:   nameConflict,
+	}
+}
+
+// forTarget - returns the filter for a listing copied to a target of type
+// typ, name conflicts only matter to filesystem targets.
+func (f listFilter) forTarget(typ ClientURLType) listFilter {
+	if typ != fileSystem {
+		f.nameConflict = nameConflictAllow
+	}
+	return f
+}
+
+func (f listFilter) isSet() bool {
+	return len(f.exclude) > 0 || len(f.include) > 0 || f.skipDirMarkers
+}
+
+// isDirMarker - reports whether content is a directory marker, a zero
+// byte object named like a prefix, e.g. 'photos/', which many tools create
+// to show an empty folder. They are listed as directories.
+func isDirMarker(content *ClientContent, typ ClientURLType) bool {
+	return typ == objectStorage && content.Type.IsDir() && content.Size == 0
+}
+
+// match - returns true when the object named name should be processed,
+// excluded names win over included ones.
+func (f listFilter) match(name string, typ ClientURLType) bool {
+	if matchExcludeOptions(f.exclude, name, typ) {
+		return false
+	}
+	return len(f.include) == 0 || matchExcludeOptions(f.include, name, typ)
+}
+
+// filterList - drops the entries of the listing of baseURL not matching
+// the filter, as they are listed. Directories are only excluded.
+func filterList(ctx context.Context, listCh <-chan *ClientContent, baseURL string, f listFilter) <-chan *ClientContent {
+	if !f.isSet() {
+		return resolveNameConflicts(ctx, listCh, baseURL, f.nameConflict)
+	}
+	typ := newClientURL(baseURL).Type
+	filteredCh := make(chan *ClientContent)
+	go func() {
+		defer close(filteredCh)
+		for content := range listCh {
+			if content.Err == nil {
+				name := strings.TrimPrefix(content.URL.String(), baseURL)
+				if f.skipDirMarkers && isDirMarker(content, typ) {
+					continue
+				}
+				if content.Type.IsDir() {
+					if matchExcludeOptions(f.exclude, name, typ) {
+						continue
+					}
+				} else if !f.match(name, typ) {
+					continue
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case filteredCh <- content:
+			}
+		}
+	}()
+	return resolveNameConflicts(ctx, filteredCh, baseURL, f.nameConflict)
+}
+
+// splitSessionFlag - returns the patterns of a string slice flag saved
+// in a session, one per line.
+func splitSessionFlag(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
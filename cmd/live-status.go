@@ -0,0 +1,219 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+	"golang.org/x/term"
+)
+
+// Number of the most recent errors shown in the live status.
+const liveStatusRecentErrors = 5
+
+// liveStatusMessage - snapshot of a running cp, mirror or diff, printed on
+// SIGUSR1 (SIGINFO with ctrl-t on BSDs) or when Enter is pressed, like the
+// SIGINFO of dd, without interrupting the operation.
+type liveStatusMessage struct {
+	Status       string   `json:"status"`
+	Operation    string   `json:"operation"`
+	Elapsed      float64  `json:"elapsedSeconds"`
+	Objects      int64    `json:"objects"`
+	TotalObjects int64    `json:"totalObjects,omitempty"`
+	Transferred  int64    `json:"transferred"`
+	TotalSize    int64    `json:"totalSize,omitempty"`
+	Speed        float64  `json:"speed"`
+	Queued       int64    `json:"queued"`
+	Differences  int64    `json:"differences,omitempty"`
+	Errors       int64    `json:"errors"`
+	RecentErrors []string `json:"recentErrors,omitempty"`
+}
+
+func (m liveStatusMessage) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Status of `%s` after %s:\n", m.Operation, (time.Duration(m.Elapsed * float64(time.Second))).Round(time.Second))
+	if m.TotalObjects > 0 || m.Transferred > 0 {
+		fmt.Fprintf(&b, "  Objects:     %d of %d done\n", m.Objects, m.TotalObjects)
+		fmt.Fprintf(&b, "  Transferred: %s of %s at %s/s\n", humanize.IBytes(uint64(m.Transferred)),
+			humanize.IBytes(uint64(m.TotalSize)), humanize.IBytes(uint64(m.Speed)))
+		fmt.Fprintf(&b, "  Queued:      %d\n", m.Queued)
+	}
+	if m.Differences > 0 {
+		fmt.Fprintf(&b, "  Differences: %d\n", m.Differences)
+	}
+	fmt.Fprintf(&b, "  Errors:      %d", m.Errors)
+	for _, e := range m.RecentErrors {
+		b.WriteString("\n    " + e)
+	}
+	return b.String()
+}
+
+func (m liveStatusMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// recentErrors - the errors reported by errorIf, the last ones are kept
+// for the live status.
+var recentErrors struct {
+	sync.Mutex
+	count int64
+	last  []string
+}
+
+func recordRecentError(msg string) {
+	recentErrors.Lock()
+	defer recentErrors.Unlock()
+	recentErrors.count++
+	if len(recentErrors.last) == liveStatusRecentErrors {
+		recentErrors.last = recentErrors.last[1:]
+	}
+	recentErrors.last = append(recentErrors.last, msg)
+}
+
+// getRecentErrors - returns the number of errors reported so far and the
+// most recent ones.
+func getRecentErrors() (int64, []string) {
+	recentErrors.Lock()
+	defer recentErrors.Unlock()
+	return recentErrors.count, append([]string(nil), recentErrors.last...)
+}
+
+// liveStatus - the running operation, its snapshot fills the counters of
+// the message.
+var liveStatus struct {
+	sync.Mutex
+	operation string
+	start     time.Time
+	errors    int64
+	snapshot  func(*liveStatusMessage)
+}
+
+var (
+	liveStatusOnce sync.Once
+	liveStatusCh   = make(chan struct{}, 1)
+)
+
+// startLiveStatus - prints the live status of operation on request until
+// the returned function is called.
+func startLiveStatus(operation string, snapshot func(*liveStatusMessage)) (stop func()) {
+	liveStatusOnce.Do(func() {
+		if len(liveStatusSignals) > 0 {
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, liveStatusSignals...)
+			go func() {
+				for range sigCh {
+					requestLiveStatus()
+				}
+			}()
+		}
+		// Enter is only read from a terminal, not to take the input of
+		// the operation.
+		if term.IsTerminal(int(os.Stdin.Fd())) {
+			go func() {
+				scanner := bufio.NewScanner(os.Stdin)
+				for scanner.Scan() {
+					requestLiveStatus()
+				}
+			}()
+		}
+		go func() {
+			for range liveStatusCh {
+				if msg, ok := getLiveStatus(); ok {
+					if isProgressBarEnabled() {
+						console.Eraseline()
+					}
+					printMsg(msg)
+				}
+			}
+		}()
+	})
+
+	errors, _ := getRecentErrors()
+	liveStatus.Lock()
+	liveStatus.operation, liveStatus.start, liveStatus.errors, liveStatus.snapshot = operation, time.Now(), errors, snapshot
+	liveStatus.Unlock()
+	return func() {
+		liveStatus.Lock()
+		liveStatus.snapshot = nil
+		liveStatus.Unlock()
+	}
+}
+
+// requestLiveStatus - asks for the live status, requests made while it
+// is printed are merged.
+func requestLiveStatus() {
+	select {
+	case liveStatusCh <- struct{}{}:
+	default:
+	}
+}
+
+// getLiveStatus - returns the live status of the running operation, false
+// when none runs.
+func getLiveStatus() (liveStatusMessage, bool) {
+	liveStatus.Lock()
+	defer liveStatus.Unlock()
+	if liveStatus.snapshot == nil {
+		return liveStatusMessage{}, false
+	}
+	msg := liveStatusMessage{
+		Operation: liveStatus.operation,
+		Elapsed:   time.Since(liveStatus.start).Seconds(),
+	}
+	liveStatus.snapshot(&msg)
+	if msg.Elapsed > 0 {
+		msg.Speed = float64(msg.Transferred) / msg.Elapsed
+	}
+	errors, last := getRecentErrors()
+	msg.Errors = errors - liveStatus.errors
+	if int64(len(last)) > msg.Errors {
+		last = last[int64(len(last))-msg.Errors:]
+	}
+	msg.RecentErrors = last
+	return msg, true
+}
+
+// progressTotal - returns the total size known to a progress bar or an
+// accounter.
+func progressTotal(progress interface{}) int64 {
+	switch p := progress.(type) {
+	case *accounter:
+		return atomic.LoadInt64(&p.total)
+	case *progressBar:
+		return p.ProgressBar.Total
+	case *QuietStatus:
+		return progressTotal(p.accounter)
+	case *ProgressStatus:
+		return progressTotal(p.progressBar)
+	}
+	return 0
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestLiveStatus(t *testing.T) {
+	if _, ok := getLiveStatus(); ok {
+		t.Fatal("expected no live status without a running operation")
+	}
+
+	errorIf(probe.NewError(errors.New("before")), "Unable to list.")
+	stop := startLiveStatus("cp", func(m *liveStatusMessage) {
+		m.Objects, m.TotalObjects = 2, 5
+		m.Transferred, m.TotalSize = 2048, 4096
+		m.Queued = 3
+	})
+	for i := 0; i < liveStatusRecentErrors+2; i++ {
+		errorIf(probe.NewError(fmt.Errorf("error %d", i)), "Failed to copy `%s`.", "f")
+	}
+
+	msg, ok := getLiveStatus()
+	if !ok {
+		t.Fatal("expected the live status of the running operation")
+	}
+	if msg.Operation != "cp" || msg.Objects != 2 || msg.TotalObjects != 5 || msg.Queued != 3 {
+		t.Errorf("unexpected live status %+v", msg)
+	}
+	if msg.Errors != liveStatusRecentErrors+2 {
+		t.Errorf("expected %d errors, got %d", liveStatusRecentErrors+2, msg.Errors)
+	}
+	var want []string
+	for i := 2; i < liveStatusRecentErrors+2; i++ {
+		want = append(want, fmt.Sprintf("Failed to copy `f`. error %d", i))
+	}
+	if !reflect.DeepEqual(msg.RecentErrors, want) {
+		t.Errorf("expected recent errors %q, got %q", want, msg.RecentErrors)
+	}
+	if s := msg.String(); !strings.Contains(s, "2 of 5 done") || !strings.Contains(s, "2.0 KiB of 4.0 KiB") {
+		t.Errorf("unexpected live status message %q", s)
+	}
+
+	stop()
+	if _, ok := getLiveStatus(); ok {
+		t.Fatal("expected no live status once the operation stopped")
+	}
+
+	// Only the errors of the operation are shown.
+	stop = startLiveStatus("diff", func(m *liveStatusMessage) { m.Differences = 1 })
+	defer stop()
+	errorIf(probe.NewError(errors.New("missing")), "Unable to calculate objects difference.")
+	msg, _ = getLiveStatus()
+	if msg.Errors != 1 || !reflect.DeepEqual(msg.RecentErrors, []string{"Unable to calculate objects difference. missing"}) {
+		t.Errorf("unexpected errors of the operation %d %q", msg.Errors, msg.RecentErrors)
+	}
+	if s := msg.String(); strings.Contains(s, "Objects:") || !strings.Contains(s, "Differences: 1") {
+		t.Errorf("unexpected live status message %q", s)
+	}
+}
@@ -60,6 +60,26 @@ var (
 			Name:  "zip",
 			Usage: "list files inside zip archive (MinIO servers only)",
 		},
+		cli.StringSliceFlag{
+			Name:  "match",
+			Usage: "list only objects matching the '*' and '?' wildcard pattern, patterns without '/' match the object name only",
+		},
+		cli.StringFlag{
+			Name:  "sort",
+			Usage: "sort the listing by 'name', 'size' (largest first) or 'mtime' (most recent first)",
+		},
+		cli.BoolFlag{
+			Name:  "reverse",
+			Usage: "reverse the order of --sort",
+		},
+		cli.BoolFlag{
+			Name:  "skip-dir-markers",
+			Usage: "skip the zero byte objects named like a folder, e.g. 'photos/', created by other tools to show folders",
+		},
+		cli.StringSliceFlag{
+			Name:  "tags",
+			Usage: "list only objects with tags matching RE2 regex pattern. Specify each with key=regex. MinIO server only.",
+		},
 	}
 )
 
@@ -112,6 +132,18 @@ EXAMPLES:
   
   10. List all objects on mybucket, for the GLACIER storage class
      {{.Prompt}} {{.HelpName}} --storage-class 'GLACIER' s3/mybucket 
+
+  11. List all log files on mybucket recursively, largest first.
+     {{.Prompt}} {{.HelpName}} --recursive --match '*.log' --sort size s3/mybucket
+
+  12. List the oldest objects under a prefix first.
+     {{.Prompt}} {{.HelpName}} --recursive --sort mtime --reverse s3/mybucket/archive/
+
+  13. List all objects on mybucket recursively, without the empty folder markers created by other tools.
+     {{.Prompt}} {{.HelpName}} --recursive --skip-dir-markers s3/mybucket
+
+  14. List all objects on mybucket recursively, tagged as project 'apollo' and without a status tag.
+     {{.Prompt}} {{.HelpName}} --recursive --tags "project=^apollo$" --tags "status=" s3/mybucket
 `,
 }
 
@@ -179,6 +211,21 @@ func checkListSyntax(cliCtx *cli.Context) ([]string, doListOptions) {
 	if listZip && (withOlderVersions || !timeRef.IsZero()) {
 		fatalIf(errInvalidArgument().Trace(args...), "Zip file listing can only be performed on the latest version")
 	}
+	sortBy := cliCtx.String("sort")
+	switch sortBy {
+	case "", lsSortByName, lsSortBySize, lsSortByMTime:
+	default:
+		fatalIf(errInvalidArgument().Trace(sortBy), "Unsupported --sort value, valid values are 'name', 'size' and 'mtime'.")
+	}
+	if cliCtx.Bool("reverse") && sortBy == "" {
+		fatalIf(errInvalidArgument().Trace(args...), "--reverse requires --sort.")
+	}
+	for _, pattern := range cliCtx.StringSlice("match") {
+		if pattern == "" {
+			fatalIf(errInvalidArgument().Trace(args...), "--match requires a non-empty pattern.")
+		}
+	}
+
 	storageClasss := cliCtx.String("storage-class")
 	opts := doListOptions{
 		timeRef:           timeRef,
@@ -188,6 +235,11 @@ func checkListSyntax(cliCtx *cli.Context) ([]string, doListOptions) {
 		withOlderVersions: withOlderVersions,
 		listZip:           listZip,
 		filter:            storageClasss,
+		match:             cliCtx.StringSlice("match"),
+		sortBy:            sortBy,
+		reverse:           cliCtx.Bool("reverse"),
+		skipDirMarkers:    cliCtx.Bool("skip-dir-markers"),
+		tags:              getRegexMap(cliCtx, "tags"),
 	}
 	return args, opts
 }
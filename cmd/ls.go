@@ -20,7 +20,9 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"path"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -75,7 +77,7 @@ func (c contentMessage) String() string {
 		}
 	}
 
-	fileDesc += " " + c.Key
+	fileDesc += " " + quoteName(c.Key)
 
 	if c.Filetype == "folder" {
 		message += console.Colorize("Dir", fileDesc)
@@ -197,13 +199,59 @@ func (s summaryMessage) JSON() string {
 	return string(jsonMessageBytes)
 }
 
-// Pretty print the list of versions belonging to one object
-func printObjectVersions(clntURL ClientURL, ctntVersions []*ClientContent, printAllVersions bool) {
-	sortObjectVersions(ctntVersions)
-	msgs := generateContentMessages(clntURL, ctntVersions, printAllVersions)
-	for _, msg := range msgs {
-		printMsg(msg)
+// Sort orders supported by --sort.
+const (
+	lsSortByName  = "name"
+	lsSortBySize  = "size"
+	lsSortByMTime = "mtime"
+)
+
+// sortContentMessages - sorts the listing by the first (latest) version of
+// every object, sizes and modification times are sorted largest and most
+// recent first as ls(1) does.
+func sortContentMessages(listing [][]contentMessage, sortBy string, reverse bool) {
+	less := func(a, b contentMessage) bool {
+		switch sortBy {
+		case lsSortBySize:
+			if a.Size != b.Size {
+				return a.Size > b.Size
+			}
+		case lsSortByMTime:
+			if !a.Time.Equal(b.Time) {
+				return a.Time.After(b.Time)
+			}
+		}
+		return a.Key < b.Key
+	}
+	sort.SliceStable(listing, func(i, j int) bool {
+		if reverse {
+			return less(listing[j][0], listing[i][0])
+		}
+		return less(listing[i][0], listing[j][0])
+	})
+}
+
+// matchListPatterns - reports whether the key relative to the listed prefix
+// matches one of the --match patterns, patterns without a slash are matched
+// against the base name only. Patterns support the '*' and '?' wildcards of
+// find --name, and any other character matches itself.
+func matchListPatterns(patterns []string, key string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	key = strings.TrimSuffix(key, "/")
+	for _, pattern := range patterns {
+		if !strings.Contains(pattern, "/") {
+			if pathMatch(pattern, path.Base(key)) {
+				return true
+			}
+			continue
+		}
+		if pathMatch(pattern, key) {
+			return true
+		}
 	}
+	return false
 }
 
 type doListOptions struct {
@@ -214,6 +262,11 @@ type doListOptions struct {
 	withOlderVersions bool
 	listZip           bool
 	filter            string
+	match             []string
+	sortBy            string
+	reverse           bool
+	skipDirMarkers    bool
+	tags              map[string]*regexp.Regexp
 }
 
 // doList - list all entities inside a folder.
@@ -224,8 +277,32 @@ func doList(ctx context.Context, clnt Client, o doListOptions) error {
 		cErr              error
 		totalSize         int64
 		totalObjects      int64
+		listing           [][]contentMessage
 	)
 
+	// Print or, when sorting, collect the versions of the current object.
+	flushObjectVersions := func() {
+		if len(perObjectVersions) == 0 {
+			return
+		}
+		sortObjectVersions(perObjectVersions)
+		msgs := generateContentMessages(clnt.GetURL(), perObjectVersions, o.withOlderVersions)
+		if !matchListPatterns(o.match, msgs[0].Key) {
+			return
+		}
+		for _, content := range perObjectVersions {
+			totalSize += content.Size
+			totalObjects++
+		}
+		if o.sortBy != "" {
+			listing = append(listing, msgs)
+			return
+		}
+		for _, msg := range msgs {
+			printMsg(msg)
+		}
+	}
+
 	for content := range clnt.List(ctx, ListOptions{
 		Recursive:         o.isRecursive,
 		Incomplete:        o.isIncomplete,
@@ -234,6 +311,7 @@ func doList(ctx context.Context, clnt Client, o doListOptions) error {
 		WithDeleteMarkers: true,
 		ShowDir:           DirNone,
 		ListZip:           o.listZip,
+		WithMetadata:      len(o.tags) > 0,
 	}) {
 		if content.Err != nil {
 			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list folder.")
@@ -245,19 +323,35 @@ func doList(ctx context.Context, clnt Client, o doListOptions) error {
 			continue
 		}
 
+		// Recursive listings hold no prefixes, their folders are markers.
+		if o.skipDirMarkers && o.isRecursive && isDirMarker(content, clnt.GetURL().Type) {
+			continue
+		}
+
+		if !matchContentTags(o.tags, content) {
+			continue
+		}
+
 		if lastPath != content.URL.Path {
 			// Print any object in the current list before reinitializing it
-			printObjectVersions(clnt.GetURL(), perObjectVersions, o.withOlderVersions)
+			flushObjectVersions()
 			lastPath = content.URL.Path
 			perObjectVersions = []*ClientContent{}
 		}
 
 		perObjectVersions = append(perObjectVersions, content)
-		totalSize += content.Size
-		totalObjects++
 	}
 
-	printObjectVersions(clnt.GetURL(), perObjectVersions, o.withOlderVersions)
+	flushObjectVersions()
+
+	if o.sortBy != "" {
+		sortContentMessages(listing, o.sortBy, o.reverse)
+		for _, msgs := range listing {
+			for _, msg := range msgs {
+				printMsg(msg)
+			}
+		}
+	}
 
 	if o.isSummary {
 		printMsg(summaryMessage{
@@ -16,3 +16,69 @@
 // along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
 package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMatchListPatterns(t *testing.T) {
+	testCases := []struct {
+		patterns []string
+		key      string
+		match    bool
+	}{
+		{nil, "a/b.log", true},
+		{[]string{"*.log"}, "b.log", true},
+		{[]string{"*.log"}, "a/b.log", true},
+		{[]string{"*.log"}, "a/b.txt", false},
+		{[]string{"*.txt", "*.log"}, "a/b.log", true},
+		{[]string{"a/*"}, "a/b/c.log", true},
+		{[]string{"a/*"}, "b/c.log", false},
+		{[]string{"logs"}, "logs/", true},
+		{[]string{"b?.log"}, "a/b1.log", true},
+		// '[' is not special, matching what --match accepts.
+		{[]string{"[ab].log"}, "[ab].log", true},
+		{[]string{"[ab].log"}, "a.log", false},
+	}
+	for i, testCase := range testCases {
+		if match := matchListPatterns(testCase.patterns, testCase.key); match != testCase.match {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.match, match)
+		}
+	}
+}
+
+func TestSortContentMessages(t *testing.T) {
+	now := time.Now()
+	listing := func() [][]contentMessage {
+		return [][]contentMessage{
+			{{Key: "b", Size: 1, Time: now}},
+			{{Key: "c", Size: 3, Time: now.Add(-time.Hour)}},
+			{{Key: "a", Size: 2, Time: now.Add(time.Hour)}},
+			{{Key: "d", Size: 2, Time: now}},
+		}
+	}
+	testCases := []struct {
+		sortBy   string
+		reverse  bool
+		expected string
+	}{
+		{lsSortByName, false, "abcd"},
+		{lsSortByName, true, "dcba"},
+		{lsSortBySize, false, "cadb"},
+		{lsSortBySize, true, "bdac"},
+		{lsSortByMTime, false, "abdc"},
+		{lsSortByMTime, true, "cdba"},
+	}
+	for i, testCase := range testCases {
+		l := listing()
+		sortContentMessages(l, testCase.sortBy, testCase.reverse)
+		var keys string
+		for _, msgs := range l {
+			keys += msgs[0].Key
+		}
+		if keys != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, keys)
+		}
+	}
+}
@@ -19,6 +19,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -52,6 +53,11 @@ var mcFlags = []cli.Flag{
 		Name:  "autocompletion",
 		Usage: "install auto-completion for your shell",
 	},
+	cli.BoolFlag{
+		Name:   "dry-run",
+		Usage:  "report the operations of mb, cp, mv, rm and mirror without performing them",
+		EnvVar: envPrefix + "DRY_RUN",
+	},
 }
 
 // Help template for mc
@@ -67,6 +73,11 @@ COMMANDS:
 GLOBAL FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}{{end}}
+ENVIRONMENT VARIABLES:
+  Flags listing an MC_* variable, such as MC_JSON or MC_INSECURE, are set by that variable when
+  not given on the command line. A flag given on the command line takes precedence over its
+  environment variable, which takes precedence over the configuration file, then the default.
+
 TIP:
   Use '{{.Name}} --autocompletion' to enable shell autocompletion
 
@@ -130,16 +141,49 @@ func Main(args []string) error {
 		appName = appName[:strings.LastIndex(appName, ".")]
 	}
 
+	// The deadline of the command covers all of it, the signal handler
+	// cancels both contexts.
+	if timeout := parseTimeoutFlag(args[1:]); timeout > 0 {
+		globalTimeout = timeout
+		var cancelTimeout context.CancelFunc
+		globalContext, cancelTimeout = context.WithTimeout(globalContext, timeout)
+		cancel := globalCancel
+		globalCancel = func() {
+			cancelTimeout()
+			cancel()
+		}
+	}
+
 	// Monitor OS exit signals and cancel the global context in such case
 	go trapSignals(os.Interrupt, syscall.SIGTERM, syscall.SIGKILL)
 
+	// Pause the transfers on SIGTSTP until SIGCONT.
+	go trapPauseSignals()
+
 	globalHelpPager = newTermPager()
 	// Wait until the user quits the pager
 	defer globalHelpPager.WaitForExit()
 
 	parsePagerDisableFlag(args)
+
+	// Save the traffic of the aliases, including when a command exits
+	// with a custom exit status.
+	cli.OsExiter = func(code int) {
+		globalUsage.flush()
+		os.Exit(code)
+	}
+	defer globalUsage.flush()
+
 	// Run the app
-	return registerApp(appName).Run(args)
+	if e := registerApp(appName).Run(args); e != nil {
+		return e
+	}
+	// Commands stop quietly on a canceled context, a timeout is an error.
+	if errors.Is(globalContext.Err(), context.DeadlineExceeded) {
+		fatal(probe.NewError(globalContext.Err()), "Unable to complete the command.")
+	}
+	exitIfStrictWarnings()
+	return nil
 }
 
 func flagValue(f cli.Flag) reflect.Value {
@@ -267,7 +311,8 @@ func migrate() {
 func initMC() {
 	// Check if mc config exists.
 	if !isMcConfigExists() {
-		err := saveMcConfig(newMcConfig())
+		// Another mc process may be writing it at the same time.
+		err := updateMcConfig(func(*configV10) *probe.Error { return nil })
 		fatalIf(err.Trace(), "Unable to save new mc config.")
 
 		if !globalQuiet && !globalJSON {
@@ -297,6 +342,9 @@ func initMC() {
 
 	// Load all authority certificates present in CAs dir
 	loadRootCAs()
+
+	// Load the certificate of --client-cert
+	loadClientCertificate()
 }
 
 func getShellName() (string, bool) {
@@ -374,9 +422,16 @@ func registerBefore(ctx *cli.Context) error {
 	// Migrate any old version of config / state files to newer format.
 	migrate()
 
+	// Offer to add an alias on the first run.
+	setupWizard := !isMcConfigExists() && wantSetupWizard(ctx)
+
 	// Initialize default config files.
 	initMC()
 
+	if setupWizard {
+		runSetupWizard(globalContext)
+	}
+
 	// Check if config can be read.
 	checkConfig()
 
@@ -422,11 +477,15 @@ func checkUpdate(ctx *cli.Context) {
 var appCmds = []cli.Command{
 	aliasCmd,
 	adminCmd,
+	analyzeCmd,
 	anonymousCmd,
+	auditCmd,
 	batchCmd,
+	completionCmd,
 	cpCmd,
 	catCmd,
 	configCmd,
+	daemonCmd,
 	diffCmd,
 	duCmd,
 	encryptCmd,
@@ -436,12 +495,14 @@ var appCmds = []cli.Command{
 	headCmd,
 	ilmCmd,
 	idpCmd,
+	jobCmd,
 	licenseCmd,
 	legalHoldCmd,
 	lsCmd,
 	mbCmd,
 	mvCmd,
 	mirrorCmd,
+	migrateCmd,
 	odCmd,
 	pingCmd,
 	policyCmd,
@@ -457,19 +518,20 @@ var appCmds = []cli.Command{
 	statCmd,
 	supportCmd,
 	shareCmd,
+	shellCmd,
+	snapshotCmd,
 	treeCmd,
 	tagCmd,
 	undoCmd,
 	updateCmd,
+	usageCmd,
+	verifyCmd,
 	versionCmd,
 	watchCmd,
 }
 
 func printMCVersion(c *cli.Context) {
-	fmt.Fprintf(c.App.Writer, "%s version %s (commit-id=%s)\n", c.App.Name, c.App.Version, CommitID)
-	fmt.Fprintf(c.App.Writer, "Runtime: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
-	fmt.Fprintf(c.App.Writer, "Copyright (c) 2015-%s MinIO, Inc.\n", CopyrightYear)
-	fmt.Fprintf(c.App.Writer, "License GNU AGPLv3 <https://www.gnu.org/licenses/agpl-3.0.html>\n")
+	newVersionMessage(c.App.Version).print(c.App.Writer, c.App.Name, c.Bool("json") || c.GlobalBool("json"))
 }
 
 func registerApp(name string) *cli.App {
@@ -525,7 +587,9 @@ func registerApp(name string) *cli.App {
 
 // mustGetProfilePath must get location that the profile will be written to.
 func mustGetProfileDir() string {
-	return filepath.Join(mustGetMcConfigDir(), globalProfileDir)
+	stateDir, err := getMcStateDir()
+	fatalIf(err.Trace(), "Unable to get the state folder.")
+	return filepath.Join(stateDir, globalProfileDir)
 }
 
 func showCommandHelpAndExit(cliCtx *cli.Context, code int) {
@@ -45,6 +45,10 @@ var mbFlags = []cli.Flag{
 		Name:  "with-versioning",
 		Usage: "enable versioned bucket",
 	},
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "report the bucket(s) that would be created, without creating them",
+	},
 }
 
 // make a bucket.
@@ -88,6 +92,9 @@ EXAMPLES:
 
   8. Create a new bucket on MinIO with versioning enabled.
      {{.Prompt}} {{.HelpName}} --with-versioning myminio/myversionedbucket
+
+  9. Report the buckets that would be created, without creating them.
+     {{.Prompt}} {{.HelpName}} --dry-run myminio/logs myminio/backups
 `,
 }
 
@@ -96,10 +103,14 @@ type makeBucketMessage struct {
 	Status string `json:"status"`
 	Bucket string `json:"bucket"`
 	Region string `json:"region"`
+	DryRun bool   `json:"dryRun,omitempty"`
 }
 
 // String colorized make bucket message.
 func (s makeBucketMessage) String() string {
+	if s.DryRun {
+		return "DRYRUN: " + console.Colorize("MakeBucket", "Creating bucket `"+s.Bucket+"`.")
+	}
 	return console.Colorize("MakeBucket", "Bucket created successfully `"+s.Bucket+"`.")
 }
 
@@ -144,6 +155,17 @@ func mainMakeBucket(cliCtx *cli.Context) error {
 		ctx, cancelMakeBucket := context.WithCancel(globalContext)
 		defer cancelMakeBucket()
 
+		if isDryRun(cliCtx) {
+			// Only report an existing bucket the same way mb would.
+			if _, err = clnt.Stat(ctx, StatOptions{}); err == nil && !ignoreExisting {
+				errorIf(errTargetExists(targetURL), "Unable to make bucket `"+targetURL+"`.")
+				cErr = exitStatus(globalErrorExitStatus)
+				continue
+			}
+			printMsg(makeBucketMessage{Status: "success", Bucket: targetURL, Region: region, DryRun: true})
+			continue
+		}
+
 		// Make bucket.
 		if err = clnt.MakeBucket(ctx, region, ignoreExisting, withLock); err != nil {
 			switch err.ToGoError().(type) {
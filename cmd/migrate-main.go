@@ -0,0 +1,222 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	colorjson "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+var migrateSubcommands = []cli.Command{
+	migratePlanCmd,
+	migrateRunCmd,
+}
+
+var migrateCmd = cli.Command{
+	Name:            "migrate",
+	Usage:           "plan and run the migration of a bucket or folder",
+	Action:          mainMigrate,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	Subcommands:     migrateSubcommands,
+	HideHelpCommand: true,
+}
+
+// mainMigrate is the handle for "mc migrate" command.
+func mainMigrate(ctx *cli.Context) error {
+	commandNotFound(ctx, migrateSubcommands)
+	return nil
+	// Sub-commands like "plan", "run" have their own main.
+}
+
+const migrationPlanVersion = "1"
+
+// Verification levels of a migration.
+const (
+	migrationVerifyNone     = "none"     // no verification
+	migrationVerifyListing  = "listing"  // compare source and target listings after the copy
+	migrationVerifyChecksum = "checksum" // also verify the checksum of each copied object
+)
+
+var migrationVerifyLevels = []string{migrationVerifyNone, migrationVerifyListing, migrationVerifyChecksum}
+
+// migrationPlan - the migration plan file written by `mc migrate plan`.
+type migrationPlan struct {
+	Version   string    `json:"version"`
+	Created   time.Time `json:"created"`
+	Source    string    `json:"source"`
+	Target    string    `json:"target"`
+	Exclude   []string  `json:"exclude,omitempty"`
+	Include   []string  `json:"include,omitempty"`
+	Bandwidth string    `json:"bandwidth,omitempty"`
+	Window    string    `json:"window,omitempty"`
+	Verify    string    `json:"verify"`
+	Report    string    `json:"report,omitempty"`
+}
+
+func (p migrationPlan) validate() *probe.Error {
+	if p.Version != migrationPlanVersion {
+		return probe.NewError(fmt.Errorf("unsupported migration plan version `%s`", p.Version))
+	}
+	if p.Source == "" || p.Target == "" {
+		return probe.NewError(fmt.Errorf("the migration plan needs a source and a target"))
+	}
+	if p.Bandwidth != "" {
+		if _, e := parseRateLimit(p.Bandwidth); e != nil {
+			return probe.NewError(e).Trace(p.Bandwidth)
+		}
+	}
+	if p.Window != "" {
+		if _, err := parseMigrationWindow(p.Window); err != nil {
+			return err.Trace(p.Window)
+		}
+	}
+	switch p.Verify {
+	case migrationVerifyNone:
+	case migrationVerifyListing, migrationVerifyChecksum:
+		if p.Report == "" {
+			return probe.NewError(fmt.Errorf("the migration plan needs a report file for `%s` verification", p.Verify))
+		}
+	default:
+		return probe.NewError(fmt.Errorf("unknown verification `%s`, valid values are %s", p.Verify, strings.Join(migrationVerifyLevels, ", ")))
+	}
+	return nil
+}
+
+// loadMigrationPlan - reads and validates the migration plan file, returns
+// its content too, identifying the copy session of the plan.
+func loadMigrationPlan(file string) (migrationPlan, []byte, *probe.Error) {
+	var plan migrationPlan
+	data, e := os.ReadFile(file)
+	if e != nil {
+		return plan, nil, probe.NewError(e)
+	}
+	if e = json.Unmarshal(data, &plan); e != nil {
+		return plan, nil, probe.NewError(e)
+	}
+	return plan, data, plan.validate()
+}
+
+func saveMigrationPlan(file string, plan migrationPlan) *probe.Error {
+	data, e := json.MarshalIndent(plan, "", "  ")
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if e = os.WriteFile(file, append(data, '\n'), 0o600); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// migrationReportFile - returns the default report file of the plan file.
+func migrationReportFile(planFile string) string {
+	return strings.TrimSuffix(planFile, filepath.Ext(planFile)) + ".report.jsonl"
+}
+
+// migrationWindow - a daily range of local time, such as 22:00-06:00,
+// ending the next day when it ends before its start.
+type migrationWindow struct {
+	start, end time.Duration // since midnight
+}
+
+func parseMigrationWindow(s string) (migrationWindow, *probe.Error) {
+	var w migrationWindow
+	bounds := strings.Split(s, "-")
+	if len(bounds) != 2 {
+		return w, probe.NewError(fmt.Errorf("invalid window `%s`, expected a range like 22:00-06:00", s))
+	}
+	for i, bound := range bounds {
+		t, e := time.Parse("15:04", strings.TrimSpace(bound))
+		if e != nil {
+			return w, probe.NewError(fmt.Errorf("invalid window `%s`, expected a range like 22:00-06:00", s))
+		}
+		offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+		if i == 0 {
+			w.start = offset
+		} else {
+			w.end = offset
+		}
+	}
+	if w.start == w.end {
+		return w, probe.NewError(fmt.Errorf("invalid window `%s`, it starts and ends at the same time", s))
+	}
+	return w, nil
+}
+
+// next - returns when the window open at now, or else the next window,
+// opens and closes.
+func (w migrationWindow) next(now time.Time) (opens, closes time.Time) {
+	length := w.end - w.start
+	if length < 0 {
+		length += 24 * time.Hour
+	}
+	for day := -1; ; day++ {
+		midnight := time.Date(now.Year(), now.Month(), now.Day()+day, 0, 0, 0, 0, now.Location())
+		opens = midnight.Add(w.start)
+		closes = opens.Add(length)
+		if closes.After(now) {
+			return opens, closes
+		}
+	}
+}
+
+// migrateMessage - reports the progress of a migration.
+type migrateMessage struct {
+	Status string     `json:"status"`
+	Event  string     `json:"event"` // saved, waiting, started or completed
+	Plan   string     `json:"plan"`
+	Source string     `json:"source"`
+	Target string     `json:"target"`
+	Opens  *time.Time `json:"opens,omitempty"`
+	Closes *time.Time `json:"closes,omitempty"`
+}
+
+// String colorized migration message
+func (m migrateMessage) String() string {
+	switch m.Event {
+	case "saved":
+		return console.Colorize("Migrate", fmt.Sprintf("Saved the migration plan `%s`, start it with `mc migrate run %s`.", m.Plan, m.Plan))
+	case "waiting":
+		return console.Colorize("Migrate", fmt.Sprintf("Waiting for the migration window opening at %s.", m.Opens.Local().Format(printDate)))
+	case "started":
+		if m.Closes != nil {
+			return console.Colorize("Migrate", fmt.Sprintf("Migrating `%s` to `%s` until the window closes at %s.",
+				m.Source, m.Target, m.Closes.Local().Format(printDate)))
+		}
+		return console.Colorize("Migrate", fmt.Sprintf("Migrating `%s` to `%s`.", m.Source, m.Target))
+	default:
+		return console.Colorize("Migrate", fmt.Sprintf("Migrated `%s` to `%s`.", m.Source, m.Target))
+	}
+}
+
+// JSON jsonified migration message
+func (m migrateMessage) JSON() string {
+	m.Status = "success"
+	msgBytes, e := colorjson.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
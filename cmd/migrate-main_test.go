@@ -0,0 +1,163 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/cli"
+)
+
+func TestMigrationWindow(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.March, day, hour, minute, 0, 0, time.Local)
+	}
+	testCases := []struct {
+		window        string
+		now           time.Time
+		opens, closes time.Time
+	}{
+		{"09:00-17:00", at(10, 8, 0), at(10, 9, 0), at(10, 17, 0)},
+		{"09:00-17:00", at(10, 12, 0), at(10, 9, 0), at(10, 17, 0)},
+		{"09:00-17:00", at(10, 17, 0), at(11, 9, 0), at(11, 17, 0)},
+		// Windows over midnight.
+		{"22:00-06:00", at(10, 3, 0), at(9, 22, 0), at(10, 6, 0)},
+		{"22:00-06:00", at(10, 12, 0), at(10, 22, 0), at(11, 6, 0)},
+		{"22:00-06:00", at(10, 23, 30), at(10, 22, 0), at(11, 6, 0)},
+	}
+	for i, testCase := range testCases {
+		w, err := parseMigrationWindow(testCase.window)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		opens, closes := w.next(testCase.now)
+		if !opens.Equal(testCase.opens) || !closes.Equal(testCase.closes) {
+			t.Errorf("Test %d: expected %s-%s, got %s-%s", i+1, testCase.opens, testCase.closes, opens, closes)
+		}
+	}
+
+	for _, window := range []string{"", "22:00", "9-17", "25:00-06:00", "10:00-10:00"} {
+		if _, err := parseMigrationWindow(window); err == nil {
+			t.Errorf("Window %q: expected an error", window)
+		}
+	}
+}
+
+func TestMigration(t *testing.T) {
+	useTestMcConfig(t)
+	if err := createSessionDir(); err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	source, target := filepath.Join(root, "src"), filepath.Join(root, "tgt")
+	for name, content := range map[string]string{
+		"a.txt":       "a",
+		"dir/b.txt":   "bb",
+		"dir/c.tmp":   "ccc",
+		"dir/d/e.txt": "eeee",
+	} {
+		name = filepath.Join(source, name)
+		if e := os.MkdirAll(filepath.Dir(name), 0o755); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(name, []byte(content), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	// Invalid answers are asked again.
+	planFile := filepath.Join(root, "plan.json")
+	answers := strings.Join([]string{
+		filepath.Join(root, "missing"), source, target,
+		"*.tmp", "",
+		"fast", "1MiB/s",
+		"22:00", "",
+		"all", "",
+		"",
+		"y",
+	}, "\n") + "\n"
+	var out bytes.Buffer
+	plan, ok := interviewMigration(context.Background(), prompt{in: bufio.NewReader(strings.NewReader(answers)), out: &out}, planFile)
+	if !ok {
+		t.Fatalf("Expected the plan to be confirmed:\n%s", out.String())
+	}
+	expected := migrationPlan{
+		Version:   migrationPlanVersion,
+		Created:   plan.Created,
+		Source:    source,
+		Target:    target,
+		Exclude:   []string{"*.tmp"},
+		Bandwidth: "1MiB/s",
+		Verify:    migrationVerifyListing,
+		Report:    filepath.Join(root, "plan.report.jsonl"),
+	}
+	if !reflect.DeepEqual(plan, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, plan)
+	}
+	if err := saveMigrationPlan(planFile, plan); err != nil {
+		t.Fatal(err)
+	}
+
+	limitUpload, limitDownload := globalLimitUpload, globalLimitDownload
+	t.Cleanup(func() { globalLimitUpload, globalLimitDownload = limitUpload, limitDownload })
+	app := cli.NewApp()
+	app.Flags = globalFlags
+	app.Commands = []cli.Command{migrateCmd}
+	if e := app.Run([]string{"mc", "migrate", "run", planFile}); e != nil {
+		t.Fatal(e)
+	}
+
+	for name, exists := range map[string]bool{
+		"a.txt":       true,
+		"dir/b.txt":   true,
+		"dir/c.tmp":   false,
+		"dir/d/e.txt": true,
+	} {
+		if _, e := os.Stat(filepath.Join(target, name)); (e == nil) != exists {
+			t.Errorf("%s: expected exists %v, got %v", name, exists, e)
+		}
+	}
+
+	f, e := os.Open(plan.Report)
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer f.Close()
+	data, e := io.ReadAll(f)
+	if e != nil {
+		t.Fatal(e)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	var summary mirrorVerifyRecord
+	if e = json.Unmarshal([]byte(lines[len(lines)-1]), &summary); e != nil {
+		t.Fatal(e)
+	}
+	if summary.Type != "summary" || summary.Objects != 3 || summary.Match == nil || !*summary.Match {
+		t.Errorf("Expected a matching summary of 3 objects, got %s", lines[len(lines)-1])
+	}
+}
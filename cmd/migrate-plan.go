@@ -0,0 +1,182 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/pkg/v2/console"
+)
+
+var migratePlanCmd = cli.Command{
+	Name:         "plan",
+	Usage:        "write a migration plan, asking for its source, target and options",
+	Action:       mainMigratePlan,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} FILE
+
+  The plan asks for:
+    - the source, a bucket, a prefix or a local folder, and the target,
+    - the patterns of the objects to exclude or include,
+    - the bandwidth limit and the daily window of the migration,
+    - the verification: "none", "listing" to compare the source and target
+      listings after the copy, or "checksum" to also verify the checksum of
+      each copied object, with the file of the signed verification report.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Write the plan of a migration to the file "photos.json".
+     {{.Prompt}} {{.HelpName}} photos.json
+`,
+}
+
+// migrationURL - returns aliased URLs as is and local paths as absolute
+// paths, so that a plan runs the same from any folder.
+func migrationURL(url string) string {
+	if _, _, aliasCfg := mustExpandAlias(url); aliasCfg != nil {
+		return url
+	}
+	if abs, e := filepath.Abs(url); e == nil {
+		return abs
+	}
+	return url
+}
+
+// splitMigrationList - splits a comma separated answer.
+func splitMigrationList(answer string) (list []string) {
+	for _, s := range strings.Split(answer, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// interviewMigration - asks for the migration plan saved to planFile,
+// returns false when the plan is not confirmed.
+func interviewMigration(ctx context.Context, w prompt, planFile string) (migrationPlan, bool) {
+	plan := migrationPlan{
+		Version: migrationPlanVersion,
+		Created: UTCNow(),
+	}
+
+	for plan.Source == "" {
+		source := w.require("Source bucket, prefix or folder")
+		_, content, err := url2Stat(ctx, url2StatOptions{urlStr: source})
+		switch {
+		case err != nil:
+			fmt.Fprintf(w.out, "Unable to access `%s`: %s\n", source, err.ToGoError())
+		case !content.Type.IsDir():
+			fmt.Fprintf(w.out, "`%s` is not a bucket, a prefix or a folder.\n", source)
+		default:
+			plan.Source = migrationURL(source)
+		}
+	}
+	for plan.Target == "" {
+		target := w.require("Target bucket, prefix or folder")
+		if _, err := newClient(target); err != nil {
+			fmt.Fprintf(w.out, "Unable to access `%s`: %s\n", target, err.ToGoError())
+			continue
+		}
+		plan.Target = migrationURL(target)
+	}
+
+	plan.Exclude = splitMigrationList(w.ask("Exclude objects matching, comma separated patterns like *.tmp", ""))
+	plan.Include = splitMigrationList(w.ask("Include only objects matching, comma separated patterns", ""))
+
+	for {
+		plan.Bandwidth = w.ask("Bandwidth limit like 50MiB/s, empty for none", "")
+		if plan.Bandwidth == "" {
+			break
+		}
+		if _, e := parseRateLimit(plan.Bandwidth); e == nil {
+			break
+		}
+		fmt.Fprintf(w.out, "Invalid bandwidth `%s`.\n", plan.Bandwidth)
+	}
+	for {
+		plan.Window = w.ask("Daily window like 22:00-06:00, empty for any time", "")
+		if plan.Window == "" {
+			break
+		}
+		_, err := parseMigrationWindow(plan.Window)
+		if err == nil {
+			break
+		}
+		fmt.Fprintln(w.out, err.ToGoError())
+	}
+
+	for {
+		plan.Verify = strings.ToLower(w.ask("Verification ("+strings.Join(migrationVerifyLevels, ", ")+")", migrationVerifyListing))
+		if plan.Verify == migrationVerifyNone || plan.Verify == migrationVerifyListing || plan.Verify == migrationVerifyChecksum {
+			break
+		}
+		fmt.Fprintf(w.out, "Unknown verification `%s`.\n", plan.Verify)
+	}
+	if plan.Verify != migrationVerifyNone {
+		plan.Report = migrationURL(w.ask("Verification report file", migrationReportFile(planFile)))
+	}
+
+	fmt.Fprintf(w.out, "Migrate `%s` to `%s`", plan.Source, plan.Target)
+	if plan.Window != "" {
+		fmt.Fprintf(w.out, " between %s", plan.Window)
+	}
+	if plan.Bandwidth != "" {
+		fmt.Fprintf(w.out, " at most at %s", plan.Bandwidth)
+	}
+	fmt.Fprintf(w.out, ", verification %s.\n", plan.Verify)
+	return plan, w.confirm("Save the plan?", true)
+}
+
+// mainMigratePlan is the handle for "mc migrate plan" command.
+func mainMigratePlan(cliCtx *cli.Context) error {
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	console.SetColor("Migrate", color.New(color.FgGreen, color.Bold))
+
+	planFile := cliCtx.Args().Get(0)
+	plan, ok := interviewMigration(globalContext, prompt{in: bufio.NewReader(os.Stdin), out: os.Stdout}, planFile)
+	if !ok {
+		return nil
+	}
+	fatalIf(saveMigrationPlan(planFile, plan).Trace(planFile), "Unable to save the migration plan.")
+
+	printMsg(migrateMessage{
+		Event:  "saved",
+		Plan:   planFile,
+		Source: plan.Source,
+		Target: plan.Target,
+	})
+	return nil
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"flag"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+var migrateRunFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:   "verify-key",
+		Usage:  "secret key signing the verification report with a chain of HMAC-SHA256",
+		EnvVar: envPrefix + "VERIFY_KEY",
+	},
+}
+
+var migrateRunCmd = cli.Command{
+	Name:         "run",
+	Usage:        "run or resume a migration plan",
+	Action:       mainMigrateRun,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(migrateRunFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} FILE
+
+  The migration copies the objects of the source missing on the target in a
+  copy session, saved after every object. An interrupted migration resumes
+  from the last copied object when run again. With a daily window, the
+  migration waits for the window to open and stops when it closes, to be
+  run again in the next window. The verification report is written once
+  all objects are copied.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Run the migration plan "photos.json", written by "mc migrate plan".
+     {{.Prompt}} {{.HelpName}} photos.json
+
+  2. Run the migration plan "photos.json", signing its verification report.
+     {{.Prompt}} {{.HelpName}} --verify-key "my secret" photos.json
+`,
+}
+
+// migrationCopyContext - returns the context of the cp command copying the
+// content of the plan source to its target.
+func migrationCopyContext(cliCtx *cli.Context, plan migrationPlan) (*cli.Context, *probe.Error) {
+	_, expandedSource, _ := mustExpandAlias(plan.Source)
+	separator := string(newClientURL(expandedSource).Separator)
+
+	args := []string{"--recursive", "--continue"}
+	for _, pattern := range plan.Exclude {
+		args = append(args, "--exclude", pattern)
+	}
+	for _, pattern := range plan.Include {
+		args = append(args, "--include", pattern)
+	}
+	if plan.Verify == migrationVerifyChecksum {
+		args = append(args, "--verify")
+	}
+	// With a trailing separator the content of the source is copied.
+	args = append(args, strings.TrimSuffix(plan.Source, separator)+separator, plan.Target)
+
+	set := flag.NewFlagSet(cpCmd.Name, flag.ContinueOnError)
+	for _, f := range cpCmd.Flags {
+		f.Apply(set)
+	}
+	if e := set.Parse(args); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return cli.NewContext(cliCtx.App, set, cliCtx), nil
+}
+
+// mainMigrateRun is the handle for "mc migrate run" command.
+func mainMigrateRun(cliCtx *cli.Context) error {
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	console.SetColor("Migrate", color.New(color.FgGreen, color.Bold))
+	console.SetColor("Mirror", color.New(color.FgGreen, color.Bold))
+	console.SetColor("MakeBucket", color.New(color.FgGreen, color.Bold))
+
+	planFile := cliCtx.Args().Get(0)
+	plan, data, err := loadMigrationPlan(planFile)
+	fatalIf(err.Trace(planFile), "Unable to load the migration plan.")
+
+	// The limit of the plan applies unless set by --limit-upload or --limit-download.
+	if plan.Bandwidth != "" && globalLimitUpload == 0 && globalLimitDownload == 0 {
+		rate, _ := parseRateLimit(plan.Bandwidth)
+		globalLimitUpload, globalLimitDownload = rate, rate
+	}
+
+	msg := migrateMessage{
+		Event:  "started",
+		Plan:   planFile,
+		Source: plan.Source,
+		Target: plan.Target,
+	}
+	if plan.Window != "" {
+		window, _ := parseMigrationWindow(plan.Window)
+		opens, closes := window.next(time.Now())
+		if wait := time.Until(opens); wait > 0 {
+			printMsg(migrateMessage{Event: "waiting", Plan: planFile, Source: plan.Source, Target: plan.Target, Opens: &opens})
+			select {
+			case <-globalContext.Done():
+				return nil
+			case <-time.After(wait):
+			}
+		}
+		msg.Closes = &closes
+
+		// The copy session is saved and stopped when the window closes.
+		ctx, cancel := context.WithDeadline(globalContext, closes)
+		defer cancel()
+		parentCtx := globalContext
+		globalContext = ctx
+		defer func() { globalContext = parentCtx }()
+	}
+	printMsg(msg)
+
+	dryRun := isDryRun(cliCtx)
+	if _, _, aliasCfg := mustExpandAlias(plan.Target); aliasCfg != nil {
+		bucketMsg, err := createMirrorDestBucket(globalContext, plan.Target, "", false, dryRun)
+		fatalIf(err.Trace(plan.Target), "Unable to prepare the target bucket.")
+		if bucketMsg != nil {
+			printMsg(bucketMsg)
+		}
+	}
+
+	cpCtx, err := migrationCopyContext(cliCtx, plan)
+	fatalIf(err.Trace(planFile), "Unable to prepare the migration.")
+
+	// The session of the plan is identified by its file and content, a
+	// changed plan starts again from the first object.
+	absPlanFile, e := filepath.Abs(planFile)
+	fatalIf(probe.NewError(e), "Unable to get the path of the migration plan.")
+	if e = runCopy(cpCtx, getHash("migrate", []string{absPlanFile, string(data)})); e != nil {
+		return e
+	}
+
+	if plan.Verify != migrationVerifyNone && !dryRun {
+		srcClt, err := newClient(plan.Source)
+		fatalIf(err.Trace(plan.Source), "Unable to initialize `"+plan.Source+"`.")
+		dstClt, err := newClient(plan.Target)
+		fatalIf(err.Trace(plan.Target), "Unable to initialize `"+plan.Target+"`.")
+
+		verifyMsg, err := verifyMirror(globalContext, srcClt, dstClt, plan.Source, plan.Target, plan.Report,
+			[]byte(cliCtx.String("verify-key")), mirrorOptions{filter: newListFilter(cpCtx)})
+		fatalIf(err.Trace(plan.Source, plan.Target), "Unable to verify the migration.")
+		printMsg(verifyMsg)
+		if !verifyMsg.Match {
+			errorIf(errDummy().Trace(plan.Source, plan.Target), "Source and target do not match, see `"+plan.Report+"`.")
+			return exitStatus(globalErrorExitStatus)
+		}
+	}
+
+	msg.Event, msg.Closes = "completed", nil
+	printMsg(msg)
+	return nil
+}
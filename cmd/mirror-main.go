@@ -27,6 +27,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/fatih/color"
@@ -67,10 +68,48 @@ var (
 			Name:  "watch, w",
 			Usage: "watch and synchronize changes",
 		},
+		cli.DurationFlag{
+			Name:  "reconcile-interval",
+			Usage: "with --watch, compare source and target again after every interval to copy the changes missed by the watch",
+		},
 		cli.BoolFlag{
 			Name:  "remove",
 			Usage: "remove extraneous object(s) on target",
 		},
+		cli.IntFlag{
+			Name:  "restore-days",
+			Usage: "restore archived (GLACIER, DEEP_ARCHIVE) source object(s) for N days and copy them once restored",
+		},
+		cli.DurationFlag{
+			Name:  "restore-poll",
+			Usage: "initial interval between restore status checks, doubled up to 1h while waiting",
+			Value: time.Minute,
+		},
+		cli.StringFlag{
+			Name:  "name-template",
+			Usage: "rewrite target object names with a Go template (e.g. '{{.Dir}}/{{.NameLower}}{{.ExtLower}}')",
+		},
+		cli.BoolFlag{
+			Name:  "verify",
+			Usage: "verify the checksum of each copied object against its source",
+		},
+		cli.StringFlag{
+			Name:  "verify-report",
+			Usage: "compare source and target after mirroring and write a signed verification report to FILE",
+		},
+		cli.StringFlag{
+			Name:   "verify-key",
+			Usage:  "secret key signing the verification report with a chain of HMAC-SHA256",
+			EnvVar: envPrefix + "VERIFY_KEY",
+		},
+		cli.Int64Flag{
+			Name:  "max-delete",
+			Usage: "abort --remove if more than N object(s) would be removed from target",
+		},
+		cli.Float64Flag{
+			Name:  "max-delete-percent",
+			Usage: "abort --remove if more than PERCENT of the object(s) on target would be removed",
+		},
 		cli.StringFlag{
 			Name:  "region",
 			Usage: "specify region when creating new bucket(s) on target",
@@ -94,20 +133,33 @@ var (
 			Usage: "enable active-active multi-site setup",
 		},
 		cli.BoolFlag{
-			Name:  "disable-multipart",
-			Usage: "disable multipart upload feature",
+			Name:   "disable-multipart",
+			Usage:  "disable multipart upload feature",
+			EnvVar: envPrefix + "DISABLE_MULTIPART",
 		},
 		cli.StringSliceFlag{
 			Name:  "exclude",
 			Usage: "exclude object(s) that match specified object name pattern",
 		},
+		cli.StringSliceFlag{
+			Name:  "include",
+			Usage: "only mirror object(s) that match specified object name pattern",
+		},
+		cli.BoolFlag{
+			Name:  "skip-dir-markers",
+			Usage: "skip the zero byte objects named like a folder, e.g. 'photos/', instead of mirroring them as folders",
+		},
+		cli.StringFlag{
+			Name:  "name-conflict",
+			Usage: "keep the 'object' or the 'prefix' of an object named like a prefix, when mirroring to a filesystem",
+		},
 		cli.StringSliceFlag{
 			Name:  "exclude-bucket",
 			Usage: "exclude bucket(s) that match specified bucket name pattern",
 		},
 		cli.StringSliceFlag{
-			Name:  "exclude-storageclass",
-			Usage: "exclude object(s) that match the specified storage class",
+			Name:  "exclude-storageclass, ignore-storage-class",
+			Usage: "exclude object(s) stored in the specified storage class on source or target",
 		},
 		cli.StringFlag{
 			Name:  "older-than",
@@ -117,9 +169,22 @@ var (
 			Name:  "newer-than",
 			Usage: "filter object(s) newer than value in duration string (e.g. 7d10h31s)",
 		},
+		cli.BoolFlag{
+			Name:  "create-dest",
+			Usage: "create the destination bucket in --region if it does not exist",
+		},
+		cli.BoolFlag{
+			Name:  "trailing-slash",
+			Usage: "follow rsync trailing slash semantics, 'dir' mirrors the folder itself and 'dir/' its contents",
+		},
+		cli.BoolFlag{
+			Name:  "newer",
+			Usage: "compare modification time only, object(s) on target older than the source are replaced with --overwrite",
+		},
 		cli.StringFlag{
-			Name:  "storage-class, sc",
-			Usage: "specify storage class for new object(s) on target",
+			Name:   "storage-class, sc",
+			Usage:  "specify storage class for new object(s) on target",
+			EnvVar: envPrefix + "STORAGE_CLASS",
 		},
 		cli.StringFlag{
 			Name:  "attr",
@@ -151,7 +216,7 @@ var mirrorCmd = cli.Command{
 	Action:       mainMirror,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(append(mirrorFlags, ioFlags...), globalFlags...),
+	Flags:        append(append(append(append(append(append(append(mirrorFlags, reportHTMLFlags...), downloadScanFlags...), downloadSparseFlags...), transferConfirmFlags...), multipartFlags...), ioFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -162,8 +227,10 @@ FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
 ENVIRONMENT VARIABLES:
-   MC_ENCRYPT:      list of comma delimited prefixes
-   MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+   MC_ENCRYPT:        list of comma delimited prefixes
+   MC_ENCRYPT_KEY:    list of comma delimited prefix=secret values
+   MC_SFTP_IDENTITY:  private key file used for sftp:// URLs, instead of ~/.ssh/id_*
+   MC_SFTP_PASSWORD:  password used for sftp:// URLs when key authentication fails
 
 EXAMPLES:
   01. Mirror a bucket recursively from MinIO cloud storage to a bucket on Amazon S3 cloud storage.
@@ -222,6 +289,67 @@ EXAMPLES:
   17. Cross mirror between sites in a active-active deployment.
       Site-A: {{.Prompt}} {{.HelpName}} --active-active siteA siteB
       Site-B: {{.Prompt}} {{.HelpName}} --active-active siteB siteA
+
+  18. Mirror a local folder to Amazon S3 cloud storage, only uploading files modified since their last upload.
+      {{.Prompt}} {{.HelpName}} --newer --overwrite backup/ s3/archive
+
+  19. Mirror the local folder 'backup' itself into 's3/archive/backup', as rsync does without a trailing slash.
+      {{.Prompt}} {{.HelpName}} --trailing-slash backup s3/archive
+
+  20. Mirror a local folder into a bucket that may not exist yet, creating it in the 'eu-west-1' region.
+      {{.Prompt}} {{.HelpName}} --create-dest --region eu-west-1 backup/ s3/new-archive/backup
+
+  21. Mirror a local folder and remove extraneous objects on target, unless more than 10% of them would be removed.
+      {{.Prompt}} {{.HelpName}} --remove --max-delete-percent 10 backup/ s3/archive
+
+  22. Mirror a bucket and write a report, signed with the key in MC_VERIFY_KEY, proving that source and target matched once done.
+      {{.Prompt}} {{.HelpName}} --verify-report /var/log/mirror-photos.jsonl play/photos s3/backup-photos
+
+  23. Mirror a bucket, leaving objects archived to GLACIER on either side untouched.
+      {{.Prompt}} {{.HelpName}} --ignore-storage-class GLACIER --remove s3/photos play/photos
+
+  24. Mirror a bucket with archived objects, restoring them for 2 days first. Running the
+      same command again after an interruption waits for the restores already requested.
+      {{.Prompt}} {{.HelpName}} --restore-days 2 s3/archive play/archive
+
+  25. Mirror a bucket to a directory of a remote server over SFTP, authenticating with the SSH agent.
+      {{.Prompt}} {{.HelpName}} play/photos sftp://backup@backup.example.com/srv/photos/
+
+  26. Mirror a local folder to a bucket over a shared link, uploading at most 10MiB/s in total.
+      {{.Prompt}} {{.HelpName}} --limit-upload 10MiB/s --watch /var/www/ myminio/www/
+
+  27. Mirror only the Go sources of a project, skipping the 'vendor/' folder.
+      {{.Prompt}} {{.HelpName}} --include "*.go" --exclude "vendor/" ~/project play/backup/project
+
+  28. Mirror a folder, lower-casing all the object names on the target.
+      {{.Prompt}} {{.HelpName}} --name-template '{{"{{"}}.Dir{{"}}"}}/{{"{{"}}.NameLower{{"}}"}}{{"{{"}}.ExtLower{{"}}"}}' ./DCIM/ play/photos/
+
+  29. Mirror a folder of large files in 128MiB parts, uploading 2 parts of each file in parallel.
+      {{.Prompt}} {{.HelpName}} --part-size 128MiB --parallel-parts 2 ./videos/ play/videos/
+
+  30. Mirror a bucket, verifying the checksum of each copied object against its source object.
+      {{.Prompt}} {{.HelpName}} --verify play/photos s3/backup-photos
+
+  31. Mirror a bucket to a local folder, keeping the objects under a prefix, e.g. 'logs/today.log', over an object named like it, e.g. 'logs'.
+      {{.Prompt}} {{.HelpName}} --name-conflict prefix play/mybucket /mnt/backup
+
+  32. Keep a local folder synchronized to a bucket, deletes included, comparing them again every hour.
+      {{.Prompt}} {{.HelpName}} --watch --remove --reconcile-interval 1h /var/lib/data play/backup
+
+  33. Mirror a bucket to a local folder, without the empty folder markers created by other tools.
+      {{.Prompt}} {{.HelpName}} --skip-dir-markers s3/mybucket /mnt/backup
+
+  34. Mirror a bucket to a local folder, scanning the objects with an ICAP antivirus server.
+      {{.Prompt}} {{.HelpName}} --scan-icap icap://127.0.0.1:1344/avscan --quarantine-dir /var/quarantine s3/uploads /mnt/uploads
+
+  35. Mirror a local folder to a bucket, asking to confirm the mirror first when larger than 1TiB.
+      {{.Prompt}} {{.HelpName}} --confirm-above 1TiB backup/ prod-backup/archive
+
+  36. Mirror a bucket of disk images to a local folder as sparse files, downloading only the changed parts of the images updated.
+      {{.Prompt}} {{.HelpName}} --overwrite --sparse --skip-matching-parts play/images /var/lib/images
+
+  37. Mirror a bucket to another site, and save the objects copied and removed per prefix and the failures in a page attached to a change ticket.
+      {{.Prompt}} {{.HelpName}} --remove --report-html mirror-report.html s3/mybucket dr/mybucket
 `,
 }
 
@@ -255,6 +383,12 @@ var (
 const uaMirrorAppName = "mc-mirror"
 
 type mirrorJob struct {
+	// Number of objects copied.
+	// Keep this as first element of struct because it guarantees 64bit
+	// alignment on 32 bit machines. atomic.* functions crash if operand is not
+	// aligned at 64bit. See https://github.com/golang/go/issues/599
+	copied int64
+
 	stopCh chan struct{}
 
 	// the global watcher object, which receives notifications of created
@@ -276,6 +410,14 @@ type mirrorJob struct {
 	targetURL string
 
 	opts mirrorOptions
+
+	// holds back archived source objects until restored
+	restorer *mirrorRestorer
+
+	// accumulates the objects copied and removed for --report-html,
+	// named relative to targetPath
+	report     *runReport
+	targetPath string
 }
 
 // mirrorMessage container for file mirror messages
@@ -286,11 +428,16 @@ type mirrorMessage struct {
 	Size       int64  `json:"size"`
 	TotalCount int64  `json:"totalCount"`
 	TotalSize  int64  `json:"totalSize"`
+	DryRun     bool   `json:"dryRun,omitempty"`
 }
 
 // String colorized mirror message
 func (m mirrorMessage) String() string {
-	return console.Colorize("Mirror", fmt.Sprintf("`%s` -> `%s`", m.Source, m.Target))
+	msg := console.Colorize("Mirror", fmt.Sprintf("`%s` -> `%s`", m.Source, m.Target))
+	if m.DryRun {
+		msg = "DRYRUN: " + msg
+	}
+	return msg
 }
 
 // JSON jsonified mirror message
@@ -440,6 +587,16 @@ func (mj *mirrorJob) doMirror(ctx context.Context, sURLs URLs) URLs {
 	// and accounting readers under relevant conditions.
 	if mj.opts.isFake {
 		if sURLs.SourceContent != nil {
+			if !mj.opts.isSummary {
+				mj.status.PrintMsg(mirrorMessage{
+					Source:     filepath.ToSlash(filepath.Join(sURLs.SourceAlias, sURLs.SourceContent.URL.Path)),
+					Target:     filepath.ToSlash(filepath.Join(sURLs.TargetAlias, sURLs.TargetContent.URL.Path)),
+					Size:       sURLs.SourceContent.Size,
+					TotalCount: sURLs.TotalCount,
+					TotalSize:  sURLs.TotalSize,
+					DryRun:     true,
+				})
+			}
 			mj.status.Add(sURLs.SourceContent.Size)
 		}
 		mj.status.Update()
@@ -491,9 +648,22 @@ func (mj *mirrorJob) doMirror(ctx context.Context, sURLs URLs) URLs {
 
 	var ret URLs
 
+	uploadOpts := uploadSourceToTargetURLOpts{
+		urls:               sURLs,
+		progress:           mj.status,
+		encKeyDB:           mj.opts.encKeyDB,
+		preserve:           mj.opts.isMetadata,
+		multipartSize:      mj.opts.multipartSize,
+		multipartThreads:   mj.opts.multipartThreads,
+		multipartThreshold: mj.opts.multipartThreshold,
+		verify:             mj.opts.verify,
+		scanner:            mj.opts.scanner,
+		sparse:             mj.opts.sparse,
+		skipMatchingParts:  mj.opts.skipMatchingParts,
+	}
 	if !mj.opts.isRetriable {
 		now := time.Now()
-		ret = uploadSourceToTargetURL(ctx, uploadSourceToTargetURLOpts{urls: sURLs, progress: mj.status, encKeyDB: mj.opts.encKeyDB, preserve: mj.opts.isMetadata, isZip: false})
+		ret = uploadSourceToTargetURL(ctx, uploadOpts)
 		if ret.Error == nil {
 			durationMs := time.Since(now).Milliseconds()
 			mirrorReplicationDurations.With(prometheus.Labels{"object_size": convertSizeToTag(sURLs.SourceContent.Size)}).Observe(float64(durationMs))
@@ -512,12 +682,16 @@ func (mj *mirrorJob) doMirror(ctx context.Context, sURLs URLs) URLs {
 		}
 
 		now := time.Now()
-		ret = uploadSourceToTargetURL(ctx, uploadSourceToTargetURLOpts{urls: sURLs, progress: mj.status, encKeyDB: mj.opts.encKeyDB, preserve: mj.opts.isMetadata, isZip: false})
+		ret = uploadSourceToTargetURL(ctx, uploadOpts)
 		if ret.Error == nil {
 			durationMs := time.Since(now).Milliseconds()
 			mirrorReplicationDurations.With(prometheus.Labels{"object_size": convertSizeToTag(sURLs.SourceContent.Size)}).Observe(float64(durationMs))
 		}
 
+		if isObjectRefused(ret.Error) {
+			// The object is refused again at the next try.
+			return nil
+		}
 		return ret.Error
 	})
 
@@ -548,7 +722,15 @@ func (mj *mirrorJob) monitorMirrorStatus(cancel context.CancelFunc) (errDuringMi
 
 			switch {
 			case sURLs.SourceContent != nil:
-				if isErrIgnored(sURLs.Error) {
+				if isObjectRefused(sURLs.Error) {
+					// Infected or rejected objects fail the mirror without stopping it.
+					errorIf(sURLs.Error.Trace(sURLs.SourceContent.URL.String()),
+						fmt.Sprintf("Failed to copy `%s`.", sURLs.SourceContent.URL.String()))
+					mirrorFailedOps.Inc()
+					mj.reportFailure(sURLs)
+					errDuringMirror = true
+					ignoreErr = true
+				} else if isErrIgnored(sURLs.Error) {
 					ignoreErr = true
 				} else {
 					switch sURLs.Error.ToGoError().(type) {
@@ -561,7 +743,8 @@ func (mj *mirrorJob) monitorMirrorStatus(cancel context.CancelFunc) (errDuringMi
 							errorIf(sURLs.Error.Trace(sURLs.SourceContent.URL.String()),
 								fmt.Sprintf("Failed to copy `%s`.", sURLs.SourceContent.URL.String()))
 						} else {
-							console.Infof("[Warn] Failed to copy `%s`. %s", sURLs.SourceContent.URL.String(), sURLs.Error.Trace(sURLs.SourceContent.URL.String()))
+							warningIf(sURLs.Error.Trace(sURLs.SourceContent.URL.String()),
+								"Failed to copy `%s`.", sURLs.SourceContent.URL.String())
 						}
 					}
 				}
@@ -583,6 +766,7 @@ func (mj *mirrorJob) monitorMirrorStatus(cancel context.CancelFunc) (errDuringMi
 
 			if !ignoreErr {
 				mirrorFailedOps.Inc()
+				mj.reportFailure(sURLs)
 				errDuringMirror = true
 				// Quit mirroring if --watch and --active-active are not passed
 				if !mj.opts.skipErrors && !mj.opts.activeActive && !mj.opts.isWatch {
@@ -595,17 +779,71 @@ func (mj *mirrorJob) monitorMirrorStatus(cancel context.CancelFunc) (errDuringMi
 		}
 
 		if sURLs.SourceContent != nil {
+			atomic.AddInt64(&mj.copied, 1)
 			mirrorTotalUploadedBytes.Add(float64(sURLs.SourceContent.Size))
+			mj.reportObject(sURLs, mirrorReportCopied, sURLs.SourceContent.Size)
 		} else if sURLs.TargetContent != nil {
+			mj.reportObject(sURLs, mirrorReportRemoved, sURLs.TargetContent.Size)
 			// Construct user facing message and path.
 			targetPath := filepath.ToSlash(filepath.Join(sURLs.TargetAlias, sURLs.TargetContent.URL.Path))
-			mj.status.PrintMsg(rmMessage{Key: targetPath})
+			mj.status.PrintMsg(rmMessage{Key: targetPath, DryRun: mj.opts.isFake})
 		}
 	}
 
 	return
 }
 
+// Kinds of the objects of the mirror report.
+const (
+	mirrorReportCopied = iota
+	mirrorReportRemoved
+)
+
+// newMirrorReport - returns the report of --report-html.
+func newMirrorReport(srcURL, dstURL string, isFake bool) *runReport {
+	title := fmt.Sprintf("Mirror of %s to %s", srcURL, dstURL)
+	if isFake {
+		title += " (dry run)"
+	}
+	return newRunReport(title, "Copied", "Removed")
+}
+
+// reportObject - counts the object of sURLs copied or removed in the
+// report, if any.
+func (mj *mirrorJob) reportObject(sURLs URLs, kind int, size int64) {
+	if mj.report == nil || sURLs.TargetContent == nil {
+		return
+	}
+	u := sURLs.TargetContent.URL
+	mj.report.add(relativeName(mirrorReportPath(u), mj.targetPath, u.Separator), kind, size)
+}
+
+// mirrorReportPath - returns the path of u, absolute on the local
+// filesystem, as copied objects have relative paths.
+func mirrorReportPath(u ClientURL) string {
+	if u.Type == fileSystem {
+		if path, e := filepath.Abs(u.Path); e == nil {
+			return path
+		}
+	}
+	return u.Path
+}
+
+// reportFailure - lists the failure of sURLs in the report, if any.
+func (mj *mirrorJob) reportFailure(sURLs URLs) {
+	if mj.report == nil {
+		return
+	}
+	var urlStr string
+	switch {
+	case sURLs.SourceContent != nil:
+		urlStr = sURLs.SourceContent.URL.String()
+	case sURLs.TargetContent != nil:
+		urlStr = sURLs.TargetContent.URL.String()
+	}
+	mj.report.addFailure(sURLs.Error, urlStr)
+}
+
 func (mj *mirrorJob) watchMirrorEvents(ctx context.Context, events []EventInfo) {
 	for _, event := range events {
 		// It will change the expanded alias back to the alias
@@ -635,8 +873,8 @@ func (mj *mirrorJob) watchMirrorEvents(ctx context.Context, events []EventInfo)
 		// build target path, it is the relative of the eventPath with the sourceUrl
 		// joined to the targetURL.
 		sourceSuffix := strings.TrimPrefix(eventPath, sourceURLFull)
-		// Skip the object, if it matches the Exclude options provided
-		if matchExcludeOptions(mj.opts.excludeOptions, sourceSuffix, sourceURL.Type) {
+		// Skip the object, if it matches the Exclude or Include options provided
+		if !mj.opts.filter.match(sourceSuffix, sourceURL.Type) {
 			continue
 		}
 		// Skip the bucket, if it matches the Exclude options provided
@@ -644,18 +882,21 @@ func (mj *mirrorJob) watchMirrorEvents(ctx context.Context, events []EventInfo)
 			continue
 		}
 
-		sc, ok := event.UserMetadata["x-amz-storage-class"]
-		if ok {
-			var found bool
-			for _, esc := range mj.opts.excludeStorageClasses {
-				if esc == sc {
-					found = true
-					break
-				}
+		if sc, ok := event.UserMetadata["x-amz-storage-class"]; ok {
+			if matchExcludeStorageClasses(mj.opts.excludeStorageClasses, &ClientContent{StorageClass: sc}) {
+				continue
 			}
-			if found {
+		}
+
+		if mj.opts.nameTemplate != nil {
+			sourceModTime, _ := time.Parse(time.RFC3339Nano, event.Time)
+			rel := strings.TrimPrefix(filepath.ToSlash(sourceSuffix), "/")
+			renamed, err := renderNameTemplate(mj.opts.nameTemplate, rel, event.Size, sourceModTime)
+			if err != nil {
+				errorIf(err.Trace(eventPath), "Unable to rename `"+eventPath+"`.")
 				continue
 			}
+			sourceSuffix = renamed
 		}
 
 		targetPath := urlJoinPath(mj.targetURL, sourceSuffix)
@@ -784,6 +1025,17 @@ func (mj *mirrorJob) startMirror(ctx context.Context) {
 		select {
 		case sURLs, ok := <-URLsCh:
 			if !ok {
+				if mj.restorer != nil {
+					mj.restorer.wait(ctx, mj.stopCh, func(sURLs URLs) {
+						if sURLs.Error != nil {
+							mj.statusCh <- sURLs
+							return
+						}
+						mj.parallel.queueTask(func() URLs {
+							return mj.doMirror(ctx, sURLs)
+						}, sURLs.SourceContent.Size)
+					})
+				}
 				return
 			}
 			if sURLs.Error != nil {
@@ -813,6 +1065,16 @@ func (mj *mirrorJob) startMirror(ctx context.Context) {
 			sURLs.TotalSize = mj.status.Get()
 
 			if sURLs.SourceContent != nil {
+				if mj.restorer != nil && isArchivedStorageClass(sURLs.SourceContent.StorageClass) {
+					ready, err := mj.restorer.add(ctx, sURLs)
+					if err != nil {
+						mj.statusCh <- sURLs.WithError(err)
+						continue
+					}
+					if !ready {
+						continue
+					}
+				}
 				mj.parallel.queueTask(func() URLs {
 					return mj.doMirror(ctx, sURLs)
 				}, sURLs.SourceContent.Size)
@@ -829,6 +1091,23 @@ func (mj *mirrorJob) startMirror(ctx context.Context) {
 	}
 }
 
+// reconcile - runs a full mirror pass after every reconcile interval,
+// to copy the changes the watch missed, until the mirror stops.
+func (mj *mirrorJob) reconcile(ctx context.Context) {
+	ticker := time.NewTicker(mj.opts.reconcileInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			mj.startMirror(ctx)
+		case <-ctx.Done():
+			return
+		case <-mj.stopCh:
+			return
+		}
+	}
+}
+
 // when using a struct for copying, we could save a lot of passing of variables
 func (mj *mirrorJob) mirror(ctx context.Context) bool {
 	var wg sync.WaitGroup
@@ -849,8 +1128,19 @@ func (mj *mirrorJob) mirror(ctx context.Context) bool {
 		defer wg.Done()
 		// startMirror locks and blocks itself.
 		mj.startMirror(ctx)
+		if mj.opts.isWatch && mj.opts.reconcileInterval > 0 {
+			mj.reconcile(ctx)
+		}
 	}()
 
+	defer startLiveStatus("mirror", func(m *liveStatusMessage) {
+		m.Objects = atomic.LoadInt64(&mj.copied)
+		m.TotalObjects = mj.status.GetCounts()
+		m.Transferred = mj.parallel.sent()
+		m.TotalSize = mj.status.Get()
+		m.Queued = mj.parallel.queued()
+	})()
+
 	// Close statusCh when both watch & mirror quits
 	go func() {
 		wg.Wait()
@@ -872,16 +1162,24 @@ func newMirrorJob(srcURL, dstURL string, opts mirrorOptions) *mirrorJob {
 		watcher:   NewWatcher(UTCNow()),
 	}
 
-	mj.parallel = newParallelManager(mj.statusCh)
+	mj.parallel = newParallelManager(mj.statusCh, globalTransfer.Parallel)
 
 	// we'll define the status to use here,
 	// do we want the quiet status? or the progressbar
-	if globalQuiet {
-		mj.status = NewQuietStatus(mj.parallel)
-	} else if globalJSON {
-		mj.status = NewQuietStatus(mj.parallel)
-	} else {
+	if isProgressBarEnabled() {
 		mj.status = NewProgressStatus(mj.parallel)
+	} else {
+		mj.status = NewQuietStatus(mj.parallel)
+	}
+
+	if opts.restoreDays > 0 && !opts.isFake {
+		mj.restorer = &mirrorRestorer{
+			days:      opts.restoreDays,
+			poll:      opts.restorePoll,
+			encKeyDB:  opts.encKeyDB,
+			status:    mj.status,
+			newClient: newClientFromAlias,
+		}
 	}
 
 	return &mj
@@ -939,6 +1237,10 @@ func getEventPathURLWin(srcURL, eventPath string) string {
 }
 
 // runMirror - mirrors all buckets to another S3 server
+// mirrorConfirmed - set once the first mirror is confirmed, the mirrors
+// restarted by --watch are not confirmed again.
+var mirrorConfirmed bool
+
 func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, encKeyDB map[string][]prefixSSEPair) bool {
 	// Parse metadata.
 	userMetadata := make(map[string]string)
@@ -965,7 +1267,7 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 
 	// preserve is also expected to be overwritten if necessary
 	isMetadata := cli.Bool("a") || isWatch || len(userMetadata) > 0
-	isFake := cli.Bool("fake") || cli.Bool("dry-run")
+	isFake := cli.Bool("fake") || isDryRun(cli)
 
 	mopts := mirrorOptions{
 		isFake:                isFake,
@@ -978,7 +1280,7 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 		md5:                   cli.Bool("md5"),
 		disableMultipart:      cli.Bool("disable-multipart"),
 		skipErrors:            cli.Bool("skip-errors"),
-		excludeOptions:        cli.StringSlice("exclude"),
+		filter:                newListFilter(cli),
 		excludeBuckets:        cli.StringSlice("exclude-bucket"),
 		excludeStorageClasses: cli.StringSlice("exclude-storageclass"),
 		olderThan:             cli.String("older-than"),
@@ -988,9 +1290,32 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 		encKeyDB:              encKeyDB,
 		activeActive:          isWatch,
 	}
+	if cli.Bool("newer") {
+		mopts.cmpTime = diffTimeNewer
+	}
+	mopts.maxDelete = cli.Int64("max-delete")
+	mopts.maxDeletePercent = cli.Float64("max-delete-percent")
+	if text := cli.String("name-template"); text != "" {
+		mopts.nameTemplate, err = parseNameTemplate(text)
+		fatalIf(err.Trace(text), "Unable to parse --name-template.")
+	}
+	mopts.restoreDays = cli.Int("restore-days")
+	mopts.restorePoll = cli.Duration("restore-poll")
+	mopts.reconcileInterval = cli.Duration("reconcile-interval")
+	mopts.multipartSize, mopts.multipartThreads, mopts.multipartThreshold = multipartFlagValues(cli)
+	mopts.verify = cli.Bool("verify")
+	mopts.sparse = cli.Bool("sparse")
+	mopts.skipMatchingParts = cli.Bool("skip-matching-parts")
+	mopts.scanner, err = newDownloadScanner(cli)
+	fatalIf(err, "Unable to scan the downloads.")
 
 	// Create a new mirror job and execute it
 	mj := newMirrorJob(srcURL, dstURL, mopts)
+	reportPath := cli.String("report-html")
+	if reportPath != "" {
+		mj.report = newMirrorReport(srcURL, dstURL, isFake)
+		mj.targetPath = mirrorReportPath(dstClt.GetURL())
+	}
 
 	preserve := cli.Bool("preserve")
 
@@ -998,6 +1323,14 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 	mirrorSrcBuckets := srcClt.GetURL().Type == objectStorage && srcClt.GetURL().Path == string(srcClt.GetURL().Separator)
 	mirrorBucketsToBuckets := mirrorSrcBuckets && createDstBuckets
 
+	if cli.Bool("create-dest") && !createDstBuckets && dstClt.GetURL().Type == objectStorage {
+		bucketMsg, err := createMirrorDestBucket(ctx, dstURL, cli.String("region"), cli.IsSet("region"), isFake)
+		mj.status.fatalIf(err, "Unable to prepare the destination bucket.")
+		if bucketMsg != nil {
+			mj.status.PrintMsg(bucketMsg)
+		}
+	}
+
 	if mirrorSrcBuckets || createDstBuckets {
 		// Synchronize buckets using dirDifference function
 		for d := range bucketDifference(ctx, srcClt, dstClt) {
@@ -1045,6 +1378,7 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 				mj.status.PrintMsg(mirrorMessage{
 					Source: newSrcURL,
 					Target: newTgtURL,
+					DryRun: mj.opts.isFake,
 				})
 
 				if mj.opts.isFake {
@@ -1080,6 +1414,30 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 		}
 	}
 
+	if !mirrorConfirmed {
+		// Mirrors larger than --confirm-above are compared once first,
+		// to be confirmed.
+		confirmAbove, err := confirmAboveSize(cli)
+		fatalIf(err, "Invalid value for --confirm-above.")
+		if confirmAbove > 0 && !isFake {
+			summary := transferSummary{
+				Verb:   transferVerb(srcClt.GetURL().Type, dstClt.GetURL().Type, false),
+				Target: dstURL,
+			}
+			for sURLs := range prepareMirrorURLs(ctx, srcURL, dstURL, mopts) {
+				if sURLs.Error != nil || sURLs.SourceContent == nil {
+					continue
+				}
+				if isOlder(sURLs.SourceContent.Time, mopts.olderThan) || isNewer(sURLs.SourceContent.Time, mopts.newerThan) {
+					continue
+				}
+				summary.add(sURLs.SourceContent.Size)
+			}
+			mj.status.fatalIf(confirmTransfer(summary, confirmAbove), "Unable to start mirroring.")
+		}
+		mirrorConfirmed = true
+	}
+
 	if mj.opts.isWatch {
 		// monitor mode will watch the source folders for changes,
 		// and queue them for copying.
@@ -1092,13 +1450,84 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 		}
 	}
 
-	return mj.mirror(ctx)
+	errorDetected := mj.mirror(ctx)
+	if mj.report != nil {
+		if err := mj.report.write(reportPath); err != nil {
+			errorIf(err, "Unable to save the report.")
+			errorDetected = true
+		}
+	}
+
+	if reportPath := cli.String("verify-report"); reportPath != "" && !isWatch && !isFake {
+		msg, err := verifyMirror(ctx, srcClt, dstClt, srcURL, dstURL, reportPath, []byte(cli.String("verify-key")), mopts)
+		if err != nil {
+			errorIf(err.Trace(srcURL, dstURL), "Unable to verify mirroring.")
+			return true
+		}
+		printMsg(msg)
+		if !msg.Match {
+			errorIf(errDummy().Trace(srcURL, dstURL), "Source and target do not match, see `"+reportPath+"`.")
+			return true
+		}
+	}
+	return errorDetected
+}
+
+// createMirrorDestBucket - creates the bucket of the mirror destination if
+// missing, an existing bucket must be located in region when checkRegion is set.
+func createMirrorDestBucket(ctx context.Context, dstURL, region string, checkRegion, isFake bool) (*makeBucketMessage, *probe.Error) {
+	alias, bucketPath := url2Alias(dstURL)
+	bucket := splitStr(filepath.ToSlash(bucketPath), "/", 2)[0]
+	bucketURL := path.Join(alias, bucket)
+
+	clnt, err := newClient(bucketURL)
+	if err != nil {
+		return nil, err.Trace(bucketURL)
+	}
+	return ensureMirrorDestBucket(ctx, clnt, bucketURL, region, checkRegion, isFake)
+}
+
+// ensureMirrorDestBucket - creates the bucket of clnt unless it exists,
+// in which case its location is checked when checkRegion is set. Returns
+// the message of the created bucket.
+func ensureMirrorDestBucket(ctx context.Context, clnt Client, bucketURL, region string, checkRegion, isFake bool) (*makeBucketMessage, *probe.Error) {
+	_, err := clnt.Stat(ctx, StatOptions{})
+	if err == nil {
+		if !checkRegion {
+			return nil, nil
+		}
+		info, err := clnt.GetBucketInfo(ctx)
+		if err != nil {
+			return nil, err.Trace(bucketURL)
+		}
+		// Buckets in the default region report an empty location.
+		location := info.Location
+		if location == "" {
+			location = "us-east-1"
+		}
+		if location != region {
+			return nil, probe.NewError(fmt.Errorf("bucket `%s` is located in region `%s`, not `%s`", bucketURL, location, region))
+		}
+		return nil, nil
+	}
+	if _, ok := err.ToGoError().(BucketDoesNotExist); !ok {
+		return nil, err.Trace(bucketURL)
+	}
+
+	if !isFake {
+		if err = clnt.MakeBucket(ctx, region, true, false); err != nil {
+			return nil, err.Trace(bucketURL, region)
+		}
+	}
+	return &makeBucketMessage{Status: "success", Bucket: bucketURL, Region: region}, nil
 }
 
 // Main entry point for mirror command.
 func mainMirror(cliCtx *cli.Context) error {
 	// Additional command specific theme customization.
 	console.SetColor("Mirror", color.New(color.FgGreen, color.Bold))
+	console.SetColor("MakeBucket", color.New(color.FgGreen, color.Bold))
+	console.SetColor("MirrorRestore", color.New(color.FgCyan))
 
 	ctx, cancelMirror := context.WithCancel(globalContext)
 	defer cancelMirror()
@@ -1107,6 +1536,8 @@ func mainMirror(cliCtx *cli.Context) error {
 	encKeyDB, err := getEncKeys(cliCtx)
 	fatalIf(err, "Unable to parse encryption keys.")
 
+	fatalIf(loadTransferConfig(), "Unable to load the transfer options.")
+
 	// check 'mirror' cli arguments.
 	srcURL, tgtURL := checkMirrorSyntax(ctx, cliCtx, encKeyDB)
 
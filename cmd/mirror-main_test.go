@@ -0,0 +1,89 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// fakeBucketClient - a client of a bucket that may not exist yet.
+type fakeBucketClient struct {
+	Client
+	exists   bool
+	location string
+	made     []string
+}
+
+func (c *fakeBucketClient) Stat(_ context.Context, _ StatOptions) (*ClientContent, *probe.Error) {
+	if !c.exists {
+		return nil, probe.NewError(BucketDoesNotExist{Bucket: "bucket"})
+	}
+	return &ClientContent{}, nil
+}
+
+func (c *fakeBucketClient) GetBucketInfo(_ context.Context) (BucketInfo, *probe.Error) {
+	return BucketInfo{Location: c.location}, nil
+}
+
+func (c *fakeBucketClient) MakeBucket(_ context.Context, region string, _, _ bool) *probe.Error {
+	c.made = append(c.made, region)
+	return nil
+}
+
+func TestEnsureMirrorDestBucket(t *testing.T) {
+	testCases := []struct {
+		exists      bool
+		location    string
+		region      string
+		checkRegion bool
+		isFake      bool
+
+		created bool
+		made    int
+		err     bool
+	}{
+		// Missing buckets are created, except for dry runs.
+		{region: "eu-west-1", checkRegion: true, created: true, made: 1},
+		{region: "us-east-1", created: true, made: 1},
+		{region: "eu-west-1", checkRegion: true, isFake: true, created: true},
+		// Existing buckets are kept, in the requested region only.
+		{exists: true, location: "eu-west-1", region: "us-east-1"},
+		{exists: true, location: "eu-west-1", region: "eu-west-1", checkRegion: true},
+		{exists: true, location: "", region: "us-east-1", checkRegion: true},
+		{exists: true, location: "eu-west-1", region: "us-east-1", checkRegion: true, err: true},
+	}
+	for i, testCase := range testCases {
+		clnt := &fakeBucketClient{exists: testCase.exists, location: testCase.location}
+		msg, err := ensureMirrorDestBucket(context.Background(), clnt, "s3/bucket", testCase.region, testCase.checkRegion, testCase.isFake)
+		if (err != nil) != testCase.err {
+			t.Fatalf("Test %d: expected error %v, got %v", i+1, testCase.err, err)
+		}
+		if (msg != nil) != testCase.created {
+			t.Errorf("Test %d: expected created %v, got %v", i+1, testCase.created, msg)
+		}
+		if len(clnt.made) != testCase.made {
+			t.Errorf("Test %d: expected %d MakeBucket calls, got %d", i+1, testCase.made, len(clnt.made))
+		}
+		if msg != nil && msg.Region != testCase.region {
+			t.Errorf("Test %d: expected region %s, got %s", i+1, testCase.region, msg.Region)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+// Storage classes whose objects must be restored before they can be read.
+var archivedStorageClasses = []string{"GLACIER", "DEEP_ARCHIVE"}
+
+const (
+	// Longest wait between two polls of pending restores.
+	mirrorRestoreMaxPoll = time.Hour
+
+	// Polls in a row without any restore status after which a pending
+	// restore fails.
+	mirrorRestoreUnreportedPolls = 3
+)
+
+// isArchivedStorageClass - reports whether objects of the storage class
+// need a restore request before they can be read.
+func isArchivedStorageClass(storageClass string) bool {
+	for _, sc := range archivedStorageClasses {
+		if strings.EqualFold(sc, storageClass) {
+			return true
+		}
+	}
+	return false
+}
+
+// mirrorRestoreMessage - reports the restore status of an archived source object.
+type mirrorRestoreMessage struct {
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Action string `json:"action"` // "requested", "pending" or "restored"
+}
+
+// String colorized mirror restore message
+func (m mirrorRestoreMessage) String() string {
+	switch m.Action {
+	case "requested":
+		return console.Colorize("MirrorRestore", fmt.Sprintf("Requested restore of `%s`.", m.Source))
+	case "pending":
+		return console.Colorize("MirrorRestore", fmt.Sprintf("Restore of `%s` is in progress.", m.Source))
+	}
+	return console.Colorize("MirrorRestore", fmt.Sprintf("Restored `%s`.", m.Source))
+}
+
+// JSON jsonified mirror restore message
+func (m mirrorRestoreMessage) JSON() string {
+	m.Status = "success"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// mirrorRestorer - requests the restore of archived source objects and
+// holds them back until their restored copy is available. Restore state
+// lives on the server, so running mirror again after an interruption
+// waits for the restores already requested instead of sending new ones.
+type mirrorRestorer struct {
+	days      int
+	poll      time.Duration
+	encKeyDB  map[string][]prefixSSEPair
+	status    Status
+	newClient func(alias, urlStr string) (Client, *probe.Error)
+
+	pending []mirrorRestorePending
+}
+
+// mirrorRestorePending - an object waiting for its restore, with the
+// number of polls in a row that did not report any restore status.
+type mirrorRestorePending struct {
+	sURLs      URLs
+	unreported int
+}
+
+// restoreState - stats the source object, returns whether its restored
+// copy can be read, whether a restore is in progress and whether the
+// server reported any restore status at all.
+func (r *mirrorRestorer) restoreState(ctx context.Context, clnt Client, sURLs URLs) (restored, ongoing, reported bool, err *probe.Error) {
+	sourcePath := filepath.ToSlash(filepath.Join(sURLs.SourceAlias, sURLs.SourceContent.URL.Path))
+	st, err := clnt.Stat(ctx, StatOptions{
+		versionID: sURLs.SourceContent.VersionID,
+		sse:       getSSE(sourcePath, r.encKeyDB[sURLs.SourceAlias]),
+	})
+	if err != nil {
+		return false, false, false, err
+	}
+	if st.Restore == nil {
+		return false, false, false, nil
+	}
+	return !st.Restore.OngoingRestore, st.Restore.OngoingRestore, true, nil
+}
+
+// add - sends a restore request for the archived source object if needed,
+// ready is true when the object can be copied right away.
+func (r *mirrorRestorer) add(ctx context.Context, sURLs URLs) (ready bool, err *probe.Error) {
+	sourceURL := sURLs.SourceContent.URL.String()
+	clnt, err := r.newClient(sURLs.SourceAlias, sourceURL)
+	if err != nil {
+		return false, err.Trace(sourceURL)
+	}
+	restored, ongoing, _, err := r.restoreState(ctx, clnt, sURLs)
+	if err != nil {
+		return false, err.Trace(sourceURL)
+	}
+	if restored {
+		return true, nil
+	}
+
+	msg := mirrorRestoreMessage{
+		Source: filepath.ToSlash(filepath.Join(sURLs.SourceAlias, sURLs.SourceContent.URL.Path)),
+		Action: "pending",
+	}
+	if !ongoing {
+		if err = clnt.Restore(ctx, sURLs.SourceContent.VersionID, r.days); err != nil {
+			return false, err.Trace(sourceURL)
+		}
+		msg.Action = "requested"
+	}
+	r.status.PrintMsg(msg)
+	r.pending = append(r.pending, mirrorRestorePending{sURLs: sURLs})
+	return false, nil
+}
+
+// nextRestorePoll - doubles the wait between two polls up to mirrorRestoreMaxPoll.
+func nextRestorePoll(poll time.Duration) time.Duration {
+	if poll *= 2; poll > mirrorRestoreMaxPoll {
+		return mirrorRestoreMaxPoll
+	}
+	return poll
+}
+
+// wait - polls the pending restores with an exponential backoff, calling
+// ready for every restored object, and for every object that failed with
+// the error set. An object whose restore status is not reported for
+// mirrorRestoreUnreportedPolls polls in a row fails, as the server either
+// does not report restores or the restored copy expired before it was
+// seen. Returns when no restore is pending or ctx is canceled.
+func (r *mirrorRestorer) wait(ctx context.Context, stopCh <-chan struct{}, ready func(URLs)) {
+	poll := r.poll
+	for len(r.pending) > 0 {
+		select {
+		case <-time.After(poll):
+		case <-ctx.Done():
+			return
+		case <-stopCh:
+			return
+		}
+
+		pending := r.pending[:0]
+		for _, p := range r.pending {
+			sURLs := p.sURLs
+			sourceURL := sURLs.SourceContent.URL.String()
+			clnt, err := r.newClient(sURLs.SourceAlias, sourceURL)
+			if err != nil {
+				ready(sURLs.WithError(err.Trace(sourceURL)))
+				continue
+			}
+			restored, _, reported, err := r.restoreState(ctx, clnt, sURLs)
+			switch {
+			case err != nil:
+				ready(sURLs.WithError(err.Trace(sourceURL)))
+			case restored:
+				r.status.PrintMsg(mirrorRestoreMessage{
+					Source: filepath.ToSlash(filepath.Join(sURLs.SourceAlias, sURLs.SourceContent.URL.Path)),
+					Action: "restored",
+				})
+				ready(sURLs)
+			case !reported && p.unreported+1 >= mirrorRestoreUnreportedPolls:
+				ready(sURLs.WithError(probe.NewError(fmt.Errorf("restore of `%s` was requested but its status is not reported", sourceURL))))
+			case !reported:
+				p.unreported++
+				pending = append(pending, p)
+			default:
+				p.unreported = 0
+				pending = append(pending, p)
+			}
+		}
+		r.pending = pending
+		poll = nextRestorePoll(poll)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+)
+
+// fakeRestoreClient - a client whose Stat reports the restore states in
+// turn and repeats the last one.
+type fakeRestoreClient struct {
+	Client
+	states     []*minio.RestoreInfo
+	statErr    *probe.Error
+	restoreErr *probe.Error
+	stats      int
+	restores   int
+}
+
+func (c *fakeRestoreClient) Stat(_ context.Context, _ StatOptions) (*ClientContent, *probe.Error) {
+	if c.statErr != nil {
+		return nil, c.statErr
+	}
+	state := c.states[len(c.states)-1]
+	if c.stats < len(c.states) {
+		state = c.states[c.stats]
+	}
+	c.stats++
+	return &ClientContent{Restore: state}, nil
+}
+
+func (c *fakeRestoreClient) Restore(_ context.Context, _ string, _ int) *probe.Error {
+	c.restores++
+	return c.restoreErr
+}
+
+// restoreMsgStatus - a status recording the actions of restore messages.
+type restoreMsgStatus struct {
+	Status
+	actions []string
+}
+
+func (s *restoreMsgStatus) PrintMsg(msg message) {
+	s.actions = append(s.actions, msg.(mirrorRestoreMessage).Action)
+}
+
+func TestMirrorRestorer(t *testing.T) {
+	var (
+		ongoing  = &minio.RestoreInfo{OngoingRestore: true}
+		restored = &minio.RestoreInfo{ExpiryTime: time.Now().Add(time.Hour)}
+		failure  = probe.NewError(minio.ErrorResponse{Code: "AccessDenied", StatusCode: 403})
+	)
+	testCases := []struct {
+		states     []*minio.RestoreInfo
+		statErr    *probe.Error
+		restoreErr *probe.Error
+
+		addErr   bool
+		ready    bool
+		restores int
+		actions  []string
+		readyErr bool
+	}{
+		// Already restored objects are copied right away.
+		{states: []*minio.RestoreInfo{restored}, ready: true},
+		// Restore requested, then in progress and done.
+		{states: []*minio.RestoreInfo{nil, ongoing, restored}, restores: 1, actions: []string{"requested", "restored"}},
+		// Restore already in progress is not requested again.
+		{states: []*minio.RestoreInfo{ongoing, ongoing, restored}, actions: []string{"pending", "restored"}},
+		// Stat and Restore errors.
+		{statErr: failure, addErr: true},
+		{states: []*minio.RestoreInfo{nil}, restoreErr: failure, restores: 1, addErr: true},
+		// Restore status never reported after the request.
+		{states: []*minio.RestoreInfo{nil}, restores: 1, actions: []string{"requested"}, readyErr: true},
+		// Restore status lost between two polls.
+		{states: []*minio.RestoreInfo{ongoing, ongoing, nil}, actions: []string{"pending"}, readyErr: true},
+	}
+	for i, testCase := range testCases {
+		clnt := &fakeRestoreClient{states: testCase.states, statErr: testCase.statErr, restoreErr: testCase.restoreErr}
+		status := &restoreMsgStatus{}
+		r := &mirrorRestorer{
+			days:   1,
+			poll:   time.Millisecond,
+			status: status,
+			newClient: func(string, string) (Client, *probe.Error) {
+				return clnt, nil
+			},
+		}
+		sURLs := URLs{
+			SourceAlias:   "src",
+			SourceContent: &ClientContent{URL: *newClientURL("http://localhost/bucket/object")},
+		}
+		ready, err := r.add(context.Background(), sURLs)
+		if (err != nil) != testCase.addErr {
+			t.Fatalf("Test %d: expected add error %v, got %v", i+1, testCase.addErr, err)
+		}
+		if ready != testCase.ready {
+			t.Errorf("Test %d: expected ready %v, got %v", i+1, testCase.ready, ready)
+		}
+		if clnt.restores != testCase.restores {
+			t.Errorf("Test %d: expected %d restore requests, got %d", i+1, testCase.restores, clnt.restores)
+		}
+		if err != nil || ready {
+			continue
+		}
+
+		var readyURLs []URLs
+		r.wait(context.Background(), nil, func(sURLs URLs) {
+			readyURLs = append(readyURLs, sURLs)
+		})
+		if len(readyURLs) != 1 {
+			t.Fatalf("Test %d: expected 1 ready object, got %d", i+1, len(readyURLs))
+		}
+		if (readyURLs[0].Error != nil) != testCase.readyErr {
+			t.Errorf("Test %d: expected ready error %v, got %v", i+1, testCase.readyErr, readyURLs[0].Error)
+		}
+		if len(r.pending) != 0 {
+			t.Errorf("Test %d: expected no pending restore, got %d", i+1, len(r.pending))
+		}
+		if len(status.actions) != len(testCase.actions) {
+			t.Fatalf("Test %d: expected actions %v, got %v", i+1, testCase.actions, status.actions)
+		}
+		for j := range status.actions {
+			if status.actions[j] != testCase.actions[j] {
+				t.Errorf("Test %d: expected actions %v, got %v", i+1, testCase.actions, status.actions)
+			}
+		}
+	}
+}
+
+func TestMirrorRestorerStatError(t *testing.T) {
+	clnt := &fakeRestoreClient{states: []*minio.RestoreInfo{nil}}
+	r := &mirrorRestorer{
+		days:   1,
+		poll:   time.Millisecond,
+		status: &restoreMsgStatus{},
+		newClient: func(string, string) (Client, *probe.Error) {
+			return clnt, nil
+		},
+	}
+	sURLs := URLs{
+		SourceAlias:   "src",
+		SourceContent: &ClientContent{URL: *newClientURL("http://localhost/bucket/object")},
+	}
+	if _, err := r.add(context.Background(), sURLs); err != nil {
+		t.Fatal(err)
+	}
+	clnt.statErr = probe.NewError(minio.ErrorResponse{Code: "NoSuchKey", StatusCode: 404})
+	var readyURLs []URLs
+	r.wait(context.Background(), nil, func(sURLs URLs) {
+		readyURLs = append(readyURLs, sURLs)
+	})
+	if len(readyURLs) != 1 || readyURLs[0].Error == nil {
+		t.Fatalf("expected 1 object failed with the Stat error, got %v", readyURLs)
+	}
+}
+
+func TestNextRestorePoll(t *testing.T) {
+	testCases := []struct {
+		poll, next time.Duration
+	}{
+		{time.Second, 2 * time.Second},
+		{10 * time.Minute, 20 * time.Minute},
+		{40 * time.Minute, mirrorRestoreMaxPoll},
+		{mirrorRestoreMaxPoll, mirrorRestoreMaxPoll},
+	}
+	for i, testCase := range testCases {
+		if next := nextRestorePoll(testCase.poll); next != testCase.next {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.next, next)
+		}
+	}
+}
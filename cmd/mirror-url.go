@@ -23,9 +23,11 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/pkg/v2/wildcard"
 )
 
@@ -45,12 +47,47 @@ func checkMirrorSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[st
 	srcURL = URLs[0]
 	tgtURL = URLs[1]
 
+	if cliCtx.Bool("trailing-slash") {
+		tgtURL = rsyncTargetURL(srcURL, tgtURL)
+	}
+
 	if cliCtx.Bool("force") && cliCtx.Bool("remove") {
 		errorIf(errInvalidArgument().Trace(URLs...), "`--force` is deprecated, please use `--overwrite` instead with `--remove` for the same functionality.")
 	} else if cliCtx.Bool("force") {
 		errorIf(errInvalidArgument().Trace(URLs...), "`--force` is deprecated, please use `--overwrite` instead for the same functionality.")
 	}
 
+	checkMultipartSyntax(cliCtx)
+
+	if cliCtx.Int("restore-days") < 0 {
+		fatalIf(errInvalidArgument().Trace(URLs...), "--restore-days should be equal or greater than 1.")
+	}
+	if cliCtx.Int("restore-days") > 0 && cliCtx.Duration("restore-poll") <= 0 {
+		fatalIf(errInvalidArgument().Trace(URLs...), "--restore-poll should be a positive duration.")
+	}
+
+	if cliCtx.Duration("reconcile-interval") < 0 {
+		fatalIf(errInvalidArgument().Trace(URLs...), "--reconcile-interval cannot be negative.")
+	}
+	if cliCtx.IsSet("reconcile-interval") && !cliCtx.Bool("watch") && !cliCtx.Bool("active-active") && !cliCtx.Bool("multi-master") {
+		fatalIf(errInvalidArgument().Trace(URLs...), "--reconcile-interval can only be used with --watch.")
+	}
+
+	if cliCtx.String("report-html") != "" && (cliCtx.Bool("watch") || cliCtx.Bool("active-active") || cliCtx.Bool("multi-master")) {
+		fatalIf(errInvalidArgument().Trace(URLs...), "--report-html cannot be used with --watch.")
+	}
+
+	if cliCtx.String("verify-report") != "" && cliCtx.String("verify-key") == "" {
+		fatalIf(errInvalidArgument().Trace(URLs...), "--verify-report requires a --verify-key to sign the report with.")
+	}
+
+	if (cliCtx.IsSet("max-delete") || cliCtx.IsSet("max-delete-percent")) && !cliCtx.Bool("remove") {
+		fatalIf(errInvalidArgument().Trace(URLs...), "--max-delete and --max-delete-percent can only be used with --remove.")
+	}
+	if cliCtx.Int64("max-delete") < 0 || cliCtx.Float64("max-delete-percent") < 0 {
+		fatalIf(errInvalidArgument().Trace(URLs...), "--max-delete and --max-delete-percent cannot be negative.")
+	}
+
 	_, expandedSourcePath, _ := mustExpandAlias(srcURL)
 	srcClient := newClientURL(expandedSourcePath)
 	_, expandedTargetPath, _ := mustExpandAlias(tgtURL)
@@ -102,6 +139,12 @@ func matchExcludeOptions(excludeOptions []string, srcSuffix string, typ ClientUR
 		if wildcard.Match(pattern, srcSuffix) {
 			return true
 		}
+		// A pattern ending with a slash, like .git/, matches the
+		// directory at any depth.
+		if strings.HasSuffix(pattern, "/") &&
+			(wildcard.Match(pattern+"*", srcSuffix) || wildcard.Match("*/"+pattern+"*", srcSuffix)) {
+			return true
+		}
 	}
 	return false
 }
@@ -126,6 +169,22 @@ func matchExcludeBucketOptions(excludeBuckets []string, srcSuffix string) bool {
 	return false
 }
 
+// matchExcludeStorageClasses - reports whether any of the contents belongs
+// to one of the excluded storage classes.
+func matchExcludeStorageClasses(storageClasses []string, contents ...*ClientContent) bool {
+	for _, content := range contents {
+		if content == nil || content.StorageClass == "" {
+			continue
+		}
+		for _, sc := range storageClasses {
+			if strings.EqualFold(sc, content.StorageClass) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mirrorOptions, URLsCh chan<- URLs) {
 	// source and targets are always directories
 	sourceSeparator := string(newClientURL(sourceURL).Separator)
@@ -164,8 +223,14 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 		}
 	}
 
+	// With deletion limits, removals are held back until the number of
+	// objects on the target is known, similar objects are needed to count them.
+	limitDelete := opts.isRemove && (opts.maxDelete > 0 || opts.maxDeletePercent > 0)
+	var removeURLs []URLs
+	var targetObjects int64
+
 	// List both source and target, compare and return values through channel.
-	for diffMsg := range objectDifference(ctx, sourceClnt, targetClnt, opts.isMetadata) {
+	for diffMsg := range mirrorDifference(ctx, sourceClnt, targetClnt, opts, opts.isMetadata, limitDelete) {
 		if diffMsg.Error != nil {
 			// Send all errors through the channel
 			URLsCh <- URLs{Error: diffMsg.Error, ErrorCond: differInUnknown}
@@ -173,46 +238,38 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 		}
 
 		srcSuffix := strings.TrimPrefix(diffMsg.FirstURL, sourceURL)
-		// Skip the source object if it matches the Exclude options provided
-		if matchExcludeOptions(opts.excludeOptions, srcSuffix, newClientURL(sourceURL).Type) {
-			continue
-		}
-
 		// Skip the source bucket if it matches the Exclude options provided
 		if matchExcludeBucketOptions(opts.excludeBuckets, srcSuffix) {
 			continue
 		}
 
 		tgtSuffix := strings.TrimPrefix(diffMsg.SecondURL, targetURL)
-		// Skip the target object if it matches the Exclude options provided
-		if matchExcludeOptions(opts.excludeOptions, tgtSuffix, newClientURL(targetURL).Type) {
+		// Skip the target bucket if it matches the Exclude options provided
+		if matchExcludeBucketOptions(opts.excludeBuckets, tgtSuffix) {
 			continue
 		}
 
-		// Skip the target bucket if it matches the Exclude options provided
-		if matchExcludeBucketOptions(opts.excludeBuckets, tgtSuffix) {
+		// Skip objects archived to an excluded storage class on either side,
+		// they can't be read without a restore.
+		if matchExcludeStorageClasses(opts.excludeStorageClasses, diffMsg.firstContent, diffMsg.secondContent) {
 			continue
 		}
 
-		if diffMsg.firstContent != nil {
-			var found bool
-			for _, esc := range opts.excludeStorageClasses {
-				if esc == diffMsg.firstContent.StorageClass {
-					found = true
-					break
-				}
-			}
-			if found {
-				continue
-			}
+		if diffMsg.secondContent != nil {
+			targetObjects++
 		}
 
 		switch diffMsg.Diff {
 		case differInNone:
 			// No difference, continue.
 		case differInType:
+			if diffMsg.firstContent != nil && diffMsg.firstContent.Err != nil {
+				// Name conflict of the source object.
+				URLsCh <- URLs{Error: diffMsg.firstContent.Err.Trace(diffMsg.SecondURL)}
+				continue
+			}
 			URLsCh <- URLs{Error: errInvalidTarget(diffMsg.SecondURL)}
-		case differInSize, differInMetadata, differInAASourceMTime:
+		case differInSize, differInMetadata, differInAASourceMTime, differInTime:
 			if !opts.isOverwrite && !opts.isFake && !opts.activeActive {
 				// Size or time or etag differs but --overwrite not set.
 				URLsCh <- URLs{
@@ -222,9 +279,8 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 				continue
 			}
 
-			sourceSuffix := strings.TrimPrefix(diffMsg.FirstURL, sourceURL)
 			// Either available only in source or size differs and force is set
-			targetPath := urlJoinPath(targetURL, sourceSuffix)
+			targetPath := mirrorTargetPath(diffMsg, sourceURL, targetURL, opts)
 			sourceContent := diffMsg.firstContent
 			targetContent := &ClientContent{URL: *newClientURL(targetPath)}
 			URLsCh <- URLs{
@@ -235,8 +291,7 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 			}
 		case differInFirst:
 			// Only in first, always copy.
-			sourceSuffix := strings.TrimPrefix(diffMsg.FirstURL, sourceURL)
-			targetPath := urlJoinPath(targetURL, sourceSuffix)
+			targetPath := mirrorTargetPath(diffMsg, sourceURL, targetURL, opts)
 			sourceContent := diffMsg.firstContent
 			targetContent := &ClientContent{URL: *newClientURL(targetPath)}
 			URLsCh <- URLs{
@@ -249,6 +304,13 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 			if !opts.isRemove && !opts.isFake {
 				continue
 			}
+			if limitDelete {
+				removeURLs = append(removeURLs, URLs{
+					TargetAlias:   targetAlias,
+					TargetContent: diffMsg.secondContent,
+				})
+				continue
+			}
 			URLsCh <- URLs{
 				TargetAlias:   targetAlias,
 				TargetContent: diffMsg.secondContent,
@@ -260,20 +322,80 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 			}
 		}
 	}
+
+	if !limitDelete {
+		return
+	}
+	if err := checkMirrorDeleteLimits(int64(len(removeURLs)), targetObjects, opts.maxDelete, opts.maxDeletePercent); err != nil {
+		URLsCh <- URLs{Error: err.Trace(targetURL)}
+		return
+	}
+	for _, removeURL := range removeURLs {
+		URLsCh <- removeURL
+	}
+}
+
+// mirrorDifference - compares source and target, pairing source objects with
+// the target names rendered by --name-template when given.
+func mirrorDifference(ctx context.Context, sourceClnt, targetClnt Client, opts mirrorOptions, isMetadata, returnSimilar bool) chan diffMessage {
+	if opts.nameTemplate == nil {
+		return objectDifference(ctx, sourceClnt, targetClnt, isMetadata, returnSimilar, opts.cmpTime, opts.filter)
+	}
+	rename := func(rel string, content *ClientContent) (string, *probe.Error) {
+		return renderNameTemplate(opts.nameTemplate, rel, content.Size, content.Time)
+	}
+	return renamedObjectDifference(ctx, sourceClnt, targetClnt, rename, isMetadata, returnSimilar, opts.cmpTime, opts.filter)
+}
+
+// mirrorTargetPath - returns the target the source object of diffMsg is copied to.
+func mirrorTargetPath(diffMsg diffMessage, sourceURL, targetURL string, opts mirrorOptions) string {
+	if opts.nameTemplate != nil {
+		// Renamed differences already hold the target.
+		return diffMsg.SecondURL
+	}
+	return urlJoinPath(targetURL, strings.TrimPrefix(diffMsg.FirstURL, sourceURL))
+}
+
+// checkMirrorDeleteLimits - returns an error if removing toDelete of the
+// targetObjects objects on the target exceeds --max-delete or --max-delete-percent.
+func checkMirrorDeleteLimits(toDelete, targetObjects, maxDelete int64, maxDeletePercent float64) *probe.Error {
+	if maxDelete > 0 && toDelete > maxDelete {
+		return probe.NewError(fmt.Errorf("refusing to remove %d object(s) from the target, more than --max-delete %d", toDelete, maxDelete))
+	}
+	if maxDeletePercent > 0 && targetObjects > 0 {
+		if percent := float64(toDelete) * 100 / float64(targetObjects); percent > maxDeletePercent {
+			return probe.NewError(fmt.Errorf("refusing to remove %d of %d object(s) (%.1f%%) from the target, more than --max-delete-percent %g", toDelete, targetObjects, percent, maxDeletePercent))
+		}
+	}
+	return nil
 }
 
 type mirrorOptions struct {
-	isFake, isOverwrite, activeActive                     bool
-	isWatch, isRemove, isMetadata                         bool
-	isRetriable                                           bool
-	isSummary                                             bool
-	skipErrors                                            bool
-	excludeOptions, excludeStorageClasses, excludeBuckets []string
-	encKeyDB                                              map[string][]prefixSSEPair
-	md5, disableMultipart                                 bool
-	olderThan, newerThan                                  string
-	storageClass                                          string
-	userMetadata                                          map[string]string
+	isFake, isOverwrite, activeActive     bool
+	isWatch, isRemove, isMetadata         bool
+	isRetriable                           bool
+	isSummary                             bool
+	skipErrors                            bool
+	filter                                listFilter
+	nameTemplate                          *template.Template
+	excludeStorageClasses, excludeBuckets []string
+	encKeyDB                              map[string][]prefixSSEPair
+	md5, disableMultipart                 bool
+	olderThan, newerThan                  string
+	storageClass                          string
+	userMetadata                          map[string]string
+	cmpTime                               diffTimeMode
+	maxDelete                             int64
+	maxDeletePercent                      float64
+	restoreDays                           int
+	restorePoll                           time.Duration
+	reconcileInterval                     time.Duration
+	multipartSize, multipartThreads       string
+	multipartThreshold                    string
+	verify                                bool
+	scanner                               *downloadScanner
+	sparse                                bool
+	skipMatchingParts                     bool
 }
 
 // Prepares urls that need to be copied or removed based on requested options.
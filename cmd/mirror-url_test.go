@@ -0,0 +1,198 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// useTestMcConfig - loads an empty configuration for the duration of the test.
+func useTestMcConfig(t *testing.T) {
+	configDir, load := mcCustomConfigDir, loadMcConfig
+	t.Cleanup(func() {
+		mcCustomConfigDir, loadMcConfig = configDir, load
+	})
+	mcCustomConfigDir = t.TempDir()
+	loadMcConfig = loadMcConfigFactory()
+}
+
+func TestMirrorNewerOverwrite(t *testing.T) {
+	useTestMcConfig(t)
+	root := t.TempDir()
+	source, target := filepath.Join(root, "src"), filepath.Join(root, "tgt")
+	now := time.Now()
+	for p, modTime := range map[string]time.Time{
+		filepath.Join(source, "a"): now,
+		filepath.Join(target, "a"): now.Add(-time.Hour),
+		filepath.Join(source, "b"): now,
+	} {
+		if e := os.MkdirAll(filepath.Dir(p), 0o755); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(p, []byte("data"), 0o644); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.Chtimes(p, modTime, modTime); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	for _, overwrite := range []bool{false, true} {
+		var copied, refused int
+		opts := mirrorOptions{cmpTime: diffTimeNewer, isOverwrite: overwrite}
+		for mirrorURL := range prepareMirrorURLs(context.Background(), source, target, opts) {
+			switch {
+			case mirrorURL.Error != nil:
+				refused++
+			case mirrorURL.SourceContent != nil:
+				copied++
+			}
+		}
+		if overwrite && (copied != 2 || refused != 0) {
+			t.Errorf("--overwrite: expected 2 copies, got %d copies and %d errors", copied, refused)
+		}
+		if !overwrite && (copied != 1 || refused != 1) {
+			t.Errorf("expected the existing target to be refused, got %d copies and %d errors", copied, refused)
+		}
+	}
+}
+
+func TestCheckMirrorDeleteLimits(t *testing.T) {
+	testCases := []struct {
+		toDelete, targetObjects int64
+		maxDelete               int64
+		maxDeletePercent        float64
+		fail                    bool
+	}{
+		{10, 100, 0, 0, false},
+		{10, 100, 10, 0, false},
+		{11, 100, 10, 0, true},
+		{10, 100, 0, 10, false},
+		{11, 100, 0, 10, true},
+		{1, 3, 0, 50, false},
+		{2, 3, 0, 50, true},
+		// Every object removed is 100%.
+		{5, 5, 0, 99.9, true},
+		// Nothing on the target, nothing to compare with.
+		{0, 0, 1, 1, false},
+		{11, 1000, 10, 50, true},
+		{10, 11, 100, 50, true},
+	}
+	for i, testCase := range testCases {
+		err := checkMirrorDeleteLimits(testCase.toDelete, testCase.targetObjects, testCase.maxDelete, testCase.maxDeletePercent)
+		if (err != nil) != testCase.fail {
+			t.Errorf("Test %d: expected failure %t, got %v", i+1, testCase.fail, err)
+		}
+	}
+}
+
+func TestMirrorDeleteLimitsCountTarget(t *testing.T) {
+	useTestMcConfig(t)
+	root := t.TempDir()
+	source, target := filepath.Join(root, "src"), filepath.Join(root, "tgt")
+	modTime := time.Now().Add(-time.Hour)
+	// 4 objects on the target: 1 similar, 1 differing in size and 2 extraneous.
+	for p, data := range map[string]string{
+		filepath.Join(source, "a"): "a",
+		filepath.Join(target, "a"): "a",
+		filepath.Join(source, "b"): "bb",
+		filepath.Join(target, "b"): "b",
+		filepath.Join(target, "c"): "c",
+		filepath.Join(target, "d"): "d",
+	} {
+		if e := os.MkdirAll(filepath.Dir(p), 0o755); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(p, []byte(data), 0o644); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.Chtimes(p, modTime, modTime); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	for _, testCase := range []struct {
+		maxDeletePercent float64
+		removed          int
+	}{
+		// 2 of 4 target objects is 50%.
+		{50, 2},
+		{49, 0},
+	} {
+		opts := mirrorOptions{isRemove: true, isOverwrite: true, maxDeletePercent: testCase.maxDeletePercent}
+		var removed int
+		for mirrorURL := range prepareMirrorURLs(context.Background(), source, target, opts) {
+			if mirrorURL.Error == nil && mirrorURL.SourceContent == nil && mirrorURL.TargetContent != nil {
+				removed++
+			}
+		}
+		if removed != testCase.removed {
+			t.Errorf("--max-delete-percent %g: expected %d removal(s), got %d", testCase.maxDeletePercent, testCase.removed, removed)
+		}
+	}
+}
+
+func TestMirrorReconcile(t *testing.T) {
+	useTestMcConfig(t)
+	root := t.TempDir()
+	source, target := filepath.Join(root, "src"), filepath.Join(root, "tgt")
+	for _, dir := range []string{source, target} {
+		if e := os.MkdirAll(dir, 0o755); e != nil {
+			t.Fatal(e)
+		}
+	}
+	// The last file listed by the first pass.
+	if e := os.WriteFile(filepath.Join(source, "zzz"), []byte("data"), 0o644); e != nil {
+		t.Fatal(e)
+	}
+	waitFor := func(name string) bool {
+		for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+			if _, e := os.Stat(filepath.Join(target, name)); e == nil {
+				return true
+			}
+		}
+		return false
+	}
+
+	// No watch is joined, the new file can only be copied by a reconciliation.
+	ctx, cancel := context.WithCancel(context.Background())
+	mj := newMirrorJob(source, target, mirrorOptions{isWatch: true, activeActive: true, reconcileInterval: 50 * time.Millisecond})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		mj.mirror(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	if !waitFor("zzz") {
+		t.Fatal("the first pass did not copy the source")
+	}
+	if e := os.WriteFile(filepath.Join(source, "new"), []byte("data"), 0o644); e != nil {
+		t.Fatal(e)
+	}
+	if !waitFor("new") {
+		t.Fatal("the new file was not copied by a reconciliation")
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	colorjson "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+// mirrorVerifyRecord - a line of the mirror verification report. Every
+// record holds the HMAC of the previous one, so that removing, reordering
+// or altering any line breaks the chain up to the final summary record,
+// which can't be recomputed without the key.
+type mirrorVerifyRecord struct {
+	Type string `json:"type"` // "object" or "summary"
+
+	// Object records.
+	Key        string `json:"key,omitempty"`
+	Diff       string `json:"diff,omitempty"`
+	SourceSize int64  `json:"sourceSize,omitempty"`
+	SourceETag string `json:"sourceETag,omitempty"`
+	SourceTime string `json:"sourceTime,omitempty"`
+	TargetSize int64  `json:"targetSize,omitempty"`
+	TargetETag string `json:"targetETag,omitempty"`
+	TargetTime string `json:"targetTime,omitempty"`
+
+	// Summary record.
+	Time        string `json:"time,omitempty"`
+	Source      string `json:"source,omitempty"`
+	Target      string `json:"target,omitempty"`
+	Objects     int64  `json:"objects,omitempty"`
+	Differences int64  `json:"differences,omitempty"`
+	Match       *bool  `json:"match,omitempty"`
+
+	Prev string `json:"prev"`
+	HMAC string `json:"hmac"`
+}
+
+// sign - returns the HMAC-SHA256 of the record with its HMAC field unset.
+func (r mirrorVerifyRecord) sign(key []byte) (string, error) {
+	r.HMAC = ""
+	data, e := json.Marshal(r)
+	if e != nil {
+		return "", e
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// mirrorVerifyMessage - reports the outcome of the verification.
+type mirrorVerifyMessage struct {
+	Status      string `json:"status"`
+	Report      string `json:"report"`
+	Objects     int64  `json:"objects"`
+	Differences int64  `json:"differences"`
+	Match       bool   `json:"match"`
+	HMAC        string `json:"hmac"`
+}
+
+// String colorized mirror verification message
+func (m mirrorVerifyMessage) String() string {
+	return console.Colorize("Mirror", fmt.Sprintf("Verified %d object(s), %d difference(s), report `%s` (hmac-sha256:%s).",
+		m.Objects, m.Differences, m.Report, m.HMAC))
+}
+
+// JSON jsonified mirror verification message
+func (m mirrorVerifyMessage) JSON() string {
+	m.Status = "success"
+	msgBytes, e := colorjson.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// mirrorVerifyWriter - writes HMAC chained records.
+type mirrorVerifyWriter struct {
+	w    *bufio.Writer
+	key  []byte
+	prev string
+}
+
+func (v *mirrorVerifyWriter) write(r mirrorVerifyRecord) error {
+	r.Prev = v.prev
+	mac, e := r.sign(v.key)
+	if e != nil {
+		return e
+	}
+	r.HMAC = mac
+	data, e := json.Marshal(r)
+	if e != nil {
+		return e
+	}
+	if _, e = v.w.Write(append(data, '\n')); e != nil {
+		return e
+	}
+	v.prev = mac
+	return nil
+}
+
+// normalizeETag - returns the ETag without quotes, or an empty string for
+// ETags of multipart uploads which depend on the part size.
+func normalizeETag(etag string) string {
+	etag = strings.ToLower(strings.Trim(etag, `"`))
+	if strings.Contains(etag, "-") {
+		return ""
+	}
+	return etag
+}
+
+// etagDiffers - returns true if both objects report a comparable ETag and
+// they differ, i.e. their content differs despite the same size.
+func etagDiffers(src, dst *ClientContent) bool {
+	if src == nil || dst == nil {
+		return false
+	}
+	srcETag, dstETag := normalizeETag(src.ETag), normalizeETag(dst.ETag)
+	return srcETag != "" && dstETag != "" && srcETag != dstETag
+}
+
+// verifyMirror - compares source and target after a mirror and writes a
+// verification report to reportPath, chained and signed with key.
+func verifyMirror(ctx context.Context, sourceClnt, targetClnt Client, srcURL, dstURL, reportPath string, key []byte, opts mirrorOptions) (mirrorVerifyMessage, *probe.Error) {
+	msg := mirrorVerifyMessage{Report: reportPath}
+
+	f, e := os.Create(reportPath)
+	if e != nil {
+		return msg, probe.NewError(e)
+	}
+	defer f.Close()
+
+	v := &mirrorVerifyWriter{w: bufio.NewWriter(f), key: key}
+
+	sourceURL := sourceClnt.GetURL().String()
+	targetURL := targetClnt.GetURL().String()
+	for diffMsg := range mirrorDifference(ctx, sourceClnt, targetClnt, opts, false, true) {
+		if diffMsg.Error != nil {
+			return msg, diffMsg.Error
+		}
+		r := mirrorVerifyRecord{Type: "object"}
+		if diffMsg.firstContent != nil {
+			r.Key = strings.TrimPrefix(diffMsg.FirstURL, sourceURL)
+			r.SourceSize = diffMsg.firstContent.Size
+			r.SourceETag = diffMsg.firstContent.ETag
+			r.SourceTime = diffMsg.firstContent.Time.UTC().Format(time.RFC3339Nano)
+		}
+		if diffMsg.secondContent != nil {
+			r.Key = strings.TrimPrefix(diffMsg.SecondURL, targetURL)
+			r.TargetSize = diffMsg.secondContent.Size
+			r.TargetETag = diffMsg.secondContent.ETag
+			r.TargetTime = diffMsg.secondContent.Time.UTC().Format(time.RFC3339Nano)
+		}
+		r.Key = strings.TrimPrefix(r.Key, string(targetClnt.GetURL().Separator))
+		if matchExcludeStorageClasses(opts.excludeStorageClasses, diffMsg.firstContent, diffMsg.secondContent) {
+			continue
+		}
+		r.Diff = diffMsg.Diff.String()
+		switch {
+		case diffMsg.Diff == differInNone && etagDiffers(diffMsg.firstContent, diffMsg.secondContent):
+			r.Diff = "etag"
+			msg.Differences++
+		case diffMsg.Diff == differInNone:
+			r.Diff = "none"
+		case diffMsg.Diff == differInSecond && !opts.isRemove:
+			// Extraneous objects are kept on the target without --remove.
+		default:
+			msg.Differences++
+		}
+		msg.Objects++
+		if e = v.write(r); e != nil {
+			return msg, probe.NewError(e)
+		}
+	}
+
+	match := msg.Differences == 0
+	if e = v.write(mirrorVerifyRecord{
+		Type:        "summary",
+		Time:        UTCNow().Format(time.RFC3339Nano),
+		Source:      srcURL,
+		Target:      dstURL,
+		Objects:     msg.Objects,
+		Differences: msg.Differences,
+		Match:       &match,
+	}); e != nil {
+		return msg, probe.NewError(e)
+	}
+	if e = v.w.Flush(); e != nil {
+		return msg, probe.NewError(e)
+	}
+	msg.HMAC = v.prev
+	msg.Match = match
+	return msg, nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestMirrorVerifyChain(t *testing.T) {
+	key := []byte("secret")
+	var buf bytes.Buffer
+	v := &mirrorVerifyWriter{w: bufio.NewWriter(&buf), key: key}
+	for _, name := range []string{"a", "b/c", "d"} {
+		if e := v.write(mirrorVerifyRecord{Type: "object", Key: name, Diff: "none"}); e != nil {
+			t.Fatal(e)
+		}
+	}
+	match := true
+	if e := v.write(mirrorVerifyRecord{Type: "summary", Objects: 3, Match: &match}); e != nil {
+		t.Fatal(e)
+	}
+	if e := v.w.Flush(); e != nil {
+		t.Fatal(e)
+	}
+
+	var records []mirrorVerifyRecord
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var r mirrorVerifyRecord
+		if e := json.Unmarshal(scanner.Bytes(), &r); e != nil {
+			t.Fatal(e)
+		}
+		records = append(records, r)
+	}
+	if len(records) != 4 {
+		t.Fatalf("expected 4 records, got %d", len(records))
+	}
+
+	verify := func(records []mirrorVerifyRecord, key []byte) bool {
+		prev := ""
+		for _, r := range records {
+			mac, e := r.sign(key)
+			if e != nil {
+				t.Fatal(e)
+			}
+			if r.Prev != prev || r.HMAC != mac {
+				return false
+			}
+			prev = r.HMAC
+		}
+		return prev == v.prev
+	}
+	if !verify(records, key) {
+		t.Fatal("expected a valid chain")
+	}
+	if verify(records, []byte("other")) {
+		t.Error("expected a different key to fail the verification")
+	}
+
+	tampered := append([]mirrorVerifyRecord{}, records...)
+	tampered[1].Diff = "only-in-first"
+	if verify(tampered, key) {
+		t.Error("expected altered record to break the chain")
+	}
+	if verify(append(append([]mirrorVerifyRecord{}, records[:1]...), records[2:]...), key) {
+		t.Error("expected removed record to break the chain")
+	}
+}
+
+func TestEtagDiffers(t *testing.T) {
+	testCases := []struct {
+		src, dst string
+		differs  bool
+	}{
+		{`"d41d8cd98f00b204e9800998ecf8427e"`, "d41d8cd98f00b204e9800998ecf8427e", false},
+		{"d41d8cd98f00b204e9800998ecf8427e", "0cc175b9c0f1b6a831c399e269772661", true},
+		{"d41d8cd98f00b204e9800998ecf8427e", "", false},
+		{"d41d8cd98f00b204e9800998ecf8427e", "9b2cf535f27731c974343645a3985328-2", false},
+	}
+	for i, testCase := range testCases {
+		src, dst := &ClientContent{ETag: testCase.src}, &ClientContent{ETag: testCase.dst}
+		if differs := etagDiffers(src, dst); differs != testCase.differs {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.differs, differs)
+		}
+	}
+}
@@ -45,13 +45,22 @@ var (
 			Usage: "move objects newer than value in duration string (e.g. 7d10h31s)",
 		},
 		cli.StringFlag{
-			Name:  "storage-class, sc",
-			Usage: "set storage class for new object(s) on target",
+			Name:   "storage-class, sc",
+			Usage:  "set storage class for new object(s) on target",
+			EnvVar: envPrefix + "STORAGE_CLASS",
 		},
 		cli.StringFlag{
 			Name:  "attr",
 			Usage: "add custom metadata for the object",
 		},
+		cli.StringFlag{
+			Name:  "content-type",
+			Usage: "set the Content-Type of the new object(s) on target, instead of guessing it from the file extension",
+		},
+		cli.StringFlag{
+			Name:  "cache-control",
+			Usage: "set the Cache-Control of the new object(s) on target (e.g. 'max-age=3600')",
+		},
 		cli.BoolFlag{
 			Name:  "continue, c",
 			Usage: "create or resume move session",
@@ -61,8 +70,9 @@ var (
 			Usage: "preserve filesystem attributes (mode, ownership, timestamps)",
 		},
 		cli.BoolFlag{
-			Name:  "disable-multipart",
-			Usage: "disable multipart upload feature",
+			Name:   "disable-multipart",
+			Usage:  "disable multipart upload feature",
+			EnvVar: envPrefix + "DISABLE_MULTIPART",
 		},
 	}
 )
@@ -74,7 +84,7 @@ var mvCmd = cli.Command{
 	Action:       mainMove,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(append(mvFlags, ioFlags...), globalFlags...),
+	Flags:        append(append(append(mvFlags, transferConfirmFlags...), ioFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -137,6 +147,9 @@ EXAMPLES:
 
   16. Move a text file to an object storage and disable multipart upload feature.
       {{.Prompt}} {{.HelpName}} --disable-multipart myobject.txt play/mybucket
+
+  17. Move a report to an object storage, served as a PDF without caching.
+      {{.Prompt}} {{.HelpName}} --content-type "application/pdf" --cache-control "no-cache" report.bin play/mybucket
 `,
 }
 
@@ -215,6 +228,8 @@ func mainMove(cliCtx *cli.Context) error {
 	encKeyDB, err := getEncKeys(cliCtx)
 	fatalIf(err, "Unable to parse encryption keys.")
 
+	fatalIf(loadTransferConfig(), "Unable to load the transfer options.")
+
 	// Parse metadata.
 	userMetaMap := make(map[string]string)
 	if cliCtx.String("attr") != "" {
@@ -266,6 +281,8 @@ func mainMove(cliCtx *cli.Context) error {
 			session.Header.CommandStringFlags["older-than"] = olderThan
 			session.Header.CommandStringFlags["newer-than"] = newerThan
 			session.Header.CommandStringFlags["storage-class"] = storageClass
+			session.Header.CommandStringFlags["content-type"] = cliCtx.String("content-type")
+			session.Header.CommandStringFlags["cache-control"] = cliCtx.String("cache-control")
 			session.Header.CommandStringFlags["encrypt-key"] = sseKeys
 			session.Header.CommandStringFlags["encrypt"] = sse
 			session.Header.CommandBoolFlags["session"] = cliCtx.Bool("continue")
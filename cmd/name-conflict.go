@@ -0,0 +1,142 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// nameConflictMode - how a listing resolves an object named like the prefix
+// of other objects, e.g. `a` and `a/b`. Object storage holds both, copies
+// to a filesystem can only hold one of them.
+type nameConflictMode int
+
+const (
+	nameConflictAllow  nameConflictMode = iota // keep both
+	nameConflictReport                         // report the object, keep the prefix
+	nameConflictObject                         // keep the object, skip the prefix
+	nameConflictPrefix                         // keep the prefix, skip the object
+)
+
+// parseNameConflictMode - parses the value of --name-conflict.
+func parseNameConflictMode(s string) (nameConflictMode, *probe.Error) {
+	switch strings.ToLower(s) {
+	case "":
+		return nameConflictReport, nil
+	case "object":
+		return nameConflictObject, nil
+	case "prefix":
+		return nameConflictPrefix, nil
+	}
+	return nameConflictAllow, errInvalidArgument().Trace(s)
+}
+
+// resolveNameConflicts - resolves the objects of the sorted listing of
+// baseURL named like the prefix of other objects. An object is held back,
+// with the entries listed after it, until the listing passes its prefix.
+// Reported objects are sent with an ObjectNameConflict error.
+func resolveNameConflicts(ctx context.Context, listCh <-chan *ClientContent, baseURL string, mode nameConflictMode) <-chan *ClientContent {
+	if mode == nameConflictAllow {
+		return listCh
+	}
+	resolvedCh := make(chan *ClientContent)
+	go func() {
+		defer close(resolvedCh)
+
+		var pending []*ClientContent // held back, in listing order
+		var undecided []int          // objects of pending whose prefix is not passed
+		var skipPrefix string        // objects under a kept object are skipped
+
+		flush := func(all bool) bool {
+			n := len(pending)
+			if !all && len(undecided) > 0 {
+				n = undecided[0]
+			}
+			for _, content := range pending[:n] {
+				if content == nil {
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return false
+				case resolvedCh <- content:
+				}
+			}
+			pending = pending[n:]
+			for i := range undecided {
+				undecided[i] -= n
+			}
+			return true
+		}
+
+		for content := range listCh {
+			if content.Err != nil || !content.Type.IsRegular() {
+				pending = append(pending, content)
+				if !flush(false) {
+					return
+				}
+				continue
+			}
+			separator := string(content.URL.Separator)
+			name := strings.TrimPrefix(content.URL.String(), baseURL)
+			if skipPrefix != "" && strings.HasPrefix(name, skipPrefix) {
+				continue
+			}
+			skipPrefix = ""
+
+			for len(undecided) > 0 {
+				i := undecided[len(undecided)-1]
+				object := pending[i]
+				prefix := strings.TrimPrefix(object.URL.String(), baseURL) + separator
+				if strings.HasPrefix(name, prefix) {
+					undecided = undecided[:len(undecided)-1]
+					switch mode {
+					case nameConflictReport:
+						reported := *object
+						reported.Err = probe.NewError(ObjectNameConflict{Object: object.URL.String()})
+						pending[i] = &reported
+					case nameConflictPrefix:
+						pending[i] = nil
+					case nameConflictObject:
+						skipPrefix = prefix
+					}
+					continue
+				}
+				if name < prefix {
+					// Objects under the prefix can still be listed.
+					break
+				}
+				undecided = undecided[:len(undecided)-1]
+			}
+			if skipPrefix != "" {
+				continue
+			}
+
+			undecided = append(undecided, len(pending))
+			pending = append(pending, content)
+			if !flush(false) {
+				return
+			}
+		}
+		flush(true)
+	}()
+	return resolvedCh
+}
@@ -59,6 +59,9 @@ type ParallelManager struct {
 	// aligned at 64bit. See https://github.com/golang/go/issues/599
 	sentBytes int64
 
+	// Tasks queued or running
+	pending int64
+
 	// Synchronize workers
 	wg          *sync.WaitGroup
 	barrierSync sync.RWMutex
@@ -101,8 +104,12 @@ func (p *ParallelManager) addWorker() {
 				return
 			}
 
+			// Hold new tasks while the transfers are paused.
+			waitTransfers(globalContext)
+
 			// Execute the task and send the result to channel.
 			p.resultCh <- t.fn()
+			atomic.AddInt64(&p.pending, -1)
 
 			if t.barrier {
 				p.barrierSync.Unlock()
@@ -215,9 +222,20 @@ func (p *ParallelManager) doQueueTask(t task) {
 	} else {
 		p.barrierSync.RLock()
 	}
+	atomic.AddInt64(&p.pending, 1)
 	p.queueCh <- t
 }
 
+// queued - returns the number of tasks queued or running.
+func (p *ParallelManager) queued() int64 {
+	return atomic.LoadInt64(&p.pending)
+}
+
+// sent - returns the number of bytes sent.
+func (p *ParallelManager) sent() int64 {
+	return atomic.LoadInt64(&p.sentBytes)
+}
+
 // Wait for all workers to finish tasks before shutting down Parallel
 func (p *ParallelManager) stopAndWait() {
 	close(p.queueCh)
@@ -265,8 +283,9 @@ func availableMemory() (available uint64) {
 	return
 }
 
-// newParallelManager starts new workers waiting for executing tasks
-func newParallelManager(resultCh chan URLs) *ParallelManager {
+// newParallelManager starts new workers waiting for executing tasks, as
+// many as workers, or else more of them as long as the bandwidth grows.
+func newParallelManager(resultCh chan URLs, workers int) *ParallelManager {
 	p := &ParallelManager{
 		wg:            &sync.WaitGroup{},
 		workersNum:    0,
@@ -276,6 +295,13 @@ func newParallelManager(resultCh chan URLs) *ParallelManager {
 		maxMem:        availableMemory(),
 	}
 
+	if workers > 0 {
+		for i := 0; i < workers; i++ {
+			p.addWorker()
+		}
+		return p
+	}
+
 	// Start with runtime.NumCPU().
 	for i := 0; i < runtime.NumCPU(); i++ {
 		p.addWorker()
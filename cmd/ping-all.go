@@ -0,0 +1,353 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v3"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+	"github.com/olekukonko/tablewriter"
+)
+
+// pingHostStats - latency statistics of a pinged host.
+type pingHostStats struct {
+	Alias    string        `json:"alias"`
+	Endpoint string        `json:"endpoint"`
+	Online   bool          `json:"online"`
+	Last     time.Duration `json:"last"`
+	Min      time.Duration `json:"min"`
+	Max      time.Duration `json:"max"`
+	Average  time.Duration `json:"average"`
+	Count    int           `json:"count"`
+	Errors   int           `json:"errors"`
+	Error    string        `json:"error,omitempty"`
+
+	sum time.Duration
+}
+
+// update - accounts the result of a ping of the host.
+func (s *pingHostStats) update(result madmin.AliveResult) {
+	if result.Error != nil {
+		s.Online = false
+		s.Errors++
+		s.Error = result.Error.Error()
+		return
+	}
+	s.Online = result.Online
+	s.Error = ""
+	s.Last = result.ResponseTime
+	if s.Count == 0 || result.ResponseTime < s.Min {
+		s.Min = result.ResponseTime
+	}
+	if result.ResponseTime > s.Max {
+		s.Max = result.ResponseTime
+	}
+	s.Count++
+	s.sum += result.ResponseTime
+	s.Average = s.sum / time.Duration(s.Count)
+}
+
+// sortPingHostStats - sorts the hosts by average latency, the hosts
+// never reached being last.
+func sortPingHostStats(hosts []pingHostStats) {
+	sort.SliceStable(hosts, func(i, j int) bool {
+		if (hosts[i].Count == 0) != (hosts[j].Count == 0) {
+			return hosts[i].Count > 0
+		}
+		if hosts[i].Average != hosts[j].Average {
+			return hosts[i].Average < hosts[j].Average
+		}
+		return hosts[i].Alias < hosts[j].Alias
+	})
+}
+
+// pingAllMessage - latency statistics of all the pinged hosts.
+type pingAllMessage struct {
+	Status  string          `json:"status"`
+	Counter int             `json:"counter"`
+	Hosts   []pingHostStats `json:"hosts"`
+	final   bool
+}
+
+// JSON jsonified ping all message.
+func (m pingAllMessage) JSON() string {
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// String colorized ping all message.
+func (m pingAllMessage) String() string {
+	var s strings.Builder
+	table := tablewriter.NewWriter(&s)
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(true)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetTablePadding("\t") // pad with tabs
+	table.SetNoWhiteSpace(true)
+	table.SetHeader([]string{"Alias", "Endpoint", "Last", "Min", "Avg", "Max", "Errors", "Status"})
+
+	latency := func(host pingHostStats, d time.Duration) string {
+		if host.Count == 0 {
+			return "-"
+		}
+		return strings.TrimSpace(trimToTwoDecimal(d))
+	}
+	for _, host := range m.Hosts {
+		status := console.Colorize("Info", "online")
+		switch {
+		case host.Error != "":
+			status = console.Colorize("InfoFail", host.Error)
+		case !host.Online:
+			status = console.Colorize("InfoFail", "offline")
+		}
+		table.Append([]string{
+			host.Alias,
+			host.Endpoint,
+			latency(host, host.Last),
+			latency(host, host.Min),
+			latency(host, host.Average),
+			latency(host, host.Max),
+			strconv.Itoa(host.Errors),
+			status,
+		})
+	}
+	table.Render()
+	return s.String()
+}
+
+// pingTarget - a host to ping, and its statistics.
+type pingTarget struct {
+	client *madmin.AnonymousClient
+	stats  pingHostStats
+}
+
+// pingTargetAliases - returns the aliases pinged, all the configured
+// ones with --all.
+func pingTargetAliases(cliCtx *cli.Context) []string {
+	if !cliCtx.Bool("all") {
+		return cliCtx.Args()
+	}
+	config, err := loadMcConfig()
+	fatalIf(err.Trace(), "Unable to load the configuration.")
+	var aliases []string
+	for alias, hostCfg := range config.Aliases {
+		// Only HTTP endpoints answer liveness checks.
+		if strings.HasPrefix(hostCfg.URL, "http://") || strings.HasPrefix(hostCfg.URL, "https://") {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// pingAllRound - pings all the targets at once.
+func pingAllRound(ctx context.Context, targets []*pingTarget) {
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		if target.client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(target *pingTarget) {
+			defer wg.Done()
+			pingCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+			defer cancel()
+			result, ok := <-target.client.Alive(pingCtx, madmin.AliveOpts{})
+			if !ok {
+				result.Error = pingCtx.Err()
+			}
+			if result.Error != nil && ctx.Err() != nil {
+				return
+			}
+			target.stats.update(result)
+		}(target)
+	}
+	wg.Wait()
+}
+
+// pingAll - pings the targets count times, forever when count is 0,
+// sending the statistics of every round to send.
+func pingAll(ctx context.Context, aliases []string, count int, interval time.Duration, send func(pingAllMessage)) {
+	targets := make([]*pingTarget, 0, len(aliases))
+	for _, alias := range aliases {
+		target := &pingTarget{stats: pingHostStats{Alias: alias}}
+		client, err := newAnonymousClient(alias)
+		if err != nil {
+			target.stats.Error = err.ToGoError().Error()
+		} else {
+			target.client = client
+			if _, urlStrFull, _, err := expandAlias(alias); err == nil {
+				target.stats.Endpoint = urlStrFull
+			}
+		}
+		targets = append(targets, target)
+	}
+
+	for index := 1; count == 0 || index <= count; index++ {
+		pingAllRound(ctx, targets)
+		if ctx.Err() != nil {
+			return
+		}
+		msg := pingAllMessage{
+			Status:  "success",
+			Counter: index,
+			final:   index == count,
+		}
+		for _, target := range targets {
+			msg.Hosts = append(msg.Hosts, target.stats)
+		}
+		sortPingHostStats(msg.Hosts)
+		send(msg)
+		if msg.final {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// mainPingAll - pings all the targets, printing their statistics once
+// done, or updating them continuously with --watch.
+func mainPingAll(cliCtx *cli.Context) error {
+	if cliCtx.Bool("distributed") {
+		fatalIf(errInvalidArgument(), "--distributed cannot be used with --all or --watch.")
+	}
+	if cliCtx.IsSet("count") && cliCtx.Int("count") < 1 {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "ping count cannot be less than 1")
+	}
+	aliases := pingTargetAliases(cliCtx)
+	if len(aliases) == 0 {
+		fatalIf(errInvalidArgument(), "No HTTP alias configured to ping.")
+	}
+
+	count := cliCtx.Int("count")
+	interval := time.Duration(cliCtx.Int("interval")) * time.Second
+
+	ctx, cancel := context.WithCancel(globalContext)
+	defer cancel()
+
+	if !cliCtx.Bool("watch") {
+		// Without --watch, only the statistics of the last round are printed.
+		if count == 0 {
+			count = 1
+		}
+		var last pingAllMessage
+		pingAll(ctx, aliases, count, interval, func(msg pingAllMessage) { last = msg })
+		if last.Status == "" {
+			return globalContext.Err()
+		}
+		printMsg(last)
+		return nil
+	}
+
+	if globalJSON {
+		pingAll(ctx, aliases, count, interval, func(msg pingAllMessage) { printMsg(msg) })
+		return nil
+	}
+
+	ui := tea.NewProgram(initPingAllUI())
+	go func() {
+		pingAll(ctx, aliases, count, interval, func(msg pingAllMessage) { ui.Send(msg) })
+	}()
+	if _, e := ui.Run(); e != nil && !errors.Is(e, tea.ErrProgramKilled) {
+		fatalIf(probe.NewError(e), "Unable to display the ping statistics.")
+	}
+	return nil
+}
+
+type pingAllUI struct {
+	current  pingAllMessage
+	spinner  spinner.Model
+	quitting bool
+}
+
+func initPingAllUI() *pingAllUI {
+	s := spinner.New()
+	s.Spinner = spinner.Points
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	return &pingAllUI{spinner: s}
+}
+
+func (m *pingAllUI) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m *pingAllUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.quitting {
+		return m, tea.Quit
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+		default:
+			return m, nil
+		}
+	case pingAllMessage:
+		m.current = msg
+		if msg.final {
+			m.quitting = true
+			return m, tea.Quit
+		}
+		return m, nil
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m *pingAllUI) View() string {
+	var s strings.Builder
+	if !m.quitting {
+		s.WriteString(fmt.Sprintf("Pinged %d times %s\n", m.current.Counter, m.spinner.View()))
+	}
+	if m.current.Counter == 0 {
+		s.WriteString("(waiting for data)\n")
+		return s.String()
+	}
+	s.WriteString(m.current.String())
+	return s.String()
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/minio/madmin-go/v3"
+)
+
+func TestPingHostStats(t *testing.T) {
+	var stats pingHostStats
+	results := []madmin.AliveResult{
+		{ResponseTime: 30 * time.Millisecond, Online: true},
+		{Error: errors.New("connection refused")},
+		{ResponseTime: 10 * time.Millisecond, Online: true},
+		{ResponseTime: 20 * time.Millisecond, Online: true},
+	}
+	for _, result := range results {
+		stats.update(result)
+	}
+	want := pingHostStats{
+		Online:  true,
+		Last:    20 * time.Millisecond,
+		Min:     10 * time.Millisecond,
+		Max:     30 * time.Millisecond,
+		Average: 20 * time.Millisecond,
+		Count:   3,
+		Errors:  1,
+		sum:     60 * time.Millisecond,
+	}
+	if stats != want {
+		t.Fatalf("expected %+v, got %+v", want, stats)
+	}
+
+	stats.update(madmin.AliveResult{Error: errors.New("timeout")})
+	if stats.Online || stats.Error != "timeout" || stats.Errors != 2 || stats.Count != 3 {
+		t.Fatalf("unexpected statistics after an error %+v", stats)
+	}
+}
+
+func TestSortPingHostStats(t *testing.T) {
+	hosts := []pingHostStats{
+		{Alias: "down"},
+		{Alias: "slow", Count: 1, Average: 50 * time.Millisecond},
+		{Alias: "fast", Count: 1, Average: 5 * time.Millisecond},
+		{Alias: "also-fast", Count: 2, Average: 5 * time.Millisecond},
+	}
+	sortPingHostStats(hosts)
+	want := []string{"also-fast", "fast", "slow", "down"}
+	for i, host := range hosts {
+		if host.Alias != want[i] {
+			t.Fatalf("expected %v at %d, got %s", want[i], i, host.Alias)
+		}
+	}
+}
@@ -58,6 +58,14 @@ var pingFlags = []cli.Flag{
 		Name:  "distributed, a",
 		Usage: "ping all the servers in the cluster, use it when you have direct access to nodes/pods",
 	},
+	cli.BoolFlag{
+		Name:  "all",
+		Usage: "ping all the configured aliases and summarize their latency",
+	},
+	cli.BoolFlag{
+		Name:  "watch, w",
+		Usage: "continuously update a latency table of the pinged aliases",
+	},
 }
 
 // return latency and liveness probe.
@@ -74,6 +82,7 @@ var pingCmd = cli.Command{
 
 USAGE:
   {{.HelpName}} [FLAGS] TARGET [TARGET...]
+  {{.HelpName}} [FLAGS] --all
 {{if .VisibleFlags}}
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -90,6 +99,15 @@ EXAMPLES:
 
   4. Stop pinging when error count > 20.
      {{.Prompt}} {{.HelpName}} --error-count 20 myminio
+
+  5. Summarize the latency of all the configured aliases over 10 pings, fastest first.
+     {{.Prompt}} {{.HelpName}} --all --count 10
+
+  6. Continuously update a latency and error table of all the configured aliases.
+     {{.Prompt}} {{.HelpName}} --all --watch
+
+  7. Continuously compare the latency of two aliases.
+     {{.Prompt}} {{.HelpName}} --watch us-east eu-west
 `,
 }
 
@@ -97,6 +115,12 @@ var stop bool
 
 // Validate command line arguments.
 func checkPingSyntax(cliCtx *cli.Context) {
+	if cliCtx.Bool("all") {
+		if cliCtx.Args().Present() {
+			fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--all cannot be used with targets.")
+		}
+		return
+	}
 	if !cliCtx.Args().Present() {
 		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
 	}
@@ -340,6 +364,10 @@ func mainPing(cliCtx *cli.Context) error {
 	console.SetColor("Info", color.New(color.FgGreen, color.Bold))
 	console.SetColor("InfoFail", color.New(color.FgRed, color.Bold))
 
+	if cliCtx.Bool("all") || cliCtx.Bool("watch") {
+		return mainPingAll(cliCtx)
+	}
+
 	ctx, cancel := context.WithCancel(globalContext)
 	defer cancel()
 
@@ -18,15 +18,19 @@
 package cmd
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"runtime/debug"
 	"syscall"
 
 	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
 	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
 	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/minio-go/v7"
+	"github.com/minio/pkg/v2/console"
 )
 
 func defaultPartSize() string {
@@ -36,13 +40,22 @@ func defaultPartSize() string {
 
 var pipeFlags = []cli.Flag{
 	cli.StringFlag{
-		Name:  "storage-class, sc",
-		Usage: "set storage class for new object(s) on target",
+		Name:   "storage-class, sc",
+		Usage:  "set storage class for new object(s) on target",
+		EnvVar: envPrefix + "STORAGE_CLASS",
 	},
 	cli.StringFlag{
 		Name:  "attr",
 		Usage: "add custom metadata for the object",
 	},
+	cli.StringFlag{
+		Name:  "content-type",
+		Usage: "set the Content-Type of the object on target, instead of guessing it from the name",
+	},
+	cli.StringFlag{
+		Name:  "cache-control",
+		Usage: "set the Cache-Control of the object on target (e.g. 'max-age=3600')",
+	},
 	cli.StringFlag{
 		Name:  "tags",
 		Usage: "apply one or more tags to the uploaded objects",
@@ -55,7 +68,7 @@ var pipeFlags = []cli.Flag{
 	cli.StringFlag{
 		Name:  "part-size",
 		Value: defaultPartSize(),
-		Usage: "customize chunk size for each concurrent upload",
+		Usage: "customize chunk size for each concurrent upload, the stream can be at most 10000 chunks long",
 	},
 	cli.IntFlag{
 		Name:   "pipe-max-size",
@@ -106,10 +119,16 @@ EXAMPLES:
 
   7. Set tags to the uploaded objects
       {{.Prompt}} tar cvf - . | {{.HelpName}} --tags "category=prod&type=backup" play/mybucket/backup.tar
+
+  8. Upload a generated page with its Content-Type and Cache-Control.
+      {{.Prompt}} ./render.sh | {{.HelpName}} --content-type "text/html; charset=utf-8" --cache-control "max-age=300" play/mybucket/index
 `,
 }
 
 func pipe(ctx *cli.Context, targetURL string, encKeyDB map[string][]prefixSSEPair, meta map[string]string, quiet bool) *probe.Error {
+	if ctx.IsSet("pipe-max-size") && !buildWithPipeBuffer {
+		fatalIf(errNotBuiltWith("pipe-buffer"), "Unable to increase custom pipe-max-size")
+	}
 	// If possible increase the pipe buffer size
 	if e := increasePipeBufferSize(os.Stdin, ctx.Int("pipe-max-size")); e != nil {
 		fatalIf(probe.NewError(e), "Unable to increase custom pipe-max-size")
@@ -117,7 +136,10 @@ func pipe(ctx *cli.Context, targetURL string, encKeyDB map[string][]prefixSSEPai
 
 	if targetURL == "" {
 		// When no target is specified, pipe cat's stdin to stdout.
-		return catOut(os.Stdin, -1).Trace()
+		if err := catOut(os.Stdin, -1); !isStdoutClosed(err) {
+			return err.Trace()
+		}
+		return nil
 	}
 
 	storageClass := ctx.String("storage-class")
@@ -125,6 +147,10 @@ func pipe(ctx *cli.Context, targetURL string, encKeyDB map[string][]prefixSSEPai
 	sseKey := getSSE(targetURL, encKeyDB[alias])
 
 	multipartThreads := ctx.Int("concurrent")
+	concurrentStream := ctx.IsSet("concurrent")
+	if !concurrentStream && globalTransfer.ParallelParts > 1 {
+		multipartThreads, concurrentStream = globalTransfer.ParallelParts, true
+	}
 	if multipartThreads > 1 {
 		// We will be allocating large buffers, reduce default GC overhead
 		debug.SetGCPercent(20)
@@ -132,7 +158,11 @@ func pipe(ctx *cli.Context, targetURL string, encKeyDB map[string][]prefixSSEPai
 
 	var multipartSize uint64
 	var e error
-	if partSizeStr := ctx.String("part-size"); partSizeStr != "" {
+	partSizeStr := ctx.String("part-size")
+	if !ctx.IsSet("part-size") && globalTransfer.PartSize != "" {
+		partSizeStr = globalTransfer.PartSize
+	}
+	if partSizeStr != "" {
 		multipartSize, e = humanize.ParseBytes(partSizeStr)
 		if e != nil {
 			return probe.NewError(e)
@@ -148,7 +178,7 @@ func pipe(ctx *cli.Context, targetURL string, encKeyDB map[string][]prefixSSEPai
 		metadata:         meta,
 		multipartSize:    multipartSize,
 		multipartThreads: uint(multipartThreads),
-		concurrentStream: ctx.IsSet("concurrent"),
+		concurrentStream: concurrentStream,
 	}
 
 	var reader io.Reader
@@ -159,7 +189,7 @@ func pipe(ctx *cli.Context, targetURL string, encKeyDB map[string][]prefixSSEPai
 		reader = os.Stdin
 	}
 
-	_, err := putTargetStreamWithURL(targetURL, reader, -1, opts)
+	n, err := putTargetStreamWithURL(targetURL, reader, -1, opts)
 	// TODO: See if this check is necessary.
 	switch e := err.ToGoError().(type) {
 	case *os.PathError:
@@ -168,29 +198,61 @@ func pipe(ctx *cli.Context, targetURL string, encKeyDB map[string][]prefixSSEPai
 			return nil
 		}
 	}
-	return err.Trace(targetURL)
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+	if quiet {
+		// Without a progress bar, report what was uploaded.
+		printMsg(pipeMessage{Target: targetURL, Size: n})
+	}
+	return nil
+}
+
+// pipeMessage container for the uploaded stream.
+type pipeMessage struct {
+	Status string `json:"status"`
+	Target string `json:"target"`
+	Size   int64  `json:"size"`
+}
+
+// String colorized pipe message
+func (p pipeMessage) String() string {
+	return console.Colorize("Pipe", fmt.Sprintf("`stdin` -> `%s` (%s)", p.Target, humanize.IBytes(uint64(p.Size))))
+}
+
+// JSON jsonified pipe message
+func (p pipeMessage) JSON() string {
+	p.Status = "success"
+	pipeMessageBytes, e := json.MarshalIndent(p, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(pipeMessageBytes)
 }
 
 // checkPipeSyntax - validate arguments passed by user
 func checkPipeSyntax(ctx *cli.Context) {
-	if len(ctx.Args()) != 1 {
+	// Without a target stdin is written to stdout.
+	if len(ctx.Args()) > 1 {
 		showCommandHelpAndExit(ctx, 1) // last argument is exit code.
 	}
 }
 
 // mainPipe is the main entry point for pipe command.
 func mainPipe(ctx *cli.Context) error {
-	// validate pipe input arguments.
-	checkPipeSyntax(ctx)
 	// Parse encryption keys per command.
 	encKeyDB, err := getEncKeys(ctx)
 	fatalIf(err, "Unable to parse encryption keys.")
 
+	fatalIf(loadTransferConfig(), "Unable to load the transfer options.")
+
 	// validate pipe input arguments.
 	checkPipeSyntax(ctx)
 
-	// globalQuiet is true for no window size to get. We just need --quiet here.
-	quiet := ctx.IsSet("quiet")
+	// globalQuiet is true for no window size to get. We just need --quiet here,
+	// progress is still suppressed for JSON output or when stdout is not a terminal.
+	quiet := ctx.IsSet("quiet") || globalJSON || !isTerminal()
+
+	// Additional command specific theme customization.
+	console.SetColor("Pipe", color.New(color.FgGreen, color.Bold))
 
 	meta := map[string]string{}
 	if attr := ctx.String("attr"); attr != "" {
@@ -200,6 +262,12 @@ func mainPipe(ctx *cli.Context) error {
 	if tags := ctx.String("tags"); tags != "" {
 		meta["X-Amz-Tagging"] = tags
 	}
+	if contentType := ctx.String("content-type"); contentType != "" {
+		meta["Content-Type"] = contentType
+	}
+	if cacheControl := ctx.String("cache-control"); cacheControl != "" {
+		meta["Cache-Control"] = cacheControl
+	}
 	if len(ctx.Args()) == 0 {
 		err = pipe(ctx, "", nil, meta, quiet)
 		fatalIf(err.Trace("stdout"), "Unable to write to one or more targets.")
@@ -0,0 +1,43 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPipeMessage(t *testing.T) {
+	msg := pipeMessage{Target: "play/bucket/stream", Size: 3 << 20}
+	if s := msg.String(); !strings.Contains(s, "`play/bucket/stream`") || !strings.Contains(s, "3.0 MiB") {
+		t.Errorf("unexpected message %q", s)
+	}
+
+	var doc struct {
+		Status string `json:"status"`
+		Target string `json:"target"`
+		Size   int64  `json:"size"`
+	}
+	if e := json.Unmarshal([]byte(msg.JSON()), &doc); e != nil {
+		t.Fatal(e)
+	}
+	if doc.Status != "success" || doc.Target != msg.Target || doc.Size != msg.Size {
+		t.Errorf("unexpected JSON document %+v", doc)
+	}
+}
@@ -30,6 +30,9 @@ import (
 
 const pipeMaxSizeProcFile = "/proc/sys/fs/pipe-max-size"
 
+// Pipe buffers are resized with --pipe-max-size, see `mc --version`.
+const buildWithPipeBuffer = true
+
 func setPipeSize(fd uintptr, size int) error {
 	_, err := unix.FcntlInt(fd, unix.F_SETPIPE_SZ, size)
 	return err
@@ -21,6 +21,9 @@ package cmd
 
 import "os"
 
+// Pipe buffers can't be resized, see `mc --version`.
+const buildWithPipeBuffer = false
+
 func increasePipeBufferSize(_ *os.File, _ int) error {
 	// this is not supported on non-Linux platforms.
 	return nil
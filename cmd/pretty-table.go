@@ -20,6 +20,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/mattn/go-runewidth"
 	"github.com/minio/pkg/v2/console"
 )
 
@@ -61,14 +62,9 @@ func (t PrettyTable) buildRow(contents ...string) (line string) {
 		fieldContent := ""
 		fieldFormat := "%s"
 		if t.cols[i].maxLen >= 0 {
-			// Override field format
-			fieldFormat = fmt.Sprintf("%%-%d.%ds", t.cols[i].maxLen, t.cols[i].maxLen)
-			// Cut field string and add '...' if length is greater than maxLen
-			if len(contents[i]) > t.cols[i].maxLen {
-				fieldContent = contents[i][:t.cols[i].maxLen-len(dots)] + dots
-			} else {
-				fieldContent = contents[i]
-			}
+			// Cut field string and add '...' if wider than maxLen, pad it
+			// to maxLen terminal cells otherwise.
+			fieldContent = runewidth.FillRight(runewidth.Truncate(contents[i], t.cols[i].maxLen, dots), t.cols[i].maxLen)
 		} else {
 			fieldContent = contents[i]
 		}
@@ -39,6 +39,10 @@ func TestPrettyTable(t *testing.T) {
 		{" | ", []Field{{"", -1}, {"", -1}, {"", -1}}, []string{"column1", "column2", "column3"}, "column1 | column2 | column3"},
 		// Test 6: multiple fields
 		{" | ", []Field{{"", 5}, {"", -1}}, []string{"144550032", "my long content that should not be cut"}, "14... | my long content that should not be cut"},
+		// Test 7: wide characters take two cells
+		{" | ", []Field{{"", 8}, {"", -1}}, []string{"写真", "end"}, "写真     | end"},
+		// Test 8: wide characters are cut on a character boundary
+		{" | ", []Field{{"", 8}, {"", -1}}, []string{"日本語のファイル", "end"}, "日本...  | end"},
 	}
 
 	for idx, testCase := range testCases {
@@ -20,7 +20,7 @@ package cmd
 import (
 	"io"
 	"runtime"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/cheggaaa/pb"
@@ -28,6 +28,19 @@ import (
 	"github.com/minio/pkg/v2/console"
 )
 
+// isProgressBarEnabled - returns true if progress bars and scanning
+// animations can be displayed, they are suppressed in quiet and JSON
+// modes and whenever the output is not a terminal.
+func isProgressBarEnabled() bool {
+	return !globalQuiet && !globalJSON && isTerminal()
+}
+
+// isStatusLineEnabled - status lines rewritten with terminal escapes are
+// only printed to a terminal, unless --force-color is set.
+func isStatusLineEnabled() bool {
+	return !globalJSON && (isTerminal() || globalForceColor)
+}
+
 // progress extender.
 type progressBar struct {
 	*pb.ProgressBar
@@ -123,11 +136,18 @@ func (p *progressBar) SetTotal(total int64) {
 	p.ProgressBar.Total = total
 }
 
-// cursorAnimate - returns a animated rune through read channel for every read.
-func cursorAnimate() <-chan string {
-	cursorCh := make(chan string)
-	var cursors []string
+// cursorAnimation - an animated cursor, advanced on every read by the
+// command drawing it. It runs no goroutine, so there is nothing to stop
+// or leak once the command is done with it.
+type cursorAnimation struct {
+	mu      sync.Mutex
+	cursors []string
+	current int
+}
 
+// newCursorAnimation - returns a cursor animation for the terminal.
+func newCursorAnimation() *cursorAnimation {
+	var cursors []string
 	switch runtime.GOOS {
 	case "linux":
 		// cursors = "➩➪➫➬➭➮➯➱"
@@ -142,29 +162,21 @@ func cursorAnimate() <-chan string {
 	default:
 		cursors = []string{"|", "/", "-", "\\"}
 	}
-	go func() {
-		for {
-			for _, cursor := range cursors {
-				cursorCh <- cursor
-			}
-		}
-	}()
-	return cursorCh
+	return &cursorAnimation{cursors: cursors}
+}
+
+// next - returns the next cursor of the animation.
+func (c *cursorAnimation) next() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cursor := c.cursors[c.current]
+	c.current = (c.current + 1) % len(c.cursors)
+	return cursor
 }
 
 // fixateBarCaption - fancify bar caption based on the terminal width.
 func fixateBarCaption(caption string, width int) string {
-	switch {
-	case len(caption) > width:
-		// Trim caption to fit within the screen
-		trimSize := len(caption) - width + 3
-		if trimSize < len(caption) {
-			caption = "..." + caption[trimSize:]
-		}
-	case len(caption) < width:
-		caption += strings.Repeat(" ", width-len(caption))
-	}
-	return caption
+	return fitDisplayWidth(caption, width)
 }
 
 // getFixedWidth - get a fixed width based for a given percentage.
@@ -0,0 +1,40 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestCursorAnimation(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	cursor := newCursorAnimation()
+	first := cursor.next()
+	for i := 1; i < len(cursor.cursors); i++ {
+		if cursor.next() == first {
+			t.Fatalf("cursor %d repeats the first cursor", i+1)
+		}
+	}
+	if got := cursor.next(); got != first {
+		t.Errorf("expected the animation to restart with %q, got %q", first, got)
+	}
+	if n := runtime.NumGoroutine(); n > goroutines {
+		t.Errorf("expected no goroutine to be started, found %d more", n-goroutines)
+	}
+}
@@ -32,14 +32,16 @@ import (
 var (
 	putFlags = []cli.Flag{
 		cli.IntFlag{
-			Name:  "parallel, P",
-			Usage: "upload number of parts in parallel",
-			Value: 4,
+			Name:   "parallel, P",
+			Usage:  "upload number of parts in parallel",
+			EnvVar: envPrefix + "PARALLEL," + envPrefix + "UPLOAD_MULTIPART_THREADS",
+			Value:  4,
 		},
 		cli.StringFlag{
-			Name:  "part-size, s",
-			Usage: "each part size",
-			Value: "16MiB",
+			Name:   "part-size, s",
+			Usage:  "each part size",
+			EnvVar: envPrefix + "PART_SIZE," + envPrefix + "UPLOAD_MULTIPART_SIZE",
+			Value:  "16MiB",
 		},
 	}
 )
@@ -116,7 +118,7 @@ func mainPut(cliCtx *cli.Context) (e error) {
 	var pg ProgressReader
 
 	// Enable progress bar reader only during default mode.
-	if !globalQuiet && !globalJSON { // set up progress bar
+	if isProgressBarEnabled() { // set up progress bar
 		pg = newProgressBar(totalBytes)
 	} else {
 		pg = newAccounter(totalBytes)
@@ -175,7 +177,7 @@ func mainPut(cliCtx *cli.Context) (e error) {
 func printPutURLsError(putURLs *URLs) {
 	// Print in new line and adjust to top so that we
 	// don't print over the ongoing scan bar
-	if !globalQuiet && !globalJSON {
+	if isProgressBarEnabled() {
 		console.Eraseline()
 	}
 	if strings.Contains(putURLs.Error.ToGoError().Error(),
@@ -191,7 +193,7 @@ func printPutURLsError(putURLs *URLs) {
 func showLastProgressBar(pg ProgressReader, e error) {
 	if e != nil {
 		// We only erase a line if we are displaying a progress bar
-		if !globalQuiet && !globalJSON {
+		if isProgressBarEnabled() {
 			console.Eraseline()
 		}
 		return
@@ -97,7 +97,7 @@ func guessPutURLType(ctx context.Context, o prepareCopyURLsOpts) (*copyURLsConte
 			cc.copyType = copyURLsTypeInvalid
 			return cc, err
 		}
-		s3clnt, ok := client.(*S3Client)
+		s3clnt, ok := unwrapClient(client).(*S3Client)
 		if !ok {
 			cc.copyType = copyURLsTypeInvalid
 			return cc, probe.NewError(fmt.Errorf("Target is not s3."))
@@ -0,0 +1,132 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Styles of --quote for the object names printed.
+const (
+	quoteNone  = "none"
+	quoteShell = "shell"
+	quoteC     = "c"
+)
+
+// parseQuoteStyle - validates the style of --quote, none when empty.
+func parseQuoteStyle(style string) (string, error) {
+	switch strings.ToLower(style) {
+	case "", quoteNone:
+		return quoteNone, nil
+	case quoteShell:
+		return quoteShell, nil
+	case quoteC:
+		return quoteC, nil
+	}
+	return "", fmt.Errorf("unknown quoting style `%s`, expected one of `shell`, `c` or `none`", style)
+}
+
+// quoteName - renders an object name, or URL, in the --quote style.
+func quoteName(name string) string {
+	switch globalQuote {
+	case quoteShell:
+		return shellQuoteName(name)
+	case quoteC:
+		return cQuoteName(name)
+	}
+	return name
+}
+
+// shellQuoteName - quotes s for POSIX shells when it has characters the
+// shell would interpret, names with control characters are written
+// as $'...', which bash, zsh and ksh decode.
+func shellQuoteName(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool { return !isShellSafe(r) }) < 0 {
+		return s
+	}
+	if !isPrintable(s) {
+		return "$'" + escapeC(s, '\'') + "'"
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// cQuoteName - quotes s as a C string literal.
+func cQuoteName(s string) string {
+	return `"` + escapeC(s, '"') + `"`
+}
+
+func isShellSafe(r rune) bool {
+	if r >= utf8.RuneSelf {
+		return r != utf8.RuneError && unicode.IsPrint(r) && !unicode.IsSpace(r)
+	}
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+		strings.ContainsRune("_@%+=:,./-", r)
+}
+
+func isPrintable(s string) bool {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if (r == utf8.RuneError && size == 1) || !unicode.IsPrint(r) {
+			return false
+		}
+		i += size
+	}
+	return true
+}
+
+// escapeC - escapes the backslashes, the quote and the characters that
+// are not printable of s with C escape sequences. Bytes are written in
+// octal, unlike \x its digits never run into the following characters.
+func escapeC(s string, quote byte) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			fmt.Fprintf(&b, `\%03o`, s[i])
+		case r == '\\' || r == rune(quote):
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\a':
+			b.WriteString(`\a`)
+		case r == '\b':
+			b.WriteString(`\b`)
+		case r == '\f':
+			b.WriteString(`\f`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\v':
+			b.WriteString(`\v`)
+		case !unicode.IsPrint(r):
+			for _, c := range []byte(s[i : i+size]) {
+				fmt.Fprintf(&b, `\%03o`, c)
+			}
+		default:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
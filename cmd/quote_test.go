@@ -0,0 +1,79 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestQuoteName(t *testing.T) {
+	testCases := []struct {
+		name  string
+		shell string
+		c     string
+	}{
+		{"dir/object.txt", "dir/object.txt", `"dir/object.txt"`},
+		{"", "''", `""`},
+		{"my report.txt", "'my report.txt'", `"my report.txt"`},
+		{"it's", `'it'\''s'`, `"it's"`},
+		{`say "hi"`, `'say "hi"'`, `"say \"hi\""`},
+		{"$HOME*", "'$HOME*'", `"$HOME*"`},
+		{"line\nbreak", `$'line\nbreak'`, `"line\nbreak"`},
+		{"tab\t\\", `$'tab\t\\'`, `"tab\t\\"`},
+		{"bell\x07'1", `$'bell\a\'1'`, `"bell\a'1"`},
+		{"esc\x1b1", `$'esc\0331'`, `"esc\0331"`},
+		{"bad\xffutf8", `$'bad\377utf8'`, `"bad\377utf8"`},
+		{"日本語.txt", "日本語.txt", `"日本語.txt"`},
+	}
+	for i, testCase := range testCases {
+		if got := shellQuoteName(testCase.name); got != testCase.shell {
+			t.Errorf("Test %d: expected shell quoting %s, got %s", i+1, testCase.shell, got)
+		}
+		if got := cQuoteName(testCase.name); got != testCase.c {
+			t.Errorf("Test %d: expected C quoting %s, got %s", i+1, testCase.c, got)
+		}
+	}
+
+	// Names quoted for the shell are read back as is.
+	bash, e := exec.LookPath("bash")
+	if e != nil {
+		return
+	}
+	for i, testCase := range testCases {
+		out, e := exec.Command(bash, "-c", "printf %s "+testCase.shell).Output()
+		if e != nil {
+			t.Fatal(e)
+		}
+		if string(out) != testCase.name {
+			t.Errorf("Test %d: expected %q read back, got %q", i+1, testCase.name, out)
+		}
+	}
+}
+
+func TestParseQuoteStyle(t *testing.T) {
+	for style, expected := range map[string]string{"": quoteNone, "none": quoteNone, "Shell": quoteShell, "c": quoteC} {
+		got, e := parseQuoteStyle(style)
+		if e != nil || got != expected {
+			t.Errorf("%q: expected %s, got %s (%v)", style, expected, got, e)
+		}
+	}
+	if _, e := parseQuoteStyle("json"); e == nil {
+		t.Error("expected an error for an unknown quoting style")
+	}
+}
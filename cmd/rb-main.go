@@ -200,7 +200,7 @@ func deleteBucket(ctx context.Context, url string, isForce bool) *probe.Error {
 		}
 	}
 	// Return early if prefix delete
-	switch c := clnt.(type) {
+	switch c := unwrapClient(clnt).(type) {
 	case *S3Client:
 		_, object := c.url2BucketAndObject()
 		if object != "" && isForce {
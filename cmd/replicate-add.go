@@ -201,7 +201,7 @@ func extractCredentialURL(argURL string) (accessKey, secretKey string, u *url.UR
 		// get alias config by alias url
 		alias, parsedURL, aliasCfg = mustExpandAlias(argURL)
 		if aliasCfg == nil {
-			fatalIf(errInvalidAliasedURL(alias).Trace(argURL), "No such alias `"+alias+"` found.")
+			fatalIf(errInvalidAliasedURL(alias).Trace(argURL), "No such alias `"+alias+"` found."+aliasSuggestion(alias))
 			return
 		}
 		accessKey, secretKey = aliasCfg.AccessKey, aliasCfg.SecretKey
@@ -289,7 +289,7 @@ func mainReplicateAdd(cliCtx *cli.Context) error {
 	fatalIf(err, "unable to initialize connection.")
 
 	var sourceBucket string
-	switch c := client.(type) {
+	switch c := unwrapClient(client).(type) {
 	case *S3Client:
 		sourceBucket, _ = c.url2BucketAndObject()
 	default:
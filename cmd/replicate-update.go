@@ -295,7 +295,7 @@ func mainReplicateUpdate(cliCtx *cli.Context) error {
 		}
 	}
 	var sourceBucket string
-	switch c := client.(type) {
+	switch c := unwrapClient(client).(type) {
 	case *S3Client:
 		sourceBucket, _ = c.url2BucketAndObject()
 	default:
@@ -0,0 +1,95 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"html/template"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// htmlReport - a standalone HTML page of tables, e.g. to be attached to
+// change tickets.
+type htmlReport struct {
+	Title    string
+	Subtitle string
+	Created  time.Time
+	Sections []htmlReportSection
+}
+
+// htmlReportSection - a table of a report, with a bar after each row
+// when Bars is set, of a width from 0 to 1.
+type htmlReportSection struct {
+	Title   string
+	Columns []string
+	Rows    [][]string
+	Bars    []float64
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent": func(v float64) string {
+		return strconv.FormatFloat(100*v, 'f', 1, 64)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.6em; margin-bottom: 0.2em; }
+h2 { font-size: 1.2em; margin-top: 1.8em; }
+p.subtitle { color: #666; margin-top: 0; }
+table { border-collapse: collapse; min-width: 40em; }
+th, td { padding: 0.3em 0.8em; border-bottom: 1px solid #ddd; text-align: left; }
+th { background: #f4f4f4; }
+td.bar { width: 15em; }
+td.bar span { display: block; height: 0.8em; background: #c72c48; }
+footer { margin-top: 2em; color: #888; font-size: 0.8em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Subtitle}}<p class="subtitle">{{.Subtitle}}</p>{{end}}
+{{range .Sections}}
+<h2>{{.Title}}</h2>
+{{if .Rows}}<table>
+<tr>{{range .Columns}}<th>{{.}}</th>{{end}}{{if .Bars}}<th></th>{{end}}</tr>
+{{$bars := .Bars}}{{range $i, $row := .Rows}}<tr>{{range $row}}<td>{{.}}</td>{{end}}{{if $bars}}<td class="bar"><span style="width: {{percent (index $bars $i)}}%"></span></td>{{end}}</tr>
+{{end}}</table>{{else}}<p>None.</p>{{end}}
+{{end}}
+<footer>Generated by mc on {{.Created.Format "2006-01-02 15:04:05 MST"}}.</footer>
+</body>
+</html>
+`))
+
+// write - saves the report in the file at path.
+func (r htmlReport) write(path string) *probe.Error {
+	var buf bytes.Buffer
+	if e := htmlReportTemplate.Execute(&buf, r); e != nil {
+		return probe.NewError(e)
+	}
+	if e := os.WriteFile(path, buf.Bytes(), 0o644); e != nil {
+		return probe.NewError(e).Trace(path)
+	}
+	return nil
+}
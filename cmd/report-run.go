@@ -0,0 +1,169 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var reportHTMLFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "report-html",
+		Usage: "save the summary, the failures and the statistics per prefix of the run in a standalone HTML page",
+	},
+}
+
+// Prefix of the objects at the top of the compared folders.
+const runReportTopLevel = "(top level)"
+
+// runReport - accumulates the objects of a diff or a mirror run by kind and
+// by prefix, and its failures, saved as an HTML page with --report-html.
+type runReport struct {
+	mu       sync.Mutex
+	title    string
+	started  time.Time
+	kinds    []string
+	objects  []int64
+	sizes    []int64
+	prefixes map[string][]int64
+	failures [][]string
+}
+
+// newRunReport - returns the report of a run with the objects counted by
+// kinds, e.g. copied and removed.
+func newRunReport(title string, kinds ...string) *runReport {
+	return &runReport{
+		title:    title,
+		started:  time.Now(),
+		kinds:    kinds,
+		objects:  make([]int64, len(kinds)),
+		sizes:    make([]int64, len(kinds)),
+		prefixes: make(map[string][]int64),
+	}
+}
+
+// runReportPrefix - returns the first folder of the slash separated name.
+func runReportPrefix(name string) string {
+	if i := strings.Index(name, "/"); i >= 0 {
+		return name[:i+1]
+	}
+	return runReportTopLevel
+}
+
+// add - counts the object of the slash separated name, relative to the
+// source or the target, in the kind at index kind.
+func (r *runReport) add(name string, kind int, size int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.objects[kind]++
+	r.sizes[kind] += size
+	prefix := runReportPrefix(name)
+	counts, ok := r.prefixes[prefix]
+	if !ok {
+		counts = make([]int64, len(r.kinds))
+		r.prefixes[prefix] = counts
+	}
+	counts[kind]++
+}
+
+// addFailure - lists the failure of the object at the first of urlStrs
+// not empty, if any.
+func (r *runReport) addFailure(err *probe.Error, urlStrs ...string) {
+	urlStr := "-"
+	for _, u := range urlStrs {
+		if u != "" {
+			urlStr = u
+			break
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures = append(r.failures, []string{urlStr, err.ToGoError().Error()})
+}
+
+// contentSize - returns the size of the first content not nil.
+func contentSize(contents ...*ClientContent) int64 {
+	for _, c := range contents {
+		if c != nil {
+			return c.Size
+		}
+	}
+	return 0
+}
+
+// htmlReport - returns the report as an HTML page, for a run ended at created.
+func (r *runReport) htmlReport(created time.Time) htmlReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var total int64
+	summary := htmlReportSection{Title: "Summary", Columns: []string{"", "Objects", "Size"}}
+	for i, kind := range r.kinds {
+		summary.Rows = append(summary.Rows, []string{kind, humanize.Comma(r.objects[i]), humanize.IBytes(uint64(r.sizes[i]))})
+		total += r.objects[i]
+	}
+	summary.Rows = append(summary.Rows, []string{"Failures", humanize.Comma(int64(len(r.failures))), ""})
+
+	failures := htmlReportSection{Title: "Failures", Columns: []string{"Object", "Error"}, Rows: r.failures}
+
+	names := make([]string, 0, len(r.prefixes))
+	sums := make(map[string]int64, len(r.prefixes))
+	for name, counts := range r.prefixes {
+		names = append(names, name)
+		for _, n := range counts {
+			sums[name] += n
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if sums[names[i]] != sums[names[j]] {
+			return sums[names[i]] > sums[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	prefixes := htmlReportSection{Title: "Prefixes", Columns: append(append([]string{"Prefix"}, r.kinds...), "Share")}
+	for _, name := range names {
+		row := []string{name}
+		for _, n := range r.prefixes[name] {
+			row = append(row, humanize.Comma(n))
+		}
+		share := percentOf(sums[name], total)
+		prefixes.Rows = append(prefixes.Rows, append(row, fmt.Sprintf("%.1f%%", share)))
+		prefixes.Bars = append(prefixes.Bars, share/100)
+	}
+
+	return htmlReport{
+		Title: r.title,
+		Subtitle: fmt.Sprintf("Started on %s, ended after %s.", r.started.Format("2006-01-02 15:04:05 MST"),
+			created.Sub(r.started).Round(time.Second)),
+		Created:  created,
+		Sections: []htmlReportSection{summary, failures, prefixes},
+	}
+}
+
+// write - saves the report in the file at path.
+func (r *runReport) write(path string) *probe.Error {
+	return r.htmlReport(time.Now()).write(path)
+}
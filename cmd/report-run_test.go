@@ -0,0 +1,75 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestRunReport(t *testing.T) {
+	r := newRunReport("Mirror of src to dst", "Copied", "Removed")
+	r.add("a/x", 0, 10)
+	r.add("a/b/y", 0, 20)
+	r.add("t", 0, 5)
+	r.add("old/q", 1, 1)
+	r.add("a/z", 1, 2)
+	r.addFailure(probe.NewError(errors.New("access denied")), "", "/src/b/w")
+	r.addFailure(probe.NewError(errors.New("bucket not found")))
+
+	report := r.htmlReport(r.started.Add(90 * time.Second))
+	if report.Title != "Mirror of src to dst" {
+		t.Fatalf("unexpected title %q", report.Title)
+	}
+	if len(report.Sections) != 3 {
+		t.Fatalf("expected 3 sections, got %d", len(report.Sections))
+	}
+
+	summary := [][]string{
+		{"Copied", "3", "35 B"},
+		{"Removed", "2", "3 B"},
+		{"Failures", "2", ""},
+	}
+	if !reflect.DeepEqual(report.Sections[0].Rows, summary) {
+		t.Fatalf("expected summary %v, got %v", summary, report.Sections[0].Rows)
+	}
+
+	failures := [][]string{
+		{"/src/b/w", "access denied"},
+		{"-", "bucket not found"},
+	}
+	if !reflect.DeepEqual(report.Sections[1].Rows, failures) {
+		t.Fatalf("expected failures %v, got %v", failures, report.Sections[1].Rows)
+	}
+
+	prefixes := [][]string{
+		{"a/", "2", "1", "60.0%"},
+		{runReportTopLevel, "1", "0", "20.0%"},
+		{"old/", "0", "1", "20.0%"},
+	}
+	if !reflect.DeepEqual(report.Sections[2].Rows, prefixes) {
+		t.Fatalf("expected prefixes %v, got %v", prefixes, report.Sections[2].Rows)
+	}
+	if bars := []float64{0.6, 0.2, 0.2}; !reflect.DeepEqual(report.Sections[2].Bars, bars) {
+		t.Fatalf("expected bars %v, got %v", bars, report.Sections[2].Bars)
+	}
+}
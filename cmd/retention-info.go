@@ -307,7 +307,7 @@ func getRetention(ctx context.Context, target, versionID string, timeRef time.Ti
 	}
 
 	// Quit early if urlStr does not point to an S3 server
-	switch clnt.(type) {
+	switch unwrapClient(clnt).(type) {
 	case *S3Client:
 	default:
 		fatal(errDummy().Trace(), "Retention is supported only for S3 servers.")
@@ -25,9 +25,11 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/colorjson"
@@ -88,6 +90,10 @@ var (
 			Name:  "newer-than",
 			Usage: "remove objects newer than value in duration string (e.g. 7d10h31s)",
 		},
+		cli.StringSliceFlag{
+			Name:  "tags",
+			Usage: "remove only objects with tags matching RE2 regex pattern. Specify each with key=regex. MinIO server only.",
+		},
 		cli.BoolFlag{
 			Name:  "bypass",
 			Usage: "bypass governance",
@@ -167,6 +173,9 @@ EXAMPLES:
   14. Perform a fake removal of object(s) versions that are non-current and older than 10 days. If top-level version is a delete 
   marker, this will also be deleted when --non-current flag is specified.
       {{.Prompt}} {{.HelpName}} s3/docs/ --recursive --force --versions --non-current --older-than 10d --dry-run
+
+  15. Remove all objects recursively from bucket 'jazz-songs' tagged with 'retention=temporary'.
+      {{.Prompt}} {{.HelpName}} --recursive --force --tags "retention=^temporary$" s3/jazz-songs/
 `,
 }
 
@@ -191,7 +200,11 @@ func (r rmMessage) String() string {
 		msg = "Created delete marker "
 	}
 
-	msg += console.Colorize("Removed", fmt.Sprintf("`%s`", r.Key))
+	key := fmt.Sprintf("`%s`", r.Key)
+	if globalQuote != quoteNone {
+		key = quoteName(r.Key)
+	}
+	msg += console.Colorize("Removed", key)
 	if r.VersionID != "" {
 		msg += fmt.Sprintf(" (versionId=%s)", r.VersionID)
 		if r.ModTime != nil {
@@ -244,6 +257,11 @@ func checkRmSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[string
 			"You cannot specify --purge with --recursive.")
 	}
 
+	if cliCtx.IsSet("tags") && !(isRecursive || isVersions) {
+		fatalIf(errDummy().Trace(),
+			"You cannot specify --tags without --recursive or --versions.")
+	}
+
 	if isForceDel && (isNoncurrentVersion || isVersions || cliCtx.IsSet("older-than") || cliCtx.IsSet("newer-than") || versionID != "") {
 		fatalIf(errDummy().Trace(),
 			"You cannot specify --purge flag with any flag(s) other than --force.")
@@ -351,7 +369,7 @@ func removeSingle(url, versionID string, opts removeOpts) error {
 		}
 
 		if opts.isFake {
-			printDryRunMsg(targetAlias, content, opts.withVersions)
+			printDryRunMsg(opts.summary, targetAlias, content, opts.withVersions)
 			return nil
 		}
 	}
@@ -407,13 +425,46 @@ type removeOpts struct {
 	isForceDel        bool
 	olderThan         string
 	newerThan         string
+	tags              map[string]*regexp.Regexp
 	encKeyDB          map[string][]prefixSSEPair
+	// Totals of the objects of a dry run, shared by all the targets.
+	summary *rmDryRunSummaryMessage
+}
+
+// rmDryRunSummaryMessage - summary of objects that would be removed by a dry run.
+type rmDryRunSummaryMessage struct {
+	Status       string `json:"status"`
+	DryRun       bool   `json:"dryRun"`
+	TotalObjects int64  `json:"totalObjects"`
+	TotalSize    int64  `json:"totalSize"`
+}
+
+// Colorized message for console printing.
+func (r rmDryRunSummaryMessage) String() string {
+	return fmt.Sprintf("DRYRUN: %s would be removed (%s).",
+		console.Colorize("Removed", fmt.Sprintf("%d object(s)", r.TotalObjects)),
+		humanize.IBytes(uint64(r.TotalSize)))
+}
+
+// JSON'ified message for scripting.
+func (r rmDryRunSummaryMessage) JSON() string {
+	r.Status = "success"
+	r.DryRun = true
+	msgBytes, e := json.MarshalIndent(r, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
 }
 
-func printDryRunMsg(targetAlias string, content *ClientContent, printModTime bool) {
+// printDryRunMsg - reports an object a dry run would remove, adding it
+// to the totals of summary.
+func printDryRunMsg(summary *rmDryRunSummaryMessage, targetAlias string, content *ClientContent, printModTime bool) {
 	if content == nil {
 		return
 	}
+	if !content.Type.IsDir() && summary != nil {
+		summary.TotalObjects++
+		summary.TotalSize += content.Size
+	}
 	msg := rmMessage{
 		Status:    "success",
 		DryRun:    true,
@@ -444,7 +495,7 @@ func listAndRemove(url string, opts removeOpts) error {
 	contentCh := make(chan *ClientContent)
 	isRemoveBucket := false
 
-	listOpts := ListOptions{Recursive: opts.isRecursive, Incomplete: opts.isIncomplete, ShowDir: DirLast}
+	listOpts := ListOptions{Recursive: opts.isRecursive, Incomplete: opts.isIncomplete, ShowDir: DirLast, WithMetadata: len(opts.tags) > 0}
 	if !opts.timeRef.IsZero() {
 		listOpts.WithOlderVersions = opts.withVersions
 		listOpts.WithDeleteMarkers = true
@@ -483,6 +534,11 @@ func listAndRemove(url string, opts removeOpts) error {
 			}
 		}
 
+		// Skip objects not matching --tags, if specified
+		if !content.Time.IsZero() && !matchContentTags(opts.tags, content) {
+			continue
+		}
+
 		if opts.nonCurrentVersion && opts.isRecursive && opts.withVersions {
 			if lastPath != content.URL.Path {
 				lastPath = content.URL.Path
@@ -506,7 +562,7 @@ func listAndRemove(url string, opts removeOpts) error {
 					}
 
 					if opts.isFake {
-						printDryRunMsg(targetAlias, content, true)
+						printDryRunMsg(opts.summary, targetAlias, content, true)
 						continue
 					}
 
@@ -602,7 +658,7 @@ func listAndRemove(url string, opts removeOpts) error {
 				}
 			}
 		} else {
-			printDryRunMsg(targetAlias, content, opts.withVersions)
+			printDryRunMsg(opts.summary, targetAlias, content, opts.withVersions)
 		}
 	}
 
@@ -627,7 +683,7 @@ func listAndRemove(url string, opts removeOpts) error {
 			}
 
 			if opts.isFake {
-				printDryRunMsg(targetAlias, content, true)
+				printDryRunMsg(opts.summary, targetAlias, content, true)
 				continue
 			}
 
@@ -717,7 +773,7 @@ func mainRm(cliCtx *cli.Context) error {
 	// rm specific flags.
 	isIncomplete := cliCtx.Bool("incomplete")
 	isRecursive := cliCtx.Bool("recursive")
-	isFake := cliCtx.Bool("dry-run") || cliCtx.Bool("fake")
+	isFake := isDryRun(cliCtx) || cliCtx.Bool("fake")
 	isStdin := cliCtx.Bool("stdin")
 	isBypass := cliCtx.Bool("bypass")
 	olderThan := cliCtx.String("older-than")
@@ -728,6 +784,7 @@ func mainRm(cliCtx *cli.Context) error {
 	withVersions := cliCtx.Bool("versions")
 	versionID := cliCtx.String("version-id")
 	rewind := parseRewindFlag(cliCtx.String("rewind"))
+	tags := getRegexMap(cliCtx, "tags")
 
 	if withVersions && rewind.IsZero() {
 		rewind = time.Now().UTC()
@@ -736,6 +793,7 @@ func mainRm(cliCtx *cli.Context) error {
 	// Set color.
 	console.SetColor("Removed", color.New(color.FgGreen, color.Bold))
 
+	summary := &rmDryRunSummaryMessage{}
 	var rerr error
 	var e error
 	// Support multiple targets.
@@ -752,7 +810,9 @@ func mainRm(cliCtx *cli.Context) error {
 				isBypass:          isBypass,
 				olderThan:         olderThan,
 				newerThan:         newerThan,
+				tags:              tags,
 				encKeyDB:          encKeyDB,
+				summary:           summary,
 			})
 		} else {
 			e = removeSingle(url, versionID, removeOpts{
@@ -764,6 +824,7 @@ func mainRm(cliCtx *cli.Context) error {
 				olderThan:    olderThan,
 				newerThan:    newerThan,
 				encKeyDB:     encKeyDB,
+				summary:      summary,
 			})
 		}
 		if rerr == nil {
@@ -772,6 +833,9 @@ func mainRm(cliCtx *cli.Context) error {
 	}
 
 	if !isStdin {
+		if isFake {
+			printMsg(*summary)
+		}
 		return rerr
 	}
 
@@ -790,7 +854,9 @@ func mainRm(cliCtx *cli.Context) error {
 				isBypass:          isBypass,
 				olderThan:         olderThan,
 				newerThan:         newerThan,
+				tags:              tags,
 				encKeyDB:          encKeyDB,
+				summary:           summary,
 			})
 		} else {
 			e = removeSingle(url, versionID, removeOpts{
@@ -802,6 +868,7 @@ func mainRm(cliCtx *cli.Context) error {
 				olderThan:    olderThan,
 				newerThan:    newerThan,
 				encKeyDB:     encKeyDB,
+				summary:      summary,
 			})
 		}
 		if rerr == nil {
@@ -809,5 +876,8 @@ func mainRm(cliCtx *cli.Context) error {
 		}
 	}
 
+	if isFake {
+		printMsg(*summary)
+	}
 	return rerr
 }
@@ -0,0 +1,63 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRmDryRunSummary(t *testing.T) {
+	useTestMcConfig(t)
+	root := t.TempDir()
+	for name, size := range map[string]int{"a": 3, "dir/b": 5, "dir/c": 7} {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if e := os.MkdirAll(filepath.Dir(p), 0o755); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(p, make([]byte, size), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	// The totals of every target of the command add up.
+	summary := &rmDryRunSummaryMessage{}
+	if e := listAndRemove(filepath.Join(root, "dir"), removeOpts{isRecursive: true, isFake: true, summary: summary}); e != nil {
+		t.Fatal(e)
+	}
+	if e := removeSingle(filepath.Join(root, "a"), "", removeOpts{isFake: true, summary: summary}); e != nil {
+		t.Fatal(e)
+	}
+	if summary.TotalObjects != 3 || summary.TotalSize != 15 {
+		t.Errorf("expected 3 objects of 15 bytes, got %d objects of %d bytes", summary.TotalObjects, summary.TotalSize)
+	}
+
+	// Another run starts from zero.
+	summary = &rmDryRunSummaryMessage{}
+	if e := removeSingle(filepath.Join(root, "a"), "", removeOpts{isFake: true, summary: summary}); e != nil {
+		t.Fatal(e)
+	}
+	if summary.TotalObjects != 1 || summary.TotalSize != 3 {
+		t.Errorf("expected 1 object of 3 bytes, got %d objects of %d bytes", summary.TotalObjects, summary.TotalSize)
+	}
+
+	if _, e := os.Stat(filepath.Join(root, "dir", "b")); e != nil {
+		t.Errorf("expected the dry run to keep the objects, got %v", e)
+	}
+}
@@ -19,24 +19,15 @@ package cmd
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/dustin/go-humanize"
+	"github.com/mattn/go-runewidth"
 	"github.com/minio/pkg/v2/console"
 )
 
 // fixateScanBar truncates or stretches text to fit within the terminal size.
 func fixateScanBar(text string, width int) string {
-	if len([]rune(text)) > width {
-		// Trim text to fit within the screen
-		trimSize := len([]rune(text)) - width + 3 //"..."
-		if trimSize < len([]rune(text)) {
-			text = "..." + text[trimSize:]
-		}
-	} else {
-		text += strings.Repeat(" ", width-len([]rune(text)))
-	}
-	return text
+	return fitDisplayWidth(text, width)
 }
 
 // Progress bar function report objects being scaned.
@@ -46,11 +37,10 @@ type scanBarFunc func(string)
 func scanBarFactory() scanBarFunc {
 	fileCount := 0
 
-	// Cursor animate channel.
-	cursorCh := cursorAnimate()
+	cursor := newCursorAnimation()
 	return func(source string) {
-		scanPrefix := fmt.Sprintf("[%s] %s ", humanize.Comma(int64(fileCount)), <-cursorCh)
-		source = fixateScanBar(source, globalTermWidth-len([]rune(scanPrefix)))
+		scanPrefix := fmt.Sprintf("[%s] %s ", humanize.Comma(int64(fileCount)), cursor.next())
+		source = fixateScanBar(source, globalTermWidth-runewidth.StringWidth(scanPrefix))
 		barText := scanPrefix + source
 		console.PrintC("\r" + barText + "\r")
 		fileCount++
@@ -0,0 +1,194 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strings"
+
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+// Only the beginning of the files is scanned for secrets, files with a
+// NUL byte in their first block are taken for binaries and not scanned.
+const (
+	secretsScanLimit = 4 << 20
+	binaryProbeSize  = 8000
+)
+
+// Secrets of values assigned to variables named like secrets are found
+// by the entropy of the values, in bits per character.
+const secretMinEntropy = 3.8
+
+// secretRule - a kind of secret and the pattern finding it.
+type secretRule struct {
+	name    string
+	pattern *regexp.Regexp
+	// The submatch of the secret value, which must be random enough when
+	// not zero.
+	entropyGroup int
+	// Only reported when no other rule matches the line.
+	fallback bool
+}
+
+var secretRules = []secretRule{
+	{name: "AWS access key ID", pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[0-9A-Z]{16}\b`)},
+	{name: "AWS secret access key", pattern: regexp.MustCompile(`(?i)(?:aws_?secret_?access_?key|aws_?secret_?key|secret_?access_?key)["']?\s*[:=]\s*["']?([A-Za-z0-9/+]{40})\b`), entropyGroup: 1},
+	{name: "private key", pattern: regexp.MustCompile(`-----BEGIN (?:RSA |DSA |EC |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----`)},
+	{name: "GitHub token", pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{82})\b`)},
+	{name: "Slack token", pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{name: "Google API key", pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{name: "Stripe secret key", pattern: regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{24,}\b`)},
+	{name: "JSON web token", pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{name: "secret assignment", pattern: regexp.MustCompile(`(?i)(?:secret|token|passw(?:or)?d|api_?key|access_?key)["']?\s*[:=]\s*["']?([A-Za-z0-9/+_.=-]{20,})`), entropyGroup: 1, fallback: true},
+}
+
+// secretFinding - a secret found in a file, without its value.
+type secretFinding struct {
+	Rule string `json:"rule"`
+	Line int    `json:"line"`
+}
+
+func (f secretFinding) String() string {
+	return fmt.Sprintf("%s on line %d", f.Rule, f.Line)
+}
+
+// shannonEntropy - returns the entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := map[rune]int{}
+	for _, r := range s {
+		counts[r]++
+	}
+	var entropy float64
+	n := float64(len([]rune(s)))
+	for _, count := range counts {
+		p := float64(count) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// scanLineSecrets - returns the rules matching line, a single finding per
+// rule.
+func scanLineSecrets(line string) (rules []string) {
+	for _, rule := range secretRules {
+		if rule.fallback && len(rules) > 0 {
+			continue
+		}
+		if rule.entropyGroup == 0 {
+			if rule.pattern.MatchString(line) {
+				rules = append(rules, rule.name)
+			}
+			continue
+		}
+		for _, m := range rule.pattern.FindAllStringSubmatch(line, -1) {
+			if shannonEntropy(m[rule.entropyGroup]) >= secretMinEntropy {
+				rules = append(rules, rule.name)
+				break
+			}
+		}
+	}
+	return rules
+}
+
+// scanSecrets - returns the secrets found in the text read from r.
+func scanSecrets(r io.Reader) ([]secretFinding, error) {
+	br := bufio.NewReader(io.LimitReader(r, secretsScanLimit))
+	head, e := br.Peek(binaryProbeSize)
+	if e != nil && e != io.EOF && e != bufio.ErrBufferFull {
+		return nil, e
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil, nil
+	}
+
+	var findings []secretFinding
+	scanner := bufio.NewScanner(br)
+	scanner.Buffer(make([]byte, 64<<10), secretsScanLimit)
+	for line := 1; scanner.Scan(); line++ {
+		for _, rule := range scanLineSecrets(scanner.Text()) {
+			findings = append(findings, secretFinding{Rule: rule, Line: line})
+		}
+	}
+	return findings, scanner.Err()
+}
+
+// scanFileSecrets - returns the secrets found in the local file.
+func scanFileSecrets(path string) ([]secretFinding, *probe.Error) {
+	f, e := os.Open(path)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	defer f.Close()
+	findings, e := scanSecrets(f)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(path)
+	}
+	return findings, nil
+}
+
+func joinFindings(findings []secretFinding) string {
+	descriptions := make([]string, len(findings))
+	for i, f := range findings {
+		descriptions[i] = f.String()
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// SecretsFound - the upload of a file was blocked by --block-secrets.
+type SecretsFound struct {
+	Path     string
+	Findings []secretFinding
+}
+
+func (e SecretsFound) Error() string {
+	return fmt.Sprintf("`%s` contains secrets: %s. Remove them, or copy without --block-secrets.", e.Path, joinFindings(e.Findings))
+}
+
+// secretsMessage - warns of the secrets found in an uploaded file.
+type secretsMessage struct {
+	Status   string          `json:"status"`
+	Source   string          `json:"source"`
+	Target   string          `json:"target"`
+	Findings []secretFinding `json:"findings"`
+}
+
+// String colorized secrets message
+func (s secretsMessage) String() string {
+	return console.Colorize("SecretsWarning", fmt.Sprintf("Secrets found in `%s`, uploaded to `%s`: %s.",
+		s.Source, s.Target, joinFindings(s.Findings)))
+}
+
+// JSON jsonified secrets message
+func (s secretsMessage) JSON() string {
+	s.Status = "warning"
+	msgBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
@@ -237,6 +237,11 @@ func (s *sessionV8) Save() *probe.Error {
 	if err != nil {
 		return err.Trace(s.SessionID)
 	}
+	unlock, err := lockStateFile(sessionFile)
+	if err != nil {
+		return err.Trace(s.SessionID)
+	}
+	defer unlock()
 	e = qs.Save(sessionFile)
 	if e != nil {
 		return probe.NewError(e).Trace(sessionFile)
@@ -290,6 +295,11 @@ func (s *sessionV8) save() *probe.Error {
 	if err != nil {
 		return err.Trace(s.SessionID)
 	}
+	unlock, err := lockStateFile(sessionFile)
+	if err != nil {
+		return err.Trace(s.SessionID)
+	}
+	defer unlock()
 
 	// Verify if sessionFile is modified.
 	modified, err := s.isModified(sessionFile)
@@ -54,12 +54,12 @@ func createSessionDir() *probe.Error {
 
 // getSessionDir - get session directory.
 func getSessionDir() (string, *probe.Error) {
-	configDir, err := getMcConfigDir()
+	stateDir, err := getMcStateDir()
 	if err != nil {
 		return "", err.Trace()
 	}
 
-	sessionDir := filepath.Join(configDir, globalSessionDir)
+	sessionDir := filepath.Join(stateDir, globalSessionDir)
 	return sessionDir, nil
 }
 
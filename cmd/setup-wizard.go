@@ -0,0 +1,172 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/env"
+	"golang.org/x/term"
+)
+
+// mcEnvSetupWizard - set to `off` to never start the setup wizard.
+const mcEnvSetupWizard = "MC_SETUP_WIZARD"
+
+// wantSetupWizard - returns true when the first run of mc, without any
+// config yet, runs interactively a command needing an alias.
+func wantSetupWizard(ctx *cli.Context) bool {
+	if globalJSON || globalQuiet || !isTerminal() || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false
+	}
+	if strings.EqualFold(env.Get(mcEnvSetupWizard, ""), "off") || len(env.List(mcEnvHostPrefix)) > 0 {
+		return false
+	}
+	switch ctx.Args().First() {
+	case "", "alias", "config", "help", "version", "update":
+		return false
+	}
+	return true
+}
+
+// prompt - asks questions on a terminal.
+type prompt struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// setupWizard - adds the first alias, asking for its URL and keys.
+type setupWizard struct {
+	prompt
+	readSecret func() (string, error)
+	trustCert  func(ctx context.Context, url, alias string) (*x509.Certificate, *probe.Error)
+}
+
+func runSetupWizard(ctx context.Context) {
+	w := setupWizard{
+		prompt: prompt{in: bufio.NewReader(os.Stdin), out: os.Stdout},
+		readSecret: func() (string, error) {
+			secret, e := term.ReadPassword(int(os.Stdin.Fd()))
+			return string(secret), e
+		},
+		trustCert: promptTrustSelfSignedCert,
+	}
+	w.run(ctx)
+}
+
+// ask - returns the answer to question, def when empty.
+func (w prompt) ask(question, def string) string {
+	if def != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+	answer, _ := w.in.ReadString('\n')
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return def
+	}
+	return answer
+}
+
+// require - asks question until answered, fails at the end of the input.
+func (w prompt) require(question string) string {
+	for {
+		fmt.Fprintf(w.out, "%s: ", question)
+		answer, e := w.in.ReadString('\n')
+		if answer = strings.TrimSpace(answer); answer != "" {
+			return answer
+		}
+		fatalIf(probe.NewError(e), "Unable to read the answer.")
+	}
+}
+
+func (w prompt) confirm(question string, def bool) bool {
+	choices := "y/N"
+	if def {
+		choices = "Y/n"
+	}
+	switch strings.ToLower(w.ask(question+" "+choices, "")) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	}
+	return def
+}
+
+func (w setupWizard) run(ctx context.Context) {
+	fmt.Fprintf(w.out, "No alias is configured yet, set %s=off to skip this setup.\n", mcEnvSetupWizard)
+	if !w.confirm("Add an alias now?", true) {
+		return
+	}
+
+	alias := w.ask("Alias", "myminio")
+	for !isValidAlias(alias) {
+		fmt.Fprintln(w.out, errInvalidAlias(alias).ToGoError())
+		alias = w.ask("Alias", "myminio")
+	}
+	url := trimTrailingSeparator(w.ask("URL", "http://localhost:9000"))
+	for !isValidHostURL(url) {
+		fmt.Fprintln(w.out, errInvalidURL(url).ToGoError())
+		url = trimTrailingSeparator(w.ask("URL", "http://localhost:9000"))
+	}
+	accessKey := w.ask("Access Key", "")
+	fmt.Fprint(w.out, "Secret Key: ")
+	secretKey, e := w.readSecret()
+	fmt.Fprintln(w.out)
+	if e != nil {
+		fatalIf(probe.NewError(e), "Unable to read the secret key.")
+	}
+
+	// Test the connection, probing the signature as `mc alias set` does.
+	api := "S3v4"
+	peerCert, err := w.trustCert(ctx, url, alias)
+	if err == nil {
+		var s3Config *Config
+		if s3Config, err = BuildS3Config(ctx, alias, url, accessKey, secretKey, "", "auto", "", peerCert); err == nil {
+			api = normalizeAPI(s3Config.Signature)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(w.out, "Unable to connect to `%s`: %s\n", url, err.ToGoError())
+		if !w.confirm("Add the alias anyway?", false) {
+			return
+		}
+	}
+
+	setAlias(alias, aliasConfigV10{
+		URL:       url,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		API:       api,
+		Path:      "auto",
+	})
+	fmt.Fprintf(w.out, "Added `%s` successfully, try `mc ls %s`.\n", alias, alias)
+
+	if runtime.GOOS != "windows" && w.confirm("Enable shell completion?", false) {
+		installAutoCompletion()
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/x509"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func newTestSetupWizard(input string) setupWizard {
+	return setupWizard{
+		prompt:     prompt{in: bufio.NewReader(strings.NewReader(input)), out: io.Discard},
+		readSecret: func() (string, error) { return "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF", nil },
+		trustCert: func(context.Context, string, string) (*x509.Certificate, *probe.Error) {
+			return nil, nil
+		},
+	}
+}
+
+func TestSetupWizard(t *testing.T) {
+	useTestMcConfig(t)
+	server := httptest.NewServer(&v2OnlyHandler{})
+	defer server.Close()
+
+	// Declined.
+	newTestSetupWizard("n\n").run(context.Background())
+	// Invalid alias and URL asked again, the server only speaks V2.
+	newTestSetupWizard("\n1bad\nmyv2\nlocalhost\n" + server.URL + "/\nWLGDGYAQYIGI833EV05A\nn\n").run(context.Background())
+	// Unreachable, not added.
+	newTestSetupWizard("y\ndown\nhttp://127.0.0.1:1\nWLGDGYAQYIGI833EV05A\n\n").run(context.Background())
+
+	config, err := loadMcConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := config.Aliases["down"]; ok {
+		t.Fatal("expected the unreachable alias not to be added")
+	}
+	alias, ok := config.Aliases["myv2"]
+	if !ok || alias.URL != server.URL || alias.AccessKey != "WLGDGYAQYIGI833EV05A" || alias.API != "S3v2" || alias.Path != "auto" {
+		t.Fatalf("unexpected alias %+v", alias)
+	}
+}
@@ -124,6 +124,11 @@ func (s shareDBV1) save(filename string) *probe.Error {
 	if e != nil {
 		return probe.NewError(e).Trace(filename)
 	}
+	unlock, err := lockStateFile(filename)
+	if err != nil {
+		return err.Trace(filename)
+	}
+	defer unlock()
 	if e := qs.Save(filename); e != nil {
 		return probe.NewError(e).Trace(filename)
 	}
@@ -191,6 +191,7 @@ func doShareDownloadURL(ctx context.Context, targetURL, versionID string, isRecu
 			ObjectURL:   objectURL,
 			ShareURL:    shareURL,
 			TimeLeft:    expiry,
+			Expiration:  UTCNow().Add(expiry),
 			ContentType: contentType,
 		})
 	}
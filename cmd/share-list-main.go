@@ -96,6 +96,7 @@ func doShareList(cmd string) *probe.Error {
 			ObjectURL:   share.URL,
 			ShareURL:    shareURL,
 			TimeLeft:    share.Expiry - time.Since(share.Date),
+			Expiration:  share.Date.Add(share.Expiry).UTC(),
 			ContentType: share.ContentType,
 		})
 	}
@@ -175,6 +175,7 @@ func doShareUploadURL(ctx context.Context, objectURL string, isRecursive bool, e
 		ObjectURL:   objectURL,
 		ShareURL:    curlCmd,
 		TimeLeft:    expiry,
+		Expiration:  UTCNow().Add(expiry),
 		ContentType: contentType,
 	})
 
@@ -56,6 +56,7 @@ type shareMesssage struct {
 	ObjectURL   string        `json:"url"`
 	ShareURL    string        `json:"share"`
 	TimeLeft    time.Duration `json:"timeLeft"`
+	Expiration  time.Time     `json:"expiration"`
 	ContentType string        `json:"contentType,omitempty"` // Only used by upload cmd.
 }
 
@@ -105,12 +106,12 @@ func shareSetColor() {
 
 // Get share dir name.
 func getShareDir() (string, *probe.Error) {
-	configDir, err := getMcConfigDir()
+	stateDir, err := getMcStateDir()
 	if err != nil {
 		return "", err.Trace()
 	}
 
-	sharedURLsDataDir := filepath.Join(configDir, globalSharedURLsDataDir)
+	sharedURLsDataDir := filepath.Join(stateDir, globalSharedURLsDataDir)
 	return sharedURLsDataDir, nil
 }
 
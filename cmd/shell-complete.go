@@ -0,0 +1,203 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// shellCompletion - the kind of completion of an argument.
+type shellCompletion int
+
+const (
+	completeNothing shellCompletion = iota
+	completeCommand
+	completeRemote
+	completeRemoteDir
+	completeLocal
+)
+
+// completionOf - returns how to complete the argument following args.
+func completionOf(args []string) shellCompletion {
+	if len(args) == 0 {
+		return completeCommand
+	}
+	_, paths := splitFlags(args[1:])
+	switch args[0] {
+	case "cd":
+		if len(paths) == 0 {
+			return completeRemoteDir
+		}
+		return completeNothing
+	case "get":
+		if len(paths) == 0 {
+			return completeRemote
+		}
+		return completeLocal
+	case "put":
+		if len(paths) == 0 {
+			return completeLocal
+		}
+		return completeRemote
+	case "pwd", "help", "exit", "quit", "mc":
+		return completeNothing
+	}
+	return completeRemote
+}
+
+// listDir - returns the names of the entries of the folder dir, an
+// aliased URL, with a trailing '/' for the folders. Listings are cached
+// until the next command.
+func (sh *mcShell) listDir(dir string) []string {
+	dir = strings.TrimSuffix(dir, "/")
+	if names, ok := sh.listings[dir]; ok {
+		return names
+	}
+	var names []string
+	if dir == "" {
+		names, _ = shellAliases()
+	} else if clnt, err := newClient(dir + "/"); err == nil {
+		baseURL := clnt.GetURL()
+		base := baseURL.String()
+		if !strings.HasSuffix(base, string(baseURL.Separator)) {
+			base += string(baseURL.Separator)
+		}
+		for content := range clnt.List(globalContext, ListOptions{ShowDir: DirFirst}) {
+			if content.Err != nil {
+				break
+			}
+			name := strings.TrimPrefix(content.URL.String(), base)
+			if content.Type.IsDir() && !strings.HasSuffix(name, "/") {
+				name += "/"
+			}
+			if name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	sh.listings[dir] = names
+	return names
+}
+
+// listLocalDir - returns the names of the entries of the local folder,
+// with a trailing '/' for the folders.
+func listLocalDir(dir string) []string {
+	if dir == "" {
+		dir = "."
+	}
+	entries, e := os.ReadDir(filepath.FromSlash(dir))
+	if e != nil {
+		return nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			name += "/"
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// completions - returns the completions of word, the argument following
+// args.
+func (sh *mcShell) completions(args []string, word string) (completions []string) {
+	kind := completionOf(args)
+	if kind == completeCommand {
+		for _, c := range shellCommands {
+			if strings.HasPrefix(c.name, word) {
+				completions = append(completions, c.name)
+			}
+		}
+		return completions
+	}
+	if kind == completeNothing || strings.HasPrefix(word, "-") {
+		return nil
+	}
+
+	parent, prefix := "", word
+	if i := strings.LastIndex(word, "/"); i >= 0 {
+		parent, prefix = word[:i+1], word[i+1:]
+	}
+	var names []string
+	if kind == completeLocal {
+		names = listLocalDir(parent)
+	} else {
+		dir := sh.resolve(parent)
+		if parent == "" {
+			dir = sh.dir
+		}
+		names = sh.listDir(dir)
+	}
+	for _, name := range names {
+		if kind == completeRemoteDir && !strings.HasSuffix(name, "/") {
+			continue
+		}
+		if strings.HasPrefix(name, prefix) {
+			completions = append(completions, parent+name)
+		}
+	}
+	sort.Strings(completions)
+	return completions
+}
+
+// commonPrefix - returns the longest common prefix of names.
+func commonPrefix(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	prefix := names[0]
+	for _, name := range names[1:] {
+		for !strings.HasPrefix(name, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// autoComplete - completes the word before the cursor on Tab, lists the
+// completions when there are several and none is longer.
+func (sh *mcShell) autoComplete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+	head := line[:pos]
+	start := strings.LastIndexAny(head, " \t") + 1
+	word := head[start:]
+	completions := sh.completions(strings.Fields(head[:start]), word)
+	if len(completions) == 0 {
+		return line, pos, true
+	}
+
+	completed := commonPrefix(completions)
+	if len(completions) == 1 && !strings.HasSuffix(completed, "/") {
+		completed += " "
+	}
+	if completed == word {
+		names := make([]string, len(completions))
+		for i, c := range completions {
+			names[i] = c[strings.LastIndex(strings.TrimSuffix(c, "/"), "/")+1:]
+		}
+		sh.terminal.Write([]byte(strings.Join(names, "  ") + "\n"))
+	}
+	return head[:start] + completed + line[pos:], start + len(completed), true
+}
@@ -0,0 +1,498 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/shlex"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"golang.org/x/term"
+)
+
+var shellCmd = cli.Command{
+	Name:         "shell",
+	Usage:        "run commands in an interactive shell with a current bucket or prefix",
+	Action:       mainShell,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [TARGET]
+
+  The shell starts in TARGET, an alias, a bucket or a prefix, or else at the
+  root of the aliases. 'cd' changes the current folder, the paths of the
+  commands are relative to it, or to the root of the aliases when they start
+  with '/'. The names of the aliases, buckets and prefixes are completed with
+  the Tab key, from listings cached until the next command, and the commands
+  are saved in the history, recalled with the Up and Down keys.
+
+  Type 'help' in the shell for the list of its commands, 'mc COMMAND' runs
+  any mc command with its arguments as is. Flags are passed as is, write the
+  values of the flags as --flag=value to keep them from being taken as paths.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Browse a bucket, then download an object to the local folder.
+     {{.Prompt}} {{.HelpName}} s3/mybucket
+     mc s3/mybucket> cd photos/2023
+     mc s3/mybucket/photos/2023> ls
+     mc s3/mybucket/photos/2023> get beach.jpg
+
+  2. Run the commands of a file, without prompt.
+     {{.Prompt}} {{.HelpName}} s3/mybucket < commands.txt
+`,
+}
+
+// Number of commands saved in the history, the history of the terminal
+// keeps as many.
+const shellHistorySize = 100
+
+// shellCommand - a command of the shell, help and exit are run by the
+// shell itself.
+type shellCommand struct {
+	name  string
+	args  string
+	usage string
+	run   func(sh *mcShell, args []string) *probe.Error
+}
+
+// shellCommands - the commands of the shell, listed in this order by help.
+var shellCommands = []shellCommand{
+	{"cd", "[PATH]", "change the current folder, to the root of the aliases without PATH", (*mcShell).changeDir},
+	{"pwd", "", "print the current folder", (*mcShell).printDir},
+	{"ls", "[FLAGS] [PATH...]", "list buckets and objects", shellRemote("ls")},
+	{"tree", "[FLAGS] [PATH...]", "list buckets and objects in a tree format", shellRemote("tree")},
+	{"stat", "[FLAGS] PATH...", "show object metadata", shellRemote("stat")},
+	{"cat", "[FLAGS] PATH...", "display object contents", shellRemote("cat")},
+	{"du", "[FLAGS] [PATH...]", "summarize disk usage", shellRemote("du")},
+	{"rm", "[FLAGS] PATH...", "remove objects", shellRemote("rm")},
+	{"get", "[FLAGS] PATH... [LOCAL]", "download objects to LOCAL, or to the local folder", (*mcShell).get},
+	{"put", "[FLAGS] LOCAL... [PATH]", "upload local files to PATH, or to the current folder", (*mcShell).put},
+	{"mc", "COMMAND [ARGS...]", "run an mc command with its arguments as is", (*mcShell).mc},
+	{"help", "", "show the commands of the shell", nil},
+	{"exit", "", "leave the shell, as does Ctrl-D", nil},
+}
+
+func lookupShellCommand(name string) *shellCommand {
+	for i := range shellCommands {
+		if shellCommands[i].name == name || (name == "quit" && shellCommands[i].name == "exit") {
+			return &shellCommands[i]
+		}
+	}
+	return nil
+}
+
+// mcShell - the state of the shell.
+type mcShell struct {
+	// The current folder, an aliased URL without trailing separator,
+	// empty at the root of the aliases.
+	dir string
+	// The global flags of the commands run.
+	globalArgs []string
+	// Names of the folders listed for completion, by folder.
+	listings map[string][]string
+	// Terminal of the interactive shell, nil when reading commands from
+	// a file or a pipe.
+	terminal    *term.Terminal
+	historyFile string
+	out         io.Writer
+}
+
+func newMcShell(globalArgs []string) *mcShell {
+	return &mcShell{globalArgs: globalArgs, listings: map[string][]string{}, out: os.Stdout}
+}
+
+// resolve - returns the aliased URL of arg, relative to the current
+// folder or, when it starts with '/', to the root of the aliases.
+func (sh *mcShell) resolve(arg string) string {
+	p := arg
+	if !strings.HasPrefix(arg, "/") {
+		p = "/" + sh.dir + "/" + arg
+	}
+	p = strings.TrimPrefix(path.Clean(p), "/")
+	if p != "" && strings.HasSuffix(arg, "/") {
+		p += "/"
+	}
+	return p
+}
+
+// resolveArgs - resolves the arguments of args which are not flags.
+func (sh *mcShell) resolveArgs(args []string) []string {
+	resolved := make([]string, len(args))
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			resolved[i] = arg
+		} else {
+			resolved[i] = sh.resolve(arg)
+		}
+	}
+	return resolved
+}
+
+// splitFlags - returns the flags and the other arguments of args.
+func splitFlags(args []string) (flags, others []string) {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+		} else {
+			others = append(others, arg)
+		}
+	}
+	return flags, others
+}
+
+// run - runs mc with args, its failures are reported by itself.
+func (sh *mcShell) run(args ...string) *probe.Error {
+	exe, e := os.Executable()
+	if e != nil {
+		return probe.NewError(e)
+	}
+	cmd := exec.Command(exe, append(append([]string{}, sh.globalArgs...), args...)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if e = cmd.Run(); e != nil {
+		if _, ok := e.(*exec.ExitError); !ok {
+			return probe.NewError(e)
+		}
+	}
+	// The listings may have changed.
+	sh.listings = map[string][]string{}
+	return nil
+}
+
+// shellRemote - returns the shell command running the mc command name,
+// with paths, in the current folder without them.
+func shellRemote(name string) func(sh *mcShell, args []string) *probe.Error {
+	return func(sh *mcShell, args []string) *probe.Error {
+		flags, paths := splitFlags(args)
+		if len(paths) == 0 {
+			if sh.dir == "" && (name == "ls" || name == "tree") {
+				return sh.listAliases()
+			}
+			if sh.dir == "" {
+				return probe.NewError(fmt.Errorf("`%s` needs a path at the root of the aliases", name))
+			}
+			paths = []string{sh.dir + "/"}
+		}
+		return sh.run(append(append([]string{name}, flags...), sh.resolveArgs(paths)...)...)
+	}
+}
+
+func (sh *mcShell) listAliases() *probe.Error {
+	aliases, err := shellAliases()
+	if err != nil {
+		return err.Trace()
+	}
+	for _, alias := range aliases {
+		fmt.Fprintln(sh.out, alias)
+	}
+	return nil
+}
+
+// shellAliases - returns the names of the aliases, as folders.
+func shellAliases() ([]string, *probe.Error) {
+	conf, err := loadMcConfig()
+	if err != nil {
+		return nil, err.Trace()
+	}
+	aliases := make([]string, 0, len(conf.Aliases))
+	for alias := range conf.Aliases {
+		aliases = append(aliases, alias+"/")
+	}
+	sort.Strings(aliases)
+	return aliases, nil
+}
+
+func (sh *mcShell) changeDir(args []string) *probe.Error {
+	if len(args) > 1 {
+		return probe.NewError(fmt.Errorf("cd takes one path"))
+	}
+	if len(args) == 0 {
+		sh.dir = ""
+		return nil
+	}
+	dir := strings.TrimSuffix(sh.resolve(args[0]), "/")
+	if dir == "" {
+		sh.dir = ""
+		return nil
+	}
+	alias, _ := url2Alias(dir)
+	if mustGetHostConfig(alias) == nil {
+		return probe.NewError(fmt.Errorf("`%s` is not an alias", alias))
+	}
+	if dir != alias {
+		clnt, err := newClient(dir + "/")
+		if err != nil {
+			return err.Trace(dir)
+		}
+		content, err := clnt.Stat(globalContext, StatOptions{})
+		if err != nil {
+			return err.Trace(dir)
+		}
+		if !content.Type.IsDir() {
+			return probe.NewError(fmt.Errorf("`%s` is not a folder", dir))
+		}
+	}
+	sh.dir = dir
+	return nil
+}
+
+func (sh *mcShell) printDir(_ []string) *probe.Error {
+	fmt.Fprintln(sh.out, "/"+sh.dir)
+	return nil
+}
+
+// get - downloads objects, to the local folder when the last argument
+// is not a local destination.
+func (sh *mcShell) get(args []string) *probe.Error {
+	flags, paths := splitFlags(args)
+	switch len(paths) {
+	case 0:
+		return probe.NewError(fmt.Errorf("get needs the objects to download"))
+	case 1:
+		paths = append(paths, ".")
+	}
+	sources := sh.resolveArgs(paths[:len(paths)-1])
+	return sh.run(append(append(append([]string{"cp"}, flags...), sources...), paths[len(paths)-1])...)
+}
+
+// put - uploads local files, to the current folder when the last argument
+// is not a destination.
+func (sh *mcShell) put(args []string) *probe.Error {
+	flags, paths := splitFlags(args)
+	var target string
+	switch len(paths) {
+	case 0:
+		return probe.NewError(fmt.Errorf("put needs the files to upload"))
+	case 1:
+		if sh.dir == "" {
+			return probe.NewError(fmt.Errorf("put needs a destination at the root of the aliases"))
+		}
+		target = sh.dir + "/"
+	default:
+		target = sh.resolve(paths[len(paths)-1])
+		paths = paths[:len(paths)-1]
+	}
+	return sh.run(append(append(append([]string{"cp"}, flags...), paths...), target)...)
+}
+
+func (sh *mcShell) mc(args []string) *probe.Error {
+	if len(args) == 0 {
+		return probe.NewError(fmt.Errorf("mc needs a command"))
+	}
+	return sh.run(args...)
+}
+
+func (sh *mcShell) help() {
+	for _, c := range shellCommands {
+		fmt.Fprintf(sh.out, "  %-30s %s\n", strings.TrimSpace(c.name+" "+c.args), c.usage)
+	}
+}
+
+// prompt - the prompt of the current folder.
+func (sh *mcShell) prompt() string {
+	if sh.dir == "" {
+		return "mc> "
+	}
+	return "mc " + sh.dir + "> "
+}
+
+// exec - runs a command line of the shell, returns false to leave it.
+func (sh *mcShell) exec(line string) bool {
+	args, e := shlex.Split(line)
+	if e != nil {
+		errorIf(probe.NewError(e), "Unable to parse `%s`.", line)
+		return true
+	}
+	if len(args) == 0 {
+		return true
+	}
+	cmd := lookupShellCommand(args[0])
+	switch {
+	case cmd == nil:
+		errorIf(probe.NewError(fmt.Errorf("unknown command `%s`, type 'help' for the commands", args[0])), "Unable to run `%s`.", line)
+	case cmd.name == "exit":
+		return false
+	case cmd.name == "help":
+		sh.help()
+	default:
+		errorIf(cmd.run(sh, args[1:]), "Unable to run `%s`.", line)
+	}
+	return true
+}
+
+// shellReadWriter - the input and the output of the terminal, which
+// replays the history while the output is discarded.
+type shellReadWriter struct {
+	io.Reader
+	io.Writer
+}
+
+// loadHistory - returns the last commands saved in the history file.
+func loadHistory(file string) []string {
+	data, e := os.ReadFile(file)
+	if e != nil {
+		return nil
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > shellHistorySize {
+		lines = lines[len(lines)-shellHistorySize:]
+	}
+	return lines
+}
+
+// saveHistory - appends line to the history file, which keeps the last
+// shellHistorySize commands.
+func saveHistory(file, line string) {
+	lines := loadHistory(file)
+	if len(lines) >= shellHistorySize {
+		lines = append(lines[len(lines)-shellHistorySize+1:], line)
+		os.WriteFile(file, []byte(strings.Join(lines, "\n")+"\n"), 0o600)
+		return
+	}
+	f, e := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if e != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, line)
+}
+
+// newShellTerminal - returns the terminal of the shell, with the commands
+// of the history file.
+func newShellTerminal(historyFile string) *term.Terminal {
+	history := loadHistory(historyFile)
+	rw := &shellReadWriter{
+		Reader: strings.NewReader(strings.Join(history, "\r") + "\r"),
+		Writer: io.Discard,
+	}
+	t := term.NewTerminal(rw, "")
+	for range history {
+		t.ReadLine()
+	}
+	rw.Reader, rw.Writer = os.Stdin, os.Stdout
+	return t
+}
+
+// readLine - reads a command from the terminal, in raw mode until the
+// command is entered so that the commands run get a regular terminal.
+func (sh *mcShell) readLine() (string, error) {
+	fd := int(os.Stdin.Fd())
+	state, e := term.MakeRaw(fd)
+	if e != nil {
+		return "", e
+	}
+	defer term.Restore(fd, state)
+	if width, height, e := term.GetSize(fd); e == nil && width > 0 {
+		sh.terminal.SetSize(width, height)
+	}
+	sh.terminal.SetPrompt(sh.prompt())
+	line, e := sh.terminal.ReadLine()
+	if e == term.ErrPasteIndicator {
+		e = nil
+	}
+	return line, e
+}
+
+// main for shell command.
+func mainShell(cliCtx *cli.Context) error {
+	if len(cliCtx.Args()) > 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+
+	globalArgs, err := jobArgs()
+	fatalIf(err, "Unable to get the config folder.")
+	for _, flag := range []struct {
+		name string
+		set  bool
+	}{
+		{"--json", globalJSON},
+		{"--quiet", globalQuiet},
+		{"--no-color", globalNoColor},
+		{"--insecure", globalInsecure},
+	} {
+		if flag.set {
+			globalArgs = append(globalArgs, flag.name)
+		}
+	}
+	sh := newMcShell(globalArgs)
+	if cliCtx.Args().Present() {
+		fatalIf(sh.changeDir([]string{"/" + cliCtx.Args().First()}), "Unable to start the shell in `%s`.", cliCtx.Args().First())
+	}
+
+	// Ctrl-C interrupts the command run, the shell goes on.
+	signal.Reset(os.Interrupt)
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	go func() {
+		for range sigCh {
+		}
+	}()
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if !sh.exec(scanner.Text()) {
+				break
+			}
+		}
+		fatalIf(probe.NewError(scanner.Err()), "Unable to read the commands.")
+		return nil
+	}
+
+	stateDir, err := getMcStateDir()
+	fatalIf(err, "Unable to get the state folder.")
+	fatalIf(probe.NewError(os.MkdirAll(stateDir, 0o700)), "Unable to create the state folder `%s`.", stateDir)
+	sh.historyFile = filepath.Join(stateDir, "shell-history")
+	sh.terminal = newShellTerminal(sh.historyFile)
+	sh.terminal.AutoCompleteCallback = sh.autoComplete
+	for {
+		line, e := sh.readLine()
+		if e == io.EOF {
+			return nil
+		}
+		fatalIf(probe.NewError(e), "Unable to read the command.")
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		saveHistory(sh.historyFile, line)
+		if !sh.exec(line) {
+			return nil
+		}
+	}
+}
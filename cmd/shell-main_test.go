@@ -0,0 +1,94 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestShellResolve(t *testing.T) {
+	testCases := []struct {
+		dir, arg, want string
+	}{
+		{"", "play", "play"},
+		{"", "play/bucket/", "play/bucket/"},
+		{"play/bucket", "a.txt", "play/bucket/a.txt"},
+		{"play/bucket", "photos/", "play/bucket/photos/"},
+		{"play/bucket/photos", "..", "play/bucket"},
+		{"play/bucket/photos", "../../other/", "play/other/"},
+		{"play/bucket", "/s3/backups", "s3/backups"},
+		{"play/bucket", "/", ""},
+		{"play", "../../..", ""},
+	}
+	for _, testCase := range testCases {
+		sh := newMcShell(nil)
+		sh.dir = testCase.dir
+		if got := sh.resolve(testCase.arg); got != testCase.want {
+			t.Errorf("%q in %q: got %q, want %q", testCase.arg, testCase.dir, got, testCase.want)
+		}
+	}
+}
+
+func TestShellCompletions(t *testing.T) {
+	sh := newMcShell(nil)
+	sh.dir = "play/bucket"
+	sh.listings["play/bucket"] = []string{"a.txt", "photos/", "pictures/", "plan.txt"}
+	sh.listings["play/bucket/photos"] = []string{"2022/", "2023/", "beach.jpg"}
+
+	testCases := []struct {
+		args []string
+		word string
+		want []string
+	}{
+		{nil, "p", []string{"pwd", "put"}},
+		{[]string{"ls"}, "p", []string{"photos/", "pictures/", "plan.txt"}},
+		{[]string{"cd"}, "p", []string{"photos/", "pictures/"}},
+		{[]string{"cd"}, "photos/", []string{"photos/2022/", "photos/2023/"}},
+		{[]string{"get"}, "photos/b", []string{"photos/beach.jpg"}},
+		{[]string{"rm", "-r"}, "/play/bucket/ph", []string{"/play/bucket/photos/"}},
+		{[]string{"ls"}, "--rec", nil},
+		{[]string{"cd", "photos"}, "", nil},
+		{[]string{"mc"}, "ls", nil},
+	}
+	for _, testCase := range testCases {
+		if got := sh.completions(testCase.args, testCase.word); !reflect.DeepEqual(got, testCase.want) {
+			t.Errorf("%q %q: got %q, want %q", testCase.args, testCase.word, got, testCase.want)
+		}
+	}
+
+	if got := commonPrefix([]string{"photos/", "pictures/", "plan.txt"}); got != "p" {
+		t.Errorf("got common prefix %q, want %q", got, "p")
+	}
+}
+
+func TestShellHistory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "shell-history")
+	for i := 0; i < shellHistorySize+10; i++ {
+		saveHistory(file, fmt.Sprintf("ls %d", i))
+	}
+	history := loadHistory(file)
+	if len(history) != shellHistorySize {
+		t.Fatalf("got %d commands, want %d", len(history), shellHistorySize)
+	}
+	if history[0] != "ls 10" || history[len(history)-1] != fmt.Sprintf("ls %d", shellHistorySize+9) {
+		t.Errorf("got history from %q to %q", history[0], history[len(history)-1])
+	}
+}
@@ -45,6 +45,9 @@ func trapSignals(sig ...os.Signal) {
 	// Cancel the global context
 	globalCancel()
 
+	// Save the traffic done so far
+	globalUsage.flush()
+
 	var exitCode int
 	switch s.String() {
 	case "interrupt":
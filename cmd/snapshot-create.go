@@ -0,0 +1,139 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+var snapshotCreateCmd = cli.Command{
+	Name:         "create",
+	Usage:        "save the recursive listing of a bucket or folder to a file",
+	Action:       mainSnapshotCreate,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET FILE
+
+  The snapshot holds the name, size, modification time and ETag of every
+  object, for 'mc diff --base FILE' to tell the changes made since on each
+  side of a two-way sync, or for 'mc diff' to compare it with a bucket or
+  folder, or another snapshot. FILE is compressed by gzip when its name ends
+  with '.gz'.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Save the listing of a bucket after syncing it with a local folder.
+     {{.Prompt}} {{.HelpName}} s3/mybucket/photos photos.snapshot
+
+  2. Save the compressed listing of a bucket for a later audit.
+     {{.Prompt}} {{.HelpName}} s3/mybucket photos-2023-04.json.gz
+`,
+}
+
+// snapshotCreateMessage - reports the snapshot saved.
+type snapshotCreateMessage struct {
+	Status  string `json:"status"`
+	URL     string `json:"url"`
+	File    string `json:"file"`
+	Objects int64  `json:"objects"`
+	Size    int64  `json:"size"`
+}
+
+// String colorized snapshot message
+func (s snapshotCreateMessage) String() string {
+	return console.Colorize("Snapshot", fmt.Sprintf("Saved the listing of %d object(s), %s, of `%s` to `%s`.",
+		s.Objects, humanize.IBytes(uint64(s.Size)), s.URL, s.File))
+}
+
+// JSON jsonified snapshot message
+func (s snapshotCreateMessage) JSON() string {
+	s.Status = "success"
+	msgBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// createSnapshot - lists targetURL recursively into a snapshot.
+func createSnapshot(ctx context.Context, targetURL string) (snapshot, *probe.Error) {
+	s := snapshot{snapshotHeader: snapshotHeader{
+		Version: snapshotVersion,
+		Created: UTCNow(),
+		URL:     targetURL,
+	}}
+
+	separator := string(newClientURL(targetURL).Separator)
+	if !strings.HasSuffix(targetURL, separator) {
+		targetURL += separator
+	}
+	clnt, err := newClient(targetURL)
+	if err != nil {
+		return s, err.Trace(targetURL)
+	}
+	baseURL := clnt.GetURL().String()
+	for content := range clnt.List(ctx, ListOptions{Recursive: true, ShowDir: DirNone, Parallel: scanListParallel}) {
+		if content.Err != nil {
+			return s, content.Err.Trace(targetURL)
+		}
+		s.Entries = append(s.Entries, snapshotEntry{
+			Name:         relativeName(content.URL.String(), baseURL, content.URL.Separator),
+			Size:         content.Size,
+			LastModified: content.Time,
+			ETag:         content.ETag,
+		})
+	}
+	return s, nil
+}
+
+// main for snapshot create command.
+func mainSnapshotCreate(cliCtx *cli.Context) error {
+	if len(cliCtx.Args()) != 2 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	console.SetColor("Snapshot", color.New(color.FgGreen, color.Bold))
+
+	ctx, cancelSnapshot := context.WithCancel(globalContext)
+	defer cancelSnapshot()
+
+	targetURL, file := cliCtx.Args().Get(0), cliCtx.Args().Get(1)
+	s, err := createSnapshot(ctx, targetURL)
+	fatalIf(err, "Unable to list `%s`.", targetURL)
+	fatalIf(saveSnapshot(file, s).Trace(file), "Unable to save the snapshot `%s`.", file)
+
+	msg := snapshotCreateMessage{URL: targetURL, File: file, Objects: int64(len(s.Entries))}
+	for _, entry := range s.Entries {
+		msg.Size += entry.Size
+	}
+	printMsg(msg)
+	return nil
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+var snapshotDiffFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "summary",
+		Usage: "only print the number and the size of the objects added, removed and changed",
+	},
+}
+
+var snapshotDiffCmd = cli.Command{
+	Name:         "diff",
+	Usage:        "report the objects added, removed and changed between two snapshots",
+	Action:       mainSnapshotDiff,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(snapshotDiffFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] OLD NEW
+
+  Compares two snapshots of 'mc snapshot create', without listing the bucket
+  or folder they were taken of, to explain its growth between them. Objects
+  are changed when they differ in size, in ETag when both are known, or else
+  in modification time. The churn is the size of the objects added, removed
+  and changed, the growth the difference of the sizes of both snapshots.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+LEGEND:
+  + - object added in NEW.
+  - - object removed from NEW.
+  ~ - object changed in NEW.
+
+EXAMPLES:
+  1. Report the objects backed up between two nightly snapshots.
+     {{.Prompt}} {{.HelpName}} backup-2023-05-01.json.gz backup-2023-05-02.json.gz
+
+  2. Print the churn and the growth of a bucket over a month.
+     {{.Prompt}} {{.HelpName}} --summary backup-2023-04-01.json.gz backup-2023-05-01.json.gz
+`,
+}
+
+// snapshotDiffMessage - an object added, removed or changed between two
+// snapshots.
+type snapshotDiffMessage struct {
+	Status       string `json:"status"`
+	Name         string `json:"name"`
+	Change       string `json:"change"`
+	Size         int64  `json:"size"`
+	PreviousSize int64  `json:"previousSize,omitempty"`
+}
+
+func (d snapshotDiffMessage) String() string {
+	switch d.Change {
+	case "added":
+		return console.Colorize("SnapshotAdded", fmt.Sprintf("+ %s (%s)", quoteName(d.Name), humanize.IBytes(uint64(d.Size))))
+	case "removed":
+		return console.Colorize("SnapshotRemoved", fmt.Sprintf("- %s (%s)", quoteName(d.Name), humanize.IBytes(uint64(d.Size))))
+	}
+	return console.Colorize("SnapshotChanged", fmt.Sprintf("~ %s (%s, was %s)", quoteName(d.Name),
+		humanize.IBytes(uint64(d.Size)), humanize.IBytes(uint64(d.PreviousSize))))
+}
+
+func (d snapshotDiffMessage) JSON() string {
+	d.Status = "success"
+	msgBytes, e := json.MarshalIndent(d, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// snapshotDiffSummaryMessage - the number and the size of the objects
+// added, removed and changed between two snapshots.
+type snapshotDiffSummaryMessage struct {
+	Status      string `json:"status"`
+	Added       int64  `json:"added"`
+	AddedSize   int64  `json:"addedSize"`
+	Removed     int64  `json:"removed"`
+	RemovedSize int64  `json:"removedSize"`
+	Changed     int64  `json:"changed"`
+	ChangedSize int64  `json:"changedSize"`
+	Churn       int64  `json:"churn"`
+	Growth      int64  `json:"growth"`
+}
+
+func (d *snapshotDiffSummaryMessage) add(msg snapshotDiffMessage) {
+	switch msg.Change {
+	case "added":
+		d.Added++
+		d.AddedSize += msg.Size
+		d.Growth += msg.Size
+	case "removed":
+		d.Removed++
+		d.RemovedSize += msg.Size
+		d.Growth -= msg.Size
+	default:
+		d.Changed++
+		d.ChangedSize += msg.Size
+		d.Growth += msg.Size - msg.PreviousSize
+	}
+	d.Churn = d.AddedSize + d.RemovedSize + d.ChangedSize
+}
+
+func (d snapshotDiffSummaryMessage) String() string {
+	growth := "+" + humanize.IBytes(uint64(d.Growth))
+	if d.Growth < 0 {
+		growth = "-" + humanize.IBytes(uint64(-d.Growth))
+	}
+	return console.Colorize("SnapshotSummary", fmt.Sprintf("%d object(s) added (%s), %d removed (%s), %d changed (%s), churn of %s, growth of %s.",
+		d.Added, humanize.IBytes(uint64(d.AddedSize)), d.Removed, humanize.IBytes(uint64(d.RemovedSize)),
+		d.Changed, humanize.IBytes(uint64(d.ChangedSize)), humanize.IBytes(uint64(d.Churn)), growth))
+}
+
+func (d snapshotDiffSummaryMessage) JSON() string {
+	d.Status = "success"
+	msgBytes, e := json.MarshalIndent(d, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// changedIn - returns true when the object of the entry differs in the
+// newer entry of the same name.
+func (entry snapshotEntry) changedIn(newer snapshotEntry) bool {
+	if entry.Size != newer.Size {
+		return true
+	}
+	if entry.ETag != "" && newer.ETag != "" {
+		return entry.ETag != newer.ETag
+	}
+	return !entry.LastModified.Equal(newer.LastModified)
+}
+
+// diffSnapshots - returns the objects added, removed and changed from the
+// old snapshot to the new one, sorted by name.
+func diffSnapshots(before, after snapshot) []snapshotDiffMessage {
+	oldEntries, newEntries := before.byName(), after.byName()
+	var diffs []snapshotDiffMessage
+	for _, entry := range after.Entries {
+		oldEntry, ok := oldEntries[entry.Name]
+		switch {
+		case !ok:
+			diffs = append(diffs, snapshotDiffMessage{Name: entry.Name, Change: "added", Size: entry.Size})
+		case oldEntry.changedIn(entry):
+			diffs = append(diffs, snapshotDiffMessage{Name: entry.Name, Change: "changed", Size: entry.Size, PreviousSize: oldEntry.Size})
+		}
+	}
+	for _, entry := range before.Entries {
+		if _, ok := newEntries[entry.Name]; !ok {
+			diffs = append(diffs, snapshotDiffMessage{Name: entry.Name, Change: "removed", Size: entry.Size})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+	return diffs
+}
+
+// main for snapshot diff command.
+func mainSnapshotDiff(cliCtx *cli.Context) error {
+	if len(cliCtx.Args()) != 2 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	console.SetColor("SnapshotAdded", color.New(color.FgGreen))
+	console.SetColor("SnapshotRemoved", color.New(color.FgRed))
+	console.SetColor("SnapshotChanged", color.New(color.FgYellow))
+	console.SetColor("SnapshotSummary", color.New(color.FgGreen, color.Bold))
+
+	oldFile, newFile := cliCtx.Args().Get(0), cliCtx.Args().Get(1)
+	before, err := loadSnapshot(oldFile)
+	fatalIf(err.Trace(oldFile), "Unable to load the snapshot `%s`.", oldFile)
+	after, err := loadSnapshot(newFile)
+	fatalIf(err.Trace(newFile), "Unable to load the snapshot `%s`.", newFile)
+
+	var summary snapshotDiffSummaryMessage
+	for _, msg := range diffSnapshots(before, after) {
+		summary.add(msg)
+		if !cliCtx.Bool("summary") {
+			printMsg(msg)
+		}
+	}
+	printMsg(summary)
+	return nil
+}
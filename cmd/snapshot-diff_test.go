@@ -0,0 +1,72 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	day := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	before := snapshot{Entries: []snapshotEntry{
+		{Name: "a", Size: 10, LastModified: day},
+		{Name: "b", Size: 20, LastModified: day, ETag: "b1"},
+		{Name: "c", Size: 30, LastModified: day},
+		{Name: "d", Size: 40, LastModified: day, ETag: "d1"},
+		{Name: "e", Size: 50, LastModified: day},
+	}}
+	after := snapshot{Entries: []snapshotEntry{
+		// Rewritten with the same content.
+		{Name: "b", Size: 20, LastModified: day.Add(time.Hour), ETag: "b1"},
+		{Name: "c", Size: 35, LastModified: day.Add(time.Hour)},
+		{Name: "d", Size: 40, LastModified: day, ETag: "d2"},
+		{Name: "e", Size: 50, LastModified: day.Add(time.Hour)},
+		{Name: "f", Size: 60, LastModified: day.Add(time.Hour)},
+	}}
+
+	diffs := diffSnapshots(before, after)
+	expected := []snapshotDiffMessage{
+		{Name: "a", Change: "removed", Size: 10},
+		{Name: "c", Change: "changed", Size: 35, PreviousSize: 30},
+		{Name: "d", Change: "changed", Size: 40, PreviousSize: 40},
+		{Name: "e", Change: "changed", Size: 50, PreviousSize: 50},
+		{Name: "f", Change: "added", Size: 60},
+	}
+	if !reflect.DeepEqual(diffs, expected) {
+		t.Fatalf("expected %v, got %v", expected, diffs)
+	}
+
+	var summary snapshotDiffSummaryMessage
+	for _, diff := range diffs {
+		summary.add(diff)
+	}
+	expectedSummary := snapshotDiffSummaryMessage{
+		Added: 1, AddedSize: 60,
+		Removed: 1, RemovedSize: 10,
+		Changed: 3, ChangedSize: 125,
+		Churn: 195, Growth: 55,
+	}
+	if summary != expectedSummary {
+		t.Fatalf("expected %+v, got %+v", expectedSummary, summary)
+	}
+	if s := summary.String(); s != "1 object(s) added (60 B), 1 removed (10 B), 3 changed (125 B), churn of 195 B, growth of +55 B." {
+		t.Fatalf("unexpected summary %q", s)
+	}
+}
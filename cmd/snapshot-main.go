@@ -0,0 +1,195 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var snapshotSubcommands = []cli.Command{
+	snapshotCreateCmd,
+	snapshotDiffCmd,
+}
+
+var snapshotCmd = cli.Command{
+	Name:            "snapshot",
+	Usage:           "save the listing of a bucket or folder to compare it later",
+	Action:          mainSnapshot,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	Subcommands:     snapshotSubcommands,
+	HideHelpCommand: true,
+}
+
+// mainSnapshot is the handle for "mc snapshot" command.
+func mainSnapshot(ctx *cli.Context) error {
+	commandNotFound(ctx, snapshotSubcommands)
+	return nil
+	// Sub-commands like "create" have their own main.
+}
+
+const snapshotVersion = "1"
+
+// snapshotHeader - the first line of a snapshot file.
+type snapshotHeader struct {
+	Version string    `json:"version"`
+	Created time.Time `json:"created"`
+	URL     string    `json:"url"`
+}
+
+// snapshotEntry - an object of a snapshot, named relative to the URL of
+// the snapshot, a line of the snapshot file after the header.
+type snapshotEntry struct {
+	Name         string    `json:"name"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"lastModified"`
+	ETag         string    `json:"etag,omitempty"`
+}
+
+// snapshot - the recursive listing of a bucket or folder, saved by
+// `mc snapshot create`, in the listing order.
+type snapshot struct {
+	snapshotHeader
+	Entries []snapshotEntry
+}
+
+// byName - returns the entries of the snapshot by their name.
+func (s snapshot) byName() map[string]snapshotEntry {
+	entries := make(map[string]snapshotEntry, len(s.Entries))
+	for _, entry := range s.Entries {
+		entries[entry.Name] = entry
+	}
+	return entries
+}
+
+// unchanged - returns true when content, of the same name as the entry
+// of the snapshot created at created, is the object of the snapshot. ETags
+// are compared when both are known, or else the object is modified when it
+// was last modified after the snapshot, as both sides of a sync, snapshot
+// once, may have different modification times.
+func (entry snapshotEntry) unchanged(content *ClientContent, created time.Time) bool {
+	if entry.Size != content.Size {
+		return false
+	}
+	if entry.ETag != "" && content.ETag != "" {
+		return entry.ETag == content.ETag
+	}
+	return entry.LastModified.Equal(content.Time) || !content.Time.After(created)
+}
+
+func writeSnapshot(w io.Writer, s snapshot) error {
+	enc := json.NewEncoder(w)
+	if e := enc.Encode(s.snapshotHeader); e != nil {
+		return e
+	}
+	for _, entry := range s.Entries {
+		if e := enc.Encode(entry); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func readSnapshot(r io.Reader) (snapshot, error) {
+	var s snapshot
+	dec := json.NewDecoder(r)
+	if e := dec.Decode(&s.snapshotHeader); e != nil {
+		return s, fmt.Errorf("invalid snapshot header: %w", e)
+	}
+	if s.Version != snapshotVersion {
+		return s, fmt.Errorf("unsupported snapshot version `%s`", s.Version)
+	}
+	for {
+		var entry snapshotEntry
+		e := dec.Decode(&entry)
+		if e == io.EOF {
+			break
+		}
+		if e != nil {
+			return s, fmt.Errorf("invalid snapshot entry: %w", e)
+		}
+		s.Entries = append(s.Entries, entry)
+	}
+	return s, nil
+}
+
+// saveSnapshot - writes the snapshot to file, compressed by gzip when its
+// name ends with .gz, replaced only once it is complete.
+func saveSnapshot(file string, s snapshot) *probe.Error {
+	tmpFile := file + ".tmp"
+	f, e := os.OpenFile(tmpFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	w := bufio.NewWriter(f)
+	if strings.HasSuffix(file, ".gz") {
+		gw := gzip.NewWriter(w)
+		if e = writeSnapshot(gw, s); e == nil {
+			e = gw.Close()
+		}
+	} else {
+		e = writeSnapshot(w, s)
+	}
+	if e == nil {
+		e = w.Flush()
+	}
+	if e == nil {
+		e = f.Close()
+	} else {
+		f.Close()
+	}
+	if e == nil {
+		e = os.Rename(tmpFile, file)
+	}
+	if e != nil {
+		os.Remove(tmpFile)
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// loadSnapshot - reads the snapshot file, compressed by gzip or not.
+func loadSnapshot(file string) (snapshot, *probe.Error) {
+	f, e := os.Open(file)
+	if e != nil {
+		return snapshot{}, probe.NewError(e)
+	}
+	defer f.Close()
+	var r io.Reader = bufio.NewReader(f)
+	if magic, _ := r.(*bufio.Reader).Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		if r, e = gzip.NewReader(r); e != nil {
+			return snapshot{}, probe.NewError(fmt.Errorf("unable to read the snapshot `%s`: %w", file, e))
+		}
+	}
+	s, e := readSnapshot(r)
+	if e != nil {
+		return s, probe.NewError(fmt.Errorf("unable to read the snapshot `%s`: %w", file, e))
+	}
+	return s, nil
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSnapshotReadWrite(t *testing.T) {
+	created := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	s := snapshot{
+		snapshotHeader: snapshotHeader{Version: snapshotVersion, Created: created, URL: "s3/mybucket"},
+		Entries: []snapshotEntry{
+			{Name: "photos/b.jpg", Size: 20, LastModified: created.Add(-time.Hour)},
+			{Name: "photos/a.jpg", Size: 10, LastModified: created.Add(-time.Minute), ETag: "a1"},
+		},
+	}
+	var buf bytes.Buffer
+	if e := writeSnapshot(&buf, s); e != nil {
+		t.Fatal(e)
+	}
+	read, e := readSnapshot(&buf)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if read.snapshotHeader != s.snapshotHeader {
+		t.Fatalf("expected header %v, got %v", s.snapshotHeader, read.snapshotHeader)
+	}
+	if !reflect.DeepEqual(read.Entries, s.Entries) {
+		t.Fatalf("expected entries %v, got %v", s.Entries, read.Entries)
+	}
+
+	if _, e := readSnapshot(bytes.NewBufferString(`{"version":"0"}`)); e == nil {
+		t.Fatal("expected an unsupported version error")
+	}
+}
+
+func TestSnapshotEntryUnchanged(t *testing.T) {
+	created := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	before, after := created.Add(-time.Hour), created.Add(time.Hour)
+	testCases := []struct {
+		entry     snapshotEntry
+		content   ClientContent
+		unchanged bool
+	}{
+		{snapshotEntry{Size: 1, LastModified: before}, ClientContent{Size: 1, Time: before}, true},
+		{snapshotEntry{Size: 1, LastModified: before}, ClientContent{Size: 2, Time: before}, false},
+		// The other side of a sync, modified before the snapshot.
+		{snapshotEntry{Size: 1, LastModified: before}, ClientContent{Size: 1, Time: before.Add(-time.Minute)}, true},
+		{snapshotEntry{Size: 1, LastModified: before}, ClientContent{Size: 1, Time: after}, false},
+		{snapshotEntry{Size: 1, LastModified: before, ETag: "a"}, ClientContent{Size: 1, Time: after, ETag: "a"}, true},
+		{snapshotEntry{Size: 1, LastModified: before, ETag: "a"}, ClientContent{Size: 1, Time: before, ETag: "b"}, false},
+		{snapshotEntry{Size: 1, LastModified: before, ETag: "a"}, ClientContent{Size: 1, Time: after}, false},
+	}
+	for i, testCase := range testCases {
+		if unchanged := testCase.entry.unchanged(&testCase.content, created); unchanged != testCase.unchanged {
+			t.Errorf("Test %d: expected unchanged %v, got %v", i+1, testCase.unchanged, unchanged)
+		}
+	}
+}
@@ -46,6 +46,14 @@ var (
 			Name:  "recursive, r",
 			Usage: "stat all objects recursively",
 		},
+		cli.BoolFlag{
+			Name:  "probe-media",
+			Usage: "read object headers to report duration, codec and resolution of audio/video objects",
+		},
+		cli.BoolFlag{
+			Name:  "creation-date",
+			Usage: "look up the bucket creation date, this lists all buckets on S3 endpoints",
+		},
 	}
 )
 
@@ -91,6 +99,12 @@ EXAMPLES:
 
   7. Stat all objects versions recursively created before 1st January 2020.
      {{.Prompt}} {{.HelpName}} --versions --rewind 2020.01.01T00:00 s3/personal-docs/
+
+  8. Stat all videos recursively, including their duration, codec and resolution.
+     {{.Prompt}} {{.HelpName}} --recursive --probe-media s3/media/videos/
+
+  9. Stat a bucket including its creation date.
+     {{.Prompt}} {{.HelpName}} --creation-date s3/mybucket
 `,
 }
 
@@ -150,6 +164,7 @@ func mainStat(cliCtx *cli.Context) error {
 	console.SetColor("Unset", color.New(color.FgRed))
 	console.SetColor("Set", color.New(color.FgGreen))
 
+	console.SetColor("Media", color.New(color.FgMagenta))
 	console.SetColor("Title", color.New(color.Bold, color.FgBlue))
 	console.SetColor("Count", color.New(color.FgGreen))
 
@@ -164,8 +179,17 @@ func mainStat(cliCtx *cli.Context) error {
 		args = []string{"."}
 	}
 
+	opts := statURLOpts{
+		versionID:            versionID,
+		timeRef:              rewind,
+		includeOlderVersions: withVersions,
+		isRecursive:          isRecursive,
+		probeMedia:           cliCtx.Bool("probe-media"),
+		creationDate:         cliCtx.Bool("creation-date"),
+		encKeyDB:             encKeyDB,
+	}
 	for _, targetURL := range args {
-		fatalIf(statURL(ctx, targetURL, versionID, rewind, withVersions, false, isRecursive, encKeyDB), "Unable to stat `"+targetURL+"`.")
+		fatalIf(statURL(ctx, targetURL, opts), "Unable to stat `"+targetURL+"`.")
 	}
 
 	return nil
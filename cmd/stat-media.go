@@ -0,0 +1,316 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+)
+
+const (
+	// Number of bytes read from the beginning of an object to detect its media format.
+	mediaProbeHeadSize = 64 * 1024
+	// Largest 'moov' box we are willing to fetch to extract MP4/MOV properties.
+	mediaProbeMaxMoovSize = 8 * 1024 * 1024
+	// Maximum number of top level MP4/MOV boxes visited while looking for 'moov'.
+	mediaProbeMaxBoxes = 32
+)
+
+// mediaInfo holds audio/video properties extracted from the object header.
+type mediaInfo struct {
+	Format     string  `json:"format"`
+	Codec      string  `json:"codec,omitempty"`
+	Duration   float64 `json:"duration,omitempty"` // in seconds
+	Width      uint32  `json:"width,omitempty"`
+	Height     uint32  `json:"height,omitempty"`
+	SampleRate uint32  `json:"sampleRate,omitempty"`
+	Channels   uint16  `json:"channels,omitempty"`
+}
+
+// mediaReadAtFn reads up to length bytes at the given offset of an object.
+type mediaReadAtFn func(offset, length int64) ([]byte, error)
+
+var errMediaTruncated = errors.New("truncated media header")
+
+// statMedia reads just enough bytes of the object to figure out its media
+// properties, nil is returned if the object is not a recognized media file.
+func statMedia(ctx context.Context, clnt Client, content *ClientContent, sse encrypt.ServerSide) (*mediaInfo, *probe.Error) {
+	readAt := func(offset, length int64) ([]byte, error) {
+		reader, _, err := clnt.Get(ctx, GetOptions{SSE: sse, VersionID: content.VersionID, RangeStart: offset, RangeLength: length})
+		if err != nil {
+			return nil, err.ToGoError()
+		}
+		defer reader.Close()
+		return io.ReadAll(reader)
+	}
+	info, e := probeMedia(readAt, content.Size)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	return info, nil
+}
+
+// probeMedia detects the media format of an object of the given size
+// and extracts as many properties as the format allows.
+func probeMedia(readAt mediaReadAtFn, size int64) (*mediaInfo, error) {
+	if size <= 0 {
+		return nil, nil
+	}
+	head, e := readAt(0, mediaProbeHeadSize)
+	if e != nil {
+		return nil, e
+	}
+	switch {
+	case len(head) >= 12 && string(head[4:8]) == "ftyp":
+		return probeMP4(readAt, head, size)
+	case len(head) >= 12 && string(head[0:4]) == "RIFF" && string(head[8:12]) == "WAVE":
+		return probeWAV(head)
+	case bytes.HasPrefix(head, []byte("fLaC")):
+		return probeFLAC(head)
+	case bytes.HasPrefix(head, []byte("OggS")):
+		return &mediaInfo{Format: "ogg"}, nil
+	case bytes.HasPrefix(head, []byte{0x1a, 0x45, 0xdf, 0xa3}):
+		if bytes.Contains(head, []byte("webm")) {
+			return &mediaInfo{Format: "webm"}, nil
+		}
+		return &mediaInfo{Format: "matroska"}, nil
+	case bytes.HasPrefix(head, []byte("ID3")),
+		len(head) >= 2 && head[0] == 0xff && head[1]&0xe0 == 0xe0:
+		return &mediaInfo{Format: "mp3", Codec: "mp3"}, nil
+	}
+	return nil, nil
+}
+
+// mp4BoxHeader parses an ISO BMFF box header, returning the box type,
+// the total box size and the size of the header itself.
+func mp4BoxHeader(b []byte) (boxType string, size, hdrSize int64, e error) {
+	if len(b) < 8 {
+		return "", 0, 0, errMediaTruncated
+	}
+	size = int64(binary.BigEndian.Uint32(b[0:4]))
+	boxType = string(b[4:8])
+	hdrSize = 8
+	if size == 1 {
+		if len(b) < 16 {
+			return "", 0, 0, errMediaTruncated
+		}
+		size = int64(binary.BigEndian.Uint64(b[8:16]))
+		hdrSize = 16
+	}
+	if size != 0 && size < hdrSize {
+		return "", 0, 0, errors.New("invalid media box size")
+	}
+	return boxType, size, hdrSize, nil
+}
+
+// probeMP4 walks the top level boxes of MP4/MOV files until the 'moov' box
+// is found, only fetching box headers when 'moov' is not part of head.
+func probeMP4(readAt mediaReadAtFn, head []byte, size int64) (*mediaInfo, error) {
+	info := &mediaInfo{Format: "mp4"}
+	if brand := string(head[8:12]); brand == "qt  " {
+		info.Format = "mov"
+	}
+
+	var offset int64
+	for i := 0; i < mediaProbeMaxBoxes && offset < size; i++ {
+		var hdr []byte
+		if offset+16 <= int64(len(head)) {
+			hdr = head[offset : offset+16]
+		} else {
+			b, e := readAt(offset, 16)
+			if e != nil {
+				return nil, e
+			}
+			hdr = b
+		}
+		boxType, boxSize, hdrSize, e := mp4BoxHeader(hdr)
+		if e != nil {
+			break
+		}
+		if boxSize == 0 {
+			// Box extends to the end of the object.
+			boxSize = size - offset
+		}
+		if boxType == "moov" {
+			if boxSize > mediaProbeMaxMoovSize {
+				break
+			}
+			var moov []byte
+			if offset+boxSize <= int64(len(head)) {
+				moov = head[offset+hdrSize : offset+boxSize]
+			} else {
+				b, e := readAt(offset+hdrSize, boxSize-hdrSize)
+				if e != nil {
+					return nil, e
+				}
+				moov = b
+			}
+			parseMP4Moov(moov, info)
+			break
+		}
+		offset += boxSize
+	}
+	return info, nil
+}
+
+// parseMP4Moov extracts duration, resolution and codecs from a 'moov' box payload.
+func parseMP4Moov(b []byte, info *mediaInfo) {
+	var codecs []string
+	walkMP4Boxes(b, func(boxType string, payload []byte) bool {
+		switch boxType {
+		case "mvhd":
+			parseMP4Mvhd(payload, info)
+		case "trak", "mdia", "minf", "stbl":
+			return true
+		case "tkhd":
+			// Track width and height are 16.16 fixed point values at the end of the box.
+			if len(payload) >= 8 {
+				w := binary.BigEndian.Uint32(payload[len(payload)-8:]) >> 16
+				h := binary.BigEndian.Uint32(payload[len(payload)-4:]) >> 16
+				if w > 0 && h > 0 && info.Width == 0 {
+					info.Width, info.Height = w, h
+				}
+			}
+		case "stsd":
+			// version/flags (4), entry count (4), first entry size (4) and format (4).
+			if len(payload) >= 16 {
+				codec := strings.TrimSpace(string(payload[12:16]))
+				if codec != "" {
+					codecs = append(codecs, codec)
+				}
+			}
+		}
+		return false
+	})
+	info.Codec = strings.Join(codecs, ",")
+}
+
+// parseMP4Mvhd extracts the movie duration from a 'mvhd' box payload.
+func parseMP4Mvhd(b []byte, info *mediaInfo) {
+	if len(b) < 4 {
+		return
+	}
+	var timescale uint32
+	var duration uint64
+	switch b[0] {
+	case 1:
+		if len(b) < 32 {
+			return
+		}
+		timescale = binary.BigEndian.Uint32(b[20:24])
+		duration = binary.BigEndian.Uint64(b[24:32])
+	default:
+		if len(b) < 20 {
+			return
+		}
+		timescale = binary.BigEndian.Uint32(b[12:16])
+		duration = uint64(binary.BigEndian.Uint32(b[16:20]))
+	}
+	if timescale > 0 {
+		info.Duration = float64(duration) / float64(timescale)
+	}
+}
+
+// walkMP4Boxes calls fn for every box found in b, descending into the
+// box payload when fn returns true.
+func walkMP4Boxes(b []byte, fn func(boxType string, payload []byte) bool) {
+	for len(b) >= 8 {
+		boxType, size, hdrSize, e := mp4BoxHeader(b)
+		if e != nil {
+			return
+		}
+		if size == 0 || size > int64(len(b)) {
+			size = int64(len(b))
+		}
+		payload := b[hdrSize:size]
+		if fn(boxType, payload) {
+			walkMP4Boxes(payload, fn)
+		}
+		b = b[size:]
+	}
+}
+
+var wavCodecs = map[uint16]string{
+	0x0001: "pcm",
+	0x0003: "pcm_float",
+	0x0006: "alaw",
+	0x0007: "mulaw",
+	0x0055: "mp3",
+	0xfffe: "extensible",
+}
+
+// probeWAV extracts audio properties from the RIFF chunks of a WAV file.
+func probeWAV(head []byte) (*mediaInfo, error) {
+	info := &mediaInfo{Format: "wav"}
+	var byteRate uint32
+	b := head[12:]
+	for len(b) >= 8 {
+		chunkID := string(b[0:4])
+		chunkSize := int64(binary.LittleEndian.Uint32(b[4:8]))
+		payload := b[8:]
+		switch chunkID {
+		case "fmt ":
+			if len(payload) < 16 {
+				return info, nil
+			}
+			format := binary.LittleEndian.Uint16(payload[0:2])
+			info.Codec = wavCodecs[format]
+			info.Channels = binary.LittleEndian.Uint16(payload[2:4])
+			info.SampleRate = binary.LittleEndian.Uint32(payload[4:8])
+			byteRate = binary.LittleEndian.Uint32(payload[8:12])
+		case "data":
+			if byteRate > 0 {
+				info.Duration = float64(chunkSize) / float64(byteRate)
+			}
+			return info, nil
+		}
+		// Chunks are word aligned.
+		chunkSize += chunkSize & 1
+		if chunkSize > int64(len(payload)) {
+			break
+		}
+		b = payload[chunkSize:]
+	}
+	return info, nil
+}
+
+// probeFLAC extracts audio properties from the STREAMINFO block of a FLAC file.
+func probeFLAC(head []byte) (*mediaInfo, error) {
+	info := &mediaInfo{Format: "flac", Codec: "flac"}
+	// "fLaC" marker (4), metadata block header (4), STREAMINFO (34).
+	if len(head) < 42 || head[4]&0x7f != 0 {
+		return info, nil
+	}
+	si := head[8:42]
+	// Sample rate (20 bits), channels - 1 (3 bits), bits per sample - 1 (5 bits),
+	// total samples (36 bits), starting at byte 10 of STREAMINFO.
+	info.SampleRate = uint32(si[10])<<12 | uint32(si[11])<<4 | uint32(si[12])>>4
+	info.Channels = uint16((si[12]>>1)&0x07) + 1
+	totalSamples := uint64(si[13]&0x0f)<<32 | uint64(binary.BigEndian.Uint32(si[14:18]))
+	if info.SampleRate > 0 {
+		info.Duration = float64(totalSamples) / float64(info.SampleRate)
+	}
+	return info, nil
+}
@@ -46,6 +46,8 @@ type statMessage struct {
 	Size              int64              `json:"size"`
 	ETag              string             `json:"etag"`
 	Type              string             `json:"type,omitempty"`
+	ContentType       string             `json:"contentType,omitempty"`
+	StorageClass      string             `json:"storageClass,omitempty"`
 	Expires           *time.Time         `json:"expires,omitempty"`
 	Expiration        *time.Time         `json:"expiration,omitempty"`
 	ExpirationRuleID  string             `json:"expirationRuleID,omitempty"`
@@ -54,6 +56,7 @@ type statMessage struct {
 	VersionID         string             `json:"versionID,omitempty"`
 	DeleteMarker      bool               `json:"deleteMarker,omitempty"`
 	Restore           *minio.RestoreInfo `json:"restore,omitempty"`
+	Media             *mediaInfo         `json:"media,omitempty"`
 }
 
 func (stat statMessage) String() (msg string) {
@@ -79,6 +82,12 @@ func (stat statMessage) String() (msg string) {
 		msgBuilder.WriteString(fmt.Sprintf("%-10s: %s ", "VersionID", versionIDField) + "\n")
 	}
 	msgBuilder.WriteString(fmt.Sprintf("%-10s: %s ", "Type", stat.Type) + "\n")
+	if stat.ContentType != "" {
+		msgBuilder.WriteString(fmt.Sprintf("%-10s: %s ", "Content", stat.ContentType) + "\n")
+	}
+	if stat.StorageClass != "" {
+		msgBuilder.WriteString(fmt.Sprintf("%-10s: %s ", "Class", stat.StorageClass) + "\n")
+	}
 	if stat.Expires != nil {
 		msgBuilder.WriteString(fmt.Sprintf("%-10s: %s ", "Expires", stat.Expires.Format(printDate)) + "\n")
 	}
@@ -93,6 +102,23 @@ func (stat statMessage) String() (msg string) {
 		msgBuilder.WriteString(fmt.Sprintf("  %-10s: %t", "Ongoing",
 			stat.Restore.OngoingRestore) + "\n")
 	}
+	if stat.Media != nil {
+		msgBuilder.WriteString(fmt.Sprintf("%-10s:", "Media") + "\n")
+		msgBuilder.WriteString(console.Colorize("Media", fmt.Sprintf("  %-10s: %s", "Format", stat.Media.Format)) + "\n")
+		if stat.Media.Codec != "" {
+			msgBuilder.WriteString(console.Colorize("Media", fmt.Sprintf("  %-10s: %s", "Codec", stat.Media.Codec)) + "\n")
+		}
+		if stat.Media.Duration > 0 {
+			duration := time.Duration(stat.Media.Duration * float64(time.Second)).Round(time.Millisecond)
+			msgBuilder.WriteString(console.Colorize("Media", fmt.Sprintf("  %-10s: %s", "Duration", duration)) + "\n")
+		}
+		if stat.Media.Width > 0 && stat.Media.Height > 0 {
+			msgBuilder.WriteString(console.Colorize("Media", fmt.Sprintf("  %-10s: %dx%d", "Resolution", stat.Media.Width, stat.Media.Height)) + "\n")
+		}
+		if stat.Media.SampleRate > 0 {
+			msgBuilder.WriteString(console.Colorize("Media", fmt.Sprintf("  %-10s: %d Hz, %d channel(s)", "Audio", stat.Media.SampleRate, stat.Media.Channels)) + "\n")
+		}
+	}
 	maxKeyMetadata := 0
 	maxKeyEncrypted := 0
 	for k := range stat.Metadata {
@@ -158,6 +184,10 @@ func parseStat(c *ClientContent) statMessage {
 		return "file"
 	}()
 	content.Size = c.Size
+	if !c.Type.IsDir() {
+		content.ContentType = c.ContentType
+	}
+	content.StorageClass = c.StorageClass
 	content.VersionID = c.VersionID
 	content.Key = getKey(c)
 	content.Metadata = c.Metadata
@@ -180,10 +210,25 @@ func getStandardizedURL(targetURL string) string {
 	return filepath.FromSlash(targetURL)
 }
 
+// statURLOpts holds the options of statURL.
+type statURLOpts struct {
+	versionID            string
+	timeRef              time.Time
+	includeOlderVersions bool
+	isIncomplete         bool
+	isRecursive          bool
+	probeMedia           bool
+	creationDate         bool
+	encKeyDB             map[string][]prefixSSEPair
+}
+
 // statURL - uses combination of GET listing and HEAD to fetch information of one or more objects
 // HEAD can fail with 400 with an SSE-C encrypted object but we still return information gathered
 // from GET listing.
-func statURL(ctx context.Context, targetURL, versionID string, timeRef time.Time, includeOlderVersions, isIncomplete, isRecursive bool, encKeyDB map[string][]prefixSSEPair) *probe.Error {
+func statURL(ctx context.Context, targetURL string, opts statURLOpts) *probe.Error {
+	versionID, timeRef, isRecursive := opts.versionID, opts.timeRef, opts.isRecursive
+	encKeyDB := opts.encKeyDB
+
 	clnt, err := newClient(targetURL)
 	if err != nil {
 		return err
@@ -210,6 +255,11 @@ func statURL(ctx context.Context, targetURL, versionID string, timeRef time.Time
 			contentURL = strings.TrimPrefix(contentURL, prefixPath)
 			bstat.URL.Path = contentURL
 
+			if s3Clnt, ok := unwrapClient(clnt).(*S3Client); ok && opts.creationDate {
+				if date, err := s3Clnt.bucketCreationDate(ctx); err == nil {
+					bstat.Date = date
+				}
+			}
 			if bstat.Date.IsZero() || bstat.Date.Equal(timeSentinel) {
 				bstat.Date = time.Now()
 			}
@@ -239,13 +289,13 @@ func statURL(ctx context.Context, targetURL, versionID string, timeRef time.Time
 		}
 	}
 
-	lstOptions := ListOptions{Recursive: isRecursive, Incomplete: isIncomplete, ShowDir: DirNone}
+	lstOptions := ListOptions{Recursive: isRecursive, Incomplete: opts.isIncomplete, ShowDir: DirNone}
 	switch {
 	case versionID != "":
 		lstOptions.WithOlderVersions = true
 		lstOptions.WithDeleteMarkers = true
-	case !timeRef.IsZero(), includeOlderVersions:
-		lstOptions.WithOlderVersions = includeOlderVersions
+	case !timeRef.IsZero(), opts.includeOlderVersions:
+		lstOptions.WithOlderVersions = opts.includeOlderVersions
 		lstOptions.WithDeleteMarkers = true
 		lstOptions.TimeRef = timeRef
 	}
@@ -289,11 +339,17 @@ func statURL(ctx context.Context, targetURL, versionID string, timeRef time.Time
 				continue
 			}
 		}
-		_, stat, err := url2Stat(ctx, url2StatOptions{urlStr: url, versionID: content.VersionID, fileAttr: true, encKeyDB: encKeyDB, timeRef: timeRef, isZip: false, ignoreBucketExistsCheck: false})
+		objClnt, stat, err := url2Stat(ctx, url2StatOptions{urlStr: url, versionID: content.VersionID, fileAttr: true, encKeyDB: encKeyDB, timeRef: timeRef, isZip: false, ignoreBucketExistsCheck: false})
 		if err != nil {
 			continue
 		}
 
+		var media *mediaInfo
+		if opts.probeMedia && !stat.Type.IsDir() && !stat.IsDeleteMarker {
+			media, err = statMedia(ctx, objClnt, stat, getSSE(url, encKeyDB[targetAlias]))
+			errorIf(err.Trace(url), "Unable to probe media information of `"+url+"`.")
+		}
+
 		// Convert any os specific delimiters to "/".
 		contentURL := filepath.ToSlash(stat.URL.Path)
 		prefixPath = filepath.ToSlash(prefixPath)
@@ -301,7 +357,9 @@ func statURL(ctx context.Context, targetURL, versionID string, timeRef time.Time
 		contentURL = strings.TrimPrefix(contentURL, prefixPath)
 		stat.URL.Path = contentURL
 
-		printMsg(parseStat(stat))
+		statMsg := parseStat(stat)
+		statMsg.Media = media
+		printMsg(statMsg)
 	}
 
 	return probe.NewError(e)
@@ -18,6 +18,8 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/binary"
 	"os"
 	"reflect"
 	"strings"
@@ -35,6 +37,7 @@ func TestParseStat(t *testing.T) {
 		{ClientContent{URL: *newClientURL("https://play.min.io/testbucket"), Size: 500, Time: localTime, Type: os.ModeDir, ETag: "blahblah", Metadata: map[string]string{"cusom-key": "custom-value"}, Expires: time.Unix(0, 0).UTC()}, "play"},
 		{ClientContent{URL: *newClientURL("https://s3.amazonaws.com/yrdy"), Size: 0, Time: localTime, Type: 0o644, ETag: "abcdefasaas", Metadata: map[string]string{}}, "s3"},
 		{ClientContent{URL: *newClientURL("https://play.min.io/yrdy"), Size: 10000, Time: localTime, Type: 0o644, ETag: "blahblah", Metadata: map[string]string{"cusom-key": "custom-value"}}, "play"},
+		{ClientContent{URL: *newClientURL("https://play.min.io/photo.jpg"), Size: 10000, Time: localTime, Type: 0o644, ContentType: "image/jpeg", StorageClass: "REDUCED_REDUNDANCY", Metadata: map[string]string{}}, "play"},
 	}
 	for _, testCase := range testCases {
 		testCase := testCase
@@ -60,6 +63,12 @@ func TestParseStat(t *testing.T) {
 					t.Errorf("Expecting folder, got %s", statMsg.Type)
 				}
 			}
+			if testCase.content.Type.IsRegular() && statMsg.ContentType != testCase.content.ContentType {
+				t.Errorf("Expecting %s, got %s", testCase.content.ContentType, statMsg.ContentType)
+			}
+			if statMsg.StorageClass != testCase.content.StorageClass {
+				t.Errorf("Expecting %s, got %s", testCase.content.StorageClass, statMsg.StorageClass)
+			}
 			etag := strings.TrimPrefix(testCase.content.ETag, "\"")
 			etag = strings.TrimSuffix(etag, "\"")
 			if etag != statMsg.ETag {
@@ -68,3 +77,70 @@ func TestParseStat(t *testing.T) {
 		})
 	}
 }
+
+func mp4Box(boxType string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	b := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(b[0:4], uint32(8+len(body)))
+	copy(b[4:8], boxType)
+	return append(b, body...)
+}
+
+func TestProbeMedia(t *testing.T) {
+	// mvhd version 0 with a timescale of 1000 and a duration of 90500.
+	mvhd := make([]byte, 20)
+	binary.BigEndian.PutUint32(mvhd[12:16], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:20], 90500)
+	// tkhd ending with a 1920x1080 16.16 fixed point resolution.
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[76:80], 1920<<16)
+	binary.BigEndian.PutUint32(tkhd[80:84], 1080<<16)
+	stsd := make([]byte, 16)
+	copy(stsd[12:16], "avc1")
+	moov := mp4Box("moov", mp4Box("mvhd", mvhd),
+		mp4Box("trak", mp4Box("tkhd", tkhd), mp4Box("mdia", mp4Box("minf", mp4Box("stbl", mp4Box("stsd", stsd))))))
+	// Put a large 'mdat' before 'moov' so that it is not part of the header read.
+	mdat := mp4Box("mdat", make([]byte, mediaProbeHeadSize))
+	mp4 := bytes.Join([][]byte{mp4Box("ftyp", []byte("isom"), make([]byte, 4)), mdat, moov}, nil)
+
+	wav := []byte("RIFF\x00\x00\x00\x00WAVE")
+	fmtChunk := make([]byte, 16)
+	binary.LittleEndian.PutUint16(fmtChunk[0:2], 1)
+	binary.LittleEndian.PutUint16(fmtChunk[2:4], 2)
+	binary.LittleEndian.PutUint32(fmtChunk[4:8], 44100)
+	binary.LittleEndian.PutUint32(fmtChunk[8:12], 44100*4)
+	wav = append(wav, []byte("fmt \x10\x00\x00\x00")...)
+	wav = append(wav, fmtChunk...)
+	dataSize := make([]byte, 4)
+	binary.LittleEndian.PutUint32(dataSize, 44100*4*3)
+	wav = append(append(wav, []byte("data")...), dataSize...)
+
+	testCases := []struct {
+		data     []byte
+		expected *mediaInfo
+	}{
+		{mp4, &mediaInfo{Format: "mp4", Codec: "avc1", Duration: 90.5, Width: 1920, Height: 1080}},
+		{wav, &mediaInfo{Format: "wav", Codec: "pcm", Duration: 3, SampleRate: 44100, Channels: 2}},
+		{[]byte("OggS\x00\x02"), &mediaInfo{Format: "ogg"}},
+		{[]byte("not a media file"), nil},
+	}
+	for i, testCase := range testCases {
+		readAt := func(offset, length int64) ([]byte, error) {
+			if offset >= int64(len(testCase.data)) {
+				return nil, nil
+			}
+			end := offset + length
+			if end > int64(len(testCase.data)) {
+				end = int64(len(testCase.data))
+			}
+			return testCase.data[offset:end], nil
+		}
+		info, e := probeMedia(readAt, int64(len(testCase.data)))
+		if e != nil {
+			t.Fatalf("Test %d: unexpected error: %v", i+1, e)
+		}
+		if !reflect.DeepEqual(info, testCase.expected) {
+			t.Errorf("Test %d: expected %+v, got %+v", i+1, testCase.expected, info)
+		}
+	}
+}
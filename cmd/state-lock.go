@@ -0,0 +1,60 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+const (
+	stateFileLockRetry = 10 * time.Millisecond
+	stateFileLockWait  = 2 * time.Second
+	// Locks older than this were left behind by a killed process.
+	stateFileLockStale = 30 * time.Second
+)
+
+// lockStateFile - serializes the updates of a state file under the config
+// directory by concurrent mc processes with a lock file next to it, returns
+// the function releasing it. The files themselves are written to a temporary
+// file renamed over them, readers never see a partial write.
+func lockStateFile(file string) (unlock func(), err *probe.Error) {
+	lockFile := file + ".lock"
+	deadline := time.Now().Add(stateFileLockWait)
+	for {
+		f, e := os.OpenFile(lockFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if e == nil {
+			f.Close()
+			return func() { os.Remove(lockFile) }, nil
+		}
+		if !os.IsExist(e) {
+			return nil, probe.NewError(e).Trace(lockFile)
+		}
+		if fi, e := os.Stat(lockFile); e == nil && time.Since(fi.ModTime()) > stateFileLockStale {
+			os.Remove(lockFile)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, probe.NewError(fmt.Errorf("`%s` is locked by another mc process, remove `%s` if none is running", file, lockFile))
+		}
+		time.Sleep(stateFileLockRetry)
+	}
+}
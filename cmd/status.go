@@ -127,6 +127,9 @@ func (qs *QuietStatus) Start() {
 // Finish displays the accounting summary
 func (qs *QuietStatus) Finish() {
 	printMsg(qs.accounter.Stat())
+	if msg, ok := getThrottleMessage(); ok {
+		printMsg(msg)
+	}
 }
 
 // Update is ignored for quietstatus
@@ -225,6 +228,9 @@ func (ps *ProgressStatus) Start() {
 // Finish displays the accounting summary
 func (ps *ProgressStatus) Finish() {
 	ps.progressBar.Finish()
+	if msg, ok := getThrottleMessage(); ok {
+		printMsg(msg)
+	}
 }
 
 // Update is ignored for quietstatus
@@ -0,0 +1,62 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Number of warnings reported by the command, a warning being an error
+// the command recovers from such as a skipped file or unpreserved
+// metadata.
+var globalWarnings int64
+
+// recordWarning - counts a warning, so that the command exits with an
+// error in strict mode.
+func recordWarning() {
+	atomic.AddInt64(&globalWarnings, 1)
+}
+
+// exitIfStrictWarnings - exits with an error in strict mode when the
+// command succeeded but reported warnings.
+func exitIfStrictWarnings() {
+	fatalIf(strictWarningsError(), "Exiting with an error in strict mode.")
+}
+
+// strictWarningsError - returns an error in strict mode when warnings
+// were reported.
+func strictWarningsError() *probe.Error {
+	if !globalStrict {
+		return nil
+	}
+	if n := atomic.LoadInt64(&globalWarnings); n > 0 {
+		return probe.NewError(fmt.Errorf("%d warning(s) reported", n))
+	}
+	return nil
+}
+
+// warnSkippedSymlink - symlinks that cannot be followed are silently
+// skipped while listing, except in strict mode.
+func warnSkippedSymlink(path string, e error) {
+	if globalStrict {
+		warningIf(probe.NewError(e), "Skipping symlink `%s`.", path)
+	}
+}
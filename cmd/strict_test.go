@@ -0,0 +1,74 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestStrictWarnings(t *testing.T) {
+	strict, warnings := globalStrict, atomic.LoadInt64(&globalWarnings)
+	t.Cleanup(func() {
+		globalStrict = strict
+		atomic.StoreInt64(&globalWarnings, warnings)
+	})
+	atomic.StoreInt64(&globalWarnings, 0)
+
+	// Broken symlinks are skipped, and only warned about in strict mode.
+	root := t.TempDir()
+	if e := os.WriteFile(filepath.Join(root, "file"), []byte("data"), 0o644); e != nil {
+		t.Fatal(e)
+	}
+	if e := os.Symlink(filepath.Join(root, "missing"), filepath.Join(root, "broken")); e != nil {
+		t.Skip(e)
+	}
+	list := func() int {
+		clnt, err := fsNew(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n int
+		for content := range clnt.List(context.Background(), ListOptions{Recursive: true}) {
+			if content.Err != nil {
+				t.Fatal(content.Err)
+			}
+			n++
+		}
+		return n
+	}
+
+	globalStrict = false
+	if n := list(); n != 1 {
+		t.Fatalf("expected 1 file listed, got %d", n)
+	}
+	if err := strictWarningsError(); err != nil || atomic.LoadInt64(&globalWarnings) != 0 {
+		t.Fatalf("expected no warning, got %v", err)
+	}
+
+	globalStrict = true
+	if n := list(); n != 1 {
+		t.Fatalf("expected 1 file listed, got %d", n)
+	}
+	if err := strictWarningsError(); err == nil {
+		t.Fatal("expected an error for the skipped symlink in strict mode")
+	}
+}
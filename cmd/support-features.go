@@ -0,0 +1,408 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/pkg/v2/console"
+)
+
+var supportFeaturesFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "write",
+		Usage: "also probe features which need a temporary object in the bucket",
+	},
+}
+
+var supportFeaturesCmd = cli.Command{
+	Name:            "features",
+	Usage:           "probe a target for the S3 features it supports",
+	Action:          mainSupportFeatures,
+	OnUsageError:    onUsageError,
+	Before:          setGlobalsFromContext,
+	Flags:           append(supportFeaturesFlags, globalFlags...),
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Bucket level features are probed with read only requests. Select and
+  checksum support can only be found out with an object, they are probed
+  with '--write' which uploads a small object to the bucket and removes
+  every version of it afterwards. Without write access to the bucket they
+  are reported as unknown.
+
+EXAMPLES:
+  1. Show the features supported by the provider serving 'mybucket'.
+     {{.Prompt}} {{.HelpName}} myr2/mybucket
+
+  2. Also probe S3 Select and the checksum algorithms, in JSON format.
+     {{.Prompt}} {{.HelpName}} --write --json myminio/mybucket
+`,
+}
+
+const (
+	featureSupported   = "supported"
+	featureUnsupported = "unsupported"
+	featureUnknown     = "unknown"
+	featureSkipped     = "skipped"
+)
+
+// featureProbe - result of probing a single feature.
+type featureProbe struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Commands string `json:"commands,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// supportFeaturesMessage - capability matrix of a target.
+type supportFeaturesMessage struct {
+	Status   string         `json:"status"`
+	Target   string         `json:"target"`
+	Features []featureProbe `json:"features"`
+}
+
+func (m supportFeaturesMessage) String() string {
+	table := newPrettyTable("  ",
+		Field{"Feature", 20},
+		Field{"Status", 11},
+		Field{"Commands", 30},
+		Field{"Detail", -1},
+	)
+	lines := []string{console.Colorize("Headers", table.buildRow("FEATURE", "STATUS", "COMMANDS", "DETAIL"))}
+	for _, feature := range m.Features {
+		row := table.buildRow(feature.Name, feature.Status, feature.Commands, feature.Detail)
+		switch feature.Status {
+		case featureSupported:
+			row = console.Colorize("Supported", row)
+		case featureUnsupported:
+			row = console.Colorize("Unsupported", row)
+		}
+		lines = append(lines, row)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m supportFeaturesMessage) JSON() string {
+	jsonBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonBytes)
+}
+
+// S3 error codes returned by servers implementing an API for a bucket
+// with no such configuration.
+var featureNotConfiguredCodes = []string{
+	"NoSuchTagSet",
+	"NoSuchBucketPolicy",
+	"NoSuchLifecycleConfiguration",
+	"ObjectLockConfigurationNotFoundError",
+	"ReplicationConfigurationNotFoundError",
+	"ServerSideEncryptionConfigurationNotFoundError",
+}
+
+// classifyFeatureError - returns the feature status implied by the
+// error of a probe request, with the reason of unknown results.
+func classifyFeatureError(err *probe.Error) (status, detail string) {
+	if err == nil {
+		return featureSupported, ""
+	}
+	e := err.ToGoError()
+	var notImplemented APINotImplemented
+	if errors.As(e, &notImplemented) {
+		return featureUnsupported, ""
+	}
+	errResp := minio.ToErrorResponse(e)
+	switch {
+	case errResp.Code == "NotImplemented", errResp.StatusCode == http.StatusNotImplemented:
+		return featureUnsupported, ""
+	case errResp.Code != "":
+		for _, code := range featureNotConfiguredCodes {
+			if errResp.Code == code {
+				return featureSupported, "not configured"
+			}
+		}
+		return featureUnknown, errResp.Code
+	}
+	return featureUnknown, e.Error()
+}
+
+// probeBucketFeatures - probes the bucket level APIs with read only requests.
+func probeBucketFeatures(ctx context.Context, clnt Client) []featureProbe {
+	probes := []struct {
+		name, commands string
+		probe          func() *probe.Error
+	}{
+		{"Versioning", "mc version", func() *probe.Error {
+			_, err := clnt.GetVersion(ctx)
+			return err
+		}},
+		{"Object Lock", "mc retention, mc legalhold", func() *probe.Error {
+			_, _, _, _, err := clnt.GetObjectLockConfig(ctx)
+			return err
+		}},
+		{"Tagging", "mc tag", func() *probe.Error {
+			_, err := clnt.GetTags(ctx, "")
+			return err
+		}},
+		{"Lifecycle", "mc ilm", func() *probe.Error {
+			_, _, err := clnt.GetLifecycle(ctx)
+			return err
+		}},
+		{"Replication", "mc replicate", func() *probe.Error {
+			_, err := clnt.GetReplication(ctx)
+			return err
+		}},
+		{"Encryption", "mc encrypt", func() *probe.Error {
+			_, _, err := clnt.GetEncryption(ctx)
+			return err
+		}},
+		{"Notification", "mc event", func() *probe.Error {
+			s3Clnt, ok := unwrapClient(clnt).(*S3Client)
+			if !ok {
+				return probe.NewError(APINotImplemented{API: "ListNotificationConfigs", APIType: clnt.GetURL().String()})
+			}
+			_, err := s3Clnt.ListNotificationConfigs(ctx, "")
+			return err
+		}},
+		{"Bucket Policy", "mc anonymous", func() *probe.Error {
+			_, _, err := clnt.GetAccess(ctx)
+			return err
+		}},
+	}
+
+	features := make([]featureProbe, 0, len(probes))
+	for _, p := range probes {
+		status, detail := classifyFeatureError(p.probe())
+		features = append(features, featureProbe{Name: p.name, Status: status, Commands: p.commands, Detail: detail})
+	}
+	return features
+}
+
+// Checksum algorithms probed with --write.
+var featureChecksumTypes = []minio.ChecksumType{
+	minio.ChecksumCRC32,
+	minio.ChecksumCRC32C,
+	minio.ChecksumSHA1,
+	minio.ChecksumSHA256,
+}
+
+const featureProbeData = "name,value\nmc,1\n"
+
+// probeObjectFeatures - probes S3 Select and the checksum algorithms with
+// a temporary object uploaded to the bucket. Every version written while
+// probing is removed afterwards.
+func probeObjectFeatures(ctx context.Context, aliasedURL string) []featureProbe {
+	probeURL := urlJoinPath(aliasedURL, fmt.Sprintf(".mc-features-probe-%s.csv", uuid.NewString()))
+	clnt, err := newClient(probeURL)
+	fatalIf(err.Trace(probeURL), "Unable to initialize target `"+probeURL+"`.")
+
+	s3Clnt, ok := unwrapClient(clnt).(*S3Client)
+	if !ok {
+		// Only S3 implements Select and checksums.
+		return objectFeatures(featureUnsupported, "")
+	}
+
+	p := &featureObjectProbe{clnt: s3Clnt}
+	defer func() {
+		for _, err := range p.cleanup(ctx) {
+			errorIf(err.Trace(probeURL), "Unable to remove the probe object `"+probeURL+"`.")
+		}
+	}()
+
+	if e := p.put(ctx, minio.PutObjectOptions{}); e != nil {
+		// Without write access nothing more can be found out.
+		return objectFeatures(classifyFeatureError(probe.NewError(e)))
+	}
+
+	features := []featureProbe{probeSelectFeature(ctx, clnt)}
+	for _, checksumType := range featureChecksumTypes {
+		feature := featureProbe{Name: "Checksum " + checksumType.String(), Commands: "mc cp, mc put"}
+		feature.Status, feature.Detail = p.probeChecksum(ctx, checksumType)
+		features = append(features, feature)
+	}
+	return features
+}
+
+// objectFeatures - returns the features probed with an object, all with
+// the same status.
+func objectFeatures(status, detail string) []featureProbe {
+	features := []featureProbe{{Name: "Select", Commands: "mc sql", Status: status, Detail: detail}}
+	for _, checksumType := range featureChecksumTypes {
+		features = append(features, featureProbe{Name: "Checksum " + checksumType.String(), Commands: "mc cp, mc put", Status: status, Detail: detail})
+	}
+	return features
+}
+
+func probeSelectFeature(ctx context.Context, clnt Client) featureProbe {
+	feature := featureProbe{Name: "Select", Commands: "mc sql"}
+	reader, err := clnt.Select(ctx, "select count(*) from S3Object", nil, SelectObjectOpts{})
+	if err == nil {
+		_, e := io.Copy(io.Discard, reader)
+		reader.Close()
+		err = probe.NewError(e)
+	}
+	feature.Status, feature.Detail = classifyFeatureError(err)
+	return feature
+}
+
+// featureObjectProbe - the temporary object of the probes, remembering the
+// versions written in versioned buckets.
+type featureObjectProbe struct {
+	clnt     *S3Client
+	written  bool
+	versions []string
+}
+
+// put - uploads the probe data with opts.
+func (p *featureObjectProbe) put(ctx context.Context, opts minio.PutObjectOptions) error {
+	bucket, object := p.clnt.url2BucketAndObject()
+	info, e := p.clnt.api.PutObject(ctx, bucket, object, bytes.NewReader([]byte(featureProbeData)), int64(len(featureProbeData)), opts)
+	if e != nil {
+		return e
+	}
+	p.written = true
+	if info.VersionID != "" {
+		p.versions = append(p.versions, info.VersionID)
+	}
+	return nil
+}
+
+// cleanup - removes every version of the probe object written, or the
+// object itself in unversioned buckets.
+func (p *featureObjectProbe) cleanup(ctx context.Context) (errs []*probe.Error) {
+	if !p.written {
+		return nil
+	}
+	bucket, object := p.clnt.url2BucketAndObject()
+	versions := p.versions
+	if len(versions) == 0 {
+		versions = []string{""}
+	}
+	for _, versionID := range versions {
+		e := p.clnt.api.RemoveObject(ctx, bucket, object, minio.RemoveObjectOptions{VersionID: versionID})
+		if e != nil {
+			errs = append(errs, probe.NewError(e).Trace(versionID))
+		}
+	}
+	return errs
+}
+
+// probeChecksum - uploads the probe object with a checksum and reports the
+// algorithm as supported when the server stored and returns it.
+func (p *featureObjectProbe) probeChecksum(ctx context.Context, checksumType minio.ChecksumType) (status, detail string) {
+	checksum := checksumType.ChecksumBytes([]byte(featureProbeData)).Encoded()
+	e := p.put(ctx, minio.PutObjectOptions{
+		UserMetadata: map[string]string{checksumType.Key(): checksum},
+	})
+	if e != nil {
+		if code := minio.ToErrorResponse(e).Code; code == "InvalidArgument" || code == "InvalidRequest" {
+			return featureUnsupported, code
+		}
+		return classifyFeatureError(probe.NewError(e))
+	}
+
+	bucket, object := p.clnt.url2BucketAndObject()
+	info, e := p.clnt.api.StatObject(ctx, bucket, object, minio.StatObjectOptions{Checksum: true})
+	if e != nil {
+		return classifyFeatureError(probe.NewError(e))
+	}
+	var stored string
+	switch checksumType {
+	case minio.ChecksumCRC32:
+		stored = info.ChecksumCRC32
+	case minio.ChecksumCRC32C:
+		stored = info.ChecksumCRC32C
+	case minio.ChecksumSHA1:
+		stored = info.ChecksumSHA1
+	case minio.ChecksumSHA256:
+		stored = info.ChecksumSHA256
+	}
+	switch stored {
+	case checksum:
+		return featureSupported, ""
+	case "":
+		return featureUnsupported, "checksum not stored"
+	}
+	return featureUnknown, "stored checksum does not match"
+}
+
+func checkSupportFeaturesSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+}
+
+// mainSupportFeatures is the handle for "mc support features" command.
+func mainSupportFeatures(cliCtx *cli.Context) error {
+	ctx, cancelSupportFeatures := context.WithCancel(globalContext)
+	defer cancelSupportFeatures()
+
+	checkSupportFeaturesSyntax(cliCtx)
+
+	console.SetColor("Headers", color.New(color.Bold))
+	console.SetColor("Supported", color.New(color.FgGreen))
+	console.SetColor("Unsupported", color.New(color.FgRed))
+
+	aliasedURL := cliCtx.Args().Get(0)
+	clnt, err := newClient(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to initialize target `"+aliasedURL+"`.")
+
+	clientURL := clnt.GetURL()
+	bucket, object := url2BucketAndObject(&clientURL)
+	if bucket == "" || object != "" {
+		fatalIf(errInvalidArgument().Trace(aliasedURL), "Please provide a bucket, features are probed with bucket level requests.")
+	}
+	_, err = clnt.Stat(ctx, StatOptions{})
+	fatalIf(err.Trace(aliasedURL), "Unable to access bucket `"+aliasedURL+"`.")
+
+	features := probeBucketFeatures(ctx, clnt)
+	if cliCtx.Bool("write") {
+		features = append(features, probeObjectFeatures(ctx, aliasedURL)...)
+	} else {
+		for _, name := range []string{"Select", "Checksums"} {
+			features = append(features, featureProbe{Name: name, Status: featureSkipped, Detail: "requires --write"})
+		}
+	}
+
+	printMsg(supportFeaturesMessage{
+		Status:   "success",
+		Target:   aliasedURL,
+		Features: features,
+	})
+	return nil
+}
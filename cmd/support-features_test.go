@@ -0,0 +1,127 @@
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+)
+
+func TestClassifyFeatureError(t *testing.T) {
+	testCases := []struct {
+		err    *probe.Error
+		status string
+		detail string
+	}{
+		{nil, featureSupported, ""},
+		{probe.NewError(APINotImplemented{API: "GetVersion", APIType: "Cloudflare R2"}), featureUnsupported, ""},
+		{probe.NewError(minio.ErrorResponse{Code: "NotImplemented", StatusCode: http.StatusNotImplemented}), featureUnsupported, ""},
+		{probe.NewError(minio.ErrorResponse{StatusCode: http.StatusNotImplemented}), featureUnsupported, ""},
+		{probe.NewError(minio.ErrorResponse{Code: "NoSuchTagSet", StatusCode: http.StatusNotFound}), featureSupported, "not configured"},
+		{probe.NewError(minio.ErrorResponse{Code: "AccessDenied", StatusCode: http.StatusForbidden}), featureUnknown, "AccessDenied"},
+		{probe.NewError(errors.New("connection refused")), featureUnknown, "connection refused"},
+	}
+
+	for i, testCase := range testCases {
+		status, detail := classifyFeatureError(testCase.err)
+		if status != testCase.status || detail != testCase.detail {
+			t.Errorf("Test %d: expected %s (%s), got %s (%s)", i+1, testCase.status, testCase.detail, status, detail)
+		}
+	}
+}
+
+// versionedObjectHandler - a versioned bucket holding a single object,
+// recording the versions deleted.
+type versionedObjectHandler struct {
+	mu       sync.Mutex
+	deny     bool
+	versions int
+	deleted  []string
+}
+
+func (h *versionedObjectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.deny {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>`)
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Query().Has("location"):
+		fmt.Fprint(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`)
+	case r.Method == http.MethodPut:
+		h.versions++
+		w.Header().Set("x-amz-version-id", fmt.Sprintf("v%d", h.versions))
+		w.Header().Set("ETag", `"etag"`)
+	case r.Method == http.MethodDelete:
+		h.deleted = append(h.deleted, r.URL.Query().Get("versionId"))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func TestFeatureObjectProbeCleanup(t *testing.T) {
+	for _, deny := range []bool{false, true} {
+		handler := &versionedObjectHandler{deny: deny}
+		server := httptest.NewServer(handler)
+		conf := new(Config)
+		conf.HostURL = server.URL + "/bucket/probe.csv"
+		conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+		conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+		conf.Signature = "S3v4"
+		clnt, err := S3New(conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		p := &featureObjectProbe{clnt: clnt.(*S3Client)}
+		e1 := p.put(context.Background(), minio.PutObjectOptions{})
+		e2 := p.put(context.Background(), minio.PutObjectOptions{})
+		if errs := p.cleanup(context.Background()); len(errs) != 0 {
+			t.Fatal(errs)
+		}
+		server.Close()
+
+		if deny {
+			if status, detail := classifyFeatureError(probe.NewError(e1)); status != featureUnknown || detail != "AccessDenied" {
+				t.Errorf("expected unknown (AccessDenied), got %s (%s)", status, detail)
+			}
+			if len(handler.deleted) != 0 {
+				t.Errorf("expected nothing removed, got %v", handler.deleted)
+			}
+			continue
+		}
+		if e1 != nil || e2 != nil {
+			t.Fatal(e1, e2)
+		}
+		if want := []string{"v1", "v2"}; !reflect.DeepEqual(handler.deleted, want) {
+			t.Errorf("expected versions %v removed, got %v", want, handler.deleted)
+		}
+	}
+}
@@ -56,6 +56,7 @@ var supportSubcommands = []cli.Command{
 	supportTopCmd,
 	supportProxyCmd,
 	supportUploadCmd,
+	supportFeaturesCmd,
 }
 
 var supportCmd = cli.Command{
@@ -94,7 +94,8 @@ func promptTrustSelfSignedCert(ctx context.Context, endpoint, alias string) (*x5
 		Transport: &http.Transport{
 			Proxy: http.ProxyFromEnvironment,
 			TLSClientConfig: &tls.Config{
-				RootCAs: globalRootCAs, // make sure to use loaded certs before probing
+				RootCAs:      globalRootCAs, // make sure to use loaded certs before probing
+				Certificates: globalClientCertificates,
 			},
 		},
 	}
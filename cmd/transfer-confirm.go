@@ -0,0 +1,136 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+	"golang.org/x/term"
+)
+
+// Flags of the commands asking to confirm large transfers.
+var transferConfirmFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:   "confirm-above",
+		Usage:  "ask to confirm transfers larger than a size before starting them, e.g. '500GiB'",
+		EnvVar: envPrefix + "CONFIRM_ABOVE",
+	},
+	cli.BoolFlag{
+		Name:  "yes",
+		Usage: "start transfers larger than --confirm-above without asking",
+	},
+}
+
+// transferSummary - what a command is about to transfer.
+type transferSummary struct {
+	Verb    string
+	Target  string
+	Objects int64
+	Size    int64
+}
+
+func (s transferSummary) String() string {
+	objects := "objects"
+	if s.Objects == 1 {
+		objects = "object"
+	}
+	return fmt.Sprintf("About to %s %s across %s %s to `%s`.", s.Verb,
+		humanize.IBytes(uint64(s.Size)), humanize.Comma(s.Objects), objects, s.Target)
+}
+
+// add - adds an object of the transfer.
+func (s *transferSummary) add(size int64) {
+	s.Objects++
+	s.Size += size
+}
+
+// transferVerb - returns how a transfer between the URL types is named.
+func transferVerb(sourceType, targetType ClientURLType, isMove bool) string {
+	switch {
+	case isMove:
+		return "move"
+	case sourceType == fileSystem && targetType == objectStorage:
+		return "upload"
+	case sourceType == objectStorage && targetType == fileSystem:
+		return "download"
+	}
+	return "copy"
+}
+
+// aliasedURLType - returns the type of the URL of an alias, or of a path.
+func aliasedURLType(aliasedURL string) ClientURLType {
+	if _, _, hostCfg := mustExpandAlias(aliasedURL); hostCfg == nil {
+		return fileSystem
+	}
+	return objectStorage
+}
+
+// confirmAboveSize - returns the size above which transfers must be
+// confirmed, from --confirm-above or else the configuration file. Zero
+// never asks.
+func confirmAboveSize(cliCtx *cli.Context) (uint64, *probe.Error) {
+	if cliCtx.Bool("yes") {
+		return 0, nil
+	}
+	value := cliCtx.String("confirm-above")
+	if value == "" {
+		// Validated with the configuration file.
+		value = globalTransfer.ConfirmAbove
+	}
+	if value == "" {
+		return 0, nil
+	}
+	size, e := humanize.ParseBytes(value)
+	if e != nil {
+		return 0, probe.NewError(e).Trace(value)
+	}
+	return size, nil
+}
+
+// confirmTransfer - asks on the terminal to confirm a transfer larger than
+// threshold. Transfers that can't be confirmed interactively fail unless
+// --yes is passed.
+func confirmTransfer(summary transferSummary, threshold uint64) *probe.Error {
+	interactive := !globalJSON && isTerminal() && term.IsTerminal(int(os.Stdin.Fd()))
+	if interactive && isProgressBarEnabled() {
+		// Asked over a progress bar already drawn.
+		console.Eraseline()
+	}
+	return confirmTransferWith(prompt{in: bufio.NewReader(os.Stdin), out: os.Stdout}, interactive, summary, threshold)
+}
+
+func confirmTransferWith(p prompt, interactive bool, summary transferSummary, threshold uint64) *probe.Error {
+	if threshold == 0 || summary.Size <= 0 || uint64(summary.Size) <= threshold {
+		return nil
+	}
+	if !interactive {
+		return probe.NewError(fmt.Errorf("%s It is larger than %s, pass --yes to start it without confirmation",
+			summary, humanize.IBytes(threshold)))
+	}
+	fmt.Fprintln(p.out, summary)
+	if !p.confirm("Continue?", false) {
+		return probe.NewError(fmt.Errorf("the %s to `%s` was not confirmed", summary.Verb, summary.Target))
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/dustin/go-humanize"
+)
+
+func TestConfirmTransfer(t *testing.T) {
+	summary := transferSummary{Verb: "upload", Target: "prod-backup/data/"}
+	for i := 0; i < 1204311; i++ {
+		summary.add(2 * humanize.MiByte)
+	}
+	if s := summary.String(); s != "About to upload 2.3 TiB across 1,204,311 objects to `prod-backup/data/`." {
+		t.Fatalf("unexpected summary %q", s)
+	}
+
+	testCases := []struct {
+		threshold   uint64
+		interactive bool
+		answer      string
+		confirmed   bool
+	}{
+		{0, false, "", true},
+		{3 * humanize.TiByte, false, "", true},
+		{humanize.TiByte, false, "", false},
+		{humanize.TiByte, true, "y\n", true},
+		{humanize.TiByte, true, "yes\n", true},
+		{humanize.TiByte, true, "\n", false},
+		{humanize.TiByte, true, "n\n", false},
+		{humanize.TiByte, true, "", false},
+	}
+	for i, testCase := range testCases {
+		p := prompt{in: bufio.NewReader(strings.NewReader(testCase.answer)), out: io.Discard}
+		err := confirmTransferWith(p, testCase.interactive, summary, testCase.threshold)
+		if confirmed := err == nil; confirmed != testCase.confirmed {
+			t.Errorf("test %d: expected confirmed %v, got %v", i+1, testCase.confirmed, err)
+		}
+	}
+}
+
+func TestTransferVerb(t *testing.T) {
+	testCases := []struct {
+		sourceType, targetType ClientURLType
+		isMove                 bool
+		verb                   string
+	}{
+		{fileSystem, objectStorage, false, "upload"},
+		{objectStorage, fileSystem, false, "download"},
+		{objectStorage, objectStorage, false, "copy"},
+		{fileSystem, fileSystem, false, "copy"},
+		{fileSystem, objectStorage, true, "move"},
+	}
+	for _, testCase := range testCases {
+		if verb := transferVerb(testCase.sourceType, testCase.targetType, testCase.isMove); verb != testCase.verb {
+			t.Errorf("expected %q, got %q", testCase.verb, verb)
+		}
+	}
+}
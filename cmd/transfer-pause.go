@@ -0,0 +1,134 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+// transferPause - paused on SIGTSTP until SIGCONT, no new request is sent
+// and no new transfer starts meanwhile, those in flight complete.
+var transferPause struct {
+	sync.Mutex
+	resumeCh chan struct{} // closed on resume, nil when not paused
+}
+
+// pauseTransfers - pauses the transfers, returns false when they already
+// are.
+func pauseTransfers() bool {
+	transferPause.Lock()
+	defer transferPause.Unlock()
+	if transferPause.resumeCh != nil {
+		return false
+	}
+	transferPause.resumeCh = make(chan struct{})
+	return true
+}
+
+// resumeTransfers - resumes the transfers, returns false when they were
+// not paused.
+func resumeTransfers() bool {
+	transferPause.Lock()
+	defer transferPause.Unlock()
+	if transferPause.resumeCh == nil {
+		return false
+	}
+	close(transferPause.resumeCh)
+	transferPause.resumeCh = nil
+	return true
+}
+
+// waitTransfers - waits while the transfers are paused, returns true
+// when it waited.
+func waitTransfers(ctx context.Context) (bool, error) {
+	transferPause.Lock()
+	resumeCh := transferPause.resumeCh
+	transferPause.Unlock()
+	if resumeCh == nil {
+		return false, nil
+	}
+	select {
+	case <-resumeCh:
+		return true, nil
+	case <-ctx.Done():
+		return true, ctx.Err()
+	}
+}
+
+// pauseMessage - reports that the transfers are paused or resumed.
+type pauseMessage struct {
+	Status string `json:"status"`
+	Paused bool   `json:"paused"`
+}
+
+func (p pauseMessage) String() string {
+	if p.Paused {
+		return "<INFO> Paused, no new request is sent until SIGCONT, e.g. `fg` or `kill -CONT`."
+	}
+	return "<INFO> Resumed."
+}
+
+func (p pauseMessage) JSON() string {
+	p.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(p, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func printPauseMessage(paused bool) {
+	if isProgressBarEnabled() {
+		console.Eraseline()
+	}
+	printMsg(pauseMessage{Paused: paused})
+}
+
+// pauseTransport - holds the requests while the transfers are paused,
+// they are signed again once resumed since the servers reject requests
+// signed 15 minutes before they are received.
+type pauseTransport struct {
+	transport http.RoundTripper
+	accessKey string
+	secretKey string
+}
+
+func newPauseTransport(config *Config, transport http.RoundTripper) http.RoundTripper {
+	return &pauseTransport{
+		transport: transport,
+		accessKey: config.AccessKey,
+		secretKey: config.SecretKey,
+	}
+}
+
+// RoundTrip - implements http.RoundTripper.
+func (t *pauseTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	waited, e := waitTransfers(req.Context())
+	if e != nil {
+		return nil, e
+	}
+	if waited {
+		req = resignRequest(req, t.accessKey, t.secretKey, time.Now())
+	}
+	return t.transport.RoundTrip(req)
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPauseTransport(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer server.Close()
+	transport := newPauseTransport(&Config{}, http.DefaultTransport)
+
+	if !pauseTransfers() {
+		t.Fatal("expected the transfers to pause")
+	}
+	if pauseTransfers() {
+		t.Fatal("expected the transfers to be paused already")
+	}
+	done := make(chan error, 1)
+	go func() {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, e := transport.RoundTrip(req)
+		if e == nil {
+			resp.Body.Close()
+		}
+		done <- e
+	}()
+	time.Sleep(100 * time.Millisecond)
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Fatalf("expected no request while paused, got %d", n)
+	}
+
+	// Canceled requests don't wait for the transfers to resume.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, e := transport.RoundTrip(req); e != context.Canceled {
+		t.Fatalf("expected canceled request, got %v", e)
+	}
+
+	if !resumeTransfers() {
+		t.Fatal("expected the transfers to resume")
+	}
+	if e := <-done; e != nil {
+		t.Fatal(e)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Fatalf("expected the request once resumed, got %d", n)
+	}
+	if resumeTransfers() {
+		t.Fatal("expected the transfers to be resumed already")
+	}
+}
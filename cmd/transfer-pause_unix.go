@@ -0,0 +1,55 @@
+//go:build !windows
+// +build !windows
+
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+// trapPauseSignals - pauses the transfers on SIGTSTP and resumes them on
+// SIGCONT. From a terminal, ctrl-z then stops mc the way it used to, with
+// the requests to send held until `fg`, while the traffic saved so far
+// is flushed first.
+func trapPauseSignals() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTSTP, syscall.SIGCONT)
+	for s := range sigCh {
+		switch s {
+		case syscall.SIGTSTP:
+			if !pauseTransfers() {
+				continue
+			}
+			globalUsage.flush()
+			printPauseMessage(true)
+			if term.IsTerminal(int(os.Stdin.Fd())) {
+				syscall.Kill(os.Getpid(), syscall.SIGSTOP)
+			}
+		case syscall.SIGCONT:
+			if resumeTransfers() {
+				printPauseMessage(false)
+			}
+		}
+	}
+}
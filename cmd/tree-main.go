@@ -51,7 +51,7 @@ func (t treeMessage) String() string {
 	if t.IsDir {
 		entryType = "Dir"
 	}
-	return fmt.Sprintf("%s%s", t.BranchString, console.Colorize(entryType, t.Entry))
+	return fmt.Sprintf("%s%s", t.BranchString, console.Colorize(entryType, quoteName(t.Entry)))
 }
 
 // JSON'ified message for scripting.
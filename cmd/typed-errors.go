@@ -134,6 +134,13 @@ var errOverWriteNotAllowed = func(URL string) *probe.Error {
 	return probe.NewError(overwriteNotAllowedErr{errors.New(msg)})
 }
 
+type targetExistsErr error
+
+var errTargetExists = func(URL string) *probe.Error {
+	msg := "Target `" + URL + "` already exists."
+	return probe.NewError(targetExistsErr(errors.New(msg))).Untrace()
+}
+
 type targetIsNotDirErr error
 
 var errTargetIsNotDir = func(URL string) *probe.Error {
@@ -154,3 +161,45 @@ var errConflictSSE = func(sseServer, sseKeys string) *probe.Error {
 	err := fmt.Errorf("SSE alias '%s' overlaps with SSE-C aliases '%s'", sseServer, sseKeys)
 	return probe.NewError(conflictSSEErr(err)).Untrace()
 }
+
+type checksumMismatchErr error
+
+var errChecksumMismatch = func(URL, expected, actual string) *probe.Error {
+	msg := "Checksum of `" + URL + "` is `" + actual + "`, expected `" + expected + "`."
+	return probe.NewError(checksumMismatchErr(errors.New(msg))).Untrace()
+}
+
+type checksumUnverifiedErr error
+
+var errChecksumUnverified = func(URL string) *probe.Error {
+	msg := "The ETag of `" + URL + "` is not a checksum of its data."
+	return probe.NewError(checksumUnverifiedErr(errors.New(msg))).Untrace()
+}
+
+type unknownURLSchemeErr error
+
+var errUnknownURLScheme = func(URL, scheme string, suggestions []string) *probe.Error {
+	msg := "Unknown scheme `" + scheme + "://` in `" + URL + "`."
+	if len(suggestions) > 0 {
+		msg += " " + didYouMean(suggestions)
+	}
+	msg += " Use an alias, as in `myalias/bucket/object`, or one of `http://`, `https://`, `az://`, `gs://` or `sftp://`."
+	return probe.NewError(unknownURLSchemeErr(errors.New(msg))).Untrace()
+}
+
+type missingBucketErr error
+
+var errMissingBucket = func(URL string) *probe.Error {
+	msg := "The bucket name is missing in `" + URL + "`, expected `alias/bucket/object`."
+	return probe.NewError(missingBucketErr(errors.New(msg))).Untrace()
+}
+
+type invalidURLHostErr error
+
+var errInvalidURLHost = func(URL string, e error) *probe.Error {
+	msg := "Invalid host in `" + URL + "`"
+	if e != nil {
+		msg += ": " + e.Error()
+	}
+	return probe.NewError(invalidURLHostErr(errors.New(msg + "."))).Untrace()
+}
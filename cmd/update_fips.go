@@ -22,3 +22,6 @@ package cmd
 
 // Newer official download info URLs appear earlier below.
 var mcReleaseInfoURL = mcReleaseURL + "mc.fips.sha256sum"
+
+// Built with the fips tag, see `mc --version`.
+const buildWithFIPS = true
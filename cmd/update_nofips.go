@@ -22,3 +22,6 @@ package cmd
 
 // Newer official download info URLs appear earlier below.
 var mcReleaseInfoURL = mcReleaseURL + "mc.sha256sum"
+
+// Built without the fips tag, see `mc --version`.
+const buildWithFIPS = false
@@ -0,0 +1,172 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// UploadRejected - the upload of an object was refused by the upload rules
+// of its alias.
+type UploadRejected struct {
+	Path   string `json:"path"`
+	Alias  string `json:"alias"`
+	Reason string `json:"reason"`
+}
+
+func (e UploadRejected) Error() string {
+	return fmt.Sprintf("`%s` rejected by the upload rules of `%s`: %s.", e.Path, e.Alias, e.Reason)
+}
+
+// getUploadRules - returns the upload rules of alias, nil when it has none.
+func getUploadRules(alias string) *uploadRulesV10 {
+	if alias == "" {
+		return nil
+	}
+	if aliasCfg := mustGetHostConfig(alias); aliasCfg != nil {
+		return aliasCfg.UploadRules
+	}
+	return nil
+}
+
+// maxSize - returns the largest size of an object, zero without limit.
+func (r *uploadRulesV10) maxSize(alias string) (uint64, *probe.Error) {
+	if r.MaxSize == "" {
+		return 0, nil
+	}
+	size, e := humanize.ParseBytes(r.MaxSize)
+	if e != nil {
+		return 0, probe.NewError(fmt.Errorf("invalid `uploadRules.maxSize` %q of the alias `%s`: %v", r.MaxSize, alias, e))
+	}
+	return size, nil
+}
+
+// contentTypeAllowed - reports whether contentType, without its
+// parameters, matches one of the allowed types, e.g. "text/plain" or
+// "image/*".
+func (r *uploadRulesV10) contentTypeAllowed(contentType string) bool {
+	if len(r.ContentTypes) == 0 {
+		return true
+	}
+	if mediaType, _, e := mime.ParseMediaType(contentType); e == nil {
+		contentType = mediaType
+	}
+	contentType = strings.ToLower(contentType)
+	for _, allowed := range r.ContentTypes {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == "*/*" || allowed == contentType {
+			return true
+		}
+		if strings.HasSuffix(allowed, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(allowed, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// forbiddenExtension - returns the forbidden extension of the object name,
+// e.g. ".exe" or ".tar.gz", empty when it is allowed.
+func (r *uploadRulesV10) forbiddenExtension(name string) string {
+	name = strings.ToLower(path.Base(name))
+	for _, ext := range r.ForbiddenExtensions {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		if strings.HasSuffix(name, ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+// checkUploadRules - checks an object of size bytes, -1 when unknown, and
+// of contentType, guessed from its name when empty, against the upload
+// rules of alias.
+func checkUploadRules(alias, urlStr string, size int64, contentType string) *probe.Error {
+	rules := getUploadRules(alias)
+	if rules == nil {
+		return nil
+	}
+	clntURL := newClientURL(urlStr)
+	reject := func(format string, args ...interface{}) *probe.Error {
+		return probe.NewError(UploadRejected{
+			Path:   alias + clntURL.Path,
+			Alias:  alias,
+			Reason: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if ext := rules.forbiddenExtension(clntURL.Path); ext != "" {
+		return reject("the extension `%s` is forbidden", ext)
+	}
+	if contentType == "" {
+		contentType = guessURLContentType(urlStr)
+	}
+	if !rules.contentTypeAllowed(contentType) {
+		return reject("the content type `%s` is not one of %s", contentType, strings.Join(rules.ContentTypes, ", "))
+	}
+	maxSize, err := rules.maxSize(alias)
+	if err != nil {
+		return err
+	}
+	if maxSize > 0 && size > int64(maxSize) {
+		return reject("the size %s is over the limit of %s", humanize.IBytes(uint64(size)), humanize.IBytes(maxSize))
+	}
+	return nil
+}
+
+// limitUploadReader - returns a reader failing the upload of an object of
+// unknown size, e.g. streamed by pipe, once it is read past the size limit
+// of the upload rules of alias.
+func limitUploadReader(alias, urlStr string, reader io.Reader) io.Reader {
+	rules := getUploadRules(alias)
+	if rules == nil {
+		return reader
+	}
+	maxSize, err := rules.maxSize(alias)
+	if err != nil || maxSize == 0 {
+		return reader
+	}
+	return &uploadRulesReader{Reader: reader, alias: alias, urlStr: urlStr, limit: int64(maxSize)}
+}
+
+type uploadRulesReader struct {
+	io.Reader
+	alias, urlStr string
+	read, limit   int64
+}
+
+func (r *uploadRulesReader) Read(p []byte) (int, error) {
+	n, e := r.Reader.Read(p)
+	r.read += int64(n)
+	if r.read > r.limit {
+		return n, checkUploadRules(r.alias, r.urlStr, r.read, "").ToGoError()
+	}
+	return n, e
+}
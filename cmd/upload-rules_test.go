@@ -0,0 +1,83 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestCheckUploadRules(t *testing.T) {
+	load := loadMcConfig
+	defer func() { loadMcConfig = load }()
+	conf := newConfigV10()
+	conf.Aliases["curated"] = aliasConfigV10{
+		URL: "https://curated.example.com",
+		UploadRules: &uploadRulesV10{
+			MaxSize:             "1KiB",
+			ContentTypes:        []string{"text/*", "image/png"},
+			ForbiddenExtensions: []string{".exe", "tar.gz"},
+		},
+	}
+	conf.Aliases["open"] = aliasConfigV10{URL: "https://open.example.com"}
+	loadMcConfig = func() (*configV10, *probe.Error) { return conf, nil }
+
+	testCases := []struct {
+		alias       string
+		urlStr      string
+		size        int64
+		contentType string
+		rejected    string
+	}{
+		{"curated", "https://curated.example.com/bucket/notes.txt", 10, "text/plain; charset=utf-8", ""},
+		{"curated", "https://curated.example.com/bucket/logo.png", 10, "", ""},
+		{"curated", "https://curated.example.com/bucket/logo.PNG", -1, "image/png", ""},
+		{"curated", "https://curated.example.com/bucket/setup.EXE", 10, "text/plain", "the extension `.exe` is forbidden"},
+		{"curated", "https://curated.example.com/bucket/backup.tar.gz", 10, "text/plain", "the extension `.tar.gz` is forbidden"},
+		{"curated", "https://curated.example.com/bucket/data.json", 10, "", "the content type `application/json` is not one of text/*, image/png"},
+		{"curated", "https://curated.example.com/bucket/big.txt", 2048, "text/plain", "the size 2.0 KiB is over the limit of 1.0 KiB"},
+		{"open", "https://open.example.com/bucket/setup.exe", 1 << 40, "", ""},
+		{"", "/tmp/setup.exe", 1 << 40, "", ""},
+	}
+	for i, testCase := range testCases {
+		err := checkUploadRules(testCase.alias, testCase.urlStr, testCase.size, testCase.contentType)
+		var rejected UploadRejected
+		switch {
+		case testCase.rejected == "" && err != nil:
+			t.Errorf("Test %d: unexpected error %v", i+1, err)
+		case testCase.rejected != "" && (err == nil || !errors.As(err.ToGoError(), &rejected)):
+			t.Errorf("Test %d: got %v, want a rejection", i+1, err)
+		case testCase.rejected != "" && rejected.Reason != testCase.rejected:
+			t.Errorf("Test %d: got %q, want %q", i+1, rejected.Reason, testCase.rejected)
+		}
+	}
+
+	// Objects of unknown size are rejected once read past the limit.
+	reader := limitUploadReader("curated", "https://curated.example.com/bucket/stream.txt", strings.NewReader(strings.Repeat("x", 2048)))
+	if _, e := io.ReadAll(reader); !errors.As(e, &UploadRejected{}) {
+		t.Errorf("got %v, want a rejection of the stream", e)
+	}
+	reader = limitUploadReader("curated", "https://curated.example.com/bucket/stream.txt", strings.NewReader("small"))
+	if data, e := io.ReadAll(reader); e != nil || string(data) != "small" {
+		t.Errorf("got %q, %v", data, e)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/env"
+	"github.com/minio/pkg/v2/words"
+)
+
+// schemeURLRgx - matches URLs of the form scheme://rest, aliases such as
+// `minio1` are written this way for other S3 clients.
+var schemeURLRgx = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9_.+-]*)://(.*)$`)
+
+// normalizeAliasedURL - returns aliasedURL with the scheme of the
+// supported backends in lower case, alias://bucket/object as
+// alias/bucket/object and file:///path as /path. Unknown schemes,
+// invalid hosts and missing buckets are reported before any request.
+func normalizeAliasedURL(aliasedURL string) (string, *probe.Error) {
+	if !strings.Contains(aliasedURL, "://") {
+		return aliasedURL, checkAliasedBucket(aliasedURL)
+	}
+	m := schemeURLRgx.FindStringSubmatch(aliasedURL)
+	if m == nil {
+		return aliasedURL, nil
+	}
+	// A local path, however unusual.
+	if _, e := os.Lstat(aliasedURL); e == nil {
+		return aliasedURL, nil
+	}
+
+	scheme, rest := m[1], m[2]
+	switch strings.ToLower(scheme) {
+	case "http", "https":
+		urlStr := strings.ToLower(scheme) + "://" + rest
+		u, e := url.Parse(urlStr)
+		if e != nil {
+			if ue, ok := e.(*url.Error); ok {
+				e = ue.Err
+			}
+			return "", errInvalidURLHost(aliasedURL, e)
+		}
+		if u.Hostname() == "" {
+			return "", errInvalidURLHost(aliasedURL, nil)
+		}
+		return urlStr, nil
+	case strings.TrimSuffix(azureURLScheme, "://"), strings.TrimSuffix(gcsURLScheme, "://"), sftpScheme:
+		return strings.ToLower(scheme) + "://" + rest, nil
+	case "file":
+		if strings.HasPrefix(rest, "/") {
+			return rest, nil
+		}
+		return "", errInvalidURLHost(aliasedURL, nil)
+	}
+
+	if mustGetHostConfig(scheme) != nil {
+		aliasedURL = scheme + "/" + rest
+		return aliasedURL, checkAliasedBucket(aliasedURL)
+	}
+	var suggestions []string
+	for _, alias := range closestAliases(scheme) {
+		suggestions = append(suggestions, alias+"/"+rest)
+	}
+	return "", errUnknownURLScheme(aliasedURL, scheme, suggestions)
+}
+
+// checkAliasedBucket - reports alias//object, where the bucket is
+// missing, when alias is configured.
+func checkAliasedBucket(aliasedURL string) *probe.Error {
+	if !strings.Contains(aliasedURL, "//") {
+		return nil
+	}
+	alias, path := url2Alias(aliasedURL)
+	if !strings.HasPrefix(path, "/") || strings.Trim(path, "/") == "" {
+		return nil
+	}
+	if mustGetHostConfig(alias) == nil {
+		return nil
+	}
+	return errMissingBucket(aliasedURL)
+}
+
+// closestAliases - returns the configured aliases, in the config or the
+// environment, one typo away from name.
+func closestAliases(name string) []string {
+	var aliases []string
+	if config, err := loadMcConfig(); err == nil {
+		for alias := range config.Aliases {
+			aliases = append(aliases, alias)
+		}
+	}
+	for _, key := range env.List(mcEnvHostPrefix) {
+		aliases = append(aliases, strings.TrimPrefix(key, mcEnvHostPrefix))
+	}
+	sort.Strings(aliases)
+
+	var closest []string
+	for i, alias := range aliases {
+		if i > 0 && aliases[i-1] == alias {
+			continue
+		}
+		// Allow missed, wrongly added and even transposed characters, as
+		// for the commands.
+		if alias != name && (strings.EqualFold(alias, name) || words.DamerauLevenshteinDistance(name, alias) < 2) {
+			closest = append(closest, alias)
+		}
+	}
+	return closest
+}
+
+// aliasTypoHint - suggests the closest aliases when e is a local path not
+// found, under the working directory, named like an alias with a typo.
+func aliasTypoHint(e error) string {
+	notFound, ok := e.(PathNotFound)
+	if !ok || notFound.Path == "" {
+		return ""
+	}
+	path := notFound.Path
+	if filepath.IsAbs(path) {
+		wd, e := os.Getwd()
+		if e != nil {
+			return ""
+		}
+		if path, e = filepath.Rel(wd, path); e != nil || path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+			return ""
+		}
+	}
+	name, rest := url2Alias(filepath.ToSlash(path))
+	if name == "" || name == "." {
+		return ""
+	}
+	var suggestions []string
+	for _, alias := range closestAliases(name) {
+		suggestions = append(suggestions, urlJoinPath(alias, rest))
+	}
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return ". " + didYouMean(suggestions)
+}
+
+// aliasSuggestion - suggests the closest aliases to the unknown alias.
+func aliasSuggestion(alias string) string {
+	if suggestions := closestAliases(alias); len(suggestions) > 0 {
+		return " " + didYouMean(suggestions)
+	}
+	return ""
+}
+
+func didYouMean(suggestions []string) string {
+	return "Did you mean `" + strings.Join(suggestions, "` or `") + "`?"
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/quick"
+)
+
+// usageCountersV1 - traffic of an alias.
+type usageCountersV1 struct {
+	Uploaded   uint64 `json:"uploaded"`
+	Downloaded uint64 `json:"downloaded"`
+	Requests   uint64 `json:"requests"`
+}
+
+// JSON file to persist the traffic of every alias.
+type usageLedgerV1 struct {
+	Version string `json:"version"`
+
+	// Traffic by month, formatted as 2006-01, then by alias.
+	Months map[string]map[string]usageCountersV1 `json:"months"`
+}
+
+// Instantiate a new usage ledger.
+func newUsageLedgerV1() *usageLedgerV1 {
+	return &usageLedgerV1{
+		Version: "1",
+		Months:  make(map[string]map[string]usageCountersV1),
+	}
+}
+
+// Get the usage ledger file.
+func getUsageLedgerFile() (string, *probe.Error) {
+	stateDir, err := getMcStateDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	return filepath.Join(stateDir, globalUsageLedgerFile), nil
+}
+
+// loadUsageLedger - loads the usage ledger, empty if it doesn't exist yet.
+func loadUsageLedger() (*usageLedgerV1, *probe.Error) {
+	ledgerFile, err := getUsageLedgerFile()
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if _, e := os.Stat(ledgerFile); os.IsNotExist(e) {
+		return newUsageLedgerV1(), nil
+	}
+	qs, e := quick.NewConfig(newUsageLedgerV1(), nil)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(ledgerFile)
+	}
+	if e = qs.Load(ledgerFile); e != nil {
+		return nil, probe.NewError(e).Trace(ledgerFile)
+	}
+	ledger := qs.Data().(*usageLedgerV1)
+	if ledger.Months == nil {
+		ledger.Months = make(map[string]map[string]usageCountersV1)
+	}
+	return ledger, nil
+}
+
+// saveUsageLedger - persists the usage ledger.
+func saveUsageLedger(ledger *usageLedgerV1) *probe.Error {
+	ledgerFile, err := getUsageLedgerFile()
+	if err != nil {
+		return err.Trace()
+	}
+	qs, e := quick.NewConfig(ledger, nil)
+	if e != nil {
+		return probe.NewError(e).Trace(ledgerFile)
+	}
+	if e = qs.Save(ledgerFile); e != nil {
+		return probe.NewError(e).Trace(ledgerFile)
+	}
+	return nil
+}
+
+// lockUsageLedger - locks the ledger against the updates of concurrent mc
+// processes, returns the function releasing it.
+func lockUsageLedger() (unlock func(), err *probe.Error) {
+	ledgerFile, err := getUsageLedgerFile()
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if err = createMcStateDir(); err != nil {
+		return nil, err.Trace()
+	}
+	return lockStateFile(ledgerFile)
+}
+
+// usageRecorder - traffic of the aliases since the ledger was last saved.
+type usageRecorder struct {
+	mutex    sync.Mutex
+	counters map[string]*usageCountersV1
+}
+
+var globalUsage = &usageRecorder{counters: make(map[string]*usageCountersV1)}
+
+// aliasCounters - returns the counters of alias, updated atomically.
+func (r *usageRecorder) aliasCounters(alias string) *usageCountersV1 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	counters, ok := r.counters[alias]
+	if !ok {
+		counters = &usageCountersV1{}
+		r.counters[alias] = counters
+	}
+	return counters
+}
+
+// flush - adds the recorded traffic to the current month of the ledger.
+// Done on exit, failures are ignored not to fail the command.
+func (r *usageRecorder) flush() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	month := UTCNow().Format("2006-01")
+	var ledger *usageLedgerV1
+	for alias, counters := range r.counters {
+		uploaded := atomic.SwapUint64(&counters.Uploaded, 0)
+		downloaded := atomic.SwapUint64(&counters.Downloaded, 0)
+		requests := atomic.SwapUint64(&counters.Requests, 0)
+		if uploaded == 0 && downloaded == 0 && requests == 0 {
+			continue
+		}
+		if ledger == nil {
+			unlock, err := lockUsageLedger()
+			if err != nil {
+				return
+			}
+			defer unlock()
+			if ledger, err = loadUsageLedger(); err != nil {
+				return
+			}
+			if ledger.Months[month] == nil {
+				ledger.Months[month] = make(map[string]usageCountersV1)
+			}
+		}
+		total := ledger.Months[month][alias]
+		total.Uploaded += uploaded
+		total.Downloaded += downloaded
+		total.Requests += requests
+		ledger.Months[month][alias] = total
+	}
+	if ledger != nil {
+		saveUsageLedger(ledger)
+	}
+}
+
+// usageTransport - accounts the requests and the payload bytes sent to
+// and received from an alias.
+type usageTransport struct {
+	counters  *usageCountersV1
+	transport http.RoundTripper
+}
+
+func newUsageTransport(alias string, transport http.RoundTripper) http.RoundTripper {
+	return &usageTransport{
+		counters:  globalUsage.aliasCounters(alias),
+		transport: transport,
+	}
+}
+
+// usageConn - accounts the bytes sent to and received from an alias over
+// a connection not made of HTTP requests, such as SFTP.
+type usageConn struct {
+	net.Conn
+	counters *usageCountersV1
+}
+
+func newUsageConn(alias string, conn net.Conn) net.Conn {
+	return &usageConn{
+		Conn:     conn,
+		counters: globalUsage.aliasCounters(alias),
+	}
+}
+
+func (c *usageConn) Read(p []byte) (int, error) {
+	n, e := c.Conn.Read(p)
+	atomic.AddUint64(&c.counters.Downloaded, uint64(n))
+	return n, e
+}
+
+func (c *usageConn) Write(p []byte) (int, error) {
+	n, e := c.Conn.Write(p)
+	atomic.AddUint64(&c.counters.Uploaded, uint64(n))
+	return n, e
+}
+
+// usageReader - counts the bytes read into counter.
+type usageReader struct {
+	io.ReadCloser
+	counter *uint64
+}
+
+func (r usageReader) Read(p []byte) (int, error) {
+	n, e := r.ReadCloser.Read(p)
+	atomic.AddUint64(r.counter, uint64(n))
+	return n, e
+}
+
+// RoundTrip - counts the request and its payload.
+func (t *usageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddUint64(&t.counters.Requests, 1)
+	if req.Body != nil && req.Body != http.NoBody {
+		r := *req
+		r.Body = usageReader{ReadCloser: req.Body, counter: &t.counters.Uploaded}
+		req = &r
+	}
+	resp, e := t.transport.RoundTrip(req)
+	if e != nil {
+		return nil, e
+	}
+	if resp.Body != nil {
+		resp.Body = usageReader{ReadCloser: resp.Body, counter: &t.counters.Downloaded}
+	}
+	return resp, nil
+}
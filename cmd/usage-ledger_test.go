@@ -0,0 +1,130 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUsageTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte("downloaded"))
+	}))
+	defer server.Close()
+
+	recorder := &usageRecorder{counters: make(map[string]*usageCountersV1)}
+	counters := recorder.aliasCounters("myminio")
+	client := &http.Client{Transport: &usageTransport{counters: counters, transport: http.DefaultTransport}}
+
+	for _, body := range []string{"uploaded", ""} {
+		req, e := http.NewRequest(http.MethodPut, server.URL, strings.NewReader(body))
+		if e != nil {
+			t.Fatal(e)
+		}
+		resp, e := client.Do(req)
+		if e != nil {
+			t.Fatal(e)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	if counters.Requests != 2 || counters.Uploaded != 8 || counters.Downloaded != 20 {
+		t.Fatalf("unexpected counters %+v", *counters)
+	}
+	if recorder.aliasCounters("myminio") != counters {
+		t.Fatal("expected the counters of an alias to be shared")
+	}
+}
+
+func TestUsageConn(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	counters := &usageCountersV1{}
+	conn := &usageConn{Conn: client, counters: counters}
+	defer conn.Close()
+	go func() {
+		buf := make([]byte, 8)
+		io.ReadFull(server, buf)
+		server.Write([]byte("downloaded"))
+	}()
+
+	if _, e := conn.Write([]byte("uploaded")); e != nil {
+		t.Fatal(e)
+	}
+	if _, e := io.ReadFull(conn, make([]byte, 10)); e != nil {
+		t.Fatal(e)
+	}
+	if counters.Uploaded != 8 || counters.Downloaded != 10 {
+		t.Fatalf("unexpected counters %+v", *counters)
+	}
+}
+
+func TestUsageLedgerConcurrentFlush(t *testing.T) {
+	configDir := mcCustomConfigDir
+	defer func() { mcCustomConfigDir = configDir }()
+	mcCustomConfigDir = t.TempDir()
+
+	// A lock left behind by a killed process is taken over.
+	ledgerFile, err := getUsageLedgerFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := os.WriteFile(ledgerFile+".lock", nil, 0o600); e != nil {
+		t.Fatal(e)
+	}
+	stale := time.Now().Add(-2 * stateFileLockStale)
+	if e := os.Chtimes(ledgerFile+".lock", stale, stale); e != nil {
+		t.Fatal(e)
+	}
+
+	const flushes = 8
+	var wg sync.WaitGroup
+	for i := 0; i < flushes; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recorder := &usageRecorder{counters: make(map[string]*usageCountersV1)}
+			counters := recorder.aliasCounters("myminio")
+			counters.Requests, counters.Uploaded = 1, 10
+			recorder.flush()
+		}()
+	}
+	wg.Wait()
+
+	ledger, err := loadUsageLedger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := ledger.Months[UTCNow().Format("2006-01")]["myminio"]
+	if total.Requests != flushes || total.Uploaded != 10*flushes {
+		t.Fatalf("expected the traffic of every flush, got %+v", total)
+	}
+	if _, e := os.Stat(ledgerFile + ".lock"); !os.IsNotExist(e) {
+		t.Fatalf("expected the lock to be released, got %v", e)
+	}
+}
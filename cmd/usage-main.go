@@ -0,0 +1,151 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+)
+
+var usageFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "month",
+		Usage: "only show the traffic of a month, formatted as YYYY-MM",
+	},
+}
+
+var usageCmd = cli.Command{
+	Name:         "usage",
+	Usage:        "show the monthly traffic of the aliases",
+	Action:       mainUsage,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(usageFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] [ALIAS]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Bytes uploaded and downloaded, and request counts, are recorded for every alias by this machine,
+  to compare with the bills of the providers. Only the payloads of the requests and responses are
+  counted, the headers and the traffic of other clients being excluded.
+
+EXAMPLES:
+  1. Show the monthly traffic of all the aliases.
+     {{.Prompt}} {{.HelpName}}
+
+  2. Show the monthly traffic of "s3".
+     {{.Prompt}} {{.HelpName}} s3
+
+  3. Show the traffic of all the aliases in October 2026.
+     {{.Prompt}} {{.HelpName}} --month 2026-10
+`,
+}
+
+// usageMessage - traffic of an alias in a month.
+type usageMessage struct {
+	Status     string `json:"status"`
+	Month      string `json:"month"`
+	Alias      string `json:"alias"`
+	Uploaded   uint64 `json:"uploaded"`
+	Downloaded uint64 `json:"downloaded"`
+	Requests   uint64 `json:"requests"`
+}
+
+// String colorized usage message.
+func (u usageMessage) String() string {
+	return fmt.Sprintf("%s  %s %s  %s  %s",
+		console.Colorize("UsageMonth", u.Month),
+		console.Colorize("UsageAlias", fmt.Sprintf("%-16s", u.Alias)),
+		fmt.Sprintf("uploaded: %-10s", humanize.IBytes(u.Uploaded)),
+		fmt.Sprintf("downloaded: %-10s", humanize.IBytes(u.Downloaded)),
+		fmt.Sprintf("requests: %s", humanize.Comma(int64(u.Requests))))
+}
+
+// JSON jsonified usage message.
+func (u usageMessage) JSON() string {
+	u.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(u, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// checkUsageSyntax - validate all the passed arguments
+func checkUsageSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) > 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	if month := ctx.String("month"); month != "" {
+		if _, e := time.Parse("2006-01", month); e != nil {
+			fatalIf(probe.NewError(e).Trace(month), "Invalid month, expected YYYY-MM.")
+		}
+	}
+}
+
+// mainUsage is the handle for "mc usage" command.
+func mainUsage(ctx *cli.Context) error {
+	checkUsageSyntax(ctx)
+
+	console.SetColor("UsageMonth", color.New(color.FgGreen))
+	console.SetColor("UsageAlias", color.New(color.FgCyan, color.Bold))
+
+	alias := cleanAlias(ctx.Args().Get(0))
+	ledger, err := loadUsageLedger()
+	fatalIf(err.Trace(), "Unable to load the usage ledger.")
+
+	months := make([]string, 0, len(ledger.Months))
+	for month := range ledger.Months {
+		if m := ctx.String("month"); m == "" || m == month {
+			months = append(months, month)
+		}
+	}
+	sort.Strings(months)
+	for _, month := range months {
+		aliases := make([]string, 0, len(ledger.Months[month]))
+		for a := range ledger.Months[month] {
+			if alias == "" || a == alias {
+				aliases = append(aliases, a)
+			}
+		}
+		sort.Strings(aliases)
+		for _, a := range aliases {
+			counters := ledger.Months[month][a]
+			printMsg(usageMessage{
+				Month:      month,
+				Alias:      a,
+				Uploaded:   counters.Uploaded,
+				Downloaded: counters.Downloaded,
+				Requests:   counters.Requests,
+			})
+		}
+	}
+	return nil
+}
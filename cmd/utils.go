@@ -35,6 +35,7 @@ import (
 	"time"
 
 	"github.com/mattn/go-ieproxy"
+	"github.com/mattn/go-runewidth"
 	"github.com/minio/madmin-go/v3"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/encrypt"
@@ -44,12 +45,30 @@ import (
 	"github.com/minio/pkg/v2/console"
 )
 
+// isObjectRefused - reports whether the copy of an object failed because
+// it is infected or rejected by the upload rules, and fails again when
+// retried.
+func isObjectRefused(err *probe.Error) bool {
+	if err == nil {
+		return false
+	}
+	switch err.ToGoError().(type) {
+	case ObjectInfected, UploadRejected:
+		return true
+	}
+	return false
+}
+
 func isErrIgnored(err *probe.Error) (ignored bool) {
 	// For all non critical errors we can continue for the remaining files.
 	switch e := err.ToGoError().(type) {
 	// Handle these specifically for filesystem related errors.
 	case BrokenSymlink, TooManyLevelsSymlink, PathNotFound:
 		ignored = true
+	// Files skipped by --block-secrets, objects infected or rejected by
+	// the upload rules.
+	case SecretsFound, ObjectInfected, UploadRejected:
+		ignored = true
 	// Handle these specifically for object storage related errors.
 	case BucketNameEmpty, ObjectMissing, ObjectAlreadyExists:
 		ignored = true
@@ -159,24 +178,38 @@ func NewS3Config(alias, urlStr string, aliasCfg *aliasConfigV10) *Config {
 		s3Config.SessionToken = aliasCfg.SessionToken
 		s3Config.Signature = aliasCfg.API
 		s3Config.Lookup = getLookupType(aliasCfg.Path)
+		s3Config.Region = aliasCfg.Region
+		s3Config.Provider = aliasCfg.Provider
 	}
 	return s3Config
 }
 
-// lineTrunc - truncates a string to the given maximum length by
+// lineTrunc - truncates a string to the given maximum display width by
 // adding ellipsis in the middle
 func lineTrunc(content string, maxLen int) string {
-	runes := []rune(content)
-	rlen := len(runes)
-	if rlen <= maxLen {
+	if runewidth.StringWidth(content) <= maxLen {
 		return content
 	}
 	halfLen := maxLen / 2
-	fstPart := string(runes[0:halfLen])
-	sndPart := string(runes[rlen-halfLen:])
+	fstPart := runewidth.Truncate(content, halfLen, "")
+	sndPart := runewidth.TruncateLeft(content, runewidth.StringWidth(content)-halfLen, "")
 	return fstPart + "…" + sndPart
 }
 
+// fitDisplayWidth - pads text with spaces, or cuts its beginning off and
+// replaces it with "...", to fill width terminal cells. Wide characters,
+// e.g. CJK ideographs and emojis, take two cells.
+func fitDisplayWidth(text string, width int) string {
+	textWidth := runewidth.StringWidth(text)
+	if textWidth <= width {
+		return runewidth.FillRight(text, width)
+	}
+	if width <= len("...") {
+		return runewidth.Truncate(text, width, "")
+	}
+	return runewidth.TruncateLeft(text, textWidth-width+len("..."), "...")
+}
+
 // isOlder returns true if the passed object is older than olderRef
 func isOlder(ti time.Time, olderRef string) bool {
 	if olderRef == "" {
@@ -444,6 +477,7 @@ func httpClient(reqTimeout time.Duration) *http.Client {
 			Proxy: ieproxy.GetProxyFunc(),
 			TLSClientConfig: &tls.Config{
 				RootCAs:            globalRootCAs,
+				Certificates:       globalClientCertificates,
 				InsecureSkipVerify: globalInsecure,
 				// Can't use SSLv3 because of POODLE and BEAST
 				// Can't use TLSv1.0 because of POODLE and BEAST using CBC cipher
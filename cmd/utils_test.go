@@ -160,3 +160,40 @@ func TestParseAttribute(t *testing.T) {
 
 	}
 }
+
+func TestFitDisplayWidth(t *testing.T) {
+	testCases := []struct {
+		text     string
+		width    int
+		expected string
+	}{
+		{"abc", 5, "abc  "},
+		{"abcdefgh", 6, "...fgh"},
+		{"写真", 6, "写真  "},
+		{"私の写真.jpg", 10, "... 真.jpg"},
+		{"私の写真.jpg", 9, "...真.jpg"},
+		{"写真", 2, "写"},
+	}
+	for i, testCase := range testCases {
+		if got := fitDisplayWidth(testCase.text, testCase.width); got != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestLineTrunc(t *testing.T) {
+	testCases := []struct {
+		content  string
+		maxLen   int
+		expected string
+	}{
+		{"abcdef", 6, "abcdef"},
+		{"abcdefgh", 6, "abc…fgh"},
+		{"日本語のファイル", 8, "日本…イル"},
+	}
+	for i, testCase := range testCases {
+		if got := lineTrunc(testCase.content, testCase.maxLen); got != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, got)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+)
+
+// verifyEndpoint - a side of a verified copy.
+type verifyEndpoint struct {
+	alias, urlStr, aliasedURL string
+	versionID                 string
+	sse                       encrypt.ServerSide
+}
+
+// verifyCopy - proves the target of a copy holds the data of the source by
+// comparing the checksum of the local side, computed, with the ETag of the
+// remote side, the MD5 checksum of its data or of its parts. Objects whose
+// ETags are not checksums, e.g. encrypted objects, are reported unverified.
+func verifyCopy(ctx context.Context, source, target verifyEndpoint) *probe.Error {
+	sourceClnt, sourceContent, err := verifyStat(ctx, source)
+	if err != nil {
+		return err.Trace(source.urlStr)
+	}
+	targetClnt, targetContent, err := verifyStat(ctx, target)
+	if err != nil {
+		return err.Trace(target.urlStr)
+	}
+	if sourceContent.Size != targetContent.Size {
+		return errChecksumMismatch(target.urlStr, "size "+strconv.FormatInt(sourceContent.Size, 10), "size "+strconv.FormatInt(targetContent.Size, 10)).Trace(source.urlStr)
+	}
+
+	_, sourceLocal := unwrapClient(sourceClnt).(*fsClient)
+	_, targetLocal := unwrapClient(targetClnt).(*fsClient)
+	var expected, actual string
+	var e error
+	switch {
+	case sourceLocal && targetLocal:
+		if expected, e = fileETag(sourceContent.URL.Path, 0); e == nil {
+			actual, e = fileETag(targetContent.URL.Path, 0)
+		}
+	case sourceLocal || targetLocal:
+		local, remote, remoteClnt, remoteEndpoint := sourceContent, targetContent, targetClnt, target
+		if targetLocal {
+			local, remote, remoteClnt, remoteEndpoint = targetContent, sourceContent, sourceClnt, source
+		}
+		if actual = etagChecksum(remote, remoteEndpoint); actual == "" {
+			warningIf(errChecksumUnverified(target.urlStr), "Unable to verify the copy.")
+			return nil
+		}
+		var partSize int64
+		if strings.Contains(actual, "-") {
+			if partSize, err = verifyPartSize(ctx, remoteClnt, remoteEndpoint); err != nil {
+				return err.Trace(remoteEndpoint.urlStr)
+			}
+		}
+		expected, e = fileETag(local.URL.Path, partSize)
+		if _, parts, _ := strings.Cut(expected, "-"); e == nil && partSize > 0 && !strings.HasSuffix(actual, "-"+parts) {
+			// Uploaded in parts of different sizes.
+			warningIf(errChecksumUnverified(target.urlStr), "Unable to verify the copy.")
+			return nil
+		}
+		if !sourceLocal {
+			expected, actual = actual, expected
+		}
+	default:
+		// Only single part uploads of the same data have the same ETag.
+		expected, actual = etagChecksum(sourceContent, source), etagChecksum(targetContent, target)
+		if expected == "" || actual == "" || strings.Contains(expected, "-") || strings.Contains(actual, "-") {
+			warningIf(errChecksumUnverified(target.urlStr), "Unable to verify the copy.")
+			return nil
+		}
+	}
+	if e != nil {
+		return probe.NewError(e).Trace(source.urlStr, target.urlStr)
+	}
+	if expected != actual {
+		return errChecksumMismatch(target.urlStr, expected, actual).Trace(source.urlStr)
+	}
+	return nil
+}
+
+func verifyStat(ctx context.Context, endpoint verifyEndpoint) (Client, *ClientContent, *probe.Error) {
+	clnt, err := newClientFromAlias(endpoint.alias, endpoint.urlStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := clnt.Stat(ctx, StatOptions{sse: endpoint.sse, versionID: endpoint.versionID})
+	if err != nil {
+		return nil, nil, err
+	}
+	return clnt, content, nil
+}
+
+// etagChecksum - returns the ETag of the object when it is the MD5 checksum
+// of its data, or of its parts as "<md5>-<parts>", empty otherwise.
+func etagChecksum(content *ClientContent, endpoint verifyEndpoint) string {
+	if endpoint.sse != nil || clientEncryptionKey(endpoint.aliasedURL) != nil {
+		return ""
+	}
+	if strings.HasPrefix(content.Metadata["X-Amz-Server-Side-Encryption"], "aws:kms") ||
+		content.Metadata["X-Amz-Server-Side-Encryption-Customer-Algorithm"] != "" {
+		return ""
+	}
+	etag := strings.ToLower(strings.Trim(content.ETag, `"`))
+	sum, parts, multipart := strings.Cut(etag, "-")
+	if b, e := hex.DecodeString(sum); e != nil || len(b) != md5.Size {
+		return ""
+	}
+	if n, e := strconv.Atoi(parts); multipart && (e != nil || n < 1) {
+		return ""
+	}
+	return etag
+}
+
+// verifyPartSize - returns the size of the first part of a multipart object,
+// the size of all its parts but the last one.
+func verifyPartSize(ctx context.Context, clnt Client, endpoint verifyEndpoint) (int64, *probe.Error) {
+	s3Clnt, ok := unwrapClient(clnt).(*S3Client)
+	if !ok {
+		return 0, probe.NewError(APINotImplemented{API: "Stat part", APIType: endpoint.urlStr})
+	}
+	bucket, object := s3Clnt.url2BucketAndObject()
+	info, e := s3Clnt.api.StatObject(ctx, bucket, object, minio.StatObjectOptions{PartNumber: 1, VersionID: endpoint.versionID})
+	if e != nil {
+		return 0, probe.NewError(e)
+	}
+	return info.Size, nil
+}
+
+// fileETag - computes the ETag of a file uploaded in parts of partSize,
+// or in a single part when partSize is 0.
+func fileETag(path string, partSize int64) (string, error) {
+	f, e := os.Open(path)
+	if e != nil {
+		return "", e
+	}
+	defer f.Close()
+
+	if partSize <= 0 {
+		sum := md5.New()
+		if _, e = io.Copy(sum, f); e != nil {
+			return "", e
+		}
+		return hex.EncodeToString(sum.Sum(nil)), nil
+	}
+	var sums []byte
+	var parts int
+	for {
+		sum := md5.New()
+		n, e := io.CopyN(sum, f, partSize)
+		if n > 0 {
+			sums = append(sums, sum.Sum(nil)...)
+			parts++
+		}
+		if e == io.EOF {
+			break
+		}
+		if e != nil {
+			return "", e
+		}
+	}
+	sum := md5.Sum(sums)
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(sum[:]), parts), nil
+}
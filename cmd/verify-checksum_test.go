@@ -0,0 +1,85 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileETag(t *testing.T) {
+	data := []byte("0123456789")
+	path := filepath.Join(t.TempDir(), "object")
+	if e := os.WriteFile(path, data, 0o644); e != nil {
+		t.Fatal(e)
+	}
+	hexSum := func(b []byte) string {
+		sum := md5.Sum(b)
+		return hex.EncodeToString(sum[:])
+	}
+	var sums []byte
+	for _, part := range [][]byte{data[:4], data[4:8], data[8:]} {
+		sum := md5.Sum(part)
+		sums = append(sums, sum[:]...)
+	}
+	whole := md5.Sum(data)
+	testCases := []struct {
+		partSize int64
+		etag     string
+	}{
+		{0, hexSum(data)},
+		{4, hexSum(sums) + "-3"},
+		{10, hexSum(whole[:]) + "-1"},
+	}
+	for i, testCase := range testCases {
+		etag, e := fileETag(path, testCase.partSize)
+		if e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		if etag != testCase.etag {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.etag, etag)
+		}
+	}
+}
+
+func TestETagChecksum(t *testing.T) {
+	useTestMcConfig(t)
+	sum := "9e107d9d372bb6826bd81d3542a419d6"
+	testCases := []struct {
+		etag     string
+		metadata map[string]string
+		checksum string
+	}{
+		{`"` + sum + `"`, nil, sum},
+		{sum + "-12", nil, sum + "-12"},
+		{sum + "-0", nil, ""},
+		{"not-a-checksum", nil, ""},
+		{sum, map[string]string{"X-Amz-Server-Side-Encryption": "aws:kms"}, ""},
+		{sum, map[string]string{"X-Amz-Server-Side-Encryption-Customer-Algorithm": "AES256"}, ""},
+		{sum, map[string]string{"X-Amz-Server-Side-Encryption": "AES256"}, sum},
+	}
+	for i, testCase := range testCases {
+		content := &ClientContent{ETag: testCase.etag, Metadata: testCase.metadata}
+		if checksum := etagChecksum(content, verifyEndpoint{aliasedURL: "play/bucket/object"}); checksum != testCase.checksum {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.checksum, checksum)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/v2/console"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var verifyFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "continuous",
+		Usage: "compare again after every interval until interrupted",
+	},
+	cli.DurationFlag{
+		Name:  "interval",
+		Usage: "time between two comparisons with --continuous",
+		Value: time.Hour,
+	},
+	cli.StringSliceFlag{
+		Name:  "exclude",
+		Usage: "exclude object(s) that match specified object name pattern",
+	},
+	cli.StringSliceFlag{
+		Name:  "include",
+		Usage: "only compare object(s) that match specified object name pattern",
+	},
+	cli.BoolFlag{
+		Name:  "skip-dir-markers",
+		Usage: "skip the zero byte objects named like a folder, e.g. 'photos/', created by other tools to show folders",
+	},
+	cli.StringSliceFlag{
+		Name:  "ignore-storage-class",
+		Usage: "ignore object(s) stored in the specified storage class on either side",
+	},
+	cli.StringFlag{
+		Name:  "monitoring-address",
+		Usage: "if specified, a new prometheus endpoint will be created to report the drift",
+	},
+}
+
+var verifyCmd = cli.Command{
+	Name:         "verify",
+	Usage:        "compare two buckets or folders and track their drift over time",
+	Action:       mainVerify,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(verifyFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] FIRST SECOND
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Verify compares the names, sizes and ETags of the objects of FIRST and SECOND, and reports objects
+  newer in FIRST, like 'mc diff'. It reports the objects differing since the previous comparison, the objects no longer
+  differing, and the number of differences. With --continuous, the comparison runs again after
+  every --interval, for buckets kept in sync by active-active replication. The exit status is
+  not zero when the single comparison without --continuous finds differences.
+
+  The prometheus endpoint of --monitoring-address reports:
+    mc_verify_objects                    objects compared by the last comparison
+    mc_verify_differences                differences found by the last comparison
+    mc_verify_oldest_difference_seconds  time since the oldest difference was first found
+    mc_verify_last_success_timestamp     unix time of the last completed comparison
+    mc_verify_passes_total               completed comparisons
+    mc_verify_failed_passes_total        comparisons stopped by an error
+
+EXAMPLES:
+  1. Compare two buckets once.
+     {{.Prompt}} {{.HelpName}} site1/mybucket site2/mybucket
+
+  2. Compare the replicated buckets of two sites every hour, logging the drift as JSON.
+     {{.Prompt}} {{.HelpName}} --json --continuous --interval 1h site1/mybucket site2/mybucket
+
+  3. Compare two replicated buckets every 10 minutes, reporting the drift to prometheus on port 8081.
+     {{.Prompt}} {{.HelpName}} --continuous --interval 10m --monitoring-address :8081 site1/mybucket site2/mybucket
+`,
+}
+
+var (
+	verifyObjects = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "mc_verify_objects",
+		Help: "The number of objects compared by the last comparison",
+	})
+	verifyDifferences = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "mc_verify_differences",
+		Help: "The number of differences found by the last comparison",
+	})
+	verifyOldestDifference = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "mc_verify_oldest_difference_seconds",
+		Help: "The time since the oldest difference was first found",
+	})
+	verifyLastSuccess = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "mc_verify_last_success_timestamp",
+		Help: "The unix time of the last completed comparison",
+	})
+	verifyPasses = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "mc_verify_passes_total",
+		Help: "The total number of completed comparisons",
+	})
+	verifyFailedPasses = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "mc_verify_failed_passes_total",
+		Help: "The total number of comparisons stopped by an error",
+	})
+)
+
+// verifyDriftMessage - an object differing since the previous comparison,
+// or no longer differing when resolved.
+type verifyDriftMessage struct {
+	Status   string    `json:"status"`
+	Key      string    `json:"key"`
+	Diff     string    `json:"diff"`
+	Since    time.Time `json:"since"`
+	Resolved bool      `json:"resolved"`
+}
+
+// String colorized drift message
+func (m verifyDriftMessage) String() string {
+	if m.Resolved {
+		return console.Colorize("VerifyResolved", fmt.Sprintf("= %s (%s since %s)", quoteName(m.Key), m.Diff, m.Since.Local().Format(printDate)))
+	}
+	return console.Colorize("VerifyDrift", fmt.Sprintf("! %s (%s)", quoteName(m.Key), m.Diff))
+}
+
+// JSON jsonified drift message
+func (m verifyDriftMessage) JSON() string {
+	m.Status = "success"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// verifyMessage - the result of a comparison.
+type verifyMessage struct {
+	Status      string        `json:"status"`
+	Time        time.Time     `json:"time"`
+	First       string        `json:"first"`
+	Second      string        `json:"second"`
+	Objects     int64         `json:"objects"`
+	Differences int           `json:"differences"`
+	New         int           `json:"new"`
+	Resolved    int           `json:"resolved"`
+	OldestSince *time.Time    `json:"oldestSince,omitempty"`
+	Duration    time.Duration `json:"duration"`
+}
+
+// String colorized verify message
+func (m verifyMessage) String() string {
+	msg := fmt.Sprintf("[%s] %d object(s), %d difference(s), %d new, %d resolved",
+		m.Time.Local().Format(printDate), m.Objects, m.Differences, m.New, m.Resolved)
+	if m.OldestSince != nil {
+		msg += fmt.Sprintf(", oldest since %s", m.OldestSince.Local().Format(printDate))
+	}
+	if m.Differences > 0 {
+		return console.Colorize("VerifyDrift", msg+".")
+	}
+	return console.Colorize("VerifyMessage", msg+".")
+}
+
+// JSON jsonified verify message
+func (m verifyMessage) JSON() string {
+	m.Status = "success"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// verifyDrift - an object differing between the two sides.
+type verifyDrift struct {
+	diff  string
+	since time.Time
+}
+
+// verifyDriftTracker - the differences found by the previous comparison,
+// with the time each was first found.
+type verifyDriftTracker struct {
+	drifts map[string]verifyDrift
+}
+
+// update - replaces the differences by those found at now, returns the new
+// ones and those no longer found, sorted by name.
+func (t *verifyDriftTracker) update(now time.Time, found map[string]string) (added, resolved []verifyDriftMessage) {
+	drifts := make(map[string]verifyDrift, len(found))
+	for key, diff := range found {
+		drift, ok := t.drifts[key]
+		if !ok || drift.diff != diff {
+			drift = verifyDrift{diff: diff, since: now}
+			added = append(added, verifyDriftMessage{Key: key, Diff: diff, Since: now})
+		}
+		drifts[key] = drift
+	}
+	for key, drift := range t.drifts {
+		if _, ok := drifts[key]; !ok {
+			resolved = append(resolved, verifyDriftMessage{Key: key, Diff: drift.diff, Since: drift.since, Resolved: true})
+		}
+	}
+	t.drifts = drifts
+	sort.Slice(added, func(i, j int) bool { return added[i].Key < added[j].Key })
+	sort.Slice(resolved, func(i, j int) bool { return resolved[i].Key < resolved[j].Key })
+	return added, resolved
+}
+
+// oldest - returns when the oldest difference was first found.
+func (t *verifyDriftTracker) oldest() (since time.Time, ok bool) {
+	for _, drift := range t.drifts {
+		if !ok || drift.since.Before(since) {
+			since, ok = drift.since, true
+		}
+	}
+	return since, ok
+}
+
+// verifyOptions - the objects compared by a verification.
+type verifyOptions struct {
+	filter               listFilter
+	ignoreStorageClasses []string
+}
+
+// verifyCompare - compares the objects of both clients, returns the number
+// of objects and the differences by object name.
+func verifyCompare(ctx context.Context, firstClnt, secondClnt Client, opts verifyOptions) (objects int64, found map[string]string, err *probe.Error) {
+	found = make(map[string]string)
+	firstURL, secondURL := firstClnt.GetURL().String(), secondClnt.GetURL().String()
+	for diffMsg := range objectDifference(ctx, firstClnt, secondClnt, false, true, diffTimeNone, opts.filter) {
+		if diffMsg.Error != nil {
+			return objects, found, diffMsg.Error
+		}
+		if matchExcludeStorageClasses(opts.ignoreStorageClasses, diffMsg.firstContent, diffMsg.secondContent) {
+			continue
+		}
+		objects++
+		key := strings.TrimPrefix(diffMsg.FirstURL, firstURL)
+		if diffMsg.Diff == differInSecond {
+			key = strings.TrimPrefix(diffMsg.SecondURL, secondURL)
+		}
+		switch {
+		case diffMsg.Diff == differInNone && etagDiffers(diffMsg.firstContent, diffMsg.secondContent):
+			found[key] = "etag"
+		case diffMsg.Diff == differInAASourceMTime:
+			found[key] = "newer"
+		case diffMsg.Diff != differInNone:
+			found[key] = diffMsg.Diff.String()
+		}
+	}
+	return objects, found, nil
+}
+
+// verifyPass - compares both clients once, prints the differences since
+// the previous comparison and updates the metrics.
+func verifyPass(ctx context.Context, firstClnt, secondClnt Client, opts verifyOptions, tracker *verifyDriftTracker) (verifyMessage, *probe.Error) {
+	start := UTCNow()
+	msg := verifyMessage{
+		Time:   start,
+		First:  firstClnt.GetURL().String(),
+		Second: secondClnt.GetURL().String(),
+	}
+	objects, found, err := verifyCompare(ctx, firstClnt, secondClnt, opts)
+	if err != nil {
+		verifyFailedPasses.Inc()
+		return msg, err
+	}
+	added, resolved := tracker.update(start, found)
+	for _, m := range resolved {
+		printMsg(m)
+	}
+	for _, m := range added {
+		printMsg(m)
+	}
+
+	msg.Objects, msg.Differences = objects, len(found)
+	msg.New, msg.Resolved = len(added), len(resolved)
+	msg.Duration = UTCNow().Sub(start)
+	verifyObjects.Set(float64(objects))
+	verifyDifferences.Set(float64(len(found)))
+	verifyOldestDifference.Set(0)
+	if since, ok := tracker.oldest(); ok {
+		msg.OldestSince = &since
+		verifyOldestDifference.Set(UTCNow().Sub(since).Seconds())
+	}
+	verifyLastSuccess.Set(float64(UTCNow().Unix()))
+	verifyPasses.Inc()
+	return msg, nil
+}
+
+// mainVerify is the handle for "mc verify" command.
+func mainVerify(cliCtx *cli.Context) error {
+	ctx, cancelVerify := context.WithCancel(globalContext)
+	defer cancelVerify()
+
+	checkDiffSyntax(ctx, cliCtx, nil)
+	continuous, interval := cliCtx.Bool("continuous"), cliCtx.Duration("interval")
+	if continuous && interval <= 0 {
+		fatalIf(errInvalidArgument().Trace(interval.String()), "--interval must be positive.")
+	}
+
+	console.SetColor("VerifyMessage", color.New(color.FgGreen, color.Bold))
+	console.SetColor("VerifyDrift", color.New(color.FgYellow, color.Bold))
+	console.SetColor("VerifyResolved", color.New(color.FgGreen))
+
+	// Both sides are compared as folders.
+	var clnts []Client
+	for _, url := range cliCtx.Args()[:2] {
+		if separator := string(newClientURL(url).Separator); !strings.HasSuffix(url, separator) {
+			url += separator
+		}
+		clnt, err := newClient(url)
+		fatalIf(err.Trace(url), "Unable to initialize `"+url+"`.")
+		clnts = append(clnts, clnt)
+	}
+	opts := verifyOptions{
+		filter:               newListFilter(cliCtx),
+		ignoreStorageClasses: cliCtx.StringSlice("ignore-storage-class"),
+	}
+
+	if address := cliCtx.String("monitoring-address"); address != "" {
+		http.Handle("/metrics", promhttp.Handler())
+		go func() {
+			if e := http.ListenAndServe(address, nil); e != nil {
+				fatalIf(probe.NewError(e), "Unable to setup monitoring endpoint.")
+			}
+		}()
+	}
+
+	tracker := &verifyDriftTracker{}
+	for {
+		msg, err := verifyPass(ctx, clnts[0], clnts[1], opts, tracker)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !continuous {
+			fatalIf(err.Trace(cliCtx.Args()...), "Unable to compare `"+msg.First+"` and `"+msg.Second+"`.")
+			printMsg(msg)
+			if msg.Differences > 0 {
+				return exitStatus(globalErrorExitStatus)
+			}
+			return nil
+		}
+		if err != nil {
+			errorIf(err.Trace(cliCtx.Args()...), "Unable to compare `"+msg.First+"` and `"+msg.Second+"`, retrying in %s.", interval)
+		} else {
+			printMsg(msg)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
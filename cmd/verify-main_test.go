@@ -0,0 +1,126 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestVerifyDriftTracker(t *testing.T) {
+	first, second, third := time.Unix(100, 0), time.Unix(200, 0), time.Unix(300, 0)
+	keys := func(msgs []verifyDriftMessage) (keys []string) {
+		for _, m := range msgs {
+			keys = append(keys, m.Key)
+		}
+		return keys
+	}
+
+	tracker := &verifyDriftTracker{}
+	added, resolved := tracker.update(first, map[string]string{"b": "size", "a": "only-in-first"})
+	if !reflect.DeepEqual(keys(added), []string{"a", "b"}) || len(resolved) != 0 {
+		t.Fatalf("Expected a and b added, got %v and %v resolved", keys(added), keys(resolved))
+	}
+
+	// A difference changing its kind is new again.
+	added, resolved = tracker.update(second, map[string]string{"a": "only-in-first", "b": "etag", "c": "only-in-second"})
+	if !reflect.DeepEqual(keys(added), []string{"b", "c"}) || len(resolved) != 0 {
+		t.Fatalf("Expected b and c added, got %v and %v resolved", keys(added), keys(resolved))
+	}
+	if since, ok := tracker.oldest(); !ok || !since.Equal(first) {
+		t.Fatalf("Expected the oldest difference since %s, got %s", first, since)
+	}
+
+	added, resolved = tracker.update(third, map[string]string{"c": "only-in-second"})
+	if len(added) != 0 || !reflect.DeepEqual(keys(resolved), []string{"a", "b"}) {
+		t.Fatalf("Expected a and b resolved, got %v and %v added", keys(resolved), keys(added))
+	}
+	if !resolved[0].Since.Equal(first) || !resolved[1].Since.Equal(second) {
+		t.Errorf("Expected the resolved differences to keep when they were found, got %+v", resolved)
+	}
+	if since, ok := tracker.oldest(); !ok || !since.Equal(second) {
+		t.Errorf("Expected the oldest difference since %s, got %s", second, since)
+	}
+
+	tracker.update(third, nil)
+	if _, ok := tracker.oldest(); ok {
+		t.Errorf("Expected no difference left")
+	}
+}
+
+func TestVerifyCompare(t *testing.T) {
+	useTestMcConfig(t)
+	root := t.TempDir()
+	first, second := filepath.Join(root, "first")+string(filepath.Separator), filepath.Join(root, "second")+string(filepath.Separator)
+	for name, content := range map[string]string{
+		filepath.Join(first, "same.txt"):      "same",
+		filepath.Join(second, "same.txt"):     "same",
+		filepath.Join(first, "dir/size.txt"):  "one",
+		filepath.Join(second, "dir/size.txt"): "three",
+		filepath.Join(first, "only-first"):    "1",
+		filepath.Join(second, "only-second"):  "2",
+		filepath.Join(first, "newer.txt"):     "newer",
+		filepath.Join(second, "newer.txt"):    "newer",
+		filepath.Join(first, "skip.tmp"):      "tmp",
+	} {
+		if e := os.MkdirAll(filepath.Dir(name), 0o755); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(name, []byte(content), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+	// Objects of the same size differ when the first one is newer.
+	now := time.Now()
+	for name, modTime := range map[string]time.Time{
+		filepath.Join(first, "same.txt"):   now.Add(-time.Hour),
+		filepath.Join(second, "same.txt"):  now.Add(-time.Hour),
+		filepath.Join(first, "newer.txt"):  now,
+		filepath.Join(second, "newer.txt"): now.Add(-time.Hour),
+	} {
+		if e := os.Chtimes(name, modTime, modTime); e != nil {
+			t.Fatal(e)
+		}
+	}
+	firstClnt, err := newClient(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondClnt, err := newClient(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objects, found, err := verifyCompare(context.Background(), firstClnt, secondClnt, verifyOptions{filter: listFilter{exclude: []string{"*.tmp"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"dir/size.txt": "size",
+		"newer.txt":    "newer",
+		"only-first":   "only-in-first",
+		"only-second":  "only-in-second",
+	}
+	if objects != 5 || !reflect.DeepEqual(found, expected) {
+		t.Errorf("Expected 5 objects and %v, got %d and %v", expected, objects, found)
+	}
+}
@@ -86,6 +86,9 @@ EXAMPLES:
 
   6. Watch for events on local directory.
      {{.Prompt}} {{.HelpName}} /usr/share
+
+  7. Watch for new and removed ".log" files under "nginx/" in a local directory, recursively.
+     {{.Prompt}} {{.HelpName}} --recursive --events put,delete --prefix "nginx/" --suffix ".log" /var/log
 `,
 }
 
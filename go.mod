@@ -16,6 +16,7 @@ require (
 	github.com/klauspost/compress v1.17.4
 	github.com/mattn/go-ieproxy v0.0.11
 	github.com/mattn/go-isatty v0.0.20
+	github.com/mattn/go-runewidth v0.0.15
 	github.com/minio/cli v1.24.2
 	github.com/minio/colorjson v1.0.6
 	github.com/minio/filepath v1.0.0
@@ -32,7 +33,7 @@ require (
 	github.com/rs/xid v1.5.0
 	github.com/shirou/gopsutil/v3 v3.23.12
 	github.com/tidwall/gjson v1.17.0
-	golang.org/x/crypto v0.21.0 // indirect
+	golang.org/x/crypto v0.21.0
 	golang.org/x/net v0.21.0
 	golang.org/x/text v0.14.0
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c
@@ -50,6 +51,7 @@ require (
 	github.com/muesli/reflow v0.3.0
 	github.com/navidys/tvxwidgets v0.4.1
 	github.com/olekukonko/tablewriter v0.0.5
+	github.com/pkg/sftp v1.13.6
 	github.com/rivo/tview v0.0.0-20231206124440-5f078138442e
 	github.com/vbauerster/mpb/v8 v8.7.1
 	golang.org/x/term v0.18.0
@@ -60,6 +62,7 @@ require (
 	github.com/VividCortex/ewma v1.2.0 // indirect
 	github.com/acarl005/stripansi v0.0.0-20180116102854-5a71ef0e047d // indirect
 	github.com/aymanbagabas/go-osc52/v2 v2.0.1 // indirect
+	github.com/kr/fs v0.1.0 // indirect
 	github.com/kr/pretty v0.3.1 // indirect
 	github.com/matttproud/golang_protobuf_extensions/v2 v2.0.0 // indirect
 	github.com/minio/mux v1.9.0 // indirect
@@ -97,7 +100,6 @@ require (
 	github.com/lufia/plan9stats v0.0.0-20231016141302-07b5767bb0ed // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-localereader v0.0.1 // indirect
-	github.com/mattn/go-runewidth v0.0.15 // indirect
 	github.com/matttproud/golang_protobuf_extensions v1.0.4 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
@@ -110,7 +112,7 @@ require (
 	github.com/prometheus/common v0.45.0 // indirect
 	github.com/prometheus/procfs v0.12.0
 	github.com/rivo/uniseg v0.4.4 // indirect
-	github.com/secure-io/sio-go v0.3.1 // indirect
+	github.com/secure-io/sio-go v0.3.1
 	github.com/sirupsen/logrus v1.9.3 // indirect
 	github.com/tidwall/match v1.1.1 // indirect
 	github.com/tidwall/pretty v1.2.1 // indirect
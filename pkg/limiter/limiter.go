@@ -21,11 +21,49 @@ package limiter
 import (
 	"errors"
 	"io"
+	"net"
 	"net/http"
+	"sync"
 
 	"github.com/juju/ratelimit"
 )
 
+type direction int
+
+const (
+	upload direction = iota
+	download
+)
+
+type bucketKey struct {
+	dir  direction
+	rate int64
+}
+
+var (
+	bucketsMu sync.Mutex
+	buckets   = make(map[bucketKey]*ratelimit.Bucket)
+)
+
+// sharedBucket returns the token bucket of the rate in bytes per second in
+// the direction, shared by all the transports and connections limited with
+// the same rate so that the limit applies to the whole process. Uploads and
+// downloads never share a bucket, even when limited to the same rate.
+func sharedBucket(dir direction, rate int64) *ratelimit.Bucket {
+	if rate <= 0 {
+		return nil
+	}
+	bucketsMu.Lock()
+	defer bucketsMu.Unlock()
+	key := bucketKey{dir: dir, rate: rate}
+	b, ok := buckets[key]
+	if !ok {
+		b = ratelimit.NewBucketWithRate(float64(rate), rate)
+		buckets[key] = b
+	}
+	return b
+}
+
 type limiter struct {
 	upload    *ratelimit.Bucket
 	download  *ratelimit.Bucket
@@ -74,22 +112,41 @@ func New(uploadLimit, downloadLimit int64, transport http.RoundTripper) http.Rou
 		return transport
 	}
 
-	var (
-		uploadBucket   *ratelimit.Bucket
-		downloadBucket *ratelimit.Bucket
-	)
-
-	if uploadLimit > 0 {
-		uploadBucket = ratelimit.NewBucketWithRate(float64(uploadLimit), uploadLimit)
+	return &limiter{
+		upload:    sharedBucket(upload, uploadLimit),
+		download:  sharedBucket(download, downloadLimit),
+		transport: transport,
 	}
+}
 
-	if downloadLimit > 0 {
-		downloadBucket = ratelimit.NewBucketWithRate(float64(downloadLimit), downloadLimit)
-	}
+type conn struct {
+	net.Conn
+	reader io.Reader
+	writer io.Writer
+}
 
-	return &limiter{
-		upload:    uploadBucket,
-		download:  downloadBucket,
-		transport: transport,
+func (c conn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+func (c conn) Write(p []byte) (int, error) {
+	return c.writer.Write(p)
+}
+
+// NewConn return a ratelimited connection, for the protocols not going
+// through an http.RoundTripper.
+func NewConn(c net.Conn, uploadLimit, downloadLimit int64) net.Conn {
+	if uploadLimit == 0 && downloadLimit == 0 {
+		return c
+	}
+	l := limiter{upload: sharedBucket(upload, uploadLimit), download: sharedBucket(download, downloadLimit)}
+	var w io.Writer = c
+	if l.upload != nil {
+		w = ratelimit.Writer(c, l.upload)
+	}
+	return conn{
+		Conn:   c,
+		reader: l.limitReader(c, l.download),
+		writer: w,
 	}
 }
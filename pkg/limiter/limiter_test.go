@@ -0,0 +1,65 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package limiter
+
+import (
+	"net"
+	"testing"
+)
+
+func TestSharedBucket(t *testing.T) {
+	if sharedBucket(upload, 0) != nil {
+		t.Fatal("expected no bucket without a limit")
+	}
+	if sharedBucket(upload, 1<<20) != sharedBucket(upload, 1<<20) {
+		t.Fatal("expected the bucket of a rate to be shared")
+	}
+	if sharedBucket(upload, 1<<20) == sharedBucket(upload, 2<<20) {
+		t.Fatal("expected different rates to have different buckets")
+	}
+	if sharedBucket(upload, 1<<20) == sharedBucket(download, 1<<20) {
+		t.Fatal("expected equal upload and download rates to have different buckets")
+	}
+	l := New(1<<20, 1<<20, nil).(*limiter)
+	if l.upload == l.download {
+		t.Fatal("expected a transport to limit uploads and downloads separately")
+	}
+}
+
+func TestNewConn(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	if c := NewConn(client, 0, 0); c != client {
+		t.Fatal("expected the connection to be returned as is without limits")
+	}
+
+	c := NewConn(client, 1<<20, 1<<20)
+	defer c.Close()
+	go func() {
+		buf := make([]byte, 5)
+		n, _ := server.Read(buf)
+		server.Write(buf[:n])
+	}()
+	if _, e := c.Write([]byte("hello")); e != nil {
+		t.Fatal(e)
+	}
+	buf := make([]byte, 5)
+	if n, e := c.Read(buf); e != nil || string(buf[:n]) != "hello" {
+		t.Fatalf("unexpected read %q, %v", buf[:n], e)
+	}
+}